@@ -0,0 +1,211 @@
+package access
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"gopkg.in/yaml.v3"
+)
+
+// MatrixConfig defines the roles of an access matrix and their permissions.
+//
+// An example in YAML:
+//
+//	roles:
+//	  - id: 10
+//	    name: operator
+//	    permissions: [export, publish]
+//	    includes: [editor]
+//	  - id: 3
+//	    name: editor
+//	    permissions: [read, write]
+//
+// The built-in roles keep their default permissions unless they are defined, and keep
+// their hierarchy unless they are defined with included roles.
+type MatrixConfig struct {
+	Roles []RoleDefinition `json:"roles" yaml:"roles"`
+}
+
+// RoleDefinition defines a role and its permissions.
+//
+// The ID is the value of the role, which is what role bindings store, so it must not
+// change once the role is in use. Built-in roles are defined by their own IDs and names.
+//
+// A role inherits the permissions of the roles it includes, given by their names, and of
+// the roles these include in turn. A role can include several roles, combining them.
+type RoleDefinition struct {
+	ID          Role         `json:"id"                 yaml:"id"`
+	Name        string       `json:"name"               yaml:"name"`
+	Permissions []Permission `json:"permissions"        yaml:"permissions"`
+	Includes    []string     `json:"includes,omitempty" yaml:"includes"`
+}
+
+// LoadMatrixJSON loads the access matrix and its role hierarchy from the JSON configuration,
+// see MatrixConfig.
+func LoadMatrixJSON(data []byte) (Matrix, Hierarchy, error) {
+	var config MatrixConfig
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&config); err != nil {
+		return nil, nil, errorz.NewValidationError("invalid access matrix configuration: %v", err)
+	}
+
+	return NewMatrixFromConfig(config)
+}
+
+// LoadMatrixYAML loads the access matrix and its role hierarchy from the YAML configuration,
+// see MatrixConfig.
+func LoadMatrixYAML(data []byte) (Matrix, Hierarchy, error) {
+	var config MatrixConfig
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&config); err != nil {
+		return nil, nil, errorz.NewValidationError("invalid access matrix configuration: %v", err)
+	}
+
+	return NewMatrixFromConfig(config)
+}
+
+// NewMatrixFromConfig creates the access matrix of the configuration and its role hierarchy.
+// It starts from the default matrix and hierarchy, see NewMatrix and NewHierarchy, and sets
+// the permissions and the included roles of the defined roles.
+//
+// The configuration is validated first. Roles including themselves, directly or through
+// other roles, are rejected. The names of the roles it defines are registered, so they are
+// supported from then on, see IsSupportedRole; the hierarchy is only returned.
+func NewMatrixFromConfig(config MatrixConfig) (Matrix, Hierarchy, error) {
+	if err := validateMatrixConfig(config); err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[Role]string, len(config.Roles))
+
+	for _, def := range config.Roles {
+		names[def.ID] = def.Name
+	}
+
+	includes, err := resolveIncludes(config, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hierarchy := NewHierarchy()
+
+	maps.Copy(hierarchy, includes)
+
+	if err := ensureNoCycles(hierarchy, names); err != nil {
+		return nil, nil, err
+	}
+
+	if err := registerRoles(names); err != nil {
+		return nil, nil, err
+	}
+
+	matrix := NewMatrix()
+
+	for _, def := range config.Roles {
+		matrix[def.ID] = PermissionsNone.Add(def.Permissions...)
+	}
+
+	return matrix, hierarchy, nil
+}
+
+// validateMatrixConfig checks that the roles are defined once, by valid names and IDs, and
+// that the built-in roles are defined by their own IDs.
+func validateMatrixConfig(config MatrixConfig) error {
+	ids := make(map[Role]bool, len(config.Roles))
+	names := make(map[string]bool, len(config.Roles))
+
+	for _, def := range config.Roles {
+		if err := validateRoleName(def.Name); err != nil {
+			return err
+		}
+
+		if ids[def.ID] || names[def.Name] {
+			return errorz.NewValidationError("role %s (%d) is defined twice", def.Name, def.ID)
+		}
+
+		ids[def.ID] = true
+		names[def.Name] = true
+
+		if def.ID < 0 {
+			return errorz.NewValidationError("role %s has a negative id", def.Name)
+		}
+
+		for _, builtin := range AllRoles {
+			if (def.ID == builtin) != (def.Name == builtinRoleNames[builtin]) {
+				return errorz.NewValidationError("role %s (%d) conflicts with the built-in role %s (%d)",
+					def.Name, def.ID, builtinRoleNames[builtin], builtin)
+			}
+		}
+
+		if def.ID == RoleNone && len(def.Permissions) > 0 {
+			return errorz.NewValidationError("role %s cannot have permissions", def.Name)
+		}
+	}
+
+	return nil
+}
+
+// resolveIncludes returns the roles included by the defined roles. The included roles are
+// looked up by name among the defined and built-in roles. Built-in roles defined without
+// included roles are left out, so they keep their hierarchy.
+func resolveIncludes(config MatrixConfig, names map[Role]string) (Hierarchy, error) {
+	includes := make(Hierarchy, len(config.Roles))
+
+	for _, def := range config.Roles {
+		if len(def.Includes) == 0 && builtinRoleNames[def.ID] == def.Name {
+			continue
+		}
+
+		roles := make([]Role, 0, len(def.Includes))
+
+		for _, name := range def.Includes {
+			role, err := lookupRole(name, names)
+			if err != nil {
+				return nil, err
+			}
+
+			roles = append(roles, role)
+		}
+
+		includes[def.ID] = roles
+	}
+
+	return includes, nil
+}
+
+// lookupRole returns the role with the given name among the defined and built-in roles.
+// Roles defined by other configurations are not part of the hierarchy and cannot be included.
+func lookupRole(name string, names map[Role]string) (Role, error) {
+	for _, candidates := range []map[Role]string{names, builtinRoleNames} {
+		for role, n := range candidates {
+			if n == name {
+				return role, nil
+			}
+		}
+	}
+
+	return RoleNone, errorz.NewValidationError("unknown role: %s", name)
+}
+
+// validateRoleName checks the name of a defined role.
+func validateRoleName(name string) error {
+	if name == "" {
+		return errorz.NewValidationError("role name is required")
+	}
+
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return errorz.NewValidationError("invalid role name: %q", name)
+		}
+	}
+
+	return nil
+}
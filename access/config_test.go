@@ -0,0 +1,211 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMatrixJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		m, h, err := LoadMatrixJSON([]byte(`{
+			"roles": [
+				{"id": 10, "name": "json-operator", "permissions": ["read", "write", "export"]},
+				{"id": 3, "name": "editor", "permissions": ["read"]}
+			]
+		}`))
+
+		require.NoError(t, err)
+		require.Equal(t, Permissions(PermissionRead|PermissionWrite|PermissionExport), m.GetPermissions(h, Role(10)))
+		require.Equal(t, Permissions(PermissionRead|PermissionComment), m.GetPermissions(h, RoleEditor), "includes guest")
+		require.Equal(t, NewMatrix()[RoleCreator], m[RoleCreator], "default permissions")
+		require.True(t, IsSupportedRole(Role(10)))
+		require.Equal(t, "json-operator", Role(10).String())
+	})
+
+	t.Run("unknown-permission", func(t *testing.T) {
+		_, _, err := LoadMatrixJSON([]byte(`{"roles": [{"id": 11, "name": "json-auditor", "permissions": ["audit"]}]}`))
+
+		require.IsType(t, errorz.ValidationError{}, err)
+		require.False(t, IsSupportedRole(Role(11)))
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		_, _, err := LoadMatrixJSON([]byte(`{"roles": [], "groups": []}`))
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, _, err := LoadMatrixJSON([]byte(`{"roles": `))
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func TestLoadMatrixYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		m, h, err := LoadMatrixYAML([]byte(`
+roles:
+  - id: 20
+    name: yaml-operator
+    permissions: [read, write]
+  - id: 21
+    name: yaml-auditor
+    permissions:
+      - read
+      - export
+`))
+
+		require.NoError(t, err)
+		require.Equal(t, Permissions(PermissionRead|PermissionWrite), m.GetPermissions(h, Role(20)))
+		require.Equal(t, Permissions(PermissionRead|PermissionExport), m.GetPermissions(h, Role(21)))
+		require.True(t, IsSupportedRole(Role(21)))
+	})
+
+	t.Run("unknown-permission", func(t *testing.T) {
+		_, _, err := LoadMatrixYAML([]byte("roles:\n  - id: 22\n    name: yaml-other\n    permissions: [audit]\n"))
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("unknown-field", func(t *testing.T) {
+		_, _, err := LoadMatrixYAML([]byte("groups: []\n"))
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func TestNewMatrixFromConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		m, h, err := NewMatrixFromConfig(MatrixConfig{})
+
+		require.NoError(t, err)
+		require.Equal(t, NewMatrix(), m)
+		require.Equal(t, NewHierarchy(), h)
+	})
+
+	t.Run("registered-role", func(t *testing.T) {
+		config := MatrixConfig{Roles: []RoleDefinition{{ID: 30, Name: "config-viewer"}}}
+
+		_, _, err := NewMatrixFromConfig(config)
+		require.NoError(t, err)
+
+		_, _, err = NewMatrixFromConfig(config)
+		require.NoError(t, err, "the same definition can be loaded again")
+
+		_, _, err = NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{{ID: 31, Name: "config-viewer"}}})
+		require.IsType(t, errorz.ValidationError{}, err)
+
+		_, _, err = NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{{ID: 30, Name: "config-other"}}})
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string][]RoleDefinition{
+			"no-name":         {{ID: 40}},
+			"invalid-name":    {{ID: 40, Name: "Operator"}},
+			"negative-id":     {{ID: -1, Name: "config-negative"}},
+			"duplicate-id":    {{ID: 40, Name: "config-a"}, {ID: 40, Name: "config-b"}},
+			"duplicate-name":  {{ID: 40, Name: "config-a"}, {ID: 41, Name: "config-a"}},
+			"builtin-id":      {{ID: RoleEditor, Name: "config-editor"}},
+			"builtin-name":    {{ID: 40, Name: "editor"}},
+			"none-permission": {{ID: RoleNone, Name: "none", Permissions: []Permission{PermissionRead}}},
+		}
+
+		for name, roles := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := NewMatrixFromConfig(MatrixConfig{Roles: roles})
+
+				require.IsType(t, errorz.ValidationError{}, err)
+				require.False(t, IsSupportedRole(Role(40)), "must not register any role")
+			})
+		}
+	})
+}
+
+func TestNewMatrixFromConfig_includes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("composite", func(t *testing.T) {
+		m, h, err := LoadMatrixYAML([]byte(`
+roles:
+  - id: 60
+    name: include-reviewer
+    permissions: [approve]
+  - id: 61
+    name: include-lead
+    permissions: [share]
+    includes: [include-reviewer, editor]
+`))
+
+		require.NoError(t, err)
+		require.True(t, h.Has(Role(61), Role(60)))
+		require.True(t, h.Has(Role(61), RoleGuest))
+		require.False(t, h.Has(Role(61), RoleCreator))
+		require.False(t, NewHierarchy().Has(Role(61), Role(60)), "the hierarchy is only returned")
+		require.Equal(t,
+			PermissionsNone.Add(PermissionShare, PermissionApprove, PermissionWrite, PermissionExport,
+				PermissionRead, PermissionComment),
+			m.GetPermissions(h, Role(61)))
+	})
+
+	t.Run("unknown-role", func(t *testing.T) {
+		_, _, err := NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{
+			{ID: 62, Name: "include-unknown", Includes: []string{"unknown"}},
+		}})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+		require.False(t, IsSupportedRole(Role(62)))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, _, err := NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{
+			{ID: 63, Name: "include-a", Includes: []string{"include-b"}},
+			{ID: 64, Name: "include-b", Includes: []string{"include-a"}},
+		}})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+		require.False(t, IsSupportedRole(Role(63)))
+	})
+
+	t.Run("builtin-cycle", func(t *testing.T) {
+		_, _, err := NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{
+			{ID: RoleGuest, Name: "guest", Includes: []string{"admin"}},
+		}})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("builtin-hierarchy", func(t *testing.T) {
+		m, h, err := NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{
+			{ID: RoleEditor, Name: "editor", Permissions: []Permission{PermissionWrite}, Includes: []string{"none"}},
+		}})
+
+		require.NoError(t, err)
+		require.False(t, h.Has(RoleEditor, RoleGuest))
+		require.Equal(t, Permissions(PermissionWrite), m.GetPermissions(h, RoleEditor))
+		require.False(t, m.HasPermission(h, RoleCreator, PermissionRead), "creator includes editor")
+		require.True(t, NewHierarchy().Has(RoleEditor, RoleGuest), "the built-in hierarchy is not changed")
+		require.True(t, NewMatrix().HasPermission(NewHierarchy(), RoleEditor, PermissionRead))
+	})
+
+	t.Run("other-configuration", func(t *testing.T) {
+		_, _, err := NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{{ID: 65, Name: "include-other"}}})
+
+		require.NoError(t, err)
+
+		_, _, err = NewMatrixFromConfig(MatrixConfig{Roles: []RoleDefinition{
+			{ID: 66, Name: "include-from-other", Includes: []string{"include-other"}},
+		}})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
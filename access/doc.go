@@ -2,4 +2,6 @@
 // It is based on the idea of roles and permissions.
 // The roles are used to determine the access level of a user.
 // The user is represented by an actor.
+// Besides the built-in roles, roles and their permissions can be defined in the
+// configuration of the access matrix, see MatrixConfig.
 package access
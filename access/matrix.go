@@ -1,28 +1,84 @@
 package access
 
+import (
+	"maps"
+	"slices"
+)
+
 // Matrix is an access matrix.
-// It maps roles to permissions.
+// It maps roles to the permissions granted to them directly. The effective permissions of
+// a role also include the permissions of the roles it includes in the hierarchy that comes
+// with the matrix, see Hierarchy.
 type Matrix map[Role]Permissions
 
-// NewMatrix creates a new access matrix.
+// Hierarchy is a role hierarchy.
+// It maps roles to the roles they include directly. It comes with an access matrix: the
+// default matrix follows the hierarchy of the built-in roles, see NewHierarchy, and the
+// matrix of a configuration follows the hierarchy of the configuration.
+type Hierarchy map[Role][]Role
+
+// NewMatrix creates a new access matrix with the default permissions of the built-in roles.
+// Following their hierarchy, guests can read and comment, editors can also write and
+// export, creators can also create, delete, share, publish and approve, and admins have all
+// built-in permissions. Permissions registered by other packages have to be granted
+// explicitly, see Grant.
 func NewMatrix() Matrix {
 	matrix := make(Matrix)
 
 	matrix[RoleNone] = PermissionsNone
-	matrix[RoleAdmin] = PermissionsAll
-	matrix[RoleCreator] = Permissions(PermissionCreate | PermissionRead | PermissionWrite | PermissionDelete)
-	matrix[RoleEditor] = Permissions(PermissionRead | PermissionWrite)
-	matrix[RoleGuest] = Permissions(PermissionRead)
+	matrix[RoleAdmin] = Permissions(PermissionAdmin)
+	matrix[RoleCreator] = PermissionsNone.Add(
+		PermissionCreate, PermissionDelete, PermissionShare, PermissionPublish, PermissionApprove)
+	matrix[RoleEditor] = PermissionsNone.Add(PermissionWrite, PermissionExport)
+	matrix[RoleGuest] = PermissionsNone.Add(PermissionRead, PermissionComment)
 
 	return matrix
 }
 
-// GetPermissions returns the permissions for the given role.
-func (m Matrix) GetPermissions(role Role) Permissions {
-	return m[role]
+// NewHierarchy creates a new role hierarchy of the built-in roles: admin includes creator,
+// which includes editor, which includes guest.
+func NewHierarchy() Hierarchy {
+	return maps.Clone(builtinRoleIncludes)
+}
+
+// GetPermissions returns the effective permissions for the given role: its own permissions
+// and those of the roles it includes in the hierarchy.
+func (m Matrix) GetPermissions(h Hierarchy, role Role) Permissions {
+	perms := PermissionsNone
+
+	for _, r := range h.Roles(role) {
+		perms |= m[r]
+	}
+
+	return perms
+}
+
+// HasPermission checks if the given role has the given permission, directly or through the
+// roles it includes in the hierarchy.
+func (m Matrix) HasPermission(h Hierarchy, role Role, perm Permission) bool {
+	return m.GetPermissions(h, role).Has(perm)
+}
+
+// Grant adds the given permission(s) to the own permissions of the role.
+func (m Matrix) Grant(role Role, perms ...Permission) {
+	m[role] = m[role].Add(perms...)
+}
+
+// Revoke removes the given permission(s) from the own permissions of the role. The role
+// keeps the permissions of the roles it includes.
+func (m Matrix) Revoke(role Role, perms ...Permission) {
+	m[role] = m[role].Remove(perms...)
+}
+
+// Roles returns the role and all roles it includes, directly or through other roles, in
+// the order of their values.
+func (h Hierarchy) Roles(role Role) []Role {
+	return includedRoles(h, role)
 }
 
-// HasPermission checks if the given role has the given permission.
-func (m Matrix) HasPermission(role Role, perm Permission) bool {
-	return m[role].Has(perm)
+// Has checks if the role includes the other role: it is the other role, or inherits from
+// it directly or through other roles. Use this method instead of == so that roles are
+// checked with their hierarchy.
+func (h Hierarchy) Has(role, other Role) bool {
+	return slices.Contains(h.Roles(role), other)
 }
@@ -9,10 +9,10 @@ import (
 func TestMatrix_GetPermissions(t *testing.T) {
 	t.Parallel()
 
-	m := NewMatrix()
+	m, h := NewMatrix(), NewHierarchy()
 
 	t.Run("none", func(t *testing.T) {
-		require.Equal(t, PermissionsNone, m.GetPermissions(RoleNone))
+		require.Equal(t, PermissionsNone, m.GetPermissions(h, RoleNone))
 	})
 
 	t.Run("others", func(t *testing.T) {
@@ -20,7 +20,7 @@ func TestMatrix_GetPermissions(t *testing.T) {
 
 		for _, r := range AllRoles[skipNone:] {
 			t.Run(r.String(), func(t *testing.T) {
-				require.NotEqual(t, PermissionsNone, m.GetPermissions(r))
+				require.NotEqual(t, PermissionsNone, m.GetPermissions(h, r))
 			})
 		}
 	})
@@ -29,7 +29,84 @@ func TestMatrix_GetPermissions(t *testing.T) {
 func TestMatrix_HasPermissions(t *testing.T) {
 	t.Parallel()
 
-	m := NewMatrix()
+	m, h := NewMatrix(), NewHierarchy()
 
-	require.True(t, m.HasPermission(RoleAdmin, PermissionRead))
+	require.True(t, m.HasPermission(h, RoleAdmin, PermissionRead))
+}
+
+func TestNewMatrix(t *testing.T) {
+	t.Parallel()
+
+	m, h := NewMatrix(), NewHierarchy()
+
+	require.Equal(t, PermissionsAll, m.GetPermissions(h, RoleAdmin))
+	require.True(t, m.HasPermission(h, RoleCreator, PermissionShare))
+	require.False(t, m.HasPermission(h, RoleCreator, PermissionAdmin))
+	require.True(t, m.HasPermission(h, RoleEditor, PermissionWrite))
+	require.False(t, m.HasPermission(h, RoleEditor, PermissionApprove))
+	require.True(t, m.HasPermission(h, RoleGuest, PermissionComment))
+	require.False(t, m.HasPermission(h, RoleGuest, PermissionWrite))
+}
+
+func TestMatrix_Grant(t *testing.T) {
+	t.Parallel()
+
+	m, h := NewMatrix(), NewHierarchy()
+
+	m.Grant(RoleGuest, PermissionExport, PermissionWrite)
+
+	require.Equal(t, Permissions(PermissionRead|PermissionComment|PermissionExport|PermissionWrite),
+		m.GetPermissions(h, RoleGuest))
+}
+
+func TestMatrix_Revoke(t *testing.T) {
+	t.Parallel()
+
+	m, h := NewMatrix(), NewHierarchy()
+
+	m.Revoke(RoleEditor, PermissionWrite, PermissionExport)
+
+	require.Equal(t, Permissions(PermissionRead|PermissionComment), m.GetPermissions(h, RoleEditor))
+}
+
+func TestMatrix_GetPermissions_inherited(t *testing.T) {
+	t.Parallel()
+
+	m, h := NewMatrix(), NewHierarchy()
+
+	require.Equal(t, Permissions(PermissionWrite|PermissionExport), m[RoleEditor])
+	require.Equal(t, Permissions(PermissionRead|PermissionWrite|PermissionExport|PermissionComment),
+		m.GetPermissions(h, RoleEditor))
+	require.True(t, m.HasPermission(h, RoleAdmin, PermissionComment))
+	require.Equal(t, PermissionsNone, m.GetPermissions(h, Role(100)))
+}
+
+func TestHierarchy_Roles(t *testing.T) {
+	t.Parallel()
+
+	h := NewHierarchy()
+
+	require.Equal(t, []Role{RoleAdmin, RoleCreator, RoleEditor, RoleGuest}, h.Roles(RoleAdmin))
+	require.Equal(t, []Role{RoleEditor, RoleGuest}, h.Roles(RoleEditor))
+	require.Equal(t, []Role{RoleNone}, h.Roles(RoleNone))
+}
+
+func TestHierarchy_Has(t *testing.T) {
+	t.Parallel()
+
+	h := NewHierarchy()
+
+	for _, r := range AllRoles {
+		t.Run(r.String(), func(t *testing.T) {
+			require.True(t, h.Has(r, r))
+		})
+	}
+
+	require.True(t, h.Has(RoleAdmin, RoleEditor))
+	require.True(t, h.Has(RoleAdmin, RoleGuest))
+	require.True(t, h.Has(RoleCreator, RoleGuest))
+	require.False(t, h.Has(RoleEditor, RoleCreator))
+	require.False(t, h.Has(RoleGuest, RoleAdmin))
+	require.False(t, h.Has(RoleEditor, RoleNone))
+	require.False(t, h.Has(Role(100), RoleGuest))
 }
@@ -2,21 +2,33 @@ package access
 
 import (
 	"strconv"
+	"sync"
+
+	"github.com/energimind/powermesh-core/errorz"
 )
 
 // Permission represents a permission to act on a resource.
-type Permission int
+// Every permission is a single bit, so permissions can be combined into Permissions.
+type Permission uint64
 
 // Permission enumeration for access control.
+// The bits of the built-in permissions are fixed; other permissions are given the next
+// free bit by RegisterPermission.
 const (
 	PermissionCreate Permission = 1 << iota
 	PermissionRead
 	PermissionWrite
 	PermissionDelete
+	PermissionShare   // manage the role bindings of a resource
+	PermissionExport  // export a resource out of the system
+	PermissionPublish // publish or archive a resource
+	PermissionComment
+	PermissionApprove // approve the changes of others
+	PermissionAdmin   // administer a resource
 )
 
-// AllPermissions is a list of all permissions. Used for testing purposes to validate that all
-// enum values are covered.
+// AllPermissions is a list of all built-in permissions. Used for testing purposes to validate
+// that all enum values are covered.
 //
 //nolint:gochecknoglobals
 var AllPermissions = []Permission{
@@ -24,20 +36,153 @@ var AllPermissions = []Permission{
 	PermissionRead,
 	PermissionWrite,
 	PermissionDelete,
+	PermissionShare,
+	PermissionExport,
+	PermissionPublish,
+	PermissionComment,
+	PermissionApprove,
+	PermissionAdmin,
+}
+
+// permissionRegistry holds the names of the permissions by bit.
+type permissionRegistry struct {
+	mu    sync.RWMutex
+	names map[Permission]string
+	next  Permission
+}
+
+//nolint:gochecknoglobals
+var registry = &permissionRegistry{
+	names: map[Permission]string{
+		PermissionCreate:  "create",
+		PermissionRead:    "read",
+		PermissionWrite:   "write",
+		PermissionDelete:  "delete",
+		PermissionShare:   "share",
+		PermissionExport:  "export",
+		PermissionPublish: "publish",
+		PermissionComment: "comment",
+		PermissionApprove: "approve",
+		PermissionAdmin:   "admin",
+	},
+	next: PermissionAdmin << 1,
+}
+
+// RegisterPermission registers a permission with the given name and returns it. The
+// permission is given the next free bit, so permissions registered by different packages
+// never collide. Registering a name again returns the registered permission.
+//
+// The name is used to serialize the permission. It must be lowercase letters, digits and
+// dashes, and must not be a reserved name of a permission set ("none" or "all").
+func RegisterPermission(name string) (Permission, error) {
+	if err := validatePermissionName(name); err != nil {
+		return 0, err
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for p, n := range registry.names {
+		if n == name {
+			return p, nil
+		}
+	}
+
+	if registry.next == 0 {
+		return 0, errorz.NewStateError("no free permission for %s", name)
+	}
+
+	p := registry.next
+	registry.names[p] = name
+	registry.next <<= 1
+
+	return p, nil
+}
+
+// RegisteredPermissions returns the built-in and registered permissions, in the order of
+// their bits.
+func RegisteredPermissions() []Permission {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	perms := make([]Permission, 0, len(registry.names))
+
+	for p := Permission(1); p != 0; p <<= 1 {
+		if _, ok := registry.names[p]; ok {
+			perms = append(perms, p)
+		}
+	}
+
+	return perms
+}
+
+// ParsePermission returns the permission with the given name.
+func ParsePermission(name string) (Permission, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	for p, n := range registry.names {
+		if n == name {
+			return p, nil
+		}
+	}
+
+	return 0, errorz.NewValidationError("unknown permission: %s", name)
 }
 
 // String returns a string representation of the permission.
 func (p Permission) String() string {
-	switch p {
-	case PermissionCreate:
-		return "create"
-	case PermissionRead:
-		return "read"
-	case PermissionWrite:
-		return "write"
-	case PermissionDelete:
-		return "delete"
-	default:
-		return "Permission(" + strconv.Itoa(int(p)) + ")"
+	if name, ok := p.name(); ok {
+		return name
+	}
+
+	return "Permission(" + strconv.FormatUint(uint64(p), 10) + ")"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// Permissions are serialized by their names.
+func (p Permission) MarshalText() ([]byte, error) {
+	name, ok := p.name()
+	if !ok {
+		return nil, errorz.NewValidationError("unknown permission: %d", uint64(p))
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (p *Permission) UnmarshalText(text []byte) error {
+	parsed, err := ParsePermission(string(text))
+	if err != nil {
+		return err
 	}
+
+	*p = parsed
+
+	return nil
+}
+
+// name returns the name of the permission, if it is built-in or registered.
+func (p Permission) name() (string, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	name, ok := registry.names[p]
+
+	return name, ok
+}
+
+// validatePermissionName checks the name of a registered permission.
+func validatePermissionName(name string) error {
+	if name == "" || name == permissionsNoneName || name == permissionsAllName {
+		return errorz.NewValidationError("invalid permission name: %q", name)
+	}
+
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return errorz.NewValidationError("invalid permission name: %q", name)
+		}
+	}
+
+	return nil
 }
@@ -1,8 +1,10 @@
 package access
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,3 +24,99 @@ func TestPermission_String(t *testing.T) {
 		require.Equal(t, "Permission(100)", p.String())
 	})
 }
+
+func TestRegisterPermission(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid-name", func(t *testing.T) {
+		for _, name := range []string{"", "none", "all", "Upper", "with,comma", "with space"} {
+			_, err := RegisterPermission(name)
+
+			require.IsType(t, errorz.ValidationError{}, err, name)
+		}
+	})
+
+	t.Run("new", func(t *testing.T) {
+		p, err := RegisterPermission("test-simulate")
+
+		require.NoError(t, err)
+		require.Greater(t, p, PermissionAdmin)
+		require.Equal(t, "test-simulate", p.String())
+		require.Contains(t, RegisteredPermissions(), p)
+
+		for _, builtin := range AllPermissions {
+			require.Zero(t, p&builtin)
+		}
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		p1, err := RegisterPermission("test-registered")
+		require.NoError(t, err)
+
+		p2, err := RegisterPermission("test-registered")
+		require.NoError(t, err)
+
+		require.Equal(t, p1, p2)
+	})
+
+	t.Run("builtin", func(t *testing.T) {
+		p, err := RegisterPermission("read")
+
+		require.NoError(t, err)
+		require.Equal(t, PermissionRead, p)
+	})
+}
+
+func TestRegisteredPermissions(t *testing.T) {
+	t.Parallel()
+
+	perms := RegisteredPermissions()
+
+	require.Equal(t, AllPermissions, perms[:len(AllPermissions)])
+	require.IsIncreasing(t, perms)
+}
+
+func TestParsePermission(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range AllPermissions {
+		t.Run(p.String(), func(t *testing.T) {
+			parsed, err := ParsePermission(p.String())
+
+			require.NoError(t, err)
+			require.Equal(t, p, parsed)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ParsePermission("unknown")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func TestPermission_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trip", func(t *testing.T) {
+		data, err := json.Marshal([]Permission{PermissionShare, PermissionApprove})
+
+		require.NoError(t, err)
+		require.JSONEq(t, `["share","approve"]`, string(data))
+
+		var perms []Permission
+
+		require.NoError(t, json.Unmarshal(data, &perms))
+		require.Equal(t, []Permission{PermissionShare, PermissionApprove}, perms)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := Permission(3).MarshalText()
+
+		require.IsType(t, errorz.ValidationError{}, err)
+
+		var p Permission
+
+		require.Error(t, json.Unmarshal([]byte(`"unknown"`), &p))
+	})
+}
@@ -1,16 +1,52 @@
 package access
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
 
 // Permissions is a bit mask of permissions.
 type Permissions Permission
 
+// Names of the permission sets with no and all built-in permissions.
+const (
+	permissionsNoneName = "none"
+	permissionsAllName  = "all"
+)
+
 // PermissionsNone represents no permissions.
 const PermissionsNone = Permissions(0)
 
-// PermissionsAll is a combination of all permissions.
+// PermissionsAll is a combination of all built-in permissions.
 const PermissionsAll = Permissions(
-	PermissionCreate | PermissionRead | PermissionWrite | PermissionDelete)
+	PermissionCreate | PermissionRead | PermissionWrite | PermissionDelete |
+		PermissionShare | PermissionExport | PermissionPublish | PermissionComment |
+		PermissionApprove | PermissionAdmin)
+
+// ParsePermissions returns the permissions of the string representation returned by
+// Permissions.String: "none", "all" or a comma-separated list of permission names.
+func ParsePermissions(s string) (Permissions, error) {
+	switch s {
+	case permissionsNoneName:
+		return PermissionsNone, nil
+	case permissionsAllName:
+		return PermissionsAll, nil
+	}
+
+	perms := PermissionsNone
+
+	for _, name := range strings.Split(s, ",") {
+		perm, err := ParsePermission(strings.TrimSpace(name))
+		if err != nil {
+			return PermissionsNone, err
+		}
+
+		perms = perms.Add(perm)
+	}
+
+	return perms, nil
+}
 
 // Has returns true if the permission contains the given permission.
 func (p Permissions) Has(perm Permission) bool {
@@ -36,18 +72,19 @@ func (p Permissions) Remove(perms ...Permission) Permissions {
 }
 
 // String returns a string representation of the permission.
+// Unknown bits are left out.
 func (p Permissions) String() string {
 	if p == PermissionsNone {
-		return "none"
+		return permissionsNoneName
 	}
 
 	if p == PermissionsAll {
-		return "all"
+		return permissionsAllName
 	}
 
 	var b strings.Builder
 
-	for _, perm := range AllPermissions {
+	for _, perm := range RegisteredPermissions() {
 		if !p.Has(perm) {
 			continue
 		}
@@ -61,3 +98,25 @@ func (p Permissions) String() string {
 
 	return b.String()
 }
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The permissions are serialized as returned by String.
+func (p Permissions) MarshalText() ([]byte, error) {
+	if unknown := p.Remove(RegisteredPermissions()...); unknown != PermissionsNone {
+		return nil, errorz.NewValidationError("unknown permissions: %d", uint64(unknown))
+	}
+
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (p *Permissions) UnmarshalText(text []byte) error {
+	parsed, err := ParsePermissions(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package access
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -100,3 +102,56 @@ func TestPermissions_String(t *testing.T) {
 			Permissions(PermissionCreate|PermissionRead).String())
 	})
 }
+
+func TestParsePermissions(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []Permissions{
+		PermissionsNone,
+		PermissionsAll,
+		Permissions(PermissionShare),
+		Permissions(PermissionRead | PermissionComment | PermissionAdmin),
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			parsed, err := ParsePermissions(p.String())
+
+			require.NoError(t, err)
+			require.Equal(t, p, parsed)
+		})
+	}
+
+	t.Run("spaces", func(t *testing.T) {
+		parsed, err := ParsePermissions("read, write")
+
+		require.NoError(t, err)
+		require.Equal(t, Permissions(PermissionRead|PermissionWrite), parsed)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ParsePermissions("read,unknown")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func TestPermissions_MarshalText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trip", func(t *testing.T) {
+		data, err := json.Marshal(map[string]Permissions{"editor": Permissions(PermissionRead | PermissionExport)})
+
+		require.NoError(t, err)
+		require.JSONEq(t, `{"editor":"read,export"}`, string(data))
+
+		var perms map[string]Permissions
+
+		require.NoError(t, json.Unmarshal(data, &perms))
+		require.Equal(t, Permissions(PermissionRead|PermissionExport), perms["editor"])
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := Permissions(1 << 63).MarshalText()
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
@@ -1,12 +1,21 @@
 package access
 
-import "strconv"
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
 
 // Role represents the role of a user.
 // The role is used to determine the access level of the user.
 type Role int
 
 // Role enumeration.
+// Other roles can be defined in the configuration of the access matrix, see LoadMatrixJSON.
 const (
 	RoleNone Role = iota
 	RoleAdmin
@@ -15,7 +24,7 @@ const (
 	RoleGuest
 )
 
-// AllRoles is a list of all roles. Used for testing purposes to validate that all
+// AllRoles is a list of all built-in roles. Used for testing purposes to validate that all
 // enum values are covered.
 //
 //nolint:gochecknoglobals
@@ -27,38 +36,193 @@ var AllRoles = []Role{
 	RoleGuest,
 }
 
-// Has checks if the current role contains the given role.
-// Use this method instead of == to allow for future upgrades to combined roles
-// using bitwise operations.
-func (r Role) Has(role Role) bool {
-	return r&role == role
+// roleRegistry holds the names of the roles.
+type roleRegistry struct {
+	mu    sync.RWMutex
+	names map[Role]string
+}
+
+// builtinRoleNames holds the names of the built-in roles.
+//
+//nolint:gochecknoglobals
+var builtinRoleNames = map[Role]string{
+	RoleNone:    "none",
+	RoleAdmin:   "admin",
+	RoleCreator: "creator",
+	RoleEditor:  "editor",
+	RoleGuest:   "guest",
+}
+
+// builtinRoleIncludes holds the hierarchy of the built-in roles, see NewHierarchy.
+//
+//nolint:gochecknoglobals
+var builtinRoleIncludes = Hierarchy{
+	RoleAdmin:   {RoleCreator},
+	RoleCreator: {RoleEditor},
+	RoleEditor:  {RoleGuest},
+}
+
+//nolint:gochecknoglobals
+var roleNames = &roleRegistry{
+	names: maps.Clone(builtinRoleNames),
+}
+
+// RegisteredRoles returns the built-in roles and the roles defined by the loaded access
+// matrices, in the order of their values.
+func RegisteredRoles() []Role {
+	roleNames.mu.RLock()
+	defer roleNames.mu.RUnlock()
+
+	roles := make([]Role, 0, len(roleNames.names))
+
+	for r := range roleNames.names {
+		roles = append(roles, r)
+	}
+
+	slices.Sort(roles)
+
+	return roles
+}
+
+// ParseRole returns the built-in or registered role with the given name.
+func ParseRole(name string) (Role, error) {
+	roleNames.mu.RLock()
+	defer roleNames.mu.RUnlock()
+
+	for r, n := range roleNames.names {
+		if n == name {
+			return r, nil
+		}
+	}
+
+	return RoleNone, errorz.NewValidationError("unknown role: %s", name)
 }
 
 // String returns the string representation of the role.
 func (r Role) String() string {
-	switch r {
-	case RoleNone:
-		return "none"
-	case RoleAdmin:
-		return "admin"
-	case RoleCreator:
-		return "creator"
-	case RoleEditor:
-		return "editor"
-	case RoleGuest:
-		return "guest"
+	roleNames.mu.RLock()
+	defer roleNames.mu.RUnlock()
+
+	if name, ok := roleNames.names[r]; ok {
+		return name
 	}
 
 	return "Role(" + strconv.Itoa(int(r)) + ")"
 }
 
-// IsSupportedRole checks if the given role is supported.
+// IsSupportedRole checks if the given role is supported: it is a built-in role or a role
+// defined by a loaded access matrix.
 func IsSupportedRole(role Role) bool {
-	for _, r := range AllRoles {
-		if r == role {
-			return true
+	roleNames.mu.RLock()
+	defer roleNames.mu.RUnlock()
+
+	_, ok := roleNames.names[role]
+
+	return ok
+}
+
+// registerRoles registers the names of the roles. It registers none of them if any role
+// is already registered with another name, or any name with another role.
+func registerRoles(names map[Role]string) error {
+	roleNames.mu.Lock()
+	defer roleNames.mu.Unlock()
+
+	for role, name := range names {
+		for r, n := range roleNames.names {
+			if (r == role) != (n == name) {
+				return errorz.NewValidationError("role %s (%d) conflicts with the registered role %s (%d)",
+					name, role, n, r)
+			}
+		}
+	}
+
+	maps.Copy(roleNames.names, names)
+
+	return nil
+}
+
+// includedRoles returns the role and all roles it includes in the hierarchy, in the order
+// of their values. The hierarchy must not have cycles.
+func includedRoles(includes Hierarchy, role Role) []Role {
+	roles := []Role{role}
+
+	for i := 0; i < len(roles); i++ {
+		for _, included := range includes[roles[i]] {
+			if !slices.Contains(roles, included) {
+				roles = append(roles, included)
+			}
+		}
+	}
+
+	slices.Sort(roles)
+
+	return roles
+}
+
+// ensureNoCycles checks that no role of the hierarchy includes itself. The names are used
+// to report the roles of a cycle; roles without a given name are reported by their
+// registered names.
+func ensureNoCycles(includes Hierarchy, names map[Role]string) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[Role]int, len(includes))
+
+	var path []Role
+
+	var visit func(role Role) error
+
+	visit = func(role Role) error {
+		switch state[role] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := path[slices.Index(path, role):]
+			cycleNames := make([]string, 0, len(cycle)+1)
+
+			for _, r := range append(cycle, role) {
+				name, ok := names[r]
+				if !ok {
+					name = r.String()
+				}
+
+				cycleNames = append(cycleNames, name)
+			}
+
+			return errorz.NewValidationError("roles form a cycle: %s", strings.Join(cycleNames, " -> "))
+		}
+
+		state[role] = visiting
+		path = append(path, role)
+
+		for _, included := range includes[role] {
+			if err := visit(included); err != nil {
+				return err
+			}
+		}
+
+		state[role] = visited
+		path = path[:len(path)-1]
+
+		return nil
+	}
+
+	roles := make([]Role, 0, len(includes))
+
+	for role := range includes {
+		roles = append(roles, role)
+	}
+
+	slices.Sort(roles)
+
+	for _, role := range roles {
+		if err := visit(role); err != nil {
+			return err
 		}
 	}
 
-	return false
+	return nil
 }
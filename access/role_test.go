@@ -4,19 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/stretchr/testify/require"
 )
 
-func TestRole_Has(t *testing.T) {
-	t.Parallel()
-
-	for _, r := range AllRoles {
-		t.Run(r.String(), func(t *testing.T) {
-			require.True(t, r.Has(r))
-		})
-	}
-}
-
 func TestRole_String(t *testing.T) {
 	t.Parallel()
 
@@ -51,3 +42,47 @@ func TestIsSupportedRole(t *testing.T) {
 		require.False(t, IsSupportedRole(r))
 	})
 }
+
+func TestRegisteredRoles(t *testing.T) {
+	t.Parallel()
+
+	roles := RegisteredRoles()
+
+	require.Equal(t, AllRoles, roles[:len(AllRoles)])
+	require.IsIncreasing(t, roles)
+}
+
+func TestParseRole(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range AllRoles {
+		t.Run(r.String(), func(t *testing.T) {
+			parsed, err := ParseRole(r.String())
+
+			require.NoError(t, err)
+			require.Equal(t, r, parsed)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ParseRole("unknown")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func Test_ensureNoCycles(t *testing.T) {
+	t.Parallel()
+
+	names := map[Role]string{50: "a", 51: "b", 52: "c"}
+
+	require.NoError(t, ensureNoCycles(map[Role][]Role{50: {51, 52}, 51: {52}}, names))
+	require.NoError(t, ensureNoCycles(builtinRoleIncludes, nil))
+
+	err := ensureNoCycles(map[Role][]Role{50: {51}, 51: {52}, 52: {50}}, names)
+
+	require.IsType(t, errorz.ValidationError{}, err)
+	require.ErrorContains(t, err, "a -> b -> c -> a")
+
+	require.Error(t, ensureNoCycles(map[Role][]Role{50: {50}}, names))
+}
@@ -179,6 +179,36 @@ func IsConflictError(err error) bool {
 	return errors.As(err, &duplicateError)
 }
 
+// StateError is the error returned when an operation is not allowed in the current
+// state of an object.
+type StateError struct {
+	Message string
+}
+
+// NewStateError returns a new StateError.
+func NewStateError(format string, args ...any) StateError {
+	return StateError{
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// isDomainError implements the domainError interface.
+func (StateError) isDomainError() {
+	// tagging interface
+}
+
+// Error returns the error message.
+func (e StateError) Error() string {
+	return e.Message
+}
+
+// IsStateError returns true if the error is a StateError.
+func IsStateError(err error) bool {
+	var stateError StateError
+
+	return errors.As(err, &stateError)
+}
+
 // StoreError is the error returned when an error occurs while storing an object.
 type StoreError struct {
 	Message string
@@ -28,6 +28,7 @@ func TestErrors(t *testing.T) {
 	tester(NewUnauthorizedError("test:%d", 42), UnauthorizedError{})
 	tester(NewValidationError("test:%d", 42), ValidationError{})
 	tester(NewConflictError("test:%d", 42), ConflictError{})
+	tester(NewStateError("test:%d", 42), StateError{})
 	tester(NewStoreError("test:%d", 42), StoreError{})
 	tester(NewGatewayError("test:%d", 42), GatewayError{})
 	tester(NewSessionError("test:%d", 42), SessionError{})
@@ -48,6 +49,7 @@ func TestErrorIs(t *testing.T) {
 	tester(IsUnauthorizedError, UnauthorizedError{})
 	tester(IsValidationError, ValidationError{})
 	tester(IsConflictError, ConflictError{})
+	tester(IsStateError, StateError{})
 	tester(IsStoreError, StoreError{})
 	tester(IsGatewayError, GatewayError{})
 	tester(IsSessionError, SessionError{})
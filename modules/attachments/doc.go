@@ -0,0 +1,3 @@
+// Package attachments provides a model and service for managing files attached to models
+// and their nodes, e.g. single-line diagrams, datasheets and photos.
+package attachments
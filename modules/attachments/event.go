@@ -0,0 +1,48 @@
+package attachments
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// EventType is the type of event that occurred.
+type EventType string
+
+// Event types.
+const (
+	AttachmentCreated EventType = "attachment.created"
+	AttachmentDeleted EventType = "attachment.deleted"
+)
+
+// Event models an event that occurs in the attachment service.
+type Event interface {
+	IsAttachmentEvent() bool
+}
+
+// EventHeader models the header of an event.
+type EventHeader struct {
+	Type      EventType
+	Actor     access.Actor
+	Timestamp time.Time
+}
+
+// AttachmentEvent models an event that occurs in the attachment service related to an attachment.
+type AttachmentEvent struct {
+	EventHeader
+	Attachment Attachment
+}
+
+// IsAttachmentEvent implements the Event interface.
+func (AttachmentEvent) IsAttachmentEvent() bool {
+	return true
+}
+
+// ExtractAttachmentEvent extracts an attachment event from an event.
+func ExtractAttachmentEvent(e Event) (AttachmentEvent, bool) {
+	if ae, ok := e.(AttachmentEvent); ok {
+		return ae, true
+	}
+
+	return AttachmentEvent{}, false
+}
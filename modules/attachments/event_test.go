@@ -0,0 +1,26 @@
+package attachments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractAttachmentEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		ae, ok := ExtractAttachmentEvent(AttachmentEvent{EventHeader: EventHeader{Type: AttachmentCreated}})
+
+		require.True(t, ok)
+		require.NotZero(t, ae)
+		require.True(t, ae.IsAttachmentEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ae, ok := ExtractAttachmentEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, ae)
+	})
+}
@@ -0,0 +1,16 @@
+package attachments
+
+import "time"
+
+// Attachment represents a file attached to a model or to a node of its mesh.
+type Attachment struct {
+	ID          string
+	ModelID     string
+	NodeID      string // empty if the file is attached to the model itself
+	Name        string // file name
+	ContentType string // media type of the content, e.g. "application/pdf"
+	Size        int64  // content size in bytes
+	SHA256      string // hex-encoded SHA-256 checksum of the content
+	UploadedBy  string // user ID of the uploader
+	UploadedAt  time.Time
+}
@@ -0,0 +1,33 @@
+package attachments
+
+import (
+	"context"
+	"io"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// AttachmentService defines the attachment service.
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, actor access.Actor, data UploadData, content io.Reader) (Attachment, error)
+	DownloadAttachment(ctx context.Context, actor access.Actor, id string) (Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, actor access.Actor, id string) error
+	GetAttachment(ctx context.Context, actor access.Actor, id string) (Attachment, error)
+	ListAttachments(ctx context.Context, actor access.Actor, query AttachmentQuery) ([]Attachment, error)
+}
+
+// UploadData defines the data of an uploaded file.
+type UploadData struct {
+	ModelID     string
+	NodeID      string // node to attach the file to (optional, attaches to the model if empty)
+	Name        string
+	ContentType string
+	SHA256      string // expected hex-encoded SHA-256 checksum of the content (optional)
+}
+
+// AttachmentQuery defines the attachment query. It is used to list attachments.
+type AttachmentQuery struct {
+	ModelID   string
+	NodeID    string // restrict the attachments to this node (optional)
+	ModelOnly bool   // restrict the attachments to those of the model itself
+}
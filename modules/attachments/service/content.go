@@ -0,0 +1,51 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+)
+
+// errContentTooLarge is returned by the content reader when the content exceeds the size limit.
+var errContentTooLarge = errors.New("content too large")
+
+// contentReader reads the uploaded content, computing its size and checksum on the way.
+// It fails once the content exceeds the size limit.
+type contentReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	size     int64
+	maxSize  int64
+	exceeded bool
+}
+
+func newContentReader(r io.Reader, maxSize int64) *contentReader {
+	return &contentReader{
+		r:       r,
+		hash:    sha256.New(),
+		maxSize: maxSize,
+	}
+}
+
+// Read implements the io.Reader interface.
+func (r *contentReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+
+	r.size += int64(n)
+	r.hash.Write(p[:n])
+
+	if r.maxSize > 0 && r.size > r.maxSize {
+		r.exceeded = true
+
+		return n, errContentTooLarge
+	}
+
+	return n, err //nolint:wrapcheck // the errors of the wrapped reader are passed on as they are
+}
+
+// checksum returns the hex-encoded SHA-256 checksum of the content read so far.
+func (r *contentReader) checksum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
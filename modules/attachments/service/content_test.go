@@ -0,0 +1,43 @@
+package service
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_contentReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("within-limit", func(t *testing.T) {
+		r := newContentReader(strings.NewReader(validContent), int64(len(validContent)))
+
+		data, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.Equal(t, validContent, string(data))
+		require.Equal(t, int64(len(validContent)), r.size)
+		require.Equal(t, validChecksum, r.checksum())
+		require.False(t, r.exceeded)
+	})
+
+	t.Run("no-limit", func(t *testing.T) {
+		r := newContentReader(strings.NewReader(validContent), 0)
+
+		_, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.False(t, r.exceeded)
+	})
+
+	t.Run("exceeded", func(t *testing.T) {
+		r := newContentReader(strings.NewReader(validContent), int64(len(validContent))-1)
+
+		_, err := io.ReadAll(r)
+
+		require.ErrorIs(t, err, errContentTooLarge)
+		require.True(t, r.exceeded)
+	})
+}
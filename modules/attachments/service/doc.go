@@ -0,0 +1,2 @@
+// Package service implements the attachment service.
+package service
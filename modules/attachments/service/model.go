@@ -0,0 +1,28 @@
+package service
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/attachments"
+)
+
+func attachmentFromData(
+	id string,
+	actor access.Actor,
+	data attachments.UploadData,
+	content *contentReader,
+	now time.Time,
+) attachments.Attachment {
+	return attachments.Attachment{
+		ID:          id,
+		ModelID:     data.ModelID,
+		NodeID:      data.NodeID,
+		Name:        data.Name,
+		ContentType: data.ContentType,
+		Size:        content.size,
+		SHA256:      content.checksum(),
+		UploadedBy:  actor.UserID,
+		UploadedAt:  now,
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_attachmentFromData(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	content := newContentReader(strings.NewReader(validContent), 0)
+
+	_, err := io.ReadAll(content)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		attachments.Attachment{
+			ID:          validAttachmentID,
+			ModelID:     validUploadData.ModelID,
+			NodeID:      validUploadData.NodeID,
+			Name:        validUploadData.Name,
+			ContentType: validUploadData.ContentType,
+			Size:        int64(len(validContent)),
+			SHA256:      validChecksum,
+			UploadedBy:  editorActor.UserID,
+			UploadedAt:  now,
+		},
+		attachmentFromData(validAttachmentID, editorActor, validUploadData, content, now),
+	)
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// defaultMaxSize is the default maximum content size (50 MiB).
+const defaultMaxSize = 50 << 20
+
+// idGenerator defines the external ID generator.
+type idGenerator interface {
+	GenerateID() string
+}
+
+// store defines the external attachment store.
+//
+// The content of an attachment is stored separately from its metadata under the same ID.
+type store interface {
+	CreateAttachment(ctx context.Context, attachment attachments.Attachment) error
+	DeleteAttachment(ctx context.Context, id string) error
+	DeleteModelAttachments(ctx context.Context, modelID string) error
+	DeleteNodeAttachments(ctx context.Context, modelID string, nodeIDs []string) error
+	GetAttachment(ctx context.Context, id string) (attachments.Attachment, error)
+	GetAttachments(ctx context.Context, query attachments.AttachmentQuery) ([]attachments.Attachment, error)
+	UploadContent(ctx context.Context, id, name string, content io.Reader) error
+	OpenContent(ctx context.Context, id string) (io.ReadCloser, error)
+	DeleteContent(ctx context.Context, id string) error
+}
+
+// listener defines the external attachment event listener.
+type listener interface {
+	HandleAttachmentEvent(ctx context.Context, event attachments.Event) error
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+}
+
+// nodeProvider defines the external provider of mesh nodes.
+// It is implemented by the mesh service.
+type nodeProvider interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+}
+
+// AttachmentService implements the attachment service.
+//
+// It implements the attachments.AttachmentService interface.
+//
+// Access to the attachments is granted by the role binding of the actor to the model of
+// the attachment: reading requires the read permission, uploading and deleting the write
+// permission. Admins have access to all attachments.
+//
+// The service listens to mesh events and removes the attachments of deleted nodes and
+// meshes. It also takes part in the cascading model deletion as a deletion participant.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type AttachmentService struct {
+	idGen        idGenerator
+	store        store
+	authz        authorizer
+	nodes        nodeProvider
+	listener     listener
+	maxSize      int64
+	contentTypes []string
+	now          func() time.Time
+}
+
+// Ensure AttachmentService implements the attachments.AttachmentService interface.
+var _ attachments.AttachmentService = (*AttachmentService)(nil)
+
+// NewAttachmentService creates a new attachment service.
+func NewAttachmentService(
+	store store,
+	idGen idGenerator,
+	authz authorizer,
+	nodes nodeProvider,
+	opts ...Option,
+) *AttachmentService {
+	svc := &AttachmentService{
+		idGen:   idGen,
+		store:   store,
+		authz:   authz,
+		nodes:   nodes,
+		maxSize: defaultMaxSize,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// UploadAttachment implements the attachments.AttachmentService interface.
+//
+// The content is streamed to the store while its size and checksum are computed. If the
+// content exceeds the size limit or does not match the expected checksum, the stored
+// content is removed again and a validation error is returned.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) UploadAttachment(
+	ctx context.Context,
+	actor access.Actor,
+	data attachments.UploadData,
+	content io.Reader,
+) (attachments.Attachment, error) {
+	if err := validateUploadData(data); err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	if !isAllowedContentType(data.ContentType, s.contentTypes) {
+		return attachments.Attachment{}, errorz.NewValidationError("content type %s is not allowed", data.ContentType)
+	}
+
+	if err := s.authorize(ctx, actor, data.ModelID, access.PermissionWrite); err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	if data.NodeID != "" {
+		if _, err := s.nodes.GetNode(ctx, data.ModelID, data.NodeID); err != nil {
+			return attachments.Attachment{}, err
+		}
+	}
+
+	id := s.idGen.GenerateID()
+	reader := newContentReader(content, s.maxSize)
+
+	if err := s.store.UploadContent(ctx, id, data.Name, reader); err != nil {
+		s.discardContent(ctx, id)
+
+		if reader.exceeded {
+			return attachments.Attachment{}, errorz.NewValidationError(
+				"attachment exceeds the size limit of %d bytes", s.maxSize)
+		}
+
+		return attachments.Attachment{}, err
+	}
+
+	attachment := attachmentFromData(id, actor, data, reader, s.now())
+
+	if data.SHA256 != "" && data.SHA256 != attachment.SHA256 {
+		s.discardContent(ctx, id)
+
+		return attachments.Attachment{}, errorz.NewValidationError(
+			"attachment checksum %s does not match the expected checksum %s", attachment.SHA256, data.SHA256)
+	}
+
+	if err := s.store.CreateAttachment(ctx, attachment); err != nil {
+		s.discardContent(ctx, id)
+
+		return attachments.Attachment{}, err
+	}
+
+	if err := s.fireAttachmentEvent(ctx, actor, attachments.AttachmentCreated, attachment); err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// DownloadAttachment implements the attachments.AttachmentService interface.
+// The caller must close the returned content.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) DownloadAttachment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (attachments.Attachment, io.ReadCloser, error) {
+	attachment, err := s.GetAttachment(ctx, actor, id)
+	if err != nil {
+		return attachments.Attachment{}, nil, err
+	}
+
+	content, err := s.store.OpenContent(ctx, id)
+	if err != nil {
+		return attachments.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment implements the attachments.AttachmentService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) DeleteAttachment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	attachment, err := s.store.GetAttachment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorize(ctx, actor, attachment.ModelID, access.PermissionWrite); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteContent(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteAttachment(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.fireAttachmentEvent(ctx, actor, attachments.AttachmentDeleted, attachment); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAttachment implements the attachments.AttachmentService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) GetAttachment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (attachments.Attachment, error) {
+	if err := validateID(id); err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	attachment, err := s.store.GetAttachment(ctx, id)
+	if err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, attachment.ModelID, access.PermissionRead); err != nil {
+		return attachments.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments implements the attachments.AttachmentService interface.
+// The attachments are returned in the order they were uploaded.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) ListAttachments(
+	ctx context.Context,
+	actor access.Actor,
+	query attachments.AttachmentQuery,
+) ([]attachments.Attachment, error) {
+	if err := validateAttachmentQuery(query); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, actor, query.ModelID, access.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	found, err := s.store.GetAttachments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// HandleMeshEvent removes the attachments of the nodes deleted by the event. If the whole
+// mesh is deleted, the attachments of all its nodes are removed; the attachments of the
+// model itself are kept until the model is deleted.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) HandleMeshEvent(ctx context.Context, event models.MeshEvent) error {
+	//nolint:exhaustive // only deletions are of interest
+	switch event.Type {
+	case models.MeshDeleted:
+		return s.deleteAllNodeAttachments(ctx, event.Updates.ModelID)
+	case models.MeshContentsDeleted:
+		if len(event.Deletes.Nodes) == 0 {
+			return nil
+		}
+
+		nodeIDs := make([]string, 0, len(event.Deletes.Nodes))
+
+		for id := range event.Deletes.Nodes {
+			nodeIDs = append(nodeIDs, id)
+		}
+
+		return s.store.DeleteNodeAttachments(ctx, event.Deletes.ModelID, nodeIDs)
+	}
+
+	return nil
+}
+
+// DeleteModelResources removes all attachments of the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelAttachments(ctx, modelID)
+}
+
+// deleteAllNodeAttachments removes the attachments of all nodes of the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) deleteAllNodeAttachments(ctx context.Context, modelID string) error {
+	found, err := s.store.GetAttachments(ctx, attachments.AttachmentQuery{ModelID: modelID})
+	if err != nil {
+		return err
+	}
+
+	var nodeIDs []string
+
+	for _, a := range found {
+		if a.NodeID != "" {
+			nodeIDs = append(nodeIDs, a.NodeID)
+		}
+	}
+
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+
+	return s.store.DeleteNodeAttachments(ctx, modelID, nodeIDs)
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// discardContent removes the content of a failed upload. The removal is best effort:
+// a failure leaves an orphaned content, but does not hide the original error.
+func (s *AttachmentService) discardContent(ctx context.Context, id string) {
+	_ = s.store.DeleteContent(ctx, id)
+}
+
+// fireAttachmentEvent fires an attachment event.
+func (s *AttachmentService) fireAttachmentEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType attachments.EventType,
+	attachment attachments.Attachment,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := attachments.AttachmentEvent{
+		EventHeader: attachments.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Attachment: attachment,
+	}
+
+	if err := s.listener.HandleAttachmentEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
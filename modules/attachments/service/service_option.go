@@ -0,0 +1,26 @@
+package service
+
+// Option defines the option for the service.
+type Option func(service *AttachmentService)
+
+// WithListener sets the listener for the service.
+func WithListener(listener listener) Option {
+	return func(s *AttachmentService) {
+		s.listener = listener
+	}
+}
+
+// WithMaxSize sets the maximum content size in bytes. Zero disables the limit.
+func WithMaxSize(maxSize int64) Option {
+	return func(s *AttachmentService) {
+		s.maxSize = maxSize
+	}
+}
+
+// WithContentTypes sets the allowed media types, e.g. "application/pdf" or "image/*".
+// All types are allowed if none are set.
+func WithContentTypes(contentTypes ...string) Option {
+	return func(s *AttachmentService) {
+		s.contentTypes = contentTypes
+	}
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*attachments.UploadData)) attachments.UploadData {
+		data := validUploadData
+		f(&data)
+
+		return data
+	}
+
+	tests := map[string]struct {
+		actor         access.Actor
+		data          attachments.UploadData
+		content       string
+		opts          []Option
+		storeError    bool
+		listenerError bool
+		wantEvent     attachments.EventType
+		wantErr       error
+	}{
+		"invalid-data": {
+			actor:   adminActor,
+			data:    attachments.UploadData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"content-type-not-allowed": {
+			actor:   adminActor,
+			data:    validUploadData,
+			content: validContent,
+			opts:    []Option{WithContentTypes("image/*", "application/pdf")},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-binding": {
+			actor:   strangerActor,
+			data:    validUploadData,
+			content: validContent,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"no-permission": {
+			actor:   guestActor,
+			data:    validUploadData,
+			content: validContent,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"node-not-found": {
+			actor:   editorActor,
+			data:    withData(func(d *attachments.UploadData) { d.NodeID = "missing" }),
+			content: validContent,
+			wantErr: errorz.NotFoundError{},
+		},
+		"too-large": {
+			actor:   editorActor,
+			data:    validUploadData,
+			content: validContent,
+			opts:    []Option{WithMaxSize(4)},
+			wantErr: errorz.ValidationError{},
+		},
+		"checksum-mismatch": {
+			actor:   editorActor,
+			data:    withData(func(d *attachments.UploadData) { d.SHA256 = strings.Repeat("0", 64) }),
+			content: validContent,
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			actor:      editorActor,
+			data:       validUploadData,
+			content:    validContent,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         editorActor,
+			data:          validUploadData,
+			content:       validContent,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor:     editorActor,
+			data:      withData(func(d *attachments.UploadData) { d.SHA256 = validChecksum }),
+			content:   validContent,
+			opts:      []Option{WithContentTypes("text/*")},
+			wantEvent: attachments.AttachmentCreated,
+		},
+		"success-model-attachment": {
+			actor:     adminActor,
+			data:      withData(func(d *attachments.UploadData) { d.NodeID = "" }),
+			content:   validContent,
+			wantEvent: attachments.AttachmentCreated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError)
+			tl := newTestListener(test.listenerError)
+
+			opts := append([]Option{WithListener(tl)}, test.opts...)
+			svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{},
+				opts...)
+
+			a, err := svc.UploadAttachment(context.Background(), test.actor, test.data, strings.NewReader(test.content))
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, a)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validAttachmentID, a.ID)
+				require.Equal(t, test.data.NodeID, a.NodeID)
+				require.Equal(t, int64(len(validContent)), a.Size)
+				require.Equal(t, validChecksum, a.SHA256)
+				require.Equal(t, test.actor.UserID, a.UploadedBy)
+				require.NotEmpty(t, a.UploadedAt)
+				require.Equal(t, validContent, string(ts.contents[a.ID]))
+			}
+
+			if test.wantErr != nil && !test.listenerError {
+				require.Empty(t, ts.contents, "rejected content must be discarded")
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_DownloadAttachment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		id         string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			actor:   adminActor,
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   adminActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"no-binding": {
+			actor:   strangerActor,
+			id:      validAttachmentID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      adminActor,
+			id:         validAttachmentID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			actor: guestActor,
+			id:    validAttachmentID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testAttachment(validAttachmentID, validNodeID))
+
+			svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{})
+
+			a, content, err := svc.DownloadAttachment(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, a)
+				require.Nil(t, content)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testAttachment(validAttachmentID, validNodeID), a)
+
+			defer func() {
+				require.NoError(t, content.Close())
+			}()
+
+			data, err := io.ReadAll(content)
+
+			require.NoError(t, err)
+			require.Equal(t, validContent, string(data))
+		})
+	}
+}
+
+func TestAttachmentService_DeleteAttachment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		storeError    bool
+		listenerError bool
+		wantEvent     attachments.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   adminActor,
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   adminActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"no-permission": {
+			actor:   guestActor,
+			id:      validAttachmentID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      adminActor,
+			id:         validAttachmentID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         editorActor,
+			id:            validAttachmentID,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor:     editorActor,
+			id:        validAttachmentID,
+			wantEvent: attachments.AttachmentDeleted,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testAttachment(validAttachmentID, validNodeID))
+			tl := newTestListener(test.listenerError)
+
+			svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{},
+				WithListener(tl))
+
+			err := svc.DeleteAttachment(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				require.Empty(t, ts.attachments)
+				require.Empty(t, ts.contents)
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_ListAttachments(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		query      attachments.AttachmentQuery
+		storeError bool
+		wantIDs    []string
+		wantErr    error
+	}{
+		"invalid-query": {
+			actor:   adminActor,
+			query:   attachments.AttachmentQuery{},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-binding": {
+			actor:   strangerActor,
+			query:   attachments.AttachmentQuery{ModelID: validModelID},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      adminActor,
+			query:      attachments.AttachmentQuery{ModelID: validModelID},
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success-node": {
+			actor:   guestActor,
+			query:   attachments.AttachmentQuery{ModelID: validModelID, NodeID: validNodeID},
+			wantIDs: []string{"1"},
+		},
+		"success-model-only": {
+			actor:   guestActor,
+			query:   attachments.AttachmentQuery{ModelID: validModelID, ModelOnly: true},
+			wantIDs: []string{"2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testAttachment("1", validNodeID), testAttachment("2", ""))
+
+			svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{})
+
+			found, err := svc.ListAttachments(context.Background(), test.actor, test.query)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, found)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(found))
+
+			for _, a := range found {
+				ids = append(ids, a.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestAttachmentService_HandleMeshEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		event   models.MeshEvent
+		wantIDs []string
+	}{
+		"other-event": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshUpdated},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			wantIDs: []string{"1", "2", "3"},
+		},
+		"contents-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+				Deletes: models.Mesh{
+					ModelID: validModelID,
+					Nodes:   map[string]models.Node{validNodeID: {ID: validNodeID}},
+				},
+			},
+			wantIDs: []string{"2", "3"},
+		},
+		"mesh-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshDeleted},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			wantIDs: []string{"3"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false,
+				testAttachment("1", validNodeID),
+				testAttachment("2", "node2"),
+				testAttachment("3", ""))
+
+			svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{})
+
+			require.NoError(t, svc.HandleMeshEvent(context.Background(), test.event))
+
+			ids := make([]string, 0, len(ts.attachments))
+
+			for _, id := range []string{"1", "2", "3"} {
+				if _, ok := ts.attachments[id]; ok {
+					ids = append(ids, id)
+				}
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestAttachmentService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestStore(false, testAttachment("1", validNodeID), testAttachment("2", ""))
+
+	svc := NewAttachmentService(ts, newTestIDGenerator(), authz.NewAuthorizer(testBindingProvider{}), testNodeProvider{})
+
+	require.IsType(t, errorz.ValidationError{}, svc.DeleteModelResources(context.Background(), adminActor, ""))
+	require.NoError(t, svc.DeleteModelResources(context.Background(), adminActor, validModelID))
+	require.Empty(t, ts.attachments)
+	require.Empty(t, ts.contents)
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminActor        = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	editorActor       = access.Actor{UserID: "editor", Role: access.RoleCreator}
+	guestActor        = access.Actor{UserID: "guest", Role: access.RoleCreator}
+	strangerActor     = access.Actor{UserID: "stranger", Role: access.RoleCreator}
+	validModelID      = "model1"
+	validNodeID       = "node1"
+	validAttachmentID = "1" // must match generated ID from testIDGenerator
+	validContent      = "hello, attachments"
+	validChecksum     = "27e502ba3ac6caeb3f5fc55e802865191bb96e9b0a82e749d327f1c5b37cf85e"
+	validUploadData   = attachments.UploadData{
+		ModelID:     validModelID,
+		NodeID:      validNodeID,
+		Name:        "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+	}
+)
+
+type testIDGenerator struct {
+	idCounter atomic.Int64
+}
+
+// Ensure that the testIDGenerator implements the idGenerator interface.
+var _ idGenerator = (*testIDGenerator)(nil)
+
+func newTestIDGenerator() *testIDGenerator {
+	return &testIDGenerator{}
+}
+
+func (g *testIDGenerator) GenerateID() string {
+	return strconv.FormatInt(g.idCounter.Add(1), 10)
+}
+
+type testListener struct {
+	forcedError error
+	eventFired  attachments.Event
+}
+
+// Ensure that the testListener implements the listener interface.
+var _ listener = (*testListener)(nil)
+
+func newTestListener(forcedError bool) *testListener {
+	var err error
+
+	if forcedError {
+		err = errorz.NewGatewayError("forced-error")
+	}
+
+	return &testListener{forcedError: err}
+}
+
+func (l *testListener) HandleAttachmentEvent(_ context.Context, event attachments.Event) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+// testStore keeps the attachments and their contents in memory.
+type testStore struct {
+	forcedError error
+	attachments map[string]attachments.Attachment
+	contents    map[string][]byte
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...attachments.Attachment) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		attachments: map[string]attachments.Attachment{},
+		contents:    map[string][]byte{},
+	}
+
+	for _, a := range initial {
+		s.attachments[a.ID] = a
+		s.contents[a.ID] = []byte(validContent)
+	}
+
+	return s
+}
+
+func (s *testStore) CreateAttachment(_ context.Context, attachment attachments.Attachment) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.attachments[attachment.ID] = attachment
+
+	return nil
+}
+
+func (s *testStore) DeleteAttachment(_ context.Context, id string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.attachments[id]; !ok {
+		return errorz.NewNotFoundError("attachment %s not found", id)
+	}
+
+	delete(s.attachments, id)
+
+	return nil
+}
+
+func (s *testStore) DeleteModelAttachments(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, a := range s.attachments {
+		if a.ModelID == modelID {
+			delete(s.attachments, id)
+			delete(s.contents, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) DeleteNodeAttachments(_ context.Context, modelID string, nodeIDs []string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for _, nodeID := range nodeIDs {
+		for id, a := range s.attachments {
+			if a.ModelID == modelID && a.NodeID == nodeID {
+				delete(s.attachments, id)
+				delete(s.contents, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) GetAttachment(_ context.Context, id string) (attachments.Attachment, error) {
+	if s.forcedError != nil {
+		return attachments.Attachment{}, s.forcedError
+	}
+
+	a, ok := s.attachments[id]
+	if !ok {
+		return attachments.Attachment{}, errorz.NewNotFoundError("attachment %s not found", id)
+	}
+
+	return a, nil
+}
+
+func (s *testStore) GetAttachments(
+	_ context.Context,
+	query attachments.AttachmentQuery,
+) ([]attachments.Attachment, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []attachments.Attachment
+
+	for _, a := range s.attachments {
+		if a.ModelID != query.ModelID ||
+			(query.NodeID != "" && a.NodeID != query.NodeID) ||
+			(query.ModelOnly && a.NodeID != "") {
+			continue
+		}
+
+		found = append(found, a)
+	}
+
+	return found, nil
+}
+
+func (s *testStore) UploadContent(_ context.Context, id, _ string, content io.Reader) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	data, err := io.ReadAll(content)
+
+	// like GridFS, keep the chunks written before the failure
+	s.contents[id] = data
+
+	if err != nil {
+		return errorz.NewStoreError("upload failed: %v", err)
+	}
+
+	return nil
+}
+
+func (s *testStore) OpenContent(_ context.Context, id string) (io.ReadCloser, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	data, ok := s.contents[id]
+	if !ok {
+		return nil, errorz.NewNotFoundError("content of attachment %s not found", id)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *testStore) DeleteContent(_ context.Context, id string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	delete(s.contents, id)
+
+	return nil
+}
+
+// testBindingProvider grants the editor the editor role and the guest the guest role on
+// the valid model.
+type testBindingProvider struct{}
+
+func (testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	roles := map[string]access.Role{
+		editorActor.UserID: access.RoleEditor,
+		guestActor.UserID:  access.RoleGuest,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testNodeProvider knows the valid node only.
+type testNodeProvider struct{}
+
+// Ensure that the testNodeProvider implements the nodeProvider interface.
+var _ nodeProvider = testNodeProvider{}
+
+func (testNodeProvider) GetNode(_ context.Context, modelID, nodeID string) (models.Node, error) {
+	if modelID != validModelID || nodeID != validNodeID {
+		return models.Node{}, errorz.NewNotFoundError("node %s not found", nodeID)
+	}
+
+	return models.Node{ID: nodeID}, nil
+}
+
+func testAttachment(id, nodeID string) attachments.Attachment {
+	return attachments.Attachment{
+		ID:          id,
+		ModelID:     validModelID,
+		NodeID:      nodeID,
+		Name:        "notes.txt",
+		ContentType: "text/plain",
+		Size:        int64(len(validContent)),
+		SHA256:      validChecksum,
+		UploadedBy:  editorActor.UserID,
+	}
+}
+
+func requireEventFired(t *testing.T, wantEvent attachments.EventType, listener *testListener) {
+	t.Helper()
+
+	eventFired := listener.eventFired
+
+	require.NotEmpty(t, eventFired)
+
+	ae, ok := attachments.ExtractAttachmentEvent(eventFired)
+
+	require.True(t, ok)
+
+	require.Equal(t, wantEvent, ae.Type)
+	require.NotEmpty(t, ae.Actor)
+	require.NotEmpty(t, ae.Attachment)
+	require.NotEmpty(t, ae.Timestamp)
+}
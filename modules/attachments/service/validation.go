@@ -0,0 +1,91 @@
+package service
+
+import (
+	"mime"
+	"regexp"
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+)
+
+// checksumPattern matches hex-encoded SHA-256 checksums.
+var checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`) //nolint:gochecknoglobals
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateID(id string) error {
+	return requireString(id, "id")
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateUploadData(data attachments.UploadData) error {
+	if err := validateModelID(data.ModelID); err != nil {
+		return err
+	}
+
+	if err := requireString(data.Name, "file name"); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(data.Name, `/\`) {
+		return errorz.NewValidationError("file name %q must not contain a path", data.Name)
+	}
+
+	if _, _, err := mime.ParseMediaType(data.ContentType); err != nil {
+		return errorz.NewValidationError("invalid content type %q", data.ContentType)
+	}
+
+	if data.SHA256 != "" && !checksumPattern.MatchString(data.SHA256) {
+		return errorz.NewValidationError("checksum must be a lowercase hex-encoded SHA-256 hash")
+	}
+
+	return nil
+}
+
+func validateAttachmentQuery(query attachments.AttachmentQuery) error {
+	if err := validateModelID(query.ModelID); err != nil {
+		return err
+	}
+
+	if query.ModelOnly && query.NodeID != "" {
+		return errorz.NewValidationError("node id cannot be combined with model only")
+	}
+
+	return nil
+}
+
+// isAllowedContentType checks the media type of the content type against the allowed
+// types. An allowed type may end with "/*" to allow all subtypes. All types are allowed
+// if the list is empty.
+func isAllowedContentType(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		if prefix, ok := strings.CutSuffix(a, "*"); ok && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+
+		if strings.EqualFold(a, mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
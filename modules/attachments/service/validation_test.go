@@ -0,0 +1,91 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateID("1"))
+	require.Error(t, validateID(""))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateUploadData(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*attachments.UploadData)) attachments.UploadData {
+		data := validUploadData
+		f(&data)
+
+		return data
+	}
+
+	require.NoError(t, validateUploadData(validUploadData))
+	require.NoError(t, validateUploadData(withData(func(d *attachments.UploadData) { d.NodeID = "" })))
+	require.NoError(t, validateUploadData(withData(func(d *attachments.UploadData) { d.SHA256 = validChecksum })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.ModelID = "" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.Name = "" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.Name = "../notes.txt" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.Name = `c:\notes.txt` })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.ContentType = "" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.ContentType = "text/" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) { d.SHA256 = "abc" })))
+	require.Error(t, validateUploadData(withData(func(d *attachments.UploadData) {
+		d.SHA256 = strings.ToUpper(validChecksum)
+	})))
+}
+
+func Test_validateAttachmentQuery(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateAttachmentQuery(attachments.AttachmentQuery{ModelID: "1"}))
+	require.NoError(t, validateAttachmentQuery(attachments.AttachmentQuery{ModelID: "1", NodeID: "2"}))
+	require.NoError(t, validateAttachmentQuery(attachments.AttachmentQuery{ModelID: "1", ModelOnly: true}))
+	require.Error(t, validateAttachmentQuery(attachments.AttachmentQuery{}))
+	require.Error(t, validateAttachmentQuery(attachments.AttachmentQuery{ModelID: "1", NodeID: "2", ModelOnly: true}))
+}
+
+func Test_isAllowedContentType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		contentType string
+		allowed     []string
+		want        bool
+	}{
+		"no-restriction":   {contentType: "application/zip", allowed: nil, want: true},
+		"exact":            {contentType: "application/pdf", allowed: []string{"application/pdf"}, want: true},
+		"case-insensitive": {contentType: "Application/PDF", allowed: []string{"application/pdf"}, want: true},
+		"with-parameters":  {contentType: "text/csv; charset=utf-8", allowed: []string{"text/csv"}, want: true},
+		"wildcard":         {contentType: "image/png", allowed: []string{"application/pdf", "image/*"}, want: true},
+		"not-allowed":      {contentType: "application/zip", allowed: []string{"image/*"}, want: false},
+		"invalid":          {contentType: "image/", allowed: []string{"image/*"}, want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, isAllowedContentType(test.contentType, test.allowed))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/energimind/powermesh-core/modules/attachments/store/mongo"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+const testContent = "hello, attachments"
+
+func testAttachment(id, nodeID string, uploadedAt time.Time) attachments.Attachment {
+	return attachments.Attachment{
+		ID:          id,
+		ModelID:     "model1",
+		NodeID:      nodeID,
+		Name:        "notes-" + id + ".txt",
+		ContentType: "text/plain",
+		Size:        int64(len(testContent)),
+		SHA256:      "27e502ba3ac6caeb3f5fc55e802865191bb96e9b0a82e749d327f1c5b37cf85e",
+		UploadedBy:  "user1",
+		UploadedAt:  uploadedAt,
+	}
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.AttachmentStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewAttachmentStore(db)
+
+	f(t, ctx, store)
+}
@@ -0,0 +1,2 @@
+// Package mongo provides a MongoDB implementation of the attachment store.
+package mongo
@@ -0,0 +1,35 @@
+package mongo
+
+import "github.com/energimind/powermesh-core/modules/attachments"
+
+func toStoreAttachment(a attachments.Attachment) storeAttachment {
+	return storeAttachment{
+		ID:          a.ID,
+		ModelID:     a.ModelID,
+		NodeID:      a.NodeID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		SHA256:      a.SHA256,
+		UploadedBy:  a.UploadedBy,
+		UploadedAt:  a.UploadedAt,
+	}
+}
+
+func fromStoreAttachment(a storeAttachment) attachments.Attachment {
+	return attachments.Attachment{
+		ID:          a.ID,
+		ModelID:     a.ModelID,
+		NodeID:      a.NodeID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		SHA256:      a.SHA256,
+		UploadedBy:  a.UploadedBy,
+		UploadedAt:  a.UploadedAt,
+	}
+}
+
+func projectAttachmentID(a storeAttachment) string {
+	return a.ID
+}
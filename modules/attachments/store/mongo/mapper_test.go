@@ -0,0 +1,15 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreAttachment, toStoreAttachment(validAttachment))
+	require.Equal(t, validAttachment, fromStoreAttachment(validStoreAttachment))
+	require.Equal(t, validAttachment.ID, projectAttachmentID(validStoreAttachment))
+}
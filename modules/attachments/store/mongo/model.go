@@ -0,0 +1,16 @@
+package mongo
+
+import "time"
+
+// storeAttachment represents the metadata of an attachment in the MongoDB store.
+type storeAttachment struct {
+	ID          string    `bson:"id"`
+	ModelID     string    `bson:"modelId"`
+	NodeID      string    `bson:"nodeId"`
+	Name        string    `bson:"name"`
+	ContentType string    `bson:"contentType"`
+	Size        int64     `bson:"size"`
+	SHA256      string    `bson:"sha256"`
+	UploadedBy  string    `bson:"uploadedBy"`
+	UploadedAt  time.Time `bson:"uploadedAt"`
+}
@@ -0,0 +1,32 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/attachments"
+)
+
+var (
+	validAttachment = attachments.Attachment{
+		ID:          "1",
+		ModelID:     "model1",
+		NodeID:      "node1",
+		Name:        "diagram.pdf",
+		ContentType: "application/pdf",
+		Size:        1024,
+		SHA256:      "5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef",
+		UploadedBy:  "user1",
+		UploadedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	validStoreAttachment = storeAttachment{
+		ID:          validAttachment.ID,
+		ModelID:     validAttachment.ModelID,
+		NodeID:      validAttachment.NodeID,
+		Name:        validAttachment.Name,
+		ContentType: validAttachment.ContentType,
+		Size:        validAttachment.Size,
+		SHA256:      validAttachment.SHA256,
+		UploadedBy:  validAttachment.UploadedBy,
+		UploadedAt:  validAttachment.UploadedAt,
+	}
+)
@@ -0,0 +1,187 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collAttachments = "attachments"
+	bucketContents  = "attachments"
+	fieldID         = "id"
+	fieldModelID    = "modelId"
+	fieldNodeID     = "nodeId"
+	fieldUploadedAt = "uploadedAt"
+)
+
+// AttachmentStore is a MongoDB implementation of the attachment store.
+//
+// The metadata of the attachments is kept in a regular collection and the contents in a
+// GridFS bucket, with the attachment ID as the file ID.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type AttachmentStore struct {
+	db          *mongo.Database
+	attachments *mongo.Collection
+}
+
+// NewAttachmentStore creates a new MongoDB attachment store.
+func NewAttachmentStore(db *mongo.Database) *AttachmentStore {
+	return &AttachmentStore{
+		db:          db,
+		attachments: db.Collection(collAttachments),
+	}
+}
+
+// CreateAttachment implements the attachment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentStore) CreateAttachment(ctx context.Context, attachment attachments.Attachment) error {
+	return q.CreateOne(s.attachments, toStoreAttachment).Exec(ctx, attachment)
+}
+
+// DeleteAttachment implements the attachment store interface.
+// It removes the metadata only; the content is removed with DeleteContent.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentStore) DeleteAttachment(ctx context.Context, id string) error {
+	return q.DeleteOne(s.attachments).Exec(ctx, id)
+}
+
+// DeleteModelAttachments implements the attachment store interface.
+// It removes the metadata and the contents of all attachments of the model.
+func (s *AttachmentStore) DeleteModelAttachments(ctx context.Context, modelID string) error {
+	return s.deleteAttachments(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+}
+
+// DeleteNodeAttachments implements the attachment store interface.
+// It removes the metadata and the contents of all attachments of the nodes.
+func (s *AttachmentStore) DeleteNodeAttachments(ctx context.Context, modelID string, nodeIDs []string) error {
+	return s.deleteAttachments(ctx, q.Filter{}.EQ(fieldModelID, modelID).IN(fieldNodeID, nodeIDs))
+}
+
+// GetAttachment implements the attachment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentStore) GetAttachment(ctx context.Context, id string) (attachments.Attachment, error) {
+	return q.GetOne(s.attachments, fromStoreAttachment).Exec(ctx, id)
+}
+
+// GetAttachments implements the attachment store interface.
+// The attachments are returned in the order they were uploaded.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentStore) GetAttachments(
+	ctx context.Context,
+	query attachments.AttachmentQuery,
+) ([]attachments.Attachment, error) {
+	filter := q.Filter{}.EQ(fieldModelID, query.ModelID)
+
+	switch {
+	case query.NodeID != "":
+		filter = filter.EQ(fieldNodeID, query.NodeID)
+	case query.ModelOnly:
+		filter = filter.EQ(fieldNodeID, "")
+	}
+
+	return q.FindMany(s.attachments, fromStoreAttachment).
+		WithSort(fieldUploadedAt, false).
+		WithSort(fieldID, false).
+		Exec(ctx, filter)
+}
+
+// UploadContent implements the attachment store interface.
+// The content is read until EOF; a partially written content is removed if reading fails.
+func (s *AttachmentStore) UploadContent(ctx context.Context, id, name string, content io.Reader) error {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.UploadFromStreamWithID(id, name, content); err != nil {
+		return errorz.NewStoreError("failed to upload content of attachment %s: %v", id, err)
+	}
+
+	return nil
+}
+
+// OpenContent implements the attachment store interface.
+// The caller must close the returned content.
+func (s *AttachmentStore) OpenContent(ctx context.Context, id string) (io.ReadCloser, error) {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := bucket.OpenDownloadStream(id)
+	if err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, errorz.NewNotFoundError("content of attachment %s not found", id)
+		}
+
+		return nil, errorz.NewStoreError("failed to open content of attachment %s: %v", id, err)
+	}
+
+	return stream, nil
+}
+
+// DeleteContent implements the attachment store interface.
+// It succeeds if there is no content.
+func (s *AttachmentStore) DeleteContent(ctx context.Context, id string) error {
+	bucket, err := s.bucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.DeleteContext(ctx, id); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return errorz.NewStoreError("failed to delete content of attachment %s: %v", id, err)
+	}
+
+	return nil
+}
+
+// deleteAttachments removes the contents and the metadata of the attachments matching the
+// filter. The contents are removed first, so a failed removal can be repeated.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *AttachmentStore) deleteAttachments(ctx context.Context, filter q.Filter) error {
+	ids, err := q.FindMany(s.attachments, projectAttachmentID).WithProjection(fieldID).Exec(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.DeleteContent(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	_, err = q.DeleteMany(s.attachments).Exec(ctx, filter)
+
+	return err
+}
+
+// bucket returns the GridFS bucket of the contents. The bucket operations without a context
+// get the deadline of the given context.
+func (s *AttachmentStore) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(s.db, options.GridFSBucket().SetName(bucketContents))
+	if err != nil {
+		return nil, errorz.NewStoreError("failed to open %s bucket: %v", bucketContents, err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = bucket.SetReadDeadline(deadline)
+		_ = bucket.SetWriteDeadline(deadline)
+	}
+
+	return bucket, nil
+}
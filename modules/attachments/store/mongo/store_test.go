@@ -0,0 +1,186 @@
+package mongo_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/attachments"
+	"github.com/energimind/powermesh-core/modules/attachments/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// createAttachment stores the metadata and the content of the attachment.
+func createAttachment(t *testing.T, ctx context.Context, store *mongo.AttachmentStore, a attachments.Attachment) {
+	t.Helper()
+
+	require.NoError(t, store.UploadContent(ctx, a.ID, a.Name, strings.NewReader(testContent)))
+	require.NoError(t, store.CreateAttachment(ctx, a))
+}
+
+func readContent(t *testing.T, ctx context.Context, store *mongo.AttachmentStore, id string) (string, error) {
+	t.Helper()
+
+	content, err := store.OpenContent(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		require.NoError(t, content.Close())
+	}()
+
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+
+	return string(data), nil
+}
+
+func attachmentIDs(found []attachments.Attachment) []string {
+	ids := make([]string, 0, len(found))
+
+	for _, a := range found {
+		ids = append(ids, a.ID)
+	}
+
+	return ids
+}
+
+func TestAttachmentStore_CreateAttachment(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.AttachmentStore) {
+		attachment := testAttachment("1", "node1", testTime)
+
+		createAttachment(t, ctx, store, attachment)
+
+		created, err := store.GetAttachment(ctx, attachment.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, attachment, created)
+
+		content, err := readContent(t, ctx, store, attachment.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, testContent, content)
+	})
+}
+
+func TestAttachmentStore_DeleteAttachment(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.AttachmentStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.DeleteAttachment(ctx, "missing"))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			attachment := testAttachment("1", "node1", testTime)
+
+			createAttachment(t, ctx, store, attachment)
+
+			require.NoError(t, store.DeleteContent(ctx, attachment.ID))
+			require.NoError(t, store.DeleteAttachment(ctx, attachment.ID))
+
+			_, err := store.GetAttachment(ctx, attachment.ID)
+			require.IsType(t, errorz.NotFoundError{}, err)
+
+			_, err = readContent(t, ctx, store, attachment.ID)
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+
+		t.Run("missing-content", func(t *testing.T) {
+			require.NoError(t, store.DeleteContent(ctx, "missing"))
+		})
+	})
+}
+
+func TestAttachmentStore_DeleteModelAttachments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.AttachmentStore) {
+		createAttachment(t, ctx, store, testAttachment("1", "node1", testTime))
+		createAttachment(t, ctx, store, testAttachment("2", "", testTime))
+
+		require.NoError(t, store.DeleteModelAttachments(ctx, "model1"))
+
+		found, err := store.GetAttachments(ctx, attachments.AttachmentQuery{ModelID: "model1"})
+
+		require.NoError(t, err)
+		require.Empty(t, found)
+
+		_, err = readContent(t, ctx, store, "1")
+		require.IsType(t, errorz.NotFoundError{}, err)
+	})
+}
+
+func TestAttachmentStore_DeleteNodeAttachments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.AttachmentStore) {
+		createAttachment(t, ctx, store, testAttachment("1", "node1", testTime))
+		createAttachment(t, ctx, store, testAttachment("2", "node2", testTime))
+		createAttachment(t, ctx, store, testAttachment("3", "", testTime))
+
+		require.NoError(t, store.DeleteNodeAttachments(ctx, "model1", []string{"node1"}))
+
+		found, err := store.GetAttachments(ctx, attachments.AttachmentQuery{ModelID: "model1"})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"2", "3"}, attachmentIDs(found))
+
+		_, err = readContent(t, ctx, store, "1")
+		require.IsType(t, errorz.NotFoundError{}, err)
+
+		content, err := readContent(t, ctx, store, "2")
+
+		require.NoError(t, err)
+		require.Equal(t, testContent, content)
+	})
+}
+
+func TestAttachmentStore_GetAttachments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.AttachmentStore) {
+		createAttachment(t, ctx, store, testAttachment("1", "node1", testTime.Add(time.Hour)))
+		createAttachment(t, ctx, store, testAttachment("2", "node1", testTime))
+		createAttachment(t, ctx, store, testAttachment("3", "", testTime))
+
+		tests := map[string]struct {
+			query   attachments.AttachmentQuery
+			wantIDs []string
+		}{
+			"model": {
+				query:   attachments.AttachmentQuery{ModelID: "model1"},
+				wantIDs: []string{"2", "3", "1"},
+			},
+			"node": {
+				query:   attachments.AttachmentQuery{ModelID: "model1", NodeID: "node1"},
+				wantIDs: []string{"2", "1"},
+			},
+			"model-only": {
+				query:   attachments.AttachmentQuery{ModelID: "model1", ModelOnly: true},
+				wantIDs: []string{"3"},
+			},
+			"other-model": {
+				query:   attachments.AttachmentQuery{ModelID: "model2"},
+				wantIDs: []string{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				found, err := store.GetAttachments(ctx, test.query)
+
+				require.NoError(t, err)
+				require.Equal(t, test.wantIDs, attachmentIDs(found))
+			})
+		}
+	})
+}
@@ -0,0 +1,3 @@
+// Package changes provides a model and service for change requests: proposed edits of a
+// mesh that are reviewed and approved before they are applied.
+package changes
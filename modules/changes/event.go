@@ -0,0 +1,53 @@
+package changes
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// EventType is the type of event that occurred.
+type EventType string
+
+// Event types.
+const (
+	ChangeRequestOpened    EventType = "change-request.opened"
+	ChangeRequestUpdated   EventType = "change-request.updated"
+	ChangeRequestCommented EventType = "change-request.commented"
+	ChangeRequestApproved  EventType = "change-request.approved"
+	ChangeRequestRejected  EventType = "change-request.rejected"
+	ChangeRequestApplied   EventType = "change-request.applied"
+)
+
+// Event models an event that occurs in the change request service.
+type Event interface {
+	IsChangeRequestEvent() bool
+}
+
+// EventHeader models the header of an event.
+type EventHeader struct {
+	Type      EventType
+	Actor     access.Actor
+	Timestamp time.Time
+}
+
+// ChangeRequestEvent models an event that occurs in the change request service related
+// to a change request.
+type ChangeRequestEvent struct {
+	EventHeader
+	ChangeRequest ChangeRequest
+}
+
+// IsChangeRequestEvent implements the Event interface.
+func (ChangeRequestEvent) IsChangeRequestEvent() bool {
+	return true
+}
+
+// ExtractChangeRequestEvent extracts a change request event from an event.
+func ExtractChangeRequestEvent(e Event) (ChangeRequestEvent, bool) {
+	if ce, ok := e.(ChangeRequestEvent); ok {
+		return ce, true
+	}
+
+	return ChangeRequestEvent{}, false
+}
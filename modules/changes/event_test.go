@@ -0,0 +1,26 @@
+package changes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractChangeRequestEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		ce, ok := ExtractChangeRequestEvent(ChangeRequestEvent{EventHeader: EventHeader{Type: ChangeRequestOpened}})
+
+		require.True(t, ok)
+		require.NotZero(t, ce)
+		require.True(t, ce.IsChangeRequestEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ce, ok := ExtractChangeRequestEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, ce)
+	})
+}
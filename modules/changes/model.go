@@ -0,0 +1,159 @@
+package changes
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// ChangeRequest represents a proposed change of a mesh.
+//
+// The change request holds the changeset and the base snapshot: the elements touched by
+// the changeset as they were when the change request was opened. When the change request
+// is applied, the base is compared with the current mesh to detect conflicting edits.
+type ChangeRequest struct {
+	ID          string
+	ModelID     string
+	Title       string
+	Description string
+	AuthorID    string
+	Reviewers   []string // user IDs of the assigned reviewers (optional)
+	Status      Status
+	Changeset   Changeset
+	Base        models.Mesh // touched elements as they were when the change request was opened
+	Comments    []Comment
+	CreatedIDs  map[string]string // placeholder ID of a created element -> assigned public ID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ReviewedBy  string
+	ReviewedAt  time.Time
+	AppliedBy   string
+	AppliedAt   time.Time
+}
+
+// Changeset represents a set of changes of mesh elements.
+//
+// Created elements are identified by placeholder IDs, which relations of the same changeset
+// can use as their ends. The public IDs of created elements are assigned when the changeset
+// is applied.
+type Changeset struct {
+	Nodes     []NodeChange
+	Relations []RelationChange
+}
+
+// IsEmpty checks if the changeset has no changes.
+func (c Changeset) IsEmpty() bool {
+	return len(c.Nodes) == 0 && len(c.Relations) == 0
+}
+
+// NodeChange represents a change of a node. The data is ignored for deletions.
+type NodeChange struct {
+	Op     Op
+	NodeID string // public ID, or placeholder ID for creations
+	Data   models.NodeData
+}
+
+// RelationChange represents a change of a relation. The data is ignored for deletions.
+type RelationChange struct {
+	Op         Op
+	RelationID string // public ID, or placeholder ID for creations
+	Data       models.RelationData
+}
+
+// Comment represents a review comment on a change request.
+type Comment struct {
+	AuthorID  string
+	Text      string
+	CreatedAt time.Time
+}
+
+// Op represents the operation of a change.
+type Op int
+
+// Op enumeration.
+const (
+	OpCreate Op = iota
+	OpUpdate
+	OpDelete
+)
+
+// AllOps is a list of all operations. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllOps = []Op{
+	OpCreate,
+	OpUpdate,
+	OpDelete,
+}
+
+// String returns the string representation of the operation.
+func (o Op) String() string {
+	switch o {
+	case OpCreate:
+		return "create"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	}
+
+	return "Op(" + strconv.Itoa(int(o)) + ")"
+}
+
+// Status represents the status of a change request.
+//
+// A change request is opened, then approved or rejected by a reviewer. Approved change
+// requests are applied to the mesh.
+type Status int
+
+// Status enumeration.
+const (
+	StatusOpen Status = iota
+	StatusApproved
+	StatusRejected
+	StatusApplied
+)
+
+// AllStatuses is a list of all statuses. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllStatuses = []Status{
+	StatusOpen,
+	StatusApproved,
+	StatusRejected,
+	StatusApplied,
+}
+
+// String returns the string representation of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusOpen:
+		return "open"
+	case StatusApproved:
+		return "approved"
+	case StatusRejected:
+		return "rejected"
+	case StatusApplied:
+		return "applied"
+	}
+
+	return "Status(" + strconv.Itoa(int(s)) + ")"
+}
+
+// CanTransitionTo checks if the change request can move from this status to the given one.
+// Open change requests can be approved or rejected, approved ones can be applied.
+func (s Status) CanTransitionTo(next Status) bool {
+	switch s {
+	case StatusOpen:
+		return next == StatusApproved || next == StatusRejected
+	case StatusApproved:
+		return next == StatusApplied
+	case StatusRejected, StatusApplied:
+		return false
+	}
+
+	return false
+}
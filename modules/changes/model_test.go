@@ -0,0 +1,73 @@
+package changes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeset_IsEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Changeset{}.IsEmpty())
+	require.False(t, Changeset{Nodes: []NodeChange{{Op: OpDelete, NodeID: "1"}}}.IsEmpty())
+	require.False(t, Changeset{Relations: []RelationChange{{Op: OpDelete, RelationID: "1"}}}.IsEmpty())
+}
+
+func TestOp_String(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range AllOps {
+		t.Run(o.String(), func(t *testing.T) {
+			require.NotEmpty(t, o.String())
+			require.False(t, strings.HasPrefix(o.String(), "Op("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		require.Equal(t, "Op(100)", Op(100).String())
+	})
+}
+
+func TestStatus_String(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range AllStatuses {
+		t.Run(s.String(), func(t *testing.T) {
+			require.NotEmpty(t, s.String())
+			require.False(t, strings.HasPrefix(s.String(), "Status("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		require.Equal(t, "Status(100)", Status(100).String())
+	})
+}
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	t.Parallel()
+
+	allowed := map[Status][]Status{
+		StatusOpen:     {StatusApproved, StatusRejected},
+		StatusApproved: {StatusApplied},
+	}
+
+	for _, from := range AllStatuses {
+		for _, to := range AllStatuses {
+			t.Run(from.String()+"-"+to.String(), func(t *testing.T) {
+				want := false
+
+				for _, s := range allowed[from] {
+					want = want || s == to
+				}
+
+				require.Equal(t, want, from.CanTransitionTo(to))
+			})
+		}
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		require.False(t, Status(100).CanTransitionTo(StatusOpen))
+	})
+}
@@ -0,0 +1,36 @@
+package changes
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// ChangeService defines the change request service.
+type ChangeService interface {
+	OpenChangeRequest(ctx context.Context, actor access.Actor, data ChangeRequestData) (ChangeRequest, error)
+	UpdateChangeRequest(ctx context.Context, actor access.Actor, id string, data ChangeRequestData) (ChangeRequest, error)
+	AssignReviewers(ctx context.Context, actor access.Actor, id string, reviewers []string) (ChangeRequest, error)
+	CommentChangeRequest(ctx context.Context, actor access.Actor, id, text string) (ChangeRequest, error)
+	ApproveChangeRequest(ctx context.Context, actor access.Actor, id string) (ChangeRequest, error)
+	RejectChangeRequest(ctx context.Context, actor access.Actor, id string) (ChangeRequest, error)
+	ApplyChangeRequest(ctx context.Context, actor access.Actor, id string) (ChangeRequest, error)
+	GetChangeRequest(ctx context.Context, id string) (ChangeRequest, error)
+	ListChangeRequests(ctx context.Context, query ChangeRequestQuery) ([]ChangeRequest, error)
+}
+
+// ChangeRequestData defines the change request data.
+// It is used to open or update a change request.
+type ChangeRequestData struct {
+	ModelID     string
+	Title       string
+	Description string
+	Changeset   Changeset
+}
+
+// ChangeRequestQuery defines the change request query.
+// It is used to list the change requests of a model, optionally restricted to some statuses.
+type ChangeRequestQuery struct {
+	ModelID  string
+	Statuses []Status
+}
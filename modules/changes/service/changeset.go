@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// captureBase returns the elements updated or deleted by the changeset as they are now.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) captureBase(
+	ctx context.Context,
+	modelID string,
+	changeset changes.Changeset,
+) (models.Mesh, error) {
+	base := models.Mesh{
+		ModelID:   modelID,
+		Nodes:     map[string]models.Node{},
+		Relations: map[string]models.Relation{},
+	}
+
+	for _, c := range changeset.Nodes {
+		if c.Op == changes.OpCreate {
+			continue
+		}
+
+		node, err := s.meshes.GetNode(ctx, modelID, c.NodeID)
+		if err != nil {
+			return models.Mesh{}, err
+		}
+
+		base.Nodes[node.ID] = node
+	}
+
+	for _, c := range changeset.Relations {
+		if c.Op == changes.OpCreate {
+			continue
+		}
+
+		relation, err := s.meshes.GetRelation(ctx, modelID, c.RelationID)
+		if err != nil {
+			return models.Mesh{}, err
+		}
+
+		base.Relations[relation.ID] = relation
+	}
+
+	return base, nil
+}
+
+// checkConflicts compares the base of the change request with the current mesh. It returns
+// a conflict error listing the elements changed or deleted since the base was captured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) checkConflicts(ctx context.Context, cr changes.ChangeRequest) error {
+	var conflicts []string
+
+	for _, id := range models.SortedKeys(cr.Base.Nodes) {
+		current, err := s.meshes.GetNode(ctx, cr.ModelID, id)
+
+		conflict, err := describeConflict("node", id, cr.Base.Nodes[id], current, err)
+		if err != nil {
+			return err
+		}
+
+		if conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	for _, id := range models.SortedKeys(cr.Base.Relations) {
+		current, err := s.meshes.GetRelation(ctx, cr.ModelID, id)
+
+		conflict, err := describeConflict("relation", id, cr.Base.Relations[id], current, err)
+		if err != nil {
+			return err
+		}
+
+		if conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return errorz.NewConflictError("change request %s conflicts with the current mesh: %s",
+			cr.ID, strings.Join(conflicts, ", "))
+	}
+
+	return nil
+}
+
+// describeConflict describes the difference between the base and the current version of
+// an element, or returns an empty string if there is none. The error of looking up the
+// current version is returned unless it reports a deleted element.
+func describeConflict[T models.Node | models.Relation](
+	element, id string,
+	base, current T,
+	lookupErr error,
+) (string, error) {
+	if lookupErr != nil {
+		if errorz.IsNotFoundError(lookupErr) {
+			return fmt.Sprintf("%s %s was deleted", element, id), nil
+		}
+
+		return "", lookupErr
+	}
+
+	if !models.SameElement(base, current) {
+		return fmt.Sprintf("%s %s was changed", element, id), nil
+	}
+
+	return "", nil
+}
+
+// applyChangeset applies the changeset through the mesh service. Nodes are created and
+// updated first, so relations can refer to them, and deleted last, after the relations.
+// It returns the public IDs assigned to the created elements by their placeholder IDs.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) applyChangeset(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	changeset changes.Changeset,
+) (map[string]string, error) {
+	created := map[string]string{}
+
+	resolve := func(id string) string {
+		if assigned, ok := created[id]; ok {
+			return assigned
+		}
+
+		return id
+	}
+
+	for _, c := range nodeChanges(changeset, changes.OpCreate) {
+		node, err := s.meshes.CreateNode(ctx, actor, modelID, c.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		created[c.NodeID] = node.ID
+	}
+
+	for _, c := range nodeChanges(changeset, changes.OpUpdate) {
+		if _, err := s.meshes.UpdateNode(ctx, actor, modelID, c.NodeID, c.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range relationChanges(changeset, changes.OpDelete) {
+		if err := s.meshes.DeleteRelation(ctx, actor, modelID, c.RelationID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range relationChanges(changeset, changes.OpCreate) {
+		data := c.Data
+		data.From, data.To = resolve(data.From), resolve(data.To)
+
+		relation, err := s.meshes.CreateRelation(ctx, actor, modelID, data)
+		if err != nil {
+			return nil, err
+		}
+
+		created[c.RelationID] = relation.ID
+	}
+
+	for _, c := range relationChanges(changeset, changes.OpUpdate) {
+		data := c.Data
+		data.From, data.To = resolve(data.From), resolve(data.To)
+
+		if _, err := s.meshes.UpdateRelation(ctx, actor, modelID, c.RelationID, data); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range nodeChanges(changeset, changes.OpDelete) {
+		if err := s.meshes.DeleteNode(ctx, actor, modelID, c.NodeID); err != nil {
+			return nil, err
+		}
+	}
+
+	return created, nil
+}
+
+// nodeChanges returns the node changes with the operation in changeset order.
+func nodeChanges(changeset changes.Changeset, op changes.Op) []changes.NodeChange {
+	var found []changes.NodeChange
+
+	for _, c := range changeset.Nodes {
+		if c.Op == op {
+			found = append(found, c)
+		}
+	}
+
+	return found
+}
+
+// relationChanges returns the relation changes with the operation in changeset order.
+func relationChanges(changeset changes.Changeset, op changes.Op) []changes.RelationChange {
+	var found []changes.RelationChange
+
+	for _, c := range changeset.Relations {
+		if c.Op == op {
+			found = append(found, c)
+		}
+	}
+
+	return found
+}
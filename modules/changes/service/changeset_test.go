@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_describeConflict(t *testing.T) {
+	t.Parallel()
+
+	base := models.Node{ID: "n1", Props: models.PropBag{"grid": {"voltage": 10}}}
+
+	tests := map[string]struct {
+		current     models.Node
+		lookupErr   error
+		want        string
+		wantErrType error
+	}{
+		"unchanged": {
+			current: models.Node{ID: "n1", Props: models.PropBag{"grid": {"voltage": 10.0}}},
+		},
+		"changed": {
+			current: models.Node{ID: "n1", Props: models.PropBag{"grid": {"voltage": 20}}},
+			want:    "node n1 was changed",
+		},
+		"deleted": {
+			lookupErr: errorz.NewNotFoundError("node n1 not found"),
+			want:      "node n1 was deleted",
+		},
+		"lookup-error": {
+			lookupErr:   errorz.NewStoreError("forced-error"),
+			wantErrType: errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := describeConflict("node", "n1", base, test.current, test.lookupErr)
+
+			if test.wantErrType != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErrType, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
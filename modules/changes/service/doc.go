@@ -0,0 +1,2 @@
+// Package service implements the change request service.
+package service
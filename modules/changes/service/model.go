@@ -0,0 +1,30 @@
+package service
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+func changeRequestFromData(
+	id string,
+	actor access.Actor,
+	data changes.ChangeRequestData,
+	base models.Mesh,
+	now time.Time,
+) changes.ChangeRequest {
+	return changes.ChangeRequest{
+		ID:          id,
+		ModelID:     data.ModelID,
+		Title:       data.Title,
+		Description: data.Description,
+		AuthorID:    actor.UserID,
+		Status:      changes.StatusOpen,
+		Changeset:   data.Changeset,
+		Base:        base,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_changeRequestFromData(t *testing.T) {
+	base := models.Mesh{ModelID: validModelID}
+
+	require.Equal(t,
+		changes.ChangeRequest{
+			ID:          validChangeID,
+			ModelID:     validChangeRequestData.ModelID,
+			Title:       validChangeRequestData.Title,
+			Description: validChangeRequestData.Description,
+			AuthorID:    authorActor.UserID,
+			Status:      changes.StatusOpen,
+			Changeset:   validChangeRequestData.Changeset,
+			Base:        base,
+			CreatedAt:   testTime,
+			UpdatedAt:   testTime,
+		},
+		changeRequestFromData(validChangeID, authorActor, validChangeRequestData, base, testTime),
+	)
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// idGenerator defines the external ID generator.
+type idGenerator interface {
+	GenerateID() string
+}
+
+// store defines the external change request store.
+type store interface {
+	CreateChangeRequest(ctx context.Context, cr changes.ChangeRequest) error
+	UpdateChangeRequest(ctx context.Context, cr changes.ChangeRequest) error
+	DeleteModelChangeRequests(ctx context.Context, modelID string) error
+	GetChangeRequest(ctx context.Context, id string) (changes.ChangeRequest, error)
+	GetChangeRequests(ctx context.Context, query changes.ChangeRequestQuery) ([]changes.ChangeRequest, error)
+}
+
+// listener defines the external change request event listener.
+type listener interface {
+	HandleChangeRequestEvent(ctx context.Context, event changes.Event) error
+}
+
+// meshEditor defines the external editor of meshes.
+// It is implemented by the mesh service.
+type meshEditor interface {
+	CreateNode(ctx context.Context, actor access.Actor, modelID string, data models.NodeData) (models.Node, error)
+	UpdateNode(
+		ctx context.Context,
+		actor access.Actor,
+		modelID, nodeID string,
+		data models.NodeData,
+	) (models.Node, error)
+	DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	CreateRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID string,
+		data models.RelationData,
+	) (models.Relation, error)
+	UpdateRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID, relationID string,
+		data models.RelationData,
+	) (models.Relation, error)
+	DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error
+	GetRelation(ctx context.Context, modelID, relationID string) (models.Relation, error)
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+	IsAdmin(actor access.Actor) bool
+}
+
+// modelProvider defines the external provider of models.
+// It is implemented by the model service.
+type modelProvider interface {
+	GetModel(ctx context.Context, id string) (models.Model, error)
+}
+
+// transactor defines the external runner of store transactions.
+type transactor interface {
+	SupportsTransactions(ctx context.Context) (bool, error)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ChangeService implements the change request service.
+//
+// It implements the changes.ChangeService interface.
+//
+// Change requests are proposed by actors with read access to the model and can only be
+// changed by their authors while they are open. They are approved or rejected by an actor
+// other than the author who holds the approval permission on the model; if reviewers are
+// assigned, only they (or admins) can decide. Approved change requests are applied by
+// actors with write access to the model.
+//
+// The changeset is applied through the mesh editor, so all mesh events are fired as for
+// direct edits. The mesh editor has to accept edits on published models, see the
+// ForReviewedChanges method of the mesh service: for change requests, the review takes the
+// place of the draft check. Before applying, the base of the change request is compared with the
+// current mesh and the change request is refused with a conflict error if the touched
+// elements were changed in the meantime. If a transactor is configured and the store
+// supports transactions, the changeset is applied in a single transaction; otherwise, a
+// failure leaves the changes made so far in place.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type ChangeService struct {
+	idGen    idGenerator
+	store    store
+	meshes   meshEditor
+	authz    authorizer
+	listener listener
+	models   modelProvider
+	tx       transactor
+	approval access.Permission
+	now      func() time.Time
+}
+
+// Ensure ChangeService implements the changes.ChangeService interface.
+var _ changes.ChangeService = (*ChangeService)(nil)
+
+// NewChangeService creates a new change request service.
+func NewChangeService(
+	store store,
+	idGen idGenerator,
+	meshes meshEditor,
+	authz authorizer,
+	opts ...Option,
+) *ChangeService {
+	svc := &ChangeService{
+		idGen:    idGen,
+		store:    store,
+		meshes:   meshes,
+		authz:    authz,
+		approval: access.PermissionApprove,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// OpenChangeRequest implements the changes.ChangeService interface.
+// It captures the base of the changeset from the current mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) OpenChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	data changes.ChangeRequestData,
+) (changes.ChangeRequest, error) {
+	if err := validateChangeRequestData(data); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.ensureChangeable(ctx, data.ModelID); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.authorize(ctx, actor, data.ModelID, access.PermissionRead); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	base, err := s.captureBase(ctx, data.ModelID, data.Changeset)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr := changeRequestFromData(s.idGen.GenerateID(), actor, data, base, s.now())
+
+	if err := s.store.CreateChangeRequest(ctx, cr); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.fireChangeRequestEvent(ctx, actor, changes.ChangeRequestOpened, cr); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	return cr, nil
+}
+
+// UpdateChangeRequest implements the changes.ChangeService interface.
+// Only the author can update an open change request. The base is captured again, so the
+// change request is checked against the mesh as it is now.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) UpdateChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	data changes.ChangeRequestData,
+) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := validateChangeRequestData(data); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.getOpenChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if cr.ModelID != data.ModelID {
+		return changes.ChangeRequest{}, errorz.NewValidationError("change request %s cannot be moved to model %s",
+			id, data.ModelID)
+	}
+
+	if cr.AuthorID != actor.UserID {
+		return changes.ChangeRequest{}, errorz.NewAccessDeniedError("user %s is not the author of change request %s",
+			actor.UserID, id)
+	}
+
+	base, err := s.captureBase(ctx, cr.ModelID, data.Changeset)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr.Title = data.Title
+	cr.Description = data.Description
+	cr.Changeset = data.Changeset
+	cr.Base = base
+	cr.UpdatedAt = s.now()
+
+	return s.saveChangeRequest(ctx, actor, changes.ChangeRequestUpdated, cr)
+}
+
+// AssignReviewers implements the changes.ChangeService interface.
+// The reviewers of an open change request can be assigned by its author or by actors who
+// can approve it. An empty list lets every actor with the approval permission decide.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) AssignReviewers(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	reviewers []string,
+) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.getOpenChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := validateReviewers(reviewers, cr.AuthorID); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if cr.AuthorID != actor.UserID {
+		if err := s.authorize(ctx, actor, cr.ModelID, s.approval); err != nil {
+			return changes.ChangeRequest{}, err
+		}
+	}
+
+	cr.Reviewers = slices.Clone(reviewers)
+	cr.UpdatedAt = s.now()
+
+	return s.saveChangeRequest(ctx, actor, changes.ChangeRequestUpdated, cr)
+}
+
+// CommentChangeRequest implements the changes.ChangeService interface.
+// Change requests can be commented by actors with read access to the model in any status.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) CommentChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	id, text string,
+) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := validateComment(text); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.store.GetChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.authorize(ctx, actor, cr.ModelID, access.PermissionRead); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	now := s.now()
+
+	cr.Comments = append(cr.Comments, changes.Comment{
+		AuthorID:  actor.UserID,
+		Text:      text,
+		CreatedAt: now,
+	})
+	cr.UpdatedAt = now
+
+	return s.saveChangeRequest(ctx, actor, changes.ChangeRequestCommented, cr)
+}
+
+// ApproveChangeRequest implements the changes.ChangeService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) ApproveChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (changes.ChangeRequest, error) {
+	return s.review(ctx, actor, id, changes.StatusApproved, changes.ChangeRequestApproved)
+}
+
+// RejectChangeRequest implements the changes.ChangeService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) RejectChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (changes.ChangeRequest, error) {
+	return s.review(ctx, actor, id, changes.StatusRejected, changes.ChangeRequestRejected)
+}
+
+// ApplyChangeRequest implements the changes.ChangeService interface.
+// It applies the changeset of an approved change request to the mesh, unless the touched
+// elements were changed since the base was captured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) ApplyChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.store.GetChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if !cr.Status.CanTransitionTo(changes.StatusApplied) {
+		return changes.ChangeRequest{}, errorz.NewStateError("change request %s is %s and cannot be applied",
+			id, cr.Status)
+	}
+
+	if err := s.ensureChangeable(ctx, cr.ModelID); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.authorize(ctx, actor, cr.ModelID, access.PermissionWrite); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	apply := func(ctx context.Context) error {
+		if err := s.checkConflicts(ctx, cr); err != nil {
+			return err
+		}
+
+		created, err := s.applyChangeset(ctx, actor, cr.ModelID, cr.Changeset)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+
+		cr.Status = changes.StatusApplied
+		cr.CreatedIDs = created
+		cr.AppliedBy = actor.UserID
+		cr.AppliedAt = now
+		cr.UpdatedAt = now
+
+		return s.store.UpdateChangeRequest(ctx, cr)
+	}
+
+	transactional, err := s.transactional(ctx)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if transactional {
+		err = s.tx.WithTransaction(ctx, apply)
+	} else {
+		err = apply(ctx)
+	}
+
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.fireChangeRequestEvent(ctx, actor, changes.ChangeRequestApplied, cr); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	return cr, nil
+}
+
+// GetChangeRequest implements the changes.ChangeService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) GetChangeRequest(ctx context.Context, id string) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.store.GetChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	return cr, nil
+}
+
+// ListChangeRequests implements the changes.ChangeService interface.
+// The change requests are returned in the order they were opened.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) ListChangeRequests(
+	ctx context.Context,
+	query changes.ChangeRequestQuery,
+) ([]changes.ChangeRequest, error) {
+	if err := validateChangeRequestQuery(query); err != nil {
+		return nil, err
+	}
+
+	found, err := s.store.GetChangeRequests(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// DeleteModelResources removes all change requests of the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelChangeRequests(ctx, modelID)
+}
+
+// review approves or rejects an open change request.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) review(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	status changes.Status,
+	eventType changes.EventType,
+) (changes.ChangeRequest, error) {
+	if err := validateID(id); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	cr, err := s.store.GetChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if !cr.Status.CanTransitionTo(status) {
+		return changes.ChangeRequest{}, errorz.NewStateError("change request %s cannot change from %s to %s",
+			id, cr.Status, status)
+	}
+
+	if cr.AuthorID == actor.UserID {
+		return changes.ChangeRequest{}, errorz.NewAccessDeniedError(
+			"user %s cannot review the own change request %s", actor.UserID, id)
+	}
+
+	if len(cr.Reviewers) > 0 && !slices.Contains(cr.Reviewers, actor.UserID) && !s.authz.IsAdmin(actor) {
+		return changes.ChangeRequest{}, errorz.NewAccessDeniedError(
+			"user %s is not a reviewer of change request %s", actor.UserID, id)
+	}
+
+	if err := s.authorize(ctx, actor, cr.ModelID, s.approval); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	now := s.now()
+
+	cr.Status = status
+	cr.ReviewedBy = actor.UserID
+	cr.ReviewedAt = now
+	cr.UpdatedAt = now
+
+	return s.saveChangeRequest(ctx, actor, eventType, cr)
+}
+
+// getOpenChangeRequest returns the change request if it is open.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) getOpenChangeRequest(ctx context.Context, id string) (changes.ChangeRequest, error) {
+	cr, err := s.store.GetChangeRequest(ctx, id)
+	if err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if cr.Status != changes.StatusOpen {
+		return changes.ChangeRequest{}, errorz.NewStateError("change request %s is %s and cannot be changed",
+			id, cr.Status)
+	}
+
+	return cr, nil
+}
+
+// saveChangeRequest stores the changed change request and fires the event.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) saveChangeRequest(
+	ctx context.Context,
+	actor access.Actor,
+	eventType changes.EventType,
+	cr changes.ChangeRequest,
+) (changes.ChangeRequest, error) {
+	if err := s.store.UpdateChangeRequest(ctx, cr); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	if err := s.fireChangeRequestEvent(ctx, actor, eventType, cr); err != nil {
+		return changes.ChangeRequest{}, err
+	}
+
+	return cr, nil
+}
+
+// ensureChangeable checks that the model is not archived.
+// The check is skipped if no model provider is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) ensureChangeable(ctx context.Context, modelID string) error {
+	if s.models == nil {
+		return nil
+	}
+
+	model, err := s.models.GetModel(ctx, modelID)
+	if err != nil {
+		return err
+	}
+
+	if model.Status == models.ModelStatusArchived {
+		return errorz.NewStateError("model %s is archived and cannot be changed", modelID)
+	}
+
+	return nil
+}
+
+// transactional checks if the change requests can be applied in a transaction.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) transactional(ctx context.Context) (bool, error) {
+	if s.tx == nil {
+		return false, nil
+	}
+
+	return s.tx.SupportsTransactions(ctx)
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// fireChangeRequestEvent fires a change request event.
+func (s *ChangeService) fireChangeRequestEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType changes.EventType,
+	cr changes.ChangeRequest,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := changes.ChangeRequestEvent{
+		EventHeader: changes.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		ChangeRequest: cr,
+	}
+
+	if err := s.listener.HandleChangeRequestEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
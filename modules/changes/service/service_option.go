@@ -0,0 +1,37 @@
+package service
+
+import "github.com/energimind/powermesh-core/access"
+
+// Option defines the option for the service.
+type Option func(service *ChangeService)
+
+// WithListener sets the listener for the service.
+func WithListener(listener listener) Option {
+	return func(s *ChangeService) {
+		s.listener = listener
+	}
+}
+
+// WithModelProvider sets the provider used to look up the status of the model a change
+// request belongs to. If set, the service refuses change requests on archived models.
+func WithModelProvider(provider modelProvider) Option {
+	return func(s *ChangeService) {
+		s.models = provider
+	}
+}
+
+// WithTransactor sets the transactor used to apply change requests in a single transaction
+// if the store supports transactions.
+func WithTransactor(tx transactor) Option {
+	return func(s *ChangeService) {
+		s.tx = tx
+	}
+}
+
+// WithApprovalPermission sets the permission on the model required to approve or reject
+// change requests. It defaults to the approve permission.
+func WithApprovalPermission(permission access.Permission) Option {
+	return func(s *ChangeService) {
+		s.approval = permission
+	}
+}
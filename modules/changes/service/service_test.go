@@ -0,0 +1,754 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeService_OpenChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*changes.ChangeRequestData)) changes.ChangeRequestData {
+		data := validChangeRequestData
+		f(&data)
+
+		return data
+	}
+
+	tests := map[string]struct {
+		actor         access.Actor
+		data          changes.ChangeRequestData
+		modelStatus   models.ModelStatus
+		storeError    bool
+		meshError     bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-data": {
+			actor:   authorActor,
+			data:    changes.ChangeRequestData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"archived-model": {
+			actor:       authorActor,
+			data:        validChangeRequestData,
+			modelStatus: models.ModelStatusArchived,
+			wantErr:     errorz.StateError{},
+		},
+		"no-access": {
+			actor:   strangerActor,
+			data:    validChangeRequestData,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"element-not-found": {
+			actor: authorActor,
+			data: withData(func(d *changes.ChangeRequestData) {
+				d.Changeset = changes.Changeset{
+					Nodes: []changes.NodeChange{{Op: changes.OpDelete, NodeID: "missing"}},
+				}
+			}),
+			wantErr: errorz.NotFoundError{},
+		},
+		"mesh-error": {
+			actor:     authorActor,
+			data:      validChangeRequestData,
+			meshError: true,
+			wantErr:   errorz.StoreError{},
+		},
+		"store-error": {
+			actor:      authorActor,
+			data:       validChangeRequestData,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         authorActor,
+			data:          validChangeRequestData,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor: authorActor,
+			data:  validChangeRequestData,
+		},
+		"success-admin": {
+			actor: adminActor,
+			data:  validChangeRequestData,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError)
+			tl := newTestListener(test.listenerError)
+			svc := newTestService(ts, newTestMeshEditor(test.meshError), tl,
+				WithModelProvider(testModelProvider{status: test.modelStatus}))
+
+			cr, err := svc.OpenChangeRequest(context.Background(), test.actor, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validChangeID, cr.ID)
+				require.Equal(t, test.actor.UserID, cr.AuthorID)
+				require.Equal(t, changes.StatusOpen, cr.Status)
+				require.Equal(t, testChangeRequest(changes.StatusOpen).Base, cr.Base)
+				require.Equal(t, cr, ts.requests[cr.ID])
+				requireEventFired(t, changes.ChangeRequestOpened, tl)
+			}
+		})
+	}
+}
+
+func TestChangeService_UpdateChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*changes.ChangeRequestData)) changes.ChangeRequestData {
+		data := validChangeRequestData
+		f(&data)
+
+		return data
+	}
+
+	tests := map[string]struct {
+		actor   access.Actor
+		id      string
+		data    changes.ChangeRequestData
+		status  changes.Status
+		wantErr error
+	}{
+		"invalid-id": {
+			actor:   authorActor,
+			data:    validChangeRequestData,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-data": {
+			actor:   authorActor,
+			id:      "cr",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   authorActor,
+			id:      "missing",
+			data:    validChangeRequestData,
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-open": {
+			actor:   authorActor,
+			id:      "cr",
+			data:    validChangeRequestData,
+			status:  changes.StatusApproved,
+			wantErr: errorz.StateError{},
+		},
+		"other-model": {
+			actor: authorActor,
+			id:    "cr",
+			data: withData(func(d *changes.ChangeRequestData) {
+				d.ModelID = "model2"
+			}),
+			wantErr: errorz.ValidationError{},
+		},
+		"not-author": {
+			actor:   reviewerActor,
+			id:      "cr",
+			data:    validChangeRequestData,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"success": {
+			actor: authorActor,
+			id:    "cr",
+			data: withData(func(d *changes.ChangeRequestData) {
+				d.Title = "remove the line"
+				d.Changeset = changes.Changeset{
+					Relations: []changes.RelationChange{{Op: changes.OpDelete, RelationID: validRelationID}},
+				}
+			}),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testChangeRequest(test.status))
+			tl := newTestListener(false)
+
+			cr, err := newTestService(ts, newTestMeshEditor(false), tl).
+				UpdateChangeRequest(context.Background(), test.actor, test.id, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.data.Title, cr.Title)
+				require.Equal(t, test.data.Changeset, cr.Changeset)
+				require.Empty(t, cr.Base.Nodes)
+				require.Contains(t, cr.Base.Relations, validRelationID)
+				require.Equal(t, cr, ts.requests[cr.ID])
+				requireEventFired(t, changes.ChangeRequestUpdated, tl)
+			}
+		})
+	}
+}
+
+func TestChangeService_AssignReviewers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor     access.Actor
+		id        string
+		reviewers []string
+		status    changes.Status
+		wantErr   error
+	}{
+		"invalid-id": {
+			actor:   authorActor,
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   authorActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-open": {
+			actor:     authorActor,
+			id:        "cr",
+			reviewers: []string{reviewerActor.UserID},
+			status:    changes.StatusRejected,
+			wantErr:   errorz.StateError{},
+		},
+		"author-as-reviewer": {
+			actor:     authorActor,
+			id:        "cr",
+			reviewers: []string{authorActor.UserID},
+			wantErr:   errorz.ValidationError{},
+		},
+		"no-approval-permission": {
+			actor:     guestActor,
+			id:        "cr",
+			reviewers: []string{reviewerActor.UserID},
+			wantErr:   errorz.AccessDeniedError{},
+		},
+		"success-author": {
+			actor:     authorActor,
+			id:        "cr",
+			reviewers: []string{reviewerActor.UserID},
+		},
+		"success-approver": {
+			actor:     reviewerActor,
+			id:        "cr",
+			reviewers: []string{reviewerActor.UserID, guestActor.UserID},
+		},
+		"success-clear": {
+			actor: authorActor,
+			id:    "cr",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testChangeRequest(test.status))
+			tl := newTestListener(false)
+
+			cr, err := newTestService(ts, newTestMeshEditor(false), tl).
+				AssignReviewers(context.Background(), test.actor, test.id, test.reviewers)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.reviewers, cr.Reviewers)
+				require.Equal(t, cr, ts.requests[cr.ID])
+				requireEventFired(t, changes.ChangeRequestUpdated, tl)
+			}
+		})
+	}
+}
+
+func TestChangeService_CommentChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor   access.Actor
+		id      string
+		text    string
+		status  changes.Status
+		wantErr error
+	}{
+		"invalid-id": {
+			actor:   reviewerActor,
+			text:    "looks good",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-text": {
+			actor:   reviewerActor,
+			id:      "cr",
+			text:    " ",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   reviewerActor,
+			id:      "missing",
+			text:    "looks good",
+			wantErr: errorz.NotFoundError{},
+		},
+		"no-access": {
+			actor:   strangerActor,
+			id:      "cr",
+			text:    "looks good",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"success-open": {
+			actor: guestActor,
+			id:    "cr",
+			text:  "looks good",
+		},
+		"success-applied": {
+			actor:  reviewerActor,
+			id:     "cr",
+			text:   "works fine",
+			status: changes.StatusApplied,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testChangeRequest(test.status))
+			tl := newTestListener(false)
+
+			cr, err := newTestService(ts, newTestMeshEditor(false), tl).
+				CommentChangeRequest(context.Background(), test.actor, test.id, test.text)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, cr.Comments, 1)
+				require.Equal(t, test.actor.UserID, cr.Comments[0].AuthorID)
+				require.Equal(t, test.text, cr.Comments[0].Text)
+				require.Equal(t, test.status, cr.Status)
+				require.Equal(t, cr, ts.requests[cr.ID])
+				requireEventFired(t, changes.ChangeRequestCommented, tl)
+			}
+		})
+	}
+}
+
+func TestChangeService_Review(t *testing.T) {
+	t.Parallel()
+
+	withReviewers := func(reviewers ...string) changes.ChangeRequest {
+		cr := testChangeRequest(changes.StatusOpen)
+		cr.Reviewers = reviewers
+
+		return cr
+	}
+
+	tests := map[string]struct {
+		actor      access.Actor
+		id         string
+		request    changes.ChangeRequest
+		approve    bool
+		approval   access.Permission
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			actor:   reviewerActor,
+			request: testChangeRequest(changes.StatusOpen),
+			approve: true,
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   reviewerActor,
+			id:      "missing",
+			request: testChangeRequest(changes.StatusOpen),
+			approve: true,
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-open": {
+			actor:   reviewerActor,
+			id:      "cr",
+			request: testChangeRequest(changes.StatusRejected),
+			approve: true,
+			wantErr: errorz.StateError{},
+		},
+		"own-change-request": {
+			actor:   authorActor,
+			id:      "cr",
+			request: testChangeRequest(changes.StatusOpen),
+			approve: true,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"not-a-reviewer": {
+			actor:   reviewerActor,
+			id:      "cr",
+			request: withReviewers(guestActor.UserID),
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"no-approval-permission": {
+			actor:   guestActor,
+			id:      "cr",
+			request: withReviewers(guestActor.UserID),
+			approve: true,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      reviewerActor,
+			id:         "cr",
+			request:    testChangeRequest(changes.StatusOpen),
+			approve:    true,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success-approve": {
+			actor:   reviewerActor,
+			id:      "cr",
+			request: testChangeRequest(changes.StatusOpen),
+			approve: true,
+		},
+		"success-reject": {
+			actor:   reviewerActor,
+			id:      "cr",
+			request: withReviewers(reviewerActor.UserID),
+		},
+		"success-admin-not-a-reviewer": {
+			actor:   adminActor,
+			id:      "cr",
+			request: withReviewers(reviewerActor.UserID),
+			approve: true,
+		},
+		"success-custom-approval-permission": {
+			actor:    guestActor,
+			id:       "cr",
+			request:  testChangeRequest(changes.StatusOpen),
+			approve:  true,
+			approval: access.PermissionRead,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, test.request)
+			tl := newTestListener(false)
+
+			var opts []Option
+
+			if test.approval != 0 {
+				opts = append(opts, WithApprovalPermission(test.approval))
+			}
+
+			svc := newTestService(ts, newTestMeshEditor(false), tl, opts...)
+
+			var (
+				cr  changes.ChangeRequest
+				err error
+			)
+
+			wantStatus, wantEvent := changes.StatusRejected, changes.ChangeRequestRejected
+
+			if test.approve {
+				wantStatus, wantEvent = changes.StatusApproved, changes.ChangeRequestApproved
+				cr, err = svc.ApproveChangeRequest(context.Background(), test.actor, test.id)
+			} else {
+				cr, err = svc.RejectChangeRequest(context.Background(), test.actor, test.id)
+			}
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, wantStatus, cr.Status)
+				require.Equal(t, test.actor.UserID, cr.ReviewedBy)
+				require.False(t, cr.ReviewedAt.IsZero())
+				require.Equal(t, cr, ts.requests[cr.ID])
+				requireEventFired(t, wantEvent, tl)
+			}
+		})
+	}
+}
+
+func TestChangeService_ApplyChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		status        changes.Status
+		modelStatus   models.ModelStatus
+		changeMesh    func(mesh models.Mesh)
+		transactional bool
+		listenerError bool
+		wantErr       error
+		wantConflict  string
+	}{
+		"invalid-id": {
+			actor:   reviewerActor,
+			status:  changes.StatusApproved,
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   reviewerActor,
+			id:      "missing",
+			status:  changes.StatusApproved,
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-approved": {
+			actor:   reviewerActor,
+			id:      "cr",
+			status:  changes.StatusOpen,
+			wantErr: errorz.StateError{},
+		},
+		"archived-model": {
+			actor:       reviewerActor,
+			id:          "cr",
+			status:      changes.StatusApproved,
+			modelStatus: models.ModelStatusArchived,
+			wantErr:     errorz.StateError{},
+		},
+		"no-write-permission": {
+			actor:   guestActor,
+			id:      "cr",
+			status:  changes.StatusApproved,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"conflict-changed-node": {
+			actor:  reviewerActor,
+			id:     "cr",
+			status: changes.StatusApproved,
+			changeMesh: func(mesh models.Mesh) {
+				node := mesh.Nodes[validNodeID]
+				node.Code = "B1"
+				mesh.Nodes[validNodeID] = node
+			},
+			wantErr:      errorz.ConflictError{},
+			wantConflict: "node node1 was changed",
+		},
+		"conflict-deleted-relation": {
+			actor:  reviewerActor,
+			id:     "cr",
+			status: changes.StatusApproved,
+			changeMesh: func(mesh models.Mesh) {
+				delete(mesh.Relations, validRelationID)
+			},
+			wantErr:      errorz.ConflictError{},
+			wantConflict: "relation relation1 was deleted",
+		},
+		"listener-error": {
+			actor:         reviewerActor,
+			id:            "cr",
+			status:        changes.StatusApproved,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor:  reviewerActor,
+			id:     "cr",
+			status: changes.StatusApproved,
+			changeMesh: func(mesh models.Mesh) {
+				node := mesh.Nodes[validOtherNodeID]
+				node.Code = "B2"
+				mesh.Nodes[validOtherNodeID] = node
+			},
+		},
+		"success-transactional": {
+			actor:         adminActor,
+			id:            "cr",
+			status:        changes.StatusApproved,
+			transactional: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testChangeRequest(test.status))
+			te := newTestMeshEditor(false)
+			tl := newTestListener(test.listenerError)
+			tx := &testTransactor{}
+
+			if test.changeMesh != nil {
+				test.changeMesh(te.mesh)
+			}
+
+			opts := []Option{WithModelProvider(testModelProvider{status: test.modelStatus})}
+
+			if test.transactional {
+				opts = append(opts, WithTransactor(tx))
+			}
+
+			cr, err := newTestService(ts, te, tl, opts...).
+				ApplyChangeRequest(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Contains(t, err.Error(), test.wantConflict)
+				require.Empty(t, cr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, changes.StatusApplied, cr.Status)
+			require.Equal(t, test.actor.UserID, cr.AppliedBy)
+			require.False(t, cr.AppliedAt.IsZero())
+			require.Equal(t, map[string]string{validNewNodeID: "n1", validNewRelationID: "r1"}, cr.CreatedIDs)
+			require.Equal(t, cr, ts.requests[cr.ID])
+			require.Equal(t, test.transactional, tx.used)
+			requireEventFired(t, changes.ChangeRequestApplied, tl)
+
+			require.Equal(t, models.PropBag{"grid": {"voltage": 20}}, te.mesh.Nodes[validNodeID].Props)
+			require.Contains(t, te.mesh.Nodes, "n1")
+			require.NotContains(t, te.mesh.Relations, validRelationID)
+			require.Equal(t, models.Relation{ID: "r1", Kind: "line", From: validNodeID, To: "n1"},
+				te.mesh.Relations["r1"])
+		})
+	}
+}
+
+func TestChangeService_GetChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id         string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			id:         "cr",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			id: "cr",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testChangeRequest(changes.StatusOpen))
+
+			cr, err := newTestService(ts, newTestMeshEditor(false), newTestListener(false)).
+				GetChangeRequest(context.Background(), test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, cr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testChangeRequest(changes.StatusOpen), cr)
+			}
+		})
+	}
+}
+
+func TestChangeService_ListChangeRequests(t *testing.T) {
+	t.Parallel()
+
+	applied := testChangeRequest(changes.StatusApplied)
+	applied.ID = "cr2"
+	applied.CreatedAt = testTime.Add(time.Minute)
+
+	tests := map[string]struct {
+		query      changes.ChangeRequestQuery
+		storeError bool
+		wantIDs    []string
+		wantErr    error
+	}{
+		"invalid-query": {
+			query:   changes.ChangeRequestQuery{},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			query:      validChangeRequestQuery,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"all": {
+			query:   validChangeRequestQuery,
+			wantIDs: []string{"cr", "cr2"},
+		},
+		"by-status": {
+			query: changes.ChangeRequestQuery{
+				ModelID:  validModelID,
+				Statuses: []changes.Status{changes.StatusApplied},
+			},
+			wantIDs: []string{"cr2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testChangeRequest(changes.StatusOpen), applied)
+
+			found, err := newTestService(ts, newTestMeshEditor(false), newTestListener(false)).
+				ListChangeRequests(context.Background(), test.query)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, found)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(found))
+
+			for _, cr := range found {
+				ids = append(ids, cr.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestChangeService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid-model-id", func(t *testing.T) {
+		ts := newTestStore(false, testChangeRequest(changes.StatusOpen))
+
+		err := newTestService(ts, newTestMeshEditor(false), newTestListener(false)).
+			DeleteModelResources(context.Background(), adminActor, "")
+
+		require.Error(t, err)
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ts := newTestStore(false, testChangeRequest(changes.StatusOpen))
+
+		err := newTestService(ts, newTestMeshEditor(false), newTestListener(false)).
+			DeleteModelResources(context.Background(), adminActor, validModelID)
+
+		require.NoError(t, err)
+		require.Empty(t, ts.requests)
+	})
+}
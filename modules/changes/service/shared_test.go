@@ -0,0 +1,519 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminActor              = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	authorActor             = access.Actor{UserID: "author", Role: access.RoleCreator}
+	reviewerActor           = access.Actor{UserID: "reviewer", Role: access.RoleCreator}
+	guestActor              = access.Actor{UserID: "guest", Role: access.RoleCreator}
+	strangerActor           = access.Actor{UserID: "stranger", Role: access.RoleCreator}
+	validModelID            = "model1"
+	validChangeID           = "1" // must match generated ID from testIDGenerator
+	validNodeID             = "node1"
+	validOtherNodeID        = "node2"
+	validRelationID         = "relation1"
+	validNewNodeID          = "new-node"
+	validNewRelationID      = "new-relation"
+	validChangeRequestQuery = changes.ChangeRequestQuery{ModelID: validModelID}
+	validChangeset          = changes.Changeset{
+		Nodes: []changes.NodeChange{
+			{
+				Op:     changes.OpUpdate,
+				NodeID: validNodeID,
+				Data:   models.NodeData{Kind: "bus", Props: models.PropBag{"grid": {"voltage": 20}}},
+			},
+			{
+				Op:     changes.OpCreate,
+				NodeID: validNewNodeID,
+				Data:   models.NodeData{Kind: "bus"},
+			},
+		},
+		Relations: []changes.RelationChange{
+			{
+				Op:         changes.OpDelete,
+				RelationID: validRelationID,
+			},
+			{
+				Op:         changes.OpCreate,
+				RelationID: validNewRelationID,
+				Data:       models.RelationData{Kind: "line", From: validNodeID, To: validNewNodeID},
+			},
+		},
+	}
+	validChangeRequestData = changes.ChangeRequestData{
+		ModelID:     validModelID,
+		Title:       "raise voltage",
+		Description: "the bus runs at 20 kV now",
+		Changeset:   validChangeset,
+	}
+	testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testIDGenerator struct {
+	idCounter atomic.Int64
+}
+
+// Ensure that the testIDGenerator implements the idGenerator interface.
+var _ idGenerator = (*testIDGenerator)(nil)
+
+func newTestIDGenerator() *testIDGenerator {
+	return &testIDGenerator{}
+}
+
+func (g *testIDGenerator) GenerateID() string {
+	return strconv.FormatInt(g.idCounter.Add(1), 10)
+}
+
+type testListener struct {
+	forcedError error
+	eventFired  changes.Event
+}
+
+// Ensure that the testListener implements the listener interface.
+var _ listener = (*testListener)(nil)
+
+func newTestListener(forcedError bool) *testListener {
+	var err error
+
+	if forcedError {
+		err = errorz.NewGatewayError("forced-error")
+	}
+
+	return &testListener{forcedError: err}
+}
+
+func (l *testListener) HandleChangeRequestEvent(_ context.Context, event changes.Event) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+// testStore keeps the change requests in memory.
+type testStore struct {
+	forcedError error
+	requests    map[string]changes.ChangeRequest
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...changes.ChangeRequest) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		requests:    map[string]changes.ChangeRequest{},
+	}
+
+	for _, cr := range initial {
+		s.requests[cr.ID] = cr
+	}
+
+	return s
+}
+
+func (s *testStore) CreateChangeRequest(_ context.Context, cr changes.ChangeRequest) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.requests[cr.ID] = cr
+
+	return nil
+}
+
+func (s *testStore) UpdateChangeRequest(_ context.Context, cr changes.ChangeRequest) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.requests[cr.ID]; !ok {
+		return errorz.NewNotFoundError("change request %s not found", cr.ID)
+	}
+
+	s.requests[cr.ID] = cr
+
+	return nil
+}
+
+func (s *testStore) DeleteModelChangeRequests(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, cr := range s.requests {
+		if cr.ModelID == modelID {
+			delete(s.requests, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) GetChangeRequest(_ context.Context, id string) (changes.ChangeRequest, error) {
+	if s.forcedError != nil {
+		return changes.ChangeRequest{}, s.forcedError
+	}
+
+	cr, ok := s.requests[id]
+	if !ok {
+		return changes.ChangeRequest{}, errorz.NewNotFoundError("change request %s not found", id)
+	}
+
+	return cr, nil
+}
+
+func (s *testStore) GetChangeRequests(
+	_ context.Context,
+	query changes.ChangeRequestQuery,
+) ([]changes.ChangeRequest, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []changes.ChangeRequest
+
+	for _, cr := range s.requests {
+		if cr.ModelID != query.ModelID ||
+			(len(query.Statuses) > 0 && !slices.Contains(query.Statuses, cr.Status)) {
+			continue
+		}
+
+		found = append(found, cr)
+	}
+
+	slices.SortFunc(found, func(a, b changes.ChangeRequest) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return found, nil
+}
+
+// testMeshEditor keeps the mesh of the valid model in memory. Created elements get the
+// IDs "n1", "n2", ... and "r1", "r2", ...
+type testMeshEditor struct {
+	forcedError error
+	mesh        models.Mesh
+	nodeCounter int
+	relCounter  int
+}
+
+// Ensure that the testMeshEditor implements the meshEditor interface.
+var _ meshEditor = (*testMeshEditor)(nil)
+
+// newTestMeshEditor returns an editor of the mesh with two nodes linked by a relation.
+func newTestMeshEditor(forcedError bool) *testMeshEditor {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testMeshEditor{
+		forcedError: err,
+		mesh: models.Mesh{
+			ModelID: validModelID,
+			Nodes: map[string]models.Node{
+				validNodeID:      {ID: validNodeID, Kind: "bus", Props: models.PropBag{"grid": {"voltage": 10}}},
+				validOtherNodeID: {ID: validOtherNodeID, Kind: "bus"},
+			},
+			Relations: map[string]models.Relation{
+				validRelationID: {ID: validRelationID, Kind: "line", From: validNodeID, To: validOtherNodeID},
+			},
+		},
+	}
+}
+
+func (e *testMeshEditor) CreateNode(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	data models.NodeData,
+) (models.Node, error) {
+	if err := e.check(modelID); err != nil {
+		return models.Node{}, err
+	}
+
+	e.nodeCounter++
+
+	node := models.Node{ID: "n" + strconv.Itoa(e.nodeCounter), Kind: data.Kind, Code: data.Code, Props: data.Props}
+	e.mesh.Nodes[node.ID] = node
+
+	return node, nil
+}
+
+func (e *testMeshEditor) UpdateNode(
+	ctx context.Context,
+	_ access.Actor,
+	modelID, nodeID string,
+	data models.NodeData,
+) (models.Node, error) {
+	if _, err := e.GetNode(ctx, modelID, nodeID); err != nil {
+		return models.Node{}, err
+	}
+
+	node := models.Node{ID: nodeID, Kind: data.Kind, Code: data.Code, Props: data.Props}
+	e.mesh.Nodes[nodeID] = node
+
+	return node, nil
+}
+
+func (e *testMeshEditor) DeleteNode(ctx context.Context, _ access.Actor, modelID, nodeID string) error {
+	if _, err := e.GetNode(ctx, modelID, nodeID); err != nil {
+		return err
+	}
+
+	delete(e.mesh.Nodes, nodeID)
+
+	return nil
+}
+
+func (e *testMeshEditor) GetNode(_ context.Context, modelID, nodeID string) (models.Node, error) {
+	if err := e.check(modelID); err != nil {
+		return models.Node{}, err
+	}
+
+	node, ok := e.mesh.Nodes[nodeID]
+	if !ok {
+		return models.Node{}, errorz.NewNotFoundError("node %s not found", nodeID)
+	}
+
+	return node, nil
+}
+
+func (e *testMeshEditor) CreateRelation(
+	ctx context.Context,
+	_ access.Actor,
+	modelID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if err := e.checkEnds(ctx, modelID, data); err != nil {
+		return models.Relation{}, err
+	}
+
+	e.relCounter++
+
+	relation := models.Relation{
+		ID:    "r" + strconv.Itoa(e.relCounter),
+		Kind:  data.Kind,
+		From:  data.From,
+		To:    data.To,
+		Props: data.Props,
+	}
+	e.mesh.Relations[relation.ID] = relation
+
+	return relation, nil
+}
+
+func (e *testMeshEditor) UpdateRelation(
+	ctx context.Context,
+	_ access.Actor,
+	modelID, relationID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if _, err := e.GetRelation(ctx, modelID, relationID); err != nil {
+		return models.Relation{}, err
+	}
+
+	if err := e.checkEnds(ctx, modelID, data); err != nil {
+		return models.Relation{}, err
+	}
+
+	relation := models.Relation{ID: relationID, Kind: data.Kind, From: data.From, To: data.To, Props: data.Props}
+	e.mesh.Relations[relationID] = relation
+
+	return relation, nil
+}
+
+func (e *testMeshEditor) DeleteRelation(ctx context.Context, _ access.Actor, modelID, relationID string) error {
+	if _, err := e.GetRelation(ctx, modelID, relationID); err != nil {
+		return err
+	}
+
+	delete(e.mesh.Relations, relationID)
+
+	return nil
+}
+
+func (e *testMeshEditor) GetRelation(_ context.Context, modelID, relationID string) (models.Relation, error) {
+	if err := e.check(modelID); err != nil {
+		return models.Relation{}, err
+	}
+
+	relation, ok := e.mesh.Relations[relationID]
+	if !ok {
+		return models.Relation{}, errorz.NewNotFoundError("relation %s not found", relationID)
+	}
+
+	return relation, nil
+}
+
+func (e *testMeshEditor) check(modelID string) error {
+	if e.forcedError != nil {
+		return e.forcedError
+	}
+
+	if modelID != validModelID {
+		return errorz.NewNotFoundError("mesh %s not found", modelID)
+	}
+
+	return nil
+}
+
+func (e *testMeshEditor) checkEnds(ctx context.Context, modelID string, data models.RelationData) error {
+	if _, err := e.GetNode(ctx, modelID, data.From); err != nil {
+		return err
+	}
+
+	if _, err := e.GetNode(ctx, modelID, data.To); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// testBindingProvider grants the reviewer the creator role and the author and the guest the
+// guest role on the valid model.
+type testBindingProvider struct{}
+
+func (testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	roles := map[string]access.Role{
+		authorActor.UserID:   access.RoleGuest,
+		reviewerActor.UserID: access.RoleCreator,
+		guestActor.UserID:    access.RoleGuest,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testModelProvider returns the valid model with the given status.
+type testModelProvider struct {
+	status models.ModelStatus
+}
+
+// Ensure that the testModelProvider implements the modelProvider interface.
+var _ modelProvider = testModelProvider{}
+
+func (p testModelProvider) GetModel(_ context.Context, id string) (models.Model, error) {
+	if id != validModelID {
+		return models.Model{}, errorz.NewNotFoundError("model %s not found", id)
+	}
+
+	return models.Model{ID: id, Status: p.status}, nil
+}
+
+// testTransactor runs the function directly and records that it was called.
+type testTransactor struct {
+	used bool
+}
+
+// Ensure that the testTransactor implements the transactor interface.
+var _ transactor = (*testTransactor)(nil)
+
+func (t *testTransactor) SupportsTransactions(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (t *testTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.used = true
+
+	return fn(ctx)
+}
+
+// testChangeRequest returns a change request of the author with the valid changeset and
+// the base captured from the test mesh.
+func testChangeRequest(status changes.Status) changes.ChangeRequest {
+	mesh := newTestMeshEditor(false).mesh
+
+	cr := changes.ChangeRequest{
+		ID:          "cr",
+		ModelID:     validModelID,
+		Title:       validChangeRequestData.Title,
+		Description: validChangeRequestData.Description,
+		AuthorID:    authorActor.UserID,
+		Status:      status,
+		Changeset:   validChangeset,
+		Base: models.Mesh{
+			ModelID:   validModelID,
+			Nodes:     map[string]models.Node{validNodeID: mesh.Nodes[validNodeID]},
+			Relations: map[string]models.Relation{validRelationID: mesh.Relations[validRelationID]},
+		},
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}
+
+	if status != changes.StatusOpen {
+		cr.ReviewedBy = reviewerActor.UserID
+		cr.ReviewedAt = testTime
+	}
+
+	return cr
+}
+
+func newTestService(ts *testStore, te *testMeshEditor, tl *testListener, opts ...Option) *ChangeService {
+	opts = append([]Option{WithListener(tl)}, opts...)
+
+	svc := NewChangeService(ts, newTestIDGenerator(), te, authz.NewAuthorizer(testBindingProvider{}), opts...)
+	svc.now = func() time.Time { return testTime.Add(time.Hour) }
+
+	return svc
+}
+
+func requireEventFired(t *testing.T, wantEvent changes.EventType, listener *testListener) {
+	t.Helper()
+
+	eventFired := listener.eventFired
+
+	require.NotEmpty(t, eventFired)
+
+	ce, ok := changes.ExtractChangeRequestEvent(eventFired)
+
+	require.True(t, ok)
+
+	require.Equal(t, wantEvent, ce.Type)
+	require.NotEmpty(t, ce.Actor)
+	require.NotEmpty(t, ce.ChangeRequest)
+	require.NotEmpty(t, ce.Timestamp)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+)
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateID(id string) error {
+	return requireString(id, "id")
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateChangeRequestData(data changes.ChangeRequestData) error {
+	if err := validateModelID(data.ModelID); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(data.Title) == "" {
+		return errorz.NewValidationError("change request title is required")
+	}
+
+	return validateChangeset(data.Changeset)
+}
+
+// validateChangeset checks that the changeset is not empty and that every element is
+// changed only once. The node and relation data is validated by the mesh service when
+// the changeset is applied.
+func validateChangeset(changeset changes.Changeset) error {
+	if changeset.IsEmpty() {
+		return errorz.NewValidationError("changeset is empty")
+	}
+
+	nodeIDs := make(map[string]bool, len(changeset.Nodes))
+
+	for _, c := range changeset.Nodes {
+		if err := validateChange(c.Op, c.NodeID, "node", nodeIDs); err != nil {
+			return err
+		}
+	}
+
+	relationIDs := make(map[string]bool, len(changeset.Relations))
+
+	for _, c := range changeset.Relations {
+		if err := validateChange(c.Op, c.RelationID, "relation", relationIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateChange(op changes.Op, id, element string, seen map[string]bool) error {
+	if !slices.Contains(changes.AllOps, op) {
+		return errorz.NewValidationError("invalid %s change operation: %v", element, op)
+	}
+
+	if err := requireString(id, element+" id"); err != nil {
+		return err
+	}
+
+	if seen[id] {
+		return errorz.NewValidationError("%s %s is changed more than once", element, id)
+	}
+
+	seen[id] = true
+
+	return nil
+}
+
+// validateReviewers checks that the reviewers are distinct and do not include the author.
+func validateReviewers(reviewers []string, authorID string) error {
+	seen := make(map[string]bool, len(reviewers))
+
+	for _, r := range reviewers {
+		if err := requireString(r, "reviewer id"); err != nil {
+			return err
+		}
+
+		if r == authorID {
+			return errorz.NewValidationError("the author cannot review the own change request")
+		}
+
+		if seen[r] {
+			return errorz.NewValidationError("reviewer %s is assigned more than once", r)
+		}
+
+		seen[r] = true
+	}
+
+	return nil
+}
+
+func validateComment(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errorz.NewValidationError("comment text is required")
+	}
+
+	return nil
+}
+
+func validateChangeRequestQuery(query changes.ChangeRequestQuery) error {
+	if err := validateModelID(query.ModelID); err != nil {
+		return err
+	}
+
+	for _, s := range query.Statuses {
+		if !slices.Contains(changes.AllStatuses, s) {
+			return errorz.NewValidationError("invalid change request status: %v", s)
+		}
+	}
+
+	return nil
+}
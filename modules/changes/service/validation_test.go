@@ -0,0 +1,154 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateID("1"))
+	require.Error(t, validateID(""))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateChangeRequestData(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*changes.ChangeRequestData)) changes.ChangeRequestData {
+		data := validChangeRequestData
+		f(&data)
+
+		return data
+	}
+
+	tests := map[string]struct {
+		data    changes.ChangeRequestData
+		wantErr bool
+	}{
+		"valid": {data: validChangeRequestData},
+		"no-description": {data: withData(func(d *changes.ChangeRequestData) {
+			d.Description = ""
+		})},
+		"missing-model-id": {data: withData(func(d *changes.ChangeRequestData) {
+			d.ModelID = ""
+		}), wantErr: true},
+		"blank-title": {data: withData(func(d *changes.ChangeRequestData) {
+			d.Title = " "
+		}), wantErr: true},
+		"empty-changeset": {data: withData(func(d *changes.ChangeRequestData) {
+			d.Changeset = changes.Changeset{}
+		}), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateChangeRequestData(test.data)
+
+			if test.wantErr {
+				require.Error(t, err)
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateChangeset(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		changeset changes.Changeset
+		wantErr   bool
+	}{
+		"valid": {changeset: validChangeset},
+		"same-id-node-and-relation": {changeset: changes.Changeset{
+			Nodes:     []changes.NodeChange{{Op: changes.OpDelete, NodeID: "x"}},
+			Relations: []changes.RelationChange{{Op: changes.OpDelete, RelationID: "x"}},
+		}},
+		"empty": {changeset: changes.Changeset{}, wantErr: true},
+		"invalid-node-op": {changeset: changes.Changeset{
+			Nodes: []changes.NodeChange{{Op: 100, NodeID: "n1"}},
+		}, wantErr: true},
+		"missing-node-id": {changeset: changes.Changeset{
+			Nodes: []changes.NodeChange{{Op: changes.OpCreate}},
+		}, wantErr: true},
+		"duplicate-node": {changeset: changes.Changeset{
+			Nodes: []changes.NodeChange{{Op: changes.OpUpdate, NodeID: "n1"}, {Op: changes.OpDelete, NodeID: "n1"}},
+		}, wantErr: true},
+		"invalid-relation-op": {changeset: changes.Changeset{
+			Relations: []changes.RelationChange{{Op: -1, RelationID: "r1"}},
+		}, wantErr: true},
+		"duplicate-relation": {changeset: changes.Changeset{
+			Relations: []changes.RelationChange{
+				{Op: changes.OpDelete, RelationID: "r1"},
+				{Op: changes.OpDelete, RelationID: "r1"},
+			},
+		}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateChangeset(test.changeset)
+
+			if test.wantErr {
+				require.Error(t, err)
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateReviewers(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateReviewers(nil, "author"))
+	require.NoError(t, validateReviewers([]string{"r1", "r2"}, "author"))
+	require.Error(t, validateReviewers([]string{""}, "author"))
+	require.Error(t, validateReviewers([]string{"r1", "author"}, "author"))
+	require.Error(t, validateReviewers([]string{"r1", "r1"}, "author"))
+}
+
+func Test_validateComment(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateComment("looks good"))
+	require.Error(t, validateComment(""))
+	require.Error(t, validateComment(" \n\t"))
+}
+
+func Test_validateChangeRequestQuery(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateChangeRequestQuery(validChangeRequestQuery))
+	require.NoError(t, validateChangeRequestQuery(changes.ChangeRequestQuery{
+		ModelID:  validModelID,
+		Statuses: changes.AllStatuses,
+	}))
+	require.Error(t, validateChangeRequestQuery(changes.ChangeRequestQuery{}))
+	require.Error(t, validateChangeRequestQuery(changes.ChangeRequestQuery{
+		ModelID:  validModelID,
+		Statuses: []changes.Status{100},
+	}))
+}
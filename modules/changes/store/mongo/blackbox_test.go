@@ -0,0 +1,72 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/changes/store/mongo"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testChangeRequest returns an open change request updating a node of the model.
+func testChangeRequest(id, modelID string, minute int) changes.ChangeRequest {
+	return changes.ChangeRequest{
+		ID:       id,
+		ModelID:  modelID,
+		Title:    "change " + id,
+		AuthorID: "user1",
+		Status:   changes.StatusOpen,
+		Changeset: changes.Changeset{
+			Nodes: []changes.NodeChange{
+				{
+					Op:     changes.OpUpdate,
+					NodeID: "node1",
+					Data:   models.NodeData{Kind: "bus", Props: models.PropBag{"grid": {"voltage": 20.0}}},
+				},
+			},
+		},
+		Base: models.Mesh{
+			ModelID: modelID,
+			Nodes: map[string]models.Node{
+				"node1": {ID: "node1", Kind: "bus", Props: models.PropBag{"grid": {"voltage": 10.0}}},
+			},
+			Relations: map[string]models.Relation{},
+		},
+		CreatedAt: testTime.Add(time.Duration(minute) * time.Minute),
+		UpdatedAt: testTime.Add(time.Duration(minute) * time.Minute),
+	}
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.ChangeRequestStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewChangeRequestStore(db)
+
+	f(t, ctx, store)
+}
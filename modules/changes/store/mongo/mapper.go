@@ -0,0 +1,220 @@
+package mongo
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	meshstore "github.com/energimind/powermesh-core/modules/models/store/mongo"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+func toStoreChangeRequest(cr changes.ChangeRequest) storeChangeRequest {
+	return storeChangeRequest{
+		ID:          cr.ID,
+		ModelID:     cr.ModelID,
+		Title:       cr.Title,
+		Description: cr.Description,
+		AuthorID:    cr.AuthorID,
+		Reviewers:   cr.Reviewers,
+		Status:      cr.Status,
+		Changeset:   toStoreChangeset(cr.Changeset),
+		Base:        toStoreBase(cr.Base),
+		Comments:    mapSlice(cr.Comments, toStoreComment),
+		CreatedIDs:  cr.CreatedIDs,
+		CreatedAt:   cr.CreatedAt,
+		UpdatedAt:   cr.UpdatedAt,
+		ReviewedBy:  cr.ReviewedBy,
+		ReviewedAt:  cr.ReviewedAt,
+		AppliedBy:   cr.AppliedBy,
+		AppliedAt:   cr.AppliedAt,
+	}
+}
+
+func fromStoreChangeRequest(cr storeChangeRequest) changes.ChangeRequest {
+	return changes.ChangeRequest{
+		ID:          cr.ID,
+		ModelID:     cr.ModelID,
+		Title:       cr.Title,
+		Description: cr.Description,
+		AuthorID:    cr.AuthorID,
+		Reviewers:   cr.Reviewers,
+		Status:      cr.Status,
+		Changeset:   fromStoreChangeset(cr.Changeset),
+		Base:        fromStoreBase(cr.ModelID, cr.Base),
+		Comments:    mapSlice(cr.Comments, fromStoreComment),
+		CreatedIDs:  cr.CreatedIDs,
+		CreatedAt:   cr.CreatedAt,
+		UpdatedAt:   cr.UpdatedAt,
+		ReviewedBy:  cr.ReviewedBy,
+		ReviewedAt:  cr.ReviewedAt,
+		AppliedBy:   cr.AppliedBy,
+		AppliedAt:   cr.AppliedAt,
+	}
+}
+
+func toStoreChangeset(c changes.Changeset) storeChangeset {
+	return storeChangeset{
+		Nodes:     mapSlice(c.Nodes, toStoreNodeChange),
+		Relations: mapSlice(c.Relations, toStoreRelationChange),
+	}
+}
+
+func fromStoreChangeset(c storeChangeset) changes.Changeset {
+	return changes.Changeset{
+		Nodes:     mapSlice(c.Nodes, fromStoreNodeChange),
+		Relations: mapSlice(c.Relations, fromStoreRelationChange),
+	}
+}
+
+func toStoreNodeChange(c changes.NodeChange) storeNodeChange {
+	return storeNodeChange{
+		Op:     c.Op,
+		NodeID: c.NodeID,
+		Kind:   c.Data.Kind,
+		Code:   c.Data.Code,
+		Name:   c.Data.Name,
+		Props:  meshstore.ToStoreProps(c.Data.Props),
+	}
+}
+
+func fromStoreNodeChange(c storeNodeChange) changes.NodeChange {
+	return changes.NodeChange{
+		Op:     c.Op,
+		NodeID: c.NodeID,
+		Data: models.NodeData{
+			Kind:  c.Kind,
+			Code:  c.Code,
+			Name:  c.Name,
+			Props: meshstore.FromStoreProps(c.Props),
+		},
+	}
+}
+
+func toStoreRelationChange(c changes.RelationChange) storeRelationChange {
+	return storeRelationChange{
+		Op:         c.Op,
+		RelationID: c.RelationID,
+		Kind:       c.Data.Kind,
+		From:       c.Data.From,
+		To:         c.Data.To,
+		Props:      meshstore.ToStoreProps(c.Data.Props),
+	}
+}
+
+func fromStoreRelationChange(c storeRelationChange) changes.RelationChange {
+	return changes.RelationChange{
+		Op:         c.Op,
+		RelationID: c.RelationID,
+		Data: models.RelationData{
+			Kind:  c.Kind,
+			From:  c.From,
+			To:    c.To,
+			Props: meshstore.FromStoreProps(c.Props),
+		},
+	}
+}
+
+func toStoreBase(m models.Mesh) storeBase {
+	base := storeBase{
+		Nodes:     make([]storeNode, 0, len(m.Nodes)),
+		Relations: make([]storeRelation, 0, len(m.Relations)),
+	}
+
+	for _, n := range m.Nodes {
+		base.Nodes = append(base.Nodes, storeNode{
+			ID:    n.ID,
+			Kind:  n.Kind,
+			Code:  n.Code,
+			Props: meshstore.ToStoreProps(n.Props),
+		})
+	}
+
+	for _, r := range m.Relations {
+		base.Relations = append(base.Relations, storeRelation{
+			ID:    r.ID,
+			Kind:  r.Kind,
+			From:  r.From,
+			To:    r.To,
+			Props: meshstore.ToStoreProps(r.Props),
+		})
+	}
+
+	slices.SortFunc(base.Nodes, func(a, b storeNode) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(base.Relations, func(a, b storeRelation) int { return cmp.Compare(a.ID, b.ID) })
+
+	return base
+}
+
+func fromStoreBase(modelID string, base storeBase) models.Mesh {
+	m := models.Mesh{
+		ModelID:   modelID,
+		Nodes:     make(map[string]models.Node, len(base.Nodes)),
+		Relations: make(map[string]models.Relation, len(base.Relations)),
+	}
+
+	for _, n := range base.Nodes {
+		m.Nodes[n.ID] = models.Node{
+			ID:    n.ID,
+			Kind:  n.Kind,
+			Code:  n.Code,
+			Props: meshstore.FromStoreProps(n.Props),
+		}
+	}
+
+	for _, r := range base.Relations {
+		m.Relations[r.ID] = models.Relation{
+			ID:    r.ID,
+			Kind:  r.Kind,
+			From:  r.From,
+			To:    r.To,
+			Props: meshstore.FromStoreProps(r.Props),
+		}
+	}
+
+	return m
+}
+
+func toStoreComment(c changes.Comment) storeComment {
+	return storeComment{
+		AuthorID:  c.AuthorID,
+		Text:      c.Text,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func fromStoreComment(c storeComment) changes.Comment {
+	return changes.Comment{
+		AuthorID:  c.AuthorID,
+		Text:      c.Text,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// mapSlice maps the elements of the slice. A nil slice is mapped to nil, so the
+// optional lists survive a round trip through the store unchanged.
+func mapSlice[S, T any](s []S, f func(S) T) []T {
+	if s == nil {
+		return nil
+	}
+
+	mapped := make([]T, len(s))
+
+	for i, v := range s {
+		mapped[i] = f(v)
+	}
+
+	return mapped
+}
+
+// changeRequestsFilter builds the filter of the change requests matching the query.
+func changeRequestsFilter(query changes.ChangeRequestQuery) q.Filter {
+	filter := q.Filter{}.EQ(fieldModelID, query.ModelID)
+
+	if len(query.Statuses) > 0 {
+		filter = filter.IN(fieldStatus, query.Statuses)
+	}
+
+	return filter
+}
@@ -0,0 +1,75 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreChangeRequest, toStoreChangeRequest(validChangeRequest))
+	require.Equal(t, validChangeRequest, fromStoreChangeRequest(validStoreChangeRequest))
+}
+
+func Test_mapper_optionalLists(t *testing.T) {
+	t.Parallel()
+
+	cr := validChangeRequest
+	cr.Reviewers = nil
+	cr.Comments = nil
+	cr.CreatedIDs = nil
+	cr.Base = models.Mesh{ModelID: cr.ModelID, Nodes: map[string]models.Node{}, Relations: map[string]models.Relation{}}
+
+	require.Equal(t, cr, fromStoreChangeRequest(toStoreChangeRequest(cr)))
+}
+
+func Test_mapper_quantities(t *testing.T) {
+	t.Parallel()
+
+	props := models.PropBag{"rating": {"voltage": models.Quantity{Value: 20, Unit: models.UnitKilovolt}}}
+
+	cr := validChangeRequest
+	cr.Changeset = changes.Changeset{
+		Nodes: []changes.NodeChange{{Op: changes.OpUpdate, NodeID: "node1", Data: models.NodeData{Props: props}}},
+	}
+	cr.Base = models.Mesh{
+		ModelID:   cr.ModelID,
+		Nodes:     map[string]models.Node{"node1": {ID: "node1", Props: props}},
+		Relations: map[string]models.Relation{},
+	}
+
+	data, err := bson.Marshal(toStoreChangeRequest(cr))
+
+	require.NoError(t, err)
+
+	var stored storeChangeRequest
+
+	require.NoError(t, bson.Unmarshal(data, &stored))
+
+	restored := fromStoreChangeRequest(stored)
+
+	require.Equal(t, props, restored.Changeset.Nodes[0].Data.Props)
+	require.Equal(t, props, restored.Base.Nodes["node1"].Props)
+}
+
+func Test_changeRequestsFilter(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		q.Filter{}.EQ(fieldModelID, "model1"),
+		changeRequestsFilter(changes.ChangeRequestQuery{ModelID: "model1"}),
+	)
+
+	statuses := []changes.Status{changes.StatusOpen, changes.StatusApproved}
+
+	require.Equal(t,
+		q.Filter{}.EQ(fieldModelID, "model1").IN(fieldStatus, statuses),
+		changeRequestsFilter(changes.ChangeRequestQuery{ModelID: "model1", Statuses: statuses}),
+	)
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// storeChangeRequest represents a change request in the MongoDB store.
+type storeChangeRequest struct {
+	ID          string            `bson:"id"`
+	ModelID     string            `bson:"modelId"`
+	Title       string            `bson:"title"`
+	Description string            `bson:"description"`
+	AuthorID    string            `bson:"authorId"`
+	Reviewers   []string          `bson:"reviewers"`
+	Status      changes.Status    `bson:"status"`
+	Changeset   storeChangeset    `bson:"changeset"`
+	Base        storeBase         `bson:"base"`
+	Comments    []storeComment    `bson:"comments"`
+	CreatedIDs  map[string]string `bson:"createdIds"`
+	CreatedAt   time.Time         `bson:"createdAt"`
+	UpdatedAt   time.Time         `bson:"updatedAt"`
+	ReviewedBy  string            `bson:"reviewedBy"`
+	ReviewedAt  time.Time         `bson:"reviewedAt"`
+	AppliedBy   string            `bson:"appliedBy"`
+	AppliedAt   time.Time         `bson:"appliedAt"`
+}
+
+// storeChangeset represents the changeset of a change request in the MongoDB store.
+type storeChangeset struct {
+	Nodes     []storeNodeChange     `bson:"nodes"`
+	Relations []storeRelationChange `bson:"relations"`
+}
+
+// storeNodeChange represents a node change in the MongoDB store.
+type storeNodeChange struct {
+	Op     changes.Op     `bson:"op"`
+	NodeID string         `bson:"nodeId"`
+	Kind   string         `bson:"kind"`
+	Code   string         `bson:"code"`
+	Name   string         `bson:"name"`
+	Props  models.PropBag `bson:"props"`
+}
+
+// storeRelationChange represents a relation change in the MongoDB store.
+type storeRelationChange struct {
+	Op         changes.Op     `bson:"op"`
+	RelationID string         `bson:"relationId"`
+	Kind       string         `bson:"kind"`
+	From       string         `bson:"from"`
+	To         string         `bson:"to"`
+	Props      models.PropBag `bson:"props"`
+}
+
+// storeBase represents the base snapshot of a change request in the MongoDB store.
+// The elements are kept as lists ordered by ID.
+type storeBase struct {
+	Nodes     []storeNode     `bson:"nodes"`
+	Relations []storeRelation `bson:"relations"`
+}
+
+// storeNode represents a node of the base snapshot in the MongoDB store.
+type storeNode struct {
+	ID    string         `bson:"id"`
+	Kind  string         `bson:"kind"`
+	Code  string         `bson:"code"`
+	Props models.PropBag `bson:"props"`
+}
+
+// storeRelation represents a relation of the base snapshot in the MongoDB store.
+type storeRelation struct {
+	ID    string         `bson:"id"`
+	Kind  string         `bson:"kind"`
+	From  string         `bson:"from"`
+	To    string         `bson:"to"`
+	Props models.PropBag `bson:"props"`
+}
+
+// storeComment represents a review comment in the MongoDB store.
+type storeComment struct {
+	AuthorID  string    `bson:"authorId"`
+	Text      string    `bson:"text"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var (
+	validChangeRequest = changes.ChangeRequest{
+		ID:          "1",
+		ModelID:     "model1",
+		Title:       "raise voltage",
+		Description: "the bus runs at 20 kV now",
+		AuthorID:    "user1",
+		Reviewers:   []string{"user2"},
+		Status:      changes.StatusApplied,
+		Changeset: changes.Changeset{
+			Nodes: []changes.NodeChange{
+				{
+					Op:     changes.OpUpdate,
+					NodeID: "node1",
+					Data:   models.NodeData{Kind: "bus", Code: "B1", Props: models.PropBag{"grid": {"voltage": 20}}},
+				},
+				{
+					Op:     changes.OpCreate,
+					NodeID: "new-node",
+					Data:   models.NodeData{Kind: "bus", Name: "new bus"},
+				},
+			},
+			Relations: []changes.RelationChange{
+				{
+					Op:         changes.OpCreate,
+					RelationID: "new-relation",
+					Data:       models.RelationData{Kind: "line", From: "node1", To: "new-node"},
+				},
+				{
+					Op:         changes.OpDelete,
+					RelationID: "relation1",
+				},
+			},
+		},
+		Base: models.Mesh{
+			ModelID: "model1",
+			Nodes: map[string]models.Node{
+				"node1": {ID: "node1", Kind: "bus", Code: "B1", Props: models.PropBag{"grid": {"voltage": 10}}},
+			},
+			Relations: map[string]models.Relation{
+				"relation1": {ID: "relation1", Kind: "line", From: "node1", To: "node2"},
+			},
+		},
+		Comments: []changes.Comment{
+			{AuthorID: "user2", Text: "looks good", CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		CreatedIDs: map[string]string{"new-node": "node3", "new-relation": "relation2"},
+		CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		ReviewedBy: "user2",
+		ReviewedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		AppliedBy:  "user2",
+		AppliedAt:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	validStoreChangeRequest = storeChangeRequest{
+		ID:          validChangeRequest.ID,
+		ModelID:     validChangeRequest.ModelID,
+		Title:       validChangeRequest.Title,
+		Description: validChangeRequest.Description,
+		AuthorID:    validChangeRequest.AuthorID,
+		Reviewers:   validChangeRequest.Reviewers,
+		Status:      validChangeRequest.Status,
+		Changeset: storeChangeset{
+			Nodes: []storeNodeChange{
+				{
+					Op:     changes.OpUpdate,
+					NodeID: "node1",
+					Kind:   "bus",
+					Code:   "B1",
+					Props:  models.PropBag{"grid": {"voltage": 20}},
+				},
+				{
+					Op:     changes.OpCreate,
+					NodeID: "new-node",
+					Kind:   "bus",
+					Name:   "new bus",
+				},
+			},
+			Relations: []storeRelationChange{
+				{
+					Op:         changes.OpCreate,
+					RelationID: "new-relation",
+					Kind:       "line",
+					From:       "node1",
+					To:         "new-node",
+				},
+				{
+					Op:         changes.OpDelete,
+					RelationID: "relation1",
+				},
+			},
+		},
+		Base: storeBase{
+			Nodes: []storeNode{
+				{ID: "node1", Kind: "bus", Code: "B1", Props: models.PropBag{"grid": {"voltage": 10}}},
+			},
+			Relations: []storeRelation{
+				{ID: "relation1", Kind: "line", From: "node1", To: "node2"},
+			},
+		},
+		Comments: []storeComment{
+			{AuthorID: "user2", Text: "looks good", CreatedAt: validChangeRequest.Comments[0].CreatedAt},
+		},
+		CreatedIDs: validChangeRequest.CreatedIDs,
+		CreatedAt:  validChangeRequest.CreatedAt,
+		UpdatedAt:  validChangeRequest.UpdatedAt,
+		ReviewedBy: validChangeRequest.ReviewedBy,
+		ReviewedAt: validChangeRequest.ReviewedAt,
+		AppliedBy:  validChangeRequest.AppliedBy,
+		AppliedAt:  validChangeRequest.AppliedAt,
+	}
+)
@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/modules/changes"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	collChangeRequests = "changeRequests"
+	fieldID            = "id"
+	fieldModelID       = "modelId"
+	fieldStatus        = "status"
+	fieldCreatedAt     = "createdAt"
+)
+
+// ChangeRequestStore is a MongoDB implementation of the change request store.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type ChangeRequestStore struct {
+	requests *mongo.Collection
+}
+
+// NewChangeRequestStore creates a new MongoDB change request store.
+func NewChangeRequestStore(db *mongo.Database) *ChangeRequestStore {
+	return &ChangeRequestStore{
+		requests: db.Collection(collChangeRequests),
+	}
+}
+
+// CreateChangeRequest implements the change request store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeRequestStore) CreateChangeRequest(ctx context.Context, cr changes.ChangeRequest) error {
+	return q.CreateOne(s.requests, toStoreChangeRequest).Exec(ctx, cr)
+}
+
+// UpdateChangeRequest implements the change request store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeRequestStore) UpdateChangeRequest(ctx context.Context, cr changes.ChangeRequest) error {
+	return q.UpdateOne(s.requests, toStoreChangeRequest).Exec(ctx, cr.ID, cr)
+}
+
+// DeleteModelChangeRequests implements the change request store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeRequestStore) DeleteModelChangeRequests(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.requests).Exec(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+
+	return err
+}
+
+// GetChangeRequest implements the change request store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeRequestStore) GetChangeRequest(ctx context.Context, id string) (changes.ChangeRequest, error) {
+	return q.GetOne(s.requests, fromStoreChangeRequest).Exec(ctx, id)
+}
+
+// GetChangeRequests implements the change request store interface.
+// The change requests are returned in the order they were opened.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ChangeRequestStore) GetChangeRequests(
+	ctx context.Context,
+	query changes.ChangeRequestQuery,
+) ([]changes.ChangeRequest, error) {
+	return q.FindMany(s.requests, fromStoreChangeRequest).
+		WithSort(fieldCreatedAt, false).
+		WithSort(fieldID, false).
+		Exec(ctx, changeRequestsFilter(query))
+}
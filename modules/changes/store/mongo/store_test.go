@@ -0,0 +1,159 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/changes"
+	"github.com/energimind/powermesh-core/modules/changes/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func createChangeRequests(
+	t *testing.T,
+	ctx context.Context,
+	store *mongo.ChangeRequestStore,
+	crs ...changes.ChangeRequest,
+) {
+	t.Helper()
+
+	for _, cr := range crs {
+		require.NoError(t, store.CreateChangeRequest(ctx, cr))
+	}
+}
+
+func changeRequestIDs(found []changes.ChangeRequest) []string {
+	ids := make([]string, 0, len(found))
+
+	for _, cr := range found {
+		ids = append(ids, cr.ID)
+	}
+
+	return ids
+}
+
+func TestChangeRequestStore_CreateChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.ChangeRequestStore) {
+		cr := testChangeRequest("1", "model1", 0)
+
+		require.NoError(t, store.CreateChangeRequest(ctx, cr))
+
+		created, err := store.GetChangeRequest(ctx, cr.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, cr, created)
+	})
+}
+
+func TestChangeRequestStore_UpdateChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.ChangeRequestStore) {
+		t.Run("not-found", func(t *testing.T) {
+			err := store.UpdateChangeRequest(ctx, testChangeRequest("1", "model1", 0))
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+
+		t.Run("success", func(t *testing.T) {
+			cr := testChangeRequest("1", "model1", 0)
+
+			require.NoError(t, store.CreateChangeRequest(ctx, cr))
+
+			cr.Status = changes.StatusApplied
+			cr.Reviewers = []string{"user2"}
+			cr.Comments = []changes.Comment{{AuthorID: "user2", Text: "looks good", CreatedAt: testTime}}
+			cr.CreatedIDs = map[string]string{"new-node": "node2"}
+			cr.ReviewedBy = "user2"
+			cr.ReviewedAt = testTime.Add(time.Hour)
+			cr.AppliedBy = "user2"
+			cr.AppliedAt = testTime.Add(2 * time.Hour)
+
+			require.NoError(t, store.UpdateChangeRequest(ctx, cr))
+
+			updated, err := store.GetChangeRequest(ctx, cr.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, cr, updated)
+		})
+	})
+}
+
+func TestChangeRequestStore_DeleteModelChangeRequests(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.ChangeRequestStore) {
+		createChangeRequests(t, ctx, store,
+			testChangeRequest("1", "model1", 0),
+			testChangeRequest("2", "model1", 1),
+			testChangeRequest("3", "model2", 2),
+		)
+
+		require.NoError(t, store.DeleteModelChangeRequests(ctx, "model1"))
+
+		_, err := store.GetChangeRequest(ctx, "1")
+
+		require.IsType(t, errorz.NotFoundError{}, err)
+
+		_, err = store.GetChangeRequest(ctx, "3")
+
+		require.NoError(t, err)
+	})
+}
+
+func TestChangeRequestStore_GetChangeRequest(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.ChangeRequestStore) {
+		_, err := store.GetChangeRequest(ctx, "missing")
+
+		require.IsType(t, errorz.NotFoundError{}, err)
+	})
+}
+
+func TestChangeRequestStore_GetChangeRequests(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.ChangeRequestStore) {
+		approved := testChangeRequest("2", "model1", 1)
+		approved.Status = changes.StatusApproved
+
+		createChangeRequests(t, ctx, store,
+			testChangeRequest("3", "model1", 2),
+			approved,
+			testChangeRequest("1", "model1", 0),
+			testChangeRequest("4", "model2", 3),
+		)
+
+		tests := map[string]struct {
+			query   changes.ChangeRequestQuery
+			wantIDs []string
+		}{
+			"model": {
+				query:   changes.ChangeRequestQuery{ModelID: "model1"},
+				wantIDs: []string{"1", "2", "3"},
+			},
+			"statuses": {
+				query:   changes.ChangeRequestQuery{ModelID: "model1", Statuses: []changes.Status{changes.StatusApproved}},
+				wantIDs: []string{"2"},
+			},
+			"other-model": {
+				query:   changes.ChangeRequestQuery{ModelID: "model3"},
+				wantIDs: []string{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				found, err := store.GetChangeRequests(ctx, test.query)
+
+				require.NoError(t, err)
+				require.Equal(t, test.wantIDs, changeRequestIDs(found))
+			})
+		}
+	})
+}
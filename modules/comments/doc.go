@@ -0,0 +1,3 @@
+// Package comments provides a model and service for managing review comments on the
+// elements of a mesh.
+package comments
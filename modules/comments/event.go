@@ -0,0 +1,51 @@
+package comments
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// EventType is the type of event that occurred.
+type EventType string
+
+// Event types.
+const (
+	CommentCreated  EventType = "comment.created"
+	CommentUpdated  EventType = "comment.updated"
+	CommentDeleted  EventType = "comment.deleted"
+	CommentResolved EventType = "comment.resolved"
+	CommentReopened EventType = "comment.reopened"
+)
+
+// Event models an event that occurs in the comment service.
+type Event interface {
+	IsCommentEvent() bool
+}
+
+// EventHeader models the header of an event.
+type EventHeader struct {
+	Type      EventType
+	Actor     access.Actor
+	Timestamp time.Time
+}
+
+// CommentEvent models an event that occurs in the comment service related to a comment.
+type CommentEvent struct {
+	EventHeader
+	Comment Comment
+}
+
+// IsCommentEvent implements the Event interface.
+func (CommentEvent) IsCommentEvent() bool {
+	return true
+}
+
+// ExtractCommentEvent extracts a comment event from an event.
+func ExtractCommentEvent(e Event) (CommentEvent, bool) {
+	if ce, ok := e.(CommentEvent); ok {
+		return ce, true
+	}
+
+	return CommentEvent{}, false
+}
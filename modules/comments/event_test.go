@@ -0,0 +1,26 @@
+package comments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractCommentEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		ce, ok := ExtractCommentEvent(CommentEvent{EventHeader: EventHeader{Type: CommentCreated}})
+
+		require.True(t, ok)
+		require.NotZero(t, ce)
+		require.True(t, ce.IsCommentEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ce, ok := ExtractCommentEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, ce)
+	})
+}
@@ -0,0 +1,102 @@
+package comments
+
+import (
+	"strconv"
+	"time"
+)
+
+// Comment represents a comment on a mesh element.
+//
+// Comments are organized in threads: the first comment on an anchor starts a thread and
+// all replies refer to it by ThreadID, which is empty for the thread itself. The status of
+// a thread is kept on its first comment; replies are always open.
+//
+// A comment is orphaned when the element it is anchored to has been deleted. Orphaned
+// comments are kept, so the discussion is not lost, but they cannot be anchored again.
+type Comment struct {
+	ID         string
+	ModelID    string
+	ThreadID   string
+	Anchor     Anchor
+	AuthorID   string
+	Text       string
+	Status     Status
+	Orphaned   bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ResolvedBy string
+	ResolvedAt time.Time
+}
+
+// IsThread checks if the comment starts a thread.
+func (c Comment) IsThread() bool {
+	return c.ThreadID == ""
+}
+
+// Anchor represents the place in a mesh a comment refers to: a node or relation and,
+// optionally, a property of it in "section.key" notation.
+type Anchor struct {
+	ElementType ElementType
+	ElementID   string
+	PropPath    string
+}
+
+// ElementType represents the type of mesh element a comment is anchored to.
+type ElementType int
+
+// ElementType enumeration.
+const (
+	ElementTypeNode ElementType = iota
+	ElementTypeRelation
+)
+
+// AllElementTypes is a list of all element types. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllElementTypes = []ElementType{
+	ElementTypeNode,
+	ElementTypeRelation,
+}
+
+// String returns the string representation of the element type.
+func (t ElementType) String() string {
+	switch t {
+	case ElementTypeNode:
+		return "node"
+	case ElementTypeRelation:
+		return "relation"
+	}
+
+	return "ElementType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Status represents the status of a comment thread.
+type Status int
+
+// Status enumeration.
+const (
+	StatusOpen Status = iota
+	StatusResolved
+)
+
+// AllStatuses is a list of all statuses. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllStatuses = []Status{
+	StatusOpen,
+	StatusResolved,
+}
+
+// String returns the string representation of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusOpen:
+		return "open"
+	case StatusResolved:
+		return "resolved"
+	}
+
+	return "Status(" + strconv.Itoa(int(s)) + ")"
+}
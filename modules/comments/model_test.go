@@ -0,0 +1,49 @@
+package comments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComment_IsThread(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Comment{ID: "1"}.IsThread())
+	require.False(t, Comment{ID: "2", ThreadID: "1"}.IsThread())
+}
+
+func TestElementType_String(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range AllElementTypes {
+		t.Run(e.String(), func(t *testing.T) {
+			require.NotEmpty(t, e.String())
+			require.False(t, strings.HasPrefix(e.String(), "ElementType("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		e := ElementType(100)
+
+		require.Equal(t, "ElementType(100)", e.String())
+	})
+}
+
+func TestStatus_String(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range AllStatuses {
+		t.Run(s.String(), func(t *testing.T) {
+			require.NotEmpty(t, s.String())
+			require.False(t, strings.HasPrefix(s.String(), "Status("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		s := Status(100)
+
+		require.Equal(t, "Status(100)", s.String())
+	})
+}
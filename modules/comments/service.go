@@ -0,0 +1,38 @@
+package comments
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// CommentService defines the comment service.
+type CommentService interface {
+	CreateComment(ctx context.Context, actor access.Actor, data CommentData) (Comment, error)
+	ReplyToComment(ctx context.Context, actor access.Actor, threadID, text string) (Comment, error)
+	UpdateComment(ctx context.Context, actor access.Actor, id, text string) (Comment, error)
+	DeleteComment(ctx context.Context, actor access.Actor, id string) error
+	ResolveComment(ctx context.Context, actor access.Actor, id string) (Comment, error)
+	ReopenComment(ctx context.Context, actor access.Actor, id string) (Comment, error)
+	GetComment(ctx context.Context, actor access.Actor, id string) (Comment, error)
+	GetThread(ctx context.Context, actor access.Actor, id string) ([]Comment, error)
+	ListThreads(ctx context.Context, actor access.Actor, query CommentQuery) ([]Comment, error)
+}
+
+// CommentData defines the comment data. It is used to start a comment thread.
+type CommentData struct {
+	ModelID string
+	Anchor  Anchor
+	Text    string
+}
+
+// CommentQuery defines the comment query. It is used to list the comment threads of a
+// model, optionally restricted to a node or a relation, to some statuses, or to orphaned
+// threads.
+type CommentQuery struct {
+	ModelID      string
+	NodeID       string
+	RelationID   string
+	Statuses     []Status
+	OrphanedOnly bool
+}
@@ -0,0 +1,2 @@
+// Package service implements the comment service.
+package service
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/comments"
+)
+
+func threadFromData(id string, actor access.Actor, data comments.CommentData, now time.Time) comments.Comment {
+	return comments.Comment{
+		ID:        id,
+		ModelID:   data.ModelID,
+		Anchor:    data.Anchor,
+		AuthorID:  actor.UserID,
+		Text:      data.Text,
+		Status:    comments.StatusOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// replyFromThread creates a reply to the thread. The reply shares the anchor of the thread.
+func replyFromThread(
+	id string,
+	actor access.Actor,
+	thread comments.Comment,
+	text string,
+	now time.Time,
+) comments.Comment {
+	return comments.Comment{
+		ID:        id,
+		ModelID:   thread.ModelID,
+		ThreadID:  thread.ID,
+		Anchor:    thread.Anchor,
+		AuthorID:  actor.UserID,
+		Text:      text,
+		Status:    comments.StatusOpen,
+		Orphaned:  thread.Orphaned,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_threadFromData(t *testing.T) {
+	require.Equal(t,
+		comments.Comment{
+			ID:        validCommentID,
+			ModelID:   validCommentData.ModelID,
+			Anchor:    validCommentData.Anchor,
+			AuthorID:  authorActor.UserID,
+			Text:      validCommentData.Text,
+			Status:    comments.StatusOpen,
+			CreatedAt: testTime,
+			UpdatedAt: testTime,
+		},
+		threadFromData(validCommentID, authorActor, validCommentData, testTime),
+	)
+}
+
+func Test_replyFromThread(t *testing.T) {
+	thread := testThread(comments.StatusResolved)[0]
+	thread.Orphaned = true
+
+	require.Equal(t,
+		comments.Comment{
+			ID:        "2",
+			ModelID:   thread.ModelID,
+			ThreadID:  thread.ID,
+			Anchor:    thread.Anchor,
+			AuthorID:  reviewerActor.UserID,
+			Text:      "agreed",
+			Status:    comments.StatusOpen,
+			Orphaned:  true,
+			CreatedAt: testTime,
+			UpdatedAt: testTime,
+		},
+		replyFromThread("2", reviewerActor, thread, "agreed", testTime),
+	)
+}
@@ -0,0 +1,550 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// idGenerator defines the external ID generator.
+type idGenerator interface {
+	GenerateID() string
+}
+
+// store defines the external comment store.
+type store interface {
+	CreateComment(ctx context.Context, comment comments.Comment) error
+	UpdateComment(ctx context.Context, comment comments.Comment) error
+	DeleteComment(ctx context.Context, id string) error
+	DeleteThread(ctx context.Context, id string) error
+	DeleteModelComments(ctx context.Context, modelID string) error
+	GetComment(ctx context.Context, id string) (comments.Comment, error)
+	GetThread(ctx context.Context, id string) ([]comments.Comment, error)
+	GetThreads(ctx context.Context, query comments.CommentQuery) ([]comments.Comment, error)
+	OrphanElementComments(ctx context.Context, modelID string, elementType comments.ElementType, ids []string) error
+	OrphanModelComments(ctx context.Context, modelID string) error
+}
+
+// listener defines the external comment event listener.
+type listener interface {
+	HandleCommentEvent(ctx context.Context, event comments.Event) error
+}
+
+// elementProvider defines the external provider of mesh elements.
+// It is implemented by the mesh service.
+type elementProvider interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	GetRelation(ctx context.Context, modelID, relationID string) (models.Relation, error)
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+	IsAdmin(actor access.Actor) bool
+}
+
+// CommentService implements the comment service.
+//
+// It implements the comments.CommentService interface.
+//
+// Comments are read by actors with the read permission on the model, and written, resolved
+// and reopened by actors with the comment permission. Comments can only be edited by their
+// authors; admins can delete any comment. Threads can be resolved and reopened by everyone
+// who can comment.
+//
+// The service listens to mesh events and flags the comments on deleted elements as
+// orphaned. The comments of a model are removed when the model is deleted, as the service
+// takes part in the cascading model deletion.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type CommentService struct {
+	idGen    idGenerator
+	store    store
+	elements elementProvider
+	authz    authorizer
+	listener listener
+	now      func() time.Time
+}
+
+// Ensure CommentService implements the comments.CommentService interface.
+var _ comments.CommentService = (*CommentService)(nil)
+
+// NewCommentService creates a new comment service.
+func NewCommentService(
+	store store,
+	idGen idGenerator,
+	elements elementProvider,
+	authz authorizer,
+	opts ...Option,
+) *CommentService {
+	svc := &CommentService{
+		idGen:    idGen,
+		store:    store,
+		elements: elements,
+		authz:    authz,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// CreateComment implements the comments.CommentService interface.
+// It starts a new thread on an existing element.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) CreateComment(
+	ctx context.Context,
+	actor access.Actor,
+	data comments.CommentData,
+) (comments.Comment, error) {
+	if err := validateCommentData(data); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, data.ModelID, access.PermissionComment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.ensureElementExists(ctx, data.ModelID, data.Anchor); err != nil {
+		return comments.Comment{}, err
+	}
+
+	comment := threadFromData(s.idGen.GenerateID(), actor, data, s.now())
+
+	if err := s.store.CreateComment(ctx, comment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.fireCommentEvent(ctx, actor, comments.CommentCreated, comment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// ReplyToComment implements the comments.CommentService interface.
+// A reply to a reply is added to the thread of the replied comment. Orphaned threads
+// can still be replied to.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) ReplyToComment(
+	ctx context.Context,
+	actor access.Actor,
+	threadID, text string,
+) (comments.Comment, error) {
+	if err := validateID(threadID); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := validateText(text); err != nil {
+		return comments.Comment{}, err
+	}
+
+	thread, err := s.getThreadOf(ctx, threadID)
+	if err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, thread.ModelID, access.PermissionComment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	reply := replyFromThread(s.idGen.GenerateID(), actor, thread, text, s.now())
+
+	if err := s.store.CreateComment(ctx, reply); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.fireCommentEvent(ctx, actor, comments.CommentCreated, reply); err != nil {
+		return comments.Comment{}, err
+	}
+
+	return reply, nil
+}
+
+// UpdateComment implements the comments.CommentService interface.
+// Only the author can change the text of a comment.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) UpdateComment(
+	ctx context.Context,
+	actor access.Actor,
+	id, text string,
+) (comments.Comment, error) {
+	if err := validateID(id); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := validateText(text); err != nil {
+		return comments.Comment{}, err
+	}
+
+	comment, err := s.store.GetComment(ctx, id)
+	if err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, comment.ModelID, access.PermissionComment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if comment.AuthorID != actor.UserID {
+		return comments.Comment{}, errorz.NewAccessDeniedError("user %s is not the author of comment %s",
+			actor.UserID, id)
+	}
+
+	comment.Text = text
+	comment.UpdatedAt = s.now()
+
+	if err := s.store.UpdateComment(ctx, comment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.fireCommentEvent(ctx, actor, comments.CommentUpdated, comment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// DeleteComment implements the comments.CommentService interface.
+// Deleting the first comment of a thread deletes the whole thread. Only the author or an
+// admin can delete a comment.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) DeleteComment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	comment, err := s.store.GetComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authorize(ctx, actor, comment.ModelID, access.PermissionComment); err != nil {
+		return err
+	}
+
+	if comment.AuthorID != actor.UserID && !s.authz.IsAdmin(actor) {
+		return errorz.NewAccessDeniedError("user %s is not the author of comment %s", actor.UserID, id)
+	}
+
+	if comment.IsThread() {
+		err = s.store.DeleteThread(ctx, id)
+	} else {
+		err = s.store.DeleteComment(ctx, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.fireCommentEvent(ctx, actor, comments.CommentDeleted, comment); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResolveComment implements the comments.CommentService interface.
+// It resolves the thread of the comment and returns the first comment of the thread.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) ResolveComment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (comments.Comment, error) {
+	return s.changeStatus(ctx, actor, id, comments.StatusResolved, comments.CommentResolved)
+}
+
+// ReopenComment implements the comments.CommentService interface.
+// It reopens the thread of the comment and returns the first comment of the thread.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) ReopenComment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (comments.Comment, error) {
+	return s.changeStatus(ctx, actor, id, comments.StatusOpen, comments.CommentReopened)
+}
+
+// GetComment implements the comments.CommentService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) GetComment(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (comments.Comment, error) {
+	if err := validateID(id); err != nil {
+		return comments.Comment{}, err
+	}
+
+	comment, err := s.store.GetComment(ctx, id)
+	if err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, comment.ModelID, access.PermissionRead); err != nil {
+		return comments.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// GetThread implements the comments.CommentService interface.
+// It returns the thread of the comment, starting with its first comment and followed by
+// the replies in the order they were written.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) GetThread(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) ([]comments.Comment, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+
+	thread, err := s.getThreadOf(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, actor, thread.ModelID, access.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	found, err := s.store.GetThread(ctx, thread.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// ListThreads implements the comments.CommentService interface.
+// It returns the first comments of the matching threads in the order they were written.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) ListThreads(
+	ctx context.Context,
+	actor access.Actor,
+	query comments.CommentQuery,
+) ([]comments.Comment, error) {
+	if err := validateCommentQuery(query); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, actor, query.ModelID, access.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	found, err := s.store.GetThreads(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// HandleMeshEvent flags the comments on the elements deleted by the event as orphaned.
+// If the whole mesh is deleted, all comments of the model are orphaned. The flagging is
+// not announced, because the consumers are informed by the mesh event itself.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) HandleMeshEvent(ctx context.Context, event models.MeshEvent) error {
+	//nolint:exhaustive // only deletions are of interest
+	switch event.Type {
+	case models.MeshDeleted:
+		return s.store.OrphanModelComments(ctx, event.Updates.ModelID)
+	case models.MeshContentsDeleted:
+		modelID := event.Deletes.ModelID
+
+		if len(event.Deletes.Nodes) > 0 {
+			err := s.store.OrphanElementComments(ctx, modelID, comments.ElementTypeNode, keys(event.Deletes.Nodes))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(event.Deletes.Relations) > 0 {
+			err := s.store.OrphanElementComments(ctx, modelID, comments.ElementTypeRelation,
+				keys(event.Deletes.Relations))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteModelResources removes all comments of the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelComments(ctx, modelID)
+}
+
+// changeStatus changes the status of the thread of the comment.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) changeStatus(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	status comments.Status,
+	eventType comments.EventType,
+) (comments.Comment, error) {
+	if err := validateID(id); err != nil {
+		return comments.Comment{}, err
+	}
+
+	thread, err := s.getThreadOf(ctx, id)
+	if err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.authorize(ctx, actor, thread.ModelID, access.PermissionComment); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if thread.Status == status {
+		return comments.Comment{}, errorz.NewStateError("comment thread %s is already %s", thread.ID, status)
+	}
+
+	thread.Status = status
+	thread.UpdatedAt = s.now()
+	thread.ResolvedBy = ""
+	thread.ResolvedAt = time.Time{}
+
+	if status == comments.StatusResolved {
+		thread.ResolvedBy = actor.UserID
+		thread.ResolvedAt = thread.UpdatedAt
+	}
+
+	if err := s.store.UpdateComment(ctx, thread); err != nil {
+		return comments.Comment{}, err
+	}
+
+	if err := s.fireCommentEvent(ctx, actor, eventType, thread); err != nil {
+		return comments.Comment{}, err
+	}
+
+	return thread, nil
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// getThreadOf returns the first comment of the thread the comment belongs to.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) getThreadOf(ctx context.Context, id string) (comments.Comment, error) {
+	comment, err := s.store.GetComment(ctx, id)
+	if err != nil {
+		return comments.Comment{}, err
+	}
+
+	if comment.IsThread() {
+		return comment, nil
+	}
+
+	return s.store.GetComment(ctx, comment.ThreadID)
+}
+
+// ensureElementExists checks that the anchor refers to an existing element.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentService) ensureElementExists(ctx context.Context, modelID string, anchor comments.Anchor) error {
+	var err error
+
+	switch anchor.ElementType {
+	case comments.ElementTypeNode:
+		_, err = s.elements.GetNode(ctx, modelID, anchor.ElementID)
+	case comments.ElementTypeRelation:
+		_, err = s.elements.GetRelation(ctx, modelID, anchor.ElementID)
+	}
+
+	return err
+}
+
+// fireCommentEvent fires a comment event.
+func (s *CommentService) fireCommentEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType comments.EventType,
+	comment comments.Comment,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := comments.CommentEvent{
+		EventHeader: comments.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Comment: comment,
+	}
+
+	if err := s.listener.HandleCommentEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
+
+// keys returns the keys of the map in sorted order.
+func keys[T any](m map[string]T) []string {
+	ids := make([]string, 0, len(m))
+
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
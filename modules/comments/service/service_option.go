@@ -0,0 +1,11 @@
+package service
+
+// Option defines the option for the service.
+type Option func(service *CommentService)
+
+// WithListener sets the listener for the service.
+func WithListener(listener listener) Option {
+	return func(s *CommentService) {
+		s.listener = listener
+	}
+}
@@ -0,0 +1,776 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentService_CreateComment(t *testing.T) {
+	t.Parallel()
+
+	withData := func(f func(*comments.CommentData)) comments.CommentData {
+		data := validCommentData
+		f(&data)
+
+		return data
+	}
+
+	tests := map[string]struct {
+		data          comments.CommentData
+		storeError    bool
+		listenerError bool
+		wantEvent     comments.EventType
+		wantErr       error
+	}{
+		"invalid-data": {
+			data:    comments.CommentData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"element-not-found": {
+			data: withData(func(d *comments.CommentData) {
+				d.Anchor = comments.Anchor{ElementType: comments.ElementTypeNode, ElementID: validRelationID}
+			}),
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			data:       validCommentData,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			data:          validCommentData,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-relation": {
+			data:      validCommentData,
+			wantEvent: comments.CommentCreated,
+		},
+		"success-node": {
+			data: withData(func(d *comments.CommentData) {
+				d.Anchor = comments.Anchor{ElementType: comments.ElementTypeNode, ElementID: validNodeID}
+			}),
+			wantEvent: comments.CommentCreated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError)
+			tl := newTestListener(test.listenerError)
+
+			c, err := newTestService(ts, tl).CreateComment(context.Background(), authorActor, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, c)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validCommentID, c.ID)
+				require.True(t, c.IsThread())
+				require.Equal(t, test.data.Anchor, c.Anchor)
+				require.Equal(t, authorActor.UserID, c.AuthorID)
+				require.Equal(t, comments.StatusOpen, c.Status)
+				require.Equal(t, c, ts.comments[c.ID])
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_ReplyToComment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id            string
+		text          string
+		orphaned      bool
+		storeError    bool
+		listenerError bool
+		wantEvent     comments.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			id:      "",
+			text:    "agreed",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-text": {
+			id:      "t",
+			text:    " ",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			text:    "agreed",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			id:         "t",
+			text:       "agreed",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			id:            "t",
+			text:          "agreed",
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-thread": {
+			id:        "t",
+			text:      "agreed",
+			wantEvent: comments.CommentCreated,
+		},
+		"success-reply": {
+			id:        "r",
+			text:      "agreed",
+			wantEvent: comments.CommentCreated,
+		},
+		"success-orphaned": {
+			id:        "t",
+			text:      "agreed",
+			orphaned:  true,
+			wantEvent: comments.CommentCreated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			thread := testThread(comments.StatusOpen)
+			thread[0].Orphaned = test.orphaned
+
+			ts := newTestStore(test.storeError, thread...)
+			tl := newTestListener(test.listenerError)
+
+			c, err := newTestService(ts, tl).ReplyToComment(context.Background(), reviewerActor, test.id, test.text)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, c)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "t", c.ThreadID)
+				require.Equal(t, validAnchor, c.Anchor)
+				require.Equal(t, test.orphaned, c.Orphaned)
+				require.Equal(t, reviewerActor.UserID, c.AuthorID)
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_UpdateComment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		text          string
+		storeError    bool
+		listenerError bool
+		wantEvent     comments.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   authorActor,
+			id:      "",
+			text:    "fixed",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-text": {
+			actor:   authorActor,
+			id:      "t",
+			text:    "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   authorActor,
+			id:      "missing",
+			text:    "fixed",
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-author": {
+			actor:   adminActor,
+			id:      "t",
+			text:    "fixed",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      authorActor,
+			id:         "t",
+			text:       "fixed",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         authorActor,
+			id:            "t",
+			text:          "fixed",
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor:     authorActor,
+			id:        "t",
+			text:      "fixed",
+			wantEvent: comments.CommentUpdated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testThread(comments.StatusOpen)...)
+			tl := newTestListener(test.listenerError)
+
+			c, err := newTestService(ts, tl).UpdateComment(context.Background(), test.actor, test.id, test.text)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, c)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.text, c.Text)
+				require.Equal(t, testTime, c.CreatedAt)
+				require.True(t, c.UpdatedAt.After(c.CreatedAt))
+				require.Equal(t, c, ts.comments[test.id])
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		storeError    bool
+		listenerError bool
+		wantEvent     comments.EventType
+		wantIDs       []string
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   authorActor,
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   authorActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-author": {
+			actor:   reviewerActor,
+			id:      "t",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      authorActor,
+			id:         "t",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         authorActor,
+			id:            "t",
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-thread": {
+			actor:     authorActor,
+			id:        "t",
+			wantEvent: comments.CommentDeleted,
+			wantIDs:   []string{},
+		},
+		"success-reply": {
+			actor:     reviewerActor,
+			id:        "r",
+			wantEvent: comments.CommentDeleted,
+			wantIDs:   []string{"t"},
+		},
+		"success-admin": {
+			actor:     adminActor,
+			id:        "r",
+			wantEvent: comments.CommentDeleted,
+			wantIDs:   []string{"t"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testThread(comments.StatusOpen)...)
+			tl := newTestListener(test.listenerError)
+
+			err := newTestService(ts, tl).DeleteComment(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+
+				ids := []string{}
+
+				for id := range ts.comments {
+					ids = append(ids, id)
+				}
+
+				require.Equal(t, test.wantIDs, ids)
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_ResolveComment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id            string
+		status        comments.Status
+		storeError    bool
+		listenerError bool
+		wantEvent     comments.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"already-resolved": {
+			id:      "t",
+			status:  comments.StatusResolved,
+			wantErr: errorz.StateError{},
+		},
+		"store-error": {
+			id:         "t",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			id:            "t",
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-thread": {
+			id:        "t",
+			wantEvent: comments.CommentResolved,
+		},
+		"success-reply": {
+			id:        "r",
+			wantEvent: comments.CommentResolved,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testThread(test.status)...)
+			tl := newTestListener(test.listenerError)
+
+			c, err := newTestService(ts, tl).ResolveComment(context.Background(), reviewerActor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, c)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "t", c.ID)
+				require.Equal(t, comments.StatusResolved, c.Status)
+				require.Equal(t, reviewerActor.UserID, c.ResolvedBy)
+				require.Equal(t, c.UpdatedAt, c.ResolvedAt)
+				require.Equal(t, c, ts.comments["t"])
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_ReopenComment(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id        string
+		status    comments.Status
+		wantEvent comments.EventType
+		wantErr   error
+	}{
+		"invalid-id": {
+			id:      "",
+			status:  comments.StatusResolved,
+			wantErr: errorz.ValidationError{},
+		},
+		"already-open": {
+			id:      "t",
+			status:  comments.StatusOpen,
+			wantErr: errorz.StateError{},
+		},
+		"success": {
+			id:        "r",
+			status:    comments.StatusResolved,
+			wantEvent: comments.CommentReopened,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testThread(test.status)...)
+			tl := newTestListener(false)
+
+			c, err := newTestService(ts, tl).ReopenComment(context.Background(), authorActor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, c)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "t", c.ID)
+				require.Equal(t, comments.StatusOpen, c.Status)
+				require.Empty(t, c.ResolvedBy)
+				require.Zero(t, c.ResolvedAt)
+			}
+
+			if test.wantEvent != "" {
+				requireEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestCommentService_access(t *testing.T) {
+	t.Parallel()
+
+	calls := map[string]func(ctx context.Context, svc *CommentService, actor access.Actor) error{
+		"create": func(ctx context.Context, svc *CommentService, actor access.Actor) error {
+			_, err := svc.CreateComment(ctx, actor, validCommentData)
+
+			return err
+		},
+		"reply": func(ctx context.Context, svc *CommentService, actor access.Actor) error {
+			_, err := svc.ReplyToComment(ctx, actor, "r", "me too")
+
+			return err
+		},
+		"update": func(ctx context.Context, svc *CommentService, actor access.Actor) error {
+			_, err := svc.UpdateComment(ctx, actor, "t", "changed")
+
+			return err
+		},
+		"delete": func(ctx context.Context, svc *CommentService, actor access.Actor) error {
+			return svc.DeleteComment(ctx, actor, "r")
+		},
+		"resolve": func(ctx context.Context, svc *CommentService, actor access.Actor) error {
+			_, err := svc.ResolveComment(ctx, actor, "t")
+
+			return err
+		},
+	}
+
+	actors := map[string]access.Actor{
+		"no-comment-permission": viewerActor,
+		"no-binding":            {UserID: "stranger", Role: access.RoleEditor},
+	}
+
+	for callName, call := range calls {
+		for actorName, actor := range actors {
+			t.Run(callName+"-"+actorName, func(t *testing.T) {
+				ts := newTestStore(false, testThread(comments.StatusOpen)...)
+				tl := newTestListener(false)
+
+				err := call(context.Background(), newTestService(ts, tl), actor)
+
+				require.IsType(t, errorz.AccessDeniedError{}, err)
+				require.Equal(t, testThread(comments.StatusOpen), []comments.Comment{ts.comments["t"], ts.comments["r"]})
+				require.Empty(t, tl.eventFired)
+			})
+		}
+	}
+}
+
+func TestCommentService_GetComment(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(newTestStore(false, testThread(comments.StatusOpen)...), newTestListener(false))
+
+	_, err := svc.GetComment(context.Background(), authorActor, "")
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.GetComment(context.Background(), authorActor, "missing")
+	require.IsType(t, errorz.NotFoundError{}, err)
+
+	_, err = svc.GetComment(context.Background(), viewerActor, "r")
+	require.IsType(t, errorz.AccessDeniedError{}, err)
+
+	c, err := svc.GetComment(context.Background(), reviewerActor, "r")
+
+	require.NoError(t, err)
+	require.Equal(t, testThread(comments.StatusOpen)[1], c)
+}
+
+func TestCommentService_GetThread(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		id         string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"no-read-permission": {
+			actor:   viewerActor,
+			id:      "r",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			id:         "t",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success-thread": {
+			actor: reviewerActor,
+			id:    "t",
+		},
+		"success-reply": {
+			actor: authorActor,
+			id:    "r",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testThread(comments.StatusOpen)...)
+
+			found, err := newTestService(ts, newTestListener(false)).GetThread(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, found)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testThread(comments.StatusOpen), found)
+			}
+		})
+	}
+}
+
+func TestCommentService_ListThreads(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		query      comments.CommentQuery
+		storeError bool
+		wantLen    int
+		wantErr    error
+	}{
+		"invalid-query": {
+			actor:   authorActor,
+			query:   comments.CommentQuery{},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-read-permission": {
+			actor:   viewerActor,
+			query:   comments.CommentQuery{ModelID: validModelID},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"other-model": {
+			actor:   authorActor,
+			query:   comments.CommentQuery{ModelID: "model2"},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      authorActor,
+			query:      comments.CommentQuery{ModelID: validModelID},
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			actor:   authorActor,
+			query:   comments.CommentQuery{ModelID: validModelID, RelationID: validRelationID},
+			wantLen: 1,
+		},
+		"success-filtered": {
+			actor:   reviewerActor,
+			query:   comments.CommentQuery{ModelID: validModelID, Statuses: []comments.Status{comments.StatusResolved}},
+			wantLen: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testThread(comments.StatusOpen)...)
+
+			found, err := newTestService(ts, newTestListener(false)).ListThreads(context.Background(), test.actor, test.query)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, found)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, found, test.wantLen)
+			}
+		})
+	}
+}
+
+func TestCommentService_HandleMeshEvent(t *testing.T) {
+	t.Parallel()
+
+	nodeComment := comments.Comment{
+		ID:      "n",
+		ModelID: validModelID,
+		Anchor:  comments.Anchor{ElementType: comments.ElementTypeNode, ElementID: validNodeID},
+	}
+
+	tests := map[string]struct {
+		event        models.MeshEvent
+		storeError   bool
+		wantOrphaned []string
+		wantErr      error
+	}{
+		"other-event": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsUpdated},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			wantOrphaned: []string{},
+		},
+		"node-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+				Deletes: models.Mesh{
+					ModelID: validModelID,
+					Nodes:   map[string]models.Node{validNodeID: {ID: validNodeID}},
+				},
+			},
+			wantOrphaned: []string{"n"},
+		},
+		"relation-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+				Deletes: models.Mesh{
+					ModelID:   validModelID,
+					Relations: map[string]models.Relation{validRelationID: {ID: validRelationID}},
+				},
+			},
+			wantOrphaned: []string{"r", "t"},
+		},
+		"other-element-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+				Deletes: models.Mesh{
+					ModelID: validModelID,
+					Nodes:   map[string]models.Node{validRelationID: {ID: validRelationID}},
+				},
+			},
+			wantOrphaned: []string{},
+		},
+		"mesh-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshDeleted},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			wantOrphaned: []string{"n", "r", "t"},
+		},
+		"store-error": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshDeleted},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, append(testThread(comments.StatusOpen), nodeComment)...)
+
+			err := newTestService(ts, newTestListener(false)).HandleMeshEvent(context.Background(), test.event)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			orphaned := []string{}
+
+			for _, id := range []string{"n", "r", "t"} {
+				if ts.comments[id].Orphaned {
+					orphaned = append(orphaned, id)
+				}
+			}
+
+			require.Equal(t, test.wantOrphaned, orphaned)
+		})
+	}
+}
+
+func TestCommentService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestStore(false, testThread(comments.StatusOpen)...)
+	svc := newTestService(ts, newTestListener(false))
+
+	require.IsType(t, errorz.ValidationError{}, svc.DeleteModelResources(context.Background(), adminActor, ""))
+	require.NoError(t, svc.DeleteModelResources(context.Background(), adminActor, validModelID))
+	require.Empty(t, ts.comments)
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminActor      = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	authorActor     = access.Actor{UserID: "author", Role: access.RoleCreator}
+	reviewerActor   = access.Actor{UserID: "reviewer", Role: access.RoleCreator}
+	viewerActor     = access.Actor{UserID: "viewer", Role: access.RoleGuest}
+	validModelID    = "model1"
+	validNodeID     = "node1"
+	validRelationID = "relation1"
+	validCommentID  = "1" // must match generated ID from testIDGenerator
+	validAnchor     = comments.Anchor{
+		ElementType: comments.ElementTypeRelation,
+		ElementID:   validRelationID,
+		PropPath:    "electrical.impedance",
+	}
+	validCommentData = comments.CommentData{
+		ModelID: validModelID,
+		Anchor:  validAnchor,
+		Text:    "this impedance looks wrong",
+	}
+	testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testIDGenerator struct {
+	idCounter atomic.Int64
+}
+
+// Ensure that the testIDGenerator implements the idGenerator interface.
+var _ idGenerator = (*testIDGenerator)(nil)
+
+func newTestIDGenerator() *testIDGenerator {
+	return &testIDGenerator{}
+}
+
+func (g *testIDGenerator) GenerateID() string {
+	return strconv.FormatInt(g.idCounter.Add(1), 10)
+}
+
+type testListener struct {
+	forcedError error
+	eventFired  comments.Event
+}
+
+// Ensure that the testListener implements the listener interface.
+var _ listener = (*testListener)(nil)
+
+func newTestListener(forcedError bool) *testListener {
+	var err error
+
+	if forcedError {
+		err = errorz.NewGatewayError("forced-error")
+	}
+
+	return &testListener{forcedError: err}
+}
+
+func (l *testListener) HandleCommentEvent(_ context.Context, event comments.Event) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+// testStore keeps the comments in memory.
+type testStore struct {
+	forcedError error
+	comments    map[string]comments.Comment
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...comments.Comment) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		comments:    map[string]comments.Comment{},
+	}
+
+	for _, c := range initial {
+		s.comments[c.ID] = c
+	}
+
+	return s
+}
+
+func (s *testStore) CreateComment(_ context.Context, comment comments.Comment) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.comments[comment.ID] = comment
+
+	return nil
+}
+
+func (s *testStore) UpdateComment(_ context.Context, comment comments.Comment) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.comments[comment.ID]; !ok {
+		return errorz.NewNotFoundError("comment %s not found", comment.ID)
+	}
+
+	s.comments[comment.ID] = comment
+
+	return nil
+}
+
+func (s *testStore) DeleteComment(_ context.Context, id string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.comments[id]; !ok {
+		return errorz.NewNotFoundError("comment %s not found", id)
+	}
+
+	delete(s.comments, id)
+
+	return nil
+}
+
+func (s *testStore) DeleteThread(_ context.Context, id string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for cid, c := range s.comments {
+		if c.ID == id || c.ThreadID == id {
+			delete(s.comments, cid)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) DeleteModelComments(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, c := range s.comments {
+		if c.ModelID == modelID {
+			delete(s.comments, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) GetComment(_ context.Context, id string) (comments.Comment, error) {
+	if s.forcedError != nil {
+		return comments.Comment{}, s.forcedError
+	}
+
+	c, ok := s.comments[id]
+	if !ok {
+		return comments.Comment{}, errorz.NewNotFoundError("comment %s not found", id)
+	}
+
+	return c, nil
+}
+
+func (s *testStore) GetThread(_ context.Context, id string) ([]comments.Comment, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []comments.Comment
+
+	for _, c := range s.comments {
+		if c.ID == id || c.ThreadID == id {
+			found = append(found, c)
+		}
+	}
+
+	return sortedComments(found), nil
+}
+
+func (s *testStore) GetThreads(_ context.Context, query comments.CommentQuery) ([]comments.Comment, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []comments.Comment
+
+	for _, c := range s.comments {
+		if !c.IsThread() || c.ModelID != query.ModelID ||
+			(query.NodeID != "" && c.Anchor.ElementID != query.NodeID) ||
+			(query.RelationID != "" && c.Anchor.ElementID != query.RelationID) ||
+			(len(query.Statuses) > 0 && !slices.Contains(query.Statuses, c.Status)) ||
+			(query.OrphanedOnly && !c.Orphaned) {
+			continue
+		}
+
+		found = append(found, c)
+	}
+
+	return sortedComments(found), nil
+}
+
+func (s *testStore) OrphanElementComments(
+	_ context.Context,
+	modelID string,
+	elementType comments.ElementType,
+	ids []string,
+) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, c := range s.comments {
+		if c.ModelID == modelID && c.Anchor.ElementType == elementType && slices.Contains(ids, c.Anchor.ElementID) {
+			c.Orphaned = true
+			s.comments[id] = c
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) OrphanModelComments(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, c := range s.comments {
+		if c.ModelID == modelID {
+			c.Orphaned = true
+			s.comments[id] = c
+		}
+	}
+
+	return nil
+}
+
+func sortedComments(found []comments.Comment) []comments.Comment {
+	slices.SortFunc(found, func(a, b comments.Comment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return found
+}
+
+// testElementProvider knows the valid node and the valid relation only.
+type testElementProvider struct{}
+
+// Ensure that the testElementProvider implements the elementProvider interface.
+var _ elementProvider = testElementProvider{}
+
+func (testElementProvider) GetNode(_ context.Context, modelID, nodeID string) (models.Node, error) {
+	if modelID != validModelID || nodeID != validNodeID {
+		return models.Node{}, errorz.NewNotFoundError("node %s not found", nodeID)
+	}
+
+	return models.Node{ID: nodeID}, nil
+}
+
+func (testElementProvider) GetRelation(_ context.Context, modelID, relationID string) (models.Relation, error) {
+	if modelID != validModelID || relationID != validRelationID {
+		return models.Relation{}, errorz.NewNotFoundError("relation %s not found", relationID)
+	}
+
+	return models.Relation{ID: relationID}, nil
+}
+
+// testBindingProvider grants the author and the reviewer the guest role and the viewer no
+// role on the valid model.
+type testBindingProvider struct{}
+
+func (testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	roles := map[string]access.Role{
+		authorActor.UserID:   access.RoleGuest,
+		reviewerActor.UserID: access.RoleGuest,
+		viewerActor.UserID:   access.RoleNone,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testThread returns a thread with a reply. The thread has the ID "t", the reply "r".
+func testThread(status comments.Status) []comments.Comment {
+	thread := comments.Comment{
+		ID:        "t",
+		ModelID:   validModelID,
+		Anchor:    validAnchor,
+		AuthorID:  authorActor.UserID,
+		Text:      validCommentData.Text,
+		Status:    status,
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}
+
+	if status == comments.StatusResolved {
+		thread.ResolvedBy = reviewerActor.UserID
+		thread.ResolvedAt = testTime
+	}
+
+	reply := comments.Comment{
+		ID:        "r",
+		ModelID:   validModelID,
+		ThreadID:  thread.ID,
+		Anchor:    validAnchor,
+		AuthorID:  reviewerActor.UserID,
+		Text:      "agreed",
+		Status:    comments.StatusOpen,
+		CreatedAt: testTime.Add(time.Minute),
+		UpdatedAt: testTime.Add(time.Minute),
+	}
+
+	return []comments.Comment{thread, reply}
+}
+
+func newTestService(ts *testStore, tl *testListener) *CommentService {
+	svc := NewCommentService(ts, newTestIDGenerator(), testElementProvider{},
+		authz.NewAuthorizer(testBindingProvider{}), WithListener(tl))
+	svc.now = func() time.Time { return testTime.Add(time.Hour) }
+
+	return svc
+}
+
+func requireEventFired(t *testing.T, wantEvent comments.EventType, listener *testListener) {
+	t.Helper()
+
+	eventFired := listener.eventFired
+
+	require.NotEmpty(t, eventFired)
+
+	ce, ok := comments.ExtractCommentEvent(eventFired)
+
+	require.True(t, ok)
+
+	require.Equal(t, wantEvent, ce.Type)
+	require.NotEmpty(t, ce.Actor)
+	require.NotEmpty(t, ce.Comment)
+	require.NotEmpty(t, ce.Timestamp)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+)
+
+// maxTextLength is the maximum length of a comment text in bytes.
+const maxTextLength = 10000
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateID(id string) error {
+	return requireString(id, "id")
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errorz.NewValidationError("comment text is required")
+	}
+
+	if len(text) > maxTextLength {
+		return errorz.NewValidationError("comment text must not be longer than %d bytes", maxTextLength)
+	}
+
+	return nil
+}
+
+// validateAnchor checks the anchor. The prop path is optional and must be given in
+// "section.key" notation.
+func validateAnchor(anchor comments.Anchor) error {
+	if !slices.Contains(comments.AllElementTypes, anchor.ElementType) {
+		return errorz.NewValidationError("invalid element type: %v", anchor.ElementType)
+	}
+
+	if err := requireString(anchor.ElementID, "element id"); err != nil {
+		return err
+	}
+
+	if anchor.PropPath == "" {
+		return nil
+	}
+
+	section, key, ok := strings.Cut(anchor.PropPath, ".")
+	if !ok || section == "" || key == "" {
+		return errorz.NewValidationError("prop path %q must be in section.key notation", anchor.PropPath)
+	}
+
+	return nil
+}
+
+func validateCommentData(data comments.CommentData) error {
+	if err := validateModelID(data.ModelID); err != nil {
+		return err
+	}
+
+	if err := validateAnchor(data.Anchor); err != nil {
+		return err
+	}
+
+	return validateText(data.Text)
+}
+
+func validateCommentQuery(query comments.CommentQuery) error {
+	if err := validateModelID(query.ModelID); err != nil {
+		return err
+	}
+
+	if query.NodeID != "" && query.RelationID != "" {
+		return errorz.NewValidationError("node id cannot be combined with relation id")
+	}
+
+	for _, s := range query.Statuses {
+		if !slices.Contains(comments.AllStatuses, s) {
+			return errorz.NewValidationError("invalid comment status: %v", s)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateID("1"))
+	require.Error(t, validateID(""))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateText(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateText("looks good"))
+	require.NoError(t, validateText(strings.Repeat("a", maxTextLength)))
+	require.Error(t, validateText(""))
+	require.Error(t, validateText(" \n\t"))
+	require.Error(t, validateText(strings.Repeat("a", maxTextLength+1)))
+}
+
+func Test_validateAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		anchor  comments.Anchor
+		wantErr bool
+	}{
+		"node":             {anchor: comments.Anchor{ElementType: comments.ElementTypeNode, ElementID: "n1"}},
+		"relation-prop":    {anchor: validAnchor},
+		"nested-prop-key":  {anchor: comments.Anchor{ElementID: "n1", PropPath: "tech.rating.max"}},
+		"invalid-type":     {anchor: comments.Anchor{ElementType: 100, ElementID: "n1"}, wantErr: true},
+		"missing-id":       {anchor: comments.Anchor{ElementType: comments.ElementTypeNode}, wantErr: true},
+		"prop-without-key": {anchor: comments.Anchor{ElementID: "n1", PropPath: "tech"}, wantErr: true},
+		"prop-empty-key":   {anchor: comments.Anchor{ElementID: "n1", PropPath: "tech."}, wantErr: true},
+		"prop-empty-sect":  {anchor: comments.Anchor{ElementID: "n1", PropPath: ".key"}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateAnchor(test.anchor)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateCommentData(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateCommentData(validCommentData))
+	require.Error(t, validateCommentData(comments.CommentData{Anchor: validAnchor, Text: "text"}))
+	require.Error(t, validateCommentData(comments.CommentData{ModelID: validModelID, Text: "text"}))
+	require.Error(t, validateCommentData(comments.CommentData{ModelID: validModelID, Anchor: validAnchor}))
+}
+
+func Test_validateCommentQuery(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateCommentQuery(comments.CommentQuery{ModelID: "1"}))
+	require.NoError(t, validateCommentQuery(comments.CommentQuery{
+		ModelID:      "1",
+		NodeID:       "2",
+		Statuses:     comments.AllStatuses,
+		OrphanedOnly: true,
+	}))
+	require.Error(t, validateCommentQuery(comments.CommentQuery{}))
+	require.Error(t, validateCommentQuery(comments.CommentQuery{ModelID: "1", NodeID: "2", RelationID: "3"}))
+	require.Error(t, validateCommentQuery(comments.CommentQuery{ModelID: "1", Statuses: []comments.Status{100}}))
+}
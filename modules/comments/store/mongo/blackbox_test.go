@@ -0,0 +1,60 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/energimind/powermesh-core/modules/comments/store/mongo"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testComment returns a comment on a node. A reply is created if the thread ID is given.
+func testComment(id, threadID, nodeID string, minute int) comments.Comment {
+	return comments.Comment{
+		ID:       id,
+		ModelID:  "model1",
+		ThreadID: threadID,
+		Anchor: comments.Anchor{
+			ElementType: comments.ElementTypeNode,
+			ElementID:   nodeID,
+		},
+		AuthorID:  "user1",
+		Text:      "comment " + id,
+		Status:    comments.StatusOpen,
+		CreatedAt: testTime.Add(time.Duration(minute) * time.Minute),
+		UpdatedAt: testTime.Add(time.Duration(minute) * time.Minute),
+	}
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.CommentStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewCommentStore(db)
+
+	f(t, ctx, store)
+}
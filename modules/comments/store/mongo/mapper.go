@@ -0,0 +1,70 @@
+package mongo
+
+import (
+	"github.com/energimind/powermesh-core/modules/comments"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+func toStoreComment(c comments.Comment) storeComment {
+	return storeComment{
+		ID:       c.ID,
+		ModelID:  c.ModelID,
+		ThreadID: c.ThreadID,
+		Anchor: storeAnchor{
+			ElementType: c.Anchor.ElementType,
+			ElementID:   c.Anchor.ElementID,
+			PropPath:    c.Anchor.PropPath,
+		},
+		AuthorID:   c.AuthorID,
+		Text:       c.Text,
+		Status:     c.Status,
+		Orphaned:   c.Orphaned,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+		ResolvedBy: c.ResolvedBy,
+		ResolvedAt: c.ResolvedAt,
+	}
+}
+
+func fromStoreComment(c storeComment) comments.Comment {
+	return comments.Comment{
+		ID:       c.ID,
+		ModelID:  c.ModelID,
+		ThreadID: c.ThreadID,
+		Anchor: comments.Anchor{
+			ElementType: c.Anchor.ElementType,
+			ElementID:   c.Anchor.ElementID,
+			PropPath:    c.Anchor.PropPath,
+		},
+		AuthorID:   c.AuthorID,
+		Text:       c.Text,
+		Status:     c.Status,
+		Orphaned:   c.Orphaned,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+		ResolvedBy: c.ResolvedBy,
+		ResolvedAt: c.ResolvedAt,
+	}
+}
+
+// threadsFilter builds the filter of the threads matching the query.
+func threadsFilter(query comments.CommentQuery) q.Filter {
+	filter := q.Filter{}.EQ(fieldModelID, query.ModelID).EQ(fieldThreadID, "")
+
+	switch {
+	case query.NodeID != "":
+		filter = filter.EQ(fieldElementType, comments.ElementTypeNode).EQ(fieldElementID, query.NodeID)
+	case query.RelationID != "":
+		filter = filter.EQ(fieldElementType, comments.ElementTypeRelation).EQ(fieldElementID, query.RelationID)
+	}
+
+	if len(query.Statuses) > 0 {
+		filter = filter.IN(fieldStatus, query.Statuses)
+	}
+
+	if query.OrphanedOnly {
+		filter = filter.EQ(fieldOrphaned, true)
+	}
+
+	return filter
+}
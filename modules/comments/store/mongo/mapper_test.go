@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/comments"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreComment, toStoreComment(validComment))
+	require.Equal(t, validComment, fromStoreComment(validStoreComment))
+}
+
+func Test_threadsFilter(t *testing.T) {
+	t.Parallel()
+
+	threads := func() q.Filter {
+		return q.Filter{}.EQ(fieldModelID, "model1").EQ(fieldThreadID, "")
+	}
+
+	tests := map[string]struct {
+		query comments.CommentQuery
+		want  q.Filter
+	}{
+		"model": {
+			query: comments.CommentQuery{ModelID: "model1"},
+			want:  threads(),
+		},
+		"node": {
+			query: comments.CommentQuery{ModelID: "model1", NodeID: "node1"},
+			want:  threads().EQ(fieldElementType, comments.ElementTypeNode).EQ(fieldElementID, "node1"),
+		},
+		"relation": {
+			query: comments.CommentQuery{ModelID: "model1", RelationID: "relation1"},
+			want:  threads().EQ(fieldElementType, comments.ElementTypeRelation).EQ(fieldElementID, "relation1"),
+		},
+		"statuses-orphaned": {
+			query: comments.CommentQuery{
+				ModelID:      "model1",
+				Statuses:     []comments.Status{comments.StatusOpen},
+				OrphanedOnly: true,
+			},
+			want: threads().IN(fieldStatus, []comments.Status{comments.StatusOpen}).EQ(fieldOrphaned, true),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, threadsFilter(test.query))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/comments"
+)
+
+// storeComment represents a comment in the MongoDB store.
+type storeComment struct {
+	ID         string          `bson:"id"`
+	ModelID    string          `bson:"modelId"`
+	ThreadID   string          `bson:"threadId"`
+	Anchor     storeAnchor     `bson:"anchor"`
+	AuthorID   string          `bson:"authorId"`
+	Text       string          `bson:"text"`
+	Status     comments.Status `bson:"status"`
+	Orphaned   bool            `bson:"orphaned"`
+	CreatedAt  time.Time       `bson:"createdAt"`
+	UpdatedAt  time.Time       `bson:"updatedAt"`
+	ResolvedBy string          `bson:"resolvedBy"`
+	ResolvedAt time.Time       `bson:"resolvedAt"`
+}
+
+// storeAnchor represents the anchor of a comment in the MongoDB store.
+type storeAnchor struct {
+	ElementType comments.ElementType `bson:"elementType"`
+	ElementID   string               `bson:"elementId"`
+	PropPath    string               `bson:"propPath"`
+}
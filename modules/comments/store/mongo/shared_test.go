@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/comments"
+)
+
+var (
+	validComment = comments.Comment{
+		ID:       "2",
+		ModelID:  "model1",
+		ThreadID: "1",
+		Anchor: comments.Anchor{
+			ElementType: comments.ElementTypeRelation,
+			ElementID:   "relation1",
+			PropPath:    "electrical.impedance",
+		},
+		AuthorID:   "user1",
+		Text:       "this impedance looks wrong",
+		Status:     comments.StatusResolved,
+		Orphaned:   true,
+		CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		ResolvedBy: "user2",
+		ResolvedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	validStoreComment = storeComment{
+		ID:       validComment.ID,
+		ModelID:  validComment.ModelID,
+		ThreadID: validComment.ThreadID,
+		Anchor: storeAnchor{
+			ElementType: validComment.Anchor.ElementType,
+			ElementID:   validComment.Anchor.ElementID,
+			PropPath:    validComment.Anchor.PropPath,
+		},
+		AuthorID:   validComment.AuthorID,
+		Text:       validComment.Text,
+		Status:     validComment.Status,
+		Orphaned:   validComment.Orphaned,
+		CreatedAt:  validComment.CreatedAt,
+		UpdatedAt:  validComment.UpdatedAt,
+		ResolvedBy: validComment.ResolvedBy,
+		ResolvedAt: validComment.ResolvedAt,
+	}
+)
@@ -0,0 +1,142 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/modules/comments"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	collComments     = "comments"
+	fieldID          = "id"
+	fieldModelID     = "modelId"
+	fieldThreadID    = "threadId"
+	fieldElementType = "anchor.elementType"
+	fieldElementID   = "anchor.elementId"
+	fieldStatus      = "status"
+	fieldOrphaned    = "orphaned"
+	fieldCreatedAt   = "createdAt"
+)
+
+// CommentStore is a MongoDB implementation of the comment store.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type CommentStore struct {
+	comments *mongo.Collection
+}
+
+// NewCommentStore creates a new MongoDB comment store.
+func NewCommentStore(db *mongo.Database) *CommentStore {
+	return &CommentStore{
+		comments: db.Collection(collComments),
+	}
+}
+
+// CreateComment implements the comment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) CreateComment(ctx context.Context, comment comments.Comment) error {
+	return q.CreateOne(s.comments, toStoreComment).Exec(ctx, comment)
+}
+
+// UpdateComment implements the comment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) UpdateComment(ctx context.Context, comment comments.Comment) error {
+	return q.UpdateOne(s.comments, toStoreComment).Exec(ctx, comment.ID, comment)
+}
+
+// DeleteComment implements the comment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) DeleteComment(ctx context.Context, id string) error {
+	return q.DeleteOne(s.comments).Exec(ctx, id)
+}
+
+// DeleteThread implements the comment store interface.
+// It deletes the first comment of the thread and all replies.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) DeleteThread(ctx context.Context, id string) error {
+	_, err := q.DeleteMany(s.comments).Exec(ctx, q.Filter{}.EQ(fieldThreadID, id))
+	if err != nil {
+		return err
+	}
+
+	return q.DeleteOne(s.comments).Exec(ctx, id)
+}
+
+// DeleteModelComments implements the comment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) DeleteModelComments(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.comments).Exec(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+
+	return err
+}
+
+// GetComment implements the comment store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) GetComment(ctx context.Context, id string) (comments.Comment, error) {
+	return q.GetOne(s.comments, fromStoreComment).Exec(ctx, id)
+}
+
+// GetThread implements the comment store interface.
+// The first comment of the thread is followed by the replies in the order they were written.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) GetThread(ctx context.Context, id string) ([]comments.Comment, error) {
+	filter := q.Filter{}.OR(q.Filter{}.EQ(fieldID, id), q.Filter{}.EQ(fieldThreadID, id))
+
+	return q.FindMany(s.comments, fromStoreComment).
+		WithSort(fieldCreatedAt, false).
+		WithSort(fieldID, false).
+		Exec(ctx, filter)
+}
+
+// GetThreads implements the comment store interface.
+// It returns the first comments of the threads in the order they were written.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) GetThreads(ctx context.Context, query comments.CommentQuery) ([]comments.Comment, error) {
+	return q.FindMany(s.comments, fromStoreComment).
+		WithSort(fieldCreatedAt, false).
+		WithSort(fieldID, false).
+		Exec(ctx, threadsFilter(query))
+}
+
+// OrphanElementComments implements the comment store interface.
+// It flags all comments anchored to the elements as orphaned.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) OrphanElementComments(
+	ctx context.Context,
+	modelID string,
+	elementType comments.ElementType,
+	ids []string,
+) error {
+	filter := q.Filter{}.
+		EQ(fieldModelID, modelID).
+		EQ(fieldElementType, elementType).
+		IN(fieldElementID, ids)
+
+	_, err := q.UpdateMany(s.comments).Exec(ctx, filter, map[string]any{fieldOrphaned: true})
+
+	return err
+}
+
+// OrphanModelComments implements the comment store interface.
+// It flags all comments of the model as orphaned.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *CommentStore) OrphanModelComments(ctx context.Context, modelID string) error {
+	filter := q.Filter{}.EQ(fieldModelID, modelID)
+
+	_, err := q.UpdateMany(s.comments).Exec(ctx, filter, map[string]any{fieldOrphaned: true})
+
+	return err
+}
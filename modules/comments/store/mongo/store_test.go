@@ -0,0 +1,250 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/comments"
+	"github.com/energimind/powermesh-core/modules/comments/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func createComments(t *testing.T, ctx context.Context, store *mongo.CommentStore, cs ...comments.Comment) {
+	t.Helper()
+
+	for _, c := range cs {
+		require.NoError(t, store.CreateComment(ctx, c))
+	}
+}
+
+func commentIDs(found []comments.Comment) []string {
+	ids := make([]string, 0, len(found))
+
+	for _, c := range found {
+		ids = append(ids, c.ID)
+	}
+
+	return ids
+}
+
+func TestCommentStore_CreateComment(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		comment := testComment("1", "", "node1", 0)
+		comment.Anchor.PropPath = "tech.rating"
+
+		require.NoError(t, store.CreateComment(ctx, comment))
+
+		created, err := store.GetComment(ctx, comment.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, comment, created)
+	})
+}
+
+func TestCommentStore_UpdateComment(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.UpdateComment(ctx, testComment("1", "", "node1", 0)))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			comment := testComment("1", "", "node1", 0)
+
+			require.NoError(t, store.CreateComment(ctx, comment))
+
+			comment.Status = comments.StatusResolved
+			comment.ResolvedBy = "user2"
+			comment.ResolvedAt = testTime
+
+			require.NoError(t, store.UpdateComment(ctx, comment))
+
+			updated, err := store.GetComment(ctx, comment.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, comment, updated)
+		})
+	})
+}
+
+func TestCommentStore_DeleteComment(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.DeleteComment(ctx, "missing"))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			createComments(t, ctx, store, testComment("1", "", "node1", 0))
+
+			require.NoError(t, store.DeleteComment(ctx, "1"))
+
+			_, err := store.GetComment(ctx, "1")
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+	})
+}
+
+func TestCommentStore_DeleteThread(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.DeleteThread(ctx, "missing"))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			createComments(t, ctx, store,
+				testComment("1", "", "node1", 0),
+				testComment("2", "1", "node1", 1),
+				testComment("3", "", "node1", 2))
+
+			require.NoError(t, store.DeleteThread(ctx, "1"))
+
+			found, err := store.GetThreads(ctx, comments.CommentQuery{ModelID: "model1"})
+
+			require.NoError(t, err)
+			require.Equal(t, []string{"3"}, commentIDs(found))
+
+			_, err = store.GetComment(ctx, "2")
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+	})
+}
+
+func TestCommentStore_DeleteModelComments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		createComments(t, ctx, store, testComment("1", "", "node1", 0), testComment("2", "1", "node1", 1))
+
+		require.NoError(t, store.DeleteModelComments(ctx, "model1"))
+
+		_, err := store.GetComment(ctx, "2")
+		require.IsType(t, errorz.NotFoundError{}, err)
+	})
+}
+
+func TestCommentStore_GetThread(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		createComments(t, ctx, store,
+			testComment("3", "1", "node1", 2),
+			testComment("1", "", "node1", 0),
+			testComment("2", "1", "node1", 1),
+			testComment("4", "", "node1", 3))
+
+		found, err := store.GetThread(ctx, "1")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"1", "2", "3"}, commentIDs(found))
+	})
+}
+
+func TestCommentStore_GetThreads(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		resolved := testComment("3", "", "node2", 2)
+		resolved.Status = comments.StatusResolved
+
+		relation := testComment("4", "", "relation1", 3)
+		relation.Anchor.ElementType = comments.ElementTypeRelation
+		relation.Orphaned = true
+
+		createComments(t, ctx, store,
+			testComment("1", "", "node1", 0),
+			testComment("2", "1", "node1", 1),
+			resolved,
+			relation)
+
+		tests := map[string]struct {
+			query   comments.CommentQuery
+			wantIDs []string
+		}{
+			"model": {
+				query:   comments.CommentQuery{ModelID: "model1"},
+				wantIDs: []string{"1", "3", "4"},
+			},
+			"node": {
+				query:   comments.CommentQuery{ModelID: "model1", NodeID: "node1"},
+				wantIDs: []string{"1"},
+			},
+			"relation": {
+				query:   comments.CommentQuery{ModelID: "model1", RelationID: "relation1"},
+				wantIDs: []string{"4"},
+			},
+			"open": {
+				query:   comments.CommentQuery{ModelID: "model1", Statuses: []comments.Status{comments.StatusOpen}},
+				wantIDs: []string{"1", "4"},
+			},
+			"orphaned": {
+				query:   comments.CommentQuery{ModelID: "model1", OrphanedOnly: true},
+				wantIDs: []string{"4"},
+			},
+			"other-model": {
+				query:   comments.CommentQuery{ModelID: "model2"},
+				wantIDs: []string{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				found, err := store.GetThreads(ctx, test.query)
+
+				require.NoError(t, err)
+				require.Equal(t, test.wantIDs, commentIDs(found))
+			})
+		}
+	})
+}
+
+func TestCommentStore_OrphanElementComments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		relation := testComment("4", "", "node1", 3)
+		relation.Anchor.ElementType = comments.ElementTypeRelation
+
+		createComments(t, ctx, store,
+			testComment("1", "", "node1", 0),
+			testComment("2", "1", "node1", 1),
+			testComment("3", "", "node2", 2),
+			relation)
+
+		require.NoError(t, store.OrphanElementComments(ctx, "model1", comments.ElementTypeNode, []string{"node1"}))
+
+		for id, want := range map[string]bool{"1": true, "2": true, "3": false, "4": false} {
+			c, err := store.GetComment(ctx, id)
+
+			require.NoError(t, err)
+			require.Equal(t, want, c.Orphaned, id)
+		}
+	})
+}
+
+func TestCommentStore_OrphanModelComments(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.CommentStore) {
+		createComments(t, ctx, store, testComment("1", "", "node1", 0), testComment("2", "1", "node2", 1))
+
+		require.NoError(t, store.OrphanModelComments(ctx, "model1"))
+
+		found, err := store.GetThreads(ctx, comments.CommentQuery{ModelID: "model1", OrphanedOnly: true})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"1"}, commentIDs(found))
+
+		reply, err := store.GetComment(ctx, "2")
+
+		require.NoError(t, err)
+		require.True(t, reply.Orphaned)
+	})
+}
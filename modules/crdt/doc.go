@@ -0,0 +1,9 @@
+// Package crdt provides a model and service for editing meshes concurrently and offline on
+// several replicas and merging the edits without conflicts.
+//
+// The mesh is modelled as a conflict-free replicated data type: the nodes and relations are
+// add-wins sets, and the fields and properties of the elements are last-writer-wins
+// registers. Every operation is identified by a dot, the replica that made it and its
+// counter at that replica, and the causal context of a state is the version vector of the
+// dots it has seen.
+package crdt
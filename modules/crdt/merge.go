@@ -0,0 +1,165 @@
+package crdt
+
+// Merge returns the state joining the state with the delta. Merging is commutative,
+// associative and idempotent, so replicas merging the same deltas in any order and any
+// number of times end up in the same state.
+func (s State) Merge(delta State) State {
+	return State{
+		ModelID:   s.ModelID,
+		Context:   s.Context.Merge(delta.Context),
+		Nodes:     mergeElements(s.Nodes, delta.Nodes, s.Context, delta.Context),
+		Relations: mergeElements(s.Relations, delta.Relations, s.Context, delta.Context),
+		Version:   s.Version,
+	}
+}
+
+// Delta returns the part of the state a replica that has seen the dots of the version
+// vector is missing.
+func (s State) Delta(since VersionVector) State {
+	return State{
+		ModelID:   s.ModelID,
+		Context:   s.Context.Merge(nil),
+		Nodes:     deltaElements(s.Nodes, since),
+		Relations: deltaElements(s.Relations, since),
+	}
+}
+
+func mergeElements(a, b map[string]Element, aContext, bContext VersionVector) map[string]Element {
+	merged := make(map[string]Element, max(len(a), len(b)))
+
+	for id, ea := range a {
+		merged[id] = mergeElement(ea, b[id], aContext, bContext)
+	}
+
+	for id, eb := range b {
+		if _, ok := a[id]; !ok {
+			merged[id] = mergeElement(Element{}, eb, aContext, bContext)
+		}
+	}
+
+	return merged
+}
+
+// mergeElement joins the elements. An add dot stays in effect if both sides have it, or
+// one side has it and the other has not seen it yet.
+func mergeElement(a, b Element, aContext, bContext VersionVector) Element {
+	var dots []Dot
+
+	for _, d := range a.Dots {
+		if containsDot(b.Dots, d) || !bContext.Contains(d) {
+			dots = append(dots, d)
+		}
+	}
+
+	for _, d := range b.Dots {
+		if !aContext.Contains(d) {
+			dots = append(dots, d)
+		}
+	}
+
+	merged := Element{
+		Dots:   sortedDots(dots),
+		Fields: mergeRegisters(a.Fields, b.Fields),
+	}
+
+	for section, registers := range a.Props {
+		if merged.Props == nil {
+			merged.Props = map[string]map[string]Register{}
+		}
+
+		merged.Props[section] = mergeRegisters(registers, b.Props[section])
+	}
+
+	for section, registers := range b.Props {
+		if _, ok := a.Props[section]; ok {
+			continue
+		}
+
+		if merged.Props == nil {
+			merged.Props = map[string]map[string]Register{}
+		}
+
+		merged.Props[section] = mergeRegisters(nil, registers)
+	}
+
+	return merged
+}
+
+// mergeRegisters joins the registers by keeping the winning write of each key.
+func mergeRegisters(a, b map[string]Register) map[string]Register {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]Register, max(len(a), len(b)))
+
+	for key, r := range a {
+		merged[key] = r
+	}
+
+	for key, r := range b {
+		if current, ok := merged[key]; !ok || r.Wins(current) {
+			merged[key] = r
+		}
+	}
+
+	return merged
+}
+
+// deltaElements returns the elements with the add dots in effect and the registers not
+// seen by the version vector. Removed elements are left out unless they have such
+// registers.
+func deltaElements(elements map[string]Element, since VersionVector) map[string]Element {
+	delta := map[string]Element{}
+
+	for id, e := range elements {
+		d := Element{
+			Dots:   append([]Dot(nil), e.Dots...),
+			Fields: unseenRegisters(e.Fields, since),
+		}
+
+		for section, registers := range e.Props {
+			if unseen := unseenRegisters(registers, since); unseen != nil {
+				if d.Props == nil {
+					d.Props = map[string]map[string]Register{}
+				}
+
+				d.Props[section] = unseen
+			}
+		}
+
+		if d.Exists() || d.Fields != nil || d.Props != nil {
+			delta[id] = d
+		}
+	}
+
+	return delta
+}
+
+func unseenRegisters(registers map[string]Register, since VersionVector) map[string]Register {
+	var unseen map[string]Register
+
+	for key, r := range registers {
+		if since.Contains(r.Dot) {
+			continue
+		}
+
+		if unseen == nil {
+			unseen = map[string]Register{}
+		}
+
+		unseen[key] = r
+	}
+
+	return unseen
+}
+
+func containsDot(dots []Dot, d Dot) bool {
+	for _, other := range dots {
+		if other == d {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+// replicate returns a copy of the state, as received by another replica.
+func replicate(s State) State {
+	return NewState(s.ModelID).Merge(s)
+}
+
+func TestState_Merge(t *testing.T) {
+	t.Parallel()
+
+	base := NewState("m1")
+	base.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10}}}, testTime)
+	base.PutNode("a", models.Node{ID: "b2", Kind: "bus", Code: "B2"}, testTime)
+
+	t.Run("commutative", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := replicate(base), replicate(base)
+		a.PutNode("a", models.Node{ID: "b3", Kind: "bus"}, testTime)
+		b.RemoveNode("b2")
+		b.PutNode("b", models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 20}}}, testTime)
+
+		require.Equal(t, a.Merge(b).Mesh(), b.Merge(a).Mesh())
+		require.Equal(t, a.Merge(b).Context, b.Merge(a).Context)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		t.Parallel()
+
+		a := replicate(base)
+		a.PutNode("a", models.Node{ID: "b3", Kind: "bus"}, testTime)
+
+		merged := a.Merge(a)
+
+		require.Equal(t, a.Mesh(), merged.Mesh())
+		require.Equal(t, merged, merged.Merge(a))
+	})
+
+	t.Run("add-wins", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := replicate(base), replicate(base)
+		a.RemoveNode("b1")
+		b.PutNode("b", models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 20}}}, testTime)
+
+		merged := a.Merge(b)
+
+		require.Contains(t, merged.Mesh().Nodes, "b1")
+		require.Equal(t, 20, merged.Mesh().Nodes["b1"].Props["load"]["kw"])
+	})
+
+	t.Run("remove-seen-add", func(t *testing.T) {
+		t.Parallel()
+
+		a := replicate(base)
+		a.RemoveNode("b2")
+
+		merged := base.Merge(a)
+
+		require.NotContains(t, merged.Mesh().Nodes, "b2")
+	})
+
+	t.Run("last-writer-wins", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := replicate(base), replicate(base)
+		a.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 20}}},
+			testTime.Add(2))
+		b.PutNode("b", models.Node{ID: "b1", Kind: "bus", Code: "X1", Props: models.PropBag{"load": {"kw": 30}}},
+			testTime.Add(1))
+
+		node := a.Merge(b).Mesh().Nodes["b1"]
+
+		require.Equal(t, "X1", node.Code, "code written by b only")
+		require.Equal(t, 20, node.Props["load"]["kw"], "later write of a")
+	})
+
+	t.Run("removed-prop", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := replicate(base), replicate(base)
+		a.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1"}, testTime.Add(1))
+		b.PutNode("b", models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{
+			"load": {"kw": 10},
+			"gen":  {"kw": 5},
+		}}, testTime.Add(1))
+
+		node := a.Merge(b).Mesh().Nodes["b1"]
+
+		require.Equal(t, models.PropBag{"gen": {"kw": 5}}, node.Props)
+	})
+}
+
+func TestState_Delta(t *testing.T) {
+	t.Parallel()
+
+	a := NewState("m1")
+	a.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1"}, testTime)
+	a.PutNode("a", models.Node{ID: "b2", Kind: "bus", Code: "B2"}, testTime)
+
+	b := replicate(a)
+
+	a.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "X1"}, testTime)
+	a.RemoveNode("b2")
+	a.PutNode("a", models.Node{ID: "b3", Kind: "bus", Code: "B3"}, testTime)
+
+	delta := a.Delta(b.Context)
+
+	require.Equal(t, a.Context, delta.Context)
+	require.Len(t, delta.Nodes["b1"].Fields, 1, "only the changed code")
+	require.NotContains(t, delta.Nodes, "b2")
+	require.Len(t, delta.Nodes["b3"].Fields, 2)
+
+	require.Equal(t, a.Mesh(), b.Merge(delta).Mesh())
+	require.Empty(t, a.Delta(a.Context).Nodes["b1"].Fields)
+}
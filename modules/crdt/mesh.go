@@ -0,0 +1,245 @@
+package crdt
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// PutNode records the node as written by the replica at the given time. Of its fields and
+// properties, only those that differ from the state are written, and properties missing
+// from the node are removed. A node that is written, or does not exist, gets a new add dot,
+// so the write wins over a concurrent removal.
+func (s *State) PutNode(replica string, node models.Node, at time.Time) {
+	fields := map[string]any{
+		FieldKind: node.Kind,
+		FieldCode: node.Code,
+	}
+
+	s.Nodes = s.put(s.Nodes, replica, node.ID, fields, node.Props, at)
+}
+
+// PutRelation records the relation as written by the replica at the given time, in the same
+// way as PutNode.
+func (s *State) PutRelation(replica string, relation models.Relation, at time.Time) {
+	fields := map[string]any{
+		FieldKind: relation.Kind,
+		FieldFrom: relation.From,
+		FieldTo:   relation.To,
+	}
+
+	s.Relations = s.put(s.Relations, replica, relation.ID, fields, relation.Props, at)
+}
+
+// RemoveNode records the removal of the node. Adds of the node the state has not seen
+// survive the removal when merged.
+func (s *State) RemoveNode(id string) {
+	s.Nodes = remove(s.Nodes, id)
+}
+
+// RemoveRelation records the removal of the relation, in the same way as RemoveNode.
+func (s *State) RemoveRelation(id string) {
+	s.Relations = remove(s.Relations, id)
+}
+
+// Mesh returns the mesh the state represents.
+//
+// To keep the mesh consistent, relations are left out while any of their endpoints does
+// not exist, such as a relation added concurrently with the removal of its endpoint. They
+// reappear if the endpoint is added again. If several nodes have the same code, only the
+// node that was given the code first keeps it.
+func (s State) Mesh() models.Mesh {
+	mesh := models.Mesh{
+		ModelID:   s.ModelID,
+		Nodes:     map[string]models.Node{},
+		Relations: map[string]models.Relation{},
+	}
+
+	codeOwners := map[string]string{}
+
+	for id, e := range s.Nodes {
+		if !e.Exists() {
+			continue
+		}
+
+		node := models.Node{
+			ID:    id,
+			Kind:  stringField(e, FieldKind),
+			Code:  stringField(e, FieldCode),
+			Props: props(e),
+		}
+
+		if node.Code != "" {
+			if owner, ok := codeOwners[node.Code]; ok {
+				if s.Nodes[owner].Fields[FieldCode].Wins(e.Fields[FieldCode]) {
+					other := mesh.Nodes[owner]
+					other.Code = ""
+					mesh.Nodes[owner] = other
+					codeOwners[node.Code] = id
+				} else {
+					node.Code = ""
+				}
+			} else {
+				codeOwners[node.Code] = id
+			}
+		}
+
+		mesh.Nodes[id] = node
+	}
+
+	for id, e := range s.Relations {
+		if !e.Exists() {
+			continue
+		}
+
+		relation := models.Relation{
+			ID:    id,
+			Kind:  stringField(e, FieldKind),
+			From:  stringField(e, FieldFrom),
+			To:    stringField(e, FieldTo),
+			Props: props(e),
+		}
+
+		_, fromOK := mesh.Nodes[relation.From]
+		_, toOK := mesh.Nodes[relation.To]
+
+		if fromOK && toOK {
+			mesh.Relations[id] = relation
+		}
+	}
+
+	return mesh
+}
+
+// put records the element as written by the replica.
+func (s *State) put(
+	elements map[string]Element,
+	replica, id string,
+	fields map[string]any,
+	bag models.PropBag,
+	at time.Time,
+) map[string]Element {
+	if elements == nil {
+		elements = map[string]Element{}
+	}
+
+	e := elements[id]
+	changed := false
+
+	for _, name := range models.SortedKeys(fields) {
+		e.Fields = s.write(e.Fields, &changed, replica, name, fields[name], false, at)
+	}
+
+	for _, section := range models.SortedKeys(bag) {
+		registers := e.Props[section]
+
+		for _, key := range models.SortedKeys(bag[section]) {
+			registers = s.write(registers, &changed, replica, key, bag[section][key], false, at)
+		}
+
+		if e.Props == nil {
+			e.Props = map[string]map[string]Register{}
+		}
+
+		e.Props[section] = registers
+	}
+
+	for _, section := range models.SortedKeys(e.Props) {
+		for _, key := range models.SortedKeys(e.Props[section]) {
+			if _, ok := bag[section][key]; !ok && !e.Props[section][key].Deleted {
+				e.Props[section] = s.write(e.Props[section], &changed, replica, key, nil, true, at)
+			}
+		}
+	}
+
+	if changed || !e.Exists() {
+		e.Dots = []Dot{s.next(replica)}
+	}
+
+	elements[id] = e
+
+	return elements
+}
+
+// write sets the register of the key unless it already holds the value, and reports the
+// change. The write is made to win over the current one even if the clock of the replica
+// is behind.
+func (s *State) write(
+	registers map[string]Register,
+	changed *bool,
+	replica, key string,
+	value any,
+	deleted bool,
+	at time.Time,
+) map[string]Register {
+	current, ok := registers[key]
+
+	if ok && current.Deleted == deleted && (deleted || models.SameValue(current.Value, value)) {
+		return registers
+	}
+
+	if ok && !at.After(current.Time) {
+		at = current.Time.Add(time.Nanosecond)
+	}
+
+	if registers == nil {
+		registers = map[string]Register{}
+	}
+
+	registers[key] = Register{Value: value, Deleted: deleted, Time: at, Dot: s.next(replica)}
+	*changed = true
+
+	return registers
+}
+
+// next returns the dot of the next operation of the replica and adds it to the context.
+func (s *State) next(replica string) Dot {
+	if s.Context == nil {
+		s.Context = VersionVector{}
+	}
+
+	s.Context[replica]++
+
+	return Dot{Replica: replica, Counter: s.Context[replica]}
+}
+
+// remove drops the add dots of the element, keeping its registers.
+func remove(elements map[string]Element, id string) map[string]Element {
+	if e, ok := elements[id]; ok {
+		e.Dots = nil
+		elements[id] = e
+	}
+
+	return elements
+}
+
+func stringField(e Element, name string) string {
+	value, _ := e.Fields[name].Value.(string)
+
+	return value
+}
+
+// props returns the properties of the element, or nil if it has none.
+func props(e Element) models.PropBag {
+	var bag models.PropBag
+
+	for section, registers := range e.Props {
+		for key, r := range registers {
+			if r.Deleted {
+				continue
+			}
+
+			if bag == nil {
+				bag = models.PropBag{}
+			}
+
+			if bag[section] == nil {
+				bag[section] = models.PropSection{}
+			}
+
+			bag[section][key] = r.Value
+		}
+	}
+
+	return bag
+}
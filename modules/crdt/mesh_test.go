@@ -0,0 +1,100 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_PutNode(t *testing.T) {
+	t.Parallel()
+
+	s := NewState("m1")
+	node := models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10}}}
+
+	s.PutNode("a", node, testTime)
+
+	require.Equal(t, VersionVector{"a": 4}, s.Context, "kind, code, load and add")
+	require.Equal(t, node, s.Mesh().Nodes["b1"])
+
+	s.PutNode("a", node, testTime)
+
+	require.Equal(t, VersionVector{"a": 4}, s.Context, "no change")
+
+	s.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1"}, testTime)
+
+	require.Equal(t, VersionVector{"a": 6}, s.Context, "load and add")
+	require.Equal(t, []Dot{{Replica: "a", Counter: 6}}, s.Nodes["b1"].Dots)
+	require.Nil(t, s.Mesh().Nodes["b1"].Props)
+	require.True(t, s.Nodes["b1"].Props["load"]["kw"].Deleted)
+	require.True(t, s.Nodes["b1"].Props["load"]["kw"].Time.After(testTime), "must win over the earlier write")
+}
+
+func TestState_RemoveNode(t *testing.T) {
+	t.Parallel()
+
+	s := NewState("m1")
+	s.PutNode("a", models.Node{ID: "b1", Kind: "bus"}, testTime)
+	s.RemoveNode("b1")
+	s.RemoveNode("b2")
+
+	require.Empty(t, s.Mesh().Nodes)
+	require.NotContains(t, s.Nodes, "b2")
+
+	s.PutNode("a", models.Node{ID: "b1", Kind: "bus"}, testTime)
+
+	require.Contains(t, s.Mesh().Nodes, "b1")
+	require.Len(t, s.Nodes["b1"].Dots, 1)
+}
+
+func TestState_Mesh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dangling-relation", func(t *testing.T) {
+		t.Parallel()
+
+		base := NewState("m1")
+		base.PutNode("a", models.Node{ID: "b1", Kind: "bus"}, testTime)
+		base.PutNode("a", models.Node{ID: "b2", Kind: "bus"}, testTime)
+
+		a, b := replicate(base), replicate(base)
+		a.RemoveNode("b2")
+		b.PutRelation("b", models.Relation{ID: "r1", Kind: "line", From: "b1", To: "b2"}, testTime)
+
+		merged := a.Merge(b)
+
+		require.NotContains(t, merged.Mesh().Relations, "r1")
+		require.True(t, merged.Relations["r1"].Exists(), "kept in the state")
+
+		merged.PutNode("a", models.Node{ID: "b2", Kind: "bus"}, testTime)
+
+		require.Contains(t, merged.Mesh().Relations, "r1")
+	})
+
+	t.Run("duplicate-code", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := NewState("m1"), NewState("m1")
+		a.PutNode("a", models.Node{ID: "b1", Kind: "bus", Code: "B1"}, testTime.Add(1))
+		b.PutNode("b", models.Node{ID: "b2", Kind: "bus", Code: "B1"}, testTime)
+
+		mesh := a.Merge(b).Mesh()
+
+		require.Equal(t, "", mesh.Nodes["b1"].Code)
+		require.Equal(t, "B1", mesh.Nodes["b2"].Code, "given the code first")
+		require.Equal(t, mesh, b.Merge(a).Mesh())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		mesh := NewState("m1").Mesh()
+
+		require.Equal(t, models.Mesh{
+			ModelID:   "m1",
+			Nodes:     map[string]models.Node{},
+			Relations: map[string]models.Relation{},
+		}, mesh)
+	})
+}
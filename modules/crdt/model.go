@@ -0,0 +1,120 @@
+package crdt
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Dot identifies an operation: the counter-th operation of the replica.
+type Dot struct {
+	Replica string
+	Counter uint64
+}
+
+// compareDots orders the dots by replica and counter.
+func compareDots(a, b Dot) int {
+	if c := cmp.Compare(a.Replica, b.Replica); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Counter, b.Counter)
+}
+
+// VersionVector maps the replicas to the counter of their latest operation seen.
+// The operations of a replica are seen in order, so the vector covers all earlier dots.
+type VersionVector map[string]uint64
+
+// Contains checks if the dot has been seen.
+func (v VersionVector) Contains(d Dot) bool {
+	return d.Counter <= v[d.Replica]
+}
+
+// Merge returns the vector covering the dots seen by either vector.
+func (v VersionVector) Merge(other VersionVector) VersionVector {
+	merged := maps.Clone(v)
+	if merged == nil {
+		merged = VersionVector{}
+	}
+
+	for replica, counter := range other {
+		merged[replica] = max(merged[replica], counter)
+	}
+
+	return merged
+}
+
+// Register is a last-writer-wins register. Of two writes, the one made at the later time
+// wins; writes made at the same time are ordered by their dots.
+type Register struct {
+	Value   any
+	Deleted bool // marks a removed property
+	Time    time.Time
+	Dot     Dot
+}
+
+// Wins checks if the write of the register wins over the other write.
+func (r Register) Wins(other Register) bool {
+	if c := r.Time.Compare(other.Time); c != 0 {
+		return c > 0
+	}
+
+	return compareDots(r.Dot, other.Dot) > 0
+}
+
+// Element represents the state of a node or relation.
+//
+// The element exists as long as any of its add dots is in effect. Adding an element
+// concurrently with its removal keeps it, as the removal does not know the new dot. The
+// fields hold the kind and code of nodes and the kind and endpoints of relations; the
+// properties are kept by section and key.
+type Element struct {
+	Dots   []Dot // add dots in effect, ordered
+	Fields map[string]Register
+	Props  map[string]map[string]Register
+}
+
+// Exists checks if the element exists.
+func (e Element) Exists() bool {
+	return len(e.Dots) > 0
+}
+
+// Element field names.
+const (
+	FieldKind = "kind"
+	FieldCode = "code"
+	FieldFrom = "from"
+	FieldTo   = "to"
+)
+
+// State represents the replicated state of a mesh, or a delta of it.
+//
+// The context holds all dots the state has seen, including the dots of removed elements,
+// which is how removals are told apart from adds not seen yet. A delta exported since the
+// context of another replica carries the full context and the add dots of all existing
+// elements, but only the registers the other replica has not seen.
+type State struct {
+	ModelID   string
+	Context   VersionVector
+	Nodes     map[string]Element
+	Relations map[string]Element
+	Version   int64 // version of the stored state, used to detect concurrent saves
+}
+
+// NewState returns the empty state of the mesh of the model.
+func NewState(modelID string) State {
+	return State{
+		ModelID:   modelID,
+		Context:   VersionVector{},
+		Nodes:     map[string]Element{},
+		Relations: map[string]Element{},
+	}
+}
+
+// sortedDots returns the dots ordered and without duplicates.
+func sortedDots(dots []Dot) []Dot {
+	slices.SortFunc(dots, compareDots)
+
+	return slices.Compact(dots)
+}
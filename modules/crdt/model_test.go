@@ -0,0 +1,66 @@
+package crdt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestVersionVector_Contains(t *testing.T) {
+	t.Parallel()
+
+	v := VersionVector{"a": 2}
+
+	require.True(t, v.Contains(Dot{Replica: "a", Counter: 1}))
+	require.True(t, v.Contains(Dot{Replica: "a", Counter: 2}))
+	require.False(t, v.Contains(Dot{Replica: "a", Counter: 3}))
+	require.False(t, v.Contains(Dot{Replica: "b", Counter: 1}))
+}
+
+func TestVersionVector_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := VersionVector{"a": 2, "b": 1}
+	b := VersionVector{"b": 3, "c": 1}
+
+	require.Equal(t, VersionVector{"a": 2, "b": 3, "c": 1}, a.Merge(b))
+	require.Equal(t, VersionVector{"a": 2, "b": 1}, a, "must not change the vector")
+	require.Equal(t, VersionVector{}, VersionVector(nil).Merge(nil))
+}
+
+func TestRegister_Wins(t *testing.T) {
+	t.Parallel()
+
+	early := Register{Time: testTime, Dot: Dot{Replica: "b", Counter: 5}}
+	late := Register{Time: testTime.Add(time.Second), Dot: Dot{Replica: "a", Counter: 1}}
+	tie := Register{Time: testTime, Dot: Dot{Replica: "c", Counter: 1}}
+
+	require.True(t, late.Wins(early))
+	require.False(t, early.Wins(late))
+	require.True(t, tie.Wins(early))
+	require.False(t, early.Wins(tie))
+	require.False(t, early.Wins(early))
+}
+
+func TestElement_Exists(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, Element{}.Exists())
+	require.False(t, Element{Fields: map[string]Register{FieldKind: {Value: "bus"}}}.Exists())
+	require.True(t, Element{Dots: []Dot{{Replica: "a", Counter: 1}}}.Exists())
+}
+
+func TestNewState(t *testing.T) {
+	t.Parallel()
+
+	s := NewState("m1")
+
+	require.Equal(t, "m1", s.ModelID)
+	require.NotNil(t, s.Context)
+	require.NotNil(t, s.Nodes)
+	require.NotNil(t, s.Relations)
+	require.Zero(t, s.Version)
+}
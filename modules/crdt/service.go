@@ -0,0 +1,17 @@
+package crdt
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// SyncService defines the service synchronizing the mesh of a model with its replicas.
+type SyncService interface {
+	// ExportDelta returns the part of the state of the mesh a replica that has seen the
+	// dots of the version vector is missing.
+	ExportDelta(ctx context.Context, actor access.Actor, modelID string, since VersionVector) (State, error)
+	// ImportDelta merges the delta of a replica into the mesh and returns the part of the
+	// merged state the replica is missing.
+	ImportDelta(ctx context.Context, actor access.Actor, modelID string, delta State) (State, error)
+}
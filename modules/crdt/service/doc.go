@@ -0,0 +1,2 @@
+// Package service implements the sync service.
+package service
@@ -0,0 +1,20 @@
+package service
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+func nodeData(node models.Node) models.NodeData {
+	return models.NodeData{
+		Kind:  node.Kind,
+		Code:  node.Code,
+		Props: node.Props,
+	}
+}
+
+func relationData(relation models.Relation) models.RelationData {
+	return models.RelationData{
+		Kind:  relation.Kind,
+		From:  relation.From,
+		To:    relation.To,
+		Props: relation.Props,
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nodeData(t *testing.T) {
+	t.Parallel()
+
+	node := testMesh.Nodes["b1"]
+
+	require.Equal(t, models.NodeData{Kind: node.Kind, Code: node.Code, Props: node.Props}, nodeData(node))
+}
+
+func Test_relationData(t *testing.T) {
+	t.Parallel()
+
+	relation := testMesh.Relations["r1"]
+
+	require.Equal(t,
+		models.RelationData{Kind: relation.Kind, From: relation.From, To: relation.To},
+		relationData(relation),
+	)
+}
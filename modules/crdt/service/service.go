@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+const (
+	// defaultReplicaID is the default replica ID of the edits made on the server.
+	defaultReplicaID = "server"
+
+	// maxSaveAttempts is the number of times a state is saved before giving up on
+	// concurrent saves.
+	maxSaveAttempts = 5
+)
+
+// store defines the external state store.
+// GetState must return a not found error if the model has no state. SaveState must only
+// save the state if the stored state is still of the version of the state, and return the
+// saved state with the next version; otherwise it must return a conflict error. States of
+// version 0 are new.
+type store interface {
+	GetState(ctx context.Context, modelID string) (crdt.State, error)
+	SaveState(ctx context.Context, state crdt.State) (crdt.State, error)
+	DeleteState(ctx context.Context, modelID string) error
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+}
+
+// meshEditor defines the external editor of meshes.
+// It is implemented by the mesh service.
+type meshEditor interface {
+	GetMesh(ctx context.Context, modelID string) (models.Mesh, error)
+	RestoreNode(ctx context.Context, actor access.Actor, modelID string, node models.Node) (models.Node, error)
+	UpdateNode(ctx context.Context, actor access.Actor, modelID, nodeID string, data models.NodeData) (models.Node, error)
+	DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error
+	RestoreRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID string,
+		relation models.Relation,
+	) (models.Relation, error)
+	UpdateRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID, relationID string,
+		data models.RelationData,
+	) (models.Relation, error)
+	DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error
+}
+
+// SyncService implements the sync service.
+//
+// It implements the crdt.SyncService interface.
+//
+// The service keeps the replicated state of the meshes synchronized with replicas. The
+// state of a mesh is created from the mesh on its first sync, and the edits made to the
+// mesh on the server, received as a mesh listener of the mesh service, are recorded in it
+// as operations of the server replica. Creating or deleting the mesh drops the state.
+//
+// Imported deltas are merged into the state, and the mesh the merged state represents is
+// applied to the stored mesh through the mesh editor, so the edits are subject to the same
+// checks as any other edit. Applying is not atomic: if the mesh editor refuses an edit,
+// the merged state is kept and the stored mesh catches up with it on the next import.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type SyncService struct {
+	store   store
+	meshes  meshEditor
+	authz   authorizer
+	replica string
+	now     func() time.Time
+}
+
+// Ensure SyncService implements the crdt.SyncService interface.
+var _ crdt.SyncService = (*SyncService)(nil)
+
+// NewSyncService creates a new sync service.
+func NewSyncService(store store, meshes meshEditor, authz authorizer, opts ...Option) *SyncService {
+	svc := &SyncService{
+		store:   store,
+		meshes:  meshes,
+		authz:   authz,
+		replica: defaultReplicaID,
+		now:     time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// ExportDelta implements the crdt.SyncService interface.
+// The actor needs read access to the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) ExportDelta(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	since crdt.VersionVector,
+) (crdt.State, error) {
+	if err := validateModelID(modelID); err != nil {
+		return crdt.State{}, err
+	}
+
+	if err := validateVersionVector(since); err != nil {
+		return crdt.State{}, err
+	}
+
+	if err := s.authorize(ctx, actor, modelID, access.PermissionRead); err != nil {
+		return crdt.State{}, err
+	}
+
+	state, err := s.update(ctx, modelID, nil)
+	if err != nil {
+		return crdt.State{}, err
+	}
+
+	return state.Delta(since), nil
+}
+
+// ImportDelta implements the crdt.SyncService interface.
+// The actor needs write access to the model; the edits are made to the mesh on its behalf.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) ImportDelta(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	delta crdt.State,
+) (crdt.State, error) {
+	if err := validateModelID(modelID); err != nil {
+		return crdt.State{}, err
+	}
+
+	if err := validateDelta(modelID, delta); err != nil {
+		return crdt.State{}, err
+	}
+
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return crdt.State{}, err
+	}
+
+	state, err := s.update(ctx, modelID, func(state crdt.State) crdt.State {
+		return state.Merge(delta)
+	})
+	if err != nil {
+		return crdt.State{}, err
+	}
+
+	if err := s.apply(ctx, actor, modelID, state.Mesh()); err != nil {
+		return crdt.State{}, err
+	}
+
+	return state.Delta(delta.Context), nil
+}
+
+// HandleMeshEvent records the edits of the event in the state of its model as operations
+// of the server replica. If the mesh is created or deleted, the state is dropped. The
+// edits made by the service itself are not recorded, and neither are edits of models
+// without a state, as the state is created from the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) HandleMeshEvent(ctx context.Context, event models.MeshEvent) error {
+	if isImport(ctx) {
+		return nil
+	}
+
+	modelID := event.Updates.ModelID
+	if modelID == "" {
+		modelID = event.Deletes.ModelID
+	}
+
+	//nolint:exhaustive // only mesh and element edits are of interest
+	switch event.Type {
+	case models.MeshCreated, models.MeshDeleted:
+		return s.store.DeleteState(ctx, modelID)
+	case models.MeshUpdated, models.MeshContentsCreated, models.MeshContentsUpdated, models.MeshContentsDeleted:
+	default:
+		return nil
+	}
+
+	if _, err := s.store.GetState(ctx, modelID); err != nil {
+		if errorz.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	updates, deletes, complete := event.Updates, models.Mesh{}, false
+
+	if event.Type == models.MeshContentsDeleted {
+		updates, deletes = models.Mesh{}, event.Deletes
+	}
+
+	// mesh updates may merge into the mesh, so the whole mesh is recorded
+	if event.Type == models.MeshUpdated {
+		mesh, err := s.meshes.GetMesh(ctx, modelID)
+		if err != nil {
+			return err
+		}
+
+		updates, complete = mesh, true
+	}
+
+	_, err := s.update(ctx, modelID, func(state crdt.State) crdt.State {
+		return s.record(state, updates, deletes, complete)
+	})
+
+	return err
+}
+
+// DeleteModelResources removes the state of the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteState(ctx, modelID)
+}
+
+// update changes the state of the model and saves it, retrying if the state is saved
+// concurrently. A missing state is created from the mesh first. A nil change only makes
+// sure the state exists.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) update(
+	ctx context.Context,
+	modelID string,
+	change func(state crdt.State) crdt.State,
+) (crdt.State, error) {
+	for range maxSaveAttempts {
+		state, err := s.store.GetState(ctx, modelID)
+
+		switch {
+		case errorz.IsNotFoundError(err):
+			mesh, err := s.meshes.GetMesh(ctx, modelID)
+			if err != nil {
+				return crdt.State{}, err
+			}
+
+			state = s.record(crdt.NewState(modelID), mesh, models.Mesh{}, true)
+		case err != nil:
+			return crdt.State{}, err
+		case change == nil:
+			return state, nil
+		}
+
+		if change != nil {
+			state = change(state)
+		}
+
+		saved, err := s.store.SaveState(ctx, state)
+		if err == nil {
+			return saved, nil
+		}
+
+		if !errorz.IsConflictError(err) {
+			return crdt.State{}, err
+		}
+	}
+
+	return crdt.State{}, errorz.NewConflictError("state of model %s is saved concurrently", modelID)
+}
+
+// record records the server edits in the state: the elements of the updates are written
+// and the elements of the deletes are removed. If complete, the updates hold the whole
+// mesh, and the elements missing from it are removed as well.
+func (s *SyncService) record(state crdt.State, updates, deletes models.Mesh, complete bool) crdt.State {
+	now := s.now()
+
+	for _, id := range models.SortedKeys(updates.Nodes) {
+		state.PutNode(s.replica, updates.Nodes[id], now)
+	}
+
+	for _, id := range models.SortedKeys(updates.Relations) {
+		state.PutRelation(s.replica, updates.Relations[id], now)
+	}
+
+	for _, id := range models.SortedKeys(deletes.Nodes) {
+		state.RemoveNode(id)
+	}
+
+	for _, id := range models.SortedKeys(deletes.Relations) {
+		state.RemoveRelation(id)
+	}
+
+	if complete {
+		for id, e := range state.Nodes {
+			if _, ok := updates.Nodes[id]; !ok && e.Exists() {
+				state.RemoveNode(id)
+			}
+		}
+
+		for id, e := range state.Relations {
+			if _, ok := updates.Relations[id]; !ok && e.Exists() {
+				state.RemoveRelation(id)
+			}
+		}
+	}
+
+	return state
+}
+
+// apply turns the stored mesh into the target mesh through the mesh editor.
+//
+// Nodes are restored and updated before the relations referring to them, and deleted after
+// the relations referring to them. Nodes losing their code are updated first, so their
+// codes can be taken by other nodes.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) apply(ctx context.Context, actor access.Actor, modelID string, target models.Mesh) error {
+	current, err := s.meshes.GetMesh(ctx, modelID)
+	if err != nil {
+		return err
+	}
+
+	ctx = withImport(ctx)
+
+	for _, id := range models.SortedKeys(target.Nodes) {
+		node, ok := current.Nodes[id]
+		if !ok || node.Code == "" || node.Code == target.Nodes[id].Code {
+			continue
+		}
+
+		node = target.Nodes[id]
+		node.Code = ""
+
+		if _, err := s.meshes.UpdateNode(ctx, actor, modelID, id, nodeData(node)); err != nil {
+			return err
+		}
+
+		current.Nodes[id] = node
+	}
+
+	for _, id := range models.SortedKeys(target.Nodes) {
+		var err error
+
+		if node, ok := current.Nodes[id]; !ok {
+			_, err = s.meshes.RestoreNode(ctx, actor, modelID, target.Nodes[id])
+		} else if !models.SameElement(node, target.Nodes[id]) {
+			_, err = s.meshes.UpdateNode(ctx, actor, modelID, id, nodeData(target.Nodes[id]))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range models.SortedKeys(current.Relations) {
+		if _, ok := target.Relations[id]; !ok {
+			if err := s.meshes.DeleteRelation(ctx, actor, modelID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range models.SortedKeys(target.Relations) {
+		var err error
+
+		if relation, ok := current.Relations[id]; !ok {
+			_, err = s.meshes.RestoreRelation(ctx, actor, modelID, target.Relations[id])
+		} else if !models.SameElement(relation, target.Relations[id]) {
+			_, err = s.meshes.UpdateRelation(ctx, actor, modelID, id, relationData(target.Relations[id]))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range models.SortedKeys(current.Nodes) {
+		if _, ok := target.Nodes[id]; !ok {
+			if err := s.meshes.DeleteNode(ctx, actor, modelID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *SyncService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// importKey is the context key marking the edits made by the service.
+type importKey struct{}
+
+// withImport returns a context marking the edits made with it as imported.
+func withImport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, importKey{}, true)
+}
+
+// isImport checks if the edits made with the context are made by the service.
+func isImport(ctx context.Context) bool {
+	imported, _ := ctx.Value(importKey{}).(bool)
+
+	return imported
+}
@@ -0,0 +1,12 @@
+package service
+
+// Option defines the option for the service.
+type Option func(service *SyncService)
+
+// WithReplicaID sets the replica ID of the edits made to the mesh on the server.
+// It must differ from the replica IDs of the clients.
+func WithReplicaID(id string) Option {
+	return func(s *SyncService) {
+		s.replica = id
+	}
+}
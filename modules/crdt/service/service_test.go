@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncService_ExportDelta(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor       access.Actor
+		modelID     string
+		since       crdt.VersionVector
+		storeError  bool
+		wantErr     error
+		wantFields  int // number of field registers of node b1 in the delta
+		wantVersion int64
+	}{
+		"invalid-model-id": {
+			actor:   viewerActor,
+			modelID: "",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-since": {
+			actor:   viewerActor,
+			modelID: validModelID,
+			since:   crdt.VersionVector{"": 1},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-binding": {
+			actor:   otherActor,
+			modelID: validModelID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      viewerActor,
+			modelID:    validModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"mesh-not-found": {
+			actor:   adminActor,
+			modelID: "model2",
+			wantErr: errorz.NotFoundError{},
+		},
+		"success-full": {
+			actor:       viewerActor,
+			modelID:     validModelID,
+			wantFields:  2,
+			wantVersion: 1,
+		},
+		"success-since": {
+			actor:       viewerActor,
+			modelID:     validModelID,
+			since:       testState().Context,
+			wantFields:  0,
+			wantVersion: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestStore(test.storeError)
+			svc := newTestService(ts, newTestMeshEditor(false), testBindingProvider{})
+
+			delta, err := svc.ExportDelta(context.Background(), test.actor, test.modelID, test.since)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, delta)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testState().Context, delta.Context)
+			require.Len(t, delta.Nodes["b1"].Fields, test.wantFields)
+
+			// a replica that has seen the dots catches up with the delta
+			replica := crdt.NewState(validModelID)
+			if test.since != nil {
+				replica = replica.Merge(testState())
+			}
+
+			require.Equal(t, testMesh, replica.Merge(delta).Mesh())
+			require.Equal(t, test.wantVersion, ts.states[validModelID].Version)
+		})
+	}
+}
+
+func TestSyncService_ImportDelta(t *testing.T) {
+	t.Parallel()
+
+	// client returns the state of a client that has synced the test mesh
+	client := func() crdt.State {
+		return crdt.NewState(validModelID).Merge(testState())
+	}
+
+	tests := map[string]struct {
+		actor       access.Actor
+		modelID     string
+		edit        func(state *crdt.State)
+		serverEdit  func(te *testMeshEditor, svc *SyncService)
+		conflicts   int
+		editorError bool
+		wantErr     error
+		wantMesh    models.Mesh
+		wantCalls   []string
+	}{
+		"invalid-model-id": {
+			actor:   editorActor,
+			modelID: "",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-delta": {
+			actor:   editorActor,
+			modelID: validModelID,
+			edit: func(state *crdt.State) {
+				state.Nodes["b3"] = crdt.Element{Dots: []crdt.Dot{{Replica: "client", Counter: 1}}}
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-permission": {
+			actor:   viewerActor,
+			modelID: validModelID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"too-many-conflicts": {
+			actor:     editorActor,
+			modelID:   validModelID,
+			conflicts: maxSaveAttempts,
+			wantErr:   errorz.ConflictError{},
+		},
+		"editor-error": {
+			actor:       editorActor,
+			modelID:     validModelID,
+			editorError: true,
+			wantErr:     errorz.StoreError{},
+		},
+		"success-no-changes": {
+			actor:    editorActor,
+			modelID:  validModelID,
+			wantMesh: testMesh,
+		},
+		"success-edits": {
+			actor:     editorActor,
+			modelID:   validModelID,
+			conflicts: 2,
+			edit: func(state *crdt.State) {
+				state.PutNode("client", models.Node{ID: "b1", Kind: "bus", Code: "B1"}, testTime)
+				state.PutNode("client", models.Node{ID: "b3", Kind: "bus", Code: "B3"}, testTime)
+				state.PutRelation("client", models.Relation{ID: "r2", Kind: "line", From: "b2", To: "b3"}, testTime)
+				state.RemoveRelation("r1")
+			},
+			wantMesh: models.Mesh{
+				ModelID: validModelID,
+				Nodes: map[string]models.Node{
+					"b1": {ID: "b1", Kind: "bus", Code: "B1"},
+					"b2": testMesh.Nodes["b2"],
+					"b3": {ID: "b3", Kind: "bus", Code: "B3"},
+				},
+				Relations: map[string]models.Relation{
+					"r2": {ID: "r2", Kind: "line", From: "b2", To: "b3"},
+				},
+			},
+			wantCalls: []string{"update-node b1", "restore-node b3", "delete-relation r1", "restore-relation r2"},
+		},
+		"success-concurrent-delete": {
+			actor:   editorActor,
+			modelID: validModelID,
+			edit: func(state *crdt.State) {
+				state.PutRelation("client", models.Relation{ID: "r2", Kind: "line", From: "b2", To: "b1"}, testTime)
+			},
+			serverEdit: func(te *testMeshEditor, svc *SyncService) {
+				_ = te.DeleteRelation(context.Background(), otherActor, validModelID, "r1")
+				_ = te.DeleteNode(context.Background(), otherActor, validModelID, "b2")
+			},
+			wantMesh: models.Mesh{
+				ModelID:   validModelID,
+				Nodes:     map[string]models.Node{"b1": testMesh.Nodes["b1"]},
+				Relations: map[string]models.Relation{},
+			},
+		},
+		"success-swapped-codes": {
+			actor:   editorActor,
+			modelID: validModelID,
+			edit: func(state *crdt.State) {
+				state.PutNode("client", models.Node{ID: "b1", Kind: "bus", Code: "B2"}, testTime)
+				state.PutNode("client", models.Node{ID: "b2", Kind: "bus", Code: "B1"}, testTime)
+			},
+			wantMesh: models.Mesh{
+				ModelID: validModelID,
+				Nodes: map[string]models.Node{
+					"b1": {ID: "b1", Kind: "bus", Code: "B2"},
+					"b2": {ID: "b2", Kind: "bus", Code: "B1"},
+				},
+				Relations: testMesh.Relations,
+			},
+			wantCalls: []string{"update-node b1", "update-node b2", "update-node b1", "update-node b2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestStore(false, testState())
+			te := newTestMeshEditor(false)
+			svc := newTestService(ts, te, testBindingProvider{})
+
+			if test.serverEdit != nil {
+				test.serverEdit(te, svc)
+				te.calls = nil
+			}
+
+			state := client()
+			if test.edit != nil {
+				test.edit(&state)
+			}
+
+			ts.conflicts = test.conflicts
+
+			if test.editorError {
+				te.forcedError = errorz.NewStoreError("forced-error")
+			}
+
+			delta, err := svc.ImportDelta(context.Background(), test.actor, test.modelID, state.Delta(testState().Context))
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, delta)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantMesh, te.mesh)
+			require.Equal(t, test.wantCalls, te.calls)
+			require.Equal(t, te.mesh, state.Merge(delta).Mesh(), "client must catch up")
+			require.Equal(t, te.mesh, ts.states[validModelID].Mesh())
+		})
+	}
+}
+
+func TestSyncService_HandleMeshEvent(t *testing.T) {
+	t.Parallel()
+
+	changedNode := models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 12.5}}}
+
+	t.Run("edits", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestStore(false, testState())
+		te := newTestMeshEditor(false)
+		svc := newTestService(ts, te, testBindingProvider{})
+
+		_, err := te.UpdateNode(context.Background(), otherActor, validModelID, "b1", nodeData(changedNode))
+		require.NoError(t, err)
+
+		require.NoError(t, te.DeleteRelation(context.Background(), otherActor, validModelID, "r1"))
+
+		delta, err := svc.ExportDelta(context.Background(), adminActor, validModelID, testState().Context)
+		require.NoError(t, err)
+
+		require.Len(t, delta.Nodes["b1"].Props["load"], 1)
+		require.Empty(t, delta.Nodes["b2"].Fields)
+		require.NotContains(t, delta.Relations, "r1")
+		require.Equal(t, te.mesh, testState().Merge(delta).Mesh())
+	})
+
+	t.Run("mesh-updated", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestStore(false, testState())
+		te := newTestMeshEditor(false)
+		svc := newTestService(ts, te, testBindingProvider{})
+
+		te.mesh.Nodes["b1"] = changedNode
+		delete(te.mesh.Relations, "r1")
+		delete(te.mesh.Nodes, "b2")
+
+		require.NoError(t, svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshUpdated},
+			Updates:     models.Mesh{ModelID: validModelID},
+		}))
+
+		require.Equal(t, te.mesh, ts.states[validModelID].Mesh())
+	})
+
+	t.Run("no-state", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestStore(false)
+		te := newTestMeshEditor(false)
+		_ = newTestService(ts, te, testBindingProvider{})
+
+		_, err := te.UpdateNode(context.Background(), otherActor, validModelID, "b1", nodeData(changedNode))
+		require.NoError(t, err)
+
+		require.Empty(t, ts.states)
+	})
+
+	t.Run("imported", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestStore(false, testState())
+		te := newTestMeshEditor(false)
+		_ = newTestService(ts, te, testBindingProvider{})
+
+		_, err := te.UpdateNode(withImport(context.Background()), otherActor, validModelID, "b1", nodeData(changedNode))
+		require.NoError(t, err)
+
+		require.Equal(t, testState(), ts.states[validModelID])
+	})
+
+	for _, eventType := range []models.EventType{models.MeshCreated, models.MeshDeleted} {
+		t.Run(string(eventType), func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestStore(false, testState())
+			svc := newTestService(ts, newTestMeshEditor(false), testBindingProvider{})
+
+			require.NoError(t, svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+				EventHeader: models.EventHeader{Type: eventType},
+				Updates:     models.Mesh{ModelID: validModelID},
+			}))
+
+			require.Empty(t, ts.states)
+		})
+	}
+
+	t.Run("store-error", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestStore(true)
+		te := newTestMeshEditor(false)
+		_ = newTestService(ts, te, testBindingProvider{})
+
+		_, err := te.UpdateNode(context.Background(), otherActor, validModelID, "b1", nodeData(changedNode))
+		require.IsType(t, errorz.StoreError{}, err)
+	})
+}
+
+func TestSyncService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestStore(false, testState())
+	svc := newTestService(ts, newTestMeshEditor(false), testBindingProvider{})
+
+	require.IsType(t, errorz.ValidationError{}, svc.DeleteModelResources(context.Background(), adminActor, ""))
+	require.NoError(t, svc.DeleteModelResources(context.Background(), adminActor, validModelID))
+	require.Empty(t, ts.states)
+}
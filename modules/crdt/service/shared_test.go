@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+)
+
+var (
+	adminActor   = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	editorActor  = access.Actor{UserID: "editor", Role: access.RoleEditor}
+	viewerActor  = access.Actor{UserID: "viewer", Role: access.RoleEditor}
+	otherActor   = access.Actor{UserID: "other", Role: access.RoleEditor}
+	validModelID = "model1"
+	testTime     = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// testMesh holds the buses b1 and b2 connected by the line r1.
+	testMesh = models.Mesh{
+		ModelID: validModelID,
+		Nodes: map[string]models.Node{
+			"b1": {ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10}}},
+			"b2": {ID: "b2", Kind: "bus", Code: "B2"},
+		},
+		Relations: map[string]models.Relation{
+			"r1": {ID: "r1", Kind: "line", From: "b1", To: "b2"},
+		},
+	}
+)
+
+// testBindingProvider grants the editor the editor role and the viewer the guest role on
+// the valid model.
+type testBindingProvider struct {
+	forcedError error
+}
+
+func (p testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	if p.forcedError != nil {
+		return permissions.RoleBinding{}, p.forcedError
+	}
+
+	roles := map[string]access.Role{
+		editorActor.UserID: access.RoleEditor,
+		viewerActor.UserID: access.RoleGuest,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testStore keeps the states in memory.
+type testStore struct {
+	forcedError error
+	conflicts   int // number of saves failing with a conflict
+	states      map[string]crdt.State
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...crdt.State) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		states:      map[string]crdt.State{},
+	}
+
+	for _, state := range initial {
+		s.states[state.ModelID] = state
+	}
+
+	return s
+}
+
+func (s *testStore) GetState(_ context.Context, modelID string) (crdt.State, error) {
+	if s.forcedError != nil {
+		return crdt.State{}, s.forcedError
+	}
+
+	state, ok := s.states[modelID]
+	if !ok {
+		return crdt.State{}, errorz.NewNotFoundError("state of model %s not found", modelID)
+	}
+
+	// the service mutates the states it gets, so a copy is returned as read back from a store
+	stored := crdt.NewState(modelID).Merge(state)
+	stored.Version = state.Version
+
+	return stored, nil
+}
+
+func (s *testStore) SaveState(_ context.Context, state crdt.State) (crdt.State, error) {
+	if s.forcedError != nil {
+		return crdt.State{}, s.forcedError
+	}
+
+	if s.conflicts > 0 || s.states[state.ModelID].Version != state.Version {
+		s.conflicts--
+
+		return crdt.State{}, errorz.NewConflictError("state of model %s changed", state.ModelID)
+	}
+
+	state.Version++
+	s.states[state.ModelID] = state
+
+	return state, nil
+}
+
+func (s *testStore) DeleteState(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	delete(s.states, modelID)
+
+	return nil
+}
+
+// testMeshEditor keeps a copy of the test mesh in memory. Like the mesh service, it keeps
+// the node codes unique and fires the mesh contents events of its edits to the listener,
+// if set.
+type testMeshEditor struct {
+	forcedError error
+	mesh        models.Mesh
+	listener    *SyncService
+	calls       []string
+}
+
+// Ensure that the testMeshEditor implements the meshEditor interface.
+var _ meshEditor = (*testMeshEditor)(nil)
+
+func newTestMeshEditor(forcedError bool) *testMeshEditor {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testMeshEditor{
+		forcedError: err,
+		mesh: models.Mesh{
+			ModelID:   validModelID,
+			Nodes:     maps.Clone(testMesh.Nodes),
+			Relations: maps.Clone(testMesh.Relations),
+		},
+	}
+}
+
+func (e *testMeshEditor) GetMesh(_ context.Context, modelID string) (models.Mesh, error) {
+	if e.forcedError != nil {
+		return models.Mesh{}, e.forcedError
+	}
+
+	if modelID != validModelID {
+		return models.Mesh{}, errorz.NewNotFoundError("mesh %s not found", modelID)
+	}
+
+	return models.Mesh{
+		ModelID:   modelID,
+		Nodes:     maps.Clone(e.mesh.Nodes),
+		Relations: maps.Clone(e.mesh.Relations),
+	}, nil
+}
+
+func (e *testMeshEditor) RestoreNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	node models.Node,
+) (models.Node, error) {
+	if err := e.edit("restore-node "+node.ID, node); err != nil {
+		return models.Node{}, err
+	}
+
+	e.mesh.Nodes[node.ID] = node
+
+	return node, e.fire(ctx, actor, models.MeshContentsCreated, testNodes(modelID, node))
+}
+
+func (e *testMeshEditor) UpdateNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, nodeID string,
+	data models.NodeData,
+) (models.Node, error) {
+	node := models.Node{ID: nodeID, Kind: data.Kind, Code: data.Code, Props: data.Props}
+
+	if err := e.edit("update-node "+nodeID, node); err != nil {
+		return models.Node{}, err
+	}
+
+	e.mesh.Nodes[nodeID] = node
+
+	return node, e.fire(ctx, actor, models.MeshContentsUpdated, testNodes(modelID, node))
+}
+
+func (e *testMeshEditor) DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error {
+	if err := e.edit("delete-node "+nodeID, models.Node{}); err != nil {
+		return err
+	}
+
+	node := e.mesh.Nodes[nodeID]
+	delete(e.mesh.Nodes, nodeID)
+
+	return e.fire(ctx, actor, models.MeshContentsDeleted, testNodes(modelID, node))
+}
+
+func (e *testMeshEditor) RestoreRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	relation models.Relation,
+) (models.Relation, error) {
+	if err := e.edit("restore-relation "+relation.ID, models.Node{}); err != nil {
+		return models.Relation{}, err
+	}
+
+	e.mesh.Relations[relation.ID] = relation
+
+	return relation, e.fire(ctx, actor, models.MeshContentsCreated, testRelations(modelID, relation))
+}
+
+func (e *testMeshEditor) UpdateRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, relationID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if err := e.edit("update-relation "+relationID, models.Node{}); err != nil {
+		return models.Relation{}, err
+	}
+
+	relation := models.Relation{ID: relationID, Kind: data.Kind, From: data.From, To: data.To, Props: data.Props}
+	e.mesh.Relations[relationID] = relation
+
+	return relation, e.fire(ctx, actor, models.MeshContentsUpdated, testRelations(modelID, relation))
+}
+
+func (e *testMeshEditor) DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error {
+	if err := e.edit("delete-relation "+relationID, models.Node{}); err != nil {
+		return err
+	}
+
+	relation := e.mesh.Relations[relationID]
+	delete(e.mesh.Relations, relationID)
+
+	return e.fire(ctx, actor, models.MeshContentsDeleted, testRelations(modelID, relation))
+}
+
+// edit records the call, or fails with the forced error. The code of the written node must
+// not be used by another node.
+func (e *testMeshEditor) edit(call string, node models.Node) error {
+	if e.forcedError != nil {
+		return e.forcedError
+	}
+
+	for _, other := range e.mesh.Nodes {
+		if node.Code != "" && other.Code == node.Code && other.ID != node.ID {
+			return errorz.NewConflictError("node code %s is already used by node %s", node.Code, other.ID)
+		}
+	}
+
+	e.calls = append(e.calls, call)
+
+	return nil
+}
+
+// fire passes the event of an edit to the listener.
+func (e *testMeshEditor) fire(
+	ctx context.Context,
+	actor access.Actor,
+	eventType models.EventType,
+	mesh models.Mesh,
+) error {
+	if e.listener == nil {
+		return nil
+	}
+
+	event := models.MeshEvent{
+		EventHeader: models.EventHeader{Type: eventType, Actor: actor},
+	}
+
+	if eventType == models.MeshContentsDeleted {
+		event.Deletes = mesh
+	} else {
+		event.Updates = mesh
+	}
+
+	return e.listener.HandleMeshEvent(ctx, event)
+}
+
+// testNodes returns a mesh of the model holding the nodes.
+func testNodes(modelID string, nodes ...models.Node) models.Mesh {
+	m := models.Mesh{ModelID: modelID, Nodes: map[string]models.Node{}}
+
+	for _, n := range nodes {
+		m.Nodes[n.ID] = n
+	}
+
+	return m
+}
+
+// testRelations returns a mesh of the model holding the relations.
+func testRelations(modelID string, relations ...models.Relation) models.Mesh {
+	m := models.Mesh{ModelID: modelID, Relations: map[string]models.Relation{}}
+
+	for _, r := range relations {
+		m.Relations[r.ID] = r
+	}
+
+	return m
+}
+
+// testState returns the state of the test mesh as bootstrapped by the service.
+func testState() crdt.State {
+	state := crdt.NewState(validModelID)
+
+	for _, id := range models.SortedKeys(testMesh.Nodes) {
+		state.PutNode(defaultReplicaID, testMesh.Nodes[id], testTime)
+	}
+
+	for _, id := range models.SortedKeys(testMesh.Relations) {
+		state.PutRelation(defaultReplicaID, testMesh.Relations[id], testTime)
+	}
+
+	state.Version = 1
+
+	return state
+}
+
+// newTestService returns a service running at the test time, registered as the listener of
+// the mesh editor.
+func newTestService(ts *testStore, te *testMeshEditor, bindings testBindingProvider, opts ...Option) *SyncService {
+	svc := NewSyncService(ts, te, authz.NewAuthorizer(bindings), opts...)
+	svc.now = func() time.Time { return testTime }
+	te.listener = svc
+
+	return svc
+}
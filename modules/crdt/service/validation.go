@@ -0,0 +1,74 @@
+package service
+
+import (
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+)
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateVersionVector(v crdt.VersionVector) error {
+	for replica := range v {
+		if err := requireString(replica, "replica id"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateDelta(modelID string, delta crdt.State) error {
+	if delta.ModelID != "" && delta.ModelID != modelID {
+		return errorz.NewValidationError("delta of model %s cannot be imported into model %s", delta.ModelID, modelID)
+	}
+
+	if err := validateVersionVector(delta.Context); err != nil {
+		return err
+	}
+
+	if err := validateElements("node", delta.Nodes, delta.Context); err != nil {
+		return err
+	}
+
+	return validateElements("relation", delta.Relations, delta.Context)
+}
+
+// validateElements checks that the elements have IDs, and that their dots are covered by
+// the context of the delta.
+func validateElements(element string, elements map[string]crdt.Element, context crdt.VersionVector) error {
+	for id, e := range elements {
+		if err := requireString(id, element+" id"); err != nil {
+			return err
+		}
+
+		dots := append([]crdt.Dot(nil), e.Dots...)
+
+		for _, r := range e.Fields {
+			dots = append(dots, r.Dot)
+		}
+
+		for _, registers := range e.Props {
+			for _, r := range registers {
+				dots = append(dots, r.Dot)
+			}
+		}
+
+		for _, d := range dots {
+			if d.Replica == "" || d.Counter == 0 || !context.Contains(d) {
+				return errorz.NewValidationError("%s %s has an invalid dot %s:%d", element, id, d.Replica, d.Counter)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateVersionVector(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateVersionVector(nil))
+	require.NoError(t, validateVersionVector(crdt.VersionVector{"a": 1}))
+	require.Error(t, validateVersionVector(crdt.VersionVector{"": 1}))
+}
+
+func Test_validateDelta(t *testing.T) {
+	t.Parallel()
+
+	dot := crdt.Dot{Replica: "a", Counter: 1}
+	element := crdt.Element{
+		Dots:   []crdt.Dot{dot},
+		Fields: map[string]crdt.Register{crdt.FieldKind: {Value: "bus", Dot: dot}},
+	}
+
+	valid := crdt.State{
+		Context: crdt.VersionVector{"a": 1},
+		Nodes:   map[string]crdt.Element{"b1": element},
+	}
+
+	require.NoError(t, validateDelta(validModelID, crdt.State{}))
+	require.NoError(t, validateDelta(validModelID, valid))
+	require.NoError(t, validateDelta(validModelID, crdt.State{ModelID: validModelID}))
+	require.Error(t, validateDelta(validModelID, crdt.State{ModelID: "model2"}))
+	require.Error(t, validateDelta(validModelID, crdt.State{Context: crdt.VersionVector{"": 1}}))
+	require.Error(t, validateDelta(validModelID, crdt.State{Nodes: map[string]crdt.Element{"b1": element}}),
+		"dot not in context")
+	require.Error(t, validateDelta(validModelID, crdt.State{
+		Context:   crdt.VersionVector{"a": 1},
+		Relations: map[string]crdt.Element{"": element},
+	}))
+	require.Error(t, validateDelta(validModelID, crdt.State{
+		Context: crdt.VersionVector{"a": 1},
+		Nodes: map[string]crdt.Element{"b1": {
+			Props: map[string]map[string]crdt.Register{"load": {"kw": {Value: 1}}},
+		}},
+	}), "zero dot")
+}
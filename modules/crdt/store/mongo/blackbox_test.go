@@ -0,0 +1,59 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/energimind/powermesh-core/modules/crdt/store/mongo"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testState returns a state of the model holding a node written by a replica.
+func testState(modelID string) crdt.State {
+	state := crdt.NewState(modelID)
+	state.PutNode("a", models.Node{
+		ID:    "n1",
+		Kind:  "bus",
+		Code:  "B1",
+		Props: models.PropBag{"load": {"kw": 10.5, "max": models.Quantity{Value: 2, Unit: "MW"}}},
+	}, testTime)
+
+	return state
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.StateStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewStateStore(db)
+
+	require.NoError(t, store.EnsureIndexes(ctx))
+
+	f(t, ctx, store)
+}
@@ -0,0 +1,157 @@
+package mongo
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/energimind/powermesh-core/modules/crdt"
+	meshstore "github.com/energimind/powermesh-core/modules/models/store/mongo"
+)
+
+func toStoreState(s crdt.State) storeState {
+	return storeState{
+		ModelID:   s.ModelID,
+		Context:   toStoreContext(s.Context),
+		Nodes:     toStoreElements(s.Nodes),
+		Relations: toStoreElements(s.Relations),
+		Version:   s.Version,
+	}
+}
+
+func fromStoreState(s storeState) crdt.State {
+	state := crdt.NewState(s.ModelID)
+	state.Version = s.Version
+
+	for _, d := range s.Context {
+		state.Context[d.Replica] = uint64(d.Counter)
+	}
+
+	for _, e := range s.Nodes {
+		state.Nodes[e.ID] = fromStoreElement(e)
+	}
+
+	for _, e := range s.Relations {
+		state.Relations[e.ID] = fromStoreElement(e)
+	}
+
+	return state
+}
+
+// toStoreContext returns the version vector as a list of dots ordered by replica. Replica
+// IDs are not used as document keys, as they are chosen by the clients.
+func toStoreContext(v crdt.VersionVector) []storeDot {
+	dots := make([]storeDot, 0, len(v))
+
+	for replica, counter := range v {
+		dots = append(dots, storeDot{Replica: replica, Counter: int64(counter)})
+	}
+
+	slices.SortFunc(dots, func(a, b storeDot) int { return cmp.Compare(a.Replica, b.Replica) })
+
+	return dots
+}
+
+func toStoreElements(elements map[string]crdt.Element) []storeElement {
+	stored := make([]storeElement, 0, len(elements))
+
+	for id, e := range elements {
+		se := storeElement{
+			ID:     id,
+			Dots:   make([]storeDot, 0, len(e.Dots)),
+			Fields: make([]storeRegister, 0, len(e.Fields)),
+			Props:  []storeRegister{},
+		}
+
+		for _, d := range e.Dots {
+			se.Dots = append(se.Dots, toStoreDot(d))
+		}
+
+		for key, r := range e.Fields {
+			se.Fields = append(se.Fields, toStoreRegister("", key, r))
+		}
+
+		for section, registers := range e.Props {
+			for key, r := range registers {
+				se.Props = append(se.Props, toStoreRegister(section, key, r))
+			}
+		}
+
+		slices.SortFunc(se.Fields, compareRegisters)
+		slices.SortFunc(se.Props, compareRegisters)
+
+		stored = append(stored, se)
+	}
+
+	slices.SortFunc(stored, func(a, b storeElement) int { return cmp.Compare(a.ID, b.ID) })
+
+	return stored
+}
+
+// fromStoreElement returns the element. Empty register lists are returned as nil maps, as
+// in the elements of the states built by the domain.
+func fromStoreElement(se storeElement) crdt.Element {
+	var e crdt.Element
+
+	for _, d := range se.Dots {
+		e.Dots = append(e.Dots, fromStoreDot(d))
+	}
+
+	for _, r := range se.Fields {
+		if e.Fields == nil {
+			e.Fields = make(map[string]crdt.Register, len(se.Fields))
+		}
+
+		e.Fields[r.Key] = fromStoreRegister(r)
+	}
+
+	for _, r := range se.Props {
+		if e.Props == nil {
+			e.Props = map[string]map[string]crdt.Register{}
+		}
+
+		if e.Props[r.Section] == nil {
+			e.Props[r.Section] = map[string]crdt.Register{}
+		}
+
+		e.Props[r.Section][r.Key] = fromStoreRegister(r)
+	}
+
+	return e
+}
+
+func toStoreRegister(section, key string, r crdt.Register) storeRegister {
+	return storeRegister{
+		Section: section,
+		Key:     key,
+		Value:   meshstore.ToStorePropValue(r.Value),
+		Deleted: r.Deleted,
+		Time:    r.Time,
+		Dot:     toStoreDot(r.Dot),
+	}
+}
+
+func fromStoreRegister(r storeRegister) crdt.Register {
+	return crdt.Register{
+		Value:   meshstore.FromStorePropValue(r.Value),
+		Deleted: r.Deleted,
+		Time:    r.Time,
+		Dot:     fromStoreDot(r.Dot),
+	}
+}
+
+func toStoreDot(d crdt.Dot) storeDot {
+	return storeDot{Replica: d.Replica, Counter: int64(d.Counter)}
+}
+
+func fromStoreDot(d storeDot) crdt.Dot {
+	return crdt.Dot{Replica: d.Replica, Counter: uint64(d.Counter)}
+}
+
+// compareRegisters orders the registers by section and key.
+func compareRegisters(a, b storeRegister) int {
+	if c := cmp.Compare(a.Section, b.Section); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Key, b.Key)
+}
@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/crdt"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreState, toStoreState(validState))
+	require.Equal(t, validState, fromStoreState(validStoreState))
+}
+
+func Test_fromStoreState(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, crdt.NewState("model1"), fromStoreState(storeState{ModelID: "model1"}))
+}
+
+func Test_storeRegister(t *testing.T) {
+	t.Parallel()
+
+	register := crdt.Register{Value: models.Quantity{Value: 10, Unit: "kW"}}
+
+	require.NotEqual(t, register.Value, toStoreRegister("", "", register).Value)
+	require.Equal(t, register, fromStoreRegister(toStoreRegister("", "", register)))
+	require.Equal(t, "bus", toStoreRegister("", "", crdt.Register{Value: "bus"}).Value)
+}
+
+func Test_versionFilter(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		map[string]any{fieldModelID: "model1", fieldVersion: int64(2)},
+		map[string]any(versionFilter("model1", 2)),
+	)
+}
@@ -0,0 +1,38 @@
+package mongo
+
+import "time"
+
+// storeState represents the replicated state of a mesh in the MongoDB store.
+// The lists are kept in a stable order, see the mapper.
+type storeState struct {
+	ModelID   string         `bson:"modelId"`
+	Context   []storeDot     `bson:"context"`
+	Nodes     []storeElement `bson:"nodes"`
+	Relations []storeElement `bson:"relations"`
+	Version   int64          `bson:"version"`
+}
+
+// storeDot represents a dot in the MongoDB store.
+type storeDot struct {
+	Replica string `bson:"replica"`
+	Counter int64  `bson:"counter"`
+}
+
+// storeElement represents the state of a node or relation in the MongoDB store.
+type storeElement struct {
+	ID     string          `bson:"id"`
+	Dots   []storeDot      `bson:"dots"`
+	Fields []storeRegister `bson:"fields"`
+	Props  []storeRegister `bson:"props"`
+}
+
+// storeRegister represents a field or property register in the MongoDB store.
+// Field registers have no section.
+type storeRegister struct {
+	Section string    `bson:"section,omitempty"`
+	Key     string    `bson:"key"`
+	Value   any       `bson:"value"`
+	Deleted bool      `bson:"deleted"`
+	Time    time.Time `bson:"time"`
+	Dot     storeDot  `bson:"dot"`
+}
@@ -0,0 +1,71 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/crdt"
+)
+
+var (
+	testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dot1     = crdt.Dot{Replica: "a", Counter: 1}
+	dot2     = crdt.Dot{Replica: "a", Counter: 2}
+	dot3     = crdt.Dot{Replica: "b", Counter: 1}
+
+	validState = crdt.State{
+		ModelID: "model1",
+		Context: crdt.VersionVector{"b": 1, "a": 2},
+		Nodes: map[string]crdt.Element{
+			"n1": {
+				Dots: []crdt.Dot{dot2},
+				Fields: map[string]crdt.Register{
+					crdt.FieldKind: {Value: "bus", Time: testTime, Dot: dot1},
+				},
+				Props: map[string]map[string]crdt.Register{
+					"load": {"kw": {Value: 10.5, Time: testTime, Dot: dot2}},
+				},
+			},
+		},
+		Relations: map[string]crdt.Element{
+			"r2": {
+				Fields: map[string]crdt.Register{
+					crdt.FieldTo:   {Value: "n1", Time: testTime, Dot: dot3},
+					crdt.FieldFrom: {Value: "n1", Time: testTime, Dot: dot3},
+				},
+			},
+			"r1": {},
+		},
+		Version: 3,
+	}
+	validStoreState = storeState{
+		ModelID: "model1",
+		Context: []storeDot{{Replica: "a", Counter: 2}, {Replica: "b", Counter: 1}},
+		Nodes: []storeElement{
+			{
+				ID:     "n1",
+				Dots:   []storeDot{{Replica: "a", Counter: 2}},
+				Fields: []storeRegister{{Key: "kind", Value: "bus", Time: testTime, Dot: storeDot{Replica: "a", Counter: 1}}},
+				Props: []storeRegister{{
+					Section: "load",
+					Key:     "kw",
+					Value:   10.5,
+					Time:    testTime,
+					Dot:     storeDot{Replica: "a", Counter: 2},
+				}},
+			},
+		},
+		Relations: []storeElement{
+			{ID: "r1", Dots: []storeDot{}, Fields: []storeRegister{}, Props: []storeRegister{}},
+			{
+				ID:   "r2",
+				Dots: []storeDot{},
+				Fields: []storeRegister{
+					{Key: "from", Value: "n1", Time: testTime, Dot: storeDot{Replica: "b", Counter: 1}},
+					{Key: "to", Value: "n1", Time: testTime, Dot: storeDot{Replica: "b", Counter: 1}},
+				},
+				Props: []storeRegister{},
+			},
+		},
+		Version: 3,
+	}
+)
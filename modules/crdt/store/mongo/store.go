@@ -0,0 +1,103 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collStates   = "crdtStates"
+	fieldModelID = "modelId"
+	fieldVersion = "version"
+)
+
+// StateStore is a MongoDB implementation of the state store.
+//
+// The state of a mesh is kept in a single document with a version number. Saving replaces
+// the document only if it still has the version of the saved state, and new states rely on
+// the unique index of the states by model, see EnsureIndexes.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type StateStore struct {
+	states *mongo.Collection
+}
+
+// NewStateStore creates a new MongoDB state store.
+func NewStateStore(db *mongo.Database) *StateStore {
+	return &StateStore{
+		states: db.Collection(collStates),
+	}
+}
+
+// EnsureIndexes creates the unique index of the states by model if it does not exist.
+func (s *StateStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.states.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: fieldModelID, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return errorz.NewStoreError("failed to create indexes of %s: %v", collStates, err)
+	}
+
+	return nil
+}
+
+// GetState implements the state store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *StateStore) GetState(ctx context.Context, modelID string) (crdt.State, error) {
+	return q.GetOne(s.states, fromStoreState).Key(fieldModelID).Exec(ctx, modelID)
+}
+
+// SaveState implements the state store interface.
+// It returns a conflict error if the state has been saved since it was read, or a new
+// state has been saved for the model concurrently.
+func (s *StateStore) SaveState(ctx context.Context, state crdt.State) (crdt.State, error) {
+	saved := state
+	saved.Version++
+
+	if state.Version == 0 {
+		if _, err := s.states.InsertOne(ctx, toStoreState(saved)); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return crdt.State{}, errorz.NewConflictError("state of model %s already exists", state.ModelID)
+			}
+
+			return crdt.State{}, errorz.NewStoreError("failed to create state: %v", err)
+		}
+
+		return saved, nil
+	}
+
+	res, err := s.states.ReplaceOne(ctx, versionFilter(state.ModelID, state.Version), toStoreState(saved))
+	if err != nil {
+		return crdt.State{}, errorz.NewStoreError("failed to update state: %v", err)
+	}
+
+	if res.MatchedCount == 0 {
+		return crdt.State{}, errorz.NewConflictError("state of model %s has changed", state.ModelID)
+	}
+
+	return saved, nil
+}
+
+// DeleteState implements the state store interface.
+// A missing state is ignored.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *StateStore) DeleteState(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.states).Exec(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+
+	return err
+}
+
+// versionFilter builds the filter of the state of the model at the version.
+func versionFilter(modelID string, version int64) bson.M {
+	return bson.M{fieldModelID: modelID, fieldVersion: version}
+}
@@ -0,0 +1,121 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/crdt/store/mongo"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_EnsureIndexes(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.StateStore) {
+		require.NoError(t, store.EnsureIndexes(ctx))
+	})
+}
+
+func TestStateStore_SaveState(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.StateStore) {
+		state := testState("model1")
+
+		t.Run("create", func(t *testing.T) {
+			saved, err := store.SaveState(ctx, state)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(1), saved.Version)
+
+			found, err := store.GetState(ctx, "model1")
+
+			require.NoError(t, err)
+			require.Equal(t, saved.Version, found.Version)
+			require.Equal(t, state.Context, found.Context)
+			require.Equal(t, state.Mesh(), found.Mesh())
+		})
+
+		t.Run("create-conflict", func(t *testing.T) {
+			_, err := store.SaveState(ctx, state)
+
+			require.IsType(t, errorz.ConflictError{}, err)
+		})
+
+		t.Run("update", func(t *testing.T) {
+			found, err := store.GetState(ctx, "model1")
+			require.NoError(t, err)
+
+			found.PutNode("b", models.Node{ID: "n2", Kind: "bus"}, testTime)
+
+			saved, err := store.SaveState(ctx, found)
+
+			require.NoError(t, err)
+			require.Equal(t, int64(2), saved.Version)
+
+			updated, err := store.GetState(ctx, "model1")
+
+			require.NoError(t, err)
+			require.Equal(t, found.Context, updated.Context)
+			require.Contains(t, updated.Mesh().Nodes, "n2")
+		})
+
+		t.Run("update-conflict", func(t *testing.T) {
+			stale := testState("model1")
+			stale.Version = 1
+
+			_, err := store.SaveState(ctx, stale)
+
+			require.IsType(t, errorz.ConflictError{}, err)
+		})
+	})
+}
+
+func TestStateStore_GetState(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.StateStore) {
+		t.Run("not-found", func(t *testing.T) {
+			_, err := store.GetState(ctx, "model1")
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+
+		t.Run("success", func(t *testing.T) {
+			state := testState("model1")
+
+			_, err := store.SaveState(ctx, state)
+			require.NoError(t, err)
+
+			found, err := store.GetState(ctx, "model1")
+
+			require.NoError(t, err)
+			require.Equal(t, models.Quantity{Value: 2, Unit: "MW"}, found.Mesh().Nodes["n1"].Props["load"]["max"])
+			require.Equal(t, state.Nodes["n1"].Dots, found.Nodes["n1"].Dots)
+			require.Equal(t, state.Context, found.Context)
+		})
+	})
+}
+
+func TestStateStore_DeleteState(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.StateStore) {
+		_, err := store.SaveState(ctx, testState("model1"))
+		require.NoError(t, err)
+
+		_, err = store.SaveState(ctx, testState("model2"))
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteState(ctx, "model1"))
+		require.NoError(t, store.DeleteState(ctx, "model1"))
+
+		_, err = store.GetState(ctx, "model1")
+		require.IsType(t, errorz.NotFoundError{}, err)
+
+		_, err = store.GetState(ctx, "model2")
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,3 @@
+// Package history provides a model and service for undoing and redoing the mesh edits of
+// an actor.
+package history
@@ -0,0 +1,48 @@
+package history
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// EventType is the type of event that occurred.
+type EventType string
+
+// Event types.
+const (
+	EntryUndone EventType = "history.undone"
+	EntryRedone EventType = "history.redone"
+)
+
+// Event models an event that occurs in the history service.
+type Event interface {
+	IsHistoryEvent() bool
+}
+
+// EventHeader models the header of an event.
+type EventHeader struct {
+	Type      EventType
+	Actor     access.Actor
+	Timestamp time.Time
+}
+
+// EntryEvent models an event that occurs in the history service related to an entry.
+type EntryEvent struct {
+	EventHeader
+	Entry Entry
+}
+
+// IsHistoryEvent implements the Event interface.
+func (EntryEvent) IsHistoryEvent() bool {
+	return true
+}
+
+// ExtractEntryEvent extracts an entry event from an event.
+func ExtractEntryEvent(e Event) (EntryEvent, bool) {
+	if ee, ok := e.(EntryEvent); ok {
+		return ee, true
+	}
+
+	return EntryEvent{}, false
+}
@@ -0,0 +1,26 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEntryEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		ee, ok := ExtractEntryEvent(EntryEvent{EventHeader: EventHeader{Type: EntryUndone}})
+
+		require.True(t, ok)
+		require.NotZero(t, ee)
+		require.True(t, ee.IsHistoryEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ee, ok := ExtractEntryEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, ee)
+	})
+}
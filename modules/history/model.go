@@ -0,0 +1,29 @@
+package history
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// Entry represents a mesh edit recorded in the history of an actor and model.
+//
+// The entry keeps the edited elements as they were before and after the edit. Undoing the
+// entry turns the elements from their after state back into their before state; redoing
+// it does the opposite. Elements missing from a state did not exist in it.
+type Entry struct {
+	ID        string
+	ModelID   string
+	ActorID   string      // user ID of the actor who made the edit
+	Seq       int64       // position in the history of the actor and model
+	Before    models.Mesh // edited elements before the edit
+	After     models.Mesh // edited elements after the edit
+	Undone    bool
+	CreatedAt time.Time
+}
+
+// IsEmpty checks if the entry changes no element.
+func (e Entry) IsEmpty() bool {
+	return len(e.Before.Nodes) == 0 && len(e.Before.Relations) == 0 &&
+		len(e.After.Nodes) == 0 && len(e.After.Relations) == 0
+}
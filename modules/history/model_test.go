@@ -0,0 +1,17 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry_IsEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Entry{}.IsEmpty())
+	require.True(t, Entry{Before: models.Mesh{ModelID: "m1"}}.IsEmpty())
+	require.False(t, Entry{Before: models.Mesh{Nodes: map[string]models.Node{"n1": {ID: "n1"}}}}.IsEmpty())
+	require.False(t, Entry{After: models.Mesh{Relations: map[string]models.Relation{"r1": {ID: "r1"}}}}.IsEmpty())
+}
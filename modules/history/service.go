@@ -0,0 +1,14 @@
+package history
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// HistoryService defines the history service.
+type HistoryService interface {
+	Undo(ctx context.Context, actor access.Actor, modelID string) (Entry, error)
+	Redo(ctx context.Context, actor access.Actor, modelID string) (Entry, error)
+	GetHistory(ctx context.Context, actor access.Actor, modelID string) ([]Entry, error)
+}
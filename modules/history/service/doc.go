@@ -0,0 +1,2 @@
+// Package service implements the history service.
+package service
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+func entryFromEdit(
+	actor access.Actor,
+	modelID string,
+	before, after models.Mesh,
+	now time.Time,
+) history.Entry {
+	before.ModelID = modelID
+	after.ModelID = modelID
+
+	return history.Entry{
+		ModelID:   modelID,
+		ActorID:   actor.UserID,
+		Before:    before,
+		After:     after,
+		CreatedAt: now,
+	}
+}
+
+func nodeData(node models.Node) models.NodeData {
+	return models.NodeData{
+		Kind:  node.Kind,
+		Code:  node.Code,
+		Props: node.Props,
+	}
+}
+
+func relationData(relation models.Relation) models.RelationData {
+	return models.RelationData{
+		Kind:  relation.Kind,
+		From:  relation.From,
+		To:    relation.To,
+		Props: relation.Props,
+	}
+}
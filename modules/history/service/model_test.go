@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_entryFromEdit(t *testing.T) {
+	t.Parallel()
+
+	after := testNodes(validModelID, changedNode)
+
+	require.Equal(t,
+		history.Entry{
+			ModelID:   validModelID,
+			ActorID:   editorActor.UserID,
+			Before:    models.Mesh{ModelID: validModelID},
+			After:     after,
+			CreatedAt: testTime,
+		},
+		entryFromEdit(editorActor, validModelID, models.Mesh{}, after, testTime),
+	)
+}
+
+func Test_nodeData(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		models.NodeData{Kind: changedNode.Kind, Code: changedNode.Code, Props: changedNode.Props},
+		nodeData(changedNode),
+	)
+}
+
+func Test_relationData(t *testing.T) {
+	t.Parallel()
+
+	relation := testMesh.Relations["r1"]
+
+	require.Equal(t,
+		models.RelationData{Kind: relation.Kind, From: relation.From, To: relation.To},
+		relationData(relation),
+	)
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// defaultDepth is the default number of entries kept in the history of an actor and model.
+const defaultDepth = 50
+
+// idGenerator defines the external ID generator.
+type idGenerator interface {
+	GenerateID() string
+}
+
+// store defines the external history store.
+// GetEntries must return the entries of the actor and model ordered by their sequence number.
+type store interface {
+	CreateEntry(ctx context.Context, entry history.Entry) error
+	UpdateEntry(ctx context.Context, entry history.Entry) error
+	DeleteEntries(ctx context.Context, ids []string) error
+	DeleteModelEntries(ctx context.Context, modelID string) error
+	GetEntries(ctx context.Context, modelID, actorID string) ([]history.Entry, error)
+}
+
+// listener defines the external history event listener.
+type listener interface {
+	HandleHistoryEvent(ctx context.Context, event history.Event) error
+}
+
+// meshEditor defines the external editor of mesh elements.
+// It is implemented by the mesh service.
+type meshEditor interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	GetRelation(ctx context.Context, modelID, relationID string) (models.Relation, error)
+	RestoreNode(ctx context.Context, actor access.Actor, modelID string, node models.Node) (models.Node, error)
+	UpdateNode(ctx context.Context, actor access.Actor, modelID, nodeID string, data models.NodeData) (models.Node, error)
+	DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error
+	RestoreRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID string,
+		relation models.Relation,
+	) (models.Relation, error)
+	UpdateRelation(
+		ctx context.Context,
+		actor access.Actor,
+		modelID, relationID string,
+		data models.RelationData,
+	) (models.Relation, error)
+	DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+}
+
+// HistoryService implements the history service.
+//
+// It implements the history.HistoryService interface.
+//
+// The service records the mesh edits of every actor as a mesh listener of the mesh service
+// and replays them through the mesh editor, so undo and redo are subject to the same checks
+// as the edits themselves. Actors need read access to the model to see their history and
+// write access to undo and redo. The history of an actor and model is bounded: the oldest entries
+// are dropped once it is full, and a new edit drops the entries undone before it. Creating
+// or deleting the mesh clears the history of the model.
+//
+// An entry is only replayed if the elements it touches are still in the state it left them
+// in, so an undo never overwrites a newer edit made by another actor. Replaying is not
+// atomic: if the mesh editor fails half-way, the elements replayed so far stay changed and
+// the entry is kept as it was.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type HistoryService struct {
+	idGen    idGenerator
+	store    store
+	meshes   meshEditor
+	authz    authorizer
+	listener listener
+	depth    int
+	now      func() time.Time
+}
+
+// Ensure HistoryService implements the history.HistoryService interface.
+var _ history.HistoryService = (*HistoryService)(nil)
+
+// NewHistoryService creates a new history service.
+func NewHistoryService(
+	store store,
+	idGen idGenerator,
+	meshes meshEditor,
+	authz authorizer,
+	opts ...Option,
+) *HistoryService {
+	svc := &HistoryService{
+		idGen:  idGen,
+		store:  store,
+		meshes: meshes,
+		authz:  authz,
+		depth:  defaultDepth,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// Undo implements the history.HistoryService interface.
+// It reverts the latest edit of the actor in the model that has not been undone yet.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) Undo(ctx context.Context, actor access.Actor, modelID string) (history.Entry, error) {
+	entries, err := s.getEntries(ctx, actor, modelID, access.PermissionWrite)
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	// undone entries always follow the others, so the latest entry not undone precedes them
+	i := slices.IndexFunc(entries, func(e history.Entry) bool { return e.Undone })
+	if i < 0 {
+		i = len(entries)
+	}
+
+	if i == 0 {
+		return history.Entry{}, errorz.NewNotFoundError("nothing to undo in model %s", modelID)
+	}
+
+	entry := entries[i-1]
+
+	if err := s.replay(ctx, actor, modelID, entry.After, entry.Before); err != nil {
+		return history.Entry{}, err
+	}
+
+	entry.Undone = true
+
+	return s.saveEntry(ctx, actor, history.EntryUndone, entry)
+}
+
+// Redo implements the history.HistoryService interface.
+// It reapplies the earliest edit of the actor in the model that has been undone.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) Redo(ctx context.Context, actor access.Actor, modelID string) (history.Entry, error) {
+	entries, err := s.getEntries(ctx, actor, modelID, access.PermissionWrite)
+	if err != nil {
+		return history.Entry{}, err
+	}
+
+	i := slices.IndexFunc(entries, func(e history.Entry) bool { return e.Undone })
+	if i < 0 {
+		return history.Entry{}, errorz.NewNotFoundError("nothing to redo in model %s", modelID)
+	}
+
+	entry := entries[i]
+
+	if err := s.replay(ctx, actor, modelID, entry.Before, entry.After); err != nil {
+		return history.Entry{}, err
+	}
+
+	entry.Undone = false
+
+	return s.saveEntry(ctx, actor, history.EntryRedone, entry)
+}
+
+// GetHistory implements the history.HistoryService interface.
+// It returns the entries of the actor in the model, the oldest first.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) GetHistory(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+) ([]history.Entry, error) {
+	return s.getEntries(ctx, actor, modelID, access.PermissionRead)
+}
+
+// HandleMeshEvent records the element edits of the event in the history of its actor. If
+// the mesh is created or deleted, the history of the model is cleared. The edits replayed
+// by the service itself are not recorded.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) HandleMeshEvent(ctx context.Context, event models.MeshEvent) error {
+	if isReplay(ctx) {
+		return nil
+	}
+
+	//nolint:exhaustive // only mesh and element edits are of interest
+	switch event.Type {
+	case models.MeshCreated, models.MeshDeleted:
+		return s.store.DeleteModelEntries(ctx, event.Updates.ModelID)
+	case models.MeshContentsCreated:
+		return s.record(ctx, event.Actor, event.Updates.ModelID, models.Mesh{}, event.Updates)
+	case models.MeshContentsUpdated:
+		return s.record(ctx, event.Actor, event.Updates.ModelID, event.Previous, event.Updates)
+	case models.MeshContentsDeleted:
+		return s.record(ctx, event.Actor, event.Deletes.ModelID, event.Previous, models.Mesh{})
+	}
+
+	return nil
+}
+
+// DeleteModelResources removes the history of all actors in the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelEntries(ctx, modelID)
+}
+
+// getEntries returns the entries of the actor in the model, the oldest first, if the
+// actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) getEntries(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) ([]history.Entry, error) {
+	if err := validateModelID(modelID); err != nil {
+		return nil, err
+	}
+
+	if err := validateActor(actor.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetEntries(ctx, modelID, actor.UserID)
+}
+
+// record adds the edit to the history of the actor. The entries undone before the edit can
+// no longer be redone and are dropped, as are the oldest entries beyond the history depth.
+// Edits made without a user, or that change nothing, are not recorded.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) record(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	before, after models.Mesh,
+) error {
+	entry := entryFromEdit(actor, modelID, before, after, s.now())
+
+	if actor.UserID == "" || entry.IsEmpty() {
+		return nil
+	}
+
+	entries, err := s.store.GetEntries(ctx, modelID, actor.UserID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		kept    []history.Entry
+		dropped []string
+	)
+
+	for _, e := range entries {
+		entry.Seq = max(entry.Seq, e.Seq)
+
+		if e.Undone {
+			dropped = append(dropped, e.ID)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+
+	if excess := len(kept) + 1 - s.depth; excess > 0 {
+		for _, e := range kept[:min(excess, len(kept))] {
+			dropped = append(dropped, e.ID)
+		}
+	}
+
+	if len(dropped) > 0 {
+		if err := s.store.DeleteEntries(ctx, dropped); err != nil {
+			return err
+		}
+	}
+
+	entry.ID = s.idGen.GenerateID()
+	entry.Seq++
+
+	return s.store.CreateEntry(ctx, entry)
+}
+
+// replay turns the elements from one state into the other through the mesh editor. The
+// elements must currently be in the from state.
+//
+// Nodes are restored and updated before the relations referring to them, and deleted after
+// the relations referring to them.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) replay(ctx context.Context, actor access.Actor, modelID string, from, to models.Mesh) error {
+	if err := s.ensureUnchanged(ctx, modelID, from, to); err != nil {
+		return err
+	}
+
+	ctx = withReplay(ctx)
+
+	for _, id := range models.SortedKeys(to.Nodes) {
+		var err error
+
+		if _, ok := from.Nodes[id]; ok {
+			_, err = s.meshes.UpdateNode(ctx, actor, modelID, id, nodeData(to.Nodes[id]))
+		} else {
+			_, err = s.meshes.RestoreNode(ctx, actor, modelID, to.Nodes[id])
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range models.SortedKeys(from.Relations) {
+		if _, ok := to.Relations[id]; !ok {
+			if err := s.meshes.DeleteRelation(ctx, actor, modelID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range models.SortedKeys(to.Relations) {
+		var err error
+
+		if _, ok := from.Relations[id]; ok {
+			_, err = s.meshes.UpdateRelation(ctx, actor, modelID, id, relationData(to.Relations[id]))
+		} else {
+			_, err = s.meshes.RestoreRelation(ctx, actor, modelID, to.Relations[id])
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range models.SortedKeys(from.Nodes) {
+		if _, ok := to.Nodes[id]; !ok {
+			if err := s.meshes.DeleteNode(ctx, actor, modelID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureUnchanged checks that the elements of both states are currently in the from state.
+// Elements missing from the from state must not exist.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) ensureUnchanged(ctx context.Context, modelID string, from, to models.Mesh) error {
+	for _, id := range models.SortedKeys(from.Nodes, to.Nodes) {
+		node, err := s.meshes.GetNode(ctx, modelID, id)
+
+		if err := checkElement("node", id, from.Nodes, node, err); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range models.SortedKeys(from.Relations, to.Relations) {
+		relation, err := s.meshes.GetRelation(ctx, modelID, id)
+
+		if err := checkElement("relation", id, from.Relations, relation, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkElement checks the current element, as returned by its lookup, against the expected
+// elements. It returns a conflict error if the element differs from the expected one, or
+// exists although it is not expected.
+func checkElement[T models.Node | models.Relation](
+	element, id string,
+	expected map[string]T,
+	current T,
+	lookupErr error,
+) error {
+	want, ok := expected[id]
+
+	switch {
+	case lookupErr == nil && ok && models.SameElement(current, want):
+		return nil
+	case errorz.IsNotFoundError(lookupErr) && !ok:
+		return nil
+	case lookupErr != nil && !errorz.IsNotFoundError(lookupErr):
+		return lookupErr
+	}
+
+	return errorz.NewConflictError("%s %s has been changed since the edit", element, id)
+}
+
+// saveEntry stores the changed entry and fires the event.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryService) saveEntry(
+	ctx context.Context,
+	actor access.Actor,
+	eventType history.EventType,
+	entry history.Entry,
+) (history.Entry, error) {
+	if err := s.store.UpdateEntry(ctx, entry); err != nil {
+		return history.Entry{}, err
+	}
+
+	if err := s.fireEntryEvent(ctx, actor, eventType, entry); err != nil {
+		return history.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+// fireEntryEvent fires an entry event.
+func (s *HistoryService) fireEntryEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType history.EventType,
+	entry history.Entry,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := history.EntryEvent{
+		EventHeader: history.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Entry: entry,
+	}
+
+	if err := s.listener.HandleHistoryEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
+
+// replayKey is the context key marking the edits replayed by the service.
+type replayKey struct{}
+
+// withReplay returns a context marking the edits made with it as replayed.
+func withReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+// isReplay checks if the edits made with the context are replayed by the service.
+func isReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+
+	return replay
+}
@@ -0,0 +1,18 @@
+package service
+
+// Option defines the option for the service.
+type Option func(service *HistoryService)
+
+// WithListener sets the listener for the service.
+func WithListener(listener listener) Option {
+	return func(s *HistoryService) {
+		s.listener = listener
+	}
+}
+
+// WithDepth sets the number of entries kept in the history of an actor and model.
+func WithDepth(depth int) Option {
+	return func(s *HistoryService) {
+		s.depth = depth
+	}
+}
@@ -0,0 +1,533 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryService_Undo(t *testing.T) {
+	t.Parallel()
+
+	updated := testEntry("u", 1, testNodes("", testMesh.Nodes["b1"]), testNodes("", changedNode), false)
+
+	tests := map[string]struct {
+		actor         access.Actor
+		modelID       string
+		initial       []history.Entry
+		edit          func(te *testMeshEditor)
+		storeError    bool
+		editorError   bool
+		listenerError bool
+		wantCalls     []string
+		wantMesh      models.Mesh
+		wantErr       error
+	}{
+		"invalid-modelID": {
+			actor:   editorActor,
+			wantErr: errorz.ValidationError{},
+		},
+		"no-actor": {
+			modelID: validModelID,
+			wantErr: errorz.ValidationError{},
+		},
+		"no-write-permission": {
+			actor:   guestActor,
+			modelID: validModelID,
+			initial: []history.Entry{updated},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"nothing-to-undo": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{{ID: "u", ModelID: validModelID, ActorID: editorActor.UserID, Undone: true}},
+			wantErr: errorz.NotFoundError{},
+		},
+		"changed-since": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{updated},
+			wantErr: errorz.ConflictError{},
+		},
+		"deleted-since": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{
+				testEntry("d", 1, models.Mesh{}, testNodes("", models.Node{ID: "b9", Kind: "bus"}), false),
+			},
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			actor:      editorActor,
+			modelID:    validModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"editor-error": {
+			actor:       editorActor,
+			modelID:     validModelID,
+			initial:     []history.Entry{updated},
+			edit:        func(te *testMeshEditor) { te.mesh.Nodes["b1"] = changedNode },
+			editorError: true,
+			wantErr:     errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         editorActor,
+			modelID:       validModelID,
+			initial:       []history.Entry{updated},
+			edit:          func(te *testMeshEditor) { te.mesh.Nodes["b1"] = changedNode },
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-update": {
+			actor:     editorActor,
+			modelID:   validModelID,
+			initial:   []history.Entry{testEntry("old", 1, models.Mesh{}, models.Mesh{}, false), withSeq(updated, 2)},
+			edit:      func(te *testMeshEditor) { te.mesh.Nodes["b1"] = changedNode },
+			wantCalls: []string{"update-node b1"},
+			wantMesh:  testMesh,
+		},
+		"success-create": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{
+				testEntry("c", 1, models.Mesh{}, models.Mesh{
+					Nodes:     map[string]models.Node{"b3": {ID: "b3", Kind: "bus"}},
+					Relations: map[string]models.Relation{"r2": {ID: "r2", Kind: "line", From: "b2", To: "b3"}},
+				}, false),
+			},
+			edit: func(te *testMeshEditor) {
+				te.mesh.Nodes["b3"] = models.Node{ID: "b3", Kind: "bus"}
+				te.mesh.Relations["r2"] = models.Relation{ID: "r2", Kind: "line", From: "b2", To: "b3"}
+			},
+			wantCalls: []string{"delete-relation r2", "delete-node b3"},
+			wantMesh:  testMesh,
+		},
+		"success-delete": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{
+				testEntry("d", 1, models.Mesh{
+					Nodes:     map[string]models.Node{"b2": testMesh.Nodes["b2"]},
+					Relations: map[string]models.Relation{"r1": testMesh.Relations["r1"]},
+				}, models.Mesh{}, false),
+			},
+			edit: func(te *testMeshEditor) {
+				delete(te.mesh.Nodes, "b2")
+				delete(te.mesh.Relations, "r1")
+			},
+			wantCalls: []string{"restore-node b2", "restore-relation r1"},
+			wantMesh:  testMesh,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, test.initial...)
+			te := newTestMeshEditor(test.editorError)
+			tl := newTestListener(test.listenerError)
+
+			if test.edit != nil {
+				test.edit(te)
+			}
+
+			svc := newTestService(ts, te, tl)
+
+			entry, err := svc.Undo(context.Background(), test.actor, test.modelID)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, entry)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, entry.Undone)
+			require.Equal(t, entry, ts.entries[entry.ID])
+			require.Equal(t, test.wantCalls, te.calls)
+			require.Equal(t, test.wantMesh, te.mesh)
+			require.Len(t, ts.entries, len(test.initial), "replayed edits must not be recorded")
+			requireEventFired(t, history.EntryUndone, tl)
+		})
+	}
+}
+
+func TestHistoryService_Redo(t *testing.T) {
+	t.Parallel()
+
+	undone := testEntry("u", 1, testNodes("", testMesh.Nodes["b1"]), testNodes("", changedNode), true)
+
+	tests := map[string]struct {
+		actor      access.Actor
+		modelID    string
+		initial    []history.Entry
+		edit       func(te *testMeshEditor)
+		storeError bool
+		wantMesh   models.Mesh
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			actor:   editorActor,
+			wantErr: errorz.ValidationError{},
+		},
+		"no-write-permission": {
+			actor:   guestActor,
+			modelID: validModelID,
+			initial: []history.Entry{undone},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"nothing-to-redo": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{withUndone(undone, false)},
+			wantErr: errorz.NotFoundError{},
+		},
+		"changed-since": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{undone},
+			edit:    func(te *testMeshEditor) { te.mesh.Nodes["b1"] = models.Node{ID: "b1", Kind: "bus"} },
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			actor:      editorActor,
+			modelID:    validModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			actor:   editorActor,
+			modelID: validModelID,
+			initial: []history.Entry{undone, testEntry("later", 2, models.Mesh{}, models.Mesh{}, true)},
+			wantMesh: models.Mesh{
+				ModelID:   validModelID,
+				Nodes:     map[string]models.Node{"b1": changedNode, "b2": testMesh.Nodes["b2"]},
+				Relations: testMesh.Relations,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, test.initial...)
+			te := newTestMeshEditor(false)
+			tl := newTestListener(false)
+
+			if test.edit != nil {
+				test.edit(te)
+			}
+
+			svc := newTestService(ts, te, tl)
+
+			entry, err := svc.Redo(context.Background(), test.actor, test.modelID)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, entry)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, undone.ID, entry.ID)
+			require.False(t, ts.entries[entry.ID].Undone)
+			require.True(t, ts.entries["later"].Undone)
+			require.Equal(t, test.wantMesh, te.mesh)
+			requireEventFired(t, history.EntryRedone, tl)
+		})
+	}
+}
+
+func TestHistoryService_GetHistory(t *testing.T) {
+	t.Parallel()
+
+	entry := testEntry("u", 1, testNodes("", testMesh.Nodes["b1"]), testNodes("", changedNode), false)
+
+	tests := map[string]struct {
+		actor       access.Actor
+		modelID     string
+		wantEntries []history.Entry
+		wantErr     error
+	}{
+		"invalid-modelID": {
+			actor:   editorActor,
+			wantErr: errorz.ValidationError{},
+		},
+		"no-binding": {
+			actor:   access.Actor{UserID: "stranger", Role: access.RoleEditor},
+			modelID: validModelID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"other-model": {
+			actor:   editorActor,
+			modelID: "model2",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"guest": {
+			actor:   guestActor,
+			modelID: validModelID,
+		},
+		"editor": {
+			actor:       editorActor,
+			modelID:     validModelID,
+			wantEntries: []history.Entry{entry},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := newTestService(newTestStore(false, entry), newTestMeshEditor(false), newTestListener(false))
+
+			entries, err := svc.GetHistory(context.Background(), test.actor, test.modelID)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, entries)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantEntries, entries)
+		})
+	}
+}
+
+func TestHistoryService_HandleMeshEvent(t *testing.T) {
+	t.Parallel()
+
+	b1 := testMesh.Nodes["b1"]
+	other := testEntry("o", 1, models.Mesh{}, testNodes("", b1), false)
+	other.ActorID = otherActor.UserID
+
+	tests := map[string]struct {
+		ctx        context.Context
+		event      models.MeshEvent
+		initial    []history.Entry
+		storeError bool
+		wantEntry  history.Entry
+		wantIDs    []string
+		wantErr    error
+	}{
+		"created": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsCreated, Actor: editorActor},
+				Updates:     testNodes(validModelID, b1),
+			},
+			wantEntry: testEntry(validEntryID, 1, models.Mesh{}, testNodes("", b1), false),
+		},
+		"updated": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsUpdated, Actor: editorActor},
+				Updates:     testNodes(validModelID, changedNode),
+				Previous:    testNodes(validModelID, b1),
+			},
+			initial:   []history.Entry{other},
+			wantEntry: testEntry(validEntryID, 1, testNodes("", b1), testNodes("", changedNode), false),
+			wantIDs:   []string{"o"},
+		},
+		"deleted-after-undo": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsDeleted, Actor: editorActor},
+				Deletes:     testNodes(validModelID, models.Node{ID: "b1"}),
+				Previous:    testNodes(validModelID, b1),
+			},
+			initial: []history.Entry{
+				testEntry("done", 1, models.Mesh{}, models.Mesh{}, false),
+				testEntry("undone", 2, models.Mesh{}, models.Mesh{}, true),
+			},
+			wantEntry: testEntry(validEntryID, 3, testNodes("", b1), models.Mesh{}, false),
+			wantIDs:   []string{"done"},
+		},
+		"mesh-deleted": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshDeleted, Actor: editorActor},
+				Updates:     models.Mesh{ModelID: validModelID},
+			},
+			initial: []history.Entry{other},
+		},
+		"profiles-updated": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshProfilesUpdated, Actor: editorActor},
+				Updates:     testNodes(validModelID, b1),
+			},
+			initial: []history.Entry{other},
+			wantIDs: []string{"o"},
+		},
+		"replayed": {
+			ctx: withReplay(context.Background()),
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsCreated, Actor: editorActor},
+				Updates:     testNodes(validModelID, b1),
+			},
+		},
+		"no-user": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsCreated},
+				Updates:     testNodes(validModelID, b1),
+			},
+		},
+		"store-error": {
+			event: models.MeshEvent{
+				EventHeader: models.EventHeader{Type: models.MeshContentsCreated, Actor: editorActor},
+				Updates:     testNodes(validModelID, b1),
+			},
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, test.initial...)
+			svc := newTestService(ts, newTestMeshEditor(false), newTestListener(false))
+
+			ctx := test.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			err := svc.HandleMeshEvent(ctx, test.event)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			wantIDs := test.wantIDs
+
+			if test.wantEntry.ID != "" {
+				require.Equal(t, test.wantEntry, ts.entries[test.wantEntry.ID])
+
+				wantIDs = append(wantIDs, test.wantEntry.ID)
+			}
+
+			require.ElementsMatch(t, wantIDs, keys(ts.entries))
+		})
+	}
+}
+
+func TestHistoryService_depth(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestStore(false)
+	te := newTestMeshEditor(false)
+	svc := newTestService(ts, te, newTestListener(false), WithDepth(2))
+	ctx := context.Background()
+
+	for _, kw := range []float64{1, 2, 3} {
+		data := models.NodeData{Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": kw}}}
+
+		_, err := te.UpdateNode(ctx, editorActor, validModelID, "b1", data)
+
+		require.NoError(t, err)
+	}
+
+	entries, err := svc.GetHistory(ctx, editorActor, validModelID)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, []int64{2, 3}, []int64{entries[0].Seq, entries[1].Seq})
+}
+
+func TestHistoryService_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestStore(false)
+	te := newTestMeshEditor(false)
+	svc := newTestService(ts, te, newTestListener(false))
+	ctx := context.Background()
+
+	_, err := te.UpdateNode(ctx, editorActor, validModelID, "b1", nodeData(changedNode))
+	require.NoError(t, err)
+	require.NoError(t, te.DeleteRelation(ctx, editorActor, validModelID, "r1"))
+
+	_, err = svc.Undo(ctx, editorActor, validModelID)
+	require.NoError(t, err)
+	_, err = svc.Undo(ctx, editorActor, validModelID)
+	require.NoError(t, err)
+	require.Equal(t, testMesh, te.mesh)
+
+	_, err = svc.Undo(ctx, editorActor, validModelID)
+	require.IsType(t, errorz.NotFoundError{}, err)
+
+	_, err = svc.Redo(ctx, editorActor, validModelID)
+	require.NoError(t, err)
+	require.Equal(t, changedNode, te.mesh.Nodes["b1"])
+	require.Contains(t, te.mesh.Relations, "r1")
+
+	// another actor changing the node makes the redone edit unsafe to undo
+	_, err = te.UpdateNode(ctx, otherActor, validModelID, "b1", models.NodeData{Kind: "bus", Code: "B1"})
+	require.NoError(t, err)
+
+	_, err = svc.Undo(ctx, editorActor, validModelID)
+	require.IsType(t, errorz.ConflictError{}, err)
+}
+
+func TestHistoryService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid-modelID", func(t *testing.T) {
+		svc := newTestService(newTestStore(false), newTestMeshEditor(false), newTestListener(false))
+
+		require.IsType(t, errorz.ValidationError{}, svc.DeleteModelResources(context.Background(), editorActor, ""))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		kept := testEntry("kept", 1, models.Mesh{}, models.Mesh{}, false)
+		kept.ModelID = "model2"
+
+		ts := newTestStore(false, testEntry("e", 1, models.Mesh{}, models.Mesh{}, false), kept)
+		svc := newTestService(ts, newTestMeshEditor(false), newTestListener(false))
+
+		require.NoError(t, svc.DeleteModelResources(context.Background(), editorActor, validModelID))
+		require.Equal(t, []string{"kept"}, keys(ts.entries))
+	})
+}
+
+func Test_checkElement(t *testing.T) {
+	t.Parallel()
+
+	b1 := testMesh.Nodes["b1"]
+	expected := map[string]models.Node{"b1": b1}
+	notFound := errorz.NewNotFoundError("not found")
+
+	require.NoError(t, checkElement("node", "b1", expected, b1, nil))
+	require.NoError(t, checkElement("node", "b2", expected, models.Node{}, notFound))
+	require.IsType(t, errorz.ConflictError{}, checkElement("node", "b1", expected, changedNode, nil))
+	require.IsType(t, errorz.ConflictError{}, checkElement("node", "b1", expected, models.Node{}, notFound))
+	require.IsType(t, errorz.ConflictError{}, checkElement("node", "b2", expected, b1, nil))
+	require.IsType(t, errorz.StoreError{},
+		checkElement("node", "b1", expected, models.Node{}, errorz.NewStoreError("forced-error")))
+}
+
+func withSeq(e history.Entry, seq int64) history.Entry {
+	e.Seq = seq
+
+	return e
+}
+
+func withUndone(e history.Entry, undone bool) history.Entry {
+	e.Undone = undone
+
+	return e
+}
+
+func keys(entries map[string]history.Entry) []string {
+	ids := make([]string, 0, len(entries))
+
+	for id := range entries {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	editorActor  = access.Actor{UserID: "editor", Role: access.RoleEditor}
+	otherActor   = access.Actor{UserID: "other", Role: access.RoleEditor}
+	guestActor   = access.Actor{UserID: "guest", Role: access.RoleGuest}
+	validModelID = "model1"
+	validEntryID = "1" // must match generated ID from testIDGenerator
+	testTime     = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// testMesh holds the buses b1 and b2 connected by the line r1.
+	testMesh = models.Mesh{
+		ModelID: validModelID,
+		Nodes: map[string]models.Node{
+			"b1": {ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10}}},
+			"b2": {ID: "b2", Kind: "bus", Code: "B2"},
+		},
+		Relations: map[string]models.Relation{
+			"r1": {ID: "r1", Kind: "line", From: "b1", To: "b2"},
+		},
+	}
+	// changedNode is the node b1 with a changed load.
+	changedNode = models.Node{ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 12.5}}}
+)
+
+type testIDGenerator struct {
+	idCounter atomic.Int64
+}
+
+// Ensure that the testIDGenerator implements the idGenerator interface.
+var _ idGenerator = (*testIDGenerator)(nil)
+
+func newTestIDGenerator() *testIDGenerator {
+	return &testIDGenerator{}
+}
+
+func (g *testIDGenerator) GenerateID() string {
+	return strconv.FormatInt(g.idCounter.Add(1), 10)
+}
+
+type testListener struct {
+	forcedError error
+	eventFired  history.Event
+}
+
+// Ensure that the testListener implements the listener interface.
+var _ listener = (*testListener)(nil)
+
+func newTestListener(forcedError bool) *testListener {
+	var err error
+
+	if forcedError {
+		err = errorz.NewGatewayError("forced-error")
+	}
+
+	return &testListener{forcedError: err}
+}
+
+func (l *testListener) HandleHistoryEvent(_ context.Context, event history.Event) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+// testStore keeps the entries in memory.
+type testStore struct {
+	forcedError error
+	entries     map[string]history.Entry
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...history.Entry) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		entries:     map[string]history.Entry{},
+	}
+
+	for _, e := range initial {
+		s.entries[e.ID] = e
+	}
+
+	return s
+}
+
+func (s *testStore) CreateEntry(_ context.Context, entry history.Entry) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.entries[entry.ID] = entry
+
+	return nil
+}
+
+func (s *testStore) UpdateEntry(_ context.Context, entry history.Entry) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.entries[entry.ID]; !ok {
+		return errorz.NewNotFoundError("entry %s not found", entry.ID)
+	}
+
+	s.entries[entry.ID] = entry
+
+	return nil
+}
+
+func (s *testStore) DeleteEntries(_ context.Context, ids []string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for _, id := range ids {
+		delete(s.entries, id)
+	}
+
+	return nil
+}
+
+func (s *testStore) DeleteModelEntries(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, e := range s.entries {
+		if e.ModelID == modelID {
+			delete(s.entries, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) GetEntries(_ context.Context, modelID, actorID string) ([]history.Entry, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []history.Entry
+
+	for _, e := range s.entries {
+		if e.ModelID == modelID && e.ActorID == actorID {
+			found = append(found, e)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b history.Entry) int { return cmp.Compare(a.Seq, b.Seq) })
+
+	return found, nil
+}
+
+// testMeshEditor keeps a copy of the test mesh in memory. Like the mesh service, it fires
+// the mesh contents events of its edits to the listener, if set.
+type testMeshEditor struct {
+	forcedError error
+	mesh        models.Mesh
+	listener    *HistoryService
+	calls       []string
+}
+
+// Ensure that the testMeshEditor implements the meshEditor interface.
+var _ meshEditor = (*testMeshEditor)(nil)
+
+func newTestMeshEditor(forcedError bool) *testMeshEditor {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testMeshEditor{
+		forcedError: err,
+		mesh: models.Mesh{
+			ModelID:   validModelID,
+			Nodes:     maps.Clone(testMesh.Nodes),
+			Relations: maps.Clone(testMesh.Relations),
+		},
+	}
+}
+
+func (e *testMeshEditor) GetNode(_ context.Context, _, nodeID string) (models.Node, error) {
+	node, ok := e.mesh.Nodes[nodeID]
+	if !ok {
+		return models.Node{}, errorz.NewNotFoundError("node %s not found", nodeID)
+	}
+
+	return node, nil
+}
+
+func (e *testMeshEditor) GetRelation(_ context.Context, _, relationID string) (models.Relation, error) {
+	relation, ok := e.mesh.Relations[relationID]
+	if !ok {
+		return models.Relation{}, errorz.NewNotFoundError("relation %s not found", relationID)
+	}
+
+	return relation, nil
+}
+
+func (e *testMeshEditor) RestoreNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	node models.Node,
+) (models.Node, error) {
+	if err := e.edit("restore-node " + node.ID); err != nil {
+		return models.Node{}, err
+	}
+
+	e.mesh.Nodes[node.ID] = node
+
+	return node, e.fire(ctx, actor, models.MeshContentsCreated, models.Mesh{}, testNodes(modelID, node))
+}
+
+func (e *testMeshEditor) UpdateNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, nodeID string,
+	data models.NodeData,
+) (models.Node, error) {
+	if err := e.edit("update-node " + nodeID); err != nil {
+		return models.Node{}, err
+	}
+
+	previous := e.mesh.Nodes[nodeID]
+	node := models.Node{ID: nodeID, Kind: data.Kind, Code: data.Code, Props: data.Props}
+	e.mesh.Nodes[nodeID] = node
+
+	return node, e.fire(ctx, actor, models.MeshContentsUpdated, testNodes(modelID, previous), testNodes(modelID, node))
+}
+
+func (e *testMeshEditor) DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error {
+	if err := e.edit("delete-node " + nodeID); err != nil {
+		return err
+	}
+
+	previous := e.mesh.Nodes[nodeID]
+	delete(e.mesh.Nodes, nodeID)
+
+	return e.fire(ctx, actor, models.MeshContentsDeleted, testNodes(modelID, previous), models.Mesh{})
+}
+
+func (e *testMeshEditor) RestoreRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	relation models.Relation,
+) (models.Relation, error) {
+	if err := e.edit("restore-relation " + relation.ID); err != nil {
+		return models.Relation{}, err
+	}
+
+	e.mesh.Relations[relation.ID] = relation
+
+	return relation, e.fire(ctx, actor, models.MeshContentsCreated, models.Mesh{}, testRelations(modelID, relation))
+}
+
+func (e *testMeshEditor) UpdateRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, relationID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if err := e.edit("update-relation " + relationID); err != nil {
+		return models.Relation{}, err
+	}
+
+	previous := e.mesh.Relations[relationID]
+	relation := models.Relation{ID: relationID, Kind: data.Kind, From: data.From, To: data.To, Props: data.Props}
+	e.mesh.Relations[relationID] = relation
+
+	return relation, e.fire(ctx, actor, models.MeshContentsUpdated,
+		testRelations(modelID, previous), testRelations(modelID, relation))
+}
+
+func (e *testMeshEditor) DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error {
+	if err := e.edit("delete-relation " + relationID); err != nil {
+		return err
+	}
+
+	previous := e.mesh.Relations[relationID]
+	delete(e.mesh.Relations, relationID)
+
+	return e.fire(ctx, actor, models.MeshContentsDeleted, testRelations(modelID, previous), models.Mesh{})
+}
+
+// edit records the call, or fails with the forced error.
+func (e *testMeshEditor) edit(call string) error {
+	if e.forcedError != nil {
+		return e.forcedError
+	}
+
+	e.calls = append(e.calls, call)
+
+	return nil
+}
+
+// fire passes the event of an edit to the listener. The deleted elements are reported by
+// their IDs, as done by the mesh service.
+func (e *testMeshEditor) fire(
+	ctx context.Context,
+	actor access.Actor,
+	eventType models.EventType,
+	previous, updates models.Mesh,
+) error {
+	if e.listener == nil {
+		return nil
+	}
+
+	event := models.MeshEvent{
+		EventHeader: models.EventHeader{Type: eventType, Actor: actor},
+		Updates:     updates,
+		Previous:    previous,
+	}
+
+	if eventType == models.MeshContentsDeleted {
+		event.Deletes = previous
+	}
+
+	return e.listener.HandleMeshEvent(ctx, event)
+}
+
+// testNodes returns a mesh of the model holding the nodes.
+func testNodes(modelID string, nodes ...models.Node) models.Mesh {
+	m := models.Mesh{ModelID: modelID, Nodes: map[string]models.Node{}}
+
+	for _, n := range nodes {
+		m.Nodes[n.ID] = n
+	}
+
+	return m
+}
+
+// testRelations returns a mesh of the model holding the relations.
+func testRelations(modelID string, relations ...models.Relation) models.Mesh {
+	m := models.Mesh{ModelID: modelID, Relations: map[string]models.Relation{}}
+
+	for _, r := range relations {
+		m.Relations[r.ID] = r
+	}
+
+	return m
+}
+
+// testEntry returns an entry of the editor actor in the test model.
+func testEntry(id string, seq int64, before, after models.Mesh, undone bool) history.Entry {
+	before.ModelID = validModelID
+	after.ModelID = validModelID
+
+	return history.Entry{
+		ID:        id,
+		ModelID:   validModelID,
+		ActorID:   editorActor.UserID,
+		Seq:       seq,
+		Before:    before,
+		After:     after,
+		Undone:    undone,
+		CreatedAt: testTime,
+	}
+}
+
+// testBindingProvider grants the editor and the other actor the editor role and the guest
+// the guest role on the valid model.
+type testBindingProvider struct{}
+
+func (testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	roles := map[string]access.Role{
+		editorActor.UserID: access.RoleEditor,
+		otherActor.UserID:  access.RoleEditor,
+		guestActor.UserID:  access.RoleGuest,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// newTestService returns a service running at the test time, registered as the listener of
+// the mesh editor.
+func newTestService(ts *testStore, te *testMeshEditor, tl *testListener, opts ...Option) *HistoryService {
+	opts = append([]Option{WithListener(tl)}, opts...)
+
+	svc := NewHistoryService(ts, newTestIDGenerator(), te, authz.NewAuthorizer(testBindingProvider{}), opts...)
+	svc.now = func() time.Time { return testTime }
+	te.listener = svc
+
+	return svc
+}
+
+func requireEventFired(t *testing.T, wantEvent history.EventType, listener *testListener) {
+	t.Helper()
+
+	eventFired := listener.eventFired
+
+	require.NotEmpty(t, eventFired)
+
+	ee, ok := history.ExtractEntryEvent(eventFired)
+
+	require.True(t, ok)
+
+	require.Equal(t, wantEvent, ee.Type)
+	require.NotEmpty(t, ee.Actor)
+	require.NotEmpty(t, ee.Entry)
+	require.NotEmpty(t, ee.Timestamp)
+}
@@ -0,0 +1,19 @@
+package service
+
+import "github.com/energimind/powermesh-core/errorz"
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateActor(actorID string) error {
+	return requireString(actorID, "actor user id")
+}
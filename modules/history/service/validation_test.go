@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateActor(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateActor("user1"))
+	require.Error(t, validateActor(""))
+}
@@ -0,0 +1,64 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/history/store/mongo"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testEntry returns an entry of the actor in the model recording the update of a node.
+func testEntry(id, modelID, actorID string, seq int64) history.Entry {
+	return history.Entry{
+		ID:      id,
+		ModelID: modelID,
+		ActorID: actorID,
+		Seq:     seq,
+		Before: models.Mesh{
+			ModelID: modelID,
+			Nodes:   map[string]models.Node{"n1": {ID: "n1", Kind: "bus", Code: "B1"}},
+		},
+		After: models.Mesh{
+			ModelID: modelID,
+			Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10.5}}},
+			},
+		},
+		CreatedAt: testTime.Add(time.Duration(seq) * time.Minute),
+	}
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.HistoryStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewHistoryStore(db)
+
+	f(t, ctx, store)
+}
@@ -0,0 +1,2 @@
+// Package mongo provides a MongoDB implementation of the history store.
+package mongo
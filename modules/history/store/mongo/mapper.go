@@ -0,0 +1,108 @@
+package mongo
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+	meshstore "github.com/energimind/powermesh-core/modules/models/store/mongo"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+func toStoreEntry(e history.Entry) storeEntry {
+	return storeEntry{
+		ID:        e.ID,
+		ModelID:   e.ModelID,
+		ActorID:   e.ActorID,
+		Seq:       e.Seq,
+		Before:    toStoreMesh(e.Before),
+		After:     toStoreMesh(e.After),
+		Undone:    e.Undone,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func fromStoreEntry(e storeEntry) history.Entry {
+	return history.Entry{
+		ID:        e.ID,
+		ModelID:   e.ModelID,
+		ActorID:   e.ActorID,
+		Seq:       e.Seq,
+		Before:    fromStoreMesh(e.ModelID, e.Before),
+		After:     fromStoreMesh(e.ModelID, e.After),
+		Undone:    e.Undone,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func toStoreMesh(m models.Mesh) storeMesh {
+	sm := storeMesh{
+		Nodes:     make([]storeNode, 0, len(m.Nodes)),
+		Relations: make([]storeRelation, 0, len(m.Relations)),
+	}
+
+	for _, n := range m.Nodes {
+		sm.Nodes = append(sm.Nodes, storeNode{
+			ID:    n.ID,
+			Kind:  n.Kind,
+			Code:  n.Code,
+			Props: meshstore.ToStoreProps(n.Props),
+		})
+	}
+
+	for _, r := range m.Relations {
+		sm.Relations = append(sm.Relations, storeRelation{
+			ID:    r.ID,
+			Kind:  r.Kind,
+			From:  r.From,
+			To:    r.To,
+			Props: meshstore.ToStoreProps(r.Props),
+		})
+	}
+
+	slices.SortFunc(sm.Nodes, func(a, b storeNode) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(sm.Relations, func(a, b storeRelation) int { return cmp.Compare(a.ID, b.ID) })
+
+	return sm
+}
+
+// fromStoreMesh returns the edited elements as a mesh of the model. Empty element lists
+// are returned as nil maps, as in the meshes of the entries recorded by the service.
+func fromStoreMesh(modelID string, sm storeMesh) models.Mesh {
+	m := models.Mesh{ModelID: modelID}
+
+	for _, n := range sm.Nodes {
+		if m.Nodes == nil {
+			m.Nodes = make(map[string]models.Node, len(sm.Nodes))
+		}
+
+		m.Nodes[n.ID] = models.Node{
+			ID:    n.ID,
+			Kind:  n.Kind,
+			Code:  n.Code,
+			Props: meshstore.FromStoreProps(n.Props),
+		}
+	}
+
+	for _, r := range sm.Relations {
+		if m.Relations == nil {
+			m.Relations = make(map[string]models.Relation, len(sm.Relations))
+		}
+
+		m.Relations[r.ID] = models.Relation{
+			ID:    r.ID,
+			Kind:  r.Kind,
+			From:  r.From,
+			To:    r.To,
+			Props: meshstore.FromStoreProps(r.Props),
+		}
+	}
+
+	return m
+}
+
+// entriesFilter builds the filter of the entries of the actor in the model.
+func entriesFilter(modelID, actorID string) q.Filter {
+	return q.Filter{}.EQ(fieldModelID, modelID).EQ(fieldActorID, actorID)
+}
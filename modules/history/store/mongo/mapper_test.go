@@ -0,0 +1,31 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreEntry, toStoreEntry(validEntry))
+	require.Equal(t, validEntry, fromStoreEntry(validStoreEntry))
+}
+
+func Test_fromStoreMesh(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, models.Mesh{ModelID: "model1"}, fromStoreMesh("model1", storeMesh{}))
+}
+
+func Test_entriesFilter(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		q.Filter{}.EQ(fieldModelID, "model1").EQ(fieldActorID, "user1"),
+		entriesFilter("model1", "user1"),
+	)
+}
@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// storeEntry represents a history entry in the MongoDB store.
+type storeEntry struct {
+	ID        string    `bson:"id"`
+	ModelID   string    `bson:"modelId"`
+	ActorID   string    `bson:"actorId"`
+	Seq       int64     `bson:"seq"`
+	Before    storeMesh `bson:"before"`
+	After     storeMesh `bson:"after"`
+	Undone    bool      `bson:"undone"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// storeMesh represents the edited elements of an entry in the MongoDB store.
+// The elements are kept as lists ordered by ID.
+type storeMesh struct {
+	Nodes     []storeNode     `bson:"nodes"`
+	Relations []storeRelation `bson:"relations"`
+}
+
+// storeNode represents an edited node in the MongoDB store.
+type storeNode struct {
+	ID    string         `bson:"id"`
+	Kind  string         `bson:"kind"`
+	Code  string         `bson:"code"`
+	Props models.PropBag `bson:"props"`
+}
+
+// storeRelation represents an edited relation in the MongoDB store.
+type storeRelation struct {
+	ID    string         `bson:"id"`
+	Kind  string         `bson:"kind"`
+	From  string         `bson:"from"`
+	To    string         `bson:"to"`
+	Props models.PropBag `bson:"props"`
+}
@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var (
+	validEntry = history.Entry{
+		ID:      "1",
+		ModelID: "model1",
+		ActorID: "user1",
+		Seq:     3,
+		Before: models.Mesh{
+			ModelID: "model1",
+			Nodes: map[string]models.Node{
+				"n2": {ID: "n2", Kind: "bus", Code: "B2"},
+				"n1": {ID: "n1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10.0}}},
+			},
+		},
+		After: models.Mesh{
+			ModelID: "model1",
+			Relations: map[string]models.Relation{
+				"r1": {ID: "r1", Kind: "line", From: "n1", To: "n2"},
+			},
+		},
+		Undone:    true,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	validStoreEntry = storeEntry{
+		ID:      validEntry.ID,
+		ModelID: validEntry.ModelID,
+		ActorID: validEntry.ActorID,
+		Seq:     validEntry.Seq,
+		Before: storeMesh{
+			Nodes: []storeNode{
+				{ID: "n1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10.0}}},
+				{ID: "n2", Kind: "bus", Code: "B2"},
+			},
+			Relations: []storeRelation{},
+		},
+		After: storeMesh{
+			Nodes: []storeNode{},
+			Relations: []storeRelation{
+				{ID: "r1", Kind: "line", From: "n1", To: "n2"},
+			},
+		},
+		Undone:    validEntry.Undone,
+		CreatedAt: validEntry.CreatedAt,
+	}
+)
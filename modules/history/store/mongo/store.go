@@ -0,0 +1,90 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/history"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	collHistory  = "history"
+	fieldID      = "id"
+	fieldModelID = "modelId"
+	fieldActorID = "actorId"
+	fieldSeq     = "seq"
+)
+
+// HistoryStore is a MongoDB implementation of the history store.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type HistoryStore struct {
+	entries *mongo.Collection
+}
+
+// NewHistoryStore creates a new MongoDB history store.
+func NewHistoryStore(db *mongo.Database) *HistoryStore {
+	return &HistoryStore{
+		entries: db.Collection(collHistory),
+	}
+}
+
+// EnsureIndexes creates the index of the entries by model, actor and sequence number if it
+// does not exist.
+func (s *HistoryStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.entries.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: fieldModelID, Value: 1}, {Key: fieldActorID, Value: 1}, {Key: fieldSeq, Value: 1}},
+	})
+	if err != nil {
+		return errorz.NewStoreError("failed to create indexes of %s: %v", collHistory, err)
+	}
+
+	return nil
+}
+
+// CreateEntry implements the history store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryStore) CreateEntry(ctx context.Context, entry history.Entry) error {
+	return q.CreateOne(s.entries, toStoreEntry).Exec(ctx, entry)
+}
+
+// UpdateEntry implements the history store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryStore) UpdateEntry(ctx context.Context, entry history.Entry) error {
+	return q.UpdateOne(s.entries, toStoreEntry).Exec(ctx, entry.ID, entry)
+}
+
+// DeleteEntries implements the history store interface.
+// Missing entries are ignored.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryStore) DeleteEntries(ctx context.Context, ids []string) error {
+	_, err := q.DeleteMany(s.entries).Exec(ctx, q.Filter{}.IN(fieldID, ids))
+
+	return err
+}
+
+// DeleteModelEntries implements the history store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryStore) DeleteModelEntries(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.entries).Exec(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+
+	return err
+}
+
+// GetEntries implements the history store interface.
+// The entries are returned in the order of their sequence numbers.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *HistoryStore) GetEntries(ctx context.Context, modelID, actorID string) ([]history.Entry, error) {
+	return q.FindMany(s.entries, fromStoreEntry).
+		WithSort(fieldSeq, false).
+		Exec(ctx, entriesFilter(modelID, actorID))
+}
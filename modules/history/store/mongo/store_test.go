@@ -0,0 +1,171 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/history"
+	"github.com/energimind/powermesh-core/modules/history/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func createEntries(t *testing.T, ctx context.Context, store *mongo.HistoryStore, es ...history.Entry) {
+	t.Helper()
+
+	for _, e := range es {
+		require.NoError(t, store.CreateEntry(ctx, e))
+	}
+}
+
+func entryIDs(found []history.Entry) []string {
+	ids := make([]string, 0, len(found))
+
+	for _, e := range found {
+		ids = append(ids, e.ID)
+	}
+
+	return ids
+}
+
+func TestHistoryStore_EnsureIndexes(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		require.NoError(t, store.EnsureIndexes(ctx))
+		require.NoError(t, store.EnsureIndexes(ctx))
+	})
+}
+
+func TestHistoryStore_CreateEntry(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		entry := testEntry("1", "model1", "user1", 1)
+
+		require.NoError(t, store.CreateEntry(ctx, entry))
+
+		found, err := store.GetEntries(ctx, "model1", "user1")
+
+		require.NoError(t, err)
+		require.Equal(t, []history.Entry{entry}, found)
+	})
+}
+
+func TestHistoryStore_UpdateEntry(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.UpdateEntry(ctx, testEntry("1", "model1", "user1", 1)))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			entry := testEntry("1", "model1", "user1", 1)
+
+			require.NoError(t, store.CreateEntry(ctx, entry))
+
+			entry.Undone = true
+
+			require.NoError(t, store.UpdateEntry(ctx, entry))
+
+			found, err := store.GetEntries(ctx, "model1", "user1")
+
+			require.NoError(t, err)
+			require.Equal(t, []history.Entry{entry}, found)
+		})
+	})
+}
+
+func TestHistoryStore_DeleteEntries(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		createEntries(t, ctx, store,
+			testEntry("1", "model1", "user1", 1),
+			testEntry("2", "model1", "user1", 2),
+			testEntry("3", "model1", "user1", 3),
+		)
+
+		require.NoError(t, store.DeleteEntries(ctx, []string{"1", "3", "missing"}))
+
+		found, err := store.GetEntries(ctx, "model1", "user1")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"2"}, entryIDs(found))
+	})
+}
+
+func TestHistoryStore_DeleteModelEntries(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		createEntries(t, ctx, store,
+			testEntry("1", "model1", "user1", 1),
+			testEntry("2", "model1", "user2", 1),
+			testEntry("3", "model2", "user1", 1),
+		)
+
+		require.NoError(t, store.DeleteModelEntries(ctx, "model1"))
+
+		found, err := store.GetEntries(ctx, "model1", "user2")
+
+		require.NoError(t, err)
+		require.Empty(t, found)
+
+		found, err = store.GetEntries(ctx, "model2", "user1")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"3"}, entryIDs(found))
+	})
+}
+
+func TestHistoryStore_GetEntries(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.HistoryStore) {
+		createEntries(t, ctx, store,
+			testEntry("3", "model1", "user1", 3),
+			testEntry("1", "model1", "user1", 1),
+			testEntry("2", "model1", "user1", 2),
+			testEntry("4", "model1", "user2", 1),
+			testEntry("5", "model2", "user1", 1),
+		)
+
+		tests := map[string]struct {
+			modelID string
+			actorID string
+			wantIDs []string
+		}{
+			"ordered": {
+				modelID: "model1",
+				actorID: "user1",
+				wantIDs: []string{"1", "2", "3"},
+			},
+			"other-actor": {
+				modelID: "model1",
+				actorID: "user2",
+				wantIDs: []string{"4"},
+			},
+			"other-model": {
+				modelID: "model2",
+				actorID: "user1",
+				wantIDs: []string{"5"},
+			},
+			"none": {
+				modelID: "model3",
+				actorID: "user1",
+				wantIDs: []string{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				found, err := store.GetEntries(ctx, test.modelID, test.actorID)
+
+				require.NoError(t, err)
+				require.Equal(t, test.wantIDs, entryIDs(found))
+			})
+		}
+	})
+}
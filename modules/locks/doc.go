@@ -0,0 +1,3 @@
+// Package locks provides a model and service for managing advisory edit locks on the
+// elements of a mesh.
+package locks
@@ -0,0 +1,49 @@
+package locks
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// EventType is the type of event that occurred.
+type EventType string
+
+// Event types.
+const (
+	LockAcquired EventType = "lock.acquired"
+	LockRenewed  EventType = "lock.renewed"
+	LockReleased EventType = "lock.released"
+)
+
+// Event models an event that occurs in the lock service.
+type Event interface {
+	IsLockEvent() bool
+}
+
+// EventHeader models the header of an event.
+type EventHeader struct {
+	Type      EventType
+	Actor     access.Actor
+	Timestamp time.Time
+}
+
+// LockEvent models an event that occurs in the lock service related to a lock.
+type LockEvent struct {
+	EventHeader
+	Lock Lock
+}
+
+// IsLockEvent implements the Event interface.
+func (LockEvent) IsLockEvent() bool {
+	return true
+}
+
+// ExtractLockEvent extracts a lock event from an event.
+func ExtractLockEvent(e Event) (LockEvent, bool) {
+	if le, ok := e.(LockEvent); ok {
+		return le, true
+	}
+
+	return LockEvent{}, false
+}
@@ -0,0 +1,26 @@
+package locks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLockEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		le, ok := ExtractLockEvent(LockEvent{EventHeader: EventHeader{Type: LockAcquired}})
+
+		require.True(t, ok)
+		require.NotZero(t, le)
+		require.True(t, le.IsLockEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		le, ok := ExtractLockEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, le)
+	})
+}
@@ -0,0 +1,78 @@
+package locks
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Lock represents an advisory edit lock on an element of a mesh.
+//
+// The lock covers the locked element and, for containers, the contained nodes and the
+// relations among them, as they were when the lock was acquired. It expires at the given
+// time unless it is renewed.
+type Lock struct {
+	ID          string
+	ModelID     string
+	Target      Target
+	HolderID    string   // user ID of the actor holding the lock
+	NodeIDs     []string // public IDs of the covered nodes
+	RelationIDs []string // public IDs of the covered relations
+	AcquiredAt  time.Time
+	ExpiresAt   time.Time
+}
+
+// Target represents the element a lock is taken on.
+type Target struct {
+	Type TargetType
+	ID   string // public ID of the node, relation or container node
+}
+
+// IsExpired checks if the lock has expired at the given time.
+func (l Lock) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// CoversNode checks if the lock covers the node.
+func (l Lock) CoversNode(nodeID string) bool {
+	return slices.Contains(l.NodeIDs, nodeID)
+}
+
+// CoversRelation checks if the lock covers the relation.
+func (l Lock) CoversRelation(relationID string) bool {
+	return slices.Contains(l.RelationIDs, relationID)
+}
+
+// TargetType represents the type of element a lock is taken on.
+type TargetType int
+
+// TargetType enumeration.
+const (
+	TargetNode TargetType = iota
+	TargetRelation
+	TargetContainer
+)
+
+// AllTargetTypes is a list of all target types. Used for testing purposes to validate that
+// all enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllTargetTypes = []TargetType{
+	TargetNode,
+	TargetRelation,
+	TargetContainer,
+}
+
+// String returns the string representation of the target type.
+func (t TargetType) String() string {
+	switch t {
+	case TargetNode:
+		return "node"
+	case TargetRelation:
+		return "relation"
+	case TargetContainer:
+		return "container"
+	}
+
+	return "TargetType(" + strconv.Itoa(int(t)) + ")"
+}
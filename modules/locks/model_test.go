@@ -0,0 +1,48 @@
+package locks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lock := Lock{ExpiresAt: now}
+
+	require.False(t, lock.IsExpired(now.Add(-time.Second)))
+	require.True(t, lock.IsExpired(now))
+	require.True(t, lock.IsExpired(now.Add(time.Second)))
+}
+
+func TestLock_Covers(t *testing.T) {
+	t.Parallel()
+
+	lock := Lock{NodeIDs: []string{"n1", "n2"}, RelationIDs: []string{"r1"}}
+
+	require.True(t, lock.CoversNode("n2"))
+	require.False(t, lock.CoversNode("r1"))
+	require.True(t, lock.CoversRelation("r1"))
+	require.False(t, lock.CoversRelation("n1"))
+}
+
+func TestTargetType_String(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range AllTargetTypes {
+		t.Run(tt.String(), func(t *testing.T) {
+			require.NotEmpty(t, tt.String())
+			require.False(t, strings.HasPrefix(tt.String(), "TargetType("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		tt := TargetType(100)
+
+		require.Equal(t, "TargetType(100)", tt.String())
+	})
+}
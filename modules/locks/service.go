@@ -0,0 +1,31 @@
+package locks
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// LockService defines the lock service.
+type LockService interface {
+	AcquireLock(ctx context.Context, actor access.Actor, data LockData) (Lock, error)
+	RenewLock(ctx context.Context, actor access.Actor, id string, ttl time.Duration) (Lock, error)
+	ReleaseLock(ctx context.Context, actor access.Actor, id string) error
+	GetLock(ctx context.Context, id string) (Lock, error)
+	ListLocks(ctx context.Context, modelID string) ([]Lock, error)
+	CheckNodeLock(ctx context.Context, actor access.Actor, modelID, nodeID string) error
+	CheckRelationLock(ctx context.Context, actor access.Actor, modelID, relationID string) error
+	CheckMeshLock(ctx context.Context, actor access.Actor, modelID string, nodes, relations bool) error
+}
+
+// LockData defines the lock data. It is used to acquire a lock.
+//
+// Container locks cover the nodes reached from the container over relations of the
+// containment kinds. If no time to live is given, the default of the service applies.
+type LockData struct {
+	ModelID          string
+	Target           Target
+	ContainmentKinds []string      // relation kinds leading from a container to its contents
+	TTL              time.Duration // time to live (optional)
+}
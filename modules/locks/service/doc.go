@@ -0,0 +1,2 @@
+// Package service implements the lock service.
+package service
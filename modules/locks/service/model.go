@@ -0,0 +1,28 @@
+package service
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/locks"
+)
+
+func lockFromData(
+	id string,
+	actor access.Actor,
+	data locks.LockData,
+	covered coverage,
+	now time.Time,
+	ttl time.Duration,
+) locks.Lock {
+	return locks.Lock{
+		ID:          id,
+		ModelID:     data.ModelID,
+		Target:      data.Target,
+		HolderID:    actor.UserID,
+		NodeIDs:     covered.nodeIDs,
+		RelationIDs: covered.relationIDs,
+		AcquiredAt:  now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lockFromData(t *testing.T) {
+	covered := coverage{nodeIDs: []string{"b1", "b2", "s1"}, relationIDs: []string{"r1"}}
+
+	require.Equal(t,
+		locks.Lock{
+			ID:          validLockID,
+			ModelID:     validContainerData.ModelID,
+			Target:      validContainerData.Target,
+			HolderID:    holderActor.UserID,
+			NodeIDs:     covered.nodeIDs,
+			RelationIDs: covered.relationIDs,
+			AcquiredAt:  testTime,
+			ExpiresAt:   testTime.Add(time.Minute),
+		},
+		lockFromData(validLockID, holderActor, validContainerData, covered, testTime, time.Minute),
+	)
+}
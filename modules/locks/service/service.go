@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+const (
+	// defaultTTL is the default time to live of a lock.
+	defaultTTL = 5 * time.Minute
+
+	// defaultMaxTTL is the default longest time to live of a lock.
+	defaultMaxTTL = time.Hour
+)
+
+// idGenerator defines the external ID generator.
+type idGenerator interface {
+	GenerateID() string
+}
+
+// store defines the external lock store.
+//
+// The store removes expired locks on its own, but not immediately; GetLocks must only
+// return the locks that have not expired at the given time.
+type store interface {
+	CreateLock(ctx context.Context, lock locks.Lock) error
+	UpdateLock(ctx context.Context, lock locks.Lock) error
+	DeleteLock(ctx context.Context, id string) error
+	DeleteModelLocks(ctx context.Context, modelID string) error
+	GetLock(ctx context.Context, id string) (locks.Lock, error)
+	GetLocks(ctx context.Context, modelID string, now time.Time) ([]locks.Lock, error)
+}
+
+// listener defines the external lock event listener.
+type listener interface {
+	HandleLockEvent(ctx context.Context, event locks.Event) error
+}
+
+// meshProvider defines the external provider of mesh elements.
+// It is implemented by the mesh service.
+type meshProvider interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	GetRelation(ctx context.Context, modelID, relationID string) (models.Relation, error)
+	ExtractSubmesh(ctx context.Context, modelID string, spec models.SliceSpec) (models.Mesh, error)
+}
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+	IsAdmin(actor access.Actor) bool
+}
+
+// LockService implements the lock service.
+//
+// It implements the locks.LockService interface.
+//
+// Locks are advisory: they do not stop writes by themselves, but the mesh service refuses
+// to change or delete elements locked by another actor if it is configured with the lock
+// service as its lock checker. Locks are acquired by actors with write access to the
+// model. An actor acquiring a lock on an element it already holds a lock on renews that
+// lock. Locks can be released by their holders and by admins; expired locks are removed by
+// the store without an event.
+//
+// Acquiring is not atomic: two actors acquiring overlapping locks at the same moment can
+// both succeed. This is acceptable for advisory locks, which guard against accidental
+// overwrites rather than enforce exclusive access.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type LockService struct {
+	idGen      idGenerator
+	store      store
+	meshes     meshProvider
+	authz      authorizer
+	listener   listener
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	now        func() time.Time
+}
+
+// Ensure LockService implements the locks.LockService interface.
+var _ locks.LockService = (*LockService)(nil)
+
+// NewLockService creates a new lock service.
+func NewLockService(
+	store store,
+	idGen idGenerator,
+	meshes meshProvider,
+	authz authorizer,
+	opts ...Option,
+) *LockService {
+	svc := &LockService{
+		idGen:      idGen,
+		store:      store,
+		meshes:     meshes,
+		authz:      authz,
+		defaultTTL: defaultTTL,
+		maxTTL:     defaultMaxTTL,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// AcquireLock implements the locks.LockService interface.
+//
+// It refuses with a conflict error if another actor holds a lock covering any of the
+// elements the new lock would cover.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) AcquireLock(
+	ctx context.Context,
+	actor access.Actor,
+	data locks.LockData,
+) (locks.Lock, error) {
+	if err := validateLockData(data, s.maxTTL); err != nil {
+		return locks.Lock{}, err
+	}
+
+	if err := s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, data.ModelID,
+		access.PermissionWrite); err != nil {
+		return locks.Lock{}, err
+	}
+
+	covered, err := s.resolveCoverage(ctx, data)
+	if err != nil {
+		return locks.Lock{}, err
+	}
+
+	now := s.now()
+
+	active, err := s.store.GetLocks(ctx, data.ModelID, now)
+	if err != nil {
+		return locks.Lock{}, err
+	}
+
+	var own *locks.Lock
+
+	for i, lock := range active {
+		if lock.HolderID == actor.UserID {
+			if lock.Target == data.Target {
+				own = &active[i]
+			}
+
+			continue
+		}
+
+		if err := conflictError(lock, covered); err != nil {
+			return locks.Lock{}, err
+		}
+	}
+
+	if own != nil {
+		own.NodeIDs = covered.nodeIDs
+		own.RelationIDs = covered.relationIDs
+		own.ExpiresAt = now.Add(s.ttl(data.TTL))
+
+		return s.saveLock(ctx, actor, locks.LockRenewed, *own)
+	}
+
+	lock := lockFromData(s.idGen.GenerateID(), actor, data, covered, now, s.ttl(data.TTL))
+
+	if err := s.store.CreateLock(ctx, lock); err != nil {
+		return locks.Lock{}, err
+	}
+
+	if err := s.fireLockEvent(ctx, actor, locks.LockAcquired, lock); err != nil {
+		return locks.Lock{}, err
+	}
+
+	return lock, nil
+}
+
+// RenewLock implements the locks.LockService interface.
+// Only the holder can renew a lock, and only before it expires.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) RenewLock(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	ttl time.Duration,
+) (locks.Lock, error) {
+	if err := validateID(id); err != nil {
+		return locks.Lock{}, err
+	}
+
+	if err := validateTTL(ttl, s.maxTTL); err != nil {
+		return locks.Lock{}, err
+	}
+
+	lock, err := s.GetLock(ctx, id)
+	if err != nil {
+		return locks.Lock{}, err
+	}
+
+	if lock.HolderID != actor.UserID {
+		return locks.Lock{}, errorz.NewAccessDeniedError("user %s does not hold lock %s", actor.UserID, id)
+	}
+
+	lock.ExpiresAt = s.now().Add(s.ttl(ttl))
+
+	return s.saveLock(ctx, actor, locks.LockRenewed, lock)
+}
+
+// ReleaseLock implements the locks.LockService interface.
+// Locks can be released by their holders and by admins.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) ReleaseLock(ctx context.Context, actor access.Actor, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	lock, err := s.store.GetLock(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if lock.HolderID != actor.UserID && !s.authz.IsAdmin(actor) {
+		return errorz.NewAccessDeniedError("user %s does not hold lock %s", actor.UserID, id)
+	}
+
+	if err := s.store.DeleteLock(ctx, id); err != nil {
+		return err
+	}
+
+	return s.fireLockEvent(ctx, actor, locks.LockReleased, lock)
+}
+
+// GetLock implements the locks.LockService interface.
+// Expired locks are reported as not found.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) GetLock(ctx context.Context, id string) (locks.Lock, error) {
+	if err := validateID(id); err != nil {
+		return locks.Lock{}, err
+	}
+
+	lock, err := s.store.GetLock(ctx, id)
+	if err != nil {
+		return locks.Lock{}, err
+	}
+
+	if lock.IsExpired(s.now()) {
+		return locks.Lock{}, errorz.NewNotFoundError("lock %s has expired", id)
+	}
+
+	return lock, nil
+}
+
+// ListLocks implements the locks.LockService interface.
+// It returns the active locks of the model in the order they were acquired.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) ListLocks(ctx context.Context, modelID string) ([]locks.Lock, error) {
+	if err := validateModelID(modelID); err != nil {
+		return nil, err
+	}
+
+	active, err := s.store.GetLocks(ctx, modelID, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	return active, nil
+}
+
+// CheckNodeLock implements the locks.LockService interface.
+// It returns a conflict error if another actor holds a lock covering the node.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) CheckNodeLock(ctx context.Context, actor access.Actor, modelID, nodeID string) error {
+	return s.checkLock(ctx, actor, modelID, coverage{nodeIDs: []string{nodeID}})
+}
+
+// CheckRelationLock implements the locks.LockService interface.
+// It returns a conflict error if another actor holds a lock covering the relation.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) CheckRelationLock(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, relationID string,
+) error {
+	return s.checkLock(ctx, actor, modelID, coverage{relationIDs: []string{relationID}})
+}
+
+// CheckMeshLock implements the locks.LockService interface.
+// It returns a conflict error if another actor holds a lock covering any node of the model,
+// if nodes is set, or any relation of the model, if relations is set. The active locks are
+// read once, so bulk writes can be checked without a round trip per element.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) CheckMeshLock(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	nodes, relations bool,
+) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	active, err := s.store.GetLocks(ctx, modelID, s.now())
+	if err != nil {
+		return err
+	}
+
+	for _, lock := range active {
+		if lock.HolderID == actor.UserID {
+			continue
+		}
+
+		if nodes && len(lock.NodeIDs) > 0 {
+			return lockedError(lock, "node", lock.NodeIDs[0])
+		}
+
+		if relations && len(lock.RelationIDs) > 0 {
+			return lockedError(lock, "relation", lock.RelationIDs[0])
+		}
+	}
+
+	return nil
+}
+
+// DeleteModelResources removes all locks of the model.
+// It makes the service a deletion participant of the cascading model deletion.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelLocks(ctx, modelID)
+}
+
+// coverage holds the elements covered by a lock.
+type coverage struct {
+	nodeIDs     []string
+	relationIDs []string
+}
+
+// resolveCoverage returns the elements covered by a lock on the target. The target must
+// exist; the contents of a container are taken from the current mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) resolveCoverage(ctx context.Context, data locks.LockData) (coverage, error) {
+	switch data.Target.Type {
+	case locks.TargetNode:
+		if _, err := s.meshes.GetNode(ctx, data.ModelID, data.Target.ID); err != nil {
+			return coverage{}, err
+		}
+
+		return coverage{nodeIDs: []string{data.Target.ID}}, nil
+	case locks.TargetRelation:
+		if _, err := s.meshes.GetRelation(ctx, data.ModelID, data.Target.ID); err != nil {
+			return coverage{}, err
+		}
+
+		return coverage{relationIDs: []string{data.Target.ID}}, nil
+	case locks.TargetContainer:
+		contents, err := s.meshes.ExtractSubmesh(ctx, data.ModelID, models.SliceSpec{
+			Container:        data.Target.ID,
+			ContainmentKinds: data.ContainmentKinds,
+		})
+		if err != nil {
+			return coverage{}, err
+		}
+
+		return coverage{
+			nodeIDs:     models.SortedKeys(contents.Nodes),
+			relationIDs: models.SortedKeys(contents.Relations),
+		}, nil
+	}
+
+	return coverage{}, errorz.NewValidationError("invalid lock target type: %v", data.Target.Type)
+}
+
+// checkLock returns a conflict error if another actor holds a lock covering any of the
+// elements.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) checkLock(ctx context.Context, actor access.Actor, modelID string, covered coverage) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	active, err := s.store.GetLocks(ctx, modelID, s.now())
+	if err != nil {
+		return err
+	}
+
+	for _, lock := range active {
+		if lock.HolderID == actor.UserID {
+			continue
+		}
+
+		if err := conflictError(lock, covered); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conflictError returns a conflict error if the lock covers any of the elements.
+func conflictError(lock locks.Lock, covered coverage) error {
+	for _, id := range covered.nodeIDs {
+		if lock.CoversNode(id) {
+			return lockedError(lock, "node", id)
+		}
+	}
+
+	for _, id := range covered.relationIDs {
+		if lock.CoversRelation(id) {
+			return lockedError(lock, "relation", id)
+		}
+	}
+
+	return nil
+}
+
+// lockedError returns the conflict error reporting the element as locked by the lock.
+func lockedError(lock locks.Lock, element, id string) error {
+	return errorz.NewConflictError("%s %s is locked by user %s until %s",
+		element, id, lock.HolderID, lock.ExpiresAt.Format(time.RFC3339))
+}
+
+// ttl returns the time to live to use for a lock.
+func (s *LockService) ttl(requested time.Duration) time.Duration {
+	if requested == 0 {
+		return s.defaultTTL
+	}
+
+	return requested
+}
+
+// saveLock stores the changed lock and fires the event.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockService) saveLock(
+	ctx context.Context,
+	actor access.Actor,
+	eventType locks.EventType,
+	lock locks.Lock,
+) (locks.Lock, error) {
+	if err := s.store.UpdateLock(ctx, lock); err != nil {
+		return locks.Lock{}, err
+	}
+
+	if err := s.fireLockEvent(ctx, actor, eventType, lock); err != nil {
+		return locks.Lock{}, err
+	}
+
+	return lock, nil
+}
+
+// fireLockEvent fires a lock event.
+func (s *LockService) fireLockEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType locks.EventType,
+	lock locks.Lock,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := locks.LockEvent{
+		EventHeader: locks.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Lock: lock,
+	}
+
+	if err := s.listener.HandleLockEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import "time"
+
+// Option defines the option for the service.
+type Option func(service *LockService)
+
+// WithListener sets the listener for the service.
+func WithListener(listener listener) Option {
+	return func(s *LockService) {
+		s.listener = listener
+	}
+}
+
+// WithDefaultTTL sets the time to live of locks acquired without one.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(s *LockService) {
+		s.defaultTTL = ttl
+	}
+}
+
+// WithMaxTTL sets the longest time to live a lock can be acquired or renewed for.
+func WithMaxTTL(ttl time.Duration) Option {
+	return func(s *LockService) {
+		s.maxTTL = ttl
+	}
+}
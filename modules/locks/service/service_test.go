@@ -0,0 +1,587 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockService_AcquireLock(t *testing.T) {
+	t.Parallel()
+
+	otherLock := testLock("other", "b2", time.Hour)
+	otherLock.HolderID = otherActor.UserID
+
+	expiredLock := testLock("expired", validNodeID, time.Second)
+	expiredLock.HolderID = otherActor.UserID
+
+	tests := map[string]struct {
+		data          locks.LockData
+		initial       []locks.Lock
+		storeError    bool
+		listenerError bool
+		wantEvent     locks.EventType
+		wantID        string
+		wantNodes     []string
+		wantRelations []string
+		wantExpiresAt time.Time
+		wantErr       error
+	}{
+		"invalid-data": {
+			data:    locks.LockData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"ttl-too-long": {
+			data: locks.LockData{
+				ModelID: validModelID,
+				Target:  validNodeData.Target,
+				TTL:     2 * time.Hour,
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"target-not-found": {
+			data: locks.LockData{
+				ModelID: validModelID,
+				Target:  locks.Target{Type: locks.TargetRelation, ID: "missing"},
+			},
+			wantErr: errorz.NotFoundError{},
+		},
+		"locked-by-other": {
+			data:    validContainerData,
+			initial: []locks.Lock{otherLock},
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			data:       validNodeData,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			data:          validNodeData,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-node": {
+			data:          validNodeData,
+			initial:       []locks.Lock{otherLock, expiredLock},
+			wantEvent:     locks.LockAcquired,
+			wantID:        validLockID,
+			wantNodes:     []string{validNodeID},
+			wantExpiresAt: testTime.Add(time.Minute + defaultTTL),
+		},
+		"success-relation": {
+			data: locks.LockData{
+				ModelID: validModelID,
+				Target:  locks.Target{Type: locks.TargetRelation, ID: validRelationID},
+				TTL:     time.Minute,
+			},
+			initial:       []locks.Lock{otherLock},
+			wantEvent:     locks.LockAcquired,
+			wantID:        validLockID,
+			wantRelations: []string{validRelationID},
+			wantExpiresAt: testTime.Add(2 * time.Minute),
+		},
+		"success-container": {
+			data:          validContainerData,
+			wantEvent:     locks.LockAcquired,
+			wantID:        validLockID,
+			wantNodes:     []string{"b1", "b2", "s1"},
+			wantRelations: []string{"c1", "c2", "r1"},
+			wantExpiresAt: testTime.Add(time.Minute + defaultTTL),
+		},
+		"success-own-container-overlap": {
+			data:          validContainerData,
+			initial:       []locks.Lock{testLock("own", validNodeID, time.Hour)},
+			wantEvent:     locks.LockAcquired,
+			wantID:        validLockID,
+			wantNodes:     []string{"b1", "b2", "s1"},
+			wantRelations: []string{"c1", "c2", "r1"},
+			wantExpiresAt: testTime.Add(time.Minute + defaultTTL),
+		},
+		"success-renew-own": {
+			data:          validNodeData,
+			initial:       []locks.Lock{testLock("own", validNodeID, time.Hour)},
+			wantEvent:     locks.LockRenewed,
+			wantID:        "own",
+			wantNodes:     []string{validNodeID},
+			wantExpiresAt: testTime.Add(time.Minute + defaultTTL),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, test.initial...)
+			tl := newTestListener(test.listenerError)
+
+			lock, err := newTestService(ts, tl).AcquireLock(context.Background(), holderActor, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, lock)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantID, lock.ID)
+			require.Equal(t, test.data.Target, lock.Target)
+			require.Equal(t, holderActor.UserID, lock.HolderID)
+			require.Equal(t, test.wantNodes, lock.NodeIDs)
+			require.Equal(t, test.wantRelations, lock.RelationIDs)
+			require.Equal(t, test.wantExpiresAt, lock.ExpiresAt)
+			require.Equal(t, lock, ts.locks[lock.ID])
+			requireEventFired(t, test.wantEvent, tl)
+		})
+	}
+}
+
+func TestLockService_AcquireLock_access(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor   access.Actor
+		wantErr error
+	}{
+		"guest": {
+			actor:   guestActor,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"no-binding": {
+			actor:   strangerActor,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"admin": {
+			actor: adminActor,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false)
+
+			lock, err := newTestService(ts, newTestListener(false)).
+				AcquireLock(context.Background(), test.actor, validNodeData)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, lock)
+				require.Empty(t, ts.locks)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.actor.UserID, lock.HolderID)
+		})
+	}
+}
+
+func TestLockService_RenewLock(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor   access.Actor
+		id      string
+		ttl     time.Duration
+		lock    locks.Lock
+		wantErr error
+	}{
+		"invalid-id": {
+			actor:   holderActor,
+			lock:    testLock("1", validNodeID, time.Hour),
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-ttl": {
+			actor:   holderActor,
+			id:      "1",
+			ttl:     -time.Minute,
+			lock:    testLock("1", validNodeID, time.Hour),
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   holderActor,
+			id:      "missing",
+			lock:    testLock("1", validNodeID, time.Hour),
+			wantErr: errorz.NotFoundError{},
+		},
+		"expired": {
+			actor:   holderActor,
+			id:      "1",
+			lock:    testLock("1", validNodeID, time.Minute),
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-holder": {
+			actor:   adminActor,
+			id:      "1",
+			lock:    testLock("1", validNodeID, time.Hour),
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"success": {
+			actor: holderActor,
+			id:    "1",
+			ttl:   10 * time.Minute,
+			lock:  testLock("1", validNodeID, 2*time.Minute),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, test.lock)
+			tl := newTestListener(false)
+
+			lock, err := newTestService(ts, tl).RenewLock(context.Background(), test.actor, test.id, test.ttl)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, lock)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testTime.Add(11*time.Minute), lock.ExpiresAt)
+				require.Equal(t, lock, ts.locks[lock.ID])
+				requireEventFired(t, locks.LockRenewed, tl)
+			}
+		})
+	}
+}
+
+func TestLockService_ReleaseLock(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   holderActor,
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   holderActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"not-holder": {
+			actor:   otherActor,
+			id:      "1",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"store-error": {
+			actor:      holderActor,
+			id:         "1",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			actor:         holderActor,
+			id:            "1",
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success-holder": {
+			actor: holderActor,
+			id:    "1",
+		},
+		"success-admin": {
+			actor: adminActor,
+			id:    "1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(test.storeError, testLock("1", validNodeID, time.Hour))
+			tl := newTestListener(test.listenerError)
+
+			err := newTestService(ts, tl).ReleaseLock(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				require.Empty(t, ts.locks)
+				requireEventFired(t, locks.LockReleased, tl)
+			}
+		})
+	}
+}
+
+func TestLockService_ReleaseLock_configuredAdmin(t *testing.T) {
+	t.Parallel()
+
+	matrix, hierarchy, err := access.NewMatrixFromConfig(access.MatrixConfig{Roles: []access.RoleDefinition{
+		{ID: 70, Name: "locks-superuser", Includes: []string{"admin"}},
+	}})
+
+	require.NoError(t, err)
+
+	ts := newTestStore(false, testLock("1", validNodeID, time.Hour))
+	tl := newTestListener(false)
+	svc := NewLockService(ts, newTestIDGenerator(), testMeshProvider{},
+		authz.NewAuthorizer(testBindingProvider{}, authz.WithMatrix(matrix, hierarchy)), WithListener(tl))
+
+	require.NoError(t, svc.ReleaseLock(context.Background(), access.Actor{UserID: "superuser", Role: 70}, "1"))
+	require.Empty(t, ts.locks)
+}
+
+func TestLockService_GetLock(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id      string
+		wantErr error
+	}{
+		"invalid-id": {
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"expired": {
+			id:      "expired",
+			wantErr: errorz.NotFoundError{},
+		},
+		"success": {
+			id: "1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestStore(false, testLock("1", validNodeID, time.Hour), testLock("expired", "b2", time.Second))
+
+			lock, err := newTestService(ts, newTestListener(false)).GetLock(context.Background(), test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, lock)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, ts.locks[test.id], lock)
+			}
+		})
+	}
+}
+
+func TestLockService_ListLocks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid-model-id", func(t *testing.T) {
+		_, err := newTestService(newTestStore(false), newTestListener(false)).ListLocks(context.Background(), "")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("store-error", func(t *testing.T) {
+		_, err := newTestService(newTestStore(true), newTestListener(false)).
+			ListLocks(context.Background(), validModelID)
+
+		require.IsType(t, errorz.StoreError{}, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ts := newTestStore(false, testLock("1", validNodeID, time.Hour), testLock("expired", "b2", time.Second))
+
+		found, err := newTestService(ts, newTestListener(false)).ListLocks(context.Background(), validModelID)
+
+		require.NoError(t, err)
+		require.Equal(t, []locks.Lock{ts.locks["1"]}, found)
+	})
+}
+
+func TestLockService_CheckLock(t *testing.T) {
+	t.Parallel()
+
+	container := locks.Lock{
+		ID:          "1",
+		ModelID:     validModelID,
+		Target:      validContainerData.Target,
+		HolderID:    holderActor.UserID,
+		NodeIDs:     []string{"b1", "b2", "s1"},
+		RelationIDs: []string{"c1", "c2", "r1"},
+		AcquiredAt:  testTime,
+		ExpiresAt:   testTime.Add(time.Hour),
+	}
+
+	tests := map[string]struct {
+		actor      access.Actor
+		modelID    string
+		nodeID     string
+		relationID string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-model-id": {
+			actor:   otherActor,
+			nodeID:  "b1",
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			actor:      otherActor,
+			modelID:    validModelID,
+			nodeID:     "b1",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"locked-node": {
+			actor:   otherActor,
+			modelID: validModelID,
+			nodeID:  "b2",
+			wantErr: errorz.ConflictError{},
+		},
+		"locked-relation": {
+			actor:      otherActor,
+			modelID:    validModelID,
+			relationID: "r1",
+			wantErr:    errorz.ConflictError{},
+		},
+		"free-node": {
+			actor:   otherActor,
+			modelID: validModelID,
+			nodeID:  "b3",
+		},
+		"free-relation": {
+			actor:      otherActor,
+			modelID:    validModelID,
+			relationID: "r2",
+		},
+		"other-model": {
+			actor:   otherActor,
+			modelID: "model2",
+			nodeID:  "b1",
+		},
+		"holder": {
+			actor:   holderActor,
+			modelID: validModelID,
+			nodeID:  "b1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := newTestService(newTestStore(test.storeError, container), newTestListener(false))
+
+			var err error
+
+			if test.relationID != "" {
+				err = svc.CheckRelationLock(context.Background(), test.actor, test.modelID, test.relationID)
+			} else {
+				err = svc.CheckNodeLock(context.Background(), test.actor, test.modelID, test.nodeID)
+			}
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLockService_CheckMeshLock(t *testing.T) {
+	t.Parallel()
+
+	nodeLock := locks.Lock{
+		ID:         "1",
+		ModelID:    validModelID,
+		Target:     locks.Target{Type: locks.TargetNode, ID: "b1"},
+		HolderID:   holderActor.UserID,
+		NodeIDs:    []string{"b1"},
+		AcquiredAt: testTime,
+		ExpiresAt:  testTime.Add(time.Hour),
+	}
+
+	tests := map[string]struct {
+		actor      access.Actor
+		modelID    string
+		nodes      bool
+		relations  bool
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-model-id": {
+			actor:   otherActor,
+			nodes:   true,
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			actor:      otherActor,
+			modelID:    validModelID,
+			nodes:      true,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"locked-nodes": {
+			actor:   otherActor,
+			modelID: validModelID,
+			nodes:   true,
+			wantErr: errorz.ConflictError{},
+		},
+		"free-relations": {
+			actor:     otherActor,
+			modelID:   validModelID,
+			relations: true,
+		},
+		"other-model": {
+			actor:     otherActor,
+			modelID:   "model2",
+			nodes:     true,
+			relations: true,
+		},
+		"holder": {
+			actor:     holderActor,
+			modelID:   validModelID,
+			nodes:     true,
+			relations: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := newTestService(newTestStore(test.storeError, nodeLock), newTestListener(false))
+
+			err := svc.CheckMeshLock(context.Background(), test.actor, test.modelID, test.nodes, test.relations)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLockService_DeleteModelResources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid-model-id", func(t *testing.T) {
+		err := newTestService(newTestStore(false), newTestListener(false)).
+			DeleteModelResources(context.Background(), adminActor, "")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ts := newTestStore(false, testLock("1", validNodeID, time.Hour))
+
+		err := newTestService(ts, newTestListener(false)).
+			DeleteModelResources(context.Background(), adminActor, validModelID)
+
+		require.NoError(t, err)
+		require.Empty(t, ts.locks)
+	})
+}
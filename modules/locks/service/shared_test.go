@@ -0,0 +1,333 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminActor      = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	holderActor     = access.Actor{UserID: "holder", Role: access.RoleCreator}
+	otherActor      = access.Actor{UserID: "other", Role: access.RoleCreator}
+	guestActor      = access.Actor{UserID: "guest", Role: access.RoleGuest}
+	strangerActor   = access.Actor{UserID: "stranger", Role: access.RoleEditor}
+	validModelID    = "model1"
+	validLockID     = "1" // must match generated ID from testIDGenerator
+	validNodeID     = "b1"
+	validRelationID = "r1"
+	validContainer  = "s1"
+	validNodeData   = locks.LockData{
+		ModelID: validModelID,
+		Target:  locks.Target{Type: locks.TargetNode, ID: validNodeID},
+	}
+	validContainerData = locks.LockData{
+		ModelID:          validModelID,
+		Target:           locks.Target{Type: locks.TargetContainer, ID: validContainer},
+		ContainmentKinds: []string{"contains"},
+	}
+	testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+type testIDGenerator struct {
+	idCounter atomic.Int64
+}
+
+// Ensure that the testIDGenerator implements the idGenerator interface.
+var _ idGenerator = (*testIDGenerator)(nil)
+
+func newTestIDGenerator() *testIDGenerator {
+	return &testIDGenerator{}
+}
+
+func (g *testIDGenerator) GenerateID() string {
+	return strconv.FormatInt(g.idCounter.Add(1), 10)
+}
+
+type testListener struct {
+	forcedError error
+	eventFired  locks.Event
+}
+
+// Ensure that the testListener implements the listener interface.
+var _ listener = (*testListener)(nil)
+
+func newTestListener(forcedError bool) *testListener {
+	var err error
+
+	if forcedError {
+		err = errorz.NewGatewayError("forced-error")
+	}
+
+	return &testListener{forcedError: err}
+}
+
+func (l *testListener) HandleLockEvent(_ context.Context, event locks.Event) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+// testStore keeps the locks in memory.
+type testStore struct {
+	forcedError error
+	locks       map[string]locks.Lock
+}
+
+// Ensure that the testStore implements the store interface.
+var _ store = (*testStore)(nil)
+
+func newTestStore(forcedError bool, initial ...locks.Lock) *testStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	s := &testStore{
+		forcedError: err,
+		locks:       map[string]locks.Lock{},
+	}
+
+	for _, l := range initial {
+		s.locks[l.ID] = l
+	}
+
+	return s
+}
+
+func (s *testStore) CreateLock(_ context.Context, lock locks.Lock) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.locks[lock.ID] = lock
+
+	return nil
+}
+
+func (s *testStore) UpdateLock(_ context.Context, lock locks.Lock) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.locks[lock.ID]; !ok {
+		return errorz.NewNotFoundError("lock %s not found", lock.ID)
+	}
+
+	s.locks[lock.ID] = lock
+
+	return nil
+}
+
+func (s *testStore) DeleteLock(_ context.Context, id string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if _, ok := s.locks[id]; !ok {
+		return errorz.NewNotFoundError("lock %s not found", id)
+	}
+
+	delete(s.locks, id)
+
+	return nil
+}
+
+func (s *testStore) DeleteModelLocks(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	for id, l := range s.locks {
+		if l.ModelID == modelID {
+			delete(s.locks, id)
+		}
+	}
+
+	return nil
+}
+
+func (s *testStore) GetLock(_ context.Context, id string) (locks.Lock, error) {
+	if s.forcedError != nil {
+		return locks.Lock{}, s.forcedError
+	}
+
+	l, ok := s.locks[id]
+	if !ok {
+		return locks.Lock{}, errorz.NewNotFoundError("lock %s not found", id)
+	}
+
+	return l, nil
+}
+
+func (s *testStore) GetLocks(_ context.Context, modelID string, now time.Time) ([]locks.Lock, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []locks.Lock
+
+	for _, l := range s.locks {
+		if l.ModelID == modelID && !l.IsExpired(now) {
+			found = append(found, l)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b locks.Lock) int {
+		if c := a.AcquiredAt.Compare(b.AcquiredAt); c != 0 {
+			return c
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return found, nil
+}
+
+// testMeshProvider knows a substation s1 containing the buses b1 and b2, connected by the
+// line r1, and the bus b3 outside of the substation, connected to b2 by the line r2.
+type testMeshProvider struct{}
+
+// Ensure that the testMeshProvider implements the meshProvider interface.
+var _ meshProvider = testMeshProvider{}
+
+var testMesh = models.Mesh{
+	ModelID: validModelID,
+	Nodes: map[string]models.Node{
+		"s1": {ID: "s1", Kind: "substation"},
+		"b1": {ID: "b1", Kind: "bus"},
+		"b2": {ID: "b2", Kind: "bus"},
+		"b3": {ID: "b3", Kind: "bus"},
+	},
+	Relations: map[string]models.Relation{
+		"c1": {ID: "c1", Kind: "contains", From: "s1", To: "b1"},
+		"c2": {ID: "c2", Kind: "contains", From: "s1", To: "b2"},
+		"r1": {ID: "r1", Kind: "line", From: "b1", To: "b2"},
+		"r2": {ID: "r2", Kind: "line", From: "b2", To: "b3"},
+	},
+}
+
+func (testMeshProvider) GetNode(_ context.Context, modelID, nodeID string) (models.Node, error) {
+	node, ok := testMesh.Nodes[nodeID]
+	if modelID != validModelID || !ok {
+		return models.Node{}, errorz.NewNotFoundError("node %s not found", nodeID)
+	}
+
+	return node, nil
+}
+
+func (testMeshProvider) GetRelation(_ context.Context, modelID, relationID string) (models.Relation, error) {
+	relation, ok := testMesh.Relations[relationID]
+	if modelID != validModelID || !ok {
+		return models.Relation{}, errorz.NewNotFoundError("relation %s not found", relationID)
+	}
+
+	return relation, nil
+}
+
+func (testMeshProvider) ExtractSubmesh(
+	_ context.Context,
+	modelID string,
+	spec models.SliceSpec,
+) (models.Mesh, error) {
+	if modelID != validModelID || spec.Container != validContainer {
+		return models.Mesh{}, errorz.NewNotFoundError("mesh[nodes] %v[%v] not found", modelID, spec.Container)
+	}
+
+	submesh := models.Mesh{ModelID: modelID, Nodes: map[string]models.Node{}, Relations: map[string]models.Relation{}}
+
+	for _, id := range []string{"s1", "b1", "b2"} {
+		submesh.Nodes[id] = testMesh.Nodes[id]
+	}
+
+	for _, id := range []string{"c1", "c2", "r1"} {
+		submesh.Relations[id] = testMesh.Relations[id]
+	}
+
+	return submesh, nil
+}
+
+// testBindingProvider grants the holder and the other actor the editor role and the guest
+// the guest role on the valid model.
+type testBindingProvider struct{}
+
+func (testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	roles := map[string]access.Role{
+		holderActor.UserID: access.RoleEditor,
+		otherActor.UserID:  access.RoleEditor,
+		guestActor.UserID:  access.RoleGuest,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testLock returns a lock of the holder on the node, acquired at the test time.
+func testLock(id, nodeID string, ttl time.Duration) locks.Lock {
+	return locks.Lock{
+		ID:         id,
+		ModelID:    validModelID,
+		Target:     locks.Target{Type: locks.TargetNode, ID: nodeID},
+		HolderID:   holderActor.UserID,
+		NodeIDs:    []string{nodeID},
+		AcquiredAt: testTime,
+		ExpiresAt:  testTime.Add(ttl),
+	}
+}
+
+// newTestService returns a service running a minute after the test time.
+func newTestService(ts *testStore, tl *testListener, opts ...Option) *LockService {
+	opts = append([]Option{WithListener(tl)}, opts...)
+
+	svc := NewLockService(ts, newTestIDGenerator(), testMeshProvider{}, authz.NewAuthorizer(testBindingProvider{}),
+		opts...)
+	svc.now = func() time.Time { return testTime.Add(time.Minute) }
+
+	return svc
+}
+
+func requireEventFired(t *testing.T, wantEvent locks.EventType, listener *testListener) {
+	t.Helper()
+
+	eventFired := listener.eventFired
+
+	require.NotEmpty(t, eventFired)
+
+	le, ok := locks.ExtractLockEvent(eventFired)
+
+	require.True(t, ok)
+
+	require.Equal(t, wantEvent, le.Type)
+	require.NotEmpty(t, le.Actor)
+	require.NotEmpty(t, le.Lock)
+	require.NotEmpty(t, le.Timestamp)
+}
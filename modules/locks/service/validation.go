@@ -0,0 +1,62 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+)
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateID(id string) error {
+	return requireString(id, "id")
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateLockData(data locks.LockData, maxTTL time.Duration) error {
+	if err := validateModelID(data.ModelID); err != nil {
+		return err
+	}
+
+	if err := validateTarget(data.Target); err != nil {
+		return err
+	}
+
+	if data.Target.Type == locks.TargetContainer && len(data.ContainmentKinds) == 0 {
+		return errorz.NewValidationError("container lock requires containment kinds")
+	}
+
+	return validateTTL(data.TTL, maxTTL)
+}
+
+func validateTarget(target locks.Target) error {
+	if !slices.Contains(locks.AllTargetTypes, target.Type) {
+		return errorz.NewValidationError("invalid lock target type: %v", target.Type)
+	}
+
+	return requireString(target.ID, "lock target id")
+}
+
+// validateTTL checks the time to live of a lock. A zero value selects the default.
+func validateTTL(ttl, maxTTL time.Duration) error {
+	if ttl < 0 {
+		return errorz.NewValidationError("lock ttl must not be negative")
+	}
+
+	if ttl > maxTTL {
+		return errorz.NewValidationError("lock ttl must not exceed %s", maxTTL)
+	}
+
+	return nil
+}
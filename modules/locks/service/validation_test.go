@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateID("1"))
+	require.Error(t, validateID(""))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateLockData(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data    locks.LockData
+		wantErr bool
+	}{
+		"node":      {data: validNodeData},
+		"container": {data: validContainerData},
+		"max-ttl": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  validNodeData.Target,
+			TTL:     time.Hour,
+		}},
+		"missing-model-id": {data: locks.LockData{Target: validNodeData.Target}, wantErr: true},
+		"invalid-type": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  locks.Target{Type: 100, ID: "n1"},
+		}, wantErr: true},
+		"missing-target-id": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  locks.Target{Type: locks.TargetRelation},
+		}, wantErr: true},
+		"container-without-kinds": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  validContainerData.Target,
+		}, wantErr: true},
+		"negative-ttl": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  validNodeData.Target,
+			TTL:     -time.Second,
+		}, wantErr: true},
+		"ttl-too-long": {data: locks.LockData{
+			ModelID: validModelID,
+			Target:  validNodeData.Target,
+			TTL:     time.Hour + time.Second,
+		}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateLockData(test.data, time.Hour)
+
+			if test.wantErr {
+				require.Error(t, err)
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/go-kit/testutil/mongodb"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/energimind/powermesh-core/modules/locks/store/mongo"
+)
+
+var mongoEnv mongodb.MongoEnvironment
+
+// TestMain sets up the MongoDB test environment for all blackbox
+// tests in the repository_test package.
+func TestMain(m *testing.M) {
+	cleanUp, err := mongoEnv.Start()
+	defer cleanUp()
+
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testLock returns a node lock acquired at the given minute after the test time and
+// expiring five minutes later.
+func testLock(id, modelID, nodeID string, minute int) locks.Lock {
+	acquiredAt := testTime.Add(time.Duration(minute) * time.Minute)
+
+	return locks.Lock{
+		ID:         id,
+		ModelID:    modelID,
+		Target:     locks.Target{Type: locks.TargetNode, ID: nodeID},
+		HolderID:   "user1",
+		NodeIDs:    []string{nodeID},
+		AcquiredAt: acquiredAt,
+		ExpiresAt:  acquiredAt.Add(5 * time.Minute),
+	}
+}
+
+func withStore(t *testing.T, f func(*testing.T, context.Context, *mongo.LockStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewLockStore(db)
+
+	f(t, ctx, store)
+}
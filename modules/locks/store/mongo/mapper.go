@@ -0,0 +1,44 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/locks"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+func toStoreLock(l locks.Lock) storeLock {
+	return storeLock{
+		ID:          l.ID,
+		ModelID:     l.ModelID,
+		TargetType:  l.Target.Type,
+		TargetID:    l.Target.ID,
+		HolderID:    l.HolderID,
+		NodeIDs:     l.NodeIDs,
+		RelationIDs: l.RelationIDs,
+		AcquiredAt:  l.AcquiredAt,
+		ExpiresAt:   l.ExpiresAt,
+	}
+}
+
+func fromStoreLock(l storeLock) locks.Lock {
+	return locks.Lock{
+		ID:      l.ID,
+		ModelID: l.ModelID,
+		Target: locks.Target{
+			Type: l.TargetType,
+			ID:   l.TargetID,
+		},
+		HolderID:    l.HolderID,
+		NodeIDs:     l.NodeIDs,
+		RelationIDs: l.RelationIDs,
+		AcquiredAt:  l.AcquiredAt,
+		ExpiresAt:   l.ExpiresAt,
+	}
+}
+
+// activeLocksFilter builds the filter of the locks of the model that have not expired
+// at the given time.
+func activeLocksFilter(modelID string, now time.Time) q.Filter {
+	return q.Filter{}.EQ(fieldModelID, modelID).GT(fieldExpiresAt, now)
+}
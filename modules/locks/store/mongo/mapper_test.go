@@ -0,0 +1,27 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mapper(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreLock, toStoreLock(validLock))
+	require.Equal(t, validLock, fromStoreLock(validStoreLock))
+}
+
+func Test_activeLocksFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t,
+		q.Filter{}.EQ(fieldModelID, "model1").GT(fieldExpiresAt, now),
+		activeLocksFilter("model1", now),
+	)
+}
@@ -0,0 +1,20 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/locks"
+)
+
+// storeLock represents a lock in the MongoDB store.
+type storeLock struct {
+	ID          string           `bson:"id"`
+	ModelID     string           `bson:"modelId"`
+	TargetType  locks.TargetType `bson:"targetType"`
+	TargetID    string           `bson:"targetId"`
+	HolderID    string           `bson:"holderId"`
+	NodeIDs     []string         `bson:"nodeIds"`
+	RelationIDs []string         `bson:"relationIds"`
+	AcquiredAt  time.Time        `bson:"acquiredAt"`
+	ExpiresAt   time.Time        `bson:"expiresAt"`
+}
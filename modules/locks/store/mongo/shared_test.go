@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/locks"
+)
+
+var (
+	validLock = locks.Lock{
+		ID:      "1",
+		ModelID: "model1",
+		Target: locks.Target{
+			Type: locks.TargetContainer,
+			ID:   "s1",
+		},
+		HolderID:    "user1",
+		NodeIDs:     []string{"b1", "b2", "s1"},
+		RelationIDs: []string{"c1", "c2"},
+		AcquiredAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:   time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC),
+	}
+	validStoreLock = storeLock{
+		ID:          validLock.ID,
+		ModelID:     validLock.ModelID,
+		TargetType:  validLock.Target.Type,
+		TargetID:    validLock.Target.ID,
+		HolderID:    validLock.HolderID,
+		NodeIDs:     validLock.NodeIDs,
+		RelationIDs: validLock.RelationIDs,
+		AcquiredAt:  validLock.AcquiredAt,
+		ExpiresAt:   validLock.ExpiresAt,
+	}
+)
@@ -0,0 +1,109 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collLocks       = "locks"
+	fieldID         = "id"
+	fieldModelID    = "modelId"
+	fieldAcquiredAt = "acquiredAt"
+	fieldExpiresAt  = "expiresAt"
+)
+
+// LockStore is a MongoDB implementation of the lock store.
+//
+// Expired locks are removed by a TTL index on the expiry time, see EnsureIndexes. MongoDB
+// removes expired documents in the background about once a minute, so the queries of
+// active locks filter by the expiry time as well.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type LockStore struct {
+	locks *mongo.Collection
+}
+
+// NewLockStore creates a new MongoDB lock store.
+func NewLockStore(db *mongo.Database) *LockStore {
+	return &LockStore{
+		locks: db.Collection(collLocks),
+	}
+}
+
+// EnsureIndexes creates the indexes of the lock collection if they do not exist: the TTL
+// index removing expired locks and the index of the locks by model.
+func (s *LockStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.locks.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: fieldExpiresAt, Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: fieldModelID, Value: 1}},
+		},
+	})
+	if err != nil {
+		return errorz.NewStoreError("failed to create indexes of %s: %v", collLocks, err)
+	}
+
+	return nil
+}
+
+// CreateLock implements the lock store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) CreateLock(ctx context.Context, lock locks.Lock) error {
+	return q.CreateOne(s.locks, toStoreLock).Exec(ctx, lock)
+}
+
+// UpdateLock implements the lock store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) UpdateLock(ctx context.Context, lock locks.Lock) error {
+	return q.UpdateOne(s.locks, toStoreLock).Exec(ctx, lock.ID, lock)
+}
+
+// DeleteLock implements the lock store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) DeleteLock(ctx context.Context, id string) error {
+	return q.DeleteOne(s.locks).Exec(ctx, id)
+}
+
+// DeleteModelLocks implements the lock store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) DeleteModelLocks(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.locks).Exec(ctx, q.Filter{}.EQ(fieldModelID, modelID))
+
+	return err
+}
+
+// GetLock implements the lock store interface.
+// It also returns expired locks that have not been removed yet.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) GetLock(ctx context.Context, id string) (locks.Lock, error) {
+	return q.GetOne(s.locks, fromStoreLock).Exec(ctx, id)
+}
+
+// GetLocks implements the lock store interface.
+// It returns the locks of the model that have not expired at the given time, in the order
+// they were acquired.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *LockStore) GetLocks(ctx context.Context, modelID string, now time.Time) ([]locks.Lock, error) {
+	return q.FindMany(s.locks, fromStoreLock).
+		WithSort(fieldAcquiredAt, false).
+		WithSort(fieldID, false).
+		Exec(ctx, activeLocksFilter(modelID, now))
+}
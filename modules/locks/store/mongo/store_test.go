@@ -0,0 +1,162 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/locks"
+	"github.com/energimind/powermesh-core/modules/locks/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func createLocks(t *testing.T, ctx context.Context, store *mongo.LockStore, ls ...locks.Lock) {
+	t.Helper()
+
+	for _, l := range ls {
+		require.NoError(t, store.CreateLock(ctx, l))
+	}
+}
+
+func lockIDs(found []locks.Lock) []string {
+	ids := make([]string, 0, len(found))
+
+	for _, l := range found {
+		ids = append(ids, l.ID)
+	}
+
+	return ids
+}
+
+func TestLockStore_EnsureIndexes(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		require.NoError(t, store.EnsureIndexes(ctx))
+		require.NoError(t, store.EnsureIndexes(ctx))
+	})
+}
+
+func TestLockStore_CreateLock(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		lock := testLock("1", "model1", "node1", 0)
+		lock.RelationIDs = []string{"relation1"}
+
+		require.NoError(t, store.CreateLock(ctx, lock))
+
+		created, err := store.GetLock(ctx, lock.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, lock, created)
+	})
+}
+
+func TestLockStore_UpdateLock(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.UpdateLock(ctx, testLock("1", "model1", "node1", 0)))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			lock := testLock("1", "model1", "node1", 0)
+
+			require.NoError(t, store.CreateLock(ctx, lock))
+
+			lock.ExpiresAt = lock.ExpiresAt.Add(time.Hour)
+
+			require.NoError(t, store.UpdateLock(ctx, lock))
+
+			updated, err := store.GetLock(ctx, lock.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, lock, updated)
+		})
+	})
+}
+
+func TestLockStore_DeleteLock(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		createLocks(t, ctx, store, testLock("1", "model1", "node1", 0))
+
+		require.NoError(t, store.DeleteLock(ctx, "1"))
+		require.IsType(t, errorz.NotFoundError{}, store.DeleteLock(ctx, "1"))
+	})
+}
+
+func TestLockStore_DeleteModelLocks(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		createLocks(t, ctx, store,
+			testLock("1", "model1", "node1", 0),
+			testLock("2", "model1", "node2", 1),
+			testLock("3", "model2", "node1", 2),
+		)
+
+		require.NoError(t, store.DeleteModelLocks(ctx, "model1"))
+
+		_, err := store.GetLock(ctx, "1")
+
+		require.IsType(t, errorz.NotFoundError{}, err)
+
+		_, err = store.GetLock(ctx, "3")
+
+		require.NoError(t, err)
+	})
+}
+
+func TestLockStore_GetLocks(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.LockStore) {
+		createLocks(t, ctx, store,
+			testLock("3", "model1", "node3", 4),
+			testLock("1", "model1", "node1", 0),
+			testLock("2", "model1", "node2", 2),
+			testLock("4", "model2", "node1", 4),
+		)
+
+		tests := map[string]struct {
+			modelID string
+			now     time.Time
+			wantIDs []string
+		}{
+			"all-active": {
+				modelID: "model1",
+				now:     testTime,
+				wantIDs: []string{"1", "2", "3"},
+			},
+			"some-expired": {
+				modelID: "model1",
+				now:     testTime.Add(5 * time.Minute),
+				wantIDs: []string{"2", "3"},
+			},
+			"all-expired": {
+				modelID: "model1",
+				now:     testTime.Add(time.Hour),
+				wantIDs: []string{},
+			},
+			"other-model": {
+				modelID: "model2",
+				now:     testTime,
+				wantIDs: []string{"4"},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				found, err := store.GetLocks(ctx, test.modelID, test.now)
+
+				require.NoError(t, err)
+				require.Equal(t, test.wantIDs, lockIDs(found))
+			})
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// SameElement checks if the nodes or relations have the same contents. Their properties
+// are compared with SameValue, and a missing property bag equals an empty one.
+func SameElement[T Node | Relation](a, b T) bool {
+	switch a := any(a).(type) {
+	case Node:
+		b, _ := any(b).(Node)
+
+		return a.ID == b.ID && a.Kind == b.Kind && a.Code == b.Code && sameProps(a.Props, b.Props)
+	case Relation:
+		b, _ := any(b).(Relation)
+
+		return a.ID == b.ID && a.Kind == b.Kind && a.From == b.From && a.To == b.To && sameProps(a.Props, b.Props)
+	}
+
+	return false
+}
+
+// SameValue checks if the values are the same. The values are compared by their JSON
+// encoding, so values read back from a store compare equal to the values written regardless
+// of their numeric types.
+func SameValue(a, b any) bool {
+	aj, aErr := json.Marshal(a)
+	bj, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && string(aj) == string(bj)
+}
+
+// SortedKeys returns the keys of the maps in sorted order, without duplicates.
+func SortedKeys[T any](maps ...map[string]T) []string {
+	var keys []string
+
+	for _, m := range maps {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// sameProps checks if the property bags have the same contents.
+func sameProps(a, b PropBag) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+
+	return SameValue(a, b)
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameElement(t *testing.T) {
+	t.Parallel()
+
+	node := Node{ID: "n1", Kind: "bus", Code: "B1", Props: PropBag{"load": {"kw": 10}}}
+	relation := Relation{ID: "r1", Kind: "line", From: "n1", To: "n2"}
+
+	require.True(t, SameElement(node, Node{ID: "n1", Kind: "bus", Code: "B1", Props: PropBag{"load": {"kw": int64(10)}}}))
+	require.False(t, SameElement(node, Node{ID: "n1", Kind: "bus", Code: "B2", Props: node.Props}))
+	require.False(t, SameElement(node, Node{ID: "n1", Kind: "bus", Code: "B1"}))
+	require.True(t, SameElement(relation, Relation{ID: "r1", Kind: "line", From: "n1", To: "n2", Props: PropBag{}}))
+	require.False(t, SameElement(relation, Relation{ID: "r1", Kind: "line", From: "n2", To: "n1"}))
+}
+
+func TestSameValue(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, SameValue(10, int64(10)))
+	require.True(t, SameValue(10.0, int32(10)))
+	require.True(t, SameValue(PropBag{"load": {"kw": 10}}, PropBag{"load": {"kw": int64(10)}}))
+	require.False(t, SameValue(10, 11))
+	require.False(t, SameValue("10", 10))
+}
+
+func TestSortedKeys(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, SortedKeys[int]())
+	require.Equal(t, []string{"a", "b", "c"}, SortedKeys(map[string]int{"c": 1, "a": 2, "b": 3}))
+	require.Equal(t, []string{"a", "b", "c"}, SortedKeys(map[string]int{"c": 1, "a": 2}, map[string]int{"b": 3, "a": 4}))
+}
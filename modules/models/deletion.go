@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// Deletion records a cascading model deletion that has been started but not completed.
+// Pending deletions are kept in a journal, so they can be resumed after a failure.
+type Deletion struct {
+	ModelID   string
+	Actor     access.Actor // actor who started the deletion, used when it is resumed
+	StartedAt time.Time
+}
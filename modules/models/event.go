@@ -14,18 +14,25 @@ const (
 	ModelCreated        EventType = "model.created"
 	ModelUpdated        EventType = "model.updated"
 	ModelDeleted        EventType = "model.deleted"
+	ModelPublished      EventType = "model.published"
+	ModelArchived       EventType = "model.archived"
 	MeshCreated         EventType = "mesh.created"
 	MeshUpdated         EventType = "mesh.updated"
 	MeshDeleted         EventType = "mesh.deleted"
 	MeshContentsCreated EventType = "mesh-contents.created"
 	MeshContentsUpdated EventType = "mesh-contents.updated"
 	MeshContentsDeleted EventType = "mesh-contents.deleted"
+	MeshProfilesUpdated EventType = "mesh-profiles.updated"
+	TemplateCreated     EventType = "template.created"
+	TemplateUpdated     EventType = "template.updated"
+	TemplateDeleted     EventType = "template.deleted"
 )
 
 // Event models an event that occurs in the models service.
 type Event interface {
 	IsModelEvent() bool
 	IsMeshEvent() bool
+	IsTemplateEvent() bool
 }
 
 // EventHeader models the header of an event.
@@ -51,11 +58,24 @@ func (ModelEvent) IsMeshEvent() bool {
 	return false
 }
 
+// IsTemplateEvent implements the Event interface.
+func (ModelEvent) IsTemplateEvent() bool {
+	return false
+}
+
 // MeshEvent models an event that occurs in the models service related to a mesh.
+//
+// The profile events carry the nodes whose profiles changed in Updates and the changed
+// profiles in Profiles.
+//
+// The update and delete events of single elements carry the elements as they were before
+// the change in Previous.
 type MeshEvent struct {
 	EventHeader
-	Updates Mesh
-	Deletes Mesh
+	Updates  Mesh
+	Deletes  Mesh
+	Previous Mesh
+	Profiles []ProfileKey
 }
 
 // IsModelEvent implements the Event interface.
@@ -68,6 +88,32 @@ func (MeshEvent) IsMeshEvent() bool {
 	return true
 }
 
+// IsTemplateEvent implements the Event interface.
+func (MeshEvent) IsTemplateEvent() bool {
+	return false
+}
+
+// TemplateEvent models an event that occurs in the models service related to a template.
+type TemplateEvent struct {
+	EventHeader
+	Template Template
+}
+
+// IsModelEvent implements the Event interface.
+func (TemplateEvent) IsModelEvent() bool {
+	return false
+}
+
+// IsMeshEvent implements the Event interface.
+func (TemplateEvent) IsMeshEvent() bool {
+	return false
+}
+
+// IsTemplateEvent implements the Event interface.
+func (TemplateEvent) IsTemplateEvent() bool {
+	return true
+}
+
 // ExtractModelEvent extracts a model event from an event.
 func ExtractModelEvent(e Event) (ModelEvent, bool) {
 	if me, ok := e.(ModelEvent); ok {
@@ -85,3 +131,12 @@ func ExtractMeshEvent(e Event) (MeshEvent, bool) {
 
 	return MeshEvent{}, false
 }
+
+// ExtractTemplateEvent extracts a template event from an event.
+func ExtractTemplateEvent(e Event) (TemplateEvent, bool) {
+	if te, ok := e.(TemplateEvent); ok {
+		return te, true
+	}
+
+	return TemplateEvent{}, false
+}
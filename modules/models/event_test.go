@@ -16,6 +16,7 @@ func TestExtractModelEvent(t *testing.T) {
 		require.NotZero(t, me)
 		require.True(t, me.IsModelEvent())
 		require.False(t, me.IsMeshEvent())
+		require.False(t, me.IsTemplateEvent())
 	})
 
 	t.Run("failure", func(t *testing.T) {
@@ -36,6 +37,7 @@ func TestExtractMeshEvent(t *testing.T) {
 		require.NotZero(t, me)
 		require.True(t, me.IsMeshEvent())
 		require.False(t, me.IsModelEvent())
+		require.False(t, me.IsTemplateEvent())
 	})
 
 	t.Run("failure", func(t *testing.T) {
@@ -45,3 +47,24 @@ func TestExtractMeshEvent(t *testing.T) {
 		require.Zero(t, me)
 	})
 }
+
+func TestExtractTemplateEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		te, ok := ExtractTemplateEvent(TemplateEvent{EventHeader: EventHeader{Type: TemplateCreated}})
+
+		require.True(t, ok)
+		require.NotZero(t, te)
+		require.True(t, te.IsTemplateEvent())
+		require.False(t, te.IsModelEvent())
+		require.False(t, te.IsMeshEvent())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		te, ok := ExtractTemplateEvent(nil)
+
+		require.False(t, ok)
+		require.Zero(t, te)
+	})
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
+
+// Label limits. They follow the Kubernetes label syntax.
+const (
+	maxLabelNameLength   = 63
+	maxLabelPrefixLength = 253
+)
+
+//nolint:gochecknoglobals
+var (
+	// labelNamePattern matches the name part of a label key and non-empty label values.
+	labelNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+
+	// labelPrefixPattern matches the optional DNS subdomain prefix of a label key.
+	labelPrefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// setRequirementPattern matches the "key in (a, b)" and "key notin (a, b)" requirements.
+	setRequirementPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// IsValidLabelKey checks if the given string is a valid label key.
+// A key consists of an optional DNS subdomain prefix followed by a slash and a name,
+// e.g. "region" or "example.com/team".
+func IsValidLabelKey(key string) bool {
+	name := key
+
+	if i := strings.LastIndexByte(key, '/'); i >= 0 {
+		prefix := key[:i]
+
+		if len(prefix) > maxLabelPrefixLength || !labelPrefixPattern.MatchString(prefix) {
+			return false
+		}
+
+		name = key[i+1:]
+	}
+
+	return len(name) <= maxLabelNameLength && labelNamePattern.MatchString(name)
+}
+
+// IsValidLabelValue checks if the given string is a valid label value.
+// Empty values are allowed.
+func IsValidLabelValue(value string) bool {
+	return value == "" || (len(value) <= maxLabelNameLength && labelNamePattern.MatchString(value))
+}
+
+// LabelOperator defines the operator of a label requirement.
+type LabelOperator string
+
+// Label operators.
+const (
+	LabelEquals       LabelOperator = "="
+	LabelNotEquals    LabelOperator = "!="
+	LabelIn           LabelOperator = "in"
+	LabelNotIn        LabelOperator = "notin"
+	LabelExists       LabelOperator = "exists"
+	LabelDoesNotExist LabelOperator = "!"
+)
+
+// LabelRequirement defines a single condition of a label selector.
+type LabelRequirement struct {
+	Key      string
+	Operator LabelOperator
+	Values   []string // one value for = and !=, one or more for in and notin, none otherwise
+}
+
+// Matches checks if the given labels satisfy the requirement.
+// As in Kubernetes, != and notin also match labels that do not have the key.
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+
+	switch r.Operator {
+	case LabelEquals, LabelIn:
+		return ok && slices.Contains(r.Values, value)
+	case LabelNotEquals, LabelNotIn:
+		return !ok || !slices.Contains(r.Values, value)
+	case LabelExists:
+		return ok
+	case LabelDoesNotExist:
+		return !ok
+	}
+
+	return false
+}
+
+// String returns the string representation of the requirement in the selector syntax.
+func (r LabelRequirement) String() string {
+	switch r.Operator {
+	case LabelEquals, LabelNotEquals:
+		return r.Key + string(r.Operator) + strings.Join(r.Values, "")
+	case LabelIn, LabelNotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case LabelExists:
+		return r.Key
+	case LabelDoesNotExist:
+		return "!" + r.Key
+	}
+
+	return r.Key + " " + string(r.Operator)
+}
+
+// validate checks the key, the operator and the values of the requirement.
+func (r LabelRequirement) validate() error {
+	if !IsValidLabelKey(r.Key) {
+		return errorz.NewValidationError("invalid label key %q", r.Key)
+	}
+
+	switch r.Operator {
+	case LabelEquals, LabelNotEquals:
+		if len(r.Values) != 1 {
+			return errorz.NewValidationError("operator %s of label %s requires exactly one value",
+				r.Operator, r.Key)
+		}
+	case LabelIn, LabelNotIn:
+		if len(r.Values) == 0 {
+			return errorz.NewValidationError("operator %s of label %s requires at least one value",
+				r.Operator, r.Key)
+		}
+	case LabelExists, LabelDoesNotExist:
+		if len(r.Values) != 0 {
+			return errorz.NewValidationError("operator %s of label %s does not take values",
+				r.Operator, r.Key)
+		}
+	default:
+		return errorz.NewValidationError("unsupported label operator %q", r.Operator)
+	}
+
+	for _, v := range r.Values {
+		if !IsValidLabelValue(v) {
+			return errorz.NewValidationError("invalid value %q of label %s", v, r.Key)
+		}
+	}
+
+	return nil
+}
+
+// LabelSelector selects labeled objects. All requirements must match.
+// An empty selector matches everything.
+type LabelSelector []LabelRequirement
+
+// Matches checks if the given labels satisfy all requirements of the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the string representation of the selector.
+// The result can be parsed back with ParseLabelSelector.
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Validate checks that all requirements of the selector are well-formed.
+// It returns a validation error otherwise.
+func (s LabelSelector) Validate() error {
+	for _, r := range s {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseLabelSelector parses a selector in the Kubernetes syntax. The requirements are
+// separated by commas, e.g.:
+//
+//	region=north,purpose!=test,customer in (acme, globex),tier notin (gold),owner,!legacy
+//
+// The "==" operator is accepted as an alias for "=". A bare key requires the label to exist
+// and a key prefixed with "!" requires it to be absent.
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var selector LabelSelector
+
+	for _, part := range splitSelector(s) {
+		r, err := parseLabelRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+
+		selector = append(selector, r)
+	}
+
+	if err := selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	return selector, nil
+}
+
+// splitSelector splits the selector at the commas outside of parentheses.
+func splitSelector(s string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// parseLabelRequirement parses a single requirement. The result is validated by the caller.
+func parseLabelRequirement(s string) (LabelRequirement, error) {
+	if s == "" {
+		return LabelRequirement{}, errorz.NewValidationError("empty label requirement")
+	}
+
+	if m := setRequirementPattern.FindStringSubmatch(s); m != nil {
+		var values []string
+
+		for _, v := range strings.Split(m[3], ",") {
+			values = append(values, strings.TrimSpace(v))
+		}
+
+		return LabelRequirement{Key: m[1], Operator: LabelOperator(m[2]), Values: values}, nil
+	}
+
+	if key, value, ok := strings.Cut(s, "!="); ok {
+		return equalityRequirement(key, LabelNotEquals, value), nil
+	}
+
+	if key, value, ok := strings.Cut(s, "=="); ok {
+		return equalityRequirement(key, LabelEquals, value), nil
+	}
+
+	if key, value, ok := strings.Cut(s, "="); ok {
+		return equalityRequirement(key, LabelEquals, value), nil
+	}
+
+	if key, ok := strings.CutPrefix(s, "!"); ok {
+		return LabelRequirement{Key: strings.TrimSpace(key), Operator: LabelDoesNotExist}, nil
+	}
+
+	return LabelRequirement{Key: s, Operator: LabelExists}, nil
+}
+
+// equalityRequirement builds a requirement with a single value.
+func equalityRequirement(key string, op LabelOperator, value string) LabelRequirement {
+	return LabelRequirement{
+		Key:      strings.TrimSpace(key),
+		Operator: op,
+		Values:   []string{strings.TrimSpace(value)},
+	}
+}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidLabelKey(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{
+		"region",
+		"a",
+		"purpose.v2",
+		"grid_ops-team",
+		"example.com/team",
+		"a.b.c/d",
+		strings.Repeat("k", maxLabelNameLength),
+	}
+
+	invalid := []string{
+		"",
+		"-region",
+		"region-",
+		"with space",
+		"example.com/",
+		"/team",
+		"Example.com/team",
+		"example..com/team",
+		strings.Repeat("k", maxLabelNameLength+1),
+		strings.Repeat("p", maxLabelPrefixLength+1) + "/team",
+	}
+
+	for _, key := range valid {
+		require.True(t, IsValidLabelKey(key), key)
+	}
+
+	for _, key := range invalid {
+		require.False(t, IsValidLabelKey(key), key)
+	}
+}
+
+func TestIsValidLabelValue(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsValidLabelValue(""))
+	require.True(t, IsValidLabelValue("north"))
+	require.True(t, IsValidLabelValue("v1.2_rc-3"))
+	require.False(t, IsValidLabelValue("north east"))
+	require.False(t, IsValidLabelValue("a/b"))
+	require.False(t, IsValidLabelValue(".hidden"))
+	require.False(t, IsValidLabelValue(strings.Repeat("v", maxLabelNameLength+1)))
+}
+
+func TestLabelSelector_Matches(t *testing.T) {
+	t.Parallel()
+
+	labels := map[string]string{"region": "north", "purpose": "planning"}
+
+	tests := map[string]struct {
+		selector string
+		want     bool
+	}{
+		"empty":              {selector: "", want: true},
+		"equals":             {selector: "region=north", want: true},
+		"equals-mismatch":    {selector: "region=south", want: false},
+		"double-equals":      {selector: "region==north", want: true},
+		"not-equals":         {selector: "region!=south", want: true},
+		"not-equals-match":   {selector: "region!=north", want: false},
+		"not-equals-missing": {selector: "customer!=acme", want: true},
+		"in":                 {selector: "region in (south, north)", want: true},
+		"in-mismatch":        {selector: "region in (south,east)", want: false},
+		"in-missing":         {selector: "customer in (acme)", want: false},
+		"notin":              {selector: "region notin (south)", want: true},
+		"notin-match":        {selector: "region notin (north)", want: false},
+		"notin-missing":      {selector: "customer notin (acme)", want: true},
+		"exists":             {selector: "purpose", want: true},
+		"exists-missing":     {selector: "customer", want: false},
+		"not-exists":         {selector: "!customer", want: true},
+		"not-exists-present": {selector: "!purpose", want: false},
+		"all":                {selector: "region=north, purpose in (planning,ops), !customer", want: true},
+		"one-fails":          {selector: "region=north,purpose=ops", want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selector, err := ParseLabelSelector(test.selector)
+
+			require.NoError(t, err)
+			require.Equal(t, test.want, selector.Matches(labels))
+		})
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		selector, err := ParseLabelSelector(
+			"region=north, purpose!=test, customer in (acme, globex), tier notin (gold), owner, !legacy")
+
+		require.NoError(t, err)
+		require.Equal(t, LabelSelector{
+			{Key: "region", Operator: LabelEquals, Values: []string{"north"}},
+			{Key: "purpose", Operator: LabelNotEquals, Values: []string{"test"}},
+			{Key: "customer", Operator: LabelIn, Values: []string{"acme", "globex"}},
+			{Key: "tier", Operator: LabelNotIn, Values: []string{"gold"}},
+			{Key: "owner", Operator: LabelExists},
+			{Key: "legacy", Operator: LabelDoesNotExist},
+		}, selector)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		selector, err := ParseLabelSelector("  ")
+
+		require.NoError(t, err)
+		require.Empty(t, selector)
+	})
+
+	t.Run("round-trip", func(t *testing.T) {
+		s := "region=north,purpose!=test,customer in (acme,globex),tier notin (gold),owner,!legacy"
+
+		selector, err := ParseLabelSelector(s)
+
+		require.NoError(t, err)
+		require.Equal(t, s, selector.String())
+	})
+
+	for _, s := range []string{
+		"region=north,",
+		"region=north pole",
+		"-region=north",
+		"region in ()x",
+		"region=a=b",
+		"!",
+	} {
+		t.Run("invalid "+s, func(t *testing.T) {
+			_, err := ParseLabelSelector(s)
+
+			require.IsType(t, errorz.ValidationError{}, err)
+		})
+	}
+}
+
+func TestLabelSelector_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		selector LabelSelector
+		wantErr  bool
+	}{
+		"nil": {
+			selector: nil,
+		},
+		"valid": {
+			selector: LabelSelector{{Key: "region", Operator: LabelIn, Values: []string{"north", "south"}}},
+		},
+		"invalid-key": {
+			selector: LabelSelector{{Key: "", Operator: LabelExists}},
+			wantErr:  true,
+		},
+		"equals-without-value": {
+			selector: LabelSelector{{Key: "region", Operator: LabelEquals}},
+			wantErr:  true,
+		},
+		"in-without-values": {
+			selector: LabelSelector{{Key: "region", Operator: LabelIn}},
+			wantErr:  true,
+		},
+		"exists-with-values": {
+			selector: LabelSelector{{Key: "region", Operator: LabelExists, Values: []string{"north"}}},
+			wantErr:  true,
+		},
+		"invalid-value": {
+			selector: LabelSelector{{Key: "region", Operator: LabelNotIn, Values: []string{"north pole"}}},
+			wantErr:  true,
+		},
+		"unsupported-operator": {
+			selector: LabelSelector{{Key: "region", Operator: "like", Values: []string{"n%"}}},
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.selector.Validate()
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
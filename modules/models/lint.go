@@ -0,0 +1,92 @@
+package models
+
+import "strconv"
+
+// LintSeverity represents the severity of a lint finding.
+type LintSeverity int
+
+// LintSeverity enumeration. Higher values are more severe.
+const (
+	LintSeverityInfo LintSeverity = iota
+	LintSeverityWarning
+	LintSeverityError
+)
+
+// AllLintSeverities is a list of all lint severities. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllLintSeverities = []LintSeverity{
+	LintSeverityInfo,
+	LintSeverityWarning,
+	LintSeverityError,
+}
+
+// String returns the string representation of the lint severity.
+func (s LintSeverity) String() string {
+	switch s {
+	case LintSeverityInfo:
+		return "info"
+	case LintSeverityWarning:
+		return "warning"
+	case LintSeverityError:
+		return "error"
+	}
+
+	return "LintSeverity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// LintRule identifies the rule that produced a lint finding.
+type LintRule string
+
+// Lint rules.
+const (
+	LintDanglingRelation  LintRule = "dangling-relation"
+	LintOrphanNode        LintRule = "orphan-node"
+	LintDuplicateNodeCode LintRule = "duplicate-node-code"
+	LintSelfLoop          LintRule = "self-loop"
+	LintParallelRelation  LintRule = "parallel-relation"
+	LintEmptyPropSection  LintRule = "empty-prop-section"
+	LintUnknownNodeKind   LintRule = "unknown-node-kind"
+	LintUnknownRelKind    LintRule = "unknown-relation-kind"
+)
+
+// LintFinding represents a single problem found in a mesh.
+type LintFinding struct {
+	Rule        LintRule
+	Severity    LintSeverity
+	Message     string
+	NodeIDs     []string // public IDs of the affected nodes
+	RelationIDs []string // public IDs of the affected relations
+}
+
+// LintReport represents the result of linting a mesh.
+type LintReport struct {
+	ModelID  string
+	Findings []LintFinding
+}
+
+// MaxSeverity returns the highest severity of the findings in the report.
+// The second return value is false if the report has no findings.
+func (r LintReport) MaxSeverity() (LintSeverity, bool) {
+	if len(r.Findings) == 0 {
+		return LintSeverityInfo, false
+	}
+
+	maxSeverity := r.Findings[0].Severity
+
+	for _, f := range r.Findings[1:] {
+		if f.Severity > maxSeverity {
+			maxSeverity = f.Severity
+		}
+	}
+
+	return maxSeverity, true
+}
+
+// HasSeverity checks if the report contains findings of the given severity or higher.
+func (r LintReport) HasSeverity(severity LintSeverity) bool {
+	maxSeverity, ok := r.MaxSeverity()
+
+	return ok && maxSeverity >= severity
+}
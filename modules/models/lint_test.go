@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintSeverity_String(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range AllLintSeverities {
+		t.Run(s.String(), func(t *testing.T) {
+			require.NotEmpty(t, s.String())
+			require.False(t, strings.HasPrefix(s.String(), "LintSeverity("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		s := LintSeverity(100)
+
+		require.Equal(t, "LintSeverity(100)", s.String())
+	})
+}
+
+func TestLintReport_MaxSeverity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		_, ok := LintReport{}.MaxSeverity()
+
+		require.False(t, ok)
+	})
+
+	t.Run("findings", func(t *testing.T) {
+		r := LintReport{Findings: []LintFinding{
+			{Severity: LintSeverityWarning},
+			{Severity: LintSeverityError},
+			{Severity: LintSeverityInfo},
+		}}
+
+		s, ok := r.MaxSeverity()
+
+		require.True(t, ok)
+		require.Equal(t, LintSeverityError, s)
+	})
+}
+
+func TestLintReport_HasSeverity(t *testing.T) {
+	t.Parallel()
+
+	r := LintReport{Findings: []LintFinding{{Severity: LintSeverityWarning}}}
+
+	require.True(t, r.HasSeverity(LintSeverityInfo))
+	require.True(t, r.HasSeverity(LintSeverityWarning))
+	require.False(t, r.HasSeverity(LintSeverityError))
+	require.False(t, LintReport{}.HasSeverity(LintSeverityInfo))
+}
@@ -0,0 +1,141 @@
+package meshgen
+
+import (
+	"strconv"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// Default generator settings.
+const (
+	defaultNodeKind   = "bus"
+	defaultRelKind    = "line"
+	defaultFeeders    = 1
+	defaultBranching  = 0.2
+	defaultLocality   = 10
+	defaultMeshDegree = 1.5
+)
+
+// Topology defines the shape of a generated mesh.
+type Topology int
+
+// Topology enumeration.
+const (
+	// TopologyRadial generates feeders starting at a single source node. The feeders are
+	// trees, so there are exactly Nodes-1 relations.
+	TopologyRadial Topology = iota
+	// TopologyMeshed generates a connected network with loops, like a transmission grid.
+	// The relations connect nodes that are close to each other in the generation order.
+	TopologyMeshed
+	// TopologyGrid generates a rectangular grid where every node is connected to its right
+	// and lower neighbor.
+	TopologyGrid
+	// TopologyRandom generates a random graph with the given number of relations between
+	// uniformly chosen node pairs. The graph is not necessarily connected.
+	TopologyRandom
+)
+
+// AllTopologies is a list of all topologies. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllTopologies = []Topology{
+	TopologyRadial,
+	TopologyMeshed,
+	TopologyGrid,
+	TopologyRandom,
+}
+
+// String returns the string representation of the topology.
+func (t Topology) String() string {
+	switch t {
+	case TopologyRadial:
+		return "radial"
+	case TopologyMeshed:
+		return "meshed"
+	case TopologyGrid:
+		return "grid"
+	case TopologyRandom:
+		return "random"
+	}
+
+	return "Topology(" + strconv.Itoa(int(t)) + ")"
+}
+
+// KindWeight defines the relative frequency of a kind among the generated nodes or relations.
+type KindWeight struct {
+	Kind   string
+	Weight int
+}
+
+// PropSpec defines a numeric property set on every generated node or relation.
+// The values are drawn uniformly from [Min, Max] and rounded to three decimals.
+type PropSpec struct {
+	Section string
+	Key     string
+	Min     float64
+	Max     float64
+	Unit    models.Unit // stores the values as quantities of this unit (optional)
+}
+
+// Config defines the generated mesh.
+type Config struct {
+	Seed     int64  // seed of the random generator
+	ModelID  string // model ID of the mesh
+	Code     string // mesh code (optional)
+	Topology Topology
+
+	Nodes     int // number of nodes
+	Relations int // number of relations of meshed and random meshes (optional, see below)
+
+	Feeders   int      // number of radial feeders (optional, defaults to 1)
+	Branching *float64 // probability that a radial node branches off its feeder (optional, defaults to 0.2)
+	Locality  int      // max index distance of the nodes related in meshed meshes (optional, defaults to 10)
+	Width     int      // number of grid columns (optional, defaults to a square grid)
+
+	NodeKinds     []KindWeight // kind distribution of the nodes (optional, defaults to "bus")
+	RelationKinds []KindWeight // kind distribution of the relations (optional, defaults to "line")
+	NodeProps     []PropSpec   // numeric properties of the nodes (optional)
+	RelationProps []PropSpec   // numeric properties of the relations (optional)
+	Payload       int          // size of an opaque string property of every node in bytes (optional)
+}
+
+// configWithDefaults returns the config with the defaults applied to the optional fields.
+//
+// The number of relations of meshed meshes defaults to 1.5 relations per node and the number
+// of relations of random meshes to one relation per node, as far as the nodes allow. Radial
+// and grid meshes ignore it.
+func configWithDefaults(cfg Config) Config {
+	if cfg.Feeders == 0 {
+		cfg.Feeders = defaultFeeders
+	}
+
+	if cfg.Branching == nil {
+		branching := defaultBranching
+		cfg.Branching = &branching
+	}
+
+	if cfg.Locality == 0 {
+		cfg.Locality = defaultLocality
+	}
+
+	if cfg.Relations == 0 {
+		//nolint:exhaustive // the other topologies define the relations themselves
+		switch cfg.Topology {
+		case TopologyMeshed:
+			cfg.Relations = min(int(float64(cfg.Nodes)*defaultMeshDegree), localPairs(cfg.Nodes, cfg.Locality))
+		case TopologyRandom:
+			cfg.Relations = min(cfg.Nodes, localPairs(cfg.Nodes, cfg.Nodes))
+		}
+	}
+
+	if len(cfg.NodeKinds) == 0 {
+		cfg.NodeKinds = []KindWeight{{Kind: defaultNodeKind, Weight: 1}}
+	}
+
+	if len(cfg.RelationKinds) == 0 {
+		cfg.RelationKinds = []KindWeight{{Kind: defaultRelKind, Weight: 1}}
+	}
+
+	return cfg
+}
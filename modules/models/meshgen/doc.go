@@ -0,0 +1,5 @@
+// Package meshgen generates synthetic meshes for tests and benchmarks.
+//
+// The meshes are reproducible: the same config always yields the same mesh, including the
+// IDs, codes and property values.
+package meshgen
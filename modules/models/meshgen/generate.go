@@ -0,0 +1,383 @@
+package meshgen
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// Section and key of the opaque payload property.
+const (
+	PayloadSection = "meshgen"
+	PayloadKey     = "payload"
+)
+
+// propDecimals is the precision of the generated property values.
+const propDecimals = 1e3
+
+// edge is a generated relation between two node indexes.
+type edge struct {
+	from, to int
+}
+
+// generator holds the state of a single generation run.
+type generator struct {
+	cfg   Config
+	rnd   *rand.Rand
+	edges []edge
+	seen  map[edge]bool
+}
+
+// Generate generates a mesh as defined by the config.
+//
+// The node IDs are "n" followed by the zero-padded node index and the relation IDs "r"
+// followed by the zero-padded relation index, so they sort in generation order. The node
+// codes are the kind followed by the node index. Relations always lead from the node
+// generated first to the node generated later.
+//
+// It returns a validation error if the config is not consistent.
+func Generate(cfg Config) (models.Mesh, error) {
+	cfg = configWithDefaults(cfg)
+
+	if err := validateConfig(cfg); err != nil {
+		return models.Mesh{}, err
+	}
+
+	g := &generator{
+		cfg:  cfg,
+		rnd:  rand.New(rand.NewSource(cfg.Seed)), //nolint:gosec // reproducible test data
+		seen: make(map[edge]bool),
+	}
+
+	switch cfg.Topology {
+	case TopologyRadial:
+		g.radial()
+	case TopologyMeshed:
+		g.meshed()
+	case TopologyGrid:
+		g.grid()
+	case TopologyRandom:
+		g.random()
+	}
+
+	return g.mesh(), nil
+}
+
+// Contents returns the nodes and relations of the mesh ordered by ID, e.g. for a batch insert
+// into a mesh created with the code of the generated mesh.
+func Contents(mesh models.Mesh) ([]models.Node, []models.Relation) {
+	nodes := make([]models.Node, 0, len(mesh.Nodes))
+
+	for _, n := range mesh.Nodes {
+		nodes = append(nodes, n)
+	}
+
+	slices.SortFunc(nodes, func(a, b models.Node) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	relations := make([]models.Relation, 0, len(mesh.Relations))
+
+	for _, r := range mesh.Relations {
+		relations = append(relations, r)
+	}
+
+	slices.SortFunc(relations, func(a, b models.Relation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return nodes, relations
+}
+
+// validateConfig checks that the mesh defined by the config can be generated.
+func validateConfig(cfg Config) error {
+	if cfg.ModelID == "" {
+		return errorz.NewValidationError("model id is required")
+	}
+
+	if !slices.Contains(AllTopologies, cfg.Topology) {
+		return errorz.NewValidationError("invalid topology: %v", cfg.Topology)
+	}
+
+	if cfg.Nodes < 1 {
+		return errorz.NewValidationError("at least one node is required")
+	}
+
+	if err := validateTopologySettings(cfg); err != nil {
+		return err
+	}
+
+	if err := validateKindWeights(cfg.NodeKinds); err != nil {
+		return err
+	}
+
+	if err := validateKindWeights(cfg.RelationKinds); err != nil {
+		return err
+	}
+
+	for _, specs := range [][]PropSpec{cfg.NodeProps, cfg.RelationProps} {
+		if err := validatePropSpecs(specs); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Payload < 0 {
+		return errorz.NewValidationError("payload size must not be negative")
+	}
+
+	return nil
+}
+
+// validateTopologySettings checks the settings of the configured topology.
+func validateTopologySettings(cfg Config) error {
+	switch {
+	case cfg.Relations < 0 || cfg.Feeders < 0 || cfg.Locality < 0 || cfg.Width < 0:
+		return errorz.NewValidationError("generator settings must not be negative")
+	case *cfg.Branching < 0 || *cfg.Branching > 1:
+		return errorz.NewValidationError("branching must be a probability")
+	case cfg.Topology == TopologyMeshed && cfg.Relations < cfg.Nodes-1:
+		return errorz.NewValidationError("a meshed mesh of %d nodes needs at least %d relations",
+			cfg.Nodes, cfg.Nodes-1)
+	case cfg.Topology == TopologyMeshed && cfg.Relations > localPairs(cfg.Nodes, cfg.Locality):
+		return errorz.NewValidationError("a meshed mesh of %d nodes with locality %d has at most %d relations",
+			cfg.Nodes, cfg.Locality, localPairs(cfg.Nodes, cfg.Locality))
+	case cfg.Topology == TopologyRandom && cfg.Relations > localPairs(cfg.Nodes, cfg.Nodes):
+		return errorz.NewValidationError("a mesh of %d nodes has at most %d relations",
+			cfg.Nodes, localPairs(cfg.Nodes, cfg.Nodes))
+	}
+
+	return nil
+}
+
+// validateKindWeights checks that the kind distribution is usable.
+func validateKindWeights(weights []KindWeight) error {
+	for _, w := range weights {
+		if w.Kind == "" {
+			return errorz.NewValidationError("kind is required")
+		}
+
+		if w.Weight <= 0 {
+			return errorz.NewValidationError("weight of kind %s must be positive", w.Kind)
+		}
+	}
+
+	return nil
+}
+
+// validatePropSpecs checks the property specs.
+func validatePropSpecs(specs []PropSpec) error {
+	for _, s := range specs {
+		if s.Section == "" || s.Key == "" {
+			return errorz.NewValidationError("property section and key are required")
+		}
+
+		if math.IsNaN(s.Min) || math.IsNaN(s.Max) || s.Min > s.Max {
+			return errorz.NewValidationError("invalid range of property %s.%s", s.Section, s.Key)
+		}
+
+		if _, ok := s.Unit.Dimension(); s.Unit != "" && !ok {
+			return errorz.NewValidationError("unsupported unit %q", s.Unit)
+		}
+	}
+
+	return nil
+}
+
+// localPairs returns the number of node pairs whose indexes differ by at most the locality.
+func localPairs(nodes, locality int) int {
+	pairs := 0
+
+	for i := 1; i < nodes; i++ {
+		pairs += min(i, locality)
+	}
+
+	return pairs
+}
+
+// radial connects the nodes to trees starting at the source node 0. The feeders get the
+// other nodes in turn; a node continues its feeder or, with the branching probability,
+// branches off a random earlier node of the feeder.
+func (g *generator) radial() {
+	feeders := make([][]int, min(g.cfg.Feeders, g.cfg.Nodes-1))
+
+	for i := 1; i < g.cfg.Nodes; i++ {
+		f := (i - 1) % len(feeders)
+		parent := 0
+
+		if n := len(feeders[f]); n > 0 {
+			parent = feeders[f][n-1]
+
+			if g.rnd.Float64() < *g.cfg.Branching {
+				parent = feeders[f][g.rnd.Intn(n)]
+			}
+		}
+
+		g.connect(parent, i)
+		feeders[f] = append(feeders[f], i)
+	}
+}
+
+// meshed connects every node to a random close predecessor, which results in a spanning
+// tree, and adds random relations between close nodes until the relation count is reached.
+func (g *generator) meshed() {
+	locality := g.cfg.Locality
+
+	for i := 1; i < g.cfg.Nodes; i++ {
+		g.connect(i-1-g.rnd.Intn(min(i, locality)), i)
+	}
+
+	for len(g.edges) < g.cfg.Relations {
+		i := 1 + g.rnd.Intn(g.cfg.Nodes-1)
+		g.connect(i-1-g.rnd.Intn(min(i, locality)), i)
+	}
+}
+
+// grid connects every node to its right and lower neighbor. The last row may be incomplete.
+func (g *generator) grid() {
+	width := g.cfg.Width
+	if width == 0 {
+		width = int(math.Ceil(math.Sqrt(float64(g.cfg.Nodes))))
+	}
+
+	for i := range g.cfg.Nodes {
+		if i%width < width-1 && i+1 < g.cfg.Nodes {
+			g.connect(i, i+1)
+		}
+
+		if i+width < g.cfg.Nodes {
+			g.connect(i, i+width)
+		}
+	}
+}
+
+// random connects uniformly chosen node pairs until the relation count is reached.
+func (g *generator) random() {
+	for len(g.edges) < g.cfg.Relations {
+		a, b := g.rnd.Intn(g.cfg.Nodes), g.rnd.Intn(g.cfg.Nodes)
+
+		if a != b {
+			g.connect(min(a, b), max(a, b))
+		}
+	}
+}
+
+// connect adds a relation between the nodes unless they are already related.
+func (g *generator) connect(from, to int) {
+	e := edge{from: from, to: to}
+
+	if g.seen[e] {
+		return
+	}
+
+	g.seen[e] = true
+	g.edges = append(g.edges, e)
+}
+
+// mesh builds the mesh from the generated edges. The kinds and properties are drawn in
+// the order of the nodes and relations, so they do not depend on the map iteration order.
+func (g *generator) mesh() models.Mesh {
+	mesh := models.Mesh{
+		ModelID:   g.cfg.ModelID,
+		Code:      g.cfg.Code,
+		Nodes:     make(map[string]models.Node, g.cfg.Nodes),
+		Relations: make(map[string]models.Relation, len(g.edges)),
+	}
+
+	nodeIDs := make([]string, g.cfg.Nodes)
+
+	for i := range g.cfg.Nodes {
+		node := models.Node{
+			ID:    indexID("n", i, g.cfg.Nodes),
+			Kind:  g.kind(g.cfg.NodeKinds),
+			Props: g.props(g.cfg.NodeProps),
+		}
+
+		node.Code = node.Kind + "-" + strconv.Itoa(i)
+
+		if g.cfg.Payload > 0 {
+			if node.Props == nil {
+				node.Props = models.PropBag{}
+			}
+
+			node.Props[PayloadSection] = models.PropSection{PayloadKey: strings.Repeat("x", g.cfg.Payload)}
+		}
+
+		nodeIDs[i] = node.ID
+		mesh.Nodes[node.ID] = node
+	}
+
+	for i, e := range g.edges {
+		relation := models.Relation{
+			ID:    indexID("r", i, len(g.edges)),
+			Kind:  g.kind(g.cfg.RelationKinds),
+			From:  nodeIDs[e.from],
+			To:    nodeIDs[e.to],
+			Props: g.props(g.cfg.RelationProps),
+		}
+
+		mesh.Relations[relation.ID] = relation
+	}
+
+	return mesh
+}
+
+// kind draws a kind from the distribution.
+func (g *generator) kind(weights []KindWeight) string {
+	total := 0
+
+	for _, w := range weights {
+		total += w.Weight
+	}
+
+	pick := g.rnd.Intn(total)
+
+	for _, w := range weights {
+		if pick < w.Weight {
+			return w.Kind
+		}
+
+		pick -= w.Weight
+	}
+
+	return weights[len(weights)-1].Kind
+}
+
+// props draws the property values. It returns nil if there are no property specs.
+func (g *generator) props(specs []PropSpec) models.PropBag {
+	if len(specs) == 0 {
+		return nil
+	}
+
+	bag := make(models.PropBag)
+
+	for _, s := range specs {
+		value := math.Round((s.Min+g.rnd.Float64()*(s.Max-s.Min))*propDecimals) / propDecimals
+
+		if bag[s.Section] == nil {
+			bag[s.Section] = models.PropSection{}
+		}
+
+		if s.Unit != "" {
+			bag[s.Section][s.Key] = models.Quantity{Value: value, Unit: s.Unit}
+		} else {
+			bag[s.Section][s.Key] = value
+		}
+	}
+
+	return bag
+}
+
+// indexID returns the prefix followed by the index, zero-padded to the width of the largest
+// index below count.
+func indexID(prefix string, index, count int) string {
+	id := strconv.Itoa(index)
+	width := len(strconv.Itoa(max(count-1, 0)))
+
+	return prefix + strings.Repeat("0", width-len(id)) + id
+}
@@ -0,0 +1,361 @@
+package meshgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopology_String(t *testing.T) {
+	t.Parallel()
+
+	for _, tp := range AllTopologies {
+		t.Run(tp.String(), func(t *testing.T) {
+			require.NotEmpty(t, tp.String())
+			require.False(t, strings.HasPrefix(tp.String(), "Topology("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		require.Equal(t, "Topology(100)", Topology(100).String())
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg           Config
+		wantRelations int
+		wantConnected bool
+	}{
+		"radial": {
+			cfg:           Config{ModelID: "m", Topology: TopologyRadial, Nodes: 200, Feeders: 4},
+			wantRelations: 199,
+			wantConnected: true,
+		},
+		"radial-single-node": {
+			cfg:           Config{ModelID: "m", Topology: TopologyRadial, Nodes: 1},
+			wantConnected: true,
+		},
+		"meshed": {
+			cfg:           Config{ModelID: "m", Topology: TopologyMeshed, Nodes: 200},
+			wantRelations: 300,
+			wantConnected: true,
+		},
+		"meshed-dense": {
+			cfg:           Config{ModelID: "m", Topology: TopologyMeshed, Nodes: 10, Locality: 2, Relations: 17},
+			wantRelations: 17,
+			wantConnected: true,
+		},
+		"grid": {
+			cfg:           Config{ModelID: "m", Topology: TopologyGrid, Nodes: 12, Width: 4},
+			wantRelations: 17,
+			wantConnected: true,
+		},
+		"grid-incomplete": {
+			cfg:           Config{ModelID: "m", Topology: TopologyGrid, Nodes: 10},
+			wantRelations: 13,
+			wantConnected: true,
+		},
+		"random": {
+			cfg:           Config{ModelID: "m", Topology: TopologyRandom, Nodes: 100, Relations: 250},
+			wantRelations: 250,
+		},
+		"random-complete": {
+			cfg:           Config{ModelID: "m", Topology: TopologyRandom, Nodes: 5, Relations: 10},
+			wantRelations: 10,
+			wantConnected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mesh, err := Generate(test.cfg)
+
+			require.NoError(t, err)
+			require.Equal(t, test.cfg.ModelID, mesh.ModelID)
+			require.Len(t, mesh.Nodes, test.cfg.Nodes)
+			require.Len(t, mesh.Relations, test.wantRelations)
+			requireSimpleGraph(t, mesh)
+
+			if test.wantConnected {
+				require.Equal(t, test.cfg.Nodes, reachable(mesh, firstNodeID(mesh)))
+			}
+		})
+	}
+
+	t.Run("radial-tree", func(t *testing.T) {
+		mesh, err := Generate(Config{ModelID: "m", Topology: TopologyRadial, Nodes: 50, Feeders: 3,
+			Branching: probability(0.5)})
+
+		require.NoError(t, err)
+
+		parents := make(map[string]int)
+
+		for _, r := range mesh.Relations {
+			parents[r.To]++
+		}
+
+		require.Len(t, parents, 49)
+		require.NotContains(t, parents, "n00")
+
+		for _, n := range parents {
+			require.Equal(t, 1, n)
+		}
+	})
+
+	t.Run("radial-without-branching", func(t *testing.T) {
+		mesh, err := Generate(Config{ModelID: "m", Topology: TopologyRadial, Nodes: 50, Feeders: 3,
+			Branching: probability(0)})
+
+		require.NoError(t, err)
+
+		children := make(map[string]int)
+
+		for _, r := range mesh.Relations {
+			children[r.From]++
+		}
+
+		// the feeders are chains starting at the source node
+		require.Equal(t, 3, children["n00"])
+
+		for id, n := range children {
+			if id != "n00" {
+				require.Equal(t, 1, n, id)
+			}
+		}
+	})
+}
+
+func TestGenerate_reproducible(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Seed:          42,
+		ModelID:       "m",
+		Topology:      TopologyMeshed,
+		Nodes:         100,
+		NodeKinds:     []KindWeight{{Kind: "bus", Weight: 3}, {Kind: "load", Weight: 1}},
+		NodeProps:     []PropSpec{{Section: "load", Key: "kw", Min: 1, Max: 100}},
+		RelationProps: []PropSpec{{Section: "line", Key: "x", Min: 0.01, Max: 0.1}},
+	}
+
+	first, err := Generate(cfg)
+	require.NoError(t, err)
+
+	second, err := Generate(cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+
+	cfg.Seed = 43
+
+	other, err := Generate(cfg)
+	require.NoError(t, err)
+
+	require.NotEqual(t, first, other)
+}
+
+func TestGenerate_payload(t *testing.T) {
+	t.Parallel()
+
+	mesh, err := Generate(Config{
+		ModelID:   "m",
+		Code:      "synthetic",
+		Topology:  TopologyGrid,
+		Nodes:     400,
+		NodeKinds: []KindWeight{{Kind: "bus", Weight: 1}, {Kind: "load", Weight: 1}},
+		NodeProps: []PropSpec{
+			{Section: "electrical", Key: "voltage", Min: 10, Max: 20, Unit: models.UnitKilovolt},
+			{Section: "load", Key: "kw", Min: -5, Max: 5},
+		},
+		RelationKinds: []KindWeight{{Kind: "cable", Weight: 1}},
+		Payload:       64,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "synthetic", mesh.Code)
+
+	kinds := make(map[string]int)
+	codes := make(map[string]bool)
+
+	for id, n := range mesh.Nodes {
+		require.Equal(t, id, n.ID)
+		require.False(t, codes[n.Code])
+		require.True(t, strings.HasPrefix(n.Code, n.Kind+"-"))
+
+		codes[n.Code] = true
+		kinds[n.Kind]++
+
+		voltage, ok := n.Props["electrical"]["voltage"].(models.Quantity)
+		require.True(t, ok)
+		require.Equal(t, models.UnitKilovolt, voltage.Unit)
+		require.GreaterOrEqual(t, voltage.Value, 10.0)
+		require.LessOrEqual(t, voltage.Value, 20.0)
+
+		kw, ok := n.Props["load"]["kw"].(float64)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, kw, -5.0)
+		require.LessOrEqual(t, kw, 5.0)
+
+		require.Len(t, n.Props[PayloadSection][PayloadKey], 64)
+	}
+
+	// both kinds are drawn with the same weight
+	require.Len(t, kinds, 2)
+	require.InDelta(t, 200, kinds["bus"], 50)
+
+	for _, r := range mesh.Relations {
+		require.Equal(t, "cable", r.Kind)
+		require.Nil(t, r.Props)
+	}
+}
+
+func TestGenerate_invalid(t *testing.T) {
+	t.Parallel()
+
+	valid := Config{ModelID: "m", Topology: TopologyMeshed, Nodes: 10}
+
+	tests := map[string]func(cfg *Config){
+		"no-model-id":        func(cfg *Config) { cfg.ModelID = "" },
+		"invalid-topology":   func(cfg *Config) { cfg.Topology = Topology(100) },
+		"no-nodes":           func(cfg *Config) { cfg.Nodes = 0 },
+		"negative-setting":   func(cfg *Config) { cfg.Width = -1 },
+		"invalid-branching":  func(cfg *Config) { cfg.Branching = probability(2) },
+		"too-few-relations":  func(cfg *Config) { cfg.Relations = 5 },
+		"too-many-relations": func(cfg *Config) { cfg.Relations = 50; cfg.Locality = 2 },
+		"too-many-random": func(cfg *Config) {
+			cfg.Topology = TopologyRandom
+			cfg.Relations = 46
+		},
+		"no-kind":         func(cfg *Config) { cfg.NodeKinds = []KindWeight{{Weight: 1}} },
+		"zero-weight":     func(cfg *Config) { cfg.RelationKinds = []KindWeight{{Kind: "line"}} },
+		"no-prop-key":     func(cfg *Config) { cfg.NodeProps = []PropSpec{{Section: "s"}} },
+		"invalid-range":   func(cfg *Config) { cfg.RelationProps = []PropSpec{{Section: "s", Key: "k", Min: 2, Max: 1}} },
+		"invalid-unit":    func(cfg *Config) { cfg.NodeProps = []PropSpec{{Section: "s", Key: "k", Unit: "parsec"}} },
+		"invalid-payload": func(cfg *Config) { cfg.Payload = -1 },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			modify(&cfg)
+
+			mesh, err := Generate(cfg)
+
+			require.IsType(t, errorz.ValidationError{}, err)
+			require.Empty(t, mesh)
+		})
+	}
+}
+
+func TestContents(t *testing.T) {
+	t.Parallel()
+
+	mesh, err := Generate(Config{ModelID: "m", Topology: TopologyRandom, Nodes: 20, Relations: 30})
+	require.NoError(t, err)
+
+	nodes, relations := Contents(mesh)
+
+	require.Len(t, nodes, 20)
+	require.Len(t, relations, 30)
+	require.Equal(t, "n00", nodes[0].ID)
+	require.Equal(t, "n19", nodes[19].ID)
+	require.Equal(t, "r00", relations[0].ID)
+	require.Equal(t, "r29", relations[29].ID)
+
+	for _, n := range nodes {
+		require.Equal(t, mesh.Nodes[n.ID], n)
+	}
+
+	for _, r := range relations {
+		require.Equal(t, mesh.Relations[r.ID], r)
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, tp := range AllTopologies {
+		b.Run(tp.String(), func(b *testing.B) {
+			cfg := Config{
+				ModelID:   "m",
+				Topology:  tp,
+				Nodes:     10000,
+				NodeProps: []PropSpec{{Section: "load", Key: "kw", Min: 0, Max: 100}},
+			}
+
+			for range b.N {
+				if _, err := Generate(cfg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// requireSimpleGraph checks that the relations lead from earlier to later nodes of the mesh
+// and that no node pair is related twice.
+func requireSimpleGraph(t *testing.T, mesh models.Mesh) {
+	t.Helper()
+
+	pairs := make(map[[2]string]bool)
+
+	for id, r := range mesh.Relations {
+		require.Equal(t, id, r.ID)
+		require.Contains(t, mesh.Nodes, r.From)
+		require.Contains(t, mesh.Nodes, r.To)
+		require.Less(t, r.From, r.To)
+
+		pair := [2]string{r.From, r.To}
+		require.False(t, pairs[pair])
+		pairs[pair] = true
+	}
+}
+
+// reachable returns the number of nodes reachable from the start node in any direction.
+func reachable(mesh models.Mesh, start string) int {
+	neighbors := make(map[string][]string)
+
+	for _, r := range mesh.Relations {
+		neighbors[r.From] = append(neighbors[r.From], r.To)
+		neighbors[r.To] = append(neighbors[r.To], r.From)
+	}
+
+	visited := map[string]bool{start: true}
+	pending := []string{start}
+
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for _, n := range neighbors[id] {
+			if !visited[n] {
+				visited[n] = true
+				pending = append(pending, n)
+			}
+		}
+	}
+
+	return len(visited)
+}
+
+// firstNodeID returns the smallest node ID of the mesh.
+func firstNodeID(mesh models.Mesh) string {
+	first := ""
+
+	for id := range mesh.Nodes {
+		if first == "" || id < first {
+			first = id
+		}
+	}
+
+	return first
+}
+
+// probability returns a pointer to the probability, as used by the optional config settings.
+func probability(p float64) *float64 {
+	return &p
+}
@@ -1,11 +1,20 @@
 package models
 
+import (
+	"slices"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
+
 // Model defines a model.
 type Model struct {
 	ID          string
 	Code        string
 	Name        string
 	Description string
+	Status      ModelStatus
+	Labels      map[string]string // key/value labels used to organize and select models
+	Version     int64             // version of the stored model, used to detect concurrent status changes
 }
 
 // Mesh represents a model mesh.
@@ -20,7 +29,7 @@ type Mesh struct {
 type Node struct {
 	ID    string  // public ID
 	Kind  string  // node kind/type
-	Code  string  // node code (optional)
+	Code  string  // node code (optional, unique within the mesh)
 	Props PropBag // custom node properties
 }
 
@@ -31,6 +40,10 @@ type Relation struct {
 	From  string  // public ID of the start node
 	To    string  // public ID of the end node
 	Props PropBag // custom relation properties
+
+	// External marks a relation of an extracted submesh that leads to a node outside of it.
+	// It is set by the mesh slicing and is not persisted.
+	External bool
 }
 
 // PropBag represents a set of property sets.
@@ -39,3 +52,32 @@ type PropBag map[string]PropSection
 
 // PropSection represents a set of named properties.
 type PropSection map[string]any
+
+// CheckNodeCodes checks that no two nodes share a non-empty code. It returns a conflict
+// error for the first shared code, in the order of the node IDs.
+func CheckNodeCodes(nodes map[string]Node) error {
+	ids := make([]string, 0, len(nodes))
+
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	codes := make(map[string]string, len(nodes))
+
+	for _, id := range ids {
+		code := nodes[id].Code
+		if code == "" {
+			continue
+		}
+
+		if other, ok := codes[code]; ok {
+			return errorz.NewConflictError("node code %s is used by nodes %s and %s", code, other, id)
+		}
+
+		codes[code] = id
+	}
+
+	return nil
+}
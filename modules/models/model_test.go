@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckNodeCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		nodes   map[string]Node
+		wantErr error
+	}{
+		"empty": {},
+		"unique": {
+			nodes: map[string]Node{"n1": {ID: "n1", Code: "A"}, "n2": {ID: "n2", Code: "B"}},
+		},
+		"without-codes": {
+			nodes: map[string]Node{"n1": {ID: "n1"}, "n2": {ID: "n2"}},
+		},
+		"duplicate": {
+			nodes:   map[string]Node{"n1": {ID: "n1", Code: "A"}, "n2": {ID: "n2", Code: "A"}},
+			wantErr: errorz.ConflictError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckNodeCodes(test.nodes)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.EqualError(t, err, "node code A is used by nodes n1 and n2")
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package powerflow
+
+// Default solver settings.
+const (
+	defaultBaseMVA      = 100
+	defaultTolerance    = 1e-10
+	minIterationsFactor = 10
+)
+
+// PropRef references a property by its section and key.
+type PropRef struct {
+	Section string
+	Key     string
+}
+
+// Config defines how a mesh is mapped onto the power flow problem.
+//
+// The property references allow the calculation to follow the kind schemas of the
+// deployment. Numeric properties may hold any integer or floating point value, taken to be
+// in the documented unit, or a quantity that converts to that unit.
+type Config struct {
+	Reactance  PropRef  // relation property holding the series reactance in p.u.
+	Generation PropRef  // node property holding the active power generation in MW
+	Load       PropRef  // node property holding the active power load in MW
+	Kinds      []string // relation kinds treated as branches (optional, all kinds if empty)
+	SlackBuses []string // preferred slack node IDs, in order of preference (optional)
+	BaseMVA    float64  // system base power (optional, defaults to 100)
+
+	Tolerance     float64 // relative residual at which the solver stops (optional)
+	MaxIterations int     // max number of solver iterations per island (optional)
+}
+
+// configWithDefaults returns the config with the defaults applied to the optional fields.
+// The iteration limit depends on the island size and is resolved by the solver.
+func configWithDefaults(cfg Config) Config {
+	if cfg.BaseMVA == 0 {
+		cfg.BaseMVA = defaultBaseMVA
+	}
+
+	if cfg.Tolerance == 0 {
+		cfg.Tolerance = defaultTolerance
+	}
+
+	return cfg
+}
@@ -0,0 +1,2 @@
+// Package powerflow implements power flow calculations over model meshes.
+package powerflow
@@ -0,0 +1,346 @@
+package powerflow
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// Island describes an electrically connected part of the mesh.
+type Island struct {
+	Slack          string   // public ID of the slack node
+	Nodes          []string // public IDs of the nodes, sorted
+	SlackInjection float64  // net active power injected at the slack node in MW
+}
+
+// Result holds the result of a DC power flow calculation.
+type Result struct {
+	ModelID string
+	Islands []Island           // islands, ordered by their smallest node ID
+	Angles  map[string]float64 // node ID -> voltage angle in radians
+	Flows   map[string]float64 // relation ID -> active power flow from From to To in MW
+}
+
+// branch is a relation that carries power.
+type branch struct {
+	id          string
+	from, to    string
+	susceptance float64
+}
+
+// Solve runs a DC power flow calculation on the given in-memory mesh.
+//
+// The relations with a reactance are the branches of the network; the branches split the
+// mesh into islands that are solved independently. The net injection of a node is its
+// generation minus its load, missing values count as zero. In every island, the first
+// configured slack bus is used as the angle reference and balances the injections; if none
+// is configured, the node with the largest net injection is used.
+//
+// It returns a validation error if a property is not numeric or has a unit that cannot be
+// converted, a reactance is not positive or a branch references a missing node, and an
+// internal error if the solver does not converge.
+func Solve(mesh models.Mesh, cfg Config) (Result, error) {
+	cfg = configWithDefaults(cfg)
+
+	if err := validateConfig(cfg); err != nil {
+		return Result{}, err
+	}
+
+	injections, err := nodeInjections(mesh, cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	branches, err := meshBranches(mesh, cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		ModelID: mesh.ModelID,
+		Angles:  make(map[string]float64, len(mesh.Nodes)),
+		Flows:   make(map[string]float64, len(branches)),
+	}
+
+	for _, nodes := range islands(mesh, branches) {
+		island, err := solveIsland(nodes, branches, injections, cfg, result)
+		if err != nil {
+			return Result{}, err
+		}
+
+		result.Islands = append(result.Islands, island)
+	}
+
+	return result, nil
+}
+
+// validateConfig checks that the property references and solver settings are usable.
+func validateConfig(cfg Config) error {
+	refs := []struct {
+		name string
+		ref  PropRef
+	}{
+		{"reactance", cfg.Reactance},
+		{"generation", cfg.Generation},
+		{"load", cfg.Load},
+	}
+
+	for _, r := range refs {
+		if r.ref.Section == "" || r.ref.Key == "" {
+			return errorz.NewValidationError("%s property section and key are required", r.name)
+		}
+	}
+
+	if cfg.BaseMVA < 0 {
+		return errorz.NewValidationError("base power must be positive")
+	}
+
+	if cfg.Tolerance < 0 || cfg.MaxIterations < 0 {
+		return errorz.NewValidationError("solver settings must not be negative")
+	}
+
+	return nil
+}
+
+// nodeInjections returns the net injection of every node in MW.
+func nodeInjections(mesh models.Mesh, cfg Config) (map[string]float64, error) {
+	injections := make(map[string]float64, len(mesh.Nodes))
+
+	for id, n := range mesh.Nodes {
+		gen, err := numericProp(n.Props, cfg.Generation, models.UnitMegawatt, "node", id)
+		if err != nil {
+			return nil, err
+		}
+
+		load, err := numericProp(n.Props, cfg.Load, models.UnitMegawatt, "node", id)
+		if err != nil {
+			return nil, err
+		}
+
+		injections[id] = gen - load
+	}
+
+	return injections, nil
+}
+
+// meshBranches returns the branches of the mesh, sorted by relation ID.
+// If branch kinds are configured, the relations of these kinds must have a reactance.
+// Otherwise, all relations with a reactance are branches.
+func meshBranches(mesh models.Mesh, cfg Config) ([]branch, error) {
+	var branches []branch
+
+	for id, r := range mesh.Relations {
+		if len(cfg.Kinds) > 0 && !slices.Contains(cfg.Kinds, r.Kind) {
+			continue
+		}
+
+		section := r.Props[cfg.Reactance.Section]
+		if _, ok := section[cfg.Reactance.Key]; !ok && len(cfg.Kinds) == 0 {
+			continue
+		}
+
+		x, ok := section.ValueIn(cfg.Reactance.Key, models.UnitPerUnit)
+		if !ok {
+			return nil, errorz.NewValidationError("relation %s: reactance %s.%s is missing or not numeric in p.u.",
+				id, cfg.Reactance.Section, cfg.Reactance.Key)
+		}
+
+		if x <= 0 {
+			return nil, errorz.NewValidationError("relation %s: reactance must be positive", id)
+		}
+
+		if _, ok := mesh.Nodes[r.From]; !ok {
+			return nil, errorz.NewValidationError("relation %s: node %s not found", id, r.From)
+		}
+
+		if _, ok := mesh.Nodes[r.To]; !ok {
+			return nil, errorz.NewValidationError("relation %s: node %s not found", id, r.To)
+		}
+
+		branches = append(branches, branch{id: id, from: r.From, to: r.To, susceptance: 1 / x})
+	}
+
+	slices.SortFunc(branches, func(a, b branch) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	return branches, nil
+}
+
+// islands splits the nodes into the sets connected by branches.
+// The node IDs of an island are sorted and the islands are ordered by their first node.
+func islands(mesh models.Mesh, branches []branch) [][]string {
+	parent := make(map[string]string, len(mesh.Nodes))
+
+	var find func(id string) string
+
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+
+		return parent[id]
+	}
+
+	for id := range mesh.Nodes {
+		parent[id] = id
+	}
+
+	for _, b := range branches {
+		parent[find(b.from)] = find(b.to)
+	}
+
+	groups := make(map[string][]string)
+
+	for id := range mesh.Nodes {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	result := make([][]string, 0, len(groups))
+
+	for _, nodes := range groups {
+		slices.Sort(nodes)
+		result = append(result, nodes)
+	}
+
+	slices.SortFunc(result, func(a, b []string) int {
+		return cmp.Compare(a[0], b[0])
+	})
+
+	return result
+}
+
+// solveIsland solves the island and writes the angles and flows into the result.
+func solveIsland(
+	nodes []string,
+	branches []branch,
+	injections map[string]float64,
+	cfg Config,
+	result Result,
+) (Island, error) {
+	slack := slackBus(nodes, injections, cfg)
+
+	index := make(map[string]int, len(nodes)-1)
+
+	for _, id := range nodes {
+		if id != slack {
+			index[id] = len(index)
+		}
+	}
+
+	// both ends of a branch are always in the same island
+	inIsland := func(b branch) bool {
+		_, ok := index[b.from]
+
+		return ok || b.from == slack
+	}
+
+	var entries []triplet
+
+	for _, b := range branches {
+		if !inIsland(b) || b.from == b.to {
+			continue
+		}
+
+		i, fromOK := index[b.from]
+		j, toOK := index[b.to]
+
+		if fromOK {
+			entries = append(entries, triplet{i, i, b.susceptance})
+		}
+
+		if toOK {
+			entries = append(entries, triplet{j, j, b.susceptance})
+		}
+
+		if fromOK && toOK {
+			entries = append(entries, triplet{i, j, -b.susceptance}, triplet{j, i, -b.susceptance})
+		}
+	}
+
+	rhs := make([]float64, len(index))
+
+	for id, i := range index {
+		rhs[i] = injections[id] / cfg.BaseMVA
+	}
+
+	maxIter := cfg.MaxIterations
+	if maxIter == 0 {
+		maxIter = minIterationsFactor * (len(index) + 1)
+	}
+
+	angles, ok := solveCG(newCSRMatrix(len(index), entries), rhs, cfg.Tolerance, maxIter)
+	if !ok {
+		return Island{}, errorz.NewInternalError("power flow of the island of node %s did not converge", slack)
+	}
+
+	result.Angles[slack] = 0
+
+	for id, i := range index {
+		result.Angles[id] = angles[i]
+	}
+
+	island := Island{
+		Slack: slack,
+		Nodes: nodes,
+	}
+
+	for _, b := range branches {
+		if !inIsland(b) {
+			continue
+		}
+
+		flow := cfg.BaseMVA * (result.Angles[b.from] - result.Angles[b.to]) * b.susceptance
+		result.Flows[b.id] = flow
+
+		if b.from == slack {
+			island.SlackInjection += flow
+		}
+
+		if b.to == slack {
+			island.SlackInjection -= flow
+		}
+	}
+
+	return island, nil
+}
+
+// slackBus selects the slack bus of the island: the first configured slack bus in the
+// island, or else the node with the largest positive injection, or else the first node.
+func slackBus(nodes []string, injections map[string]float64, cfg Config) string {
+	for _, id := range cfg.SlackBuses {
+		if slices.Contains(nodes, id) {
+			return id
+		}
+	}
+
+	slack := nodes[0]
+
+	for _, id := range nodes[1:] {
+		if injections[id] > injections[slack] {
+			slack = id
+		}
+	}
+
+	return slack
+}
+
+// numericProp returns the value of the referenced property in the given unit.
+// A missing property is zero.
+func numericProp(bag models.PropBag, ref PropRef, unit models.Unit, owner, id string) (float64, error) {
+	section := bag[ref.Section]
+	if section[ref.Key] == nil {
+		return 0, nil
+	}
+
+	v, ok := section.ValueIn(ref.Key, unit)
+	if !ok {
+		return 0, errorz.NewValidationError("%s %s: property %s.%s is not numeric in %s",
+			owner, id, ref.Section, ref.Key, unit)
+	}
+
+	return v, nil
+}
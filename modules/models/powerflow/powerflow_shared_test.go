@@ -0,0 +1,153 @@
+package powerflow
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+//nolint:gochecknoglobals
+var testConfig = Config{
+	Reactance:  PropRef{Section: "electrical", Key: "x"},
+	Generation: PropRef{Section: "electrical", Key: "pg"},
+	Load:       PropRef{Section: "electrical", Key: "pd"},
+}
+
+// ieee14Mesh returns the IEEE 14-bus test case. The buses are named "1" to "14" and the
+// branches "b1" to "b20". The reactances are in p.u. on a 100 MVA base; the tap ratios of
+// the transformers are not modeled.
+func ieee14Mesh() models.Mesh {
+	branches := []struct {
+		from, to int
+		x        float64
+	}{
+		{1, 2, 0.05917}, {1, 5, 0.22304}, {2, 3, 0.19797}, {2, 4, 0.17632},
+		{2, 5, 0.17388}, {3, 4, 0.17103}, {4, 5, 0.04211}, {4, 7, 0.20912},
+		{4, 9, 0.55618}, {5, 6, 0.25202}, {6, 11, 0.19890}, {6, 12, 0.25581},
+		{6, 13, 0.13027}, {7, 8, 0.17615}, {7, 9, 0.11001}, {9, 10, 0.08450},
+		{9, 14, 0.27038}, {10, 11, 0.19207}, {12, 13, 0.19988}, {13, 14, 0.34802},
+	}
+
+	loads := []float64{0, 21.7, 94.2, 47.8, 7.6, 11.2, 0, 0, 29.5, 9, 3.5, 6.1, 13.5, 14.9}
+	generation := map[int]float64{1: 232.4, 2: 40}
+
+	mesh := models.Mesh{
+		ModelID:   "ieee14",
+		Nodes:     make(map[string]models.Node),
+		Relations: make(map[string]models.Relation),
+	}
+
+	for i, pd := range loads {
+		bus := i + 1
+		props := models.PropSection{"pd": pd}
+
+		if pg, ok := generation[bus]; ok {
+			props["pg"] = pg
+		}
+
+		id := strconv.Itoa(bus)
+		mesh.Nodes[id] = models.Node{ID: id, Kind: "bus", Props: models.PropBag{"electrical": props}}
+	}
+
+	for i, b := range branches {
+		id := "b" + strconv.Itoa(i+1)
+		mesh.Relations[id] = models.Relation{
+			ID:    id,
+			Kind:  "line",
+			From:  strconv.Itoa(b.from),
+			To:    strconv.Itoa(b.to),
+			Props: models.PropBag{"electrical": {"x": b.x}},
+		}
+	}
+
+	return mesh
+}
+
+// referenceAngles solves the DC power flow of a connected mesh with dense Gaussian
+// elimination. It is an independent reference for the sparse solver.
+func referenceAngles(mesh models.Mesh, slack string, baseMVA float64) map[string]float64 {
+	var ids []string
+
+	for id := range mesh.Nodes {
+		if id != slack {
+			ids = append(ids, id)
+		}
+	}
+
+	index := make(map[string]int, len(ids))
+
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	n := len(ids)
+	a := make([][]float64, n)
+
+	for i := range a {
+		a[i] = make([]float64, n+1)
+		p := mesh.Nodes[ids[i]].Props["electrical"]
+		pg, _ := models.NumericValue(p["pg"])
+		pd, _ := models.NumericValue(p["pd"])
+		a[i][n] = (pg - pd) / baseMVA
+	}
+
+	for _, r := range mesh.Relations {
+		x, _ := models.NumericValue(r.Props["electrical"]["x"])
+		i, fromOK := index[r.From]
+		j, toOK := index[r.To]
+
+		if fromOK {
+			a[i][i] += 1 / x
+		}
+
+		if toOK {
+			a[j][j] += 1 / x
+		}
+
+		if fromOK && toOK {
+			a[i][j] -= 1 / x
+			a[j][i] -= 1 / x
+		}
+	}
+
+	for k := range n {
+		pivot := k
+
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[pivot][k]) {
+				pivot = i
+			}
+		}
+
+		a[k], a[pivot] = a[pivot], a[k]
+
+		for i := k + 1; i < n; i++ {
+			f := a[i][k] / a[k][k]
+
+			for j := k; j <= n; j++ {
+				a[i][j] -= f * a[k][j]
+			}
+		}
+	}
+
+	theta := make([]float64, n)
+
+	for i := n - 1; i >= 0; i-- {
+		sum := a[i][n]
+
+		for j := i + 1; j < n; j++ {
+			sum -= a[i][j] * theta[j]
+		}
+
+		theta[i] = sum / a[i][i]
+	}
+
+	angles := map[string]float64{slack: 0}
+
+	for id, i := range index {
+		angles[id] = theta[i]
+	}
+
+	return angles
+}
@@ -0,0 +1,260 @@
+package powerflow
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolve_ieee14(t *testing.T) {
+	t.Parallel()
+
+	mesh := ieee14Mesh()
+
+	result, err := Solve(mesh, testConfig)
+
+	require.NoError(t, err)
+	require.Equal(t, "ieee14", result.ModelID)
+	require.Len(t, result.Islands, 1)
+	require.Equal(t, "1", result.Islands[0].Slack)
+	require.Len(t, result.Islands[0].Nodes, 14)
+	require.Len(t, result.Flows, 20)
+
+	// the slack bus balances the total load of 259 MW minus the 40 MW of bus 2
+	require.InDelta(t, 219, result.Islands[0].SlackInjection, 1e-6)
+
+	// the branches 1-2 and 1-5 carry the slack injection
+	require.InDelta(t, 219, result.Flows["b1"]+result.Flows["b2"], 1e-6)
+
+	reference := referenceAngles(mesh, "1", defaultBaseMVA)
+
+	for id, angle := range reference {
+		require.InDelta(t, angle, result.Angles[id], 1e-8, id)
+	}
+
+	requireBalanced(t, mesh, result)
+}
+
+func TestSolve_quantities(t *testing.T) {
+	t.Parallel()
+
+	plain, err := Solve(ieee14Mesh(), testConfig)
+
+	require.NoError(t, err)
+
+	mesh := ieee14Mesh()
+
+	for _, n := range mesh.Nodes {
+		props := n.Props["electrical"]
+
+		if pg, ok := props["pg"].(float64); ok {
+			props["pg"] = models.Quantity{Value: pg, Unit: models.UnitMegawatt}
+		}
+
+		pd, _ := props["pd"].(float64)
+		props["pd"] = models.Quantity{Value: pd * 1e3, Unit: models.UnitKilowatt}
+	}
+
+	for _, r := range mesh.Relations {
+		props := r.Props["electrical"]
+		x, _ := props["x"].(float64)
+		props["x"] = models.Quantity{Value: x, Unit: models.UnitPerUnit}
+	}
+
+	result, err := Solve(mesh, testConfig)
+
+	require.NoError(t, err)
+	require.InDelta(t, plain.Islands[0].SlackInjection, result.Islands[0].SlackInjection, 1e-6)
+
+	for id, flow := range plain.Flows {
+		require.InDelta(t, flow, result.Flows[id], 1e-6, id)
+	}
+}
+
+func TestSolve_slackBuses(t *testing.T) {
+	t.Parallel()
+
+	mesh := ieee14Mesh()
+	cfg := testConfig
+	cfg.SlackBuses = []string{"missing", "2"}
+
+	result, err := Solve(mesh, cfg)
+
+	require.NoError(t, err)
+	require.Equal(t, "2", result.Islands[0].Slack)
+	require.InDelta(t, 0, result.Angles["2"], 0)
+
+	reference := referenceAngles(mesh, "2", defaultBaseMVA)
+
+	for id, angle := range reference {
+		require.InDelta(t, angle, result.Angles[id], 1e-8, id)
+	}
+
+	// the net injection of bus 2 covers the load not served by bus 1
+	require.InDelta(t, 259-232.4-21.7, result.Islands[0].SlackInjection, 1e-6)
+
+	requireBalanced(t, mesh, result)
+}
+
+func TestSolve_islands(t *testing.T) {
+	t.Parallel()
+
+	mesh := ieee14Mesh()
+
+	// split off the buses 7 and 8 and add a standalone bus 15
+	delete(mesh.Relations, "b8")
+	delete(mesh.Relations, "b15")
+	mesh.Nodes["8"].Props["electrical"]["pg"] = 10
+	mesh.Nodes["7"].Props["electrical"]["pd"] = 10
+	mesh.Nodes["15"] = models.Node{ID: "15", Kind: "bus"}
+
+	// relations without a reactance are not branches
+	mesh.Relations["c1"] = models.Relation{ID: "c1", Kind: "contains", From: "15", To: "1"}
+
+	result, err := Solve(mesh, testConfig)
+
+	require.NoError(t, err)
+	require.Len(t, result.Islands, 3)
+	require.Equal(t, "1", result.Islands[0].Slack)
+	require.Len(t, result.Islands[0].Nodes, 12)
+	require.Equal(t, "15", result.Islands[1].Slack)
+	require.Equal(t, []string{"15"}, result.Islands[1].Nodes)
+	require.Equal(t, "8", result.Islands[2].Slack)
+	require.Equal(t, []string{"7", "8"}, result.Islands[2].Nodes)
+	require.InDelta(t, -10, result.Flows["b14"], 1e-9)
+	require.NotContains(t, result.Flows, "c1")
+
+	requireBalanced(t, mesh, result)
+}
+
+func TestSolve_kinds(t *testing.T) {
+	t.Parallel()
+
+	mesh := ieee14Mesh()
+	mesh.Relations["c1"] = models.Relation{ID: "c1", Kind: "contains", From: "1", To: "2"}
+
+	cfg := testConfig
+	cfg.Kinds = []string{"line"}
+
+	result, err := Solve(mesh, cfg)
+
+	require.NoError(t, err)
+	require.Len(t, result.Flows, 20)
+
+	cfg.Kinds = []string{"line", "contains"}
+
+	_, err = Solve(mesh, cfg)
+
+	require.IsType(t, errorz.ValidationError{}, err)
+}
+
+func TestSolve_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modify  func(mesh models.Mesh, cfg *Config)
+		wantErr error
+	}{
+		"missing-reactance-ref": {
+			modify:  func(_ models.Mesh, cfg *Config) { cfg.Reactance = PropRef{} },
+			wantErr: errorz.ValidationError{},
+		},
+		"missing-load-key": {
+			modify:  func(_ models.Mesh, cfg *Config) { cfg.Load.Key = "" },
+			wantErr: errorz.ValidationError{},
+		},
+		"negative-base": {
+			modify:  func(_ models.Mesh, cfg *Config) { cfg.BaseMVA = -1 },
+			wantErr: errorz.ValidationError{},
+		},
+		"negative-iterations": {
+			modify:  func(_ models.Mesh, cfg *Config) { cfg.MaxIterations = -1 },
+			wantErr: errorz.ValidationError{},
+		},
+		"non-numeric-load": {
+			modify:  func(mesh models.Mesh, _ *Config) { mesh.Nodes["3"].Props["electrical"]["pd"] = "94.2" },
+			wantErr: errorz.ValidationError{},
+		},
+		"non-numeric-reactance": {
+			modify:  func(mesh models.Mesh, _ *Config) { mesh.Relations["b3"].Props["electrical"]["x"] = "0.2" },
+			wantErr: errorz.ValidationError{},
+		},
+		"load-not-power": {
+			modify: func(mesh models.Mesh, _ *Config) {
+				mesh.Nodes["3"].Props["electrical"]["pd"] = models.Quantity{Value: 20, Unit: models.UnitKilovolt}
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"reactance-in-ohm": {
+			modify: func(mesh models.Mesh, _ *Config) {
+				mesh.Relations["b3"].Props["electrical"]["x"] = models.Quantity{Value: 0.8, Unit: models.UnitOhm}
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"zero-reactance": {
+			modify:  func(mesh models.Mesh, _ *Config) { mesh.Relations["b3"].Props["electrical"]["x"] = 0 },
+			wantErr: errorz.ValidationError{},
+		},
+		"dangling-branch": {
+			modify: func(mesh models.Mesh, _ *Config) {
+				mesh.Relations["b21"] = models.Relation{
+					ID: "b21", From: "1", To: "99", Props: models.PropBag{"electrical": {"x": 0.1}},
+				}
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"not-converged": {
+			modify:  func(_ models.Mesh, cfg *Config) { cfg.MaxIterations = 1 },
+			wantErr: errorz.InternalError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mesh := ieee14Mesh()
+			cfg := testConfig
+
+			test.modify(mesh, &cfg)
+
+			result, err := Solve(mesh, cfg)
+
+			require.Error(t, err)
+			require.IsType(t, test.wantErr, err)
+			require.Empty(t, result)
+		})
+	}
+}
+
+// requireBalanced checks that the flows satisfy the power balance at every non-slack node.
+func requireBalanced(t *testing.T, mesh models.Mesh, result Result) {
+	t.Helper()
+
+	balance := make(map[string]float64)
+
+	for id, flow := range result.Flows {
+		r := mesh.Relations[id]
+		balance[r.From] += flow
+		balance[r.To] -= flow
+	}
+
+	slacks := make(map[string]bool)
+
+	for _, island := range result.Islands {
+		slacks[island.Slack] = true
+
+		require.InDelta(t, island.SlackInjection, balance[island.Slack], 1e-6)
+	}
+
+	for id, n := range mesh.Nodes {
+		if slacks[id] {
+			continue
+		}
+
+		pg, _ := models.NumericValue(n.Props["electrical"]["pg"])
+		pd, _ := models.NumericValue(n.Props["electrical"]["pd"])
+
+		require.InDelta(t, pg-pd, balance[id], 1e-6, id)
+	}
+}
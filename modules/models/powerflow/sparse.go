@@ -0,0 +1,161 @@
+package powerflow
+
+import (
+	"math"
+	"sort"
+)
+
+// triplet is a single entry of a sparse matrix under construction.
+type triplet struct {
+	row, col int
+	value    float64
+}
+
+// csrMatrix is a square sparse matrix in the compressed sparse row format.
+type csrMatrix struct {
+	n      int
+	rowPtr []int
+	cols   []int
+	values []float64
+}
+
+// newCSRMatrix builds an n x n matrix from the triplets. Entries with the same row and
+// column are summed up.
+func newCSRMatrix(n int, entries []triplet) csrMatrix {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].row != entries[j].row {
+			return entries[i].row < entries[j].row
+		}
+
+		return entries[i].col < entries[j].col
+	})
+
+	m := csrMatrix{
+		n:      n,
+		rowPtr: make([]int, n+1),
+	}
+
+	for i, e := range entries {
+		if i > 0 && entries[i-1].row == e.row && entries[i-1].col == e.col {
+			m.values[len(m.values)-1] += e.value
+
+			continue
+		}
+
+		m.cols = append(m.cols, e.col)
+		m.values = append(m.values, e.value)
+		m.rowPtr[e.row+1]++
+	}
+
+	for i := range n {
+		m.rowPtr[i+1] += m.rowPtr[i]
+	}
+
+	return m
+}
+
+// mulVec computes y = M * x.
+func (m csrMatrix) mulVec(x, y []float64) {
+	for i := range m.n {
+		var sum float64
+
+		for k := m.rowPtr[i]; k < m.rowPtr[i+1]; k++ {
+			sum += m.values[k] * x[m.cols[k]]
+		}
+
+		y[i] = sum
+	}
+}
+
+// diagonal returns the diagonal of the matrix.
+func (m csrMatrix) diagonal() []float64 {
+	d := make([]float64, m.n)
+
+	for i := range m.n {
+		for k := m.rowPtr[i]; k < m.rowPtr[i+1]; k++ {
+			if m.cols[k] == i {
+				d[i] = m.values[k]
+			}
+		}
+	}
+
+	return d
+}
+
+// solveCG solves M * x = b for a symmetric positive definite matrix with the conjugate
+// gradient method, preconditioned with the diagonal of the matrix. It stops when the
+// residual norm drops below tol times the norm of b. It returns false if the solver did not
+// converge within maxIter iterations.
+func solveCG(m csrMatrix, b []float64, tol float64, maxIter int) ([]float64, bool) {
+	n := m.n
+	x := make([]float64, n)
+
+	bNorm := norm(b)
+	if bNorm == 0 {
+		return x, true
+	}
+
+	inv := m.diagonal()
+	for i, d := range inv {
+		inv[i] = 1 / d
+	}
+
+	r := make([]float64, n)
+	copy(r, b)
+
+	z := make([]float64, n)
+	for i := range z {
+		z[i] = inv[i] * r[i]
+	}
+
+	p := make([]float64, n)
+	copy(p, z)
+
+	q := make([]float64, n)
+	rz := dot(r, z)
+
+	for range maxIter {
+		m.mulVec(p, q)
+
+		alpha := rz / dot(p, q)
+
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * q[i]
+		}
+
+		if norm(r) <= tol*bNorm {
+			return x, true
+		}
+
+		for i := range z {
+			z[i] = inv[i] * r[i]
+		}
+
+		rzNext := dot(r, z)
+		beta := rzNext / rz
+		rz = rzNext
+
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+
+	return x, false
+}
+
+// dot returns the dot product of the vectors.
+func dot(a, b []float64) float64 {
+	var sum float64
+
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+
+	return sum
+}
+
+// norm returns the Euclidean norm of the vector.
+func norm(a []float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
@@ -0,0 +1,55 @@
+package powerflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newCSRMatrix(t *testing.T) {
+	t.Parallel()
+
+	m := newCSRMatrix(3, []triplet{
+		{2, 2, 1}, {0, 1, 2}, {0, 0, 4}, {0, 1, 1}, {2, 0, 5},
+	})
+
+	require.Equal(t, []int{0, 2, 2, 4}, m.rowPtr)
+	require.Equal(t, []int{0, 1, 0, 2}, m.cols)
+	require.Equal(t, []float64{4, 3, 5, 1}, m.values)
+	require.Equal(t, []float64{4, 0, 1}, m.diagonal())
+
+	y := make([]float64, 3)
+	m.mulVec([]float64{1, 2, 3}, y)
+
+	require.Equal(t, []float64{10, 0, 8}, y)
+}
+
+func Test_solveCG(t *testing.T) {
+	t.Parallel()
+
+	m := newCSRMatrix(3, []triplet{
+		{0, 0, 4}, {0, 1, -1},
+		{1, 0, -1}, {1, 1, 4}, {1, 2, -1},
+		{2, 1, -1}, {2, 2, 4},
+	})
+
+	t.Run("success", func(t *testing.T) {
+		x, ok := solveCG(m, []float64{2, 4, 10}, 1e-12, 30)
+
+		require.True(t, ok)
+		require.InDeltaSlice(t, []float64{1, 2, 3}, x, 1e-9)
+	})
+
+	t.Run("zero-rhs", func(t *testing.T) {
+		x, ok := solveCG(m, []float64{0, 0, 0}, 1e-12, 30)
+
+		require.True(t, ok)
+		require.Equal(t, []float64{0, 0, 0}, x)
+	})
+
+	t.Run("not-converged", func(t *testing.T) {
+		_, ok := solveCG(m, []float64{2, 4, 10}, 1e-12, 1)
+
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// ProfileKey identifies a time series (profile) of a node, e.g. the hourly load of a consumer.
+type ProfileKey struct {
+	ModelID string
+	NodeID  string
+	Series  string // series name, e.g. "load" or "generation"
+}
+
+// ProfilePoint is a single value of a profile.
+type ProfilePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Profile is a time series of a node. The points are ordered by time.
+type Profile struct {
+	Key    ProfileKey
+	Points []ProfilePoint
+}
+
+// Aggregation defines how the values of a downsampling interval are combined.
+type Aggregation int
+
+// Aggregation enumeration.
+const (
+	AggregationMean Aggregation = iota
+	AggregationMin
+	AggregationMax
+	AggregationSum
+	AggregationFirst
+	AggregationLast
+)
+
+// AllAggregations is a list of all aggregations. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllAggregations = []Aggregation{
+	AggregationMean,
+	AggregationMin,
+	AggregationMax,
+	AggregationSum,
+	AggregationFirst,
+	AggregationLast,
+}
+
+// String returns the string representation of the aggregation.
+func (a Aggregation) String() string {
+	switch a {
+	case AggregationMean:
+		return "mean"
+	case AggregationMin:
+		return "min"
+	case AggregationMax:
+		return "max"
+	case AggregationSum:
+		return "sum"
+	case AggregationFirst:
+		return "first"
+	case AggregationLast:
+		return "last"
+	}
+
+	return "Aggregation(" + strconv.Itoa(int(a)) + ")"
+}
+
+// ProfileQuery defines a range read of a profile.
+type ProfileQuery struct {
+	From        time.Time     // start of the range, inclusive (optional)
+	To          time.Time     // end of the range, exclusive (optional)
+	Interval    time.Duration // downsampling interval (optional, zero returns the stored points)
+	Aggregation Aggregation   // how the values of an interval are combined
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregation_String(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range AllAggregations {
+		t.Run(d.String(), func(t *testing.T) {
+			require.NotEmpty(t, d.String())
+			require.False(t, strings.HasPrefix(d.String(), "Aggregation("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		d := Aggregation(100)
+
+		require.Equal(t, "Aggregation(100)", d.String())
+	})
+}
@@ -0,0 +1,45 @@
+package models
+
+import "math"
+
+// NumericValue converts a property value to a float.
+// Integer and floating point values are accepted. It returns false if the value is not
+// a number or is not finite.
+func NumericValue(value any) (float64, bool) {
+	var v float64
+
+	switch n := value.(type) {
+	case int:
+		v = float64(n)
+	case int8:
+		v = float64(n)
+	case int16:
+		v = float64(n)
+	case int32:
+		v = float64(n)
+	case int64:
+		v = float64(n)
+	case uint:
+		v = float64(n)
+	case uint8:
+		v = float64(n)
+	case uint16:
+		v = float64(n)
+	case uint32:
+		v = float64(n)
+	case uint64:
+		v = float64(n)
+	case float32:
+		v = float64(n)
+	case float64:
+		v = n
+	default:
+		return 0, false
+	}
+
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericValue(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []any{int(2), int8(2), int16(2), int32(2), int64(2),
+		uint(2), uint8(2), uint16(2), uint32(2), uint64(2), float32(2), float64(2)} {
+		v, ok := NumericValue(value)
+
+		require.True(t, ok)
+		require.InDelta(t, 2.0, v, 0)
+	}
+
+	for _, value := range []any{"2", true, nil, []int{2}, math.NaN(), math.Inf(1), float32(math.Inf(-1))} {
+		_, ok := NumericValue(value)
+
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
+
+// Dimension defines the physical dimension of a quantity.
+type Dimension int
+
+// Dimension enumeration.
+const (
+	DimensionVoltage Dimension = iota
+	DimensionCurrent
+	DimensionActivePower
+	DimensionReactivePower
+	DimensionApparentPower
+	DimensionImpedance
+	DimensionAdmittance
+	DimensionPerUnit
+)
+
+// AllDimensions is a list of all dimensions. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllDimensions = []Dimension{
+	DimensionVoltage,
+	DimensionCurrent,
+	DimensionActivePower,
+	DimensionReactivePower,
+	DimensionApparentPower,
+	DimensionImpedance,
+	DimensionAdmittance,
+	DimensionPerUnit,
+}
+
+// String returns the string representation of the dimension.
+func (d Dimension) String() string {
+	switch d {
+	case DimensionVoltage:
+		return "voltage"
+	case DimensionCurrent:
+		return "current"
+	case DimensionActivePower:
+		return "active-power"
+	case DimensionReactivePower:
+		return "reactive-power"
+	case DimensionApparentPower:
+		return "apparent-power"
+	case DimensionImpedance:
+		return "impedance"
+	case DimensionAdmittance:
+		return "admittance"
+	case DimensionPerUnit:
+		return "per-unit"
+	}
+
+	return "Dimension(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Unit defines the unit of a quantity.
+type Unit string
+
+// Supported units.
+const (
+	UnitVolt           Unit = "V"
+	UnitKilovolt       Unit = "kV"
+	UnitAmpere         Unit = "A"
+	UnitKiloampere     Unit = "kA"
+	UnitWatt           Unit = "W"
+	UnitKilowatt       Unit = "kW"
+	UnitMegawatt       Unit = "MW"
+	UnitVar            Unit = "var"
+	UnitKilovar        Unit = "kvar"
+	UnitMegavar        Unit = "Mvar"
+	UnitVoltAmpere     Unit = "VA"
+	UnitKilovoltAmpere Unit = "kVA"
+	UnitMegavoltAmpere Unit = "MVA"
+	UnitOhm            Unit = "ohm"
+	UnitSiemens        Unit = "S"
+	UnitPerUnit        Unit = "pu"
+)
+
+// unitInfo describes a unit by its dimension and its factor relative to the SI unit.
+type unitInfo struct {
+	dimension Dimension
+	factor    float64
+}
+
+//nolint:gochecknoglobals,mnd
+var units = map[Unit]unitInfo{
+	UnitVolt:           {DimensionVoltage, 1},
+	UnitKilovolt:       {DimensionVoltage, 1e3},
+	UnitAmpere:         {DimensionCurrent, 1},
+	UnitKiloampere:     {DimensionCurrent, 1e3},
+	UnitWatt:           {DimensionActivePower, 1},
+	UnitKilowatt:       {DimensionActivePower, 1e3},
+	UnitMegawatt:       {DimensionActivePower, 1e6},
+	UnitVar:            {DimensionReactivePower, 1},
+	UnitKilovar:        {DimensionReactivePower, 1e3},
+	UnitMegavar:        {DimensionReactivePower, 1e6},
+	UnitVoltAmpere:     {DimensionApparentPower, 1},
+	UnitKilovoltAmpere: {DimensionApparentPower, 1e3},
+	UnitMegavoltAmpere: {DimensionApparentPower, 1e6},
+	UnitOhm:            {DimensionImpedance, 1},
+	UnitSiemens:        {DimensionAdmittance, 1},
+	UnitPerUnit:        {DimensionPerUnit, 1},
+}
+
+// Dimension returns the dimension of the unit.
+// The second return value is false if the unit is not supported.
+func (u Unit) Dimension() (Dimension, bool) {
+	info, ok := units[u]
+
+	return info.dimension, ok
+}
+
+// Quantity is a numeric property value with a unit. It can be stored in a PropSection
+// alongside plain values.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// Validate checks that the value is finite and the unit is supported.
+func (q Quantity) Validate() error {
+	if math.IsNaN(q.Value) || math.IsInf(q.Value, 0) {
+		return errorz.NewValidationError("quantity value %v is not finite", q.Value)
+	}
+
+	if _, ok := units[q.Unit]; !ok {
+		return errorz.NewValidationError("unsupported unit %q", q.Unit)
+	}
+
+	return nil
+}
+
+// IsCompatible checks if the quantity can be used for a property of the given dimension.
+// Per-unit values are compatible with all dimensions.
+func (q Quantity) IsCompatible(dimension Dimension) bool {
+	d, ok := q.Unit.Dimension()
+
+	return ok && (d == dimension || d == DimensionPerUnit)
+}
+
+// Convert returns the quantity converted to the given unit of the same dimension.
+// Per-unit values can only be converted with a PerUnitBase.
+func (q Quantity) Convert(to Unit) (Quantity, error) {
+	from, ok := units[q.Unit]
+	if !ok {
+		return Quantity{}, errorz.NewValidationError("unsupported unit %q", q.Unit)
+	}
+
+	target, ok := units[to]
+	if !ok {
+		return Quantity{}, errorz.NewValidationError("unsupported unit %q", to)
+	}
+
+	if from.dimension != target.dimension {
+		return Quantity{}, errorz.NewValidationError("cannot convert %s to %s", q.Unit, to)
+	}
+
+	return Quantity{Value: q.Value * from.factor / target.factor, Unit: to}, nil
+}
+
+// String returns the string representation of the quantity, e.g. "20 kV".
+func (q Quantity) String() string {
+	return strconv.FormatFloat(q.Value, 'g', -1, 64) + " " + string(q.Unit)
+}
+
+// Quantity returns the quantity stored under the given key.
+// Plain numbers are returned as quantities without a unit, so the callers can decide how
+// to treat values stored before units were introduced. The second return value is false
+// if the key is missing or the value is neither a quantity nor a number.
+func (s PropSection) Quantity(key string) (Quantity, bool) {
+	switch v := s[key].(type) {
+	case Quantity:
+		return v, true
+	case nil:
+		return Quantity{}, false
+	default:
+		n, ok := NumericValue(v)
+
+		return Quantity{Value: n}, ok
+	}
+}
+
+// ValueIn returns the value stored under the given key converted to the given unit.
+// Plain numbers are taken to be in the given unit already. If the unit is empty, the value
+// is returned as stored, whatever its unit. The second return value is false if the key is
+// missing, the value is not numeric or not finite, or its unit cannot be converted to the
+// given unit.
+func (s PropSection) ValueIn(key string, unit Unit) (float64, bool) {
+	q, ok := s.Quantity(key)
+	if !ok || math.IsNaN(q.Value) || math.IsInf(q.Value, 0) {
+		return 0, false
+	}
+
+	if q.Unit == "" || unit == "" {
+		return q.Value, true
+	}
+
+	converted, err := q.Convert(unit)
+	if err != nil {
+		return 0, false
+	}
+
+	return converted.Value, true
+}
+
+// PerUnitBase defines the base values of the per-unit system at a node.
+type PerUnitBase struct {
+	MVA float64 // base apparent power in MVA, common to the whole system
+	KV  float64 // base line-to-line voltage in kV at the node
+}
+
+// Validate checks that the base values are positive.
+func (b PerUnitBase) Validate() error {
+	if !(b.MVA > 0) || !(b.KV > 0) {
+		return errorz.NewValidationError("per-unit base values must be positive")
+	}
+
+	return nil
+}
+
+// baseValue returns the base value of the given dimension in the SI unit of the dimension.
+func (b PerUnitBase) baseValue(dimension Dimension) float64 {
+	switch dimension {
+	case DimensionVoltage:
+		return b.KV * 1e3
+	case DimensionCurrent:
+		return b.MVA * 1e6 / (math.Sqrt(3) * b.KV * 1e3) //nolint:mnd
+	case DimensionActivePower, DimensionReactivePower, DimensionApparentPower:
+		return b.MVA * 1e6
+	case DimensionImpedance:
+		return b.KV * b.KV / b.MVA
+	case DimensionAdmittance:
+		return b.MVA / (b.KV * b.KV)
+	case DimensionPerUnit:
+		return 1
+	}
+
+	return 1
+}
+
+// ToPerUnit converts the quantity to a per-unit value.
+// Per-unit quantities are returned unchanged.
+func (b PerUnitBase) ToPerUnit(q Quantity) (float64, error) {
+	if err := b.Validate(); err != nil {
+		return 0, err
+	}
+
+	info, ok := units[q.Unit]
+	if !ok {
+		return 0, errorz.NewValidationError("unsupported unit %q", q.Unit)
+	}
+
+	return q.Value * info.factor / b.baseValue(info.dimension), nil
+}
+
+// FromPerUnit converts a per-unit value to a quantity in the given unit.
+func (b PerUnitBase) FromPerUnit(value float64, to Unit) (Quantity, error) {
+	if err := b.Validate(); err != nil {
+		return Quantity{}, err
+	}
+
+	info, ok := units[to]
+	if !ok {
+		return Quantity{}, errorz.NewValidationError("unsupported unit %q", to)
+	}
+
+	return Quantity{Value: value * b.baseValue(info.dimension) / info.factor, Unit: to}, nil
+}
+
+// NodePerUnitBase returns the per-unit base at the node. The base voltage is read from the
+// given property of the node; it can be a voltage quantity or a plain number in kV.
+func NodePerUnitBase(node Node, section, key string, baseMVA float64) (PerUnitBase, error) {
+	q, ok := node.Props[section].Quantity(key)
+	if !ok {
+		return PerUnitBase{}, errorz.NewValidationError("node %s has no base voltage %s.%s",
+			node.ID, section, key)
+	}
+
+	kv := q.Value
+
+	if q.Unit != "" {
+		converted, err := q.Convert(UnitKilovolt)
+		if err != nil {
+			return PerUnitBase{}, err
+		}
+
+		kv = converted.Value
+	}
+
+	base := PerUnitBase{MVA: baseMVA, KV: kv}
+
+	if err := base.Validate(); err != nil {
+		return PerUnitBase{}, err
+	}
+
+	return base, nil
+}
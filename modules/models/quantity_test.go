@@ -0,0 +1,252 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDimension_String(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range AllDimensions {
+		t.Run(d.String(), func(t *testing.T) {
+			require.NotEmpty(t, d.String())
+			require.False(t, strings.HasPrefix(d.String(), "Dimension("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		d := Dimension(100)
+
+		require.Equal(t, "Dimension(100)", d.String())
+	})
+}
+
+func TestUnit_Dimension(t *testing.T) {
+	t.Parallel()
+
+	d, ok := UnitKilovolt.Dimension()
+
+	require.True(t, ok)
+	require.Equal(t, DimensionVoltage, d)
+
+	_, ok = Unit("furlong").Dimension()
+
+	require.False(t, ok)
+}
+
+func TestQuantity_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Quantity{Value: 20, Unit: UnitKilovolt}.Validate())
+	require.IsType(t, errorz.ValidationError{}, Quantity{Value: 20, Unit: "kv"}.Validate())
+	require.IsType(t, errorz.ValidationError{}, Quantity{Value: 20}.Validate())
+	require.IsType(t, errorz.ValidationError{}, Quantity{Value: math.NaN(), Unit: UnitVolt}.Validate())
+}
+
+func TestQuantity_IsCompatible(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Quantity{Value: 1, Unit: UnitMegawatt}.IsCompatible(DimensionActivePower))
+	require.True(t, Quantity{Value: 1, Unit: UnitPerUnit}.IsCompatible(DimensionImpedance))
+	require.False(t, Quantity{Value: 1, Unit: UnitMegavoltAmpere}.IsCompatible(DimensionActivePower))
+	require.False(t, Quantity{Value: 1, Unit: "furlong"}.IsCompatible(DimensionVoltage))
+}
+
+func TestQuantity_Convert(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		from    Quantity
+		to      Unit
+		want    float64
+		wantErr bool
+	}{
+		"kv-to-v":      {from: Quantity{Value: 20, Unit: UnitKilovolt}, to: UnitVolt, want: 20000},
+		"kw-to-mw":     {from: Quantity{Value: 1500, Unit: UnitKilowatt}, to: UnitMegawatt, want: 1.5},
+		"mvar-to-kvar": {from: Quantity{Value: 2, Unit: UnitMegavar}, to: UnitKilovar, want: 2000},
+		"same-unit":    {from: Quantity{Value: 3, Unit: UnitOhm}, to: UnitOhm, want: 3},
+		"incompatible": {from: Quantity{Value: 1, Unit: UnitMegawatt}, to: UnitMegavoltAmpere, wantErr: true},
+		"per-unit":     {from: Quantity{Value: 1, Unit: UnitPerUnit}, to: UnitOhm, wantErr: true},
+		"unknown-from": {from: Quantity{Value: 1, Unit: "furlong"}, to: UnitVolt, wantErr: true},
+		"unknown-to":   {from: Quantity{Value: 1, Unit: UnitVolt}, to: "furlong", wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.from.Convert(test.to)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.to, got.Unit)
+				require.InDelta(t, test.want, got.Value, 1e-9)
+			}
+		})
+	}
+}
+
+func TestQuantity_String(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "20 kV", Quantity{Value: 20, Unit: UnitKilovolt}.String())
+	require.Equal(t, "0.25 pu", Quantity{Value: 0.25, Unit: UnitPerUnit}.String())
+}
+
+func TestPropSection_Quantity(t *testing.T) {
+	t.Parallel()
+
+	section := PropSection{
+		"voltage": Quantity{Value: 20, Unit: UnitKilovolt},
+		"legacy":  int32(110),
+		"name":    "bus 1",
+		"empty":   nil,
+	}
+
+	q, ok := section.Quantity("voltage")
+
+	require.True(t, ok)
+	require.Equal(t, Quantity{Value: 20, Unit: UnitKilovolt}, q)
+
+	q, ok = section.Quantity("legacy")
+
+	require.True(t, ok)
+	require.Equal(t, Quantity{Value: 110}, q)
+
+	for _, key := range []string{"name", "empty", "missing"} {
+		_, ok = section.Quantity(key)
+
+		require.False(t, ok, key)
+	}
+
+	_, ok = PropSection(nil).Quantity("voltage")
+
+	require.False(t, ok)
+}
+
+func TestPropSection_ValueIn(t *testing.T) {
+	t.Parallel()
+
+	section := PropSection{
+		"power":    Quantity{Value: 2500, Unit: UnitKilowatt},
+		"legacy":   int32(110),
+		"voltage":  Quantity{Value: 20, Unit: UnitKilovolt},
+		"infinite": Quantity{Value: math.Inf(1), Unit: UnitMegawatt},
+		"unknown":  Quantity{Value: 1, Unit: "GW"},
+		"name":     "bus 1",
+	}
+
+	v, ok := section.ValueIn("power", UnitMegawatt)
+
+	require.True(t, ok)
+	require.InDelta(t, 2.5, v, 1e-12)
+
+	v, ok = section.ValueIn("legacy", UnitMegawatt)
+
+	require.True(t, ok)
+	require.InDelta(t, 110, v, 1e-12)
+
+	v, ok = section.ValueIn("voltage", "")
+
+	require.True(t, ok)
+	require.InDelta(t, 20, v, 1e-12)
+
+	for _, key := range []string{"voltage", "infinite", "unknown", "name", "missing"} {
+		_, ok = section.ValueIn(key, UnitMegawatt)
+
+		require.False(t, ok, key)
+	}
+}
+
+func TestPerUnitBase(t *testing.T) {
+	t.Parallel()
+
+	// 100 MVA and 20 kV give a base impedance of 4 ohm and a base current of 2.8868 kA
+	base := PerUnitBase{MVA: 100, KV: 20}
+
+	tests := map[string]struct {
+		quantity Quantity
+		want     float64
+	}{
+		"voltage":        {quantity: Quantity{Value: 21, Unit: UnitKilovolt}, want: 1.05},
+		"voltage-volts":  {quantity: Quantity{Value: 19000, Unit: UnitVolt}, want: 0.95},
+		"active-power":   {quantity: Quantity{Value: 50, Unit: UnitMegawatt}, want: 0.5},
+		"reactive-power": {quantity: Quantity{Value: 2500, Unit: UnitKilovar}, want: 0.025},
+		"apparent-power": {quantity: Quantity{Value: 100, Unit: UnitMegavoltAmpere}, want: 1},
+		"impedance":      {quantity: Quantity{Value: 0.8, Unit: UnitOhm}, want: 0.2},
+		"admittance":     {quantity: Quantity{Value: 0.5, Unit: UnitSiemens}, want: 2},
+		"current":        {quantity: Quantity{Value: 100e6 / (math.Sqrt(3) * 20e3), Unit: UnitAmpere}, want: 1},
+		"per-unit":       {quantity: Quantity{Value: 0.3, Unit: UnitPerUnit}, want: 0.3},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pu, err := base.ToPerUnit(test.quantity)
+
+			require.NoError(t, err)
+			require.InDelta(t, test.want, pu, 1e-9)
+
+			back, err := base.FromPerUnit(pu, test.quantity.Unit)
+
+			require.NoError(t, err)
+			require.Equal(t, test.quantity.Unit, back.Unit)
+			require.InDelta(t, test.quantity.Value, back.Value, 1e-9)
+		})
+	}
+
+	t.Run("unknown-unit", func(t *testing.T) {
+		_, err := base.ToPerUnit(Quantity{Value: 1, Unit: "furlong"})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+
+		_, err = base.FromPerUnit(1, "furlong")
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("invalid-base", func(t *testing.T) {
+		_, err := PerUnitBase{MVA: 100}.ToPerUnit(Quantity{Value: 1, Unit: UnitVolt})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+
+		_, err = PerUnitBase{KV: 20}.FromPerUnit(1, UnitVolt)
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func TestNodePerUnitBase(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   any
+		wantKV  float64
+		wantErr bool
+	}{
+		"quantity":     {value: Quantity{Value: 400, Unit: UnitVolt}, wantKV: 0.4},
+		"plain-number": {value: 20, wantKV: 20},
+		"missing":      {value: nil, wantErr: true},
+		"wrong-unit":   {value: Quantity{Value: 1, Unit: UnitPerUnit}, wantErr: true},
+		"zero":         {value: 0.0, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			node := Node{ID: "1", Props: PropBag{"rating": {"baseVoltage": test.value}}}
+
+			base, err := NodePerUnitBase(node, "rating", "baseVoltage", 100)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, PerUnitBase{MVA: 100, KV: test.wantKV}, base)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+// RollupSpec defines a numeric rollup over the nodes reachable from a start node.
+type RollupSpec struct {
+	Start         string    // public ID of the start node
+	Direction     Direction // direction in which relations are followed from the start node
+	RelationKinds []string  // relation kinds to follow (optional, all kinds if empty)
+	IncludeStart  bool      // include the value of the start node itself
+	Section       string    // property section holding the value
+	Key           string    // property key of the value within the section
+	Unit          Unit      // unit the values are converted to (optional, raw values if empty)
+	GroupByKind   bool      // additionally aggregate the values per node kind
+}
+
+// RollupStats holds the aggregates of a numeric property over a set of nodes.
+//
+// Only numeric values are aggregated. Nodes without the property and nodes with a
+// non-numeric value (including NaN and infinities) are counted separately. If there
+// are no numeric values, all aggregates are zero.
+type RollupStats struct {
+	Count      int // number of aggregated numeric values
+	Sum        float64
+	Min        float64
+	Max        float64
+	Avg        float64
+	Missing    int // number of nodes without the property
+	NonNumeric int // number of nodes with a non-numeric value
+}
+
+// Rollup represents the result of a numeric rollup.
+type Rollup struct {
+	ModelID string
+	Nodes   int                    // number of nodes covered by the rollup
+	Total   RollupStats            // aggregates over all covered nodes
+	ByKind  map[string]RollupStats // node kind -> aggregates (only if grouped by kind)
+}
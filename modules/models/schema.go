@@ -0,0 +1,49 @@
+package models
+
+import "github.com/energimind/powermesh-core/errorz"
+
+// KindSchema describes a node or relation kind known to the system.
+type KindSchema struct {
+	Kind       string
+	Quantities []QuantitySchema // expected dimensions of the quantity properties (optional)
+}
+
+// QuantitySchema describes the dimension expected for a quantity property of a kind.
+type QuantitySchema struct {
+	Section   string
+	Key       string
+	Dimension Dimension
+}
+
+// KindSchemas holds the configured node and relation kind schemas.
+type KindSchemas struct {
+	Nodes     []KindSchema
+	Relations []KindSchema
+}
+
+// IsEmpty checks if no schemas are configured.
+func (s KindSchemas) IsEmpty() bool {
+	return len(s.Nodes) == 0 && len(s.Relations) == 0
+}
+
+// CheckQuantities checks that the quantities in the property bag have units compatible with
+// the dimensions declared by the schema of the kind. Plain numbers are accepted for
+// backward compatibility, and unknown kinds and undeclared properties are not checked.
+// It returns a validation error for the first incompatible quantity.
+func CheckQuantities(schemas []KindSchema, kind string, bag PropBag) error {
+	for _, schema := range schemas {
+		if schema.Kind != kind {
+			continue
+		}
+
+		for _, qs := range schema.Quantities {
+			q, ok := bag[qs.Section][qs.Key].(Quantity)
+			if ok && !q.IsCompatible(qs.Dimension) {
+				return errorz.NewValidationError("property %s.%s of kind %s requires a %s unit, got %s",
+					qs.Section, qs.Key, kind, qs.Dimension, q.Unit)
+			}
+		}
+	}
+
+	return nil
+}
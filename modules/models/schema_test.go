@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindSchemas_IsEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, KindSchemas{}.IsEmpty())
+	require.False(t, KindSchemas{Nodes: []KindSchema{{Kind: "bus"}}}.IsEmpty())
+	require.False(t, KindSchemas{Relations: []KindSchema{{Kind: "line"}}}.IsEmpty())
+}
+
+func TestCheckQuantities(t *testing.T) {
+	t.Parallel()
+
+	schemas := []KindSchema{
+		{Kind: "bus", Quantities: []QuantitySchema{
+			{Section: "rating", Key: "voltage", Dimension: DimensionVoltage},
+		}},
+		{Kind: "line"},
+	}
+
+	tests := map[string]struct {
+		kind    string
+		value   any
+		wantErr bool
+	}{
+		"compatible":   {kind: "bus", value: Quantity{Value: 20, Unit: UnitKilovolt}},
+		"per-unit":     {kind: "bus", value: Quantity{Value: 1, Unit: UnitPerUnit}},
+		"plain-number": {kind: "bus", value: 20},
+		"missing":      {kind: "bus", value: nil},
+		"other-kind":   {kind: "line", value: Quantity{Value: 20, Unit: UnitMegawatt}},
+		"unknown-kind": {kind: "switch", value: Quantity{Value: 20, Unit: UnitMegawatt}},
+		"incompatible": {kind: "bus", value: Quantity{Value: 20, Unit: UnitMegawatt}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bag := PropBag{"rating": {"voltage": test.value}}
+
+			err := CheckQuantities(schemas, test.kind, bag)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
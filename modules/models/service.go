@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"io"
 
 	"github.com/energimind/powermesh-core/access"
 )
@@ -11,8 +12,19 @@ type ModelService interface {
 	CreateModel(ctx context.Context, actor access.Actor, data ModelData) (Model, error)
 	UpdateModel(ctx context.Context, actor access.Actor, id string, data ModelData) (Model, error)
 	DeleteModel(ctx context.Context, actor access.Actor, id string) error
+	PublishModel(ctx context.Context, actor access.Actor, id string) (Model, error)
+	ArchiveModel(ctx context.Context, actor access.Actor, id string) (Model, error)
 	GetModel(ctx context.Context, id string) (Model, error)
 	GetModelsByIDs(ctx context.Context, ids []string) ([]Model, error)
+	ListModels(ctx context.Context, query ModelQuery) (ModelPage, error)
+}
+
+// ModelDeleter defines the service that deletes models together with the resources
+// that belong to them: the mesh, the role bindings and the resources of registered
+// deletion participants (e.g. attachments).
+type ModelDeleter interface {
+	DeleteModelCascade(ctx context.Context, actor access.Actor, id string) error
+	ResumeDeletions(ctx context.Context) (int, error)
 }
 
 // ModelData defines the model data. It is used to create or update a model.
@@ -20,6 +32,74 @@ type ModelData struct {
 	Code        string
 	Name        string
 	Description string
+	Labels      map[string]string // key/value labels (optional)
+}
+
+// ModelSortField defines the field used to sort models.
+type ModelSortField string
+
+// Model sort fields.
+const (
+	ModelSortByCode ModelSortField = "code"
+	ModelSortByName ModelSortField = "name"
+)
+
+// ModelQuery defines the model query. It is used to list models.
+type ModelQuery struct {
+	Search       string         // free-text search on code, name and description (optional)
+	Labels       LabelSelector  // label selector (optional)
+	SortBy       ModelSortField // sort field (optional, defaults to code)
+	Descending   bool           // sort in descending order
+	Cursor       string         // cursor of the next page returned by the previous query (optional)
+	Limit        int            // maximum number of models in the page (optional)
+	AccessibleTo string         // restrict the models to those accessible to this user ID (optional)
+}
+
+// ModelPage defines a page of models returned by a model query.
+type ModelPage struct {
+	Models     []Model
+	NextCursor string // empty if there are no more models
+}
+
+// TemplateService defines a template service.
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, actor access.Actor, data TemplateData) (Template, error)
+	UpdateTemplate(ctx context.Context, actor access.Actor, id string, data TemplateData) (Template, error)
+	DeleteTemplate(ctx context.Context, actor access.Actor, id string) error
+	GetTemplate(ctx context.Context, id string) (Template, error)
+	GetTemplatesByIDs(ctx context.Context, ids []string) ([]Template, error)
+	InstantiateTemplate(
+		ctx context.Context,
+		actor access.Actor,
+		modelID, templateID string,
+		params TemplateParams,
+	) (Mesh, error)
+}
+
+// TemplateData defines the template data. It is used to create or update a template.
+type TemplateData struct {
+	Code        string
+	Name        string
+	Description string
+	Params      []TemplateParam
+	Nodes       []TemplateNode
+	Relations   []TemplateRelation
+	Ports       []TemplatePort
+}
+
+// TemplateParams defines the parameters of a template instantiation.
+type TemplateParams struct {
+	Values      map[string]string // parameter name -> value
+	Connections map[string]string // port name -> public ID of the existing node to connect to
+}
+
+// ProfileService defines a service of node profiles (time series).
+type ProfileService interface {
+	WriteProfile(ctx context.Context, actor access.Actor, key ProfileKey, points []ProfilePoint) error
+	ImportProfileCSV(ctx context.Context, actor access.Actor, key ProfileKey, r io.Reader) (int, error)
+	DeleteProfile(ctx context.Context, actor access.Actor, key ProfileKey) error
+	ReadProfile(ctx context.Context, key ProfileKey, query ProfileQuery) (Profile, error)
+	ListProfiles(ctx context.Context, modelID, nodeID string) ([]ProfileKey, error)
 }
 
 // MeshService defines a mesh service.
@@ -36,20 +116,28 @@ type meshOperations interface {
 	MergeMesh(ctx context.Context, actor access.Actor, modelID string, data MeshData) error
 	DeleteMesh(ctx context.Context, actor access.Actor, modelID string) error
 	GetMesh(ctx context.Context, modelID string) (Mesh, error)
+	LintMesh(ctx context.Context, modelID string) (LintReport, error)
+	ExtractSubmesh(ctx context.Context, modelID string, spec SliceSpec) (Mesh, error)
+	ImportSubmesh(ctx context.Context, actor access.Actor, modelID string, submesh Mesh) (Mesh, error)
+	InsertContents(ctx context.Context, actor access.Actor, modelID string, contents Mesh) (Mesh, error)
+	RollupMesh(ctx context.Context, modelID string, spec RollupSpec) (Rollup, error)
 }
 
 // nodeOperations defines the operations on nodes.
 type nodeOperations interface {
 	CreateNode(ctx context.Context, actor access.Actor, modelID string, data NodeData) (Node, error)
+	RestoreNode(ctx context.Context, actor access.Actor, modelID string, node Node) (Node, error)
 	UpdateNode(ctx context.Context, actor access.Actor, modelID, nodeID string, data NodeData) (Node, error)
 	DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error
 	GetNode(ctx context.Context, modelID, nodeID string) (Node, error)
+	GetNodeByCode(ctx context.Context, modelID, code string) (Node, error)
 	GetNodes(ctx context.Context, modelID string) ([]Node, error)
 }
 
 // relationOperations defines the operations on relations.
 type relationOperations interface {
 	CreateRelation(ctx context.Context, actor access.Actor, modelID string, data RelationData) (Relation, error)
+	RestoreRelation(ctx context.Context, actor access.Actor, modelID string, relation Relation) (Relation, error)
 	UpdateRelation(ctx context.Context, actor access.Actor, modelID, relationID string, data RelationData) (Relation, error)
 	DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error
 	GetRelation(ctx context.Context, modelID, relationID string) (Relation, error)
@@ -64,7 +152,7 @@ type MeshData struct {
 // NodeData defines the node data. It is used to create or update a node.
 type NodeData struct {
 	Kind  string  // node kind/type
-	Code  string  // node code (optional)
+	Code  string  // node code (optional, unique within the mesh)
 	Name  string  // node name (optional)
 	Props PropBag // custom node properties
 }
@@ -1,6 +1,34 @@
 package service
 
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
 // idGenerator defines the external ID generator.
 type idGenerator interface {
 	GenerateID() string
 }
+
+// accessResolver defines the external resolver of the resources accessible to a user.
+// It is implemented by the permissions service.
+type accessResolver interface {
+	GetAccessibleResources(ctx context.Context, query permissions.AccessibleResourcesQuery) ([]string, error)
+}
+
+// modelProvider defines the external provider of models.
+// It is implemented by the model service.
+type modelProvider interface {
+	GetModel(ctx context.Context, id string) (models.Model, error)
+}
+
+// lockChecker defines the external checker of edit locks.
+// It is implemented by the lock service.
+type lockChecker interface {
+	CheckNodeLock(ctx context.Context, actor access.Actor, modelID, nodeID string) error
+	CheckRelationLock(ctx context.Context, actor access.Actor, modelID, relationID string) error
+	CheckMeshLock(ctx context.Context, actor access.Actor, modelID string, nodes, relations bool) error
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// LintMesh checks the given in-memory mesh for structural problems and returns a report
+// of the findings. It does not access the store, so it can be used on meshes that have
+// not been stored yet (e.g. imported files).
+//
+// The unknown kind rules are only checked if the corresponding schemas are configured.
+// Findings are sorted by rule and affected element IDs, so the report is deterministic.
+func LintMesh(mesh models.Mesh, schemas models.KindSchemas) models.LintReport {
+	var findings []models.LintFinding
+
+	findings = append(findings, lintRelations(mesh)...)
+	findings = append(findings, lintOrphanNodes(mesh)...)
+	findings = append(findings, lintDuplicateNodeCodes(mesh)...)
+	findings = append(findings, lintEmptyPropSections(mesh)...)
+	findings = append(findings, lintUnknownKinds(mesh, schemas)...)
+
+	sortLintFindings(findings)
+
+	return models.LintReport{
+		ModelID:  mesh.ModelID,
+		Findings: findings,
+	}
+}
+
+// lintRelations checks the relations for dangling ends, self-loops and parallel duplicates.
+func lintRelations(mesh models.Mesh) []models.LintFinding {
+	var findings []models.LintFinding
+
+	parallels := make(map[[3]string][]string)
+
+	for _, id := range models.SortedKeys(mesh.Relations) {
+		r := mesh.Relations[id]
+
+		if missing := missingEnds(mesh, r); len(missing) > 0 {
+			findings = append(findings, models.LintFinding{
+				Rule:        models.LintDanglingRelation,
+				Severity:    models.LintSeverityError,
+				Message:     fmt.Sprintf("relation %s references missing nodes", r.ID),
+				NodeIDs:     missing,
+				RelationIDs: []string{r.ID},
+			})
+		}
+
+		if r.From == r.To {
+			findings = append(findings, models.LintFinding{
+				Rule:        models.LintSelfLoop,
+				Severity:    models.LintSeverityWarning,
+				Message:     fmt.Sprintf("relation %s starts and ends at node %s", r.ID, r.From),
+				NodeIDs:     []string{r.From},
+				RelationIDs: []string{r.ID},
+			})
+		}
+
+		key := [3]string{r.Kind, r.From, r.To}
+
+		parallels[key] = append(parallels[key], r.ID)
+	}
+
+	for key, ids := range parallels {
+		if len(ids) < 2 { //nolint:mnd
+			continue
+		}
+
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintParallelRelation,
+			Severity: models.LintSeverityWarning,
+			Message: fmt.Sprintf("%d relations of kind %s connect node %s to node %s",
+				len(ids), key[0], key[1], key[2]),
+			NodeIDs:     uniqueStrings(key[1], key[2]),
+			RelationIDs: ids,
+		})
+	}
+
+	return findings
+}
+
+// lintOrphanNodes checks for nodes that are not referenced by any relation.
+func lintOrphanNodes(mesh models.Mesh) []models.LintFinding {
+	referenced := make(map[string]bool, len(mesh.Nodes))
+
+	for _, r := range mesh.Relations {
+		referenced[r.From] = true
+		referenced[r.To] = true
+	}
+
+	var findings []models.LintFinding
+
+	for _, id := range models.SortedKeys(mesh.Nodes) {
+		if referenced[id] {
+			continue
+		}
+
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintOrphanNode,
+			Severity: models.LintSeverityWarning,
+			Message:  fmt.Sprintf("node %s has no relations", id),
+			NodeIDs:  []string{id},
+		})
+	}
+
+	return findings
+}
+
+// lintDuplicateNodeCodes checks for nodes sharing the same non-empty code.
+func lintDuplicateNodeCodes(mesh models.Mesh) []models.LintFinding {
+	byCode := make(map[string][]string)
+
+	for _, id := range models.SortedKeys(mesh.Nodes) {
+		if code := mesh.Nodes[id].Code; code != "" {
+			byCode[code] = append(byCode[code], id)
+		}
+	}
+
+	var findings []models.LintFinding
+
+	for code, ids := range byCode {
+		if len(ids) < 2 { //nolint:mnd
+			continue
+		}
+
+		findings = append(findings, models.LintFinding{
+			Rule:     models.LintDuplicateNodeCode,
+			Severity: models.LintSeverityError,
+			Message:  fmt.Sprintf("%d nodes share the code %s", len(ids), code),
+			NodeIDs:  ids,
+		})
+	}
+
+	return findings
+}
+
+// lintEmptyPropSections checks for property sections without properties.
+func lintEmptyPropSections(mesh models.Mesh) []models.LintFinding {
+	var findings []models.LintFinding
+
+	for _, id := range models.SortedKeys(mesh.Nodes) {
+		for _, section := range emptySections(mesh.Nodes[id].Props) {
+			findings = append(findings, models.LintFinding{
+				Rule:     models.LintEmptyPropSection,
+				Severity: models.LintSeverityInfo,
+				Message:  fmt.Sprintf("node %s has an empty property section %s", id, section),
+				NodeIDs:  []string{id},
+			})
+		}
+	}
+
+	for _, id := range models.SortedKeys(mesh.Relations) {
+		for _, section := range emptySections(mesh.Relations[id].Props) {
+			findings = append(findings, models.LintFinding{
+				Rule:        models.LintEmptyPropSection,
+				Severity:    models.LintSeverityInfo,
+				Message:     fmt.Sprintf("relation %s has an empty property section %s", id, section),
+				RelationIDs: []string{id},
+			})
+		}
+	}
+
+	return findings
+}
+
+// lintUnknownKinds checks for node and relation kinds that are not present in the schemas.
+func lintUnknownKinds(mesh models.Mesh, schemas models.KindSchemas) []models.LintFinding {
+	var findings []models.LintFinding
+
+	if len(schemas.Nodes) > 0 {
+		known := kindSet(schemas.Nodes)
+
+		for _, id := range models.SortedKeys(mesh.Nodes) {
+			if kind := mesh.Nodes[id].Kind; !known[kind] {
+				findings = append(findings, models.LintFinding{
+					Rule:     models.LintUnknownNodeKind,
+					Severity: models.LintSeverityWarning,
+					Message:  fmt.Sprintf("node %s has unknown kind %s", id, kind),
+					NodeIDs:  []string{id},
+				})
+			}
+		}
+	}
+
+	if len(schemas.Relations) > 0 {
+		known := kindSet(schemas.Relations)
+
+		for _, id := range models.SortedKeys(mesh.Relations) {
+			if kind := mesh.Relations[id].Kind; !known[kind] {
+				findings = append(findings, models.LintFinding{
+					Rule:        models.LintUnknownRelKind,
+					Severity:    models.LintSeverityWarning,
+					Message:     fmt.Sprintf("relation %s has unknown kind %s", id, kind),
+					RelationIDs: []string{id},
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// missingEnds returns the IDs of the relation ends that are not present in the mesh.
+func missingEnds(mesh models.Mesh, r models.Relation) []string {
+	var missing []string
+
+	for _, id := range uniqueStrings(r.From, r.To) {
+		if _, ok := mesh.Nodes[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
+}
+
+// emptySections returns the sorted names of the empty sections in the property bag.
+func emptySections(bag models.PropBag) []string {
+	var sections []string
+
+	for name, section := range bag {
+		if len(section) == 0 {
+			sections = append(sections, name)
+		}
+	}
+
+	slices.Sort(sections)
+
+	return sections
+}
+
+// kindSet converts the schemas to a set of kinds.
+func kindSet(schemas []models.KindSchema) map[string]bool {
+	set := make(map[string]bool, len(schemas))
+
+	for _, s := range schemas {
+		set[s.Kind] = true
+	}
+
+	return set
+}
+
+// sortLintFindings sorts the findings by rule and affected element IDs.
+func sortLintFindings(findings []models.LintFinding) {
+	for i := range findings {
+		slices.Sort(findings[i].NodeIDs)
+		slices.Sort(findings[i].RelationIDs)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+
+		if c := slices.Compare(a.NodeIDs, b.NodeIDs); c != 0 {
+			return c < 0
+		}
+
+		return slices.Compare(a.RelationIDs, b.RelationIDs) < 0
+	})
+}
+
+// uniqueStrings returns the given values without consecutive duplicates.
+func uniqueStrings(values ...string) []string {
+	return slices.Compact(values)
+}
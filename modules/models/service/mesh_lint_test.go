@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintMesh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean", func(t *testing.T) {
+		mesh := models.Mesh{
+			ModelID: validModelID,
+			Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: "A"},
+				"n2": {ID: "n2", Kind: "bus", Code: "B"},
+			},
+			Relations: map[string]models.Relation{
+				"r1": {ID: "r1", Kind: "line", From: "n1", To: "n2"},
+			},
+		}
+
+		report := LintMesh(mesh, models.KindSchemas{
+			Nodes:     []models.KindSchema{{Kind: "bus"}},
+			Relations: []models.KindSchema{{Kind: "line"}},
+		})
+
+		require.Equal(t, validModelID, report.ModelID)
+		require.Empty(t, report.Findings)
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		mesh := models.Mesh{
+			ModelID: validModelID,
+			Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: "A"},
+				"n2": {ID: "n2", Kind: "bus", Code: "A"},
+				"n3": {ID: "n3", Kind: "pump", Props: models.PropBag{"empty": {}}},
+			},
+			Relations: map[string]models.Relation{
+				"r1": {ID: "r1", Kind: "line", From: "n1", To: "n2"},
+				"r2": {ID: "r2", Kind: "line", From: "n1", To: "n2"},
+				"r3": {ID: "r3", Kind: "line", From: "n1", To: "n9"},
+				"r4": {ID: "r4", Kind: "pipe", From: "n2", To: "n2"},
+			},
+		}
+
+		report := LintMesh(mesh, models.KindSchemas{
+			Nodes:     []models.KindSchema{{Kind: "bus"}},
+			Relations: []models.KindSchema{{Kind: "line"}},
+		})
+
+		require.Equal(t, []models.LintFinding{
+			{
+				Rule:        models.LintDanglingRelation,
+				Severity:    models.LintSeverityError,
+				Message:     "relation r3 references missing nodes",
+				NodeIDs:     []string{"n9"},
+				RelationIDs: []string{"r3"},
+			},
+			{
+				Rule:     models.LintDuplicateNodeCode,
+				Severity: models.LintSeverityError,
+				Message:  "2 nodes share the code A",
+				NodeIDs:  []string{"n1", "n2"},
+			},
+			{
+				Rule:     models.LintEmptyPropSection,
+				Severity: models.LintSeverityInfo,
+				Message:  "node n3 has an empty property section empty",
+				NodeIDs:  []string{"n3"},
+			},
+			{
+				Rule:     models.LintOrphanNode,
+				Severity: models.LintSeverityWarning,
+				Message:  "node n3 has no relations",
+				NodeIDs:  []string{"n3"},
+			},
+			{
+				Rule:        models.LintParallelRelation,
+				Severity:    models.LintSeverityWarning,
+				Message:     "2 relations of kind line connect node n1 to node n2",
+				NodeIDs:     []string{"n1", "n2"},
+				RelationIDs: []string{"r1", "r2"},
+			},
+			{
+				Rule:        models.LintSelfLoop,
+				Severity:    models.LintSeverityWarning,
+				Message:     "relation r4 starts and ends at node n2",
+				NodeIDs:     []string{"n2"},
+				RelationIDs: []string{"r4"},
+			},
+			{
+				Rule:     models.LintUnknownNodeKind,
+				Severity: models.LintSeverityWarning,
+				Message:  "node n3 has unknown kind pump",
+				NodeIDs:  []string{"n3"},
+			},
+			{
+				Rule:        models.LintUnknownRelKind,
+				Severity:    models.LintSeverityWarning,
+				Message:     "relation r4 has unknown kind pipe",
+				RelationIDs: []string{"r4"},
+			},
+		}, report.Findings)
+	})
+
+	t.Run("no-schemas", func(t *testing.T) {
+		mesh := models.Mesh{
+			Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "any"},
+				"n2": {ID: "n2", Kind: "any"},
+			},
+			Relations: map[string]models.Relation{
+				"r1": {ID: "r1", Kind: "any", From: "n1", To: "n2", Props: models.PropBag{"empty": {}}},
+			},
+		}
+
+		report := LintMesh(mesh, models.KindSchemas{})
+
+		require.Len(t, report.Findings, 1)
+		require.Equal(t, models.LintEmptyPropSection, report.Findings[0].Rule)
+		require.Equal(t, []string{"r1"}, report.Findings[0].RelationIDs)
+	})
+}
@@ -0,0 +1,118 @@
+package service
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+// RollupMesh aggregates a numeric node property over the nodes of the given in-memory mesh
+// reachable from the start node of the spec. It does not access the store and does not
+// validate the spec; an unknown start node covers no nodes.
+//
+// Quantities are converted to the unit of the spec and plain numbers are taken to be in that
+// unit already. Without a unit, the values are aggregated as stored. Missing properties and
+// non-numeric values, including quantities that cannot be converted to the unit, are counted
+// instead, so the caller can decide whether the result is trustworthy.
+func RollupMesh(mesh models.Mesh, spec models.RollupSpec) models.Rollup {
+	neighbors := adjacency(mesh, spec.Direction, spec.RelationKinds)
+	covered := neighborhood(mesh, neighbors, []string{spec.Start}, len(mesh.Nodes))
+
+	if !spec.IncludeStart {
+		delete(covered, spec.Start)
+	}
+
+	var (
+		total  rollupAccumulator
+		byKind map[string]*rollupAccumulator
+	)
+
+	if spec.GroupByKind {
+		byKind = make(map[string]*rollupAccumulator)
+	}
+
+	for id := range covered {
+		node := mesh.Nodes[id]
+		section := node.Props[spec.Section]
+
+		total.add(section, spec.Key, spec.Unit)
+
+		if byKind != nil {
+			acc, ok := byKind[node.Kind]
+			if !ok {
+				acc = &rollupAccumulator{}
+				byKind[node.Kind] = acc
+			}
+
+			acc.add(section, spec.Key, spec.Unit)
+		}
+	}
+
+	rollup := models.Rollup{
+		ModelID: mesh.ModelID,
+		Nodes:   len(covered),
+		Total:   total.stats(),
+	}
+
+	if byKind != nil {
+		rollup.ByKind = make(map[string]models.RollupStats, len(byKind))
+
+		for kind, acc := range byKind {
+			rollup.ByKind[kind] = acc.stats()
+		}
+	}
+
+	return rollup
+}
+
+// rollupAccumulator accumulates the values of a rollup.
+type rollupAccumulator struct {
+	count      int
+	sum        float64
+	minValue   float64
+	maxValue   float64
+	missing    int
+	nonNumeric int
+}
+
+// add adds the property value stored under the key, converted to the unit, to the
+// accumulator. Missing values are properties that are not present or hold nil.
+func (a *rollupAccumulator) add(section models.PropSection, key string, unit models.Unit) {
+	if section[key] == nil {
+		a.missing++
+
+		return
+	}
+
+	v, ok := section.ValueIn(key, unit)
+	if !ok {
+		a.nonNumeric++
+
+		return
+	}
+
+	if a.count == 0 || v < a.minValue {
+		a.minValue = v
+	}
+
+	if a.count == 0 || v > a.maxValue {
+		a.maxValue = v
+	}
+
+	a.count++
+	a.sum += v
+}
+
+// stats returns the accumulated aggregates.
+func (a *rollupAccumulator) stats() models.RollupStats {
+	stats := models.RollupStats{
+		Count:      a.count,
+		Sum:        a.sum,
+		Min:        a.minValue,
+		Max:        a.maxValue,
+		Missing:    a.missing,
+		NonNumeric: a.nonNumeric,
+	}
+
+	if a.count > 0 {
+		stats.Avg = a.sum / float64(a.count)
+	}
+
+	return stats
+}
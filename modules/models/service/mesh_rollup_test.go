@@ -0,0 +1,171 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupMesh(t *testing.T) {
+	t.Parallel()
+
+	load := models.RollupSpec{Section: "load", Key: "kw"}
+
+	tests := map[string]struct {
+		spec      func(spec models.RollupSpec) models.RollupSpec
+		wantNodes int
+		wantTotal models.RollupStats
+		wantKinds map[string]models.RollupStats
+	}{
+		"contents": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "s1"
+				spec.Direction = models.DirectionOutgoing
+				spec.RelationKinds = []string{"contains"}
+
+				return spec
+			},
+			wantNodes: 2,
+			wantTotal: models.RollupStats{Count: 2, Sum: 12.5, Min: 2.5, Max: 10, Avg: 6.25},
+		},
+		"downstream": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "s1"
+				spec.Direction = models.DirectionOutgoing
+
+				return spec
+			},
+			wantNodes: 3,
+			wantTotal: models.RollupStats{Count: 2, Sum: 12.5, Min: 2.5, Max: 10, Avg: 6.25, NonNumeric: 1},
+		},
+		"downstream-including-start": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "s1"
+				spec.Direction = models.DirectionOutgoing
+				spec.IncludeStart = true
+
+				return spec
+			},
+			wantNodes: 4,
+			wantTotal: models.RollupStats{Count: 2, Sum: 12.5, Min: 2.5, Max: 10, Avg: 6.25, Missing: 1, NonNumeric: 1},
+		},
+		"grouped": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "b1"
+				spec.IncludeStart = true
+				spec.GroupByKind = true
+
+				return spec
+			},
+			wantNodes: 5,
+			wantTotal: models.RollupStats{
+				Count: 3, Sum: 8.5, Min: -4, Max: 10, Avg: 8.5 / 3, Missing: 1, NonNumeric: 1,
+			},
+			wantKinds: map[string]models.RollupStats{
+				"bus":        {Count: 2, Sum: 12.5, Min: 2.5, Max: 10, Avg: 6.25, NonNumeric: 1},
+				"generator":  {Count: 1, Sum: -4, Min: -4, Max: -4, Avg: -4},
+				"substation": {Missing: 1},
+			},
+		},
+		"unknown-property": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "b1"
+				spec.Key = "kvar"
+
+				return spec
+			},
+			wantNodes: 4,
+			wantTotal: models.RollupStats{Missing: 4},
+		},
+		"unknown-start": {
+			spec: func(spec models.RollupSpec) models.RollupSpec {
+				spec.Start = "missing"
+				spec.GroupByKind = true
+
+				return spec
+			},
+			wantKinds: map[string]models.RollupStats{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rollup := RollupMesh(slicedMesh, test.spec(load))
+
+			require.Equal(t, slicedMesh.ModelID, rollup.ModelID)
+			require.Equal(t, test.wantNodes, rollup.Nodes)
+			require.InDelta(t, test.wantTotal.Avg, rollup.Total.Avg, 1e-9)
+
+			rollup.Total.Avg = test.wantTotal.Avg
+
+			require.Equal(t, test.wantTotal, rollup.Total)
+			require.Equal(t, test.wantKinds, rollup.ByKind)
+		})
+	}
+}
+
+func TestRollupMesh_quantities(t *testing.T) {
+	t.Parallel()
+
+	load := func(id string, value any) models.Node {
+		return models.Node{ID: id, Kind: "bus", Props: models.PropBag{"load": {"p": value}}}
+	}
+
+	mesh := models.Mesh{
+		ModelID: validModelID,
+		Nodes: map[string]models.Node{
+			"s":  {ID: "s", Kind: "substation"},
+			"n1": load("n1", models.Quantity{Value: 2500, Unit: models.UnitKilowatt}),
+			"n2": load("n2", models.Quantity{Value: 1.5, Unit: models.UnitMegawatt}),
+			"n3": load("n3", 3),
+			"n4": load("n4", models.Quantity{Value: 20, Unit: models.UnitKilovolt}),
+		},
+		Relations: map[string]models.Relation{
+			"r1": {ID: "r1", Kind: "feeds", From: "s", To: "n1"},
+			"r2": {ID: "r2", Kind: "feeds", From: "s", To: "n2"},
+			"r3": {ID: "r3", Kind: "feeds", From: "s", To: "n3"},
+			"r4": {ID: "r4", Kind: "feeds", From: "s", To: "n4"},
+		},
+	}
+
+	tests := map[string]struct {
+		unit      models.Unit
+		wantTotal models.RollupStats
+	}{
+		"raw": {
+			wantTotal: models.RollupStats{Count: 4, Sum: 2524.5, Min: 1.5, Max: 2500},
+		},
+		"megawatt": {
+			unit:      models.UnitMegawatt,
+			wantTotal: models.RollupStats{Count: 3, Sum: 7, Min: 1.5, Max: 3, NonNumeric: 1},
+		},
+		"kilowatt": {
+			unit:      models.UnitKilowatt,
+			wantTotal: models.RollupStats{Count: 3, Sum: 4003, Min: 3, Max: 2500, NonNumeric: 1},
+		},
+		"kilovolt": {
+			unit:      models.UnitKilovolt,
+			wantTotal: models.RollupStats{Count: 2, Sum: 23, Min: 3, Max: 20, NonNumeric: 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rollup := RollupMesh(mesh, models.RollupSpec{
+				Start:     "s",
+				Direction: models.DirectionOutgoing,
+				Section:   "load",
+				Key:       "p",
+				Unit:      test.unit,
+			})
+
+			require.Equal(t, 4, rollup.Nodes)
+			require.InDelta(t, test.wantTotal.Sum/float64(test.wantTotal.Count), rollup.Total.Avg, 1e-9)
+
+			rollup.Total.Avg = 0
+
+			require.Equal(t, test.wantTotal, rollup.Total)
+		})
+	}
+}
@@ -23,6 +23,7 @@ type meshOperations interface {
 	MergeMesh(ctx context.Context, mesh models.Mesh) error
 	DeleteMesh(ctx context.Context, modelID string) error
 	GetMesh(ctx context.Context, modelID string) (models.Mesh, error)
+	InsertContents(ctx context.Context, modelID string, nodes []models.Node, relations []models.Relation) error
 }
 
 // nodeOperations defines the operations on nodes.
@@ -31,6 +32,7 @@ type nodeOperations interface {
 	UpdateNode(ctx context.Context, modelID string, node models.Node) error
 	DeleteNode(ctx context.Context, modelID, nodeID string) error
 	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	GetNodeByCode(ctx context.Context, modelID, code string) (models.Node, error)
 	GetNodes(ctx context.Context, modelID string) ([]models.Node, error)
 }
 
@@ -58,6 +60,10 @@ type MeshService struct {
 	idGen    idGenerator
 	store    meshStore
 	listener meshListener
+	models   modelProvider
+	locks    lockChecker
+	schemas  models.KindSchemas
+	reviewed bool
 	now      func() time.Time
 }
 
@@ -79,6 +85,18 @@ func NewMeshService(store meshStore, idGen idGenerator, opts ...MeshServiceOptio
 	return svc
 }
 
+// ForReviewedChanges returns a copy of the service that also edits the meshes of published
+// models, for the change service to apply reviewed change requests with: the review takes
+// the place of the draft check. Archived models still refuse the edits, and the service it
+// is called on keeps refusing them, so only the holders of the copy can edit published
+// models.
+func (s *MeshService) ForReviewedChanges() *MeshService {
+	reviewed := *s
+	reviewed.reviewed = true
+
+	return &reviewed
+}
+
 // CreateMesh implements the models.MeshService interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -92,6 +110,10 @@ func (s *MeshService) CreateMesh(
 		return models.Mesh{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
 	mesh := meshFromData(modelID, data)
 
 	if err := s.store.CreateMesh(ctx, mesh); err != nil {
@@ -118,8 +140,17 @@ func (s *MeshService) UpdateMesh(
 		return models.Mesh{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
 	mesh := meshFromData(modelID, data)
 
+	// the update replaces the whole mesh, so every element is replaced or removed
+	if err := s.ensureMeshUnlocked(ctx, actor, modelID, true, true); err != nil {
+		return models.Mesh{}, err
+	}
+
 	if err := s.store.UpdateMesh(ctx, mesh); err != nil {
 		return models.Mesh{}, err
 	}
@@ -144,8 +175,17 @@ func (s *MeshService) MergeMesh(
 		return err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return err
+	}
+
 	mesh := meshFromData(modelID, data)
 
+	// the merge replaces the nodes and the relations only if the mesh carries any
+	if err := s.ensureMeshUnlocked(ctx, actor, modelID, len(mesh.Nodes) > 0, len(mesh.Relations) > 0); err != nil {
+		return err
+	}
+
 	if err := s.store.MergeMesh(ctx, mesh); err != nil {
 		return err
 	}
@@ -169,6 +209,10 @@ func (s *MeshService) DeleteMesh(
 		return err
 	}
 
+	if err := s.ensureMeshDeletable(ctx, modelID); err != nil {
+		return err
+	}
+
 	if err := s.store.DeleteMesh(ctx, modelID); err != nil {
 		return err
 	}
@@ -199,6 +243,142 @@ func (s *MeshService) GetMesh(
 	return mesh, nil
 }
 
+// LintMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) LintMesh(
+	ctx context.Context,
+	modelID string,
+) (models.LintReport, error) {
+	mesh, err := s.GetMesh(ctx, modelID)
+	if err != nil {
+		return models.LintReport{}, err
+	}
+
+	return LintMesh(mesh, s.schemas), nil
+}
+
+// ExtractSubmesh implements the models.MeshService interface.
+//
+// It returns the part of the mesh selected by the spec. See SliceMesh for details.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ExtractSubmesh(
+	ctx context.Context,
+	modelID string,
+	spec models.SliceSpec,
+) (models.Mesh, error) {
+	if err := validateSliceSpec(spec); err != nil {
+		return models.Mesh{}, err
+	}
+
+	mesh, err := s.GetMesh(ctx, modelID)
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	for _, id := range spec.Roots {
+		if _, ok := mesh.Nodes[id]; !ok {
+			return models.Mesh{}, errorz.NewNotFoundError("mesh[nodes] %v[%v] not found", modelID, id)
+		}
+	}
+
+	if spec.Container != "" {
+		if _, ok := mesh.Nodes[spec.Container]; !ok {
+			return models.Mesh{}, errorz.NewNotFoundError("mesh[nodes] %v[%v] not found", modelID, spec.Container)
+		}
+	}
+
+	return SliceMesh(mesh, spec), nil
+}
+
+// RollupMesh implements the models.MeshService interface.
+//
+// It aggregates a numeric node property over the nodes reachable from the start node.
+// See the RollupMesh function for details.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RollupMesh(
+	ctx context.Context,
+	modelID string,
+	spec models.RollupSpec,
+) (models.Rollup, error) {
+	if err := validateRollupSpec(spec); err != nil {
+		return models.Rollup{}, err
+	}
+
+	mesh, err := s.GetMesh(ctx, modelID)
+	if err != nil {
+		return models.Rollup{}, err
+	}
+
+	if _, ok := mesh.Nodes[spec.Start]; !ok {
+		return models.Rollup{}, errorz.NewNotFoundError("mesh[nodes] %v[%v] not found", modelID, spec.Start)
+	}
+
+	return RollupMesh(mesh, spec), nil
+}
+
+// ImportSubmesh implements the models.MeshService interface.
+//
+// It adds the nodes and relations of the submesh (e.g. a slice of another model) to the mesh
+// of the model with fresh IDs and returns the added contents. External relations and
+// relations whose ends are not part of the submesh are skipped. The node codes must not be
+// used by the nodes already in the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ImportSubmesh(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	submesh models.Mesh,
+) (models.Mesh, error) {
+	if err := validateModelID(modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	imported, err := s.renumberSubmesh(modelID, submesh)
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.insertContents(ctx, actor, imported)
+}
+
+// InsertContents implements the models.MeshService interface.
+//
+// It adds the nodes and relations to the mesh of the model with their IDs in a single write
+// and returns the added contents. The IDs must be fresh, as generated for new elements; the
+// relations can connect the new nodes to the nodes already in the mesh. The node codes must
+// be unique among the new nodes and must not be used by the nodes already in the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) InsertContents(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	contents models.Mesh,
+) (models.Mesh, error) {
+	if err := validateModelID(modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	inserted, err := s.validateContents(modelID, contents)
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.insertContents(ctx, actor, inserted)
+}
+
 // CreateNode implements the models.MeshService interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -212,26 +392,61 @@ func (s *MeshService) CreateNode(
 		return models.Node{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Node{}, err
+	}
+
 	if err := validateNodeData(data); err != nil {
 		return models.Node{}, err
 	}
 
-	node := nodeFromData(s.idGen.GenerateID(), data)
+	if err := models.CheckQuantities(s.schemas.Nodes, data.Kind, data.Props); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.insertNode(ctx, actor, modelID, nodeFromData(s.idGen.GenerateID(), data))
+}
 
-	if err := s.store.CreateNode(ctx, modelID, node); err != nil {
+// RestoreNode implements the models.MeshService interface.
+//
+// It creates the node with its public ID, so a deleted node can be brought back as it was.
+// The ID must not be used by another node of the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RestoreNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	node models.Node,
+) (models.Node, error) {
+	if err := validateModelID(modelID); err != nil {
 		return models.Node{}, err
 	}
 
-	updates := models.Mesh{
-		ModelID: modelID,
-		Nodes:   map[string]models.Node{node.ID: node},
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Node{}, err
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsCreated, updates, models.Mesh{}); err != nil {
+	if err := validateNodeID(node.ID); err != nil {
 		return models.Node{}, err
 	}
 
-	return node, nil
+	data := models.NodeData{Kind: node.Kind, Code: node.Code, Props: node.Props}
+
+	if err := validateNodeData(data); err != nil {
+		return models.Node{}, err
+	}
+
+	if err := models.CheckQuantities(s.schemas.Nodes, data.Kind, data.Props); err != nil {
+		return models.Node{}, err
+	}
+
+	_, lookupErr := s.store.GetNode(ctx, modelID, node.ID)
+	if err := ensureFreeID("node", node.ID, lookupErr); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.insertNode(ctx, actor, modelID, nodeFromData(node.ID, data))
 }
 
 // UpdateNode implements the models.MeshService interface.
@@ -247,16 +462,37 @@ func (s *MeshService) UpdateNode(
 		return models.Node{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Node{}, err
+	}
+
 	if err := validateNodeID(nodeID); err != nil {
 		return models.Node{}, err
 	}
 
+	if err := s.ensureNodeUnlocked(ctx, actor, modelID, nodeID); err != nil {
+		return models.Node{}, err
+	}
+
 	if err := validateNodeData(data); err != nil {
 		return models.Node{}, err
 	}
 
+	if err := models.CheckQuantities(s.schemas.Nodes, data.Kind, data.Props); err != nil {
+		return models.Node{}, err
+	}
+
 	node := nodeFromData(nodeID, data)
 
+	if err := s.ensureUniqueNodeCode(ctx, modelID, node); err != nil {
+		return models.Node{}, err
+	}
+
+	previous, err := s.store.GetNode(ctx, modelID, nodeID)
+	if err != nil {
+		return models.Node{}, err
+	}
+
 	if err := s.store.UpdateNode(ctx, modelID, node); err != nil {
 		return models.Node{}, err
 	}
@@ -266,7 +502,12 @@ func (s *MeshService) UpdateNode(
 		Nodes:   map[string]models.Node{node.ID: node},
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsUpdated, updates, models.Mesh{}); err != nil {
+	before := models.Mesh{
+		ModelID: modelID,
+		Nodes:   map[string]models.Node{previous.ID: previous},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsUpdated, updates, models.Mesh{}, before); err != nil {
 		return models.Node{}, err
 	}
 
@@ -285,10 +526,23 @@ func (s *MeshService) DeleteNode(
 		return err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return err
+	}
+
 	if err := validateNodeID(nodeID); err != nil {
 		return err
 	}
 
+	if err := s.ensureNodeUnlocked(ctx, actor, modelID, nodeID); err != nil {
+		return err
+	}
+
+	previous, err := s.store.GetNode(ctx, modelID, nodeID)
+	if err != nil {
+		return err
+	}
+
 	if err := s.store.DeleteNode(ctx, modelID, nodeID); err != nil {
 		return err
 	}
@@ -298,7 +552,12 @@ func (s *MeshService) DeleteNode(
 		Nodes:   map[string]models.Node{nodeID: {ID: nodeID}},
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsDeleted, models.Mesh{}, deletes); err != nil {
+	before := models.Mesh{
+		ModelID: modelID,
+		Nodes:   map[string]models.Node{previous.ID: previous},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsDeleted, models.Mesh{}, deletes, before); err != nil {
 		return err
 	}
 
@@ -328,6 +587,29 @@ func (s *MeshService) GetNode(
 	return node, nil
 }
 
+// GetNodeByCode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetNodeByCode(
+	ctx context.Context,
+	modelID, code string,
+) (models.Node, error) {
+	if err := validateModelID(modelID); err != nil {
+		return models.Node{}, err
+	}
+
+	if err := validateNodeCode(code); err != nil {
+		return models.Node{}, err
+	}
+
+	node, err := s.store.GetNodeByCode(ctx, modelID, code)
+	if err != nil {
+		return models.Node{}, err
+	}
+
+	return node, nil
+}
+
 // GetNodes implements the models.MeshService interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -360,26 +642,61 @@ func (s *MeshService) CreateRelation(
 		return models.Relation{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Relation{}, err
+	}
+
 	if err := validateRelationData(data); err != nil {
 		return models.Relation{}, err
 	}
 
-	relation := relationFromData(s.idGen.GenerateID(), data)
+	if err := models.CheckQuantities(s.schemas.Relations, data.Kind, data.Props); err != nil {
+		return models.Relation{}, err
+	}
 
-	if err := s.store.CreateRelation(ctx, modelID, relation); err != nil {
+	return s.insertRelation(ctx, actor, modelID, relationFromData(s.idGen.GenerateID(), data))
+}
+
+// RestoreRelation implements the models.MeshService interface.
+//
+// It creates the relation with its public ID, so a deleted relation can be brought back as
+// it was. The ID must not be used by another relation of the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RestoreRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	relation models.Relation,
+) (models.Relation, error) {
+	if err := validateModelID(modelID); err != nil {
 		return models.Relation{}, err
 	}
 
-	updates := models.Mesh{
-		ModelID:   modelID,
-		Relations: map[string]models.Relation{relation.ID: relation},
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Relation{}, err
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsCreated, updates, models.Mesh{}); err != nil {
+	if err := validateRelationID(relation.ID); err != nil {
 		return models.Relation{}, err
 	}
 
-	return relation, nil
+	data := models.RelationData{Kind: relation.Kind, From: relation.From, To: relation.To, Props: relation.Props}
+
+	if err := validateRelationData(data); err != nil {
+		return models.Relation{}, err
+	}
+
+	if err := models.CheckQuantities(s.schemas.Relations, data.Kind, data.Props); err != nil {
+		return models.Relation{}, err
+	}
+
+	_, lookupErr := s.store.GetRelation(ctx, modelID, relation.ID)
+	if err := ensureFreeID("relation", relation.ID, lookupErr); err != nil {
+		return models.Relation{}, err
+	}
+
+	return s.insertRelation(ctx, actor, modelID, relationFromData(relation.ID, data))
 }
 
 // UpdateRelation implements the models.MeshService interface.
@@ -395,16 +712,33 @@ func (s *MeshService) UpdateRelation(
 		return models.Relation{}, err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return models.Relation{}, err
+	}
+
 	if err := validateRelationID(relationID); err != nil {
 		return models.Relation{}, err
 	}
 
+	if err := s.ensureRelationUnlocked(ctx, actor, modelID, relationID); err != nil {
+		return models.Relation{}, err
+	}
+
 	if err := validateRelationData(data); err != nil {
 		return models.Relation{}, err
 	}
 
+	if err := models.CheckQuantities(s.schemas.Relations, data.Kind, data.Props); err != nil {
+		return models.Relation{}, err
+	}
+
 	relation := relationFromData(relationID, data)
 
+	previous, err := s.store.GetRelation(ctx, modelID, relationID)
+	if err != nil {
+		return models.Relation{}, err
+	}
+
 	if err := s.store.UpdateRelation(ctx, modelID, relation); err != nil {
 		return models.Relation{}, err
 	}
@@ -414,7 +748,12 @@ func (s *MeshService) UpdateRelation(
 		Relations: map[string]models.Relation{relation.ID: relation},
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsUpdated, updates, models.Mesh{}); err != nil {
+	before := models.Mesh{
+		ModelID:   modelID,
+		Relations: map[string]models.Relation{previous.ID: previous},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsUpdated, updates, models.Mesh{}, before); err != nil {
 		return models.Relation{}, err
 	}
 
@@ -433,10 +772,23 @@ func (s *MeshService) DeleteRelation(
 		return err
 	}
 
+	if err := s.ensureEditable(ctx, modelID); err != nil {
+		return err
+	}
+
 	if err := validateRelationID(relationID); err != nil {
 		return err
 	}
 
+	if err := s.ensureRelationUnlocked(ctx, actor, modelID, relationID); err != nil {
+		return err
+	}
+
+	previous, err := s.store.GetRelation(ctx, modelID, relationID)
+	if err != nil {
+		return err
+	}
+
 	if err := s.store.DeleteRelation(ctx, modelID, relationID); err != nil {
 		return err
 	}
@@ -446,7 +798,12 @@ func (s *MeshService) DeleteRelation(
 		Relations: map[string]models.Relation{relationID: {ID: relationID}},
 	}
 
-	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsDeleted, models.Mesh{}, deletes); err != nil {
+	before := models.Mesh{
+		ModelID:   modelID,
+		Relations: map[string]models.Relation{previous.ID: previous},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsDeleted, models.Mesh{}, deletes, before); err != nil {
 		return err
 	}
 
@@ -495,6 +852,393 @@ func (s *MeshService) GetRelations(
 	return relations, nil
 }
 
+// insertNode stores the new node and fires the event.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) insertNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	node models.Node,
+) (models.Node, error) {
+	if err := s.ensureUniqueNodeCode(ctx, modelID, node); err != nil {
+		return models.Node{}, err
+	}
+
+	if err := s.store.CreateNode(ctx, modelID, node); err != nil {
+		return models.Node{}, err
+	}
+
+	updates := models.Mesh{
+		ModelID: modelID,
+		Nodes:   map[string]models.Node{node.ID: node},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsCreated, updates, models.Mesh{}, models.Mesh{}); err != nil {
+		return models.Node{}, err
+	}
+
+	return node, nil
+}
+
+// insertRelation stores the new relation and fires the event.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) insertRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	relation models.Relation,
+) (models.Relation, error) {
+	if err := s.store.CreateRelation(ctx, modelID, relation); err != nil {
+		return models.Relation{}, err
+	}
+
+	updates := models.Mesh{
+		ModelID:   modelID,
+		Relations: map[string]models.Relation{relation.ID: relation},
+	}
+
+	if err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsCreated, updates, models.Mesh{}, models.Mesh{}); err != nil {
+		return models.Relation{}, err
+	}
+
+	return relation, nil
+}
+
+// ensureFreeID checks the error of looking up an element of the given kind by the ID it is
+// to be restored with. The ID is free if the element is not found.
+//
+//nolint:wrapcheck // see comment in the header
+func ensureFreeID(kind, id string, err error) error {
+	if err == nil {
+		return errorz.NewConflictError("%s %s already exists", kind, id)
+	}
+
+	if errorz.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
+// ensureEditable checks that the model owning the mesh is a draft. Published models are
+// accepted by the service for reviewed change requests, see ForReviewedChanges.
+// The check is skipped if no model provider is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureEditable(ctx context.Context, modelID string) error {
+	if s.models == nil {
+		return nil
+	}
+
+	model, err := s.models.GetModel(ctx, modelID)
+	if err != nil {
+		return err
+	}
+
+	if model.Status == models.ModelStatusPublished && s.reviewed {
+		return nil
+	}
+
+	if !model.Status.IsEditable() {
+		return errorz.NewStateError("model %s is %s and its mesh cannot be edited", modelID, model.Status)
+	}
+
+	return nil
+}
+
+// ensureNodeUnlocked checks that the node is not locked by another actor.
+// The check is skipped if no lock checker is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureNodeUnlocked(ctx context.Context, actor access.Actor, modelID, nodeID string) error {
+	if s.locks == nil {
+		return nil
+	}
+
+	return s.locks.CheckNodeLock(ctx, actor, modelID, nodeID)
+}
+
+// ensureRelationUnlocked checks that the relation is not locked by another actor.
+// The check is skipped if no lock checker is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureRelationUnlocked(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, relationID string,
+) error {
+	if s.locks == nil {
+		return nil
+	}
+
+	return s.locks.CheckRelationLock(ctx, actor, modelID, relationID)
+}
+
+// ensureMeshUnlocked checks that none of the nodes or relations replaced by a bulk mesh
+// operation is locked by another actor. The check is skipped if no lock checker is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureMeshUnlocked(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	nodes, relations bool,
+) error {
+	if s.locks == nil || (!nodes && !relations) {
+		return nil
+	}
+
+	return s.locks.CheckMeshLock(ctx, actor, modelID, nodes, relations)
+}
+
+// ensureMeshDeletable checks that the model owning the mesh is not published.
+// Meshes of archived models and meshes left behind by deleted models can be deleted.
+// The check is skipped if no model provider is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureMeshDeletable(ctx context.Context, modelID string) error {
+	if s.models == nil {
+		return nil
+	}
+
+	model, err := s.models.GetModel(ctx, modelID)
+	if err != nil {
+		if errorz.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if model.Status == models.ModelStatusPublished {
+		return errorz.NewStateError("model %s is published and its mesh cannot be deleted", modelID)
+	}
+
+	return nil
+}
+
+// ensureUniqueNodeCode checks that the code of the node is not used by another node in the mesh.
+// Nodes without a code are not checked.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureUniqueNodeCode(
+	ctx context.Context,
+	modelID string,
+	node models.Node,
+) error {
+	if node.Code == "" {
+		return nil
+	}
+
+	found, err := s.store.GetNodeByCode(ctx, modelID, node.Code)
+	if err != nil {
+		if errorz.IsNotFoundError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if found.ID != node.ID {
+		return errorz.NewConflictError("node code %s is already used by node %s", node.Code, found.ID)
+	}
+
+	return nil
+}
+
+// renumberSubmesh validates the contents of the submesh and copies them with fresh IDs.
+// The relations are rewired to the new node IDs.
+func (s *MeshService) renumberSubmesh(modelID string, submesh models.Mesh) (models.Mesh, error) {
+	imported := models.Mesh{
+		ModelID:   modelID,
+		Nodes:     make(map[string]models.Node, len(submesh.Nodes)),
+		Relations: make(map[string]models.Relation, len(submesh.Relations)),
+	}
+
+	ids := make(map[string]string, len(submesh.Nodes))
+	codes := make(map[string]string)
+
+	for _, oldID := range models.SortedKeys(submesh.Nodes) {
+		n := submesh.Nodes[oldID]
+		data := models.NodeData{Kind: n.Kind, Code: n.Code, Props: n.Props}
+
+		if err := validateNodeData(data); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := models.CheckQuantities(s.schemas.Nodes, data.Kind, data.Props); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if n.Code != "" {
+			if other, ok := codes[n.Code]; ok {
+				return models.Mesh{}, errorz.NewValidationError("node code %s is used by nodes %s and %s",
+					n.Code, other, oldID)
+			}
+
+			codes[n.Code] = oldID
+		}
+
+		node := nodeFromData(s.idGen.GenerateID(), data)
+		ids[oldID] = node.ID
+		imported.Nodes[node.ID] = node
+	}
+
+	for _, oldID := range models.SortedKeys(submesh.Relations) {
+		r := submesh.Relations[oldID]
+		from, fromOK := ids[r.From]
+		to, toOK := ids[r.To]
+
+		if r.External || !fromOK || !toOK {
+			continue
+		}
+
+		data := models.RelationData{Kind: r.Kind, From: from, To: to, Props: r.Props}
+
+		if err := validateRelationData(data); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := models.CheckQuantities(s.schemas.Relations, data.Kind, data.Props); err != nil {
+			return models.Mesh{}, err
+		}
+
+		relation := relationFromData(s.idGen.GenerateID(), data)
+		imported.Relations[relation.ID] = relation
+	}
+
+	return imported, nil
+}
+
+// validateContents validates the nodes and relations to insert and copies them into a
+// mesh of the model.
+func (s *MeshService) validateContents(modelID string, contents models.Mesh) (models.Mesh, error) {
+	inserted := models.Mesh{
+		ModelID:   modelID,
+		Nodes:     make(map[string]models.Node, len(contents.Nodes)),
+		Relations: make(map[string]models.Relation, len(contents.Relations)),
+	}
+
+	codes := make(map[string]string)
+
+	for _, id := range models.SortedKeys(contents.Nodes) {
+		n := contents.Nodes[id]
+		data := models.NodeData{Kind: n.Kind, Code: n.Code, Props: n.Props}
+
+		if err := validateNodeID(n.ID); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := validateNodeData(data); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := models.CheckQuantities(s.schemas.Nodes, data.Kind, data.Props); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if n.Code != "" {
+			if other, ok := codes[n.Code]; ok {
+				return models.Mesh{}, errorz.NewValidationError("node code %s is used by nodes %s and %s",
+					n.Code, other, n.ID)
+			}
+
+			codes[n.Code] = n.ID
+		}
+
+		inserted.Nodes[n.ID] = nodeFromData(n.ID, data)
+	}
+
+	for _, id := range models.SortedKeys(contents.Relations) {
+		r := contents.Relations[id]
+		data := models.RelationData{Kind: r.Kind, From: r.From, To: r.To, Props: r.Props}
+
+		if err := validateRelationID(r.ID); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := validateRelationData(data); err != nil {
+			return models.Mesh{}, err
+		}
+
+		if err := models.CheckQuantities(s.schemas.Relations, data.Kind, data.Props); err != nil {
+			return models.Mesh{}, err
+		}
+
+		inserted.Relations[r.ID] = relationFromData(r.ID, data)
+	}
+
+	return inserted, nil
+}
+
+// insertContents stores the new nodes and relations in a single write and fires the event.
+// The codes of the new nodes must not be used by the nodes already in the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) insertContents(
+	ctx context.Context,
+	actor access.Actor,
+	contents models.Mesh,
+) (models.Mesh, error) {
+	if err := s.ensureUniqueImportedCodes(ctx, contents.ModelID, contents); err != nil {
+		return models.Mesh{}, err
+	}
+
+	nodes := make([]models.Node, 0, len(contents.Nodes))
+
+	for _, id := range models.SortedKeys(contents.Nodes) {
+		nodes = append(nodes, contents.Nodes[id])
+	}
+
+	relations := make([]models.Relation, 0, len(contents.Relations))
+
+	for _, id := range models.SortedKeys(contents.Relations) {
+		relations = append(relations, contents.Relations[id])
+	}
+
+	if err := s.store.InsertContents(ctx, contents.ModelID, nodes, relations); err != nil {
+		return models.Mesh{}, err
+	}
+
+	err := s.fireMeshContentsEvent(ctx, actor, models.MeshContentsCreated, contents, models.Mesh{}, models.Mesh{})
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	return contents, nil
+}
+
+// ensureUniqueImportedCodes checks that the codes of the imported nodes are not used by the
+// nodes already in the mesh.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ensureUniqueImportedCodes(ctx context.Context, modelID string, imported models.Mesh) error {
+	existing, err := s.store.GetNodes(ctx, modelID)
+	if err != nil {
+		return err
+	}
+
+	codes := make(map[string]string, len(existing))
+
+	for _, n := range existing {
+		if n.Code != "" {
+			codes[n.Code] = n.ID
+		}
+	}
+
+	for _, id := range models.SortedKeys(imported.Nodes) {
+		code := imported.Nodes[id].Code
+
+		if other, ok := codes[code]; ok && code != "" {
+			return errorz.NewConflictError("node code %s is already used by node %s", code, other)
+		}
+	}
+
+	return nil
+}
+
 // fireMeshEvent fires a mesh event.
 func (s *MeshService) fireMeshEvent(
 	ctx context.Context,
@@ -527,7 +1271,7 @@ func (s *MeshService) fireMeshContentsEvent(
 	ctx context.Context,
 	actor access.Actor,
 	eventType models.EventType,
-	updates, deletes models.Mesh,
+	updates, deletes, previous models.Mesh,
 ) error {
 	if s.listener == nil {
 		return nil
@@ -539,8 +1283,9 @@ func (s *MeshService) fireMeshContentsEvent(
 			Actor:     actor,
 			Timestamp: s.now(),
 		},
-		Updates: updates,
-		Deletes: deletes,
+		Updates:  updates,
+		Deletes:  deletes,
+		Previous: previous,
 	}
 
 	if err := s.listener.HandleMeshEvent(ctx, event); err != nil {
@@ -1,5 +1,7 @@
 package service
 
+import "github.com/energimind/powermesh-core/modules/models"
+
 // MeshServiceOption defines the option for the service.
 type MeshServiceOption func(*MeshService)
 
@@ -9,3 +11,26 @@ func WithMeshListener(listener meshListener) MeshServiceOption {
 		s.listener = listener
 	}
 }
+
+// WithKindSchemas sets the kind schemas used to check the kinds of nodes and relations.
+func WithKindSchemas(schemas models.KindSchemas) MeshServiceOption {
+	return func(s *MeshService) {
+		s.schemas = schemas
+	}
+}
+
+// WithModelProvider sets the provider used to look up the status of the model owning a mesh.
+// If set, the service refuses to change the meshes of models that are not drafts.
+func WithModelProvider(provider modelProvider) MeshServiceOption {
+	return func(s *MeshService) {
+		s.models = provider
+	}
+}
+
+// WithLockChecker sets the checker of edit locks. If set, the service refuses to update or
+// delete nodes and relations locked by another actor.
+func WithLockChecker(checker lockChecker) MeshServiceOption {
+	return func(s *MeshService) {
+		s.locks = checker
+	}
+}
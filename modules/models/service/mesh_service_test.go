@@ -314,6 +314,12 @@ func TestMeshService_CreateNode(t *testing.T) {
 			data:    models.NodeData{},
 			wantErr: errorz.ValidationError{},
 		},
+		"code-conflict": {
+			actor:   adminActor,
+			modelID: validModelID,
+			data:    models.NodeData{Kind: "kind1", Code: takenNodeCode},
+			wantErr: errorz.ConflictError{},
+		},
 		"store-error": {
 			actor:      adminActor,
 			modelID:    validModelID,
@@ -388,6 +394,13 @@ func TestMeshService_UpdateNode(t *testing.T) {
 			data:    models.NodeData{},
 			wantErr: errorz.ValidationError{},
 		},
+		"code-conflict": {
+			actor:   adminActor,
+			modelID: validModelID,
+			nodeID:  validNodeID,
+			data:    models.NodeData{Kind: "kind1", Code: takenNodeCode},
+			wantErr: errorz.ConflictError{},
+		},
 		"store-error": {
 			actor:      adminActor,
 			modelID:    validModelID,
@@ -554,6 +567,62 @@ func TestMeshService_GetNode(t *testing.T) {
 	}
 }
 
+func TestMeshService_GetNodeByCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID    string
+		code       string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			code:    takenNodeCode,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-code": {
+			modelID: validModelID,
+			code:    "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			modelID: validModelID,
+			code:    "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			code:       takenNodeCode,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID: validModelID,
+			code:    takenNodeCode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+
+			svc := NewMeshService(ts, newTestIDGenerator())
+
+			node, err := svc.GetNodeByCode(context.Background(), test.modelID, test.code)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, node)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.code, node.Code)
+			}
+		})
+	}
+}
+
 func TestMeshService_GetNodes(t *testing.T) {
 	t.Parallel()
 
@@ -926,6 +995,856 @@ func TestMeshService_fireMeshContentsEvent_noListener(t *testing.T) {
 			models.MeshContentsCreated,
 			models.Mesh{},
 			models.Mesh{},
+			models.Mesh{},
 		)
 	})
 }
+
+func TestMeshService_LintMesh(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID    string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			modelID: "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID: validModelID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+
+			svc := NewMeshService(ts, newTestIDGenerator(), WithKindSchemas(models.KindSchemas{
+				Nodes: []models.KindSchema{{Kind: "kind1"}},
+			}))
+
+			report, err := svc.LintMesh(context.Background(), test.modelID)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, report)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.modelID, report.ModelID)
+			}
+		})
+	}
+}
+
+func TestMeshService_ExtractSubmesh(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID    string
+		spec       models.SliceSpec
+		storeError bool
+		wantNodes  int
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-spec": {
+			modelID: slicedModelID,
+			spec:    models.SliceSpec{Roots: []string{"b1"}, Hops: -1},
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			modelID: "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"missing-root": {
+			modelID: slicedModelID,
+			spec:    models.SliceSpec{Roots: []string{"missing"}},
+			wantErr: errorz.NotFoundError{},
+		},
+		"missing-container": {
+			modelID: slicedModelID,
+			spec:    models.SliceSpec{Container: "missing", ContainmentKinds: []string{"contains"}},
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:    slicedModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID:   slicedModelID,
+			spec:      models.SliceSpec{Roots: []string{"b1"}, Hops: 1, KeepBoundary: true},
+			wantNodes: 4,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+
+			svc := NewMeshService(ts, newTestIDGenerator())
+
+			submesh, err := svc.ExtractSubmesh(context.Background(), test.modelID, test.spec)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, submesh)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.modelID, submesh.ModelID)
+				require.Len(t, submesh.Nodes, test.wantNodes)
+			}
+		})
+	}
+}
+
+func TestMeshService_RollupMesh(t *testing.T) {
+	t.Parallel()
+
+	spec := models.RollupSpec{Start: "s1", Direction: models.DirectionOutgoing, Section: "load", Key: "kw"}
+
+	tests := map[string]struct {
+		modelID    string
+		spec       models.RollupSpec
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			spec:    spec,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-spec": {
+			modelID: slicedModelID,
+			spec:    models.RollupSpec{Start: "s1"},
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			modelID: "missing",
+			spec:    spec,
+			wantErr: errorz.NotFoundError{},
+		},
+		"missing-start": {
+			modelID: slicedModelID,
+			spec:    models.RollupSpec{Start: "missing", Section: "load", Key: "kw"},
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:    slicedModelID,
+			spec:       spec,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID: slicedModelID,
+			spec:    spec,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+
+			svc := NewMeshService(ts, newTestIDGenerator())
+
+			rollup, err := svc.RollupMesh(context.Background(), test.modelID, test.spec)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, rollup)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.modelID, rollup.ModelID)
+				require.Equal(t, 3, rollup.Nodes)
+				require.InDelta(t, 12.5, rollup.Total.Sum, 0)
+			}
+		})
+	}
+}
+
+func TestMeshService_ImportSubmesh(t *testing.T) {
+	t.Parallel()
+
+	submesh := SliceMesh(slicedMesh, models.SliceSpec{
+		Container:        "s1",
+		ContainmentKinds: []string{"contains"},
+		KeepBoundary:     true,
+	})
+
+	tests := map[string]struct {
+		modelID       string
+		submesh       models.Mesh
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			submesh: submesh,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-node": {
+			modelID: validModelID,
+			submesh: models.Mesh{Nodes: map[string]models.Node{"n1": {ID: "n1"}}},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-relation": {
+			modelID: validModelID,
+			submesh: models.Mesh{
+				Nodes:     map[string]models.Node{"n1": {ID: "n1", Kind: "bus"}},
+				Relations: map[string]models.Relation{"r1": {ID: "r1", From: "n1", To: "n1"}},
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"duplicate-code": {
+			modelID: validModelID,
+			submesh: models.Mesh{Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: "B1"},
+				"n2": {ID: "n2", Kind: "bus", Code: "B1"},
+			}},
+			wantErr: errorz.ValidationError{},
+		},
+		"code-conflict": {
+			modelID: validModelID,
+			submesh: models.Mesh{Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: takenNodeCode},
+			}},
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			submesh:    submesh,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			modelID:       validModelID,
+			submesh:       submesh,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			modelID: validModelID,
+			submesh: submesh,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+			tl := newTestMeshListener(test.listenerError)
+
+			svc := NewMeshService(ts, newTestIDGenerator(), WithMeshListener(tl))
+
+			imported, err := svc.ImportSubmesh(context.Background(), adminActor, test.modelID, test.submesh)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, imported)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.modelID, imported.ModelID)
+			require.Len(t, imported.Nodes, len(submesh.Nodes))
+			require.Len(t, imported.Relations, 3) // the external relations are skipped
+
+			for _, r := range imported.Relations {
+				require.Contains(t, imported.Nodes, r.From)
+				require.Contains(t, imported.Nodes, r.To)
+				require.False(t, r.External)
+			}
+
+			require.Equal(t, models.MeshContentsCreated, tl.eventFired.Type)
+			require.Equal(t, imported, tl.eventFired.Updates)
+		})
+	}
+}
+
+func TestMeshService_InsertContents(t *testing.T) {
+	t.Parallel()
+
+	contents := models.Mesh{
+		Nodes: map[string]models.Node{
+			"n1": {ID: "n1", Kind: "bus", Code: "B1"},
+			"n2": {ID: "n2", Kind: "breaker", Code: "CB1"},
+		},
+		Relations: map[string]models.Relation{
+			"r1": {ID: "r1", Kind: "connects", From: "n1", To: "n2"},
+			"r2": {ID: "r2", Kind: "line", From: "n2", To: validNodeID},
+		},
+	}
+
+	tests := map[string]struct {
+		modelID       string
+		contents      models.Mesh
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-modelID": {
+			modelID:  "",
+			contents: contents,
+			wantErr:  errorz.ValidationError{},
+		},
+		"invalid-node-id": {
+			modelID:  validModelID,
+			contents: models.Mesh{Nodes: map[string]models.Node{"": {Kind: "bus"}}},
+			wantErr:  errorz.ValidationError{},
+		},
+		"invalid-node": {
+			modelID:  validModelID,
+			contents: models.Mesh{Nodes: map[string]models.Node{"n1": {ID: "n1"}}},
+			wantErr:  errorz.ValidationError{},
+		},
+		"invalid-relation": {
+			modelID: validModelID,
+			contents: models.Mesh{
+				Relations: map[string]models.Relation{"r1": {ID: "r1", From: "n1", To: "n1"}},
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"duplicate-code": {
+			modelID: validModelID,
+			contents: models.Mesh{Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: "B1"},
+				"n2": {ID: "n2", Kind: "bus", Code: "B1"},
+			}},
+			wantErr: errorz.ValidationError{},
+		},
+		"code-conflict": {
+			modelID: validModelID,
+			contents: models.Mesh{Nodes: map[string]models.Node{
+				"n1": {ID: "n1", Kind: "bus", Code: takenNodeCode},
+			}},
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			contents:   contents,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			modelID:       validModelID,
+			contents:      contents,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			modelID:  validModelID,
+			contents: contents,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestMeshStore(t, test.storeError)
+			tl := newTestMeshListener(test.listenerError)
+
+			svc := NewMeshService(ts, newTestIDGenerator(), WithMeshListener(tl))
+
+			inserted, err := svc.InsertContents(context.Background(), adminActor, test.modelID, test.contents)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, inserted)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.modelID, inserted.ModelID)
+			require.Equal(t, contents.Nodes, inserted.Nodes)
+			require.Equal(t, contents.Relations, inserted.Relations)
+
+			require.Equal(t, models.MeshContentsCreated, tl.eventFired.Type)
+			require.Equal(t, inserted, tl.eventFired.Updates)
+		})
+	}
+}
+
+func TestMeshService_quantitySchemas(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestMeshStore(t, false)
+
+	svc := NewMeshService(ts, newTestIDGenerator(), WithKindSchemas(models.KindSchemas{
+		Nodes: []models.KindSchema{{Kind: "bus", Quantities: []models.QuantitySchema{
+			{Section: "rating", Key: "voltage", Dimension: models.DimensionVoltage},
+		}}},
+		Relations: []models.KindSchema{{Kind: "line", Quantities: []models.QuantitySchema{
+			{Section: "rating", Key: "current", Dimension: models.DimensionCurrent},
+		}}},
+	}))
+
+	ctx := context.Background()
+	wrongVoltage := models.PropBag{"rating": {"voltage": models.Quantity{Value: 20, Unit: models.UnitMegawatt}}}
+	wrongCurrent := models.PropBag{"rating": {"current": models.Quantity{Value: 20, Unit: models.UnitKilovolt}}}
+
+	_, err := svc.CreateNode(ctx, adminActor, validModelID, models.NodeData{Kind: "bus", Props: wrongVoltage})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.UpdateNode(ctx, adminActor, validModelID, validNodeID, models.NodeData{Kind: "bus", Props: wrongVoltage})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.CreateRelation(ctx, adminActor, validModelID, models.RelationData{
+		Kind: "line", From: "1", To: "2", Props: wrongCurrent,
+	})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.UpdateRelation(ctx, adminActor, validModelID, validRelationID, models.RelationData{
+		Kind: "line", From: "1", To: "2", Props: wrongCurrent,
+	})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.ImportSubmesh(ctx, adminActor, validModelID, models.Mesh{
+		Nodes: map[string]models.Node{"n1": {ID: "n1", Kind: "bus", Props: wrongVoltage}},
+	})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+
+	_, err = svc.ImportSubmesh(ctx, adminActor, validModelID, models.Mesh{
+		Nodes:     map[string]models.Node{"n1": {ID: "n1", Kind: "bus"}},
+		Relations: map[string]models.Relation{"r1": {ID: "r1", Kind: "line", From: "n1", To: "n1", Props: wrongCurrent}},
+	})
+
+	require.IsType(t, errorz.ValidationError{}, err)
+}
+
+func TestMeshService_modelStatusGuard(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID       string
+		storeError    bool
+		wantEditErr   error
+		wantDeleteErr error
+	}{
+		"draft": {
+			modelID: validModelID,
+		},
+		"published": {
+			modelID:       publishedModelID,
+			wantEditErr:   errorz.StateError{},
+			wantDeleteErr: errorz.StateError{},
+		},
+		"archived": {
+			modelID:     archivedModelID,
+			wantEditErr: errorz.StateError{},
+		},
+		"missing": {
+			modelID:     "missing",
+			wantEditErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:       validModelID,
+			storeError:    true,
+			wantEditErr:   errorz.StoreError{},
+			wantDeleteErr: errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+				WithModelProvider(newTestModelStore(t, test.storeError)))
+
+			err := svc.ensureEditable(context.Background(), test.modelID)
+
+			if test.wantEditErr != nil {
+				require.IsType(t, test.wantEditErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = svc.ensureMeshDeletable(context.Background(), test.modelID)
+
+			if test.wantDeleteErr != nil {
+				require.IsType(t, test.wantDeleteErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("refused-write", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithModelProvider(newTestModelStore(t, false)))
+
+		_, err := svc.CreateNode(context.Background(), adminActor, publishedModelID, validNodeData)
+
+		require.IsType(t, errorz.StateError{}, err)
+
+		err = svc.DeleteMesh(context.Background(), adminActor, publishedModelID)
+
+		require.IsType(t, errorz.StateError{}, err)
+	})
+
+	t.Run("reviewed-change", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithModelProvider(newTestModelStore(t, false)))
+
+		reviewed := svc.ForReviewedChanges()
+		ctx := context.Background()
+
+		require.NoError(t, reviewed.ensureEditable(ctx, publishedModelID))
+		require.IsType(t, errorz.StateError{}, reviewed.ensureEditable(ctx, archivedModelID))
+		require.IsType(t, errorz.StateError{}, reviewed.ensureMeshDeletable(ctx, publishedModelID))
+	})
+
+	t.Run("ordinary-caller", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithModelProvider(newTestModelStore(t, false)))
+
+		_ = svc.ForReviewedChanges()
+
+		// the service handed out a reviewed copy, but keeps refusing edits of published models
+		_, err := svc.CreateNode(context.Background(), adminActor, publishedModelID, validNodeData)
+
+		require.IsType(t, errorz.StateError{}, err)
+		require.IsType(t, errorz.StateError{}, svc.ensureEditable(context.Background(), publishedModelID))
+	})
+}
+
+func TestMeshService_lockGuard(t *testing.T) {
+	t.Parallel()
+
+	holder := access.Actor{UserID: "holder", Role: access.RoleEditor}
+	other := access.Actor{UserID: "other", Role: access.RoleEditor}
+
+	tests := map[string]struct {
+		actor   access.Actor
+		wantErr error
+	}{
+		"locked-by-other": {
+			actor:   other,
+			wantErr: errorz.ConflictError{},
+		},
+		"lock-holder": {
+			actor: holder,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+				WithLockChecker(testLockChecker{holder: holder.UserID}))
+
+			ctx := context.Background()
+
+			_, updateNodeErr := svc.UpdateNode(ctx, test.actor, validModelID, validNodeID, validNodeData)
+			deleteNodeErr := svc.DeleteNode(ctx, test.actor, validModelID, validNodeID)
+			_, updateRelationErr := svc.UpdateRelation(ctx, test.actor, validModelID, validRelationID, validRelationData)
+			deleteRelationErr := svc.DeleteRelation(ctx, test.actor, validModelID, validRelationID)
+
+			for _, err := range []error{updateNodeErr, deleteNodeErr, updateRelationErr, deleteRelationErr} {
+				if test.wantErr != nil {
+					require.IsType(t, test.wantErr, err)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+		})
+	}
+
+	t.Run("bulk-writes", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithLockChecker(testLockChecker{holder: holder.UserID}))
+
+		ctx := context.Background()
+
+		_, err := svc.UpdateMesh(ctx, other, validModelID, validMeshData)
+		require.IsType(t, errorz.ConflictError{}, err)
+
+		_, err = svc.UpdateMesh(ctx, holder, validModelID, validMeshData)
+		require.NoError(t, err)
+
+		// the merge carries no elements and replaces none
+		require.NoError(t, svc.MergeMesh(ctx, other, validModelID, validMeshData))
+	})
+
+	t.Run("bulk-merge", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithLockChecker(testLockChecker{holder: holder.UserID}))
+
+		ctx := context.Background()
+
+		require.IsType(t, errorz.ConflictError{}, svc.ensureMeshUnlocked(ctx, other, validModelID, true, false))
+		require.IsType(t, errorz.ConflictError{}, svc.ensureMeshUnlocked(ctx, other, validModelID, false, true))
+		require.NoError(t, svc.ensureMeshUnlocked(ctx, holder, validModelID, true, true))
+		require.NoError(t, svc.ensureMeshUnlocked(ctx, other, validModelID, false, false))
+	})
+
+	t.Run("unlocked-element", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(),
+			WithLockChecker(testLockChecker{holder: holder.UserID}))
+
+		// the guard lets the call through to the store, which does not know the node
+		require.IsType(t, errorz.NotFoundError{}, svc.DeleteNode(context.Background(), other, validModelID, "2"))
+	})
+}
+
+// freeIDMeshStore is a test store that knows no elements by ID, so elements can be restored.
+type freeIDMeshStore struct {
+	*testMeshStore
+}
+
+func (s freeIDMeshStore) GetNode(_ context.Context, _, nodeID string) (models.Node, error) {
+	return models.Node{}, errorz.NewNotFoundError("node %v not found", nodeID)
+}
+
+func (s freeIDMeshStore) GetRelation(_ context.Context, _, relationID string) (models.Relation, error) {
+	return models.Relation{}, errorz.NewNotFoundError("relation %v not found", relationID)
+}
+
+func TestMeshService_RestoreNode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID       string
+		node          models.Node
+		taken         bool
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-modelID": {
+			modelID: "",
+			node:    validNode,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-nodeID": {
+			modelID: validModelID,
+			node:    models.Node{Kind: "kind1"},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-node": {
+			modelID: validModelID,
+			node:    models.Node{ID: validNodeID},
+			wantErr: errorz.ValidationError{},
+		},
+		"id-taken": {
+			modelID: validModelID,
+			node:    validNode,
+			taken:   true,
+			wantErr: errorz.ConflictError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			node:       validNode,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			modelID:       validModelID,
+			node:          validNode,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			modelID: validModelID,
+			node:    validNode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ts meshStore = freeIDMeshStore{newTestMeshStore(t, test.storeError)}
+
+			if test.taken {
+				ts = newTestMeshStore(t, test.storeError)
+			}
+
+			tl := newTestMeshListener(test.listenerError)
+
+			svc := NewMeshService(ts, newTestIDGenerator(), WithMeshListener(tl))
+
+			node, err := svc.RestoreNode(context.Background(), adminActor, test.modelID, test.node)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, node)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, validNode, node)
+			require.Equal(t, models.MeshContentsCreated, tl.eventFired.Type)
+			require.Equal(t, validNode, tl.eventFired.Updates.Nodes[validNodeID])
+		})
+	}
+}
+
+func TestMeshService_RestoreRelation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID    string
+		relation   models.Relation
+		taken      bool
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-modelID": {
+			modelID:  "",
+			relation: validRelation,
+			wantErr:  errorz.ValidationError{},
+		},
+		"invalid-relationID": {
+			modelID:  validModelID,
+			relation: models.Relation{Kind: "kind1", From: "node1", To: "node2"},
+			wantErr:  errorz.ValidationError{},
+		},
+		"invalid-relation": {
+			modelID:  validModelID,
+			relation: models.Relation{ID: validRelationID},
+			wantErr:  errorz.ValidationError{},
+		},
+		"id-taken": {
+			modelID:  validModelID,
+			relation: validRelation,
+			taken:    true,
+			wantErr:  errorz.ConflictError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			relation:   validRelation,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID:  validModelID,
+			relation: validRelation,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ts meshStore = freeIDMeshStore{newTestMeshStore(t, test.storeError)}
+
+			if test.taken {
+				ts = newTestMeshStore(t, test.storeError)
+			}
+
+			tl := newTestMeshListener(false)
+
+			svc := NewMeshService(ts, newTestIDGenerator(), WithMeshListener(tl))
+
+			relation, err := svc.RestoreRelation(context.Background(), adminActor, test.modelID, test.relation)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, relation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, validRelation, relation)
+			require.Equal(t, models.MeshContentsCreated, tl.eventFired.Type)
+			require.Equal(t, validRelation, tl.eventFired.Updates.Relations[validRelationID])
+		})
+	}
+}
+
+func TestMeshService_ensureFreeID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ensureFreeID("node", validNodeID, errorz.NewNotFoundError("node not found")))
+	require.IsType(t, errorz.StoreError{}, ensureFreeID("node", validNodeID, errorz.NewStoreError("forced-error")))
+
+	err := ensureFreeID("relation", validRelationID, nil)
+
+	require.IsType(t, errorz.ConflictError{}, err)
+	require.ErrorContains(t, err, "relation "+validRelationID+" already exists")
+}
+
+func TestMeshService_previousElements(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	previousNode := models.Mesh{ModelID: validModelID, Nodes: map[string]models.Node{validNodeID: {ID: validNodeID}}}
+	previousRelation := models.Mesh{
+		ModelID:   validModelID,
+		Relations: map[string]models.Relation{validRelationID: {ID: validRelationID}},
+	}
+
+	tests := map[string]struct {
+		call func(svc *MeshService) error
+		want models.Mesh
+	}{
+		"update-node": {
+			call: func(svc *MeshService) error {
+				_, err := svc.UpdateNode(ctx, adminActor, validModelID, validNodeID, validNodeData)
+
+				return err
+			},
+			want: previousNode,
+		},
+		"delete-node": {
+			call: func(svc *MeshService) error {
+				return svc.DeleteNode(ctx, adminActor, validModelID, validNodeID)
+			},
+			want: previousNode,
+		},
+		"update-relation": {
+			call: func(svc *MeshService) error {
+				_, err := svc.UpdateRelation(ctx, adminActor, validModelID, validRelationID, validRelationData)
+
+				return err
+			},
+			want: previousRelation,
+		},
+		"delete-relation": {
+			call: func(svc *MeshService) error {
+				return svc.DeleteRelation(ctx, adminActor, validModelID, validRelationID)
+			},
+			want: previousRelation,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tl := newTestMeshListener(false)
+
+			svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator(), WithMeshListener(tl))
+
+			require.NoError(t, test.call(svc))
+			require.Equal(t, test.want, tl.eventFired.Previous)
+		})
+	}
+
+	t.Run("missing-element", func(t *testing.T) {
+		svc := NewMeshService(newTestMeshStore(t, false), newTestIDGenerator())
+
+		_, err := svc.UpdateNode(ctx, adminActor, validModelID, "2", validNodeData)
+
+		require.IsType(t, errorz.NotFoundError{}, err)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/energimind/powermesh-core/access"
 	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,7 @@ import (
 var (
 	validNodeID     = "1" // must match generated ID from testIDGenerator
 	validRelationID = "1" // must match generated ID from testIDGenerator
+	takenNodeCode   = "taken-code"
 	validMeshData   = models.MeshData{
 		Code: "code1",
 	}
@@ -52,6 +54,27 @@ var (
 		To:    validRelationData.To,
 		Props: validRelationData.Props,
 	}
+	slicedModelID = "sliced"
+	// slicedMesh is a substation s1 containing the buses b1 and b2, fed by the generator g1
+	// and connected to the bus b3 outside of the substation. The nodes carry a load.kw property.
+	slicedMesh = models.Mesh{
+		ModelID: slicedModelID,
+		Code:    "sliced",
+		Nodes: map[string]models.Node{
+			"s1": {ID: "s1", Kind: "substation", Code: "S1"},
+			"b1": {ID: "b1", Kind: "bus", Code: "B1", Props: models.PropBag{"load": {"kw": 10}}},
+			"b2": {ID: "b2", Kind: "bus", Code: "B2", Props: models.PropBag{"load": {"kw": 2.5}}},
+			"b3": {ID: "b3", Kind: "bus", Code: "B3", Props: models.PropBag{"load": {"kw": "n/a"}}},
+			"g1": {ID: "g1", Kind: "generator", Props: models.PropBag{"load": {"kw": int64(-4)}}},
+		},
+		Relations: map[string]models.Relation{
+			"c1": {ID: "c1", Kind: "contains", From: "s1", To: "b1"},
+			"c2": {ID: "c2", Kind: "contains", From: "s1", To: "b2"},
+			"r1": {ID: "r1", Kind: "line", From: "b1", To: "b2"},
+			"r2": {ID: "r2", Kind: "feeds", From: "g1", To: "b1"},
+			"r3": {ID: "r3", Kind: "line", From: "b2", To: "b3"},
+		},
+	}
 )
 
 type testMeshListener struct {
@@ -177,8 +200,11 @@ func (s *testMeshStore) GetMesh(
 
 	require.NotEmpty(s.t, modelID)
 
-	if modelID == validModelID {
+	switch modelID {
+	case validModelID:
 		return models.Mesh{ModelID: modelID}, nil
+	case slicedModelID:
+		return slicedMesh, nil
 	}
 
 	return models.Mesh{}, errorz.NewNotFoundError("mesh %v not found", modelID)
@@ -254,6 +280,26 @@ func (s *testMeshStore) GetNode(
 	return models.Node{}, errorz.NewNotFoundError("node %v not found", nodeID)
 }
 
+func (s *testMeshStore) GetNodeByCode(
+	_ context.Context,
+	modelID, code string,
+) (models.Node, error) {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return models.Node{}, s.forcedError
+	}
+
+	require.NotEmpty(s.t, modelID)
+	require.NotEmpty(s.t, code)
+
+	if code == takenNodeCode {
+		return models.Node{ID: "taken", Code: code}, nil
+	}
+
+	return models.Node{}, errorz.NewNotFoundError("node with code %v not found", code)
+}
+
 func (s *testMeshStore) GetNodes(
 	_ context.Context,
 	modelID string,
@@ -266,7 +312,7 @@ func (s *testMeshStore) GetNodes(
 
 	require.NotEmpty(s.t, modelID)
 
-	return []models.Node{{ID: validNodeID}}, nil
+	return []models.Node{{ID: validNodeID}, {ID: "taken", Code: takenNodeCode}}, nil
 }
 
 func (s *testMeshStore) CreateRelation(
@@ -353,3 +399,61 @@ func (s *testMeshStore) GetRelations(
 
 	return []models.Relation{{ID: validRelationID}}, nil
 }
+
+func (s *testMeshStore) InsertContents(
+	_ context.Context,
+	modelID string,
+	nodes []models.Node,
+	relations []models.Relation,
+) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.NotEmpty(s.t, modelID)
+
+	for _, n := range nodes {
+		require.NotEmpty(s.t, n.ID)
+	}
+
+	for _, r := range relations {
+		require.NotEmpty(s.t, r.ID)
+		require.False(s.t, r.External)
+	}
+
+	return nil
+}
+
+// testLockChecker reports the valid node and the valid relation as locked by the holder.
+type testLockChecker struct {
+	holder string
+}
+
+// Ensure that the testLockChecker implements the lockChecker interface.
+var _ lockChecker = testLockChecker{}
+
+func (c testLockChecker) CheckNodeLock(_ context.Context, actor access.Actor, _, nodeID string) error {
+	if nodeID == validNodeID && actor.UserID != c.holder {
+		return errorz.NewConflictError("node %s is locked by user %s", nodeID, c.holder)
+	}
+
+	return nil
+}
+
+func (c testLockChecker) CheckMeshLock(_ context.Context, actor access.Actor, _ string, nodes, relations bool) error {
+	if (nodes || relations) && actor.UserID != c.holder {
+		return errorz.NewConflictError("mesh is locked by user %s", c.holder)
+	}
+
+	return nil
+}
+
+func (c testLockChecker) CheckRelationLock(_ context.Context, actor access.Actor, _, relationID string) error {
+	if relationID == validRelationID && actor.UserID != c.holder {
+		return errorz.NewConflictError("relation %s is locked by user %s", relationID, c.holder)
+	}
+
+	return nil
+}
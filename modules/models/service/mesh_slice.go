@@ -0,0 +1,158 @@
+package service
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// SliceMesh extracts the part of the given in-memory mesh selected by the spec.
+// It does not access the store and does not validate the spec; unknown roots and
+// containers simply select nothing.
+//
+// The returned submesh keeps the model ID and the code of the mesh. The relations between
+// the selected nodes are copied as they are. If spec.KeepBoundary is set, the relations with
+// exactly one end in the submesh are copied too and marked as external.
+func SliceMesh(mesh models.Mesh, spec models.SliceSpec) models.Mesh {
+	selected := selectSliceNodes(mesh, spec)
+
+	submesh := models.Mesh{
+		ModelID:   mesh.ModelID,
+		Code:      mesh.Code,
+		Nodes:     make(map[string]models.Node, len(selected)),
+		Relations: make(map[string]models.Relation),
+	}
+
+	for id := range selected {
+		submesh.Nodes[id] = mesh.Nodes[id]
+	}
+
+	for id, r := range mesh.Relations {
+		from, to := selected[r.From], selected[r.To]
+
+		switch {
+		case from && to:
+			submesh.Relations[id] = r
+		case (from || to) && spec.KeepBoundary:
+			r.External = true
+			submesh.Relations[id] = r
+		}
+	}
+
+	return submesh
+}
+
+// selectSliceNodes returns the IDs of the nodes selected by the spec.
+func selectSliceNodes(mesh models.Mesh, spec models.SliceSpec) map[string]bool {
+	selected := make(map[string]bool)
+
+	if len(spec.Roots) == 0 && spec.Container == "" {
+		for id := range mesh.Nodes {
+			selected[id] = true
+		}
+	} else {
+		maps.Copy(selected, neighborhood(mesh, adjacency(mesh, spec.Direction, nil), spec.Roots, spec.Hops))
+		maps.Copy(selected, containerContents(mesh, spec.Container, spec.ContainmentKinds))
+	}
+
+	if len(spec.NodeKinds) > 0 {
+		for id := range selected {
+			if !slices.Contains(spec.NodeKinds, mesh.Nodes[id].Kind) {
+				delete(selected, id)
+			}
+		}
+	}
+
+	return selected
+}
+
+// adjacency returns the neighbors of the nodes when following the relations of the given
+// kinds in the given direction. All relations are followed if no kinds are given.
+func adjacency(mesh models.Mesh, direction models.Direction, kinds []string) map[string][]string {
+	neighbors := make(map[string][]string)
+
+	for _, r := range mesh.Relations {
+		if len(kinds) > 0 && !slices.Contains(kinds, r.Kind) {
+			continue
+		}
+
+		if direction != models.DirectionIncoming {
+			neighbors[r.From] = append(neighbors[r.From], r.To)
+		}
+
+		if direction != models.DirectionOutgoing {
+			neighbors[r.To] = append(neighbors[r.To], r.From)
+		}
+	}
+
+	return neighbors
+}
+
+// neighborhood returns the IDs of the nodes reachable from the roots over at most the given
+// number of steps in the adjacency. The roots are part of the result.
+func neighborhood(mesh models.Mesh, neighbors map[string][]string, roots []string, hops int) map[string]bool {
+	visited := make(map[string]bool)
+
+	var frontier []string
+
+	for _, id := range roots {
+		if _, ok := mesh.Nodes[id]; ok && !visited[id] {
+			visited[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+
+	for hop := 0; hop < hops && len(frontier) > 0; hop++ {
+		var next []string
+
+		for _, id := range frontier {
+			for _, neighbor := range neighbors[id] {
+				if _, ok := mesh.Nodes[neighbor]; ok && !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return visited
+}
+
+// containerContents returns the ID of the container and the IDs of all nodes it contains,
+// directly or through nested containers. The containment relations lead from the container
+// to its contents.
+func containerContents(mesh models.Mesh, container string, kinds []string) map[string]bool {
+	contents := make(map[string]bool)
+
+	if _, ok := mesh.Nodes[container]; !ok {
+		return contents
+	}
+
+	children := make(map[string][]string)
+
+	for _, r := range mesh.Relations {
+		if slices.Contains(kinds, r.Kind) {
+			children[r.From] = append(children[r.From], r.To)
+		}
+	}
+
+	contents[container] = true
+	pending := []string{container}
+
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for _, child := range children[id] {
+			if _, ok := mesh.Nodes[child]; ok && !contents[child] {
+				contents[child] = true
+				pending = append(pending, child)
+			}
+		}
+	}
+
+	return contents
+}
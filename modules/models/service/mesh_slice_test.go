@@ -0,0 +1,125 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceMesh(t *testing.T) {
+	t.Parallel()
+
+	containment := []string{"contains"}
+
+	tests := map[string]struct {
+		spec          models.SliceSpec
+		wantNodes     []string
+		wantRelations []string
+		wantExternal  []string
+	}{
+		"everything": {
+			spec:          models.SliceSpec{},
+			wantNodes:     []string{"b1", "b2", "b3", "g1", "s1"},
+			wantRelations: []string{"c1", "c2", "r1", "r2", "r3"},
+		},
+		"root-only": {
+			spec:      models.SliceSpec{Roots: []string{"b1"}},
+			wantNodes: []string{"b1"},
+		},
+		"root-only-boundary": {
+			spec:         models.SliceSpec{Roots: []string{"b1"}, KeepBoundary: true},
+			wantNodes:    []string{"b1"},
+			wantExternal: []string{"c1", "r1", "r2"},
+		},
+		"one-hop": {
+			spec:          models.SliceSpec{Roots: []string{"b1"}, Hops: 1},
+			wantNodes:     []string{"b1", "b2", "g1", "s1"},
+			wantRelations: []string{"c1", "c2", "r1", "r2"},
+		},
+		"one-hop-outgoing": {
+			spec:          models.SliceSpec{Roots: []string{"b1"}, Hops: 1, Direction: models.DirectionOutgoing},
+			wantNodes:     []string{"b1", "b2"},
+			wantRelations: []string{"r1"},
+		},
+		"one-hop-incoming": {
+			spec:          models.SliceSpec{Roots: []string{"b1"}, Hops: 1, Direction: models.DirectionIncoming},
+			wantNodes:     []string{"b1", "g1", "s1"},
+			wantRelations: []string{"c1", "r2"},
+		},
+		"container": {
+			spec:          models.SliceSpec{Container: "s1", ContainmentKinds: containment},
+			wantNodes:     []string{"b1", "b2", "s1"},
+			wantRelations: []string{"c1", "c2", "r1"},
+		},
+		"container-boundary": {
+			spec:          models.SliceSpec{Container: "s1", ContainmentKinds: containment, KeepBoundary: true},
+			wantNodes:     []string{"b1", "b2", "s1"},
+			wantRelations: []string{"c1", "c2", "r1"},
+			wantExternal:  []string{"r2", "r3"},
+		},
+		"container-and-root": {
+			spec: models.SliceSpec{
+				Roots:            []string{"b3"},
+				Container:        "s1",
+				ContainmentKinds: containment,
+			},
+			wantNodes:     []string{"b1", "b2", "b3", "s1"},
+			wantRelations: []string{"c1", "c2", "r1", "r3"},
+		},
+		"node-kinds": {
+			spec:          models.SliceSpec{NodeKinds: []string{"bus"}},
+			wantNodes:     []string{"b1", "b2", "b3"},
+			wantRelations: []string{"r1", "r3"},
+		},
+		"node-kinds-two-hops": {
+			spec:          models.SliceSpec{Roots: []string{"g1"}, Hops: 2, NodeKinds: []string{"bus"}},
+			wantNodes:     []string{"b1", "b2"},
+			wantRelations: []string{"r1"},
+		},
+		"unknown-root": {
+			spec: models.SliceSpec{Roots: []string{"missing"}, Hops: 3},
+		},
+		"unknown-container": {
+			spec: models.SliceSpec{Container: "missing", ContainmentKinds: containment},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			submesh := SliceMesh(slicedMesh, test.spec)
+
+			var relations, external []string
+
+			for _, id := range models.SortedKeys(submesh.Relations) {
+				if submesh.Relations[id].External {
+					external = append(external, id)
+				} else {
+					relations = append(relations, id)
+				}
+			}
+
+			require.Equal(t, slicedMesh.ModelID, submesh.ModelID)
+			require.Equal(t, slicedMesh.Code, submesh.Code)
+			require.Equal(t, test.wantNodes, nilIfEmpty(models.SortedKeys(submesh.Nodes)))
+			require.Equal(t, test.wantRelations, relations)
+			require.Equal(t, test.wantExternal, external)
+		})
+	}
+
+	t.Run("source-unchanged", func(t *testing.T) {
+		SliceMesh(slicedMesh, models.SliceSpec{Roots: []string{"b1"}, KeepBoundary: true})
+
+		for _, r := range slicedMesh.Relations {
+			require.False(t, r.External)
+		}
+	})
+}
+
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"slices"
+
 	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 )
@@ -13,6 +15,10 @@ func validateNodeID(id string) error {
 	return requireString(id, "node id")
 }
 
+func validateNodeCode(code string) error {
+	return requireString(code, "node code")
+}
+
 func validateRelationID(id string) error {
 	return requireString(id, "relation id")
 }
@@ -76,10 +82,62 @@ func validatePropBag(bag models.PropBag) error {
 }
 
 func validatePropSection(section models.PropSection) error {
-	for k := range section {
+	for k, v := range section {
 		if k == "" {
 			return errorz.NewValidationError("property section key is required")
 		}
+
+		if q, ok := v.(models.Quantity); ok {
+			if err := q.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSliceSpec(spec models.SliceSpec) error {
+	for _, id := range spec.Roots {
+		if err := requireString(id, "slice root"); err != nil {
+			return err
+		}
+	}
+
+	if spec.Hops < 0 {
+		return errorz.NewValidationError("slice hops must not be negative")
+	}
+
+	if !slices.Contains(models.AllDirections, spec.Direction) {
+		return errorz.NewValidationError("invalid slice direction: %v", spec.Direction)
+	}
+
+	if spec.Container != "" && len(spec.ContainmentKinds) == 0 {
+		return errorz.NewValidationError("slice container requires containment kinds")
+	}
+
+	return nil
+}
+
+func validateRollupSpec(spec models.RollupSpec) error {
+	if err := requireString(spec.Start, "rollup start node"); err != nil {
+		return err
+	}
+
+	if !slices.Contains(models.AllDirections, spec.Direction) {
+		return errorz.NewValidationError("invalid rollup direction: %v", spec.Direction)
+	}
+
+	if err := requireString(spec.Section, "rollup property section"); err != nil {
+		return err
+	}
+
+	if err := requireString(spec.Key, "rollup property key"); err != nil {
+		return err
+	}
+
+	if _, ok := spec.Unit.Dimension(); spec.Unit != "" && !ok {
+		return errorz.NewValidationError("unsupported rollup unit %q", spec.Unit)
 	}
 
 	return nil
@@ -21,6 +21,13 @@ func Test_validateNodeID(t *testing.T) {
 	require.Error(t, validateNodeID(""))
 }
 
+func Test_validateNodeCode(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateNodeCode("code"))
+	require.Error(t, validateNodeCode(""))
+}
+
 func Test_validateRelationID(t *testing.T) {
 	t.Parallel()
 
@@ -214,6 +221,17 @@ func Test_validatePropSection(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"quantity": {
+			section: models.PropSection{
+				"voltage": models.Quantity{Value: 20, Unit: models.UnitKilovolt},
+			},
+		},
+		"invalid-quantity": {
+			section: models.PropSection{
+				"voltage": models.Quantity{Value: 20, Unit: "kv"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -228,3 +246,103 @@ func Test_validatePropSection(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateSliceSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		spec    models.SliceSpec
+		wantErr bool
+	}{
+		"empty": {
+			spec: models.SliceSpec{},
+		},
+		"valid": {
+			spec: models.SliceSpec{
+				Roots:            []string{"1"},
+				Hops:             2,
+				Direction:        models.DirectionOutgoing,
+				Container:        "2",
+				ContainmentKinds: []string{"contains"},
+				NodeKinds:        []string{"bus"},
+				KeepBoundary:     true,
+			},
+		},
+		"empty-root": {
+			spec:    models.SliceSpec{Roots: []string{""}},
+			wantErr: true,
+		},
+		"negative-hops": {
+			spec:    models.SliceSpec{Roots: []string{"1"}, Hops: -1},
+			wantErr: true,
+		},
+		"invalid-direction": {
+			spec:    models.SliceSpec{Direction: models.Direction(100)},
+			wantErr: true,
+		},
+		"container-without-kinds": {
+			spec:    models.SliceSpec{Container: "2"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateSliceSpec(test.spec)
+
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateRollupSpec(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		spec    models.RollupSpec
+		wantErr bool
+	}{
+		"valid": {
+			spec: models.RollupSpec{Start: "1", Section: "load", Key: "kw"},
+		},
+		"missing-start": {
+			spec:    models.RollupSpec{Section: "load", Key: "kw"},
+			wantErr: true,
+		},
+		"invalid-direction": {
+			spec:    models.RollupSpec{Start: "1", Direction: models.Direction(100), Section: "load", Key: "kw"},
+			wantErr: true,
+		},
+		"missing-section": {
+			spec:    models.RollupSpec{Start: "1", Key: "kw"},
+			wantErr: true,
+		},
+		"missing-key": {
+			spec:    models.RollupSpec{Start: "1", Section: "load"},
+			wantErr: true,
+		},
+		"valid-unit": {
+			spec: models.RollupSpec{Start: "1", Section: "load", Key: "kw", Unit: models.UnitKilowatt},
+		},
+		"unsupported-unit": {
+			spec:    models.RollupSpec{Start: "1", Section: "load", Key: "kw", Unit: "GW"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateRollupSpec(test.spec)
+
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
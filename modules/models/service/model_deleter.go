@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// modelRemover defines the external service that removes models.
+// It is implemented by the model service.
+type modelRemover interface {
+	GetModel(ctx context.Context, id string) (models.Model, error)
+	DeleteModel(ctx context.Context, actor access.Actor, id string) error
+}
+
+// meshRemover defines the external service that removes meshes.
+// It is implemented by the mesh service.
+type meshRemover interface {
+	DeleteMesh(ctx context.Context, actor access.Actor, modelID string) error
+}
+
+// bindingRemover defines the external service that removes role bindings.
+// It is implemented by the permissions service.
+type bindingRemover interface {
+	DeleteRoleBindingsByResource(
+		ctx context.Context,
+		actor access.Actor,
+		resourceID string,
+		resourceType permissions.ResourceType,
+	) error
+}
+
+// DeletionParticipant defines an external service that owns resources of a model
+// (e.g. attachments or snapshots) and removes them when the model is deleted.
+//
+// The removal must be idempotent: it must succeed if there is nothing left to remove.
+type DeletionParticipant interface {
+	DeleteModelResources(ctx context.Context, actor access.Actor, modelID string) error
+}
+
+// transactor defines the external runner of store transactions.
+type transactor interface {
+	SupportsTransactions(ctx context.Context) (bool, error)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// deletionJournal defines the external store of pending deletions.
+type deletionJournal interface {
+	SaveDeletion(ctx context.Context, deletion models.Deletion) error
+	DeleteDeletion(ctx context.Context, modelID string) error
+	GetDeletions(ctx context.Context) ([]models.Deletion, error)
+}
+
+// ModelDeleter implements the cascading model deletion.
+//
+// It implements the models.ModelDeleter interface.
+//
+// The deletion removes the resources of the deletion participants, the mesh, the role
+// bindings of the model and finally the model itself. The steps go through the owning
+// services, so all the corresponding events are fired. Every step treats a missing
+// resource as already removed, so a deletion can be repeated until it succeeds.
+//
+// If a transactor is configured and the store supports transactions, all steps run in a
+// single transaction. Otherwise, the deletion runs as a saga: it is recorded in the journal
+// before the first step and removed from it after the last one, so ResumeDeletions can
+// complete the deletions interrupted by a failure. Note that events fired inside an aborted
+// transaction are not revoked, so the listeners must tolerate them.
+//
+// We do not wrap the errors returned by the services because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type ModelDeleter struct {
+	models       modelRemover
+	meshes       meshRemover
+	bindings     bindingRemover
+	participants []DeletionParticipant
+	tx           transactor
+	journal      deletionJournal
+	now          func() time.Time
+}
+
+// Ensure ModelDeleter implements the models.ModelDeleter interface.
+var _ models.ModelDeleter = (*ModelDeleter)(nil)
+
+// NewModelDeleter creates a new cascading model deleter.
+func NewModelDeleter(
+	modelSvc modelRemover,
+	meshSvc meshRemover,
+	bindingSvc bindingRemover,
+	opts ...ModelDeleterOption,
+) *ModelDeleter {
+	d := &ModelDeleter{
+		models:   modelSvc,
+		meshes:   meshSvc,
+		bindings: bindingSvc,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// DeleteModelCascade implements the models.ModelDeleter interface.
+//
+// Published models must be archived before they can be deleted.
+//
+//nolint:wrapcheck // see comment in the header
+func (d *ModelDeleter) DeleteModelCascade(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	model, err := d.models.GetModel(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if model.Status == models.ModelStatusPublished {
+		return errorz.NewStateError("model %s is published and must be archived before deleting", id)
+	}
+
+	transactional, err := d.transactional(ctx)
+	if err != nil {
+		return err
+	}
+
+	if transactional {
+		return d.tx.WithTransaction(ctx, func(ctx context.Context) error {
+			return d.deleteAll(ctx, actor, id)
+		})
+	}
+
+	return d.deleteAsSaga(ctx, models.Deletion{
+		ModelID:   id,
+		Actor:     actor,
+		StartedAt: d.now(),
+	})
+}
+
+// ResumeDeletions implements the models.ModelDeleter interface.
+//
+// It completes the deletions recorded in the journal on behalf of the actors who started
+// them and returns the number of completed deletions. A failed deletion does not stop the
+// others; the first error is returned and the failed deletions stay in the journal.
+//
+//nolint:wrapcheck // see comment in the header
+func (d *ModelDeleter) ResumeDeletions(ctx context.Context) (int, error) {
+	if d.journal == nil {
+		return 0, nil
+	}
+
+	pending, err := d.journal.GetDeletions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		completed int
+		firstErr  error
+	)
+
+	for _, deletion := range pending {
+		if err := d.deleteAsSaga(ctx, deletion); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		completed++
+	}
+
+	return completed, firstErr
+}
+
+// transactional checks if the deletion can run in a transaction.
+//
+//nolint:wrapcheck // see comment in the header
+func (d *ModelDeleter) transactional(ctx context.Context) (bool, error) {
+	if d.tx == nil {
+		return false, nil
+	}
+
+	return d.tx.SupportsTransactions(ctx)
+}
+
+// deleteAsSaga runs the deletion steps, recording the deletion in the journal while they run.
+//
+//nolint:wrapcheck // see comment in the header
+func (d *ModelDeleter) deleteAsSaga(ctx context.Context, deletion models.Deletion) error {
+	if d.journal != nil {
+		if err := d.journal.SaveDeletion(ctx, deletion); err != nil {
+			return err
+		}
+	}
+
+	if err := d.deleteAll(ctx, deletion.Actor, deletion.ModelID); err != nil {
+		return err
+	}
+
+	if d.journal != nil {
+		if err := d.journal.DeleteDeletion(ctx, deletion.ModelID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteAll runs the deletion steps. The model is deleted last, so it stays visible
+// until all of its resources are gone.
+//
+//nolint:wrapcheck // see comment in the header
+func (d *ModelDeleter) deleteAll(ctx context.Context, actor access.Actor, id string) error {
+	for _, p := range d.participants {
+		if err := ignoreNotFound(p.DeleteModelResources(ctx, actor, id)); err != nil {
+			return err
+		}
+	}
+
+	if err := ignoreNotFound(d.meshes.DeleteMesh(ctx, actor, id)); err != nil {
+		return err
+	}
+
+	if err := ignoreNotFound(d.bindings.DeleteRoleBindingsByResource(
+		ctx, actor, id, permissions.ResourceTypeModel)); err != nil {
+		return err
+	}
+
+	if err := ignoreNotFound(d.models.DeleteModel(ctx, actor, id)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ignoreNotFound returns nil if the error is a not found error.
+func ignoreNotFound(err error) error {
+	if errorz.IsNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
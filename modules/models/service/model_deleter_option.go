@@ -0,0 +1,28 @@
+package service
+
+// ModelDeleterOption defines the option for the model deleter.
+type ModelDeleterOption func(*ModelDeleter)
+
+// WithDeletionParticipants adds the participants whose model resources are removed
+// before the mesh and the model.
+func WithDeletionParticipants(participants ...DeletionParticipant) ModelDeleterOption {
+	return func(d *ModelDeleter) {
+		d.participants = append(d.participants, participants...)
+	}
+}
+
+// WithTransactor sets the transactor used to run the deletion in a single transaction
+// if the store supports transactions.
+func WithTransactor(tx transactor) ModelDeleterOption {
+	return func(d *ModelDeleter) {
+		d.tx = tx
+	}
+}
+
+// WithDeletionJournal sets the journal used to resume the deletions that did not run
+// in a transaction.
+func WithDeletionJournal(journal deletionJournal) ModelDeleterOption {
+	return func(d *ModelDeleter) {
+		d.journal = journal
+	}
+}
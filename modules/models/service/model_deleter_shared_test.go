@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// deletionSteps records the steps run by the model deleter and fails the configured ones.
+type deletionSteps struct {
+	models   map[string]models.Model
+	calls    []string
+	failures map[string]error
+}
+
+// Ensure that the deletionSteps implements the dependencies of the model deleter.
+var (
+	_ modelRemover        = (*deletionSteps)(nil)
+	_ meshRemover         = (*deletionSteps)(nil)
+	_ bindingRemover      = (*deletionSteps)(nil)
+	_ DeletionParticipant = (*deletionSteps)(nil)
+)
+
+func newDeletionSteps(existing ...models.Model) *deletionSteps {
+	s := &deletionSteps{
+		models:   make(map[string]models.Model),
+		failures: make(map[string]error),
+	}
+
+	for _, m := range existing {
+		s.models[m.ID] = m
+	}
+
+	return s
+}
+
+func (s *deletionSteps) record(step string) error {
+	s.calls = append(s.calls, step)
+
+	return s.failures[step]
+}
+
+func (s *deletionSteps) GetModel(_ context.Context, id string) (models.Model, error) {
+	if err := s.failures["get-model"]; err != nil {
+		return models.Model{}, err
+	}
+
+	if m, ok := s.models[id]; ok {
+		return m, nil
+	}
+
+	return models.Model{}, errorz.NewNotFoundError("model %v not found", id)
+}
+
+func (s *deletionSteps) DeleteModel(_ context.Context, _ access.Actor, id string) error {
+	if err := s.record("model"); err != nil {
+		return err
+	}
+
+	if _, ok := s.models[id]; !ok {
+		return errorz.NewNotFoundError("model %v not found", id)
+	}
+
+	delete(s.models, id)
+
+	return nil
+}
+
+func (s *deletionSteps) DeleteMesh(_ context.Context, _ access.Actor, _ string) error {
+	return s.record("mesh")
+}
+
+func (s *deletionSteps) DeleteRoleBindingsByResource(
+	_ context.Context,
+	_ access.Actor,
+	_ string,
+	resourceType permissions.ResourceType,
+) error {
+	if resourceType != permissions.ResourceTypeModel {
+		return errors.New("unexpected resource type")
+	}
+
+	return s.record("bindings")
+}
+
+func (s *deletionSteps) DeleteModelResources(_ context.Context, _ access.Actor, _ string) error {
+	return s.record("participant")
+}
+
+type testTransactor struct {
+	supported    bool
+	forcedError  error
+	transactions int
+}
+
+// Ensure that the testTransactor implements the transactor interface.
+var _ transactor = (*testTransactor)(nil)
+
+func (t *testTransactor) SupportsTransactions(_ context.Context) (bool, error) {
+	if t.forcedError != nil {
+		return false, t.forcedError
+	}
+
+	return t.supported, nil
+}
+
+func (t *testTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.transactions++
+
+	return fn(ctx)
+}
+
+type testDeletionJournal struct {
+	deletions   map[string]models.Deletion
+	forcedError error
+}
+
+// Ensure that the testDeletionJournal implements the deletionJournal interface.
+var _ deletionJournal = (*testDeletionJournal)(nil)
+
+func newTestDeletionJournal(pending ...models.Deletion) *testDeletionJournal {
+	j := &testDeletionJournal{
+		deletions: make(map[string]models.Deletion),
+	}
+
+	for _, d := range pending {
+		j.deletions[d.ModelID] = d
+	}
+
+	return j
+}
+
+func (j *testDeletionJournal) SaveDeletion(_ context.Context, deletion models.Deletion) error {
+	if j.forcedError != nil {
+		return j.forcedError
+	}
+
+	j.deletions[deletion.ModelID] = deletion
+
+	return nil
+}
+
+func (j *testDeletionJournal) DeleteDeletion(_ context.Context, modelID string) error {
+	delete(j.deletions, modelID)
+
+	return nil
+}
+
+func (j *testDeletionJournal) GetDeletions(_ context.Context) ([]models.Deletion, error) {
+	if j.forcedError != nil {
+		return nil, j.forcedError
+	}
+
+	deletions := make([]models.Deletion, 0, len(j.deletions))
+
+	for _, id := range models.SortedKeys(j.deletions) {
+		deletions = append(deletions, j.deletions[id])
+	}
+
+	return deletions, nil
+}
+
+func fixedNow() time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+}
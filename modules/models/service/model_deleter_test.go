@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+var allDeletionSteps = []string{"participant", "mesh", "bindings", "model"}
+
+func TestModelDeleter_DeleteModelCascade(t *testing.T) {
+	t.Parallel()
+
+	draft := models.Model{ID: validModelID}
+	published := models.Model{ID: publishedModelID, Status: models.ModelStatusPublished}
+	archived := models.Model{ID: archivedModelID, Status: models.ModelStatusArchived}
+
+	tests := map[string]struct {
+		id              string
+		failures        map[string]error
+		transactor      *testTransactor
+		wantSteps       []string
+		wantTransaction bool
+		wantPending     bool
+		wantErr         error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"published": {
+			id:      publishedModelID,
+			wantErr: errorz.StateError{},
+		},
+		"saga": {
+			id:        validModelID,
+			wantSteps: allDeletionSteps,
+		},
+		"archived": {
+			id:        archivedModelID,
+			wantSteps: allDeletionSteps,
+		},
+		"missing-resources": {
+			id: validModelID,
+			failures: map[string]error{
+				"participant": errorz.NewNotFoundError("no attachments"),
+				"mesh":        errorz.NewNotFoundError("mesh not found"),
+			},
+			wantSteps: allDeletionSteps,
+		},
+		"saga-step-error": {
+			id:          validModelID,
+			failures:    map[string]error{"bindings": errorz.NewStoreError("forced error")},
+			wantSteps:   []string{"participant", "mesh", "bindings"},
+			wantPending: true,
+			wantErr:     errorz.StoreError{},
+		},
+		"transaction": {
+			id:              validModelID,
+			transactor:      &testTransactor{supported: true},
+			wantSteps:       allDeletionSteps,
+			wantTransaction: true,
+		},
+		"transactions-unsupported": {
+			id:         validModelID,
+			transactor: &testTransactor{supported: false},
+			wantSteps:  allDeletionSteps,
+		},
+		"transaction-step-error": {
+			id:              validModelID,
+			failures:        map[string]error{"mesh": errorz.NewStoreError("forced error")},
+			transactor:      &testTransactor{supported: true},
+			wantSteps:       []string{"participant", "mesh"},
+			wantTransaction: true,
+			wantErr:         errorz.StoreError{},
+		},
+		"transactor-error": {
+			id:         validModelID,
+			transactor: &testTransactor{forcedError: errorz.NewStoreError("forced error")},
+			wantErr:    errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			steps := newDeletionSteps(draft, published, archived)
+			journal := newTestDeletionJournal()
+
+			for step, err := range test.failures {
+				steps.failures[step] = err
+			}
+
+			opts := []ModelDeleterOption{
+				WithDeletionParticipants(steps),
+				WithDeletionJournal(journal),
+			}
+
+			if test.transactor != nil {
+				opts = append(opts, WithTransactor(test.transactor))
+			}
+
+			d := NewModelDeleter(steps, steps, steps, opts...)
+			d.now = fixedNow
+
+			err := d.DeleteModelCascade(context.Background(), adminActor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				require.NotContains(t, steps.models, test.id)
+			}
+
+			require.Equal(t, test.wantSteps, steps.calls)
+
+			if test.transactor != nil {
+				require.Equal(t, test.wantTransaction, test.transactor.transactions == 1)
+			}
+
+			if test.wantPending {
+				require.Equal(t, models.Deletion{
+					ModelID:   test.id,
+					Actor:     adminActor,
+					StartedAt: fixedNow(),
+				}, journal.deletions[test.id])
+			} else {
+				require.Empty(t, journal.deletions)
+			}
+		})
+	}
+
+	t.Run("journal-error", func(t *testing.T) {
+		steps := newDeletionSteps(draft)
+		journal := newTestDeletionJournal()
+		journal.forcedError = errorz.NewStoreError("forced error")
+
+		d := NewModelDeleter(steps, steps, steps, WithDeletionJournal(journal))
+
+		err := d.DeleteModelCascade(context.Background(), adminActor, validModelID)
+
+		require.IsType(t, errorz.StoreError{}, err)
+		require.Empty(t, steps.calls)
+	})
+
+	t.Run("without-journal", func(t *testing.T) {
+		steps := newDeletionSteps(draft)
+
+		d := NewModelDeleter(steps, steps, steps)
+
+		require.NoError(t, d.DeleteModelCascade(context.Background(), adminActor, validModelID))
+		require.Equal(t, []string{"mesh", "bindings", "model"}, steps.calls)
+	})
+}
+
+func TestModelDeleter_ResumeDeletions(t *testing.T) {
+	t.Parallel()
+
+	actor := access.NewActor("user", access.RoleAdmin)
+
+	t.Run("success", func(t *testing.T) {
+		// the model of the first deletion is already gone; the second one is interrupted
+		// before its model has been deleted
+		steps := newDeletionSteps(models.Model{ID: "2"})
+		journal := newTestDeletionJournal(
+			models.Deletion{ModelID: "1", Actor: actor},
+			models.Deletion{ModelID: "2", Actor: actor},
+		)
+
+		d := NewModelDeleter(steps, steps, steps, WithDeletionJournal(journal))
+
+		completed, err := d.ResumeDeletions(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, 2, completed)
+		require.Empty(t, journal.deletions)
+		require.Empty(t, steps.models)
+	})
+
+	t.Run("step-error", func(t *testing.T) {
+		steps := newDeletionSteps()
+		steps.failures["mesh"] = errorz.NewStoreError("forced error")
+		journal := newTestDeletionJournal(models.Deletion{ModelID: "1", Actor: actor})
+
+		d := NewModelDeleter(steps, steps, steps, WithDeletionJournal(journal))
+
+		completed, err := d.ResumeDeletions(context.Background())
+
+		require.IsType(t, errorz.StoreError{}, err)
+		require.Zero(t, completed)
+		require.Contains(t, journal.deletions, "1")
+	})
+
+	t.Run("journal-error", func(t *testing.T) {
+		steps := newDeletionSteps()
+		journal := newTestDeletionJournal()
+		journal.forcedError = errorz.NewStoreError("forced error")
+
+		d := NewModelDeleter(steps, steps, steps, WithDeletionJournal(journal))
+
+		_, err := d.ResumeDeletions(context.Background())
+
+		require.IsType(t, errorz.StoreError{}, err)
+	})
+
+	t.Run("without-journal", func(t *testing.T) {
+		steps := newDeletionSteps()
+
+		d := NewModelDeleter(steps, steps, steps)
+
+		completed, err := d.ResumeDeletions(context.Background())
+
+		require.NoError(t, err)
+		require.Zero(t, completed)
+	})
+}
+
+func Test_ignoreNotFound(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ignoreNotFound(nil))
+	require.NoError(t, ignoreNotFound(errorz.NewNotFoundError("not found")))
+	require.IsType(t, errorz.StoreError{}, ignoreNotFound(errorz.NewStoreError("failed")))
+}
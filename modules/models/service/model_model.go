@@ -8,5 +8,22 @@ func modelFromData(id string, data models.ModelData) models.Model {
 		Code:        data.Code,
 		Name:        data.Name,
 		Description: data.Description,
+		Labels:      data.Labels,
+		Status:      models.ModelStatusDraft,
 	}
 }
+
+// defaultModelPageLimit is the page limit used when the query does not specify one.
+const defaultModelPageLimit = 50
+
+func modelQueryWithDefaults(query models.ModelQuery) models.ModelQuery {
+	if query.SortBy == "" {
+		query.SortBy = models.ModelSortByCode
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultModelPageLimit
+	}
+
+	return query
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"testing"
 
+	"github.com/energimind/powermesh-core/modules/models"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +13,14 @@ func Test_modelFromData(t *testing.T) {
 		modelFromData(validModelID, validModelData),
 	)
 }
+
+func Test_modelQueryWithDefaults(t *testing.T) {
+	require.Equal(t,
+		models.ModelQuery{SortBy: models.ModelSortByCode, Limit: defaultModelPageLimit},
+		modelQueryWithDefaults(models.ModelQuery{}),
+	)
+
+	query := models.ModelQuery{SortBy: models.ModelSortByName, Limit: 5}
+
+	require.Equal(t, query, modelQueryWithDefaults(query))
+}
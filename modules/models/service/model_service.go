@@ -7,6 +7,7 @@ import (
 	"github.com/energimind/powermesh-core/access"
 	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
 )
 
 // modelStore defines the external model store.
@@ -14,8 +15,10 @@ type modelStore interface {
 	CreateModel(ctx context.Context, model models.Model) error
 	UpdateModel(ctx context.Context, model models.Model) error
 	DeleteModel(ctx context.Context, id string) error
+	UpdateModelStatus(ctx context.Context, current models.Model, status models.ModelStatus) error
 	GetModel(ctx context.Context, id string) (models.Model, error)
 	GetModelsByIDs(ctx context.Context, ids []string) ([]models.Model, error)
+	ListModels(ctx context.Context, query models.ModelQuery, ids []string) (models.ModelPage, error)
 }
 
 // modelListener defines the external model event modelListener.
@@ -33,6 +36,7 @@ type ModelService struct {
 	idGen    idGenerator
 	store    modelStore
 	listener modelListener
+	access   accessResolver
 	now      func() time.Time
 }
 
@@ -96,7 +100,18 @@ func (s *ModelService) UpdateModel(
 		return models.Model{}, err
 	}
 
+	current, err := s.store.GetModel(ctx, id)
+	if err != nil {
+		return models.Model{}, err
+	}
+
+	if !current.Status.IsEditable() {
+		return models.Model{}, errorz.NewStateError("model %s is %s and cannot be edited", id, current.Status)
+	}
+
 	model := modelFromData(id, data)
+	model.Status = current.Status
+	model.Version = current.Version
 
 	if err := s.store.UpdateModel(ctx, model); err != nil {
 		return models.Model{}, err
@@ -121,6 +136,15 @@ func (s *ModelService) DeleteModel(
 		return err
 	}
 
+	current, err := s.store.GetModel(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if current.Status == models.ModelStatusPublished {
+		return errorz.NewStateError("model %s is published and must be archived before deleting", id)
+	}
+
 	if err := s.store.DeleteModel(ctx, id); err != nil {
 		return err
 	}
@@ -132,6 +156,28 @@ func (s *ModelService) DeleteModel(
 	return nil
 }
 
+// PublishModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) PublishModel(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (models.Model, error) {
+	return s.transitionModel(ctx, actor, id, models.ModelStatusPublished, models.ModelPublished)
+}
+
+// ArchiveModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) ArchiveModel(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) (models.Model, error) {
+	return s.transitionModel(ctx, actor, id, models.ModelStatusArchived, models.ModelArchived)
+}
+
 // GetModel implements the models.ModelService interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -176,6 +222,100 @@ func (s *ModelService) GetModelsByIDs(
 	return found, nil
 }
 
+// ListModels implements the models.ModelService interface.
+//
+// If the query is restricted to the models accessible to a user, the accessible models are
+// resolved first and passed to the store as the set of candidate IDs.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) ListModels(
+	ctx context.Context,
+	query models.ModelQuery,
+) (models.ModelPage, error) {
+	if err := validateModelQuery(query); err != nil {
+		return models.ModelPage{}, err
+	}
+
+	query = modelQueryWithDefaults(query)
+
+	var ids []string
+
+	if query.AccessibleTo != "" {
+		accessible, err := s.accessibleModelIDs(ctx, query.AccessibleTo)
+		if err != nil {
+			return models.ModelPage{}, err
+		}
+
+		if len(accessible) == 0 {
+			return models.ModelPage{Models: []models.Model{}}, nil
+		}
+
+		ids = accessible
+	}
+
+	page, err := s.store.ListModels(ctx, query, ids)
+	if err != nil {
+		return models.ModelPage{}, err
+	}
+
+	return page, nil
+}
+
+// accessibleModelIDs returns the IDs of the models accessible to the given user.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) accessibleModelIDs(ctx context.Context, userID string) ([]string, error) {
+	if s.access == nil {
+		return nil, errorz.NewInternalError("access resolver is not configured")
+	}
+
+	return s.access.GetAccessibleResources(ctx, permissions.AccessibleResourcesQuery{
+		UserID:       userID,
+		ResourceType: permissions.ResourceTypeModel,
+	})
+}
+
+// transitionModel moves the model to the given status and fires the given event.
+// The status is only changed if the stored model still has the status and version it was
+// read with; otherwise a conflict error is returned, so concurrent transitions cannot both
+// succeed.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) transitionModel(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	status models.ModelStatus,
+	eventType models.EventType,
+) (models.Model, error) {
+	if err := validateID(id); err != nil {
+		return models.Model{}, err
+	}
+
+	model, err := s.store.GetModel(ctx, id)
+	if err != nil {
+		return models.Model{}, err
+	}
+
+	if !model.Status.CanTransitionTo(status) {
+		return models.Model{}, errorz.NewStateError("model %s cannot change from %s to %s",
+			id, model.Status, status)
+	}
+
+	if err := s.store.UpdateModelStatus(ctx, model, status); err != nil {
+		return models.Model{}, err
+	}
+
+	model.Status = status
+	model.Version++
+
+	if err := s.fireModelEvent(ctx, actor, eventType, model); err != nil {
+		return models.Model{}, err
+	}
+
+	return model, nil
+}
+
 // fireModelEvent fires a model event.
 func (s *ModelService) fireModelEvent(
 	ctx context.Context,
@@ -9,3 +9,11 @@ func WithModelListener(listener modelListener) ModelServiceOption {
 		s.listener = listener
 	}
 }
+
+// WithAccessResolver sets the resolver used to restrict listed models to those
+// accessible to a user.
+func WithAccessResolver(resolver accessResolver) ModelServiceOption {
+	return func(s *ModelService) {
+		s.access = resolver
+	}
+}
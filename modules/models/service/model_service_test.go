@@ -97,6 +97,12 @@ func TestModelService_UpdateModel(t *testing.T) {
 			data:    models.ModelData{},
 			wantErr: errorz.ValidationError{},
 		},
+		"not-editable": {
+			actor:   adminActor,
+			id:      publishedModelID,
+			data:    validModelData,
+			wantErr: errorz.StateError{},
+		},
 		"store-error": {
 			actor:      adminActor,
 			id:         validModelID,
@@ -163,6 +169,16 @@ func TestModelService_DeleteModel(t *testing.T) {
 			id:      "",
 			wantErr: errorz.ValidationError{},
 		},
+		"not-found": {
+			actor:   adminActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"published": {
+			actor:   adminActor,
+			id:      publishedModelID,
+			wantErr: errorz.StateError{},
+		},
 		"store-error": {
 			actor:      adminActor,
 			id:         validModelID,
@@ -207,6 +223,160 @@ func TestModelService_DeleteModel(t *testing.T) {
 	}
 }
 
+func TestModelService_PublishModel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		storeError    bool
+		listenerError bool
+		wantEvent     models.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   adminActor,
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			actor:   adminActor,
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"already-published": {
+			actor:   adminActor,
+			id:      publishedModelID,
+			wantErr: errorz.StateError{},
+		},
+		"archived": {
+			actor:   adminActor,
+			id:      archivedModelID,
+			wantErr: errorz.StateError{},
+		},
+		"store-error": {
+			actor:      adminActor,
+			id:         validModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"concurrent-change": {
+			actor:   adminActor,
+			id:      changedModelID,
+			wantErr: errorz.ConflictError{},
+		},
+		"modelListener-error": {
+			actor:         adminActor,
+			id:            validModelID,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			actor:     adminActor,
+			id:        validModelID,
+			wantEvent: models.ModelPublished,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestModelStore(t, test.storeError)
+			tl := newTestModelListener(test.listenerError)
+
+			svc := NewModelService(ts, newTestIDGenerator(), WithModelListener(tl))
+
+			model, err := svc.PublishModel(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, model)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, models.ModelStatusPublished, model.Status)
+				require.Equal(t, int64(1), model.Version)
+			}
+
+			if test.wantEvent != "" {
+				requireModelEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestModelService_ArchiveModel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor         access.Actor
+		id            string
+		storeError    bool
+		listenerError bool
+		wantEvent     models.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			actor:   adminActor,
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"already-archived": {
+			actor:   adminActor,
+			id:      archivedModelID,
+			wantErr: errorz.StateError{},
+		},
+		"store-error": {
+			actor:      adminActor,
+			id:         publishedModelID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"concurrent-change": {
+			actor:   adminActor,
+			id:      changedModelID,
+			wantErr: errorz.ConflictError{},
+		},
+		"draft": {
+			actor:     adminActor,
+			id:        validModelID,
+			wantEvent: models.ModelArchived,
+		},
+		"published": {
+			actor:     adminActor,
+			id:        publishedModelID,
+			wantEvent: models.ModelArchived,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestModelStore(t, test.storeError)
+			tl := newTestModelListener(test.listenerError)
+
+			svc := NewModelService(ts, newTestIDGenerator(), WithModelListener(tl))
+
+			model, err := svc.ArchiveModel(context.Background(), test.actor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, model)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, models.ModelStatusArchived, model.Status)
+			}
+
+			if test.wantEvent != "" {
+				requireModelEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
 func TestModelService_GetModel(t *testing.T) {
 	t.Parallel()
 
@@ -304,6 +474,80 @@ func TestModelService_GetModelsByIDs(t *testing.T) {
 	}
 }
 
+func TestModelService_ListModels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query    models.ModelQuery
+		resolver *testAccessResolver
+		storeErr bool
+		want     []models.Model
+		wantErr  error
+	}{
+		"invalid-sort": {
+			query:   models.ModelQuery{SortBy: "unknown"},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-limit": {
+			query:   models.ModelQuery{Limit: -1},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			query:    models.ModelQuery{},
+			storeErr: true,
+			wantErr:  errorz.StoreError{},
+		},
+		"no-resolver": {
+			query:   models.ModelQuery{AccessibleTo: "user1"},
+			wantErr: errorz.InternalError{},
+		},
+		"resolver-error": {
+			query:    models.ModelQuery{AccessibleTo: "user1"},
+			resolver: newTestAccessResolver(true),
+			wantErr:  errorz.StoreError{},
+		},
+		"nothing-accessible": {
+			query:    models.ModelQuery{AccessibleTo: "user1"},
+			resolver: newTestAccessResolver(false),
+			want:     []models.Model{},
+		},
+		"accessible": {
+			query:    models.ModelQuery{AccessibleTo: "user1"},
+			resolver: newTestAccessResolver(false, validModelID),
+			want:     []models.Model{validModel},
+		},
+		"success": {
+			query: models.ModelQuery{Search: "code", SortBy: models.ModelSortByName, Limit: 10},
+			want:  []models.Model{validModel},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestModelStore(t, test.storeErr)
+
+			opts := []ModelServiceOption{WithModelListener(newTestModelListener(false))}
+
+			if test.resolver != nil {
+				opts = append(opts, WithAccessResolver(test.resolver))
+			}
+
+			svc := NewModelService(ts, newTestIDGenerator(), opts...)
+
+			page, err := svc.ListModels(context.Background(), test.query)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, page)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.want, page.Models)
+			}
+		})
+	}
+}
+
 func TestModelService_fireModelEvent_noListener(t *testing.T) {
 	t.Parallel()
 
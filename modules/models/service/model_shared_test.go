@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,12 +17,14 @@ var (
 		Code:        "code1",
 		Name:        "name1",
 		Description: "description1",
+		Labels:      map[string]string{"region": "north"},
 	}
 	validModel = models.Model{
 		ID:          validModelID,
 		Code:        validModelData.Code,
 		Name:        validModelData.Name,
 		Description: validModelData.Description,
+		Labels:      validModelData.Labels,
 	}
 )
 
@@ -134,13 +138,44 @@ func (s *testModelStore) GetModel(
 
 	require.NotEmpty(s.t, id)
 
-	if id == validModelID {
+	switch id {
+	case validModelID:
 		return models.Model{ID: id}, nil
+	case publishedModelID:
+		return models.Model{ID: id, Status: models.ModelStatusPublished}, nil
+	case archivedModelID:
+		return models.Model{ID: id, Status: models.ModelStatusArchived}, nil
+	case changedModelID:
+		return models.Model{ID: id, Version: 1}, nil
 	}
 
 	return models.Model{}, errorz.NewNotFoundError("model %v not found", id)
 }
 
+// UpdateModelStatus fails with a conflict for the changed model, as if it had been changed
+// since it was read.
+func (s *testModelStore) UpdateModelStatus(
+	_ context.Context,
+	current models.Model,
+	status models.ModelStatus,
+) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.NotEmpty(s.t, current.ID)
+	require.Contains(s.t, models.AllModelStatuses, status)
+	require.True(s.t, current.Status.CanTransitionTo(status))
+
+	if current.ID == changedModelID {
+		return errorz.NewConflictError("model %s has changed", current.ID)
+	}
+
+	return nil
+}
+
 func (s *testModelStore) GetModelsByIDs(
 	_ context.Context,
 	ids []string,
@@ -164,6 +199,63 @@ func (s *testModelStore) GetModelsByIDs(
 	return found, nil
 }
 
+func (s *testModelStore) ListModels(
+	_ context.Context,
+	query models.ModelQuery,
+	ids []string,
+) (models.ModelPage, error) {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return models.ModelPage{}, s.forcedError
+	}
+
+	require.NotEmpty(s.t, query.SortBy)
+	require.Positive(s.t, query.Limit)
+
+	if ids != nil && !slices.Contains(ids, validModelID) {
+		return models.ModelPage{Models: []models.Model{}}, nil
+	}
+
+	return models.ModelPage{Models: []models.Model{validModel}}, nil
+}
+
+type testAccessResolver struct {
+	forcedError error
+	resources   []string
+}
+
+// Ensure that the testAccessResolver implements the accessResolver interface.
+var _ accessResolver = (*testAccessResolver)(nil)
+
+func newTestAccessResolver(forcedError bool, resources ...string) *testAccessResolver {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testAccessResolver{
+		forcedError: err,
+		resources:   resources,
+	}
+}
+
+func (r *testAccessResolver) GetAccessibleResources(
+	_ context.Context,
+	query permissions.AccessibleResourcesQuery,
+) ([]string, error) {
+	if r.forcedError != nil {
+		return nil, r.forcedError
+	}
+
+	if query.UserID == "" || query.ResourceType != permissions.ResourceTypeModel {
+		return nil, errorz.NewValidationError("invalid query")
+	}
+
+	return r.resources, nil
+}
+
 func requireModelEventFired(t *testing.T, wantEvent models.EventType, listener *testModelListener) {
 	t.Helper()
 
@@ -1,9 +1,16 @@
 package service
 
 import (
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 )
 
+// maxModelPageLimit is the maximum number of models that can be requested in a single page.
+const maxModelPageLimit = 500
+
+// maxModelLabels is the maximum number of labels a model can have.
+const maxModelLabels = 64
+
 func validateID(id string) error {
 	return requireString(id, "id")
 }
@@ -25,5 +32,60 @@ func validateModelData(data models.ModelData) error {
 		return err
 	}
 
+	if err := validateLabels(data.Labels); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxModelLabels {
+		return errorz.NewValidationError("a model can have at most %d labels", maxModelLabels)
+	}
+
+	for key, value := range labels {
+		if !models.IsValidLabelKey(key) {
+			return errorz.NewValidationError("invalid label key %q", key)
+		}
+
+		if !models.IsValidLabelValue(value) {
+			return errorz.NewValidationError("invalid value %q of label %s", value, key)
+		}
+	}
+
+	return nil
+}
+
+func validateModelSortField(field models.ModelSortField) error {
+	switch field {
+	case "", models.ModelSortByCode, models.ModelSortByName:
+		return nil
+	}
+
+	return errorz.NewValidationError("sort field %s is not supported", field)
+}
+
+func validateModelPageLimit(limit int) error {
+	if limit < 0 || limit > maxModelPageLimit {
+		return errorz.NewValidationError("limit must be between 0 and %d", maxModelPageLimit)
+	}
+
+	return nil
+}
+
+func validateModelQuery(query models.ModelQuery) error {
+	if err := validateModelSortField(query.SortBy); err != nil {
+		return err
+	}
+
+	if err := validateModelPageLimit(query.Limit); err != nil {
+		return err
+	}
+
+	if err := query.Labels.Validate(); err != nil {
+		return err //nolint:wrapcheck // already a validation error
+	}
+
 	return nil
 }
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"strconv"
 	"testing"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 	"github.com/stretchr/testify/require"
 )
@@ -56,6 +58,14 @@ func Test_validateModelData(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		"invalid-labels": {
+			data: models.ModelData{
+				Code:   "code",
+				Name:   "name",
+				Labels: map[string]string{"region": "north pole"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -70,3 +80,100 @@ func Test_validateModelData(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateLabels(t *testing.T) {
+	t.Parallel()
+
+	tooMany := make(map[string]string, maxModelLabels+1)
+
+	for i := range maxModelLabels + 1 {
+		tooMany["key"+strconv.Itoa(i)] = "value"
+	}
+
+	tests := map[string]struct {
+		labels  map[string]string
+		wantErr bool
+	}{
+		"nil": {
+			labels: nil,
+		},
+		"valid": {
+			labels: map[string]string{"region": "north", "example.com/team": "grid-ops", "draft": ""},
+		},
+		"invalid-key": {
+			labels:  map[string]string{"-region": "north"},
+			wantErr: true,
+		},
+		"invalid-value": {
+			labels:  map[string]string{"region": "north/east"},
+			wantErr: true,
+		},
+		"too-many": {
+			labels:  tooMany,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateLabels(test.labels)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_validateModelQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query   models.ModelQuery
+		wantErr bool
+	}{
+		"empty": {
+			query: models.ModelQuery{},
+		},
+		"valid": {
+			query: models.ModelQuery{SortBy: models.ModelSortByName, Limit: maxModelPageLimit},
+		},
+		"invalid-sort": {
+			query:   models.ModelQuery{SortBy: "unknown"},
+			wantErr: true,
+		},
+		"negative-limit": {
+			query:   models.ModelQuery{Limit: -1},
+			wantErr: true,
+		},
+		"excessive-limit": {
+			query:   models.ModelQuery{Limit: maxModelPageLimit + 1},
+			wantErr: true,
+		},
+		"valid-labels": {
+			query: models.ModelQuery{Labels: models.LabelSelector{
+				{Key: "region", Operator: models.LabelEquals, Values: []string{"north"}},
+			}},
+		},
+		"invalid-labels": {
+			query: models.ModelQuery{Labels: models.LabelSelector{
+				{Key: "region", Operator: models.LabelIn},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateModelQuery(test.query)
+
+			if test.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// profileCSVColumns is the number of columns of a profile CSV record.
+const profileCSVColumns = 2
+
+// parseProfileCSV reads the points of a profile from CSV data.
+//
+// Every record holds the time in RFC 3339 format and the value. The first record is taken
+// as a header and skipped if its time cannot be parsed. Surrounding spaces are ignored.
+func parseProfileCSV(r io.Reader) ([]models.ProfilePoint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = profileCSVColumns
+	reader.TrimLeadingSpace = true
+
+	var points []models.ProfilePoint
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errorz.NewValidationError("invalid profile csv: %v", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(record[0]))
+		if err != nil {
+			if first {
+				continue
+			}
+
+			return nil, errorz.NewValidationError("invalid profile csv: line %d: invalid time %q", line, record[0])
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, errorz.NewValidationError("invalid profile csv: line %d: invalid value %q", line, record[1])
+		}
+
+		points = append(points, models.ProfilePoint{Time: t, Value: value})
+	}
+
+	return points, nil
+}
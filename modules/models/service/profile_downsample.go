@@ -0,0 +1,96 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// DownsampleProfile combines the points of a profile into intervals of the given length.
+// The points must be ordered by time. It does not validate its input; a non-positive
+// interval returns the points unchanged.
+//
+// The intervals are aligned to multiples of the interval length since the zero time, so
+// hourly and daily intervals start at full hours and days in UTC. Every interval with at
+// least one point results in a point at the start of the interval; empty intervals are
+// left out.
+func DownsampleProfile(
+	points []models.ProfilePoint,
+	interval time.Duration,
+	aggregation models.Aggregation,
+) []models.ProfilePoint {
+	if interval <= 0 {
+		return points
+	}
+
+	var (
+		result []models.ProfilePoint
+		bucket []float64
+		start  time.Time
+	)
+
+	flush := func() {
+		if len(bucket) > 0 {
+			result = append(result, models.ProfilePoint{Time: start, Value: aggregate(bucket, aggregation)})
+		}
+	}
+
+	for _, p := range points {
+		if t := p.Time.Truncate(interval); !t.Equal(start) || len(bucket) == 0 {
+			flush()
+
+			start = t
+			bucket = bucket[:0]
+		}
+
+		bucket = append(bucket, p.Value)
+	}
+
+	flush()
+
+	return result
+}
+
+// aggregate combines the values of an interval. The values must not be empty.
+func aggregate(values []float64, aggregation models.Aggregation) float64 {
+	switch aggregation {
+	case models.AggregationMin:
+		result := values[0]
+
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+
+		return result
+	case models.AggregationMax:
+		result := values[0]
+
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+
+		return result
+	case models.AggregationSum:
+		return sum(values)
+	case models.AggregationFirst:
+		return values[0]
+	case models.AggregationLast:
+		return values[len(values)-1]
+	case models.AggregationMean:
+		return sum(values) / float64(len(values))
+	}
+
+	return sum(values) / float64(len(values))
+}
+
+// sum returns the sum of the values.
+func sum(values []float64) float64 {
+	var total float64
+
+	for _, v := range values {
+		total += v
+	}
+
+	return total
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsampleProfile(t *testing.T) {
+	t.Parallel()
+
+	// two full hours and a single point in the fourth hour
+	points := append(quarterHourPoints(1, 2, 3, 4, 5, 6, 7, 8),
+		models.ProfilePoint{Time: profileStart.Add(3*time.Hour + 30*time.Minute), Value: 10})
+
+	hourly := func(values ...float64) []models.ProfilePoint {
+		return []models.ProfilePoint{
+			{Time: profileStart, Value: values[0]},
+			{Time: profileStart.Add(time.Hour), Value: values[1]},
+			{Time: profileStart.Add(3 * time.Hour), Value: values[2]},
+		}
+	}
+
+	tests := map[string]struct {
+		aggregation models.Aggregation
+		want        []models.ProfilePoint
+	}{
+		"mean":  {aggregation: models.AggregationMean, want: hourly(2.5, 6.5, 10)},
+		"min":   {aggregation: models.AggregationMin, want: hourly(1, 5, 10)},
+		"max":   {aggregation: models.AggregationMax, want: hourly(4, 8, 10)},
+		"sum":   {aggregation: models.AggregationSum, want: hourly(10, 26, 10)},
+		"first": {aggregation: models.AggregationFirst, want: hourly(1, 5, 10)},
+		"last":  {aggregation: models.AggregationLast, want: hourly(4, 8, 10)},
+	}
+
+	for _, a := range models.AllAggregations {
+		require.Contains(t, tests, a.String())
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, DownsampleProfile(points, time.Hour, test.aggregation))
+		})
+	}
+
+	t.Run("aligned", func(t *testing.T) {
+		shifted := []models.ProfilePoint{
+			{Time: profileStart.Add(50 * time.Minute), Value: 1},
+			{Time: profileStart.Add(70 * time.Minute), Value: 2},
+		}
+
+		require.Equal(t, []models.ProfilePoint{
+			{Time: profileStart, Value: 1},
+			{Time: profileStart.Add(time.Hour), Value: 2},
+		}, DownsampleProfile(shifted, time.Hour, models.AggregationMean))
+	})
+
+	t.Run("no-interval", func(t *testing.T) {
+		require.Equal(t, points, DownsampleProfile(points, 0, models.AggregationMean))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Empty(t, DownsampleProfile(nil, time.Hour, models.AggregationMean))
+	})
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// profileStore defines the external profile store.
+type profileStore interface {
+	WritePoints(ctx context.Context, key models.ProfileKey, points []models.ProfilePoint) error
+	DeleteProfile(ctx context.Context, key models.ProfileKey) error
+	DeleteNodeProfiles(ctx context.Context, modelID string, nodeIDs []string) error
+	DeleteModelProfiles(ctx context.Context, modelID string) error
+	GetPoints(ctx context.Context, key models.ProfileKey, from, to time.Time) ([]models.ProfilePoint, error)
+	GetProfileKeys(ctx context.Context, modelID, nodeID string) ([]models.ProfileKey, error)
+}
+
+// nodeProvider defines the external provider of mesh nodes.
+// It is implemented by the mesh service.
+type nodeProvider interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+}
+
+// ProfileService implements the profile service.
+//
+// It implements the models.ProfileService interface.
+//
+// Profiles are bound to existing nodes. Changes are announced as mesh events of type
+// models.MeshProfilesUpdated, so the consumers of mesh events can refresh their caches.
+// The service also listens to mesh events and removes the profiles of deleted nodes and
+// meshes; it takes part in the cascading model deletion for the same purpose.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type ProfileService struct {
+	store    profileStore
+	nodes    nodeProvider
+	listener meshListener
+	models   modelProvider
+	now      func() time.Time
+}
+
+// Ensure ProfileService implements the models.ProfileService interface.
+var _ models.ProfileService = (*ProfileService)(nil)
+
+// Ensure ProfileService can remove the profiles of deleted models.
+var _ DeletionParticipant = (*ProfileService)(nil)
+
+// NewProfileService creates a new profile service.
+func NewProfileService(store profileStore, nodes nodeProvider, opts ...ProfileServiceOption) *ProfileService {
+	svc := &ProfileService{
+		store: store,
+		nodes: nodes,
+		now:   time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// WriteProfile implements the models.ProfileService interface.
+//
+// The points replace the stored points of the profile in the time range from the first to
+// the last written point, so a profile can be corrected by writing it again.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) WriteProfile(
+	ctx context.Context,
+	actor access.Actor,
+	key models.ProfileKey,
+	points []models.ProfilePoint,
+) error {
+	if err := validateProfileKey(key); err != nil {
+		return err
+	}
+
+	sorted, err := validateProfilePoints(points)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureWritable(ctx, key); err != nil {
+		return err
+	}
+
+	if err := s.store.WritePoints(ctx, key, sorted); err != nil {
+		return err
+	}
+
+	return s.fireProfileEvent(ctx, actor, key)
+}
+
+// ImportProfileCSV implements the models.ProfileService interface.
+//
+// The CSV records hold the time in RFC 3339 format and the value; an optional header is
+// skipped. The points are written like with WriteProfile. It returns the number of
+// imported points.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) ImportProfileCSV(
+	ctx context.Context,
+	actor access.Actor,
+	key models.ProfileKey,
+	r io.Reader,
+) (int, error) {
+	if err := validateProfileKey(key); err != nil {
+		return 0, err
+	}
+
+	points, err := parseProfileCSV(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.WriteProfile(ctx, actor, key, points); err != nil {
+		return 0, err
+	}
+
+	return len(points), nil
+}
+
+// DeleteProfile implements the models.ProfileService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) DeleteProfile(
+	ctx context.Context,
+	actor access.Actor,
+	key models.ProfileKey,
+) error {
+	if err := validateProfileKey(key); err != nil {
+		return err
+	}
+
+	if err := s.ensureEditable(ctx, key.ModelID); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteProfile(ctx, key); err != nil {
+		return err
+	}
+
+	return s.fireProfileEvent(ctx, actor, key)
+}
+
+// ReadProfile implements the models.ProfileService interface.
+//
+// A profile without points in the range is returned empty.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) ReadProfile(
+	ctx context.Context,
+	key models.ProfileKey,
+	query models.ProfileQuery,
+) (models.Profile, error) {
+	if err := validateProfileKey(key); err != nil {
+		return models.Profile{}, err
+	}
+
+	if err := validateProfileQuery(query); err != nil {
+		return models.Profile{}, err
+	}
+
+	points, err := s.store.GetPoints(ctx, key, query.From, query.To)
+	if err != nil {
+		return models.Profile{}, err
+	}
+
+	return models.Profile{
+		Key:    key,
+		Points: DownsampleProfile(points, query.Interval, query.Aggregation),
+	}, nil
+}
+
+// ListProfiles implements the models.ProfileService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) ListProfiles(
+	ctx context.Context,
+	modelID, nodeID string,
+) ([]models.ProfileKey, error) {
+	if err := validateModelID(modelID); err != nil {
+		return nil, err
+	}
+
+	if err := validateNodeID(nodeID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.store.GetProfileKeys(ctx, modelID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// HandleMeshEvent removes the profiles of the nodes and meshes deleted by the event.
+// The removal is not announced, because the consumers are informed by the mesh event itself.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) HandleMeshEvent(ctx context.Context, event models.MeshEvent) error {
+	//nolint:exhaustive // only deletions are of interest
+	switch event.Type {
+	case models.MeshDeleted:
+		return s.store.DeleteModelProfiles(ctx, event.Updates.ModelID)
+	case models.MeshContentsDeleted:
+		if len(event.Deletes.Nodes) == 0 {
+			return nil
+		}
+
+		return s.store.DeleteNodeProfiles(ctx, event.Deletes.ModelID, models.SortedKeys(event.Deletes.Nodes))
+	}
+
+	return nil
+}
+
+// DeleteModelResources implements the DeletionParticipant interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) DeleteModelResources(ctx context.Context, _ access.Actor, modelID string) error {
+	if err := validateModelID(modelID); err != nil {
+		return err
+	}
+
+	return s.store.DeleteModelProfiles(ctx, modelID)
+}
+
+// ensureWritable checks that the model is editable and the node of the profile exists.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) ensureWritable(ctx context.Context, key models.ProfileKey) error {
+	if err := s.ensureEditable(ctx, key.ModelID); err != nil {
+		return err
+	}
+
+	if _, err := s.nodes.GetNode(ctx, key.ModelID, key.NodeID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureEditable checks that the model owning the profile is a draft.
+// The check is skipped if no model provider is configured.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileService) ensureEditable(ctx context.Context, modelID string) error {
+	if s.models == nil {
+		return nil
+	}
+
+	model, err := s.models.GetModel(ctx, modelID)
+	if err != nil {
+		return err
+	}
+
+	if !model.Status.IsEditable() {
+		return errorz.NewStateError("model %s is %s and its profiles cannot be edited", modelID, model.Status)
+	}
+
+	return nil
+}
+
+// fireProfileEvent fires a mesh event announcing the change of the profile.
+func (s *ProfileService) fireProfileEvent(ctx context.Context, actor access.Actor, key models.ProfileKey) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := models.MeshEvent{
+		EventHeader: models.EventHeader{
+			Type:      models.MeshProfilesUpdated,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Updates: models.Mesh{
+			ModelID: key.ModelID,
+			Nodes:   map[string]models.Node{key.NodeID: {ID: key.NodeID}},
+		},
+		Profiles: []models.ProfileKey{key},
+	}
+
+	if err := s.listener.HandleMeshEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", models.MeshProfilesUpdated, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package service
+
+// ProfileServiceOption defines the option for the profile service.
+type ProfileServiceOption func(*ProfileService)
+
+// WithProfileListener sets the listener for the service.
+func WithProfileListener(listener meshListener) ProfileServiceOption {
+	return func(s *ProfileService) {
+		s.listener = listener
+	}
+}
+
+// WithProfileModelProvider sets the provider used to look up the status of the model owning
+// a profile. If set, the service refuses to change the profiles of models that are not drafts.
+func WithProfileModelProvider(provider modelProvider) ProfileServiceOption {
+	return func(s *ProfileService) {
+		s.models = provider
+	}
+}
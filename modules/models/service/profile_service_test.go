@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileService_WriteProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		key           models.ProfileKey
+		points        []models.ProfilePoint
+		modelID       string
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-key": {
+			key:     models.ProfileKey{ModelID: validModelID, NodeID: existingNodeID},
+			points:  quarterHourPoints(1),
+			wantErr: errorz.ValidationError{},
+		},
+		"no-points": {
+			key:     validProfileKey,
+			wantErr: errorz.ValidationError{},
+		},
+		"missing-node": {
+			key:     models.ProfileKey{ModelID: validModelID, NodeID: "missing", Series: "load"},
+			points:  quarterHourPoints(1),
+			wantErr: errorz.NotFoundError{},
+		},
+		"published-model": {
+			key:     models.ProfileKey{ModelID: publishedModelID, NodeID: existingNodeID, Series: "load"},
+			points:  quarterHourPoints(1),
+			wantErr: errorz.StateError{},
+		},
+		"store-error": {
+			key:        validProfileKey,
+			points:     quarterHourPoints(1),
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			key:           validProfileKey,
+			points:        quarterHourPoints(1),
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			key:    validProfileKey,
+			points: quarterHourPoints(1, 2, 3),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps := newTestProfileStore(t, test.storeError)
+			ml := newTestMeshListener(test.listenerError)
+
+			svc := NewProfileService(ps, newTestMeshEditor(false),
+				WithProfileListener(ml),
+				WithProfileModelProvider(newTestModelStore(t, false)))
+
+			err := svc.WriteProfile(context.Background(), adminActor, test.key, test.points)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, ml.eventFired)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.points, ps.points)
+				requireProfileEventFired(t, test.key, ml)
+			}
+		})
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		ps := newTestProfileStore(t, false)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+		points := quarterHourPoints(1, 2, 3)
+
+		err := svc.WriteProfile(context.Background(), adminActor, validProfileKey,
+			[]models.ProfilePoint{points[2], points[0], points[1]})
+
+		require.NoError(t, err)
+		require.Equal(t, points, ps.points)
+	})
+}
+
+func TestProfileService_ImportProfileCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		csv        string
+		storeError bool
+		wantCount  int
+		wantPoints []models.ProfilePoint
+		wantErr    error
+	}{
+		"header": {
+			csv:        "time,value\n2024-01-01T00:00:00Z,1.5\n2024-01-01T00:15:00Z, 2\n",
+			wantCount:  2,
+			wantPoints: quarterHourPoints(1.5, 2),
+		},
+		"no-header": {
+			csv:        "2024-01-01T00:00:00Z,1\n2024-01-01T00:15:00Z,-2.5\n2024-01-01T00:30:00Z,3e1\n",
+			wantCount:  3,
+			wantPoints: quarterHourPoints(1, -2.5, 30),
+		},
+		"offset": {
+			csv:        "2024-01-01T01:00:00+01:00,1\n",
+			wantCount:  1,
+			wantPoints: quarterHourPoints(1),
+		},
+		"empty": {
+			csv:     "time,value\n",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-time": {
+			csv:     "2024-01-01T00:00:00Z,1\nyesterday,2\n",
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-value": {
+			csv:     "2024-01-01T00:00:00Z,high\n",
+			wantErr: errorz.ValidationError{},
+		},
+		"wrong-columns": {
+			csv:     "2024-01-01T00:00:00Z,1,2\n",
+			wantErr: errorz.ValidationError{},
+		},
+		"duplicate-time": {
+			csv:     "2024-01-01T00:00:00Z,1\n2024-01-01T00:00:00Z,2\n",
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			csv:        "2024-01-01T00:00:00Z,1\n",
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps := newTestProfileStore(t, test.storeError)
+			svc := NewProfileService(ps, newTestMeshEditor(false))
+
+			count, err := svc.ImportProfileCSV(context.Background(), adminActor, validProfileKey,
+				strings.NewReader(test.csv))
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Zero(t, count)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.wantCount, count)
+				require.Len(t, ps.points, len(test.wantPoints))
+
+				for i, p := range test.wantPoints {
+					require.True(t, p.Time.Equal(ps.points[i].Time))
+					require.InDelta(t, p.Value, ps.points[i].Value, 1e-12)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileService_DeleteProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		key           models.ProfileKey
+		storeError    bool
+		listenerError bool
+		wantErr       error
+	}{
+		"invalid-key": {
+			key:     models.ProfileKey{},
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			key:     models.ProfileKey{ModelID: validModelID, NodeID: existingNodeID, Series: "missing"},
+			wantErr: errorz.NotFoundError{},
+		},
+		"published-model": {
+			key:     models.ProfileKey{ModelID: publishedModelID, NodeID: existingNodeID, Series: "load"},
+			wantErr: errorz.StateError{},
+		},
+		"store-error": {
+			key:        validProfileKey,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			key:           validProfileKey,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			key: validProfileKey,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ml := newTestMeshListener(test.listenerError)
+
+			svc := NewProfileService(newTestProfileStore(t, test.storeError), newTestMeshEditor(false),
+				WithProfileListener(ml),
+				WithProfileModelProvider(newTestModelStore(t, false)))
+
+			err := svc.DeleteProfile(context.Background(), adminActor, test.key)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				requireProfileEventFired(t, test.key, ml)
+			}
+		})
+	}
+}
+
+func TestProfileService_ReadProfile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		key        models.ProfileKey
+		query      models.ProfileQuery
+		storeError bool
+		wantPoints []models.ProfilePoint
+		wantErr    error
+	}{
+		"invalid-key": {
+			key:     models.ProfileKey{},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-range": {
+			key:     validProfileKey,
+			query:   models.ProfileQuery{From: profileStart, To: profileStart},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-interval": {
+			key:     validProfileKey,
+			query:   models.ProfileQuery{Interval: -time.Hour},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-aggregation": {
+			key:     validProfileKey,
+			query:   models.ProfileQuery{Aggregation: -1},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			key:        validProfileKey,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"raw": {
+			key:        validProfileKey,
+			wantPoints: quarterHourPoints(1, 2, 3, 4, 5, 6),
+		},
+		"range": {
+			key: validProfileKey,
+			query: models.ProfileQuery{
+				From: profileStart.Add(15 * time.Minute),
+				To:   profileStart.Add(45 * time.Minute),
+			},
+			wantPoints: quarterHourPoints(1, 2, 3)[1:],
+		},
+		"downsampled": {
+			key: validProfileKey,
+			query: models.ProfileQuery{
+				Interval:    time.Hour,
+				Aggregation: models.AggregationMax,
+			},
+			wantPoints: []models.ProfilePoint{
+				{Time: profileStart, Value: 4},
+				{Time: profileStart.Add(time.Hour), Value: 6},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ps := newTestProfileStore(t, test.storeError)
+			ps.points = quarterHourPoints(1, 2, 3, 4, 5, 6)
+
+			svc := NewProfileService(ps, newTestMeshEditor(false))
+
+			profile, err := svc.ReadProfile(context.Background(), test.key, test.query)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, profile)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.key, profile.Key)
+				require.Equal(t, test.wantPoints, profile.Points)
+			}
+		})
+	}
+}
+
+func TestProfileService_ListProfiles(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modelID    string
+		nodeID     string
+		storeError bool
+		wantKeys   []models.ProfileKey
+		wantErr    error
+	}{
+		"invalid-model-id": {
+			nodeID:  existingNodeID,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-node-id": {
+			modelID: validModelID,
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			nodeID:     existingNodeID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"none": {
+			modelID:  validModelID,
+			nodeID:   "other",
+			wantKeys: []models.ProfileKey{},
+		},
+		"success": {
+			modelID:  validModelID,
+			nodeID:   existingNodeID,
+			wantKeys: []models.ProfileKey{validProfileKey},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewProfileService(newTestProfileStore(t, test.storeError), newTestMeshEditor(false))
+
+			keys, err := svc.ListProfiles(context.Background(), test.modelID, test.nodeID)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Nil(t, keys)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.wantKeys, keys)
+			}
+		})
+	}
+}
+
+func TestProfileService_cleanup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("node-deleted", func(t *testing.T) {
+		ps := newTestProfileStore(t, false)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+
+		err := svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+			Deletes: models.Mesh{
+				ModelID: validModelID,
+				Nodes:   map[string]models.Node{"n2": {ID: "n2"}, "n1": {ID: "n1"}},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"n1", "n2"}, ps.deletedNodes)
+	})
+
+	t.Run("relation-deleted", func(t *testing.T) {
+		ps := newTestProfileStore(t, false)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+
+		err := svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshContentsDeleted},
+			Deletes: models.Mesh{
+				ModelID:   validModelID,
+				Relations: map[string]models.Relation{"r1": {ID: "r1"}},
+			},
+		})
+
+		require.NoError(t, err)
+		require.Nil(t, ps.deletedNodes)
+	})
+
+	t.Run("mesh-deleted", func(t *testing.T) {
+		ps := newTestProfileStore(t, false)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+
+		err := svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshDeleted},
+			Updates:     models.Mesh{ModelID: validModelID},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, validModelID, ps.deletedModelID)
+	})
+
+	t.Run("other-event", func(t *testing.T) {
+		ps := newTestProfileStore(t, true)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+
+		err := svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshContentsUpdated},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("store-error", func(t *testing.T) {
+		svc := NewProfileService(newTestProfileStore(t, true), newTestMeshEditor(false))
+
+		err := svc.HandleMeshEvent(context.Background(), models.MeshEvent{
+			EventHeader: models.EventHeader{Type: models.MeshDeleted},
+			Updates:     models.Mesh{ModelID: validModelID},
+		})
+
+		require.IsType(t, errorz.StoreError{}, err)
+	})
+
+	t.Run("model-deleted", func(t *testing.T) {
+		ps := newTestProfileStore(t, false)
+		svc := NewProfileService(ps, newTestMeshEditor(false))
+
+		require.IsType(t, errorz.ValidationError{}, svc.DeleteModelResources(context.Background(), adminActor, ""))
+		require.NoError(t, svc.DeleteModelResources(context.Background(), adminActor, validModelID))
+		require.Equal(t, validModelID, ps.deletedModelID)
+	})
+}
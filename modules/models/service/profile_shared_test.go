@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	validProfileKey = models.ProfileKey{
+		ModelID: validModelID,
+		NodeID:  existingNodeID,
+		Series:  "load",
+	}
+	profileStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// quarterHourPoints returns points every 15 minutes starting at profileStart.
+func quarterHourPoints(values ...float64) []models.ProfilePoint {
+	points := make([]models.ProfilePoint, len(values))
+
+	for i, v := range values {
+		points[i] = models.ProfilePoint{Time: profileStart.Add(time.Duration(i) * 15 * time.Minute), Value: v}
+	}
+
+	return points
+}
+
+// testProfileStore keeps the points of a single profile in memory.
+type testProfileStore struct {
+	t              *testing.T
+	forcedError    error
+	points         []models.ProfilePoint
+	deletedNodes   []string
+	deletedModelID string
+}
+
+// Ensure that the testProfileStore implements the profileStore interface.
+var _ profileStore = (*testProfileStore)(nil)
+
+func newTestProfileStore(t *testing.T, forcedError bool) *testProfileStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testProfileStore{
+		t:           t,
+		forcedError: err,
+	}
+}
+
+func (s *testProfileStore) WritePoints(_ context.Context, key models.ProfileKey, points []models.ProfilePoint) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.Equal(s.t, validProfileKey, key)
+
+	s.points = points
+
+	return nil
+}
+
+func (s *testProfileStore) DeleteProfile(_ context.Context, key models.ProfileKey) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	if key != validProfileKey {
+		return errorz.NewNotFoundError("profile %s of node %s not found", key.Series, key.NodeID)
+	}
+
+	s.points = nil
+
+	return nil
+}
+
+func (s *testProfileStore) DeleteNodeProfiles(_ context.Context, modelID string, nodeIDs []string) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.Equal(s.t, validModelID, modelID)
+
+	s.deletedNodes = nodeIDs
+
+	return nil
+}
+
+func (s *testProfileStore) DeleteModelProfiles(_ context.Context, modelID string) error {
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	s.deletedModelID = modelID
+
+	return nil
+}
+
+func (s *testProfileStore) GetPoints(
+	_ context.Context,
+	_ models.ProfileKey,
+	from, to time.Time,
+) ([]models.ProfilePoint, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	points := []models.ProfilePoint{}
+
+	for _, p := range s.points {
+		if (from.IsZero() || !p.Time.Before(from)) && (to.IsZero() || p.Time.Before(to)) {
+			points = append(points, p)
+		}
+	}
+
+	return points, nil
+}
+
+func (s *testProfileStore) GetProfileKeys(_ context.Context, modelID, nodeID string) ([]models.ProfileKey, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	if modelID == validProfileKey.ModelID && nodeID == validProfileKey.NodeID {
+		return []models.ProfileKey{validProfileKey}, nil
+	}
+
+	return []models.ProfileKey{}, nil
+}
+
+func requireProfileEventFired(t *testing.T, key models.ProfileKey, listener *testMeshListener) {
+	t.Helper()
+
+	require.Equal(t, models.MeshProfilesUpdated, listener.eventFired.Type)
+	require.NotEmpty(t, listener.eventFired.Actor)
+	require.NotEmpty(t, listener.eventFired.Timestamp)
+	require.Equal(t, key.ModelID, listener.eventFired.Updates.ModelID)
+	require.Contains(t, listener.eventFired.Updates.Nodes, key.NodeID)
+	require.Equal(t, []models.ProfileKey{key}, listener.eventFired.Profiles)
+}
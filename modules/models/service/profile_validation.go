@@ -0,0 +1,67 @@
+package service
+
+import (
+	"math"
+	"slices"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+func validateProfileKey(key models.ProfileKey) error {
+	if err := validateModelID(key.ModelID); err != nil {
+		return err
+	}
+
+	if err := validateNodeID(key.NodeID); err != nil {
+		return err
+	}
+
+	return requireString(key.Series, "profile series")
+}
+
+// validateProfilePoints checks the points and returns them ordered by time.
+// The points must have distinct times and finite values.
+func validateProfilePoints(points []models.ProfilePoint) ([]models.ProfilePoint, error) {
+	if len(points) == 0 {
+		return nil, errorz.NewValidationError("profile points are required")
+	}
+
+	sorted := slices.Clone(points)
+
+	slices.SortStableFunc(sorted, func(a, b models.ProfilePoint) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	for i, p := range sorted {
+		if p.Time.IsZero() {
+			return nil, errorz.NewValidationError("profile point time is required")
+		}
+
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			return nil, errorz.NewValidationError("profile value at %s is not finite", p.Time)
+		}
+
+		if i > 0 && sorted[i-1].Time.Equal(p.Time) {
+			return nil, errorz.NewValidationError("duplicate profile point at %s", p.Time)
+		}
+	}
+
+	return sorted, nil
+}
+
+func validateProfileQuery(query models.ProfileQuery) error {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return errorz.NewValidationError("profile range start must be before its end")
+	}
+
+	if query.Interval < 0 {
+		return errorz.NewValidationError("profile interval must not be negative")
+	}
+
+	if !slices.Contains(models.AllAggregations, query.Aggregation) {
+		return errorz.NewValidationError("invalid profile aggregation: %v", query.Aggregation)
+	}
+
+	return nil
+}
@@ -8,8 +8,11 @@ import (
 )
 
 var (
-	adminActor   = access.Actor{Role: access.RoleAdmin}
-	validModelID = "1"
+	adminActor       = access.Actor{Role: access.RoleAdmin}
+	validModelID     = "1"
+	publishedModelID = "published"
+	archivedModelID  = "archived"
+	changedModelID   = "changed"
 )
 
 type testIDGenerator struct {
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// placeholderPattern matches the ${name} placeholders in template codes and property values.
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`) //nolint:gochecknoglobals
+
+// placeholderNames returns the names of the parameters referenced by the placeholders in s.
+func placeholderNames(s string) []string {
+	var names []string
+
+	for _, m := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+		names = append(names, m[1])
+	}
+
+	return names
+}
+
+// propStrings returns the string values of the property bag, including nested ones.
+func propStrings(bag models.PropBag) []string {
+	var values []string
+
+	var collect func(v any)
+
+	collect = func(v any) {
+		switch tv := v.(type) {
+		case string:
+			values = append(values, tv)
+		case []any:
+			for _, e := range tv {
+				collect(e)
+			}
+		case map[string]any:
+			for _, e := range tv {
+				collect(e)
+			}
+		}
+	}
+
+	for _, section := range bag {
+		for _, v := range section {
+			collect(v)
+		}
+	}
+
+	return values
+}
+
+// resolveTemplateParams returns the values of all template parameters. The given values
+// take precedence over the defaults. Unknown and missing required parameters are rejected.
+func resolveTemplateParams(t models.Template, given map[string]string) (map[string]string, error) {
+	declared := make(map[string]bool, len(t.Params))
+	values := make(map[string]string, len(t.Params))
+
+	for _, p := range t.Params {
+		declared[p.Name] = true
+
+		v, ok := given[p.Name]
+
+		switch {
+		case ok:
+			values[p.Name] = v
+		case p.Required:
+			return nil, errorz.NewValidationError("template parameter %s is required", p.Name)
+		default:
+			values[p.Name] = p.Default
+		}
+	}
+
+	for name := range given {
+		if !declared[name] {
+			return nil, errorz.NewValidationError("template %s has no parameter %s", t.ID, name)
+		}
+	}
+
+	return values, nil
+}
+
+// validateTemplateConnections checks that the connections refer to the ports of the template.
+func validateTemplateConnections(t models.Template, connections map[string]string) error {
+	ports := make(map[string]bool, len(t.Ports))
+
+	for _, p := range t.Ports {
+		ports[p.Name] = true
+	}
+
+	for port, nodeID := range connections {
+		if !ports[port] {
+			return errorz.NewValidationError("template %s has no port %s", t.ID, port)
+		}
+
+		if err := validateNodeID(nodeID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expandTemplate returns copies of the template nodes and relations with the placeholders
+// replaced by the parameter values.
+func expandTemplate(
+	t models.Template,
+	values map[string]string,
+) ([]models.TemplateNode, []models.TemplateRelation) {
+	nodes := make([]models.TemplateNode, len(t.Nodes))
+
+	for i, n := range t.Nodes {
+		nodes[i] = models.TemplateNode{
+			Key:   n.Key,
+			Kind:  n.Kind,
+			Code:  substitute(n.Code, values),
+			Props: substituteProps(n.Props, values),
+		}
+	}
+
+	relations := make([]models.TemplateRelation, len(t.Relations))
+
+	for i, r := range t.Relations {
+		relations[i] = models.TemplateRelation{
+			Kind:  r.Kind,
+			From:  r.From,
+			To:    r.To,
+			Props: substituteProps(r.Props, values),
+		}
+	}
+
+	return nodes, relations
+}
+
+// substitute replaces the placeholders in s with the parameter values.
+func substitute(s string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return values[m[2:len(m)-1]]
+	})
+}
+
+// substituteProps returns a deep copy of the property bag with the placeholders in
+// the string values replaced by the parameter values.
+func substituteProps(bag models.PropBag, values map[string]string) models.PropBag {
+	if bag == nil {
+		return nil
+	}
+
+	result := make(models.PropBag, len(bag))
+
+	for name, section := range bag {
+		copied := make(models.PropSection, len(section))
+
+		for k, v := range section {
+			copied[k] = substituteValue(v, values)
+		}
+
+		result[name] = copied
+	}
+
+	return result
+}
+
+// substituteValue replaces the placeholders in the string values, including nested ones.
+func substituteValue(v any, values map[string]string) any {
+	switch tv := v.(type) {
+	case string:
+		return substitute(tv, values)
+	case []any:
+		copied := make([]any, len(tv))
+
+		for i, e := range tv {
+			copied[i] = substituteValue(e, values)
+		}
+
+		return copied
+	case map[string]any:
+		copied := make(map[string]any, len(tv))
+
+		for k, e := range tv {
+			copied[k] = substituteValue(e, values)
+		}
+
+		return copied
+	}
+
+	return v
+}
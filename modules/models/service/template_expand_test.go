@@ -0,0 +1,128 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_placeholderNames(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"a", "b_2"}, placeholderNames("${a}-x-${b_2}"))
+	require.Empty(t, placeholderNames("$a {b} ${} ${1x}"))
+}
+
+func Test_propStrings(t *testing.T) {
+	t.Parallel()
+
+	bag := models.PropBag{
+		"s": {
+			"a": "x",
+			"b": 1,
+			"c": []any{"y", 2},
+			"d": map[string]any{"e": "z"},
+		},
+	}
+
+	require.ElementsMatch(t, []string{"x", "y", "z"}, propStrings(bag))
+}
+
+func Test_resolveTemplateParams(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		values, err := resolveTemplateParams(validTemplate, map[string]string{"name": "north"})
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"name": "north", "voltage": "10"}, values)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		values, err := resolveTemplateParams(validTemplate, map[string]string{"name": "north", "voltage": "20"})
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"name": "north", "voltage": "20"}, values)
+	})
+
+	t.Run("missing-required", func(t *testing.T) {
+		_, err := resolveTemplateParams(validTemplate, nil)
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := resolveTemplateParams(validTemplate, map[string]string{"name": "north", "x": "y"})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func Test_validateTemplateConnections(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateTemplateConnections(validTemplate, nil))
+	require.NoError(t, validateTemplateConnections(validTemplate, map[string]string{"feed": "1"}))
+	require.IsType(t, errorz.ValidationError{},
+		validateTemplateConnections(validTemplate, map[string]string{"unknown": "1"}))
+	require.IsType(t, errorz.ValidationError{},
+		validateTemplateConnections(validTemplate, map[string]string{"feed": ""}))
+}
+
+func Test_expandTemplate(t *testing.T) {
+	t.Parallel()
+
+	nodes, relations := expandTemplate(validTemplate, map[string]string{"name": "n", "voltage": "20"})
+
+	require.Equal(t, []models.TemplateNode{
+		{
+			Key:   "bus",
+			Kind:  "bus",
+			Code:  "n-bus",
+			Props: models.PropBag{"electrical": {"voltage": "20 kV", "phases": 3}},
+		},
+		{Key: "breaker", Kind: "breaker", Code: "n-cb"},
+	}, nodes)
+	require.Equal(t, validTemplate.Relations, relations)
+}
+
+func Test_substitute(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"a": "1", "b": "2"}
+
+	require.Equal(t, "1-2-1", substitute("${a}-${b}-${a}", values))
+	require.Equal(t, "x--", substitute("x-${c}-", values))
+	require.Equal(t, "no placeholders", substitute("no placeholders", values))
+}
+
+func Test_substituteProps(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"a": "1"}
+
+	bag := models.PropBag{
+		"s": {
+			"str":  "${a}",
+			"num":  2.5,
+			"list": []any{"${a}", true},
+			"map":  map[string]any{"k": "${a}"},
+		},
+	}
+
+	require.Nil(t, substituteProps(nil, values))
+	require.Equal(t, models.PropBag{
+		"s": {
+			"str":  "1",
+			"num":  2.5,
+			"list": []any{"1", true},
+			"map":  map[string]any{"k": "1"},
+		},
+	}, substituteProps(bag, values))
+
+	// the original bag is not modified
+	require.Equal(t, "${a}", bag["s"]["str"])
+	require.Equal(t, []any{"${a}", true}, bag["s"]["list"])
+}
@@ -0,0 +1,16 @@
+package service
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+func templateFromData(id string, data models.TemplateData) models.Template {
+	return models.Template{
+		ID:          id,
+		Code:        data.Code,
+		Name:        data.Name,
+		Description: data.Description,
+		Params:      data.Params,
+		Nodes:       data.Nodes,
+		Relations:   data.Relations,
+		Ports:       data.Ports,
+	}
+}
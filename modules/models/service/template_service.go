@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// templateStore defines the external template store.
+type templateStore interface {
+	CreateTemplate(ctx context.Context, template models.Template) error
+	UpdateTemplate(ctx context.Context, template models.Template) error
+	DeleteTemplate(ctx context.Context, id string) error
+	GetTemplate(ctx context.Context, id string) (models.Template, error)
+	GetTemplatesByIDs(ctx context.Context, ids []string) ([]models.Template, error)
+}
+
+// templateListener defines the external template event listener.
+type templateListener interface {
+	HandleTemplateEvent(ctx context.Context, event models.TemplateEvent) error
+}
+
+// meshEditor defines the external service used to write instantiated templates into meshes.
+// It is implemented by the mesh service.
+type meshEditor interface {
+	GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error)
+	InsertContents(ctx context.Context, actor access.Actor, modelID string, contents models.Mesh) (models.Mesh, error)
+}
+
+// TemplateService implements the template service.
+//
+// It implements the models.TemplateService interface.
+//
+// Templates are instantiated through the mesh service, so the instantiated nodes and
+// relations are validated, guarded and announced like any other mesh change.
+//
+// We do not wrap the errors returned by the store because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type TemplateService struct {
+	idGen    idGenerator
+	store    templateStore
+	meshes   meshEditor
+	listener templateListener
+	now      func() time.Time
+}
+
+// Ensure TemplateService implements the models.TemplateService interface.
+var _ models.TemplateService = (*TemplateService)(nil)
+
+// NewTemplateService creates a new template service.
+func NewTemplateService(
+	store templateStore,
+	idGen idGenerator,
+	meshes meshEditor,
+	opts ...TemplateServiceOption,
+) *TemplateService {
+	svc := &TemplateService{
+		idGen:  idGen,
+		store:  store,
+		meshes: meshes,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// CreateTemplate implements the models.TemplateService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) CreateTemplate(
+	ctx context.Context,
+	actor access.Actor,
+	data models.TemplateData,
+) (models.Template, error) {
+	if err := validateTemplateData(data); err != nil {
+		return models.Template{}, err
+	}
+
+	template := templateFromData(s.idGen.GenerateID(), data)
+
+	if err := s.store.CreateTemplate(ctx, template); err != nil {
+		return models.Template{}, err
+	}
+
+	if err := s.fireTemplateEvent(ctx, actor, models.TemplateCreated, template); err != nil {
+		return models.Template{}, err
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate implements the models.TemplateService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) UpdateTemplate(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	data models.TemplateData,
+) (models.Template, error) {
+	if err := validateTemplateID(id); err != nil {
+		return models.Template{}, err
+	}
+
+	if err := validateTemplateData(data); err != nil {
+		return models.Template{}, err
+	}
+
+	template := templateFromData(id, data)
+
+	if err := s.store.UpdateTemplate(ctx, template); err != nil {
+		return models.Template{}, err
+	}
+
+	if err := s.fireTemplateEvent(ctx, actor, models.TemplateUpdated, template); err != nil {
+		return models.Template{}, err
+	}
+
+	return template, nil
+}
+
+// DeleteTemplate implements the models.TemplateService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) DeleteTemplate(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+) error {
+	if err := validateTemplateID(id); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteTemplate(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.fireTemplateEvent(ctx, actor, models.TemplateDeleted, models.Template{ID: id}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTemplate implements the models.TemplateService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) GetTemplate(
+	ctx context.Context,
+	id string,
+) (models.Template, error) {
+	if err := validateTemplateID(id); err != nil {
+		return models.Template{}, err
+	}
+
+	template, err := s.store.GetTemplate(ctx, id)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	return template, nil
+}
+
+// GetTemplatesByIDs implements the models.TemplateService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) GetTemplatesByIDs(
+	ctx context.Context,
+	ids []string,
+) ([]models.Template, error) {
+	if len(ids) == 0 {
+		return []models.Template{}, nil
+	}
+
+	for _, id := range ids {
+		if err := validateTemplateID(id); err != nil {
+			return nil, err
+		}
+	}
+
+	found, err := s.store.GetTemplatesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// InstantiateTemplate implements the models.TemplateService interface.
+//
+// It adds the template nodes and relations to the mesh of the model with fresh IDs and
+// connects the ports to the given existing nodes, all in a single write, so a failed
+// instantiation leaves the mesh unchanged. It returns the created mesh fragment.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateService) InstantiateTemplate(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, templateID string,
+	params models.TemplateParams,
+) (models.Mesh, error) {
+	if err := validateModelID(modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	if err := validateTemplateID(templateID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	template, err := s.store.GetTemplate(ctx, templateID)
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	values, err := resolveTemplateParams(template, params.Values)
+	if err != nil {
+		return models.Mesh{}, err
+	}
+
+	if err := validateTemplateConnections(template, params.Connections); err != nil {
+		return models.Mesh{}, err
+	}
+
+	for _, nodeID := range params.Connections {
+		if _, err := s.meshes.GetNode(ctx, modelID, nodeID); err != nil {
+			return models.Mesh{}, err
+		}
+	}
+
+	fragment := s.buildFragment(modelID, template, values, params.Connections)
+
+	return s.meshes.InsertContents(ctx, actor, modelID, fragment)
+}
+
+// buildFragment builds the expanded template nodes and relations and the port connections
+// with fresh IDs.
+func (s *TemplateService) buildFragment(
+	modelID string,
+	template models.Template,
+	values map[string]string,
+	connections map[string]string,
+) models.Mesh {
+	nodes, relations := expandTemplate(template, values)
+	ids := make(map[string]string, len(nodes))
+
+	fragment := models.Mesh{
+		ModelID:   modelID,
+		Nodes:     make(map[string]models.Node, len(nodes)),
+		Relations: make(map[string]models.Relation, len(relations)+len(connections)),
+	}
+
+	for _, n := range nodes {
+		node := nodeFromData(s.idGen.GenerateID(), models.NodeData{
+			Kind:  n.Kind,
+			Code:  n.Code,
+			Props: n.Props,
+		})
+
+		ids[n.Key] = node.ID
+		fragment.Nodes[node.ID] = node
+	}
+
+	for _, r := range relations {
+		relation := relationFromData(s.idGen.GenerateID(), models.RelationData{
+			Kind:  r.Kind,
+			From:  ids[r.From],
+			To:    ids[r.To],
+			Props: r.Props,
+		})
+
+		fragment.Relations[relation.ID] = relation
+	}
+
+	for _, p := range template.Ports {
+		nodeID, ok := connections[p.Name]
+		if !ok {
+			continue
+		}
+
+		relation := relationFromData(s.idGen.GenerateID(), models.RelationData{
+			Kind: p.Kind,
+			From: ids[p.Node],
+			To:   nodeID,
+		})
+
+		fragment.Relations[relation.ID] = relation
+	}
+
+	return fragment
+}
+
+// fireTemplateEvent fires a template event.
+func (s *TemplateService) fireTemplateEvent(
+	ctx context.Context,
+	actor access.Actor,
+	eventType models.EventType,
+	template models.Template,
+) error {
+	if s.listener == nil {
+		return nil
+	}
+
+	event := models.TemplateEvent{
+		EventHeader: models.EventHeader{
+			Type:      eventType,
+			Actor:     actor,
+			Timestamp: s.now(),
+		},
+		Template: template,
+	}
+
+	if err := s.listener.HandleTemplateEvent(ctx, event); err != nil {
+		return errorz.NewInternalError("%s event handler failed: %v", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package service
+
+// TemplateServiceOption defines the option for the template service.
+type TemplateServiceOption func(*TemplateService)
+
+// WithTemplateListener sets the listener for the service.
+func WithTemplateListener(listener templateListener) TemplateServiceOption {
+	return func(s *TemplateService) {
+		s.listener = listener
+	}
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data          models.TemplateData
+		storeError    bool
+		listenerError bool
+		wantEvent     models.EventType
+		wantErr       error
+	}{
+		"invalid-data": {
+			data:    models.TemplateData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			data:       validTemplateData,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			data:          validTemplateData,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			data:      validTemplateData,
+			wantEvent: models.TemplateCreated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestTemplateStore(t, test.storeError)
+			tl := newTestTemplateListener(test.listenerError)
+
+			svc := NewTemplateService(ts, newTestIDGenerator(), newTestMeshEditor(false), WithTemplateListener(tl))
+
+			template, err := svc.CreateTemplate(context.Background(), adminActor, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, template)
+			} else {
+				require.NoError(t, err)
+				require.NotEmpty(t, template.ID)
+			}
+
+			if test.wantEvent != "" {
+				requireTemplateEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id            string
+		data          models.TemplateData
+		storeError    bool
+		listenerError bool
+		wantEvent     models.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			id:      "",
+			data:    validTemplateData,
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-data": {
+			id:      validTemplateID,
+			data:    models.TemplateData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			id:         validTemplateID,
+			data:       validTemplateData,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			id:            validTemplateID,
+			data:          validTemplateData,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			id:        validTemplateID,
+			data:      validTemplateData,
+			wantEvent: models.TemplateUpdated,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestTemplateStore(t, test.storeError)
+			tl := newTestTemplateListener(test.listenerError)
+
+			svc := NewTemplateService(ts, newTestIDGenerator(), newTestMeshEditor(false), WithTemplateListener(tl))
+
+			template, err := svc.UpdateTemplate(context.Background(), adminActor, test.id, test.data)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, template)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validTemplate, template)
+			}
+
+			if test.wantEvent != "" {
+				requireTemplateEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestTemplateService_DeleteTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id            string
+		storeError    bool
+		listenerError bool
+		wantEvent     models.EventType
+		wantErr       error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			id:         validTemplateID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"listener-error": {
+			id:            validTemplateID,
+			listenerError: true,
+			wantErr:       errorz.InternalError{},
+		},
+		"success": {
+			id:        validTemplateID,
+			wantEvent: models.TemplateDeleted,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := newTestTemplateStore(t, test.storeError)
+			tl := newTestTemplateListener(test.listenerError)
+
+			svc := NewTemplateService(ts, newTestIDGenerator(), newTestMeshEditor(false), WithTemplateListener(tl))
+
+			err := svc.DeleteTemplate(context.Background(), adminActor, test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if test.wantEvent != "" {
+				requireTemplateEventFired(t, test.wantEvent, tl)
+			} else {
+				require.Empty(t, tl.eventFired)
+			}
+		})
+	}
+}
+
+func TestTemplateService_GetTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id         string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"not-found": {
+			id:      "missing",
+			wantErr: errorz.NotFoundError{},
+		},
+		"store-error": {
+			id:         validTemplateID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			id: validTemplateID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewTemplateService(newTestTemplateStore(t, test.storeError), newTestIDGenerator(),
+				newTestMeshEditor(false))
+
+			template, err := svc.GetTemplate(context.Background(), test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, template)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validTemplate, template)
+			}
+		})
+	}
+}
+
+func TestTemplateService_GetTemplatesByIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ids        []string
+		storeError bool
+		want       []models.Template
+		wantErr    error
+	}{
+		"empty": {
+			ids:  nil,
+			want: []models.Template{},
+		},
+		"invalid-id": {
+			ids:     []string{""},
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			ids:        []string{validTemplateID},
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			ids:  []string{validTemplateID},
+			want: []models.Template{validTemplate},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewTemplateService(newTestTemplateStore(t, test.storeError), newTestIDGenerator(),
+				newTestMeshEditor(false))
+
+			templates, err := svc.GetTemplatesByIDs(context.Background(), test.ids)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.want, templates)
+			}
+		})
+	}
+}
+
+func TestTemplateService_InstantiateTemplate(t *testing.T) {
+	t.Parallel()
+
+	validParams := models.TemplateParams{
+		Values:      map[string]string{"name": "north"},
+		Connections: map[string]string{"feed": existingNodeID},
+	}
+
+	tests := map[string]struct {
+		modelID    string
+		templateID string
+		params     models.TemplateParams
+		storeError bool
+		insertErr  bool
+		wantErr    error
+	}{
+		"invalid-model-id": {
+			modelID:    "",
+			templateID: validTemplateID,
+			params:     validParams,
+			wantErr:    errorz.ValidationError{},
+		},
+		"invalid-template-id": {
+			modelID:    validModelID,
+			templateID: "",
+			params:     validParams,
+			wantErr:    errorz.ValidationError{},
+		},
+		"template-not-found": {
+			modelID:    validModelID,
+			templateID: "missing",
+			params:     validParams,
+			wantErr:    errorz.NotFoundError{},
+		},
+		"store-error": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params:     validParams,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"missing-param": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params:     models.TemplateParams{},
+			wantErr:    errorz.ValidationError{},
+		},
+		"unknown-port": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params: models.TemplateParams{
+				Values:      validParams.Values,
+				Connections: map[string]string{"unknown": existingNodeID},
+			},
+			wantErr: errorz.ValidationError{},
+		},
+		"missing-connected-node": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params: models.TemplateParams{
+				Values:      validParams.Values,
+				Connections: map[string]string{"feed": "missing"},
+			},
+			wantErr: errorz.NotFoundError{},
+		},
+		"insert-error": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params:     validParams,
+			insertErr:  true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			modelID:    validModelID,
+			templateID: validTemplateID,
+			params:     validParams,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			editor := newTestMeshEditor(test.insertErr)
+
+			svc := NewTemplateService(newTestTemplateStore(t, test.storeError), newTestIDGenerator(), editor)
+
+			fragment, err := svc.InstantiateTemplate(context.Background(), adminActor,
+				test.modelID, test.templateID, test.params)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, fragment)
+
+				// nothing is written to the mesh
+				require.Len(t, editor.mesh.Nodes, 1)
+				require.Empty(t, editor.mesh.Relations)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, 1, editor.inserts)
+			require.Len(t, fragment.Nodes, 2)
+			require.Len(t, fragment.Relations, 2)
+			require.Equal(t, editor.mesh.Relations, fragment.Relations)
+
+			byCode := make(map[string]models.Node)
+
+			for id, node := range fragment.Nodes {
+				require.Equal(t, editor.mesh.Nodes[id], node)
+
+				byCode[node.Code] = node
+			}
+
+			bus, breaker := byCode["north-bus"], byCode["north-cb"]
+
+			require.Equal(t, "bus", bus.Kind)
+			require.Equal(t, models.PropBag{"electrical": {"voltage": "10 kV", "phases": 3}}, bus.Props)
+			require.Equal(t, "breaker", breaker.Kind)
+
+			ends := make(map[string][2]string)
+
+			for _, r := range fragment.Relations {
+				ends[r.Kind] = [2]string{r.From, r.To}
+			}
+
+			require.Equal(t, map[string][2]string{
+				"connects": {bus.ID, breaker.ID},
+				"line":     {breaker.ID, existingNodeID},
+			}, ends)
+
+			// the template itself is not modified by the instantiation
+			require.Equal(t, "${voltage} kV", validTemplate.Nodes[0].Props["electrical"]["voltage"])
+		})
+	}
+
+	t.Run("without-connections", func(t *testing.T) {
+		editor := newTestMeshEditor(false)
+
+		svc := NewTemplateService(newTestTemplateStore(t, false), newTestIDGenerator(), editor)
+
+		fragment, err := svc.InstantiateTemplate(context.Background(), access.Actor{}, validModelID,
+			validTemplateID, models.TemplateParams{Values: map[string]string{"name": "south", "voltage": "20"}})
+
+		require.NoError(t, err)
+		require.Len(t, fragment.Nodes, 2)
+		require.Len(t, fragment.Relations, 1)
+	})
+}
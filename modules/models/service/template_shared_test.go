@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	validTemplateID   = "t1"
+	validTemplateData = models.TemplateData{
+		Code: "substation",
+		Name: "Substation",
+		Params: []models.TemplateParam{
+			{Name: "name", Required: true},
+			{Name: "voltage", Default: "10"},
+		},
+		Nodes: []models.TemplateNode{
+			{
+				Key:   "bus",
+				Kind:  "bus",
+				Code:  "${name}-bus",
+				Props: models.PropBag{"electrical": {"voltage": "${voltage} kV", "phases": 3}},
+			},
+			{Key: "breaker", Kind: "breaker", Code: "${name}-cb"},
+		},
+		Relations: []models.TemplateRelation{
+			{Kind: "connects", From: "bus", To: "breaker"},
+		},
+		Ports: []models.TemplatePort{
+			{Name: "feed", Node: "breaker", Kind: "line"},
+		},
+	}
+	validTemplate  = templateFromData(validTemplateID, validTemplateData)
+	existingNodeID = "existing"
+)
+
+type testTemplateListener struct {
+	forcedError error
+	eventFired  models.TemplateEvent
+}
+
+// Ensure that the testTemplateListener implements the templateListener interface.
+var _ templateListener = (*testTemplateListener)(nil)
+
+func newTestTemplateListener(forcedError bool) *testTemplateListener {
+	var err error
+
+	if forcedError {
+		err = errors.New("forced-error")
+	}
+
+	return &testTemplateListener{
+		forcedError: err,
+	}
+}
+
+func (l *testTemplateListener) HandleTemplateEvent(_ context.Context, event models.TemplateEvent) error {
+	if l.forcedError != nil {
+		return l.forcedError
+	}
+
+	l.eventFired = event
+
+	return nil
+}
+
+type testTemplateStore struct {
+	t           *testing.T
+	forcedError error
+}
+
+// Ensure that the testTemplateStore implements the templateStore interface.
+var _ templateStore = (*testTemplateStore)(nil)
+
+func newTestTemplateStore(t *testing.T, forcedError bool) *testTemplateStore {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testTemplateStore{
+		t:           t,
+		forcedError: err,
+	}
+}
+
+func (s *testTemplateStore) CreateTemplate(_ context.Context, template models.Template) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.NotEmpty(s.t, template.ID)
+	require.Equal(s.t, validTemplateData, templateData(template))
+
+	return nil
+}
+
+func (s *testTemplateStore) UpdateTemplate(_ context.Context, template models.Template) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.Equal(s.t, validTemplate, template)
+
+	return nil
+}
+
+func (s *testTemplateStore) DeleteTemplate(_ context.Context, id string) error {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return s.forcedError
+	}
+
+	require.NotEmpty(s.t, id)
+
+	return nil
+}
+
+func (s *testTemplateStore) GetTemplate(_ context.Context, id string) (models.Template, error) {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return models.Template{}, s.forcedError
+	}
+
+	if id == validTemplateID {
+		return validTemplate, nil
+	}
+
+	return models.Template{}, errorz.NewNotFoundError("template %v not found", id)
+}
+
+func (s *testTemplateStore) GetTemplatesByIDs(_ context.Context, ids []string) ([]models.Template, error) {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	require.NotEmpty(s.t, ids)
+
+	return []models.Template{validTemplate}, nil
+}
+
+// templateData converts the template back to the data it was created from.
+func templateData(t models.Template) models.TemplateData {
+	return models.TemplateData{
+		Code:        t.Code,
+		Name:        t.Name,
+		Description: t.Description,
+		Params:      t.Params,
+		Nodes:       t.Nodes,
+		Relations:   t.Relations,
+		Ports:       t.Ports,
+	}
+}
+
+// testMeshEditor keeps the nodes and relations written by the template service in memory.
+// It fails the insert call if forced to.
+type testMeshEditor struct {
+	mesh      models.Mesh
+	forceFail bool
+	inserts   int
+}
+
+// Ensure that the testMeshEditor implements the meshEditor interface.
+var _ meshEditor = (*testMeshEditor)(nil)
+
+func newTestMeshEditor(forceFail bool) *testMeshEditor {
+	return &testMeshEditor{
+		mesh: models.Mesh{
+			ModelID:   validModelID,
+			Nodes:     map[string]models.Node{existingNodeID: {ID: existingNodeID, Kind: "bus"}},
+			Relations: map[string]models.Relation{},
+		},
+		forceFail: forceFail,
+	}
+}
+
+func (e *testMeshEditor) GetNode(_ context.Context, _, nodeID string) (models.Node, error) {
+	if node, ok := e.mesh.Nodes[nodeID]; ok {
+		return node, nil
+	}
+
+	return models.Node{}, errorz.NewNotFoundError("node %v not found", nodeID)
+}
+
+func (e *testMeshEditor) InsertContents(
+	_ context.Context,
+	_ access.Actor,
+	_ string,
+	contents models.Mesh,
+) (models.Mesh, error) {
+	e.inserts++
+
+	if e.forceFail {
+		return models.Mesh{}, errorz.NewStoreError("forced-error")
+	}
+
+	for id, node := range contents.Nodes {
+		e.mesh.Nodes[id] = node
+	}
+
+	for id, relation := range contents.Relations {
+		e.mesh.Relations[id] = relation
+	}
+
+	return contents, nil
+}
+
+func requireTemplateEventFired(t *testing.T, wantEvent models.EventType, listener *testTemplateListener) {
+	t.Helper()
+
+	require.NotEmpty(t, listener.eventFired)
+	require.Equal(t, wantEvent, listener.eventFired.Type)
+	require.NotEmpty(t, listener.eventFired.Actor)
+	require.NotEmpty(t, listener.eventFired.Template)
+	require.NotEmpty(t, listener.eventFired.Timestamp)
+}
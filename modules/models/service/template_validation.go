@@ -0,0 +1,168 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// templateParamPattern matches valid template parameter names.
+var templateParamPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`) //nolint:gochecknoglobals
+
+func validateTemplateID(id string) error {
+	return requireString(id, "template id")
+}
+
+func validateTemplateData(data models.TemplateData) error {
+	if err := validateCode(data.Code); err != nil {
+		return err
+	}
+
+	if err := validateName(data.Name); err != nil {
+		return err
+	}
+
+	params, err := validateTemplateParams(data.Params)
+	if err != nil {
+		return err
+	}
+
+	keys, err := validateTemplateNodes(data.Nodes, params)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTemplateRelations(data.Relations, keys, params); err != nil {
+		return err
+	}
+
+	if err := validateTemplatePorts(data.Ports, keys); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTemplateParams validates the parameters and returns the set of their names.
+func validateTemplateParams(params []models.TemplateParam) (map[string]bool, error) {
+	names := make(map[string]bool, len(params))
+
+	for _, p := range params {
+		if !templateParamPattern.MatchString(p.Name) {
+			return nil, errorz.NewValidationError("invalid template parameter name %q", p.Name)
+		}
+
+		if names[p.Name] {
+			return nil, errorz.NewValidationError("duplicate template parameter %s", p.Name)
+		}
+
+		names[p.Name] = true
+	}
+
+	return names, nil
+}
+
+// validateTemplateNodes validates the nodes and returns the set of their keys.
+func validateTemplateNodes(nodes []models.TemplateNode, params map[string]bool) (map[string]bool, error) {
+	keys := make(map[string]bool, len(nodes))
+
+	for _, n := range nodes {
+		if err := requireString(n.Key, "template node key"); err != nil {
+			return nil, err
+		}
+
+		if keys[n.Key] {
+			return nil, errorz.NewValidationError("duplicate template node key %s", n.Key)
+		}
+
+		keys[n.Key] = true
+
+		if err := validateKind(n.Kind); err != nil {
+			return nil, err
+		}
+
+		if err := validatePropBag(n.Props); err != nil {
+			return nil, err
+		}
+
+		if err := validatePlaceholders(params, n.Code); err != nil {
+			return nil, err
+		}
+
+		if err := validatePlaceholders(params, propStrings(n.Props)...); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func validateTemplateRelations(
+	relations []models.TemplateRelation,
+	keys map[string]bool,
+	params map[string]bool,
+) error {
+	for _, r := range relations {
+		if err := validateKind(r.Kind); err != nil {
+			return err
+		}
+
+		if !keys[r.From] {
+			return errorz.NewValidationError("relation source node %q is not a template node", r.From)
+		}
+
+		if !keys[r.To] {
+			return errorz.NewValidationError("relation target node %q is not a template node", r.To)
+		}
+
+		if err := validatePropBag(r.Props); err != nil {
+			return err
+		}
+
+		if err := validatePlaceholders(params, propStrings(r.Props)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateTemplatePorts(ports []models.TemplatePort, keys map[string]bool) error {
+	names := make(map[string]bool, len(ports))
+
+	for _, p := range ports {
+		if err := requireString(p.Name, "template port name"); err != nil {
+			return err
+		}
+
+		if names[p.Name] {
+			return errorz.NewValidationError("duplicate template port %s", p.Name)
+		}
+
+		names[p.Name] = true
+
+		if !keys[p.Node] {
+			return errorz.NewValidationError("port %s node %q is not a template node", p.Name, p.Node)
+		}
+
+		if err := validateKind(p.Kind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePlaceholders checks that the placeholders in the values refer to declared parameters.
+func validatePlaceholders(params map[string]bool, values ...string) error {
+	for _, v := range values {
+		for _, name := range placeholderNames(v) {
+			if !params[name] {
+				return errorz.NewValidationError("placeholder ${%s} refers to an undeclared parameter", name)
+			}
+		}
+	}
+
+	return nil
+}
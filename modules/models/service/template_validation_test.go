@@ -0,0 +1,134 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateTemplateID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateTemplateID("1"))
+	require.Error(t, validateTemplateID(""))
+}
+
+func Test_validateTemplateData(t *testing.T) {
+	t.Parallel()
+
+	modify := func(f func(d *models.TemplateData)) models.TemplateData {
+		d := validTemplateData
+		d.Params = append([]models.TemplateParam(nil), d.Params...)
+		d.Nodes = append([]models.TemplateNode(nil), d.Nodes...)
+		d.Relations = append([]models.TemplateRelation(nil), d.Relations...)
+		d.Ports = append([]models.TemplatePort(nil), d.Ports...)
+
+		f(&d)
+
+		return d
+	}
+
+	tests := map[string]struct {
+		data    models.TemplateData
+		wantErr bool
+	}{
+		"valid": {
+			data: validTemplateData,
+		},
+		"minimal": {
+			data: models.TemplateData{Code: "code", Name: "name"},
+		},
+		"missing-code": {
+			data:    modify(func(d *models.TemplateData) { d.Code = "" }),
+			wantErr: true,
+		},
+		"missing-name": {
+			data:    modify(func(d *models.TemplateData) { d.Name = "" }),
+			wantErr: true,
+		},
+		"invalid-param-name": {
+			data:    modify(func(d *models.TemplateData) { d.Params[0].Name = "1st" }),
+			wantErr: true,
+		},
+		"duplicate-param": {
+			data:    modify(func(d *models.TemplateData) { d.Params[1].Name = d.Params[0].Name }),
+			wantErr: true,
+		},
+		"missing-node-key": {
+			data:    modify(func(d *models.TemplateData) { d.Nodes[0].Key = "" }),
+			wantErr: true,
+		},
+		"duplicate-node-key": {
+			data:    modify(func(d *models.TemplateData) { d.Nodes[1].Key = d.Nodes[0].Key }),
+			wantErr: true,
+		},
+		"missing-node-kind": {
+			data:    modify(func(d *models.TemplateData) { d.Nodes[0].Kind = "" }),
+			wantErr: true,
+		},
+		"undeclared-code-param": {
+			data:    modify(func(d *models.TemplateData) { d.Nodes[1].Code = "${unknown}" }),
+			wantErr: true,
+		},
+		"undeclared-prop-param": {
+			data: modify(func(d *models.TemplateData) {
+				d.Nodes[1].Props = models.PropBag{"s": {"k": []any{"${unknown}"}}}
+			}),
+			wantErr: true,
+		},
+		"invalid-node-props": {
+			data:    modify(func(d *models.TemplateData) { d.Nodes[1].Props = models.PropBag{"": {}} }),
+			wantErr: true,
+		},
+		"missing-relation-kind": {
+			data:    modify(func(d *models.TemplateData) { d.Relations[0].Kind = "" }),
+			wantErr: true,
+		},
+		"unknown-relation-source": {
+			data:    modify(func(d *models.TemplateData) { d.Relations[0].From = "unknown" }),
+			wantErr: true,
+		},
+		"unknown-relation-target": {
+			data:    modify(func(d *models.TemplateData) { d.Relations[0].To = "unknown" }),
+			wantErr: true,
+		},
+		"undeclared-relation-param": {
+			data: modify(func(d *models.TemplateData) {
+				d.Relations[0].Props = models.PropBag{"s": {"k": "${unknown}"}}
+			}),
+			wantErr: true,
+		},
+		"missing-port-name": {
+			data:    modify(func(d *models.TemplateData) { d.Ports[0].Name = "" }),
+			wantErr: true,
+		},
+		"duplicate-port": {
+			data: modify(func(d *models.TemplateData) {
+				d.Ports = append(d.Ports, d.Ports[0])
+			}),
+			wantErr: true,
+		},
+		"unknown-port-node": {
+			data:    modify(func(d *models.TemplateData) { d.Ports[0].Node = "unknown" }),
+			wantErr: true,
+		},
+		"missing-port-kind": {
+			data:    modify(func(d *models.TemplateData) { d.Ports[0].Kind = "" }),
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateTemplateData(test.data)
+
+			if test.wantErr {
+				require.IsType(t, errorz.ValidationError{}, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import "strconv"
+
+// Direction defines the direction in which relations are followed when traversing a mesh.
+type Direction int
+
+// Direction enumeration.
+const (
+	DirectionBoth Direction = iota
+	DirectionOutgoing
+	DirectionIncoming
+)
+
+// AllDirections is a list of all directions. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllDirections = []Direction{
+	DirectionBoth,
+	DirectionOutgoing,
+	DirectionIncoming,
+}
+
+// String returns the string representation of the direction.
+func (d Direction) String() string {
+	switch d {
+	case DirectionBoth:
+		return "both"
+	case DirectionOutgoing:
+		return "outgoing"
+	case DirectionIncoming:
+		return "incoming"
+	}
+
+	return "Direction(" + strconv.Itoa(int(d)) + ")"
+}
+
+// SliceSpec defines which part of a mesh is extracted as a submesh.
+//
+// The slice contains the union of the neighborhood of the roots and the contents of the
+// container. If neither is given, the slice starts with all nodes of the mesh. The result is
+// then narrowed down to the given node kinds. The relations between the selected nodes are
+// always kept; the relations leaving the slice are only kept if KeepBoundary is set.
+type SliceSpec struct {
+	Roots            []string  // public IDs of the nodes to start the neighborhood from
+	Hops             int       // max number of relations between a root and a selected node
+	Direction        Direction // direction in which relations are followed from the roots
+	Container        string    // public ID of the container node (optional)
+	ContainmentKinds []string  // relation kinds leading from a container to its contents
+	NodeKinds        []string  // node kinds to keep (optional, all kinds if empty)
+	KeepBoundary     bool      // keep the relations to nodes outside the slice, marked as external
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirection_String(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range AllDirections {
+		t.Run(d.String(), func(t *testing.T) {
+			require.NotEmpty(t, d.String())
+			require.False(t, strings.HasPrefix(d.String(), "Direction("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		d := Direction(100)
+
+		require.Equal(t, "Direction(100)", d.String())
+	})
+}
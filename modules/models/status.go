@@ -0,0 +1,60 @@
+package models
+
+import "strconv"
+
+// ModelStatus represents the lifecycle status of a model.
+//
+// A model starts as a draft, gets published and eventually gets archived.
+// Only draft models can be edited.
+type ModelStatus int
+
+// ModelStatus enumeration.
+const (
+	ModelStatusDraft ModelStatus = iota
+	ModelStatusPublished
+	ModelStatusArchived
+)
+
+// AllModelStatuses is a list of all model statuses. Used for testing purposes to validate that all
+// enum values are covered.
+//
+//nolint:gochecknoglobals
+var AllModelStatuses = []ModelStatus{
+	ModelStatusDraft,
+	ModelStatusPublished,
+	ModelStatusArchived,
+}
+
+// String returns the string representation of the model status.
+func (s ModelStatus) String() string {
+	switch s {
+	case ModelStatusDraft:
+		return "draft"
+	case ModelStatusPublished:
+		return "published"
+	case ModelStatusArchived:
+		return "archived"
+	}
+
+	return "ModelStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// IsEditable checks if the model and its mesh can be changed in this status.
+func (s ModelStatus) IsEditable() bool {
+	return s == ModelStatusDraft
+}
+
+// CanTransitionTo checks if the model can move from this status to the given one.
+// Drafts can be published or archived, published models can only be archived.
+func (s ModelStatus) CanTransitionTo(next ModelStatus) bool {
+	switch s {
+	case ModelStatusDraft:
+		return next == ModelStatusPublished || next == ModelStatusArchived
+	case ModelStatusPublished:
+		return next == ModelStatusArchived
+	case ModelStatusArchived:
+		return false
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelStatus_String(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range AllModelStatuses {
+		t.Run(s.String(), func(t *testing.T) {
+			require.NotEmpty(t, s.String())
+			require.False(t, strings.HasPrefix(s.String(), "ModelStatus("))
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		s := ModelStatus(100)
+
+		require.Equal(t, "ModelStatus(100)", s.String())
+	})
+}
+
+func TestModelStatus_IsEditable(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, ModelStatusDraft.IsEditable())
+	require.False(t, ModelStatusPublished.IsEditable())
+	require.False(t, ModelStatusArchived.IsEditable())
+}
+
+func TestModelStatus_CanTransitionTo(t *testing.T) {
+	t.Parallel()
+
+	allowed := map[ModelStatus][]ModelStatus{
+		ModelStatusDraft:     {ModelStatusPublished, ModelStatusArchived},
+		ModelStatusPublished: {ModelStatusArchived},
+	}
+
+	for _, from := range append(AllModelStatuses, ModelStatus(100)) {
+		for _, to := range AllModelStatuses {
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				want := false
+
+				for _, s := range allowed[from] {
+					want = want || s == to
+				}
+
+				require.Equal(t, want, from.CanTransitionTo(to))
+			})
+		}
+	}
+}
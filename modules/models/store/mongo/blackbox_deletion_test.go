@@ -0,0 +1,36 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	mongodrv "go.mongodb.org/mongo-driver/mongo"
+)
+
+func withDeletionStore(t *testing.T, f func(*testing.T, context.Context, *mongo.DeletionStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewDeletionStore(db)
+
+	f(t, ctx, store)
+}
+
+func withTransactor(t *testing.T, f func(*testing.T, context.Context, *mongodrv.Database, *mongo.Transactor)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	f(t, ctx, db, mongo.NewTransactor(db))
+}
@@ -20,6 +20,16 @@ func testMesh() models.Mesh {
 	}
 }
 
+func duplicateCodeMesh() models.Mesh {
+	mesh := testMesh()
+
+	n := testNode()
+	n.ID = "2"
+	mesh.Nodes[n.ID] = n
+
+	return mesh
+}
+
 func testNode() models.Node {
 	return models.Node{
 		ID:   "1",
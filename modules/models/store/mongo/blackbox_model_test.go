@@ -15,6 +15,7 @@ func testModel() models.Model {
 		Code:        "code1",
 		Name:        "model1",
 		Description: "description1",
+		Labels:      map[string]string{"region": "north", "purpose": "planning"},
 	}
 }
 
@@ -24,6 +25,7 @@ func testModel2() models.Model {
 		Code:        "code2",
 		Name:        "model2",
 		Description: "description2",
+		Labels:      map[string]string{"region": "south"},
 	}
 }
 
@@ -0,0 +1,39 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfilePoints returns hourly points starting at the given hour of 2024-01-01 UTC.
+func testProfilePoints(startHour int, values ...float64) []models.ProfilePoint {
+	start := time.Date(2024, 1, 1, startHour, 0, 0, 0, time.UTC)
+	points := make([]models.ProfilePoint, len(values))
+
+	for i, v := range values {
+		points[i] = models.ProfilePoint{Time: start.Add(time.Duration(i) * time.Hour), Value: v}
+	}
+
+	return points
+}
+
+func withProfileStore(t *testing.T, f func(*testing.T, context.Context, *mongo.ProfileStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewProfileStore(db)
+
+	require.NoError(t, store.EnsureCollection(ctx))
+
+	f(t, ctx, store)
+}
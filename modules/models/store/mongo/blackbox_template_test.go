@@ -0,0 +1,48 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+)
+
+func testTemplate() models.Template {
+	return models.Template{
+		ID:          "1",
+		Code:        "substation",
+		Name:        "Substation",
+		Description: "standard substation layout",
+		Params:      []models.TemplateParam{{Name: "name", Required: true}},
+		Nodes: []models.TemplateNode{
+			{Key: "bus", Kind: "bus", Code: "${name}-bus", Props: models.PropBag{"s": {"k": "${name}"}}},
+			{Key: "breaker", Kind: "breaker", Code: "${name}-cb"},
+		},
+		Relations: []models.TemplateRelation{{Kind: "connects", From: "bus", To: "breaker"}},
+		Ports:     []models.TemplatePort{{Name: "feed", Node: "breaker", Kind: "line"}},
+	}
+}
+
+func testTemplate2() models.Template {
+	return models.Template{
+		ID:   "2",
+		Code: "feeder",
+		Name: "Feeder",
+	}
+}
+
+func withTemplateStore(t *testing.T, f func(*testing.T, context.Context, *mongo.TemplateStore)) {
+	t.Helper()
+
+	db, closer := mongoEnv.NewInstance()
+	defer closer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	store := mongo.NewTemplateStore(db)
+
+	f(t, ctx, store)
+}
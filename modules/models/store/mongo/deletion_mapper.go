@@ -0,0 +1,23 @@
+package mongo
+
+import (
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+func toStoreDeletion(d models.Deletion) storeDeletion {
+	return storeDeletion{
+		ModelID:     d.ModelID,
+		ActorUserID: d.Actor.UserID,
+		ActorRole:   d.Actor.Role,
+		StartedAt:   d.StartedAt,
+	}
+}
+
+func fromStoreDeletion(d storeDeletion) models.Deletion {
+	return models.Deletion{
+		ModelID:   d.ModelID,
+		Actor:     access.NewActor(d.ActorUserID, d.ActorRole),
+		StartedAt: d.StartedAt,
+	}
+}
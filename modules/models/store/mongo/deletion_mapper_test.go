@@ -0,0 +1,14 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_deletionMappers(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreDeletion, toStoreDeletion(validDeletionModel))
+	require.Equal(t, validDeletionModel, fromStoreDeletion(validStoreDeletion))
+}
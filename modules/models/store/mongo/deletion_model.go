@@ -0,0 +1,15 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+)
+
+// storeDeletion models a pending model deletion in the MongoDB store.
+type storeDeletion struct {
+	ModelID     string      `bson:"modelId"`
+	ActorUserID string      `bson:"actorUserId"`
+	ActorRole   access.Role `bson:"actorRole"`
+	StartedAt   time.Time   `bson:"startedAt"`
+}
@@ -0,0 +1,22 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var (
+	validDeletionModel = models.Deletion{
+		ModelID:   "model-id",
+		Actor:     access.NewActor("user-id", access.RoleAdmin),
+		StartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	validStoreDeletion = storeDeletion{
+		ModelID:     validDeletionModel.ModelID,
+		ActorUserID: validDeletionModel.Actor.UserID,
+		ActorRole:   validDeletionModel.Actor.Role,
+		StartedAt:   validDeletionModel.StartedAt,
+	}
+)
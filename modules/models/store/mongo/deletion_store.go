@@ -0,0 +1,58 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	collDeletions = "model_deletions"
+	deletionKey   = "modelId"
+)
+
+// DeletionStore is a MongoDB journal of pending model deletions.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type DeletionStore struct {
+	deletions *mongo.Collection
+}
+
+// NewDeletionStore creates a new MongoDB deletion store.
+func NewDeletionStore(db *mongo.Database) *DeletionStore {
+	return &DeletionStore{
+		deletions: db.Collection(collDeletions),
+	}
+}
+
+// SaveDeletion implements the deletion journal interface.
+// It replaces the pending deletion of the same model, if any.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *DeletionStore) SaveDeletion(ctx context.Context, deletion models.Deletion) error {
+	return q.UpdateOne(s.deletions, toStoreDeletion).Key(deletionKey).Upsert().
+		Exec(ctx, deletion.ModelID, deletion)
+}
+
+// DeleteDeletion implements the deletion journal interface.
+// It succeeds if there is no pending deletion of the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *DeletionStore) DeleteDeletion(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.deletions).Exec(ctx, q.Filter{}.EQ(deletionKey, modelID))
+
+	return err
+}
+
+// GetDeletions implements the deletion journal interface.
+// The deletions are returned in the order they were started.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *DeletionStore) GetDeletions(ctx context.Context) ([]models.Deletion, error) {
+	return q.FindMany(s.deletions, fromStoreDeletion).
+		WithSort(fieldStartedAt, false).
+		Exec(ctx, q.Filter{})
+}
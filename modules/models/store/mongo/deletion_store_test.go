@@ -0,0 +1,57 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeletion(modelID string, startedAt time.Time) models.Deletion {
+	return models.Deletion{
+		ModelID:   modelID,
+		Actor:     access.NewActor("user1", access.RoleAdmin),
+		StartedAt: startedAt,
+	}
+}
+
+func TestDeletionStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("save-and-get", func(t *testing.T) {
+		withDeletionStore(t, func(t *testing.T, ctx context.Context, store *mongo.DeletionStore) {
+			started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			deletion1 := testDeletion("1", started.Add(time.Minute))
+			deletion2 := testDeletion("2", started)
+
+			require.NoError(t, store.SaveDeletion(ctx, deletion1))
+			require.NoError(t, store.SaveDeletion(ctx, deletion2))
+
+			// saving the same deletion again replaces it
+			require.NoError(t, store.SaveDeletion(ctx, deletion1))
+
+			deletions, err := store.GetDeletions(ctx)
+
+			require.NoError(t, err)
+			require.Equal(t, []models.Deletion{deletion2, deletion1}, deletions)
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		withDeletionStore(t, func(t *testing.T, ctx context.Context, store *mongo.DeletionStore) {
+			require.NoError(t, store.SaveDeletion(ctx, testDeletion("1", time.Now().UTC())))
+
+			require.NoError(t, store.DeleteDeletion(ctx, "1"))
+			require.NoError(t, store.DeleteDeletion(ctx, "1"))
+
+			deletions, err := store.GetDeletions(ctx)
+
+			require.NoError(t, err)
+			require.Empty(t, deletions)
+		})
+	})
+}
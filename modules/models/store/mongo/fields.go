@@ -2,8 +2,16 @@ package mongo
 
 // Fields used in the MongoDB collections.
 const (
-	fieldID        = "id"
-	fieldCode      = "code"
-	fieldNodes     = "nodes"
-	fieldRelations = "relations"
+	fieldID          = "id"
+	fieldCode        = "code"
+	fieldName        = "name"
+	fieldDescription = "description"
+	fieldStatus      = "status"
+	fieldLabels      = "labels"
+	fieldVersion     = "version"
+	fieldKey         = "key"
+	fieldValue       = "value"
+	fieldStartedAt   = "startedAt"
+	fieldNodes       = "nodes"
+	fieldRelations   = "relations"
 )
@@ -2,6 +2,8 @@ package mongo
 
 import (
 	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func toStoreMesh(m models.Mesh) storeMesh {
@@ -50,7 +52,7 @@ func toStoreNode(n models.Node) storeNode {
 		ID:    n.ID,
 		Kind:  n.Kind,
 		Code:  n.Code,
-		Props: n.Props,
+		Props: ToStoreProps(n.Props),
 	}
 }
 
@@ -59,7 +61,7 @@ func fromStoreNode(n storeNode) models.Node {
 		ID:    n.ID,
 		Kind:  n.Kind,
 		Code:  n.Code,
-		Props: n.Props,
+		Props: FromStoreProps(n.Props),
 	}
 }
 
@@ -92,7 +94,7 @@ func toStoreRelation(r models.Relation) storeRelation {
 		Kind:  r.Kind,
 		From:  r.From,
 		To:    r.To,
-		Props: r.Props,
+		Props: ToStoreProps(r.Props),
 	}
 }
 
@@ -102,7 +104,7 @@ func fromStoreRelation(r storeRelation) models.Relation {
 		Kind:  r.Kind,
 		From:  r.From,
 		To:    r.To,
-		Props: r.Props,
+		Props: FromStoreProps(r.Props),
 	}
 }
 
@@ -153,3 +155,52 @@ func mergeMeshUpdate(m models.Mesh) map[string]any {
 
 	return update
 }
+
+// uniqueNodeCodeFilter returns a filter that matches the mesh only if no node other
+// than the given one uses the code of the given node. Nodes without a code are not checked.
+//
+// The condition is expressed with $expr, so it does not interfere with the positional
+// operator used by the embedded update queries.
+func uniqueNodeCodeFilter(modelID string, node models.Node) q.Filter {
+	filter := q.Filter{}.EQ(meshKey, modelID)
+
+	if node.Code == "" {
+		return filter
+	}
+
+	conflicting := bson.M{
+		"$filter": bson.M{
+			"input": "$" + fieldNodes,
+			"cond": bson.M{
+				"$and": bson.A{
+					bson.M{"$eq": bson.A{"$$this." + fieldCode, node.Code}},
+					bson.M{"$ne": bson.A{"$$this." + fieldID, node.ID}},
+				},
+			},
+		},
+	}
+
+	return filter.EQ("$expr", bson.M{
+		"$eq": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{conflicting, bson.A{}}}}, 0},
+	})
+}
+
+// freeNodeCodesFilter returns a filter that matches the mesh only if none of its nodes uses
+// one of the codes of the given nodes. Nodes without a code are not checked.
+func freeNodeCodesFilter(modelID string, nodes []models.Node) q.Filter {
+	filter := q.Filter{}.EQ(meshKey, modelID)
+
+	var codes []string
+
+	for _, n := range nodes {
+		if n.Code != "" {
+			codes = append(codes, n.Code)
+		}
+	}
+
+	if len(codes) == 0 {
+		return filter
+	}
+
+	return filter.NIN(fieldNodes+"."+fieldCode, codes)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +54,42 @@ func Test_mergeMeshUpdate(t *testing.T) {
 		fieldRelations: validStoreMesh.Relations,
 	}, update)
 }
+
+func Test_uniqueNodeCodeFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no-code", func(t *testing.T) {
+		filter := uniqueNodeCodeFilter("model-id", models.Node{ID: "node-id"})
+
+		require.Equal(t, q.Filter{meshKey: "model-id"}, filter)
+	})
+
+	t.Run("code", func(t *testing.T) {
+		filter := uniqueNodeCodeFilter("model-id", models.Node{ID: "node-id", Code: "code"})
+
+		require.Equal(t, "model-id", filter[meshKey])
+		require.Contains(t, filter, "$expr")
+	})
+}
+
+func Test_freeNodeCodesFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no-codes", func(t *testing.T) {
+		filter := freeNodeCodesFilter("model-id", []models.Node{{ID: "node-id"}})
+
+		require.Equal(t, q.Filter{meshKey: "model-id"}, filter)
+	})
+
+	t.Run("codes", func(t *testing.T) {
+		filter := freeNodeCodesFilter("model-id", []models.Node{
+			{ID: "node1", Code: "code1"},
+			{ID: "node2"},
+			{ID: "node3", Code: "code3"},
+		})
+
+		require.Equal(t, q.Filter{}.
+			EQ(meshKey, "model-id").
+			NIN(fieldNodes+"."+fieldCode, []string{"code1", "code3"}), filter)
+	})
+}
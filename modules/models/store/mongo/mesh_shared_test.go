@@ -28,3 +28,15 @@ var (
 		},
 	}
 )
+
+// validQuantityProps mixes quantities with plain values.
+//
+//nolint:gochecknoglobals
+var validQuantityProps = models.PropBag{
+	"rating": {
+		"voltage": models.Quantity{Value: 20, Unit: models.UnitKilovolt},
+		"current": 400.0,
+		"name":    "bus",
+	},
+	"empty": {},
+}
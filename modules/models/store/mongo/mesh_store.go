@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 	q "github.com/energimind/powermesh-core/mongoquery"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -32,6 +33,10 @@ func NewMeshStore(db *mongo.Database) *MeshStore {
 //
 //nolint:wrapcheck // see comment in the header
 func (s *MeshStore) CreateMesh(ctx context.Context, mesh models.Mesh) error {
+	if err := models.CheckNodeCodes(mesh.Nodes); err != nil {
+		return err
+	}
+
 	return q.CreateOne(s.meshes, toStoreMesh).Exec(ctx, mesh)
 }
 
@@ -39,6 +44,12 @@ func (s *MeshStore) CreateMesh(ctx context.Context, mesh models.Mesh) error {
 //
 //nolint:wrapcheck // see comment in the header
 func (s *MeshStore) UpdateMesh(ctx context.Context, mesh models.Mesh) error {
+	// The nodes are replaced as a whole, so unlike CreateNode and InsertContents
+	// the node codes can be checked before the write without a filter.
+	if err := models.CheckNodeCodes(mesh.Nodes); err != nil {
+		return err
+	}
+
 	return q.UpdateOne(s.meshes, toStoreMesh).
 		Key(meshKey).
 		Exec(ctx, mesh.ModelID, mesh)
@@ -48,6 +59,10 @@ func (s *MeshStore) UpdateMesh(ctx context.Context, mesh models.Mesh) error {
 //
 //nolint:wrapcheck // see comment in the header
 func (s *MeshStore) MergeMesh(ctx context.Context, mesh models.Mesh) error {
+	if err := models.CheckNodeCodes(mesh.Nodes); err != nil {
+		return err
+	}
+
 	return q.MergeFields(s.meshes).
 		Key(meshKey).
 		Exec(ctx, mesh.ModelID, mergeMeshUpdate(mesh))
@@ -71,22 +86,80 @@ func (s *MeshStore) GetMesh(ctx context.Context, modelID string) (models.Mesh, e
 		Exec(ctx, modelID)
 }
 
+// InsertContents implements the mesh store interface.
+//
+// The nodes and relations are added in a single update. They are only added if no node in
+// the mesh uses one of the codes of the new nodes. Inserting nothing is a no-op.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshStore) InsertContents(
+	ctx context.Context,
+	modelID string,
+	nodes []models.Node,
+	relations []models.Relation,
+) error {
+	fields := map[string]any{}
+
+	if len(nodes) > 0 {
+		fields[fieldNodes] = mapSlice(nodes, toStoreNode)
+	}
+
+	if len(relations) > 0 {
+		fields[fieldRelations] = mapSlice(relations, toStoreRelation)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	err := q.PushFields(s.meshes).
+		Key(meshKey).
+		Exec(ctx, freeNodeCodesFilter(modelID, nodes), fields)
+	if errorz.IsNotFoundError(err) {
+		for _, node := range nodes {
+			if err := s.resolveNodeCodeConflict(ctx, modelID, node, nil); err != nil {
+				return err
+			}
+		}
+
+		return errorz.NewNotFoundError("mesh %v not found", modelID)
+	}
+
+	return err
+}
+
 // CreateNode implements the mesh store interface.
 //
+// The node is only added if no other node in the mesh uses the same code.
+//
 //nolint:wrapcheck // see comment in the header
 func (s *MeshStore) CreateNode(ctx context.Context, modelID string, node models.Node) error {
-	return q.EmbeddedPush(s.meshes, fieldNodes, toStoreNode).
+	err := q.EmbeddedPush(s.meshes, fieldNodes, toStoreNode).
 		Key(meshKey).
-		Exec(ctx, modelID, node)
+		Exec(ctx, uniqueNodeCodeFilter(modelID, node), node)
+	if errorz.IsNotFoundError(err) {
+		return s.resolveNodeCodeConflict(ctx, modelID, node,
+			errorz.NewNotFoundError("mesh %v not found", modelID))
+	}
+
+	return err
 }
 
 // UpdateNode implements the mesh store interface.
 //
+// The node is only updated if no other node in the mesh uses the same code.
+//
 //nolint:wrapcheck // see comment in the header
 func (s *MeshStore) UpdateNode(ctx context.Context, modelID string, node models.Node) error {
-	return q.EmbeddedUpdate(s.meshes, fieldNodes, fieldID, toStoreNode).
+	err := q.EmbeddedUpdate(s.meshes, fieldNodes, fieldID, toStoreNode).
 		Key(meshKey).
-		Exec(ctx, modelID, node.ID, node)
+		Exec(ctx, uniqueNodeCodeFilter(modelID, node), node.ID, node)
+	if errorz.IsNotFoundError(err) {
+		return s.resolveNodeCodeConflict(ctx, modelID, node,
+			errorz.NewNotFoundError("mesh[nodes] %v[%v] not found", modelID, node.ID))
+	}
+
+	return err
 }
 
 // DeleteNode implements the mesh store interface.
@@ -107,6 +180,15 @@ func (s *MeshStore) GetNode(ctx context.Context, modelID, nodeID string) (models
 		Exec(ctx, modelID, nodeID)
 }
 
+// GetNodeByCode implements the mesh store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshStore) GetNodeByCode(ctx context.Context, modelID, code string) (models.Node, error) {
+	return q.EmbeddedGetOne(s.meshes, fieldNodes, fieldCode, extractFirstNode).
+		Key(meshKey).
+		Exec(ctx, modelID, code)
+}
+
 // GetNodes implements the mesh store interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -160,3 +242,32 @@ func (s *MeshStore) GetRelations(ctx context.Context, modelID string) ([]models.
 		Key(meshKey).
 		Exec(ctx, modelID)
 }
+
+// resolveNodeCodeConflict determines why a conditional node write did not match the mesh.
+// It returns a conflict error if another node uses the code of the given node, or the
+// fallback error otherwise.
+func (s *MeshStore) resolveNodeCodeConflict(
+	ctx context.Context,
+	modelID string,
+	node models.Node,
+	fallback error,
+) error {
+	if node.Code == "" {
+		return fallback
+	}
+
+	found, err := s.GetNodeByCode(ctx, modelID, node.Code)
+	if err != nil {
+		if errorz.IsNotFoundError(err) {
+			return fallback
+		}
+
+		return err
+	}
+
+	if found.ID != node.ID {
+		return errorz.NewConflictError("node code %s is already used by node %s", node.Code, found.ID)
+	}
+
+	return fallback
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/models/meshgen"
 	"github.com/energimind/powermesh-core/modules/models/store/mongo"
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +25,14 @@ func TestMeshStore_CreateMesh(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, mesh, foundMesh)
 	})
+
+	withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+		require.IsType(t, errorz.ConflictError{}, store.CreateMesh(ctx, duplicateCodeMesh()))
+
+		_, err := store.GetMesh(ctx, testMesh().ModelID)
+
+		require.IsType(t, errorz.NotFoundError{}, err)
+	})
 }
 
 func TestMeshStore_UpdateMesh(t *testing.T) {
@@ -51,6 +61,10 @@ func TestMeshStore_UpdateMesh(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, mesh, updatedMesh)
 		})
+
+		t.Run("duplicate-code", func(t *testing.T) {
+			require.IsType(t, errorz.ConflictError{}, store.UpdateMesh(ctx, duplicateCodeMesh()))
+		})
 	})
 }
 
@@ -76,6 +90,10 @@ func TestMeshStore_MergeMesh(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, mesh, updatedMesh)
 		})
+
+		t.Run("duplicate-code", func(t *testing.T) {
+			require.IsType(t, errorz.ConflictError{}, store.MergeMesh(ctx, duplicateCodeMesh()))
+		})
 	})
 }
 
@@ -127,22 +145,158 @@ func TestMeshStore_GetMesh(t *testing.T) {
 func TestMeshStore_CreateNode(t *testing.T) {
 	t.Parallel()
 
-	withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
-		mesh := testMesh()
+	t.Run("success", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
 
-		// create without nodes
-		mesh.Nodes = nil
+			// create without nodes
+			mesh.Nodes = nil
 
-		require.NoError(t, store.CreateMesh(ctx, mesh))
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := testNode()
 
-		node := testNode()
+			require.NoError(t, store.CreateNode(ctx, mesh.ModelID, node))
 
-		require.NoError(t, store.CreateNode(ctx, mesh.ModelID, node))
+			updatedMesh, err := store.GetMesh(ctx, mesh.ModelID)
+
+			require.NoError(t, err)
+			require.Contains(t, updatedMesh.Nodes, node.ID)
+		})
+	})
 
-		updatedMesh, err := store.GetMesh(ctx, mesh.ModelID)
+	t.Run("quantities", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
 
-		require.NoError(t, err)
-		require.Contains(t, updatedMesh.Nodes, node.ID)
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := testNode()
+			node.ID, node.Code = "2", "code2"
+			node.Props = models.PropBag{
+				"rating": {
+					"voltage": models.Quantity{Value: 0.4, Unit: models.UnitKilovolt},
+					"current": 630.0,
+				},
+			}
+
+			require.NoError(t, store.CreateNode(ctx, mesh.ModelID, node))
+
+			foundNode, err := store.GetNode(ctx, mesh.ModelID, node.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, node, foundNode)
+		})
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			require.IsType(t, errorz.NotFoundError{}, store.CreateNode(ctx, "missing", testNode()))
+		})
+	})
+
+	t.Run("code-conflict", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := testNode()
+			node.ID = "2"
+
+			require.IsType(t, errorz.ConflictError{}, store.CreateNode(ctx, mesh.ModelID, node))
+		})
+	})
+}
+
+func TestMeshStore_InsertContents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node1, node2 := testNode(), testNode()
+			node1.ID, node1.Code = "2", "code2"
+			node2.ID, node2.Code = "3", ""
+			relation := testRelation()
+			relation.ID, relation.From, relation.To = "2", node1.ID, node2.ID
+
+			require.NoError(t, store.InsertContents(ctx, mesh.ModelID,
+				[]models.Node{node1, node2}, []models.Relation{relation}))
+
+			updatedMesh, err := store.GetMesh(ctx, mesh.ModelID)
+
+			require.NoError(t, err)
+			require.Len(t, updatedMesh.Nodes, 3)
+			require.Equal(t, node1, updatedMesh.Nodes[node1.ID])
+			require.Equal(t, relation, updatedMesh.Relations[relation.ID])
+		})
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			generated, err := meshgen.Generate(meshgen.Config{
+				Seed:      1,
+				ModelID:   "generated",
+				Code:      "generated",
+				Topology:  meshgen.TopologyMeshed,
+				Nodes:     500,
+				NodeKinds: []meshgen.KindWeight{{Kind: "bus", Weight: 4}, {Kind: "load", Weight: 1}},
+				NodeProps: []meshgen.PropSpec{
+					{Section: "electrical", Key: "voltage", Min: 10, Max: 20, Unit: models.UnitKilovolt},
+				},
+				RelationProps: []meshgen.PropSpec{{Section: "line", Key: "x", Min: 0.01, Max: 0.1}},
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, store.CreateMesh(ctx, models.Mesh{ModelID: generated.ModelID, Code: generated.Code}))
+
+			nodes, relations := meshgen.Contents(generated)
+
+			require.NoError(t, store.InsertContents(ctx, generated.ModelID, nodes, relations))
+
+			found, err := store.GetMesh(ctx, generated.ModelID)
+
+			require.NoError(t, err)
+			require.Equal(t, generated, found)
+		})
+	})
+
+	t.Run("nothing", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			require.NoError(t, store.InsertContents(ctx, "missing", nil, nil))
+		})
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			node := testNode()
+
+			require.IsType(t, errorz.NotFoundError{},
+				store.InsertContents(ctx, "missing", []models.Node{node}, nil))
+		})
+	})
+
+	t.Run("code-conflict", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := testNode()
+			node.ID = "2"
+
+			require.IsType(t, errorz.ConflictError{},
+				store.InsertContents(ctx, mesh.ModelID, []models.Node{node}, nil))
+
+			unchangedMesh, err := store.GetMesh(ctx, mesh.ModelID)
+
+			require.NoError(t, err)
+			require.Len(t, unchangedMesh.Nodes, 1)
+		})
 	})
 }
 
@@ -185,6 +339,24 @@ func TestMeshStore_UpdateNode(t *testing.T) {
 			require.Equal(t, node, updatedMesh.Nodes[node.ID])
 		})
 	})
+
+	t.Run("code-conflict", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := testNode()
+			node.ID = "2"
+			node.Code = "code2"
+
+			require.NoError(t, store.CreateNode(ctx, mesh.ModelID, node))
+
+			node.Code = testNode().Code
+
+			require.IsType(t, errorz.ConflictError{}, store.UpdateNode(ctx, mesh.ModelID, node))
+		})
+	})
 }
 
 func TestMeshStore_DeleteNode(t *testing.T) {
@@ -259,6 +431,37 @@ func TestMeshStore_GetNode(t *testing.T) {
 	})
 }
 
+func TestMeshStore_GetNodeByCode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not-found", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			_, err := store.GetNodeByCode(ctx, mesh.ModelID, "missing")
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+	})
+
+	t.Run("success", func(t *testing.T) {
+		withMeshStore(t, func(t *testing.T, ctx context.Context, store *mongo.MeshStore) {
+			mesh := testMesh()
+
+			require.NoError(t, store.CreateMesh(ctx, mesh))
+
+			node := mesh.Nodes["1"]
+
+			foundNode, err := store.GetNodeByCode(ctx, mesh.ModelID, node.Code)
+
+			require.NoError(t, err)
+			require.Equal(t, node, foundNode)
+		})
+	})
+}
+
 func TestMeshStore_GetNodes(t *testing.T) {
 	t.Parallel()
 
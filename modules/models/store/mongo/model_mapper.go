@@ -1,6 +1,12 @@
 package mongo
 
-import "github.com/energimind/powermesh-core/modules/models"
+import (
+	"slices"
+	"strings"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
 
 func toStoreModel(m models.Model) storeModel {
 	return storeModel{
@@ -8,6 +14,9 @@ func toStoreModel(m models.Model) storeModel {
 		Code:        m.Code,
 		Name:        m.Name,
 		Description: m.Description,
+		Status:      m.Status,
+		Labels:      toStoreLabels(m.Labels),
+		Version:     m.Version,
 	}
 }
 
@@ -17,5 +26,58 @@ func fromStoreModel(m storeModel) models.Model {
 		Code:        m.Code,
 		Name:        m.Name,
 		Description: m.Description,
+		Status:      m.Status,
+		Labels:      fromStoreLabels(m.Labels),
+		Version:     m.Version,
+	}
+}
+
+// toStoreLabels converts the labels to key/value pairs sorted by key.
+func toStoreLabels(labels map[string]string) []storeLabel {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	sl := make([]storeLabel, 0, len(labels))
+
+	for k, v := range labels {
+		sl = append(sl, storeLabel{Key: k, Value: v})
+	}
+
+	slices.SortFunc(sl, func(a, b storeLabel) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return sl
+}
+
+func fromStoreLabels(sl []storeLabel) map[string]string {
+	if len(sl) == 0 {
+		return nil
 	}
+
+	labels := make(map[string]string, len(sl))
+
+	for _, l := range sl {
+		labels[l.Key] = l.Value
+	}
+
+	return labels
+}
+
+// modelVersionFilter builds the filter of the model at its status and version.
+// The models stored before the version was introduced have no version and match version 0.
+func modelVersionFilter(m models.Model) q.Filter {
+	filter := q.Filter{}.EQ(fieldID, m.ID).EQ(fieldStatus, m.Status)
+
+	if m.Version == 0 {
+		return filter.IN(fieldVersion, []any{int64(0), nil})
+	}
+
+	return filter.EQ(fieldVersion, m.Version)
+}
+
+// identity returns the value as is. It is used to fetch store documents without mapping.
+func identity[T any](v T) T {
+	return v
 }
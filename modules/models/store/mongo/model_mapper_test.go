@@ -3,6 +3,8 @@ package mongo
 import (
 	"testing"
 
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,3 +14,40 @@ func Test_modelMappers(t *testing.T) {
 	require.Equal(t, validStoreModel, toStoreModel(validModelModel))
 	require.Equal(t, validModelModel, fromStoreModel(validStoreModel))
 }
+
+func Test_modelVersionFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unversioned", func(t *testing.T) {
+		filter := modelVersionFilter(models.Model{ID: "model-id", Status: models.ModelStatusDraft})
+
+		require.Equal(t, q.Filter{}.
+			EQ(fieldID, "model-id").
+			EQ(fieldStatus, models.ModelStatusDraft).
+			IN(fieldVersion, []any{int64(0), nil}), filter)
+	})
+
+	t.Run("versioned", func(t *testing.T) {
+		filter := modelVersionFilter(validModelModel)
+
+		require.Equal(t, q.Filter{}.
+			EQ(fieldID, validModelModel.ID).
+			EQ(fieldStatus, validModelModel.Status).
+			EQ(fieldVersion, validModelModel.Version), filter)
+	})
+}
+
+func Test_identity(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreModel, identity(validStoreModel))
+}
+
+func Test_labelMappers(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, toStoreLabels(nil))
+	require.Nil(t, fromStoreLabels(nil))
+	require.Equal(t, validStoreModel.Labels, toStoreLabels(validModelModel.Labels))
+	require.Equal(t, validModelModel.Labels, fromStoreLabels(validStoreModel.Labels))
+}
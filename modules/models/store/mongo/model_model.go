@@ -1,9 +1,22 @@
 package mongo
 
+import "github.com/energimind/powermesh-core/modules/models"
+
 // storeModel models a model in the MongoDB store.
 type storeModel struct {
-	ID          string `bson:"id"`
-	Code        string `bson:"code"`
-	Name        string `bson:"name"`
-	Description string `bson:"description"`
+	ID          string             `bson:"id"`
+	Code        string             `bson:"code"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Status      models.ModelStatus `bson:"status"`
+	Labels      []storeLabel       `bson:"labels"`
+	Version     int64              `bson:"version"`
+}
+
+// storeLabel models a model label in the MongoDB store.
+// Labels are stored as an array of key/value pairs, because label keys may contain
+// dots, which MongoDB interprets as field paths.
+type storeLabel struct {
+	Key   string `bson:"key"`
+	Value string `bson:"value"`
 }
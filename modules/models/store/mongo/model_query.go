@@ -0,0 +1,169 @@
+package mongo
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strconv"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+// modelCursor is the position of the last model of a page.
+// It is encoded into an opaque string and returned to the client as the next page cursor.
+// The cursor also holds a hash of the query ordering and search, because its position is
+// only meaningful for the query it was returned for.
+type modelCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+	Query string `json:"q"`
+}
+
+// encodeModelCursor encodes the cursor pointing after the given model in the results of the
+// given query.
+func encodeModelCursor(m storeModel, query models.ModelQuery) string {
+	c := modelCursor{
+		Value: modelSortValue(m, query.SortBy),
+		ID:    m.ID,
+		Query: modelQueryHash(query),
+	}
+
+	data, _ := json.Marshal(c) //nolint:errchkjson // the cursor only contains strings
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeModelCursor decodes the cursor returned by encodeModelCursor.
+// It returns a validation error if the cursor was returned for a query with another
+// ordering or search.
+func decodeModelCursor(s string, query models.ModelQuery) (modelCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return modelCursor{}, errorz.NewValidationError("invalid cursor: %v", err)
+	}
+
+	var c modelCursor
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return modelCursor{}, errorz.NewValidationError("invalid cursor: %v", err)
+	}
+
+	if c.ID == "" {
+		return modelCursor{}, errorz.NewValidationError("invalid cursor: missing id")
+	}
+
+	if c.Query != modelQueryHash(query) {
+		return modelCursor{}, errorz.NewValidationError("invalid cursor: it does not match the query")
+	}
+
+	return c, nil
+}
+
+// modelQueryHash returns a hash of the sort field, sort order and search of the query.
+func modelQueryHash(query models.ModelQuery) string {
+	h := sha256.New()
+
+	for _, part := range []string{modelSortKey(query.SortBy), strconv.FormatBool(query.Descending), query.Search} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// modelSortKey returns the store field used to sort models by the given field.
+func modelSortKey(sortBy models.ModelSortField) string {
+	if sortBy == models.ModelSortByName {
+		return fieldName
+	}
+
+	return fieldCode
+}
+
+// modelSortValue returns the value of the sort field of the given model.
+func modelSortValue(m storeModel, sortBy models.ModelSortField) string {
+	if sortBy == models.ModelSortByName {
+		return m.Name
+	}
+
+	return m.Code
+}
+
+// modelQueryFilter builds the filter for the given model query.
+// If ids is not nil, the models are restricted to the given IDs.
+func modelQueryFilter(query models.ModelQuery, ids []string) (q.Filter, error) {
+	var conditions []q.Filter
+
+	if ids != nil {
+		conditions = append(conditions, q.Filter{}.IN(fieldID, ids))
+	}
+
+	if query.Search != "" {
+		pattern := regexp.QuoteMeta(query.Search)
+
+		conditions = append(conditions, q.Filter{}.OR(
+			q.Filter{}.REGEX(fieldCode, pattern, "i"),
+			q.Filter{}.REGEX(fieldName, pattern, "i"),
+			q.Filter{}.REGEX(fieldDescription, pattern, "i"),
+		))
+	}
+
+	for _, r := range query.Labels {
+		conditions = append(conditions, labelRequirementFilter(r))
+	}
+
+	if query.Cursor != "" {
+		c, err := decodeModelCursor(query.Cursor, query)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, modelCursorFilter(c, query))
+	}
+
+	if len(conditions) == 0 {
+		return q.Filter{}, nil
+	}
+
+	return q.Filter{}.AND(conditions...), nil
+}
+
+// modelCursorFilter builds the filter that matches the models after the cursor
+// in the sort order of the query.
+func modelCursorFilter(c modelCursor, query models.ModelQuery) q.Filter {
+	key := modelSortKey(query.SortBy)
+
+	after := q.Filter.GT
+
+	if query.Descending {
+		after = q.Filter.LT
+	}
+
+	return q.Filter{}.OR(
+		after(q.Filter{}, key, c.Value),
+		after(q.Filter{}.EQ(key, c.Value), fieldID, c.ID),
+	)
+}
+
+// labelRequirementFilter builds the filter that matches the models satisfying the label requirement.
+// Negative requirements also match models without the label, as in Kubernetes.
+func labelRequirementFilter(r models.LabelRequirement) q.Filter {
+	label := q.Filter{}.EQ(fieldKey, r.Key)
+
+	if len(r.Values) > 0 {
+		label = label.IN(fieldValue, r.Values)
+	}
+
+	switch r.Operator {
+	case models.LabelNotEquals, models.LabelNotIn, models.LabelDoesNotExist:
+		return q.Filter{}.NELEMMATCH(fieldLabels, label)
+	case models.LabelEquals, models.LabelIn, models.LabelExists:
+		return q.Filter{}.ELEMMATCH(fieldLabels, label)
+	}
+
+	return q.Filter{}.ELEMMATCH(fieldLabels, label)
+}
@@ -0,0 +1,186 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_modelCursor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trip", func(t *testing.T) {
+		for _, sortBy := range []models.ModelSortField{models.ModelSortByCode, models.ModelSortByName} {
+			query := models.ModelQuery{SortBy: sortBy}
+
+			c, err := decodeModelCursor(encodeModelCursor(validStoreModel, query), query)
+
+			require.NoError(t, err)
+			require.Equal(t, modelSortValue(validStoreModel, sortBy), c.Value)
+			require.Equal(t, validStoreModel.ID, c.ID)
+		}
+	})
+
+	t.Run("invalid-encoding", func(t *testing.T) {
+		_, err := decodeModelCursor("%%%", models.ModelQuery{})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("invalid-json", func(t *testing.T) {
+		_, err := decodeModelCursor("bm90LWpzb24", models.ModelQuery{})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("missing-id", func(t *testing.T) {
+		_, err := decodeModelCursor("e30", models.ModelQuery{})
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+
+	t.Run("other-query", func(t *testing.T) {
+		query := models.ModelQuery{SortBy: models.ModelSortByName, Search: "north", Limit: 1}
+		cursor := encodeModelCursor(validStoreModel, query)
+
+		others := map[string]models.ModelQuery{
+			"sort-by":    {SortBy: models.ModelSortByCode, Search: query.Search},
+			"descending": {SortBy: query.SortBy, Descending: true, Search: query.Search},
+			"search":     {SortBy: query.SortBy, Search: "south"},
+		}
+
+		for name, other := range others {
+			_, err := decodeModelCursor(cursor, other)
+
+			require.IsType(t, errorz.ValidationError{}, err, name)
+		}
+
+		// the page size may change between the pages
+		_, err := decodeModelCursor(cursor, models.ModelQuery{SortBy: query.SortBy, Search: query.Search, Limit: 5})
+
+		require.NoError(t, err)
+	})
+}
+
+func Test_modelQueryHash(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t,
+		modelQueryHash(models.ModelQuery{}),
+		modelQueryHash(models.ModelQuery{SortBy: models.ModelSortByCode}))
+	require.NotEqual(t,
+		modelQueryHash(models.ModelQuery{Search: "a"}),
+		modelQueryHash(models.ModelQuery{}))
+}
+
+func Test_modelSortKey(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, fieldCode, modelSortKey(models.ModelSortByCode))
+	require.Equal(t, fieldName, modelSortKey(models.ModelSortByName))
+	require.Equal(t, fieldCode, modelSortKey(""))
+}
+
+func Test_modelQueryFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		filter, err := modelQueryFilter(models.ModelQuery{}, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, q.Filter{}, filter)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		query := models.ModelQuery{
+			Search: "a.b",
+			Labels: models.LabelSelector{{Key: "region", Operator: models.LabelExists}},
+		}
+		query.Cursor = encodeModelCursor(validStoreModel, query)
+
+		filter, err := modelQueryFilter(query, []string{"1"})
+
+		require.NoError(t, err)
+		require.Contains(t, filter, "$and")
+		require.Len(t, filter["$and"], 4)
+	})
+
+	t.Run("invalid-cursor", func(t *testing.T) {
+		_, err := modelQueryFilter(models.ModelQuery{Cursor: "%%%"}, nil)
+
+		require.IsType(t, errorz.ValidationError{}, err)
+	})
+}
+
+func Test_modelCursorFilter(t *testing.T) {
+	t.Parallel()
+
+	c := modelCursor{Value: "code1", ID: "1"}
+
+	t.Run("ascending", func(t *testing.T) {
+		require.Equal(t,
+			q.Filter{}.OR(
+				q.Filter{}.GT(fieldCode, "code1"),
+				q.Filter{}.EQ(fieldCode, "code1").GT(fieldID, "1"),
+			),
+			modelCursorFilter(c, models.ModelQuery{SortBy: models.ModelSortByCode}))
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		require.Equal(t,
+			q.Filter{}.OR(
+				q.Filter{}.LT(fieldName, "code1"),
+				q.Filter{}.EQ(fieldName, "code1").LT(fieldID, "1"),
+			),
+			modelCursorFilter(c, models.ModelQuery{SortBy: models.ModelSortByName, Descending: true}))
+	})
+}
+
+func Test_labelRequirementFilter(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"north", "south"}
+
+	tests := map[string]struct {
+		requirement models.LabelRequirement
+		want        q.Filter
+	}{
+		"equals": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelEquals, Values: values[:1]},
+			want: q.Filter{}.ELEMMATCH(fieldLabels,
+				q.Filter{}.EQ(fieldKey, "region").IN(fieldValue, values[:1])),
+		},
+		"not-equals": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelNotEquals, Values: values[:1]},
+			want: q.Filter{}.NELEMMATCH(fieldLabels,
+				q.Filter{}.EQ(fieldKey, "region").IN(fieldValue, values[:1])),
+		},
+		"in": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelIn, Values: values},
+			want: q.Filter{}.ELEMMATCH(fieldLabels,
+				q.Filter{}.EQ(fieldKey, "region").IN(fieldValue, values)),
+		},
+		"notin": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelNotIn, Values: values},
+			want: q.Filter{}.NELEMMATCH(fieldLabels,
+				q.Filter{}.EQ(fieldKey, "region").IN(fieldValue, values)),
+		},
+		"exists": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelExists},
+			want:        q.Filter{}.ELEMMATCH(fieldLabels, q.Filter{}.EQ(fieldKey, "region")),
+		},
+		"does-not-exist": {
+			requirement: models.LabelRequirement{Key: "region", Operator: models.LabelDoesNotExist},
+			want:        q.Filter{}.NELEMMATCH(fieldLabels, q.Filter{}.EQ(fieldKey, "region")),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, labelRequirementFilter(test.requirement))
+		})
+	}
+}
@@ -8,11 +8,20 @@ var (
 		Code:        "model-code",
 		Name:        "model-name",
 		Description: "model-description",
+		Status:      models.ModelStatusPublished,
+		Labels:      map[string]string{"region": "north", "example.com/team": "ops"},
+		Version:     2,
 	}
 	validStoreModel = storeModel{
 		ID:          validModelModel.ID,
 		Code:        validModelModel.Code,
 		Name:        validModelModel.Name,
 		Description: validModelModel.Description,
+		Status:      validModelModel.Status,
+		Labels: []storeLabel{
+			{Key: "example.com/team", Value: "ops"},
+			{Key: "region", Value: "north"},
+		},
+		Version: validModelModel.Version,
 	}
 )
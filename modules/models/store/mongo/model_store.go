@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 
+	"github.com/energimind/powermesh-core/errorz"
 	"github.com/energimind/powermesh-core/modules/models"
 	q "github.com/energimind/powermesh-core/mongoquery"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -48,6 +49,29 @@ func (s *ModelStore) DeleteModel(ctx context.Context, id string) error {
 	return q.DeleteOne(s.models).Exec(ctx, id)
 }
 
+// UpdateModelStatus implements the model store interface.
+//
+// It moves the model to the status and increments its version, if the stored model still has
+// the status and version of the given current model. It returns a conflict error if the
+// model has been changed since it was read.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelStore) UpdateModelStatus(ctx context.Context, current models.Model, status models.ModelStatus) error {
+	err := q.MergeFields(s.models).Exec(ctx, modelVersionFilter(current), map[string]any{
+		fieldStatus:  status,
+		fieldVersion: current.Version + 1,
+	})
+	if errorz.IsNotFoundError(err) {
+		if _, err := s.GetModel(ctx, current.ID); err != nil {
+			return err
+		}
+
+		return errorz.NewConflictError("model %s has been changed since it was read", current.ID)
+	}
+
+	return err
+}
+
 // GetModel implements the model store interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -61,3 +85,50 @@ func (s *ModelStore) GetModel(ctx context.Context, id string) (models.Model, err
 func (s *ModelStore) GetModelsByIDs(ctx context.Context, ids []string) ([]models.Model, error) {
 	return q.FindMany(s.models, fromStoreModel).Exec(ctx, q.Filter{}.IN(fieldID, ids))
 }
+
+// ListModels implements the model store interface.
+//
+// It fetches one model more than the page limit to find out if there is a next page.
+// A zero limit returns all matching models.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelStore) ListModels(
+	ctx context.Context,
+	query models.ModelQuery,
+	ids []string,
+) (models.ModelPage, error) {
+	filter, err := modelQueryFilter(query, ids)
+	if err != nil {
+		return models.ModelPage{}, err
+	}
+
+	var limit int64
+
+	if query.Limit > 0 {
+		limit = int64(query.Limit) + 1
+	}
+
+	found, err := q.FindMany(s.models, identity[storeModel]).
+		WithSort(modelSortKey(query.SortBy), query.Descending).
+		WithSort(fieldID, query.Descending).
+		WithLimit(limit).
+		Exec(ctx, filter)
+	if err != nil {
+		return models.ModelPage{}, err
+	}
+
+	page := models.ModelPage{
+		Models: make([]models.Model, 0, len(found)),
+	}
+
+	if query.Limit > 0 && len(found) > query.Limit {
+		found = found[:query.Limit]
+		page.NextCursor = encodeModelCursor(found[len(found)-1], query)
+	}
+
+	for _, m := range found {
+		page.Models = append(page.Models, fromStoreModel(m))
+	}
+
+	return page, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
 	"github.com/energimind/powermesh-core/modules/models/store/mongo"
 	"github.com/stretchr/testify/require"
 )
@@ -87,6 +88,38 @@ func TestModelStore_GetModel(t *testing.T) {
 	})
 }
 
+func TestModelStore_UpdateModelStatus(t *testing.T) {
+	t.Parallel()
+
+	withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+		t.Run("not-found", func(t *testing.T) {
+			err := store.UpdateModelStatus(ctx, models.Model{ID: "missing"}, models.ModelStatusPublished)
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+
+		t.Run("success", func(t *testing.T) {
+			model := testModel()
+
+			require.NoError(t, store.CreateModel(ctx, model))
+			require.NoError(t, store.UpdateModelStatus(ctx, model, models.ModelStatusPublished))
+
+			found, err := store.GetModel(ctx, model.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, models.ModelStatusPublished, found.Status)
+			require.Equal(t, model.Version+1, found.Version)
+
+			// the model read before the status change is outdated
+			err = store.UpdateModelStatus(ctx, model, models.ModelStatusArchived)
+
+			require.IsType(t, errorz.ConflictError{}, err)
+
+			require.NoError(t, store.UpdateModelStatus(ctx, found, models.ModelStatusArchived))
+		})
+	})
+}
+
 func TestModelStore_GetModelsByIDs(t *testing.T) {
 	t.Parallel()
 
@@ -116,3 +149,125 @@ func TestModelStore_GetModelsByIDs(t *testing.T) {
 		})
 	})
 }
+
+func TestModelStore_ListModels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("search", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			model1 := testModel()
+			model2 := testModel2()
+
+			require.NoError(t, store.CreateModel(ctx, model1))
+			require.NoError(t, store.CreateModel(ctx, model2))
+
+			page, err := store.ListModels(ctx, models.ModelQuery{Search: "DESCRIPTION2", Limit: 10}, nil)
+
+			require.NoError(t, err)
+			require.Equal(t, []models.Model{model2}, page.Models)
+			require.Empty(t, page.NextCursor)
+		})
+	})
+
+	t.Run("restricted", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			model1 := testModel()
+			model2 := testModel2()
+
+			require.NoError(t, store.CreateModel(ctx, model1))
+			require.NoError(t, store.CreateModel(ctx, model2))
+
+			page, err := store.ListModels(ctx, models.ModelQuery{Limit: 10}, []string{model1.ID})
+
+			require.NoError(t, err)
+			require.Equal(t, []models.Model{model1}, page.Models)
+		})
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			model1 := testModel()
+			model2 := testModel2()
+
+			require.NoError(t, store.CreateModel(ctx, model1))
+			require.NoError(t, store.CreateModel(ctx, model2))
+
+			query := models.ModelQuery{SortBy: models.ModelSortByName, Descending: true, Limit: 1}
+
+			page1, err := store.ListModels(ctx, query, nil)
+
+			require.NoError(t, err)
+			require.Equal(t, []models.Model{model2}, page1.Models)
+			require.NotEmpty(t, page1.NextCursor)
+
+			query.Cursor = page1.NextCursor
+
+			page2, err := store.ListModels(ctx, query, nil)
+
+			require.NoError(t, err)
+			require.Equal(t, []models.Model{model1}, page2.Models)
+			require.Empty(t, page2.NextCursor)
+		})
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			model1 := testModel()
+			model2 := testModel2()
+
+			require.NoError(t, store.CreateModel(ctx, model1))
+			require.NoError(t, store.CreateModel(ctx, model2))
+
+			tests := map[string][]models.Model{
+				"region=north":                {model1},
+				"region!=north":               {model2},
+				"region in (north, south)":    {model1, model2},
+				"region notin (north, south)": nil,
+				"purpose":                     {model1},
+				"!purpose":                    {model2},
+				"purpose!=test":               {model1, model2},
+				"region=south,!purpose":       {model2},
+			}
+
+			for s, want := range tests {
+				selector, err := models.ParseLabelSelector(s)
+
+				require.NoError(t, err)
+
+				page, err := store.ListModels(ctx, models.ModelQuery{Labels: selector}, nil)
+
+				require.NoError(t, err)
+				require.ElementsMatch(t, want, page.Models, s)
+			}
+		})
+	})
+
+	t.Run("invalid-cursor", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			_, err := store.ListModels(ctx, models.ModelQuery{Cursor: "%%%"}, nil)
+
+			require.IsType(t, errorz.ValidationError{}, err)
+		})
+	})
+
+	t.Run("cursor-of-other-query", func(t *testing.T) {
+		withModelStore(t, func(t *testing.T, ctx context.Context, store *mongo.ModelStore) {
+			require.NoError(t, store.CreateModel(ctx, testModel()))
+			require.NoError(t, store.CreateModel(ctx, testModel2()))
+
+			query := models.ModelQuery{SortBy: models.ModelSortByName, Limit: 1}
+
+			page, err := store.ListModels(ctx, query, nil)
+
+			require.NoError(t, err)
+			require.NotEmpty(t, page.NextCursor)
+
+			query.Descending = true
+			query.Cursor = page.NextCursor
+
+			_, err = store.ListModels(ctx, query, nil)
+
+			require.IsType(t, errorz.ValidationError{}, err)
+		})
+	})
+}
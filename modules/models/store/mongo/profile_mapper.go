@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+)
+
+func toStoreProfileMeta(key models.ProfileKey) storeProfileMeta {
+	return storeProfileMeta{
+		ModelID: key.ModelID,
+		NodeID:  key.NodeID,
+		Series:  key.Series,
+	}
+}
+
+// toStoreProfilePoints returns a mapper of the points of the given profile.
+func toStoreProfilePoints(key models.ProfileKey) func(models.ProfilePoint) storeProfilePoint {
+	meta := toStoreProfileMeta(key)
+
+	return func(p models.ProfilePoint) storeProfilePoint {
+		return storeProfilePoint{
+			Meta:  meta,
+			Time:  p.Time,
+			Value: p.Value,
+		}
+	}
+}
+
+func fromStoreProfilePoint(p storeProfilePoint) models.ProfilePoint {
+	return models.ProfilePoint{
+		Time:  p.Time,
+		Value: p.Value,
+	}
+}
+
+// profileFilter returns the filter matching the points of the profile.
+func profileFilter(key models.ProfileKey) q.Filter {
+	return q.Filter{}.
+		EQ(fieldProfileModelID, key.ModelID).
+		EQ(fieldProfileNodeID, key.NodeID).
+		EQ(fieldProfileSeries, key.Series)
+}
+
+// profileRangeFilter returns the filter matching the points of the profile in the range
+// [from, to). A zero bound leaves the range open on that side.
+func profileRangeFilter(key models.ProfileKey, from, to time.Time) q.Filter {
+	filter := profileFilter(key)
+
+	var bounds []q.Filter
+
+	if !from.IsZero() {
+		bounds = append(bounds, q.Filter{}.GTE(fieldProfileTime, from))
+	}
+
+	if !to.IsZero() {
+		bounds = append(bounds, q.Filter{}.LT(fieldProfileTime, to))
+	}
+
+	if len(bounds) > 0 {
+		filter = filter.AND(bounds...)
+	}
+
+	return filter
+}
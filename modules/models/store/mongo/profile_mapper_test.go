@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_profileMappers(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreProfilePoint, toStoreProfilePoints(validProfileKey)(validProfilePoint))
+	require.Equal(t, validProfilePoint, fromStoreProfilePoint(validStoreProfilePoint))
+}
+
+func Test_profileRangeFilter(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	t.Run("open", func(t *testing.T) {
+		require.Equal(t, profileFilter(validProfileKey), profileRangeFilter(validProfileKey, time.Time{}, time.Time{}))
+	})
+
+	t.Run("from", func(t *testing.T) {
+		require.Equal(t,
+			profileFilter(validProfileKey).AND(q.Filter{}.GTE(fieldProfileTime, from)),
+			profileRangeFilter(validProfileKey, from, time.Time{}))
+	})
+
+	t.Run("from-to", func(t *testing.T) {
+		require.Equal(t,
+			profileFilter(validProfileKey).AND(
+				q.Filter{}.GTE(fieldProfileTime, from),
+				q.Filter{}.LT(fieldProfileTime, to)),
+			profileRangeFilter(validProfileKey, from, to))
+	})
+}
@@ -0,0 +1,17 @@
+package mongo
+
+import "time"
+
+// storeProfilePoint models a point of a profile in the MongoDB time-series collection.
+type storeProfilePoint struct {
+	Meta  storeProfileMeta `bson:"meta"`
+	Time  time.Time        `bson:"time"`
+	Value float64          `bson:"value"`
+}
+
+// storeProfileMeta models the metadata identifying the profile of a point.
+type storeProfileMeta struct {
+	ModelID string `bson:"modelId"`
+	NodeID  string `bson:"nodeId"`
+	Series  string `bson:"series"`
+}
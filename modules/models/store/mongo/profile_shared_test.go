@@ -0,0 +1,28 @@
+package mongo
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+var (
+	validProfileKey = models.ProfileKey{
+		ModelID: "model-id",
+		NodeID:  "node-id",
+		Series:  "load",
+	}
+	validProfilePoint = models.ProfilePoint{
+		Time:  time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Value: 42.5,
+	}
+	validStoreProfilePoint = storeProfilePoint{
+		Meta: storeProfileMeta{
+			ModelID: validProfileKey.ModelID,
+			NodeID:  validProfileKey.NodeID,
+			Series:  validProfileKey.Series,
+		},
+		Time:  validProfilePoint.Time,
+		Value: validProfilePoint.Value,
+	}
+)
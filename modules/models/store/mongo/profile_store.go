@@ -0,0 +1,164 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collProfiles = "profiles"
+
+	fieldProfileMeta    = "meta"
+	fieldProfileModelID = "meta.modelId"
+	fieldProfileNodeID  = "meta.nodeId"
+	fieldProfileSeries  = "meta.series"
+	fieldProfileTime    = "time"
+
+	profileGranularity = "minutes"
+)
+
+// ProfileStore is a MongoDB implementation of the profile store.
+//
+// The points of all profiles are kept in a single time-series collection with the profile
+// key as metadata. The collection must be created with EnsureCollection before the store is
+// used. Replacing and deleting points relies on deletes by time, available since MongoDB 7.0.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type ProfileStore struct {
+	db       *mongo.Database
+	profiles *mongo.Collection
+}
+
+// NewProfileStore creates a new MongoDB profile store.
+func NewProfileStore(db *mongo.Database) *ProfileStore {
+	return &ProfileStore{
+		db:       db,
+		profiles: db.Collection(collProfiles),
+	}
+}
+
+// EnsureCollection creates the time-series collection of the profiles if it does not exist.
+func (s *ProfileStore) EnsureCollection(ctx context.Context) error {
+	opts := options.CreateCollection().SetTimeSeriesOptions(options.TimeSeries().
+		SetTimeField(fieldProfileTime).
+		SetMetaField(fieldProfileMeta).
+		SetGranularity(profileGranularity))
+
+	err := s.db.CreateCollection(ctx, collProfiles, opts)
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceExists" {
+		return nil
+	}
+
+	if err != nil {
+		return errorz.NewStoreError("failed to create %s collection: %v", collProfiles, err)
+	}
+
+	return nil
+}
+
+// WritePoints implements the profile store interface.
+// The points must be ordered by time. They replace the stored points of the profile in the
+// time range from the first to the last point.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) WritePoints(ctx context.Context, key models.ProfileKey, points []models.ProfilePoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	covered := profileRangeFilter(key, points[0].Time, points[len(points)-1].Time.Add(time.Nanosecond))
+
+	if _, err := q.DeleteMany(s.profiles).Exec(ctx, covered); err != nil {
+		return err
+	}
+
+	return q.CreateMany(s.profiles, toStoreProfilePoints(key)).Exec(ctx, points)
+}
+
+// DeleteProfile implements the profile store interface.
+// It returns a not found error if the profile has no points.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) DeleteProfile(ctx context.Context, key models.ProfileKey) error {
+	deleted, err := q.DeleteMany(s.profiles).Exec(ctx, profileFilter(key))
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return errorz.NewNotFoundError("profile %s of node %s not found", key.Series, key.NodeID)
+	}
+
+	return nil
+}
+
+// DeleteNodeProfiles implements the profile store interface.
+// It succeeds if the nodes have no profiles.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) DeleteNodeProfiles(ctx context.Context, modelID string, nodeIDs []string) error {
+	_, err := q.DeleteMany(s.profiles).Exec(ctx, q.Filter{}.
+		EQ(fieldProfileModelID, modelID).
+		IN(fieldProfileNodeID, nodeIDs))
+
+	return err
+}
+
+// DeleteModelProfiles implements the profile store interface.
+// It succeeds if the model has no profiles.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) DeleteModelProfiles(ctx context.Context, modelID string) error {
+	_, err := q.DeleteMany(s.profiles).Exec(ctx, q.Filter{}.EQ(fieldProfileModelID, modelID))
+
+	return err
+}
+
+// GetPoints implements the profile store interface.
+// The points in the range [from, to) are returned ordered by time; a zero bound leaves the
+// range open on that side.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) GetPoints(
+	ctx context.Context,
+	key models.ProfileKey,
+	from, to time.Time,
+) ([]models.ProfilePoint, error) {
+	return q.FindMany(s.profiles, fromStoreProfilePoint).
+		WithSort(fieldProfileTime, false).
+		Exec(ctx, profileRangeFilter(key, from, to))
+}
+
+// GetProfileKeys implements the profile store interface.
+// The keys are ordered by series name.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ProfileStore) GetProfileKeys(ctx context.Context, modelID, nodeID string) ([]models.ProfileKey, error) {
+	series, err := q.Distinct[string](s.profiles, fieldProfileSeries).Exec(ctx, q.Filter{}.
+		EQ(fieldProfileModelID, modelID).
+		EQ(fieldProfileNodeID, nodeID))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(series)
+
+	keys := make([]models.ProfileKey, len(series))
+
+	for i, name := range series {
+		keys[i] = models.ProfileKey{ModelID: modelID, NodeID: nodeID, Series: name}
+	}
+
+	return keys, nil
+}
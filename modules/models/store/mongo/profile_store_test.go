@@ -0,0 +1,183 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testLoadKey       = models.ProfileKey{ModelID: "m1", NodeID: "n1", Series: "load"}
+	testGenerationKey = models.ProfileKey{ModelID: "m1", NodeID: "n1", Series: "generation"}
+	testOtherNodeKey  = models.ProfileKey{ModelID: "m1", NodeID: "n2", Series: "load"}
+	testOtherModelKey = models.ProfileKey{ModelID: "m2", NodeID: "n1", Series: "load"}
+)
+
+func TestProfileStore_EnsureCollection(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		// the collection already exists
+		require.NoError(t, store.EnsureCollection(ctx))
+	})
+}
+
+func TestProfileStore_WritePoints(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1, 2, 3, 4)))
+		require.NoError(t, store.WritePoints(ctx, testOtherNodeKey, testProfilePoints(0, 9)))
+
+		// the second write replaces the points from 01:00 to 02:00
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(1, 20, 30)))
+
+		points, err := store.GetPoints(ctx, testLoadKey, time.Time{}, time.Time{})
+
+		require.NoError(t, err)
+		require.Equal(t, testProfilePoints(0, 1, 20, 30, 4), points)
+
+		points, err = store.GetPoints(ctx, testOtherNodeKey, time.Time{}, time.Time{})
+
+		require.NoError(t, err)
+		require.Equal(t, testProfilePoints(0, 9), points)
+	})
+}
+
+func TestProfileStore_GetPoints(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1, 2, 3, 4)))
+
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		tests := map[string]struct {
+			from, to time.Time
+			want     []models.ProfilePoint
+		}{
+			"open": {
+				want: testProfilePoints(0, 1, 2, 3, 4),
+			},
+			"from": {
+				from: start.Add(2 * time.Hour),
+				want: testProfilePoints(2, 3, 4),
+			},
+			"to": {
+				to:   start.Add(2 * time.Hour),
+				want: testProfilePoints(0, 1, 2),
+			},
+			"from-to": {
+				from: start.Add(time.Hour),
+				to:   start.Add(3 * time.Hour),
+				want: testProfilePoints(1, 2, 3),
+			},
+			"empty": {
+				from: start.Add(10 * time.Hour),
+				want: []models.ProfilePoint{},
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				points, err := store.GetPoints(ctx, testLoadKey, test.from, test.to)
+
+				require.NoError(t, err)
+				require.Equal(t, test.want, points)
+			})
+		}
+	})
+}
+
+func TestProfileStore_GetProfileKeys(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1)))
+		require.NoError(t, store.WritePoints(ctx, testGenerationKey, testProfilePoints(0, 2)))
+		require.NoError(t, store.WritePoints(ctx, testOtherNodeKey, testProfilePoints(0, 3)))
+
+		keys, err := store.GetProfileKeys(ctx, "m1", "n1")
+
+		require.NoError(t, err)
+		require.Equal(t, []models.ProfileKey{testGenerationKey, testLoadKey}, keys)
+
+		keys, err = store.GetProfileKeys(ctx, "m1", "missing")
+
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+}
+
+func TestProfileStore_DeleteProfile(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1, 2)))
+		require.NoError(t, store.WritePoints(ctx, testGenerationKey, testProfilePoints(0, 3)))
+
+		require.NoError(t, store.DeleteProfile(ctx, testLoadKey))
+		require.IsType(t, errorz.NotFoundError{}, store.DeleteProfile(ctx, testLoadKey))
+
+		keys, err := store.GetProfileKeys(ctx, "m1", "n1")
+
+		require.NoError(t, err)
+		require.Equal(t, []models.ProfileKey{testGenerationKey}, keys)
+	})
+}
+
+func TestProfileStore_DeleteNodeProfiles(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1)))
+		require.NoError(t, store.WritePoints(ctx, testOtherNodeKey, testProfilePoints(0, 2)))
+		require.NoError(t, store.WritePoints(ctx, testOtherModelKey, testProfilePoints(0, 3)))
+
+		require.NoError(t, store.DeleteNodeProfiles(ctx, "m1", []string{"n1"}))
+		require.NoError(t, store.DeleteNodeProfiles(ctx, "m1", []string{"n1"}))
+
+		keys, err := store.GetProfileKeys(ctx, "m1", "n1")
+
+		require.NoError(t, err)
+		require.Empty(t, keys)
+
+		keys, err = store.GetProfileKeys(ctx, "m1", "n2")
+
+		require.NoError(t, err)
+		require.Equal(t, []models.ProfileKey{testOtherNodeKey}, keys)
+
+		keys, err = store.GetProfileKeys(ctx, "m2", "n1")
+
+		require.NoError(t, err)
+		require.Equal(t, []models.ProfileKey{testOtherModelKey}, keys)
+	})
+}
+
+func TestProfileStore_DeleteModelProfiles(t *testing.T) {
+	t.Parallel()
+
+	withProfileStore(t, func(t *testing.T, ctx context.Context, store *mongo.ProfileStore) {
+		require.NoError(t, store.WritePoints(ctx, testLoadKey, testProfilePoints(0, 1)))
+		require.NoError(t, store.WritePoints(ctx, testOtherNodeKey, testProfilePoints(0, 2)))
+		require.NoError(t, store.WritePoints(ctx, testOtherModelKey, testProfilePoints(0, 3)))
+
+		require.NoError(t, store.DeleteModelProfiles(ctx, "m1"))
+		require.NoError(t, store.DeleteModelProfiles(ctx, "m1"))
+
+		keys, err := store.GetProfileKeys(ctx, "m1", "n2")
+
+		require.NoError(t, err)
+		require.Empty(t, keys)
+
+		keys, err = store.GetProfileKeys(ctx, "m2", "n1")
+
+		require.NoError(t, err)
+		require.Equal(t, []models.ProfileKey{testOtherModelKey}, keys)
+	})
+}
@@ -0,0 +1,107 @@
+package mongo
+
+import (
+	"github.com/energimind/powermesh-core/modules/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ToStoreProps returns a copy of the property bag with the quantities replaced by their
+// store representation. Plain values are stored as they are.
+// It is exported for the stores of other modules that keep mesh properties.
+func ToStoreProps(bag models.PropBag) models.PropBag {
+	return mapProps(bag, ToStorePropValue)
+}
+
+// FromStoreProps returns a copy of the property bag with the stored quantities restored.
+// It is exported for the stores of other modules that keep mesh properties.
+func FromStoreProps(bag models.PropBag) models.PropBag {
+	return mapProps(bag, FromStorePropValue)
+}
+
+// ToStorePropValue returns the store representation of a single property value.
+// It is exported for the stores of other modules that keep property values on their own.
+func ToStorePropValue(v any) any {
+	if q, ok := v.(models.Quantity); ok {
+		return storeQuantity{Type: quantityType, Value: q.Value, Unit: string(q.Unit)}
+	}
+
+	return v
+}
+
+// FromStorePropValue returns the property value of its store representation.
+// It is exported for the stores of other modules that keep property values on their own.
+func FromStorePropValue(v any) any {
+	if q, ok := fromStoreQuantity(v); ok {
+		return q
+	}
+
+	return v
+}
+
+// mapProps returns a copy of the property bag with the mapper applied to every value.
+func mapProps(bag models.PropBag, mapper func(any) any) models.PropBag {
+	if bag == nil {
+		return nil
+	}
+
+	mapped := make(models.PropBag, len(bag))
+
+	for name, section := range bag {
+		if section == nil {
+			mapped[name] = nil
+
+			continue
+		}
+
+		copied := make(models.PropSection, len(section))
+
+		for k, v := range section {
+			copied[k] = mapper(v)
+		}
+
+		mapped[name] = copied
+	}
+
+	return mapped
+}
+
+// fromStoreQuantity converts a decoded embedded document to a quantity.
+// It returns false if the value is not a stored quantity.
+func fromStoreQuantity(v any) (models.Quantity, bool) {
+	var doc map[string]any
+
+	switch tv := v.(type) {
+	case storeQuantity:
+		return models.Quantity{Value: tv.Value, Unit: models.Unit(tv.Unit)}, tv.Type == quantityType
+	case primitive.D:
+		doc = make(map[string]any, len(tv))
+
+		for _, e := range tv {
+			doc[e.Key] = e.Value
+		}
+	case primitive.M:
+		doc = tv
+	case map[string]any:
+		doc = tv
+	case models.PropSection: // the driver decodes embedded documents into the type of the parent map
+		doc = tv
+	default:
+		return models.Quantity{}, false
+	}
+
+	if doc["_type"] != quantityType {
+		return models.Quantity{}, false
+	}
+
+	value, ok := models.NumericValue(doc["value"])
+	if !ok {
+		return models.Quantity{}, false
+	}
+
+	unit, ok := doc["unit"].(string)
+	if !ok {
+		return models.Quantity{}, false
+	}
+
+	return models.Quantity{Value: value, Unit: models.Unit(unit)}, true
+}
@@ -0,0 +1,101 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func Test_propMappers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		require.Nil(t, ToStoreProps(nil))
+		require.Nil(t, FromStoreProps(nil))
+	})
+
+	t.Run("round-trip", func(t *testing.T) {
+		stored := ToStoreProps(validQuantityProps)
+
+		require.Equal(t, storeQuantity{Type: quantityType, Value: 20, Unit: "kV"}, stored["rating"]["voltage"])
+		require.Equal(t, validQuantityProps, FromStoreProps(stored))
+	})
+
+	t.Run("bson-round-trip", func(t *testing.T) {
+		data, err := bson.Marshal(toStoreNode(models.Node{ID: "node-id", Props: validQuantityProps}))
+
+		require.NoError(t, err)
+
+		var node storeNode
+
+		require.NoError(t, bson.Unmarshal(data, &node))
+		require.Equal(t, validQuantityProps, fromStoreNode(node).Props)
+	})
+}
+
+func Test_propValueMappers(t *testing.T) {
+	t.Parallel()
+
+	quantity := models.Quantity{Value: 10, Unit: models.UnitKilowatt}
+
+	require.Equal(t, storeQuantity{Type: quantityType, Value: 10, Unit: "kW"}, ToStorePropValue(quantity))
+	require.Equal(t, quantity, FromStorePropValue(ToStorePropValue(quantity)))
+	require.Equal(t, "bus", ToStorePropValue("bus"))
+	require.Equal(t, "bus", FromStorePropValue("bus"))
+	require.Nil(t, FromStorePropValue(ToStorePropValue(nil)))
+}
+
+func Test_fromStoreQuantity(t *testing.T) {
+	t.Parallel()
+
+	want := models.Quantity{Value: 0.4, Unit: models.UnitKilovolt}
+
+	tests := map[string]struct {
+		value  any
+		wantOK bool
+	}{
+		"document": {
+			value:  primitive.D{{Key: "_type", Value: quantityType}, {Key: "value", Value: 0.4}, {Key: "unit", Value: "kV"}},
+			wantOK: true,
+		},
+		"map": {
+			value:  primitive.M{"_type": quantityType, "value": 0.4, "unit": "kV"},
+			wantOK: true,
+		},
+		"plain-map": {
+			value:  map[string]any{"_type": quantityType, "value": 0.4, "unit": "kV"},
+			wantOK: true,
+		},
+		"section": {
+			value:  models.PropSection{"_type": quantityType, "value": 0.4, "unit": "kV"},
+			wantOK: true,
+		},
+		"untyped": {
+			value: primitive.M{"value": 0.4, "unit": "kV"},
+		},
+		"non-numeric": {
+			value: primitive.M{"_type": quantityType, "value": "0.4", "unit": "kV"},
+		},
+		"no-unit": {
+			value: primitive.M{"_type": quantityType, "value": 0.4},
+		},
+		"plain-number": {
+			value: 0.4,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, ok := fromStoreQuantity(test.value)
+
+			require.Equal(t, test.wantOK, ok)
+
+			if test.wantOK {
+				require.Equal(t, want, q)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package mongo
+
+// quantityType marks the embedded documents that hold quantities.
+const quantityType = "quantity"
+
+// storeQuantity models a quantity property value in the MongoDB store.
+// The type marker distinguishes quantities from other embedded documents in the properties.
+type storeQuantity struct {
+	Type  string  `bson:"_type"`
+	Value float64 `bson:"value"`
+	Unit  string  `bson:"unit"`
+}
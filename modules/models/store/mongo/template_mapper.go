@@ -0,0 +1,84 @@
+package mongo
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+func toStoreTemplate(t models.Template) storeTemplate {
+	return storeTemplate{
+		ID:          t.ID,
+		Code:        t.Code,
+		Name:        t.Name,
+		Description: t.Description,
+		Params:      mapSlice(t.Params, toStoreTemplateParam),
+		Nodes:       mapSlice(t.Nodes, toStoreTemplateNode),
+		Relations:   mapSlice(t.Relations, toStoreTemplateRelation),
+		Ports:       mapSlice(t.Ports, toStoreTemplatePort),
+	}
+}
+
+func fromStoreTemplate(t storeTemplate) models.Template {
+	return models.Template{
+		ID:          t.ID,
+		Code:        t.Code,
+		Name:        t.Name,
+		Description: t.Description,
+		Params:      mapSlice(t.Params, fromStoreTemplateParam),
+		Nodes:       mapSlice(t.Nodes, fromStoreTemplateNode),
+		Relations:   mapSlice(t.Relations, fromStoreTemplateRelation),
+		Ports:       mapSlice(t.Ports, fromStoreTemplatePort),
+	}
+}
+
+func toStoreTemplateParam(p models.TemplateParam) storeTemplateParam {
+	return storeTemplateParam(p)
+}
+
+func fromStoreTemplateParam(p storeTemplateParam) models.TemplateParam {
+	return models.TemplateParam(p)
+}
+
+func toStoreTemplateNode(n models.TemplateNode) storeTemplateNode {
+	n.Props = ToStoreProps(n.Props)
+
+	return storeTemplateNode(n)
+}
+
+func fromStoreTemplateNode(n storeTemplateNode) models.TemplateNode {
+	n.Props = FromStoreProps(n.Props)
+
+	return models.TemplateNode(n)
+}
+
+func toStoreTemplateRelation(r models.TemplateRelation) storeTemplateRelation {
+	r.Props = ToStoreProps(r.Props)
+
+	return storeTemplateRelation(r)
+}
+
+func fromStoreTemplateRelation(r storeTemplateRelation) models.TemplateRelation {
+	r.Props = FromStoreProps(r.Props)
+
+	return models.TemplateRelation(r)
+}
+
+func toStoreTemplatePort(p models.TemplatePort) storeTemplatePort {
+	return storeTemplatePort(p)
+}
+
+func fromStoreTemplatePort(p storeTemplatePort) models.TemplatePort {
+	return models.TemplatePort(p)
+}
+
+// mapSlice maps the elements of the slice. A nil slice is mapped to nil.
+func mapSlice[F, T any](from []F, mapper func(F) T) []T {
+	if from == nil {
+		return nil
+	}
+
+	to := make([]T, len(from))
+
+	for i, v := range from {
+		to[i] = mapper(v)
+	}
+
+	return to
+}
@@ -0,0 +1,23 @@
+package mongo
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_templateMappers(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, validStoreTemplate, toStoreTemplate(validTemplateModel))
+	require.Equal(t, validTemplateModel, fromStoreTemplate(validStoreTemplate))
+}
+
+func Test_mapSlice(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, mapSlice([]int(nil), strconv.Itoa))
+	require.Equal(t, []string{}, mapSlice([]int{}, strconv.Itoa))
+	require.Equal(t, []string{"1", "2"}, mapSlice([]int{1, 2}, strconv.Itoa))
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+// storeTemplate models a template in the MongoDB store.
+type storeTemplate struct {
+	ID          string                  `bson:"id"`
+	Code        string                  `bson:"code"`
+	Name        string                  `bson:"name"`
+	Description string                  `bson:"description"`
+	Params      []storeTemplateParam    `bson:"params"`
+	Nodes       []storeTemplateNode     `bson:"nodes"`
+	Relations   []storeTemplateRelation `bson:"relations"`
+	Ports       []storeTemplatePort     `bson:"ports"`
+}
+
+// storeTemplateParam models a template parameter in the MongoDB store.
+type storeTemplateParam struct {
+	Name     string `bson:"name"`
+	Default  string `bson:"default"`
+	Required bool   `bson:"required"`
+}
+
+// storeTemplateNode models a template node in the MongoDB store.
+type storeTemplateNode struct {
+	Key   string         `bson:"key"`
+	Kind  string         `bson:"kind"`
+	Code  string         `bson:"code"`
+	Props models.PropBag `bson:"props"`
+}
+
+// storeTemplateRelation models a template relation in the MongoDB store.
+type storeTemplateRelation struct {
+	Kind  string         `bson:"kind"`
+	From  string         `bson:"from"`
+	To    string         `bson:"to"`
+	Props models.PropBag `bson:"props"`
+}
+
+// storeTemplatePort models a template port in the MongoDB store.
+type storeTemplatePort struct {
+	Name string `bson:"name"`
+	Node string `bson:"node"`
+	Kind string `bson:"kind"`
+}
@@ -0,0 +1,30 @@
+package mongo
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+var (
+	validTemplateModel = models.Template{
+		ID:          "template-id",
+		Code:        "template-code",
+		Name:        "template-name",
+		Description: "template-description",
+		Params:      []models.TemplateParam{{Name: "name", Default: "x", Required: true}},
+		Nodes: []models.TemplateNode{
+			{Key: "bus", Kind: "bus", Code: "${name}-bus", Props: models.PropBag{"s": {"k": "${name}"}}},
+		},
+		Relations: []models.TemplateRelation{{Kind: "connects", From: "bus", To: "bus"}},
+		Ports:     []models.TemplatePort{{Name: "feed", Node: "bus", Kind: "line"}},
+	}
+	validStoreTemplate = storeTemplate{
+		ID:          validTemplateModel.ID,
+		Code:        validTemplateModel.Code,
+		Name:        validTemplateModel.Name,
+		Description: validTemplateModel.Description,
+		Params:      []storeTemplateParam{{Name: "name", Default: "x", Required: true}},
+		Nodes: []storeTemplateNode{
+			{Key: "bus", Kind: "bus", Code: "${name}-bus", Props: models.PropBag{"s": {"k": "${name}"}}},
+		},
+		Relations: []storeTemplateRelation{{Kind: "connects", From: "bus", To: "bus"}},
+		Ports:     []storeTemplatePort{{Name: "feed", Node: "bus", Kind: "line"}},
+	}
+)
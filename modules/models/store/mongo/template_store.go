@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	q "github.com/energimind/powermesh-core/mongoquery"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	collTemplates = "templates"
+)
+
+// TemplateStore is a MongoDB implementation of the template store.
+//
+// We do not wrap the errors returned by mongoquery utilities because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type TemplateStore struct {
+	templates *mongo.Collection
+}
+
+// NewTemplateStore creates a new MongoDB template store.
+func NewTemplateStore(db *mongo.Database) *TemplateStore {
+	return &TemplateStore{
+		templates: db.Collection(collTemplates),
+	}
+}
+
+// CreateTemplate implements the template store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateStore) CreateTemplate(ctx context.Context, template models.Template) error {
+	return q.CreateOne(s.templates, toStoreTemplate).Exec(ctx, template)
+}
+
+// UpdateTemplate implements the template store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateStore) UpdateTemplate(ctx context.Context, template models.Template) error {
+	return q.UpdateOne(s.templates, toStoreTemplate).Exec(ctx, template.ID, template)
+}
+
+// DeleteTemplate implements the template store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateStore) DeleteTemplate(ctx context.Context, id string) error {
+	return q.DeleteOne(s.templates).Exec(ctx, id)
+}
+
+// GetTemplate implements the template store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateStore) GetTemplate(ctx context.Context, id string) (models.Template, error) {
+	return q.GetOne(s.templates, fromStoreTemplate).Exec(ctx, id)
+}
+
+// GetTemplatesByIDs implements the template store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *TemplateStore) GetTemplatesByIDs(ctx context.Context, ids []string) ([]models.Template, error) {
+	return q.FindMany(s.templates, fromStoreTemplate).Exec(ctx, q.Filter{}.IN(fieldID, ids))
+}
@@ -0,0 +1,90 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateStore_CreateTemplate(t *testing.T) {
+	t.Parallel()
+
+	withTemplateStore(t, func(t *testing.T, ctx context.Context, store *mongo.TemplateStore) {
+		template := testTemplate()
+
+		require.NoError(t, store.CreateTemplate(ctx, template))
+
+		found, err := store.GetTemplate(ctx, template.ID)
+
+		require.NoError(t, err)
+		require.Equal(t, template, found)
+	})
+}
+
+func TestTemplateStore_UpdateTemplate(t *testing.T) {
+	t.Parallel()
+
+	withTemplateStore(t, func(t *testing.T, ctx context.Context, store *mongo.TemplateStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.UpdateTemplate(ctx, testTemplate2()))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			template := testTemplate()
+
+			require.NoError(t, store.CreateTemplate(ctx, template))
+
+			template.Name = "updated"
+			template.Ports = nil
+
+			require.NoError(t, store.UpdateTemplate(ctx, template))
+
+			found, err := store.GetTemplate(ctx, template.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, template, found)
+		})
+	})
+}
+
+func TestTemplateStore_DeleteTemplate(t *testing.T) {
+	t.Parallel()
+
+	withTemplateStore(t, func(t *testing.T, ctx context.Context, store *mongo.TemplateStore) {
+		t.Run("not-found", func(t *testing.T) {
+			require.IsType(t, errorz.NotFoundError{}, store.DeleteTemplate(ctx, "missing"))
+		})
+
+		t.Run("success", func(t *testing.T) {
+			template := testTemplate()
+
+			require.NoError(t, store.CreateTemplate(ctx, template))
+			require.NoError(t, store.DeleteTemplate(ctx, template.ID))
+
+			_, err := store.GetTemplate(ctx, template.ID)
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+	})
+}
+
+func TestTemplateStore_GetTemplatesByIDs(t *testing.T) {
+	t.Parallel()
+
+	withTemplateStore(t, func(t *testing.T, ctx context.Context, store *mongo.TemplateStore) {
+		template1 := testTemplate()
+		template2 := testTemplate2()
+
+		require.NoError(t, store.CreateTemplate(ctx, template1))
+		require.NoError(t, store.CreateTemplate(ctx, template2))
+
+		found, err := store.GetTemplatesByIDs(ctx, []string{template2.ID, "missing"})
+
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		require.Equal(t, template2.ID, found[0].ID)
+	})
+}
@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs functions in MongoDB transactions.
+//
+// Transactions are only available on replica sets and sharded clusters. Use
+// SupportsTransactions to check if the deployment supports them.
+type Transactor struct {
+	client *mongo.Client
+	admin  *mongo.Database
+}
+
+// NewTransactor creates a new transactor for the client of the given database.
+func NewTransactor(db *mongo.Database) *Transactor {
+	return &Transactor{
+		client: db.Client(),
+		admin:  db.Client().Database("admin"),
+	}
+}
+
+// helloResult is the part of the hello command result used to detect the deployment type.
+type helloResult struct {
+	SetName string `bson:"setName"`
+	Msg     string `bson:"msg"`
+}
+
+// SupportsTransactions checks if the deployment is a replica set or a sharded cluster.
+func (t *Transactor) SupportsTransactions(ctx context.Context) (bool, error) {
+	var res helloResult
+
+	if err := t.admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&res); err != nil {
+		return false, errorz.NewStoreError("failed to detect the deployment type: %v", err)
+	}
+
+	return res.SetName != "" || res.Msg == "isdbgrid", nil
+}
+
+// WithTransaction runs the function in a transaction. The function must use the given context
+// for all store operations, so they are part of the transaction. The transaction is committed
+// if the function succeeds and aborted otherwise. The function may be retried on transient
+// transaction errors.
+//
+// Domain errors returned by the function are returned as is.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return errorz.NewStoreError("failed to start session: %v", err)
+	}
+
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+
+	if err != nil && !errorz.IsDomainError(err) {
+		return errorz.NewStoreError("transaction failed: %v", err)
+	}
+
+	return err //nolint:wrapcheck // domain errors are returned as is
+}
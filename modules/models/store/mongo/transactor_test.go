@@ -0,0 +1,58 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models/store/mongo"
+	"github.com/stretchr/testify/require"
+	mongodrv "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTransactor(t *testing.T) {
+	t.Parallel()
+
+	withTransactor(t, func(t *testing.T, ctx context.Context, db *mongodrv.Database, tx *mongo.Transactor) {
+		supported, err := tx.SupportsTransactions(ctx)
+
+		require.NoError(t, err)
+
+		if !supported {
+			t.Skip("the test deployment does not support transactions")
+		}
+
+		store := mongo.NewModelStore(db)
+
+		// create the collection outside of the transaction
+		require.NoError(t, store.CreateModel(ctx, testModel2()))
+
+		t.Run("commit", func(t *testing.T) {
+			err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+				return store.CreateModel(ctx, testModel())
+			})
+
+			require.NoError(t, err)
+
+			_, err = store.GetModel(ctx, testModel().ID)
+
+			require.NoError(t, err)
+		})
+
+		t.Run("abort", func(t *testing.T) {
+			err := tx.WithTransaction(ctx, func(ctx context.Context) error {
+				if err := store.DeleteModel(ctx, testModel2().ID); err != nil {
+					return err
+				}
+
+				return errorz.NewStateError("forced error")
+			})
+
+			require.IsType(t, errorz.StateError{}, err)
+
+			_, err = store.GetModel(ctx, testModel2().ID)
+
+			require.NoError(t, err)
+		})
+	})
+}
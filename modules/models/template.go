@@ -0,0 +1,49 @@
+package models
+
+// Template defines a parameterized mesh fragment, e.g. a standard substation layout.
+//
+// The codes of the nodes and the string property values may contain ${name} placeholders
+// that are replaced with the parameter values when the template is instantiated.
+// The template nodes are referenced by their keys, which are local to the template.
+type Template struct {
+	ID          string
+	Code        string
+	Name        string
+	Description string
+	Params      []TemplateParam
+	Nodes       []TemplateNode
+	Relations   []TemplateRelation
+	Ports       []TemplatePort
+}
+
+// TemplateParam defines a template parameter.
+type TemplateParam struct {
+	Name     string // parameter name used in the ${name} placeholders
+	Default  string // value used if the parameter is not given and not required
+	Required bool   // the parameter must be given on instantiation
+}
+
+// TemplateNode defines a node of a template.
+type TemplateNode struct {
+	Key   string  // key of the node within the template
+	Kind  string  // node kind/type
+	Code  string  // node code (optional, may contain placeholders)
+	Props PropBag // custom node properties (string values may contain placeholders)
+}
+
+// TemplateRelation defines a relation between two nodes of a template.
+type TemplateRelation struct {
+	Kind  string  // relation kind/type
+	From  string  // key of the start node
+	To    string  // key of the end node
+	Props PropBag // custom relation properties (string values may contain placeholders)
+}
+
+// TemplatePort defines a point where an instantiated template can be connected to
+// an existing node of the target mesh. The connecting relation starts at the template
+// node and ends at the existing node.
+type TemplatePort struct {
+	Name string // port name
+	Node string // key of the template node
+	Kind string // kind of the connecting relation
+}
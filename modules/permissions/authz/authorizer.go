@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// bindingProvider defines the external provider of role bindings.
+// It is implemented by the permissions service.
+type bindingProvider interface {
+	GetRoleBinding(ctx context.Context, query permissions.RoleBindingQuery) (permissions.RoleBinding, error)
+}
+
+// Authorizer authorizes the actions of actors on resources.
+//
+// An actor whose global role includes the admin role in the role hierarchy may do anything.
+// Other actors are granted the permissions of the role they are bound to on the resource,
+// as given by the access matrix and its role hierarchy.
+//
+// We do not wrap the errors returned by the binding provider because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type Authorizer struct {
+	bindings  bindingProvider
+	matrix    access.Matrix
+	hierarchy access.Hierarchy
+}
+
+// NewAuthorizer creates a new authorizer looking up the role bindings of the actors with
+// the binding provider.
+func NewAuthorizer(bindings bindingProvider, opts ...Option) *Authorizer {
+	a := &Authorizer{
+		bindings:  bindings,
+		matrix:    access.NewMatrix(),
+		hierarchy: access.NewHierarchy(),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Authorize checks that the actor has the permission on the resource. It returns an access
+// denied error if the actor has no role binding to the resource, or the role it is bound
+// to lacks the permission.
+//
+//nolint:wrapcheck // see comment in the header
+func (a *Authorizer) Authorize(
+	ctx context.Context,
+	actor access.Actor,
+	resourceType permissions.ResourceType,
+	resourceID string,
+	permission access.Permission,
+) error {
+	if a.IsAdmin(actor) {
+		return nil
+	}
+
+	role, err := a.boundRole(ctx, actor, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if !a.matrix.HasPermission(a.hierarchy, role, permission) {
+		return errorz.NewAccessDeniedError("user %s has no %s permission on %s %s: role %s does not grant it",
+			actor.UserID, permission, resourceType, resourceID, role)
+	}
+
+	return nil
+}
+
+// AuthorizeGrant checks that the actor may grant the role on the resource: the role must
+// not have permissions beyond those of the role the actor is bound to on the resource, so
+// actors cannot give themselves or others more access than they have. Admins may grant any
+// role.
+func (a *Authorizer) AuthorizeGrant(
+	ctx context.Context,
+	actor access.Actor,
+	resourceType permissions.ResourceType,
+	resourceID string,
+	role access.Role,
+) error {
+	if a.IsAdmin(actor) {
+		return nil
+	}
+
+	own, err := a.boundRole(ctx, actor, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+
+	exceeding := a.matrix.GetPermissions(a.hierarchy, role) &^ a.matrix.GetPermissions(a.hierarchy, own)
+	if exceeding != access.PermissionsNone {
+		return errorz.NewAccessDeniedError("user %s cannot grant role %s on %s %s: role %s does not grant %s",
+			actor.UserID, role, resourceType, resourceID, own, exceeding)
+	}
+
+	return nil
+}
+
+// AuthorizeRole checks that the global role of the actor grants the permission. It is used
+// for the actions that do not act on a resource, such as creating a model.
+func (a *Authorizer) AuthorizeRole(actor access.Actor, permission access.Permission) error {
+	if a.IsAdmin(actor) || a.matrix.HasPermission(a.hierarchy, actor.Role, permission) {
+		return nil
+	}
+
+	return errorz.NewAccessDeniedError("user %s has no %s permission: role %s does not grant it",
+		actor.UserID, permission, actor.Role)
+}
+
+// IsAdmin checks if the global role of the actor includes the admin role in the role
+// hierarchy. It is used by the services that let admins act for other actors.
+func (a *Authorizer) IsAdmin(actor access.Actor) bool {
+	return a.hierarchy.Has(actor.Role, access.RoleAdmin)
+}
+
+// boundRole returns the role the actor is bound to on the resource. It returns an access
+// denied error if the actor has no role binding to the resource.
+//
+//nolint:wrapcheck // see comment in the header
+func (a *Authorizer) boundRole(
+	ctx context.Context,
+	actor access.Actor,
+	resourceType permissions.ResourceType,
+	resourceID string,
+) (access.Role, error) {
+	binding, err := a.bindings.GetRoleBinding(ctx, permissions.RoleBindingQuery{
+		UserID:       actor.UserID,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+	})
+	if err != nil {
+		if errorz.IsNotFoundError(err) {
+			return access.RoleNone, errorz.NewAccessDeniedError("user %s has no access to %s %s",
+				actor.UserID, resourceType, resourceID)
+		}
+
+		return access.RoleNone, err
+	}
+
+	return binding.Role, nil
+}
+
+// filter returns the items whose resources the actor has the permission on, in their order.
+// The items the actor is denied access to are left out; other errors are returned.
+func filter[T any](
+	ctx context.Context,
+	a *Authorizer,
+	actor access.Actor,
+	items []T,
+	resource func(T) (permissions.ResourceType, string),
+	permission access.Permission,
+) ([]T, error) {
+	filtered := make([]T, 0, len(items))
+
+	for _, item := range items {
+		resourceType, resourceID := resource(item)
+
+		err := a.Authorize(ctx, actor, resourceType, resourceID, permission)
+		if err != nil {
+			if errorz.IsAccessDeniedError(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		filtered = append(filtered, item)
+	}
+
+	return filtered, nil
+}
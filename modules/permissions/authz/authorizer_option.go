@@ -0,0 +1,16 @@
+package authz
+
+import "github.com/energimind/powermesh-core/access"
+
+// Option defines the option for the authorizer.
+type Option func(authorizer *Authorizer)
+
+// WithMatrix sets the access matrix giving the permissions of the roles and the role hierarchy
+// it comes with, such as those loaded from the configuration. They default to
+// access.NewMatrix and access.NewHierarchy.
+func WithMatrix(matrix access.Matrix, hierarchy access.Hierarchy) Option {
+	return func(a *Authorizer) {
+		a.matrix = matrix
+		a.hierarchy = hierarchy
+	}
+}
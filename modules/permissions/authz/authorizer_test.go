@@ -0,0 +1,268 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor        access.Actor
+		resourceType permissions.ResourceType
+		resourceID   string
+		permission   access.Permission
+		matrix       access.Matrix
+		bindingError bool
+		wantErr      error
+	}{
+		"admin": {
+			actor:        adminActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   "model2",
+			permission:   access.PermissionDelete,
+			bindingError: true,
+		},
+		"editor-write": {
+			actor:        editorActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionWrite,
+		},
+		"editor-inherited-read": {
+			actor:        editorActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionRead,
+		},
+		"editor-share": {
+			actor:        editorActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionShare,
+			wantErr:      errorz.AccessDeniedError{},
+		},
+		"guest-write": {
+			actor:        guestActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionWrite,
+			wantErr:      errorz.AccessDeniedError{},
+		},
+		"guest-write-matrix": {
+			actor:        guestActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionWrite,
+			matrix:       access.Matrix{access.RoleGuest: access.Permissions(access.PermissionWrite)},
+		},
+		"no-binding": {
+			actor:        otherActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionRead,
+			wantErr:      errorz.AccessDeniedError{},
+		},
+		"other-resource-type": {
+			actor:        editorActor,
+			resourceType: permissions.ResourceTypeTemplate,
+			resourceID:   validModelID,
+			permission:   access.PermissionRead,
+			wantErr:      errorz.AccessDeniedError{},
+		},
+		"binding-error": {
+			actor:        editorActor,
+			resourceType: permissions.ResourceTypeModel,
+			resourceID:   validModelID,
+			permission:   access.PermissionRead,
+			bindingError: true,
+			wantErr:      errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+
+			if test.matrix != nil {
+				opts = append(opts, WithMatrix(test.matrix, access.NewHierarchy()))
+			}
+
+			a := NewAuthorizer(newTestPermissionService(test.bindingError), opts...)
+
+			err := a.Authorize(context.Background(), test.actor, test.resourceType, test.resourceID, test.permission)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizer_Authorize_reason(t *testing.T) {
+	t.Parallel()
+
+	a := NewAuthorizer(newTestPermissionService(false))
+
+	err := a.Authorize(context.Background(), guestActor, permissions.ResourceTypeModel, validModelID,
+		access.PermissionWrite)
+
+	require.EqualError(t, err, "user guest has no write permission on model model1: role guest does not grant it")
+
+	err = a.Authorize(context.Background(), otherActor, permissions.ResourceTypeModel, validModelID,
+		access.PermissionRead)
+
+	require.EqualError(t, err, "user other has no access to model model1")
+}
+
+func TestAuthorizer_AuthorizeGrant(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		resourceID string
+		role       access.Role
+		wantErr    error
+	}{
+		"admin": {
+			actor:      adminActor,
+			resourceID: otherModelID,
+			role:       access.RoleAdmin,
+		},
+		"creator-own-role": {
+			actor:      creatorActor,
+			resourceID: validModelID,
+			role:       access.RoleCreator,
+		},
+		"creator-lower-role": {
+			actor:      creatorActor,
+			resourceID: validModelID,
+			role:       access.RoleEditor,
+		},
+		"creator-admin": {
+			actor:      creatorActor,
+			resourceID: validModelID,
+			role:       access.RoleAdmin,
+			wantErr:    errorz.AccessDeniedError{},
+		},
+		"editor-creator": {
+			actor:      editorActor,
+			resourceID: validModelID,
+			role:       access.RoleCreator,
+			wantErr:    errorz.AccessDeniedError{},
+		},
+		"no-binding": {
+			actor:      creatorActor,
+			resourceID: otherModelID,
+			role:       access.RoleGuest,
+			wantErr:    errorz.AccessDeniedError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a := NewAuthorizer(newTestPermissionService(false))
+
+			err := a.AuthorizeGrant(context.Background(), test.actor, permissions.ResourceTypeModel, test.resourceID,
+				test.role)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizer_AuthorizeRole(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor      access.Actor
+		permission access.Permission
+		wantErr    error
+	}{
+		"admin": {
+			actor:      adminActor,
+			permission: access.PermissionAdmin,
+		},
+		"creator-create": {
+			actor:      creatorActor,
+			permission: access.PermissionCreate,
+		},
+		"editor-create": {
+			actor:      editorActor,
+			permission: access.PermissionCreate,
+			wantErr:    errorz.AccessDeniedError{},
+		},
+		"guest-read": {
+			actor:      guestActor,
+			permission: access.PermissionRead,
+		},
+		"none-read": {
+			actor:      noneActor,
+			permission: access.PermissionRead,
+			wantErr:    errorz.AccessDeniedError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a := NewAuthorizer(newTestPermissionService(false))
+
+			err := a.AuthorizeRole(test.actor, test.permission)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthorizer_configuredAdmin(t *testing.T) {
+	t.Parallel()
+
+	matrix, hierarchy, err := access.NewMatrixFromConfig(access.MatrixConfig{Roles: []access.RoleDefinition{
+		{ID: 70, Name: "authz-superuser", Includes: []string{"admin"}},
+	}})
+
+	require.NoError(t, err)
+
+	actor := access.Actor{UserID: "superuser", Role: access.Role(70)}
+	a := NewAuthorizer(newTestPermissionService(false), WithMatrix(matrix, hierarchy))
+
+	require.NoError(t, a.Authorize(context.Background(), actor, permissions.ResourceTypeModel, otherModelID,
+		access.PermissionDelete))
+	require.NoError(t, a.AuthorizeRole(actor, access.PermissionCreate))
+	require.True(t, a.IsAdmin(actor))
+
+	// the default matrix does not know the role
+	defaultAuthorizer := NewAuthorizer(newTestPermissionService(false))
+	err = defaultAuthorizer.AuthorizeRole(actor, access.PermissionCreate)
+
+	require.IsType(t, errorz.AccessDeniedError{}, err)
+	require.False(t, defaultAuthorizer.IsAdmin(actor))
+}
@@ -0,0 +1,6 @@
+// Package authz provides the authorizer checking the permissions of actors on resources
+// against their role bindings.
+//
+// It also provides opt-in decorators of the model, mesh, permission and user services that
+// authorize the calls before delegating them to the decorated services.
+package authz
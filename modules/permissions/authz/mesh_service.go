@@ -0,0 +1,358 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// MeshService decorates a mesh service with authorization.
+//
+// It implements the models.MeshService interface.
+//
+// The meshes are authorized by their models. Deleting a mesh, a node or a relation requires
+// the delete permission on the model; the other edits are updates of the mesh and require
+// the write permission. The getters take the actor from the context and require the read
+// permission.
+//
+// We do not wrap the errors returned by the decorated service and the authorizer because
+// they are already packed as domain errors. Therefore, we disable the wrapcheck linter for
+// these calls.
+type MeshService struct {
+	next  models.MeshService
+	authz *Authorizer
+}
+
+// Ensure MeshService implements the models.MeshService interface.
+var _ models.MeshService = (*MeshService)(nil)
+
+// NewMeshService creates a new mesh service authorizing the calls to the decorated service.
+func NewMeshService(next models.MeshService, authz *Authorizer) *MeshService {
+	return &MeshService{
+		next:  next,
+		authz: authz,
+	}
+}
+
+// CreateMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) CreateMesh(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	data models.MeshData,
+) (models.Mesh, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.CreateMesh(ctx, actor, modelID, data)
+}
+
+// UpdateMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) UpdateMesh(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	data models.MeshData,
+) (models.Mesh, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.UpdateMesh(ctx, actor, modelID, data)
+}
+
+// MergeMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) MergeMesh(ctx context.Context, actor access.Actor, modelID string, data models.MeshData) error {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return err
+	}
+
+	return s.next.MergeMesh(ctx, actor, modelID, data)
+}
+
+// DeleteMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) DeleteMesh(ctx context.Context, actor access.Actor, modelID string) error {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionDelete); err != nil {
+		return err
+	}
+
+	return s.next.DeleteMesh(ctx, actor, modelID)
+}
+
+// GetMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetMesh(ctx context.Context, modelID string) (models.Mesh, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.GetMesh(ctx, modelID)
+}
+
+// LintMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) LintMesh(ctx context.Context, modelID string) (models.LintReport, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.LintReport{}, err
+	}
+
+	return s.next.LintMesh(ctx, modelID)
+}
+
+// ExtractSubmesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ExtractSubmesh(ctx context.Context, modelID string, spec models.SliceSpec) (models.Mesh, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.ExtractSubmesh(ctx, modelID, spec)
+}
+
+// ImportSubmesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) ImportSubmesh(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	submesh models.Mesh,
+) (models.Mesh, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.ImportSubmesh(ctx, actor, modelID, submesh)
+}
+
+// InsertContents implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) InsertContents(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	contents models.Mesh,
+) (models.Mesh, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Mesh{}, err
+	}
+
+	return s.next.InsertContents(ctx, actor, modelID, contents)
+}
+
+// RollupMesh implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RollupMesh(ctx context.Context, modelID string, spec models.RollupSpec) (models.Rollup, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Rollup{}, err
+	}
+
+	return s.next.RollupMesh(ctx, modelID, spec)
+}
+
+// CreateNode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) CreateNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	data models.NodeData,
+) (models.Node, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.next.CreateNode(ctx, actor, modelID, data)
+}
+
+// RestoreNode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RestoreNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	node models.Node,
+) (models.Node, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.next.RestoreNode(ctx, actor, modelID, node)
+}
+
+// UpdateNode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) UpdateNode(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, nodeID string,
+	data models.NodeData,
+) (models.Node, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.next.UpdateNode(ctx, actor, modelID, nodeID, data)
+}
+
+// DeleteNode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) DeleteNode(ctx context.Context, actor access.Actor, modelID, nodeID string) error {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionDelete); err != nil {
+		return err
+	}
+
+	return s.next.DeleteNode(ctx, actor, modelID, nodeID)
+}
+
+// GetNode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetNode(ctx context.Context, modelID, nodeID string) (models.Node, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.next.GetNode(ctx, modelID, nodeID)
+}
+
+// GetNodeByCode implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetNodeByCode(ctx context.Context, modelID, code string) (models.Node, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Node{}, err
+	}
+
+	return s.next.GetNodeByCode(ctx, modelID, code)
+}
+
+// GetNodes implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetNodes(ctx context.Context, modelID string) ([]models.Node, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return nil, err
+	}
+
+	return s.next.GetNodes(ctx, modelID)
+}
+
+// CreateRelation implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) CreateRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Relation{}, err
+	}
+
+	return s.next.CreateRelation(ctx, actor, modelID, data)
+}
+
+// RestoreRelation implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) RestoreRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	relation models.Relation,
+) (models.Relation, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Relation{}, err
+	}
+
+	return s.next.RestoreRelation(ctx, actor, modelID, relation)
+}
+
+// UpdateRelation implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) UpdateRelation(
+	ctx context.Context,
+	actor access.Actor,
+	modelID, relationID string,
+	data models.RelationData,
+) (models.Relation, error) {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionWrite); err != nil {
+		return models.Relation{}, err
+	}
+
+	return s.next.UpdateRelation(ctx, actor, modelID, relationID, data)
+}
+
+// DeleteRelation implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) DeleteRelation(ctx context.Context, actor access.Actor, modelID, relationID string) error {
+	if err := s.authorize(ctx, actor, modelID, access.PermissionDelete); err != nil {
+		return err
+	}
+
+	return s.next.DeleteRelation(ctx, actor, modelID, relationID)
+}
+
+// GetRelation implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetRelation(ctx context.Context, modelID, relationID string) (models.Relation, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return models.Relation{}, err
+	}
+
+	return s.next.GetRelation(ctx, modelID, relationID)
+}
+
+// GetRelations implements the models.MeshService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) GetRelations(ctx context.Context, modelID string) ([]models.Relation, error) {
+	if err := s.authorizeRead(ctx, modelID); err != nil {
+		return nil, err
+	}
+
+	return s.next.GetRelations(ctx, modelID)
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *MeshService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// authorizeRead checks that the actor of the context has the read permission on the model.
+func (s *MeshService) authorizeRead(ctx context.Context, modelID string) error {
+	return s.authorize(ctx, accessctx.Actor(ctx), modelID, access.PermissionRead)
+}
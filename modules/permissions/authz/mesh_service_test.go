@@ -0,0 +1,245 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeshService_authorize(t *testing.T) {
+	t.Parallel()
+
+	// calls holds the calls of the service with the least role needed on the model.
+	calls := map[string]struct {
+		call    func(ctx context.Context, svc *MeshService, actor access.Actor) error
+		minRole access.Role
+	}{
+		"create-mesh": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.CreateMesh(ctx, actor, validModelID, models.MeshData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"update-mesh": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.UpdateMesh(ctx, actor, validModelID, models.MeshData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"merge-mesh": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				return svc.MergeMesh(ctx, actor, validModelID, models.MeshData{})
+			},
+			minRole: access.RoleEditor,
+		},
+		"delete-mesh": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				return svc.DeleteMesh(ctx, actor, validModelID)
+			},
+			minRole: access.RoleCreator,
+		},
+		"get-mesh": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetMesh(ctx, validModelID)
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"lint-mesh": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.LintMesh(ctx, validModelID)
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"extract-submesh": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.ExtractSubmesh(ctx, validModelID, models.SliceSpec{})
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"import-submesh": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.ImportSubmesh(ctx, actor, validModelID, models.Mesh{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"insert-contents": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.InsertContents(ctx, actor, validModelID, models.Mesh{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"rollup-mesh": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.RollupMesh(ctx, validModelID, models.RollupSpec{})
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"create-node": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.CreateNode(ctx, actor, validModelID, models.NodeData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"restore-node": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.RestoreNode(ctx, actor, validModelID, models.Node{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"update-node": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.UpdateNode(ctx, actor, validModelID, "n1", models.NodeData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"delete-node": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				return svc.DeleteNode(ctx, actor, validModelID, "n1")
+			},
+			minRole: access.RoleCreator,
+		},
+		"get-node": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetNode(ctx, validModelID, "n1")
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"get-node-by-code": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetNodeByCode(ctx, validModelID, "N1")
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"get-nodes": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetNodes(ctx, validModelID)
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"create-relation": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.CreateRelation(ctx, actor, validModelID, models.RelationData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"restore-relation": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.RestoreRelation(ctx, actor, validModelID, models.Relation{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"update-relation": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				_, err := svc.UpdateRelation(ctx, actor, validModelID, "r1", models.RelationData{})
+
+				return err
+			},
+			minRole: access.RoleEditor,
+		},
+		"delete-relation": {
+			call: func(ctx context.Context, svc *MeshService, actor access.Actor) error {
+				return svc.DeleteRelation(ctx, actor, validModelID, "r1")
+			},
+			minRole: access.RoleCreator,
+		},
+		"get-relation": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetRelation(ctx, validModelID, "r1")
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+		"get-relations": {
+			call: func(ctx context.Context, svc *MeshService, _ access.Actor) error {
+				_, err := svc.GetRelations(ctx, validModelID)
+
+				return err
+			},
+			minRole: access.RoleGuest,
+		},
+	}
+
+	// actors holds the test actors with the roles they are bound to on the model.
+	actors := map[string]struct {
+		actor access.Actor
+		role  access.Role
+	}{
+		"admin":   {actor: adminActor, role: access.RoleAdmin},
+		"creator": {actor: creatorActor, role: access.RoleCreator},
+		"editor":  {actor: editorActor, role: access.RoleEditor},
+		"guest":   {actor: guestActor, role: access.RoleGuest},
+		"other":   {actor: otherActor, role: access.RoleNone},
+	}
+
+	for callName, call := range calls {
+		for actorName, actor := range actors {
+			t.Run(callName+"-"+actorName, func(t *testing.T) {
+				t.Parallel()
+
+				next := &testMeshService{}
+				svc := NewMeshService(next, NewAuthorizer(newTestPermissionService(false)))
+
+				err := call.call(actorContext(actor.actor), svc, actor.actor)
+
+				if !access.NewHierarchy().Has(actor.role, call.minRole) {
+					require.IsType(t, errorz.AccessDeniedError{}, err)
+					require.Empty(t, next.calls)
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, []string{callName + " " + validModelID}, next.calls)
+			})
+		}
+	}
+}
+
+func TestMeshService_bindingError(t *testing.T) {
+	t.Parallel()
+
+	next := &testMeshService{}
+	svc := NewMeshService(next, NewAuthorizer(newTestPermissionService(true)))
+
+	_, err := svc.GetMesh(actorContext(guestActor), validModelID)
+
+	require.IsType(t, errorz.StoreError{}, err)
+	require.Empty(t, next.calls)
+}
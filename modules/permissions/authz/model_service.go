@@ -0,0 +1,159 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// ModelService decorates a model service with authorization.
+//
+// It implements the models.ModelService interface.
+//
+// Creating a model requires the create permission of the global role of the actor. The other
+// actions require a permission on the model: updating the write permission, deleting the
+// delete permission, and publishing and archiving the publish permission. The getters take
+// the actor from the context and require the read permission. GetModelsByIDs leaves out the
+// models the actor cannot read, and ListModels lists only the models accessible to the actor.
+//
+// We do not wrap the errors returned by the decorated service and the authorizer because
+// they are already packed as domain errors. Therefore, we disable the wrapcheck linter for
+// these calls.
+type ModelService struct {
+	next  models.ModelService
+	authz *Authorizer
+}
+
+// Ensure ModelService implements the models.ModelService interface.
+var _ models.ModelService = (*ModelService)(nil)
+
+// NewModelService creates a new model service authorizing the calls to the decorated service.
+func NewModelService(next models.ModelService, authz *Authorizer) *ModelService {
+	return &ModelService{
+		next:  next,
+		authz: authz,
+	}
+}
+
+// CreateModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) CreateModel(
+	ctx context.Context,
+	actor access.Actor,
+	data models.ModelData,
+) (models.Model, error) {
+	if err := s.authz.AuthorizeRole(actor, access.PermissionCreate); err != nil {
+		return models.Model{}, err
+	}
+
+	return s.next.CreateModel(ctx, actor, data)
+}
+
+// UpdateModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) UpdateModel(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	data models.ModelData,
+) (models.Model, error) {
+	if err := s.authorize(ctx, actor, id, access.PermissionWrite); err != nil {
+		return models.Model{}, err
+	}
+
+	return s.next.UpdateModel(ctx, actor, id, data)
+}
+
+// DeleteModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) DeleteModel(ctx context.Context, actor access.Actor, id string) error {
+	if err := s.authorize(ctx, actor, id, access.PermissionDelete); err != nil {
+		return err
+	}
+
+	return s.next.DeleteModel(ctx, actor, id)
+}
+
+// PublishModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) PublishModel(ctx context.Context, actor access.Actor, id string) (models.Model, error) {
+	if err := s.authorize(ctx, actor, id, access.PermissionPublish); err != nil {
+		return models.Model{}, err
+	}
+
+	return s.next.PublishModel(ctx, actor, id)
+}
+
+// ArchiveModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) ArchiveModel(ctx context.Context, actor access.Actor, id string) (models.Model, error) {
+	if err := s.authorize(ctx, actor, id, access.PermissionPublish); err != nil {
+		return models.Model{}, err
+	}
+
+	return s.next.ArchiveModel(ctx, actor, id)
+}
+
+// GetModel implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) GetModel(ctx context.Context, id string) (models.Model, error) {
+	if err := s.authorize(ctx, accessctx.Actor(ctx), id, access.PermissionRead); err != nil {
+		return models.Model{}, err
+	}
+
+	return s.next.GetModel(ctx, id)
+}
+
+// GetModelsByIDs implements the models.ModelService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) GetModelsByIDs(ctx context.Context, ids []string) ([]models.Model, error) {
+	found, err := s.next.GetModelsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter(ctx, s.authz, accessctx.Actor(ctx), found, func(m models.Model) (permissions.ResourceType, string) {
+		return permissions.ResourceTypeModel, m.ID
+	}, access.PermissionRead)
+}
+
+// ListModels implements the models.ModelService interface.
+// The models are restricted to those accessible to the actor, unless the actor is an admin.
+// It requires the decorated service to be configured with an access resolver.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) ListModels(ctx context.Context, query models.ModelQuery) (models.ModelPage, error) {
+	actor := accessctx.Actor(ctx)
+
+	if !s.authz.IsAdmin(actor) {
+		if actor.UserID == "" {
+			return models.ModelPage{Models: []models.Model{}}, nil
+		}
+
+		query.AccessibleTo = actor.UserID
+	}
+
+	return s.next.ListModels(ctx, query)
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *ModelService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
@@ -0,0 +1,255 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelService_authorize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor     access.Actor
+		call      func(ctx context.Context, svc *ModelService, actor access.Actor) error
+		wantCalls []string
+		wantErr   error
+	}{
+		"create-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.CreateModel(ctx, actor, models.ModelData{})
+
+				return err
+			},
+			wantCalls: []string{"create new"},
+		},
+		"create-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.CreateModel(ctx, actor, models.ModelData{})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.UpdateModel(ctx, actor, validModelID, models.ModelData{})
+
+				return err
+			},
+			wantCalls: []string{"update model1"},
+		},
+		"update-guest": {
+			actor: guestActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.UpdateModel(ctx, actor, validModelID, models.ModelData{})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				return svc.DeleteModel(ctx, actor, validModelID)
+			},
+			wantCalls: []string{"delete model1"},
+		},
+		"delete-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				return svc.DeleteModel(ctx, actor, validModelID)
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"publish-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.PublishModel(ctx, actor, validModelID)
+
+				return err
+			},
+			wantCalls: []string{"publish model1"},
+		},
+		"archive-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *ModelService, actor access.Actor) error {
+				_, err := svc.ArchiveModel(ctx, actor, validModelID)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"get-guest": {
+			actor: guestActor,
+			call: func(ctx context.Context, svc *ModelService, _ access.Actor) error {
+				_, err := svc.GetModel(ctx, validModelID)
+
+				return err
+			},
+			wantCalls: []string{"get model1"},
+		},
+		"get-other": {
+			actor: otherActor,
+			call: func(ctx context.Context, svc *ModelService, _ access.Actor) error {
+				_, err := svc.GetModel(ctx, validModelID)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"get-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *ModelService, _ access.Actor) error {
+				_, err := svc.GetModel(ctx, otherModelID)
+
+				return err
+			},
+			wantCalls: []string{"get model2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := &testModelService{}
+			svc := NewModelService(next, NewAuthorizer(newTestPermissionService(false)))
+
+			err := test.call(actorContext(test.actor), svc, test.actor)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, next.calls)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantCalls, next.calls)
+		})
+	}
+}
+
+func TestModelService_GetModelsByIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor        access.Actor
+		bindingError bool
+		wantIDs      []string
+		wantErr      error
+	}{
+		"guest": {
+			actor:   guestActor,
+			wantIDs: []string{validModelID},
+		},
+		"other": {
+			actor:   otherActor,
+			wantIDs: []string{otherModelID},
+		},
+		"admin": {
+			actor:   adminActor,
+			wantIDs: []string{validModelID, otherModelID},
+		},
+		"anonymous": {
+			wantIDs: []string{},
+		},
+		"binding-error": {
+			actor:        guestActor,
+			bindingError: true,
+			wantErr:      errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewModelService(&testModelService{}, NewAuthorizer(newTestPermissionService(test.bindingError)))
+
+			found, err := svc.GetModelsByIDs(actorContext(test.actor), []string{validModelID, otherModelID, "model3"})
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Nil(t, found)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(found))
+
+			for _, m := range found {
+				ids = append(ids, m.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestModelService_ListModels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor            access.Actor
+		query            models.ModelQuery
+		wantAccessibleTo string
+		wantCalled       bool
+	}{
+		"guest": {
+			actor:            guestActor,
+			wantAccessibleTo: guestActor.UserID,
+			wantCalled:       true,
+		},
+		"guest-other-user": {
+			actor:            guestActor,
+			query:            models.ModelQuery{AccessibleTo: otherActor.UserID},
+			wantAccessibleTo: guestActor.UserID,
+			wantCalled:       true,
+		},
+		"admin": {
+			actor:      adminActor,
+			wantCalled: true,
+		},
+		"admin-other-user": {
+			actor:            adminActor,
+			query:            models.ModelQuery{AccessibleTo: otherActor.UserID},
+			wantAccessibleTo: otherActor.UserID,
+			wantCalled:       true,
+		},
+		"anonymous": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := &testModelService{}
+			svc := NewModelService(next, NewAuthorizer(newTestPermissionService(false)))
+
+			page, err := svc.ListModels(actorContext(test.actor), test.query)
+
+			require.NoError(t, err)
+
+			if !test.wantCalled {
+				require.Empty(t, next.calls)
+				require.Empty(t, page.Models)
+
+				return
+			}
+
+			require.Equal(t, []string{"list"}, next.calls)
+			require.Equal(t, test.wantAccessibleTo, next.query.AccessibleTo)
+		})
+	}
+}
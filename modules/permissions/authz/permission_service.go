@@ -0,0 +1,203 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/modules/permissions"
+)
+
+// PermissionService decorates a permission service with authorization.
+//
+// It implements the permissions.PermissionService interface.
+//
+// Changing the role bindings of a resource requires the share permission on the resource,
+// whoever owns them; an update moving a binding to another resource requires the share
+// permission on both. The granted role must not have permissions the actor does not have on
+// the resource, so that actors cannot escalate their own access or that of others. The
+// getters take the actor from the context and require the read permission on the resources
+// of the role bindings, leaving out the role bindings and resources the actor cannot read.
+//
+// The authorizer must look up the role bindings with the decorated service, not with this
+// one: the role bindings of the actors are not subject to their own access checks.
+//
+// We do not wrap the errors returned by the decorated service and the authorizer because
+// they are already packed as domain errors. Therefore, we disable the wrapcheck linter for
+// these calls.
+type PermissionService struct {
+	next  permissions.PermissionService
+	authz *Authorizer
+}
+
+// Ensure PermissionService implements the permissions.PermissionService interface.
+var _ permissions.PermissionService = (*PermissionService)(nil)
+
+// NewPermissionService creates a new permission service authorizing the calls to the
+// decorated service.
+func NewPermissionService(next permissions.PermissionService, authz *Authorizer) *PermissionService {
+	return &PermissionService{
+		next:  next,
+		authz: authz,
+	}
+}
+
+// CreateRoleBinding implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) CreateRoleBinding(
+	ctx context.Context,
+	actor access.Actor,
+	data permissions.RoleBindingData,
+) (permissions.RoleBinding, error) {
+	if err := s.authorizeGrant(ctx, actor, data); err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	return s.next.CreateRoleBinding(ctx, actor, data)
+}
+
+// UpdateRoleBinding implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) UpdateRoleBinding(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	data permissions.RoleBindingData,
+) (permissions.RoleBinding, error) {
+	if err := s.authorizeBinding(ctx, actor, id); err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	if err := s.authorizeGrant(ctx, actor, data); err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	return s.next.UpdateRoleBinding(ctx, actor, id, data)
+}
+
+// DeleteRoleBinding implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) DeleteRoleBinding(ctx context.Context, actor access.Actor, id string) error {
+	if err := s.authorizeBinding(ctx, actor, id); err != nil {
+		return err
+	}
+
+	return s.next.DeleteRoleBinding(ctx, actor, id)
+}
+
+// DeleteRoleBindingsByResource implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) DeleteRoleBindingsByResource(
+	ctx context.Context,
+	actor access.Actor,
+	resourceID string,
+	resourceType permissions.ResourceType,
+) error {
+	if err := s.authz.Authorize(ctx, actor, resourceType, resourceID, access.PermissionShare); err != nil {
+		return err
+	}
+
+	return s.next.DeleteRoleBindingsByResource(ctx, actor, resourceID, resourceType)
+}
+
+// GetRoleBinding implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) GetRoleBinding(
+	ctx context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	err := s.authz.Authorize(ctx, accessctx.Actor(ctx), query.ResourceType, query.ResourceID, access.PermissionRead)
+	if err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	return s.next.GetRoleBinding(ctx, query)
+}
+
+// GetRoleBindingByID implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) GetRoleBindingByID(ctx context.Context, id string) (permissions.RoleBinding, error) {
+	binding, err := s.next.GetRoleBindingByID(ctx, id)
+	if err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	err = s.authz.Authorize(ctx, accessctx.Actor(ctx), binding.ResourceType, binding.ResourceID, access.PermissionRead)
+	if err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	return binding, nil
+}
+
+// GetRoleBindingsByOwner implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) GetRoleBindingsByOwner(
+	ctx context.Context,
+	ownerID string,
+) ([]permissions.RoleBinding, error) {
+	found, err := s.next.GetRoleBindingsByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter(ctx, s.authz, accessctx.Actor(ctx), found, bindingResource, access.PermissionRead)
+}
+
+// GetAccessibleResources implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) GetAccessibleResources(
+	ctx context.Context,
+	query permissions.AccessibleResourcesQuery,
+) ([]string, error) {
+	found, err := s.next.GetAccessibleResources(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return filter(ctx, s.authz, accessctx.Actor(ctx), found, func(id string) (permissions.ResourceType, string) {
+		return query.ResourceType, id
+	}, access.PermissionRead)
+}
+
+// authorizeBinding checks that the actor has the share permission on the resource of the
+// role binding.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) authorizeBinding(ctx context.Context, actor access.Actor, id string) error {
+	binding, err := s.next.GetRoleBindingByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.authz.Authorize(ctx, actor, binding.ResourceType, binding.ResourceID, access.PermissionShare)
+}
+
+// authorizeGrant checks that the actor has the share permission on the resource of the role
+// binding data and may grant its role there.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) authorizeGrant(
+	ctx context.Context,
+	actor access.Actor,
+	data permissions.RoleBindingData,
+) error {
+	if err := s.authz.Authorize(ctx, actor, data.ResourceType, data.ResourceID, access.PermissionShare); err != nil {
+		return err
+	}
+
+	return s.authz.AuthorizeGrant(ctx, actor, data.ResourceType, data.ResourceID, data.Role)
+}
+
+// bindingResource returns the resource of the role binding.
+func bindingResource(binding permissions.RoleBinding) (permissions.ResourceType, string) {
+	return binding.ResourceType, binding.ResourceID
+}
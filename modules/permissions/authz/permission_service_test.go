@@ -0,0 +1,363 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionService_authorize(t *testing.T) {
+	t.Parallel()
+
+	validData := permissions.RoleBindingData{
+		OwnerID:      creatorActor.UserID,
+		UserID:       otherActor.UserID,
+		ResourceID:   validModelID,
+		ResourceType: permissions.ResourceTypeModel,
+		Role:         access.RoleGuest,
+	}
+
+	otherData := validData
+	otherData.ResourceID = otherModelID
+
+	creatorData := validData
+	creatorData.Role = access.RoleCreator
+
+	adminData := validData
+	adminData.Role = access.RoleAdmin
+
+	tests := map[string]struct {
+		actor     access.Actor
+		call      func(ctx context.Context, svc *PermissionService, actor access.Actor) error
+		wantCalls []string
+		wantErr   error
+	}{
+		"create-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, validData)
+
+				return err
+			},
+			wantCalls: []string{"create"},
+		},
+		"create-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, validData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"create-other-resource": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, otherData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"create-own-role": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, creatorData)
+
+				return err
+			},
+			wantCalls: []string{"create"},
+		},
+		"create-escalation": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, adminData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"create-admin-role-by-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.CreateRoleBinding(ctx, actor, adminData)
+
+				return err
+			},
+			wantCalls: []string{"create"},
+		},
+		"update-escalation": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.UpdateRoleBinding(ctx, actor, "b3", adminData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-owner": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.UpdateRoleBinding(ctx, actor, "b3", validData)
+
+				return err
+			},
+			wantCalls: []string{"update b3"},
+		},
+		"update-to-other-resource": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.UpdateRoleBinding(ctx, actor, "b3", otherData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-without-share": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.UpdateRoleBinding(ctx, actor, "b4", validData)
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				_, err := svc.UpdateRoleBinding(ctx, actor, "b4", otherData)
+
+				return err
+			},
+			wantCalls: []string{"update b4"},
+		},
+		"delete-owner": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b2")
+			},
+			wantCalls: []string{"delete b2"},
+		},
+		"delete-not-owner": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b5")
+			},
+			wantCalls: []string{"delete b5"},
+		},
+		"delete-owner-without-share": {
+			actor: otherActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b4")
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-owner-without-share-on-resource": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b5")
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-without-share": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b2")
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-unknown": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b9")
+			},
+			wantErr: errorz.NotFoundError{},
+		},
+		"delete-anonymous": {
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBinding(ctx, actor, "b2")
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-by-resource-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBindingsByResource(ctx, actor, validModelID, permissions.ResourceTypeModel)
+			},
+			wantCalls: []string{"delete-by-resource model1"},
+		},
+		"delete-by-resource-editor": {
+			actor: editorActor,
+			call: func(ctx context.Context, svc *PermissionService, actor access.Actor) error {
+				return svc.DeleteRoleBindingsByResource(ctx, actor, validModelID, permissions.ResourceTypeModel)
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"get-guest": {
+			actor: guestActor,
+			call: func(ctx context.Context, svc *PermissionService, _ access.Actor) error {
+				_, err := svc.GetRoleBinding(ctx, permissions.RoleBindingQuery{
+					UserID:       editorActor.UserID,
+					ResourceID:   validModelID,
+					ResourceType: permissions.ResourceTypeModel,
+				})
+
+				return err
+			},
+		},
+		"get-by-id-guest": {
+			actor: guestActor,
+			call: func(ctx context.Context, svc *PermissionService, _ access.Actor) error {
+				_, err := svc.GetRoleBindingByID(ctx, "b2")
+
+				return err
+			},
+		},
+		"get-by-id-other": {
+			actor: otherActor,
+			call: func(ctx context.Context, svc *PermissionService, _ access.Actor) error {
+				_, err := svc.GetRoleBindingByID(ctx, "b2")
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"get-other": {
+			actor: otherActor,
+			call: func(ctx context.Context, svc *PermissionService, _ access.Actor) error {
+				_, err := svc.GetRoleBinding(ctx, permissions.RoleBindingQuery{
+					UserID:       editorActor.UserID,
+					ResourceID:   validModelID,
+					ResourceType: permissions.ResourceTypeModel,
+				})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := newTestPermissionService(false)
+			svc := NewPermissionService(next, NewAuthorizer(next))
+
+			err := test.call(actorContext(test.actor), svc, test.actor)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, next.calls)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantCalls, next.calls)
+		})
+	}
+}
+
+func TestPermissionService_GetRoleBindingsByOwner(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor   access.Actor
+		ownerID string
+		wantIDs []string
+	}{
+		"guest": {
+			actor:   guestActor,
+			ownerID: creatorActor.UserID,
+			wantIDs: []string{"b1", "b2", "b3"},
+		},
+		"other": {
+			actor:   otherActor,
+			ownerID: creatorActor.UserID,
+			wantIDs: []string{},
+		},
+		"other-own": {
+			actor:   otherActor,
+			ownerID: otherActor.UserID,
+			wantIDs: []string{"b4"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := newTestPermissionService(false)
+			svc := NewPermissionService(next, NewAuthorizer(next))
+
+			found, err := svc.GetRoleBindingsByOwner(actorContext(test.actor), test.ownerID)
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(found))
+
+			for _, b := range found {
+				ids = append(ids, b.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
+
+func TestPermissionService_GetAccessibleResources(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor        access.Actor
+		bindingError bool
+		wantIDs      []string
+		wantErr      error
+	}{
+		"guest": {
+			actor:   guestActor,
+			wantIDs: []string{},
+		},
+		"other": {
+			actor:   otherActor,
+			wantIDs: []string{otherModelID},
+		},
+		"admin": {
+			actor:   adminActor,
+			wantIDs: []string{otherModelID},
+		},
+		"binding-error": {
+			actor:        otherActor,
+			bindingError: true,
+			wantErr:      errorz.StoreError{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := newTestPermissionService(test.bindingError)
+			svc := NewPermissionService(next, NewAuthorizer(next))
+
+			found, err := svc.GetAccessibleResources(actorContext(test.actor), permissions.AccessibleResourcesQuery{
+				UserID:       otherActor.UserID,
+				ResourceType: permissions.ResourceTypeModel,
+			})
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantIDs, found)
+		})
+	}
+}
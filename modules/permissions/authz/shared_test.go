@@ -0,0 +1,519 @@
+package authz
+
+import (
+	"context"
+	"slices"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/users"
+)
+
+var (
+	adminActor   = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	creatorActor = access.Actor{UserID: "creator", Role: access.RoleCreator}
+	editorActor  = access.Actor{UserID: "editor", Role: access.RoleEditor}
+	guestActor   = access.Actor{UserID: "guest", Role: access.RoleGuest}
+	otherActor   = access.Actor{UserID: "other", Role: access.RoleEditor}
+	noneActor    = access.Actor{UserID: "none", Role: access.RoleNone}
+	validModelID = "model1"
+	otherModelID = "model2"
+	// testBindings binds the creator, the editor and the guest to their roles on the valid
+	// model, and the other actor to the editor role on the other model. The last binding
+	// is owned by the editor, who has no share permission on the valid model.
+	testBindings = []permissions.RoleBinding{
+		testBinding("b1", creatorActor.UserID, creatorActor.UserID, validModelID, access.RoleCreator),
+		testBinding("b2", creatorActor.UserID, editorActor.UserID, validModelID, access.RoleEditor),
+		testBinding("b3", creatorActor.UserID, guestActor.UserID, validModelID, access.RoleGuest),
+		testBinding("b4", otherActor.UserID, otherActor.UserID, otherModelID, access.RoleEditor),
+		testBinding("b5", editorActor.UserID, "former", validModelID, access.RoleGuest),
+	}
+)
+
+// testPermissionService keeps the test bindings. It records the calls that change them.
+type testPermissionService struct {
+	forcedError error
+	calls       []string
+}
+
+// Ensure that the testPermissionService implements the bindingProvider interface.
+var _ bindingProvider = (*testPermissionService)(nil)
+
+// Ensure that the testPermissionService implements the permissions.PermissionService interface.
+var _ permissions.PermissionService = (*testPermissionService)(nil)
+
+func newTestPermissionService(forcedError bool) *testPermissionService {
+	var err error
+
+	if forcedError {
+		err = errorz.NewStoreError("forced-error")
+	}
+
+	return &testPermissionService{forcedError: err}
+}
+
+func (s *testPermissionService) CreateRoleBinding(
+	_ context.Context,
+	_ access.Actor,
+	data permissions.RoleBindingData,
+) (permissions.RoleBinding, error) {
+	s.calls = append(s.calls, "create")
+
+	return permissions.RoleBinding{
+		ID:           "new",
+		OwnerID:      data.OwnerID,
+		UserID:       data.UserID,
+		ResourceID:   data.ResourceID,
+		ResourceType: data.ResourceType,
+		Role:         data.Role,
+	}, nil
+}
+
+func (s *testPermissionService) UpdateRoleBinding(
+	_ context.Context,
+	_ access.Actor,
+	id string,
+	_ permissions.RoleBindingData,
+) (permissions.RoleBinding, error) {
+	s.calls = append(s.calls, "update "+id)
+
+	return permissions.RoleBinding{ID: id}, nil
+}
+
+func (s *testPermissionService) DeleteRoleBinding(_ context.Context, _ access.Actor, id string) error {
+	s.calls = append(s.calls, "delete "+id)
+
+	return nil
+}
+
+func (s *testPermissionService) DeleteRoleBindingsByResource(
+	_ context.Context,
+	_ access.Actor,
+	resourceID string,
+	_ permissions.ResourceType,
+) error {
+	s.calls = append(s.calls, "delete-by-resource "+resourceID)
+
+	return nil
+}
+
+func (s *testPermissionService) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	if s.forcedError != nil {
+		return permissions.RoleBinding{}, s.forcedError
+	}
+
+	for _, b := range testBindings {
+		if b.UserID == query.UserID && b.ResourceID == query.ResourceID && b.ResourceType == query.ResourceType {
+			return b, nil
+		}
+	}
+
+	return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+}
+
+func (s *testPermissionService) GetRoleBindingByID(_ context.Context, id string) (permissions.RoleBinding, error) {
+	if s.forcedError != nil {
+		return permissions.RoleBinding{}, s.forcedError
+	}
+
+	for _, b := range testBindings {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+
+	return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding %s not found", id)
+}
+
+func (s *testPermissionService) GetRoleBindingsByOwner(
+	_ context.Context,
+	ownerID string,
+) ([]permissions.RoleBinding, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []permissions.RoleBinding
+
+	for _, b := range testBindings {
+		if b.OwnerID == ownerID {
+			found = append(found, b)
+		}
+	}
+
+	return found, nil
+}
+
+func (s *testPermissionService) GetAccessibleResources(
+	_ context.Context,
+	query permissions.AccessibleResourcesQuery,
+) ([]string, error) {
+	if s.forcedError != nil {
+		return nil, s.forcedError
+	}
+
+	var found []string
+
+	for _, b := range testBindings {
+		if b.UserID == query.UserID && b.ResourceType == query.ResourceType {
+			found = append(found, b.ResourceID)
+		}
+	}
+
+	return found, nil
+}
+
+// testModelService holds the valid and the other model. It records the calls.
+type testModelService struct {
+	calls []string
+	query models.ModelQuery
+}
+
+// Ensure that the testModelService implements the models.ModelService interface.
+var _ models.ModelService = (*testModelService)(nil)
+
+func (s *testModelService) CreateModel(_ context.Context, _ access.Actor, _ models.ModelData) (models.Model, error) {
+	return s.model("create", "new")
+}
+
+func (s *testModelService) UpdateModel(
+	_ context.Context,
+	_ access.Actor,
+	id string,
+	_ models.ModelData,
+) (models.Model, error) {
+	return s.model("update", id)
+}
+
+func (s *testModelService) DeleteModel(_ context.Context, _ access.Actor, id string) error {
+	_, err := s.model("delete", id)
+
+	return err
+}
+
+func (s *testModelService) PublishModel(_ context.Context, _ access.Actor, id string) (models.Model, error) {
+	return s.model("publish", id)
+}
+
+func (s *testModelService) ArchiveModel(_ context.Context, _ access.Actor, id string) (models.Model, error) {
+	return s.model("archive", id)
+}
+
+func (s *testModelService) GetModel(_ context.Context, id string) (models.Model, error) {
+	return s.model("get", id)
+}
+
+func (s *testModelService) GetModelsByIDs(_ context.Context, ids []string) ([]models.Model, error) {
+	s.calls = append(s.calls, "get-by-ids")
+
+	found := make([]models.Model, 0, len(ids))
+
+	for _, id := range ids {
+		if id == validModelID || id == otherModelID {
+			found = append(found, models.Model{ID: id})
+		}
+	}
+
+	return found, nil
+}
+
+func (s *testModelService) ListModels(_ context.Context, query models.ModelQuery) (models.ModelPage, error) {
+	s.calls = append(s.calls, "list")
+	s.query = query
+
+	return models.ModelPage{Models: []models.Model{{ID: validModelID}, {ID: otherModelID}}}, nil
+}
+
+func (s *testModelService) model(call, id string) (models.Model, error) {
+	s.calls = append(s.calls, call+" "+id)
+
+	return models.Model{ID: id}, nil
+}
+
+// testMeshService records the calls.
+type testMeshService struct {
+	calls []string
+}
+
+// Ensure that the testMeshService implements the models.MeshService interface.
+var _ models.MeshService = (*testMeshService)(nil)
+
+func (s *testMeshService) CreateMesh(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.MeshData,
+) (models.Mesh, error) {
+	return s.mesh("create-mesh", modelID)
+}
+
+func (s *testMeshService) UpdateMesh(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.MeshData,
+) (models.Mesh, error) {
+	return s.mesh("update-mesh", modelID)
+}
+
+func (s *testMeshService) MergeMesh(_ context.Context, _ access.Actor, modelID string, _ models.MeshData) error {
+	_, err := s.mesh("merge-mesh", modelID)
+
+	return err
+}
+
+func (s *testMeshService) DeleteMesh(_ context.Context, _ access.Actor, modelID string) error {
+	_, err := s.mesh("delete-mesh", modelID)
+
+	return err
+}
+
+func (s *testMeshService) GetMesh(_ context.Context, modelID string) (models.Mesh, error) {
+	return s.mesh("get-mesh", modelID)
+}
+
+func (s *testMeshService) LintMesh(_ context.Context, modelID string) (models.LintReport, error) {
+	s.record("lint-mesh", modelID)
+
+	return models.LintReport{}, nil
+}
+
+func (s *testMeshService) ExtractSubmesh(_ context.Context, modelID string, _ models.SliceSpec) (models.Mesh, error) {
+	return s.mesh("extract-submesh", modelID)
+}
+
+func (s *testMeshService) ImportSubmesh(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.Mesh,
+) (models.Mesh, error) {
+	return s.mesh("import-submesh", modelID)
+}
+
+func (s *testMeshService) InsertContents(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.Mesh,
+) (models.Mesh, error) {
+	return s.mesh("insert-contents", modelID)
+}
+
+func (s *testMeshService) RollupMesh(_ context.Context, modelID string, _ models.RollupSpec) (models.Rollup, error) {
+	s.record("rollup-mesh", modelID)
+
+	return models.Rollup{}, nil
+}
+
+func (s *testMeshService) CreateNode(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.NodeData,
+) (models.Node, error) {
+	return s.node("create-node", modelID)
+}
+
+func (s *testMeshService) RestoreNode(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.Node,
+) (models.Node, error) {
+	return s.node("restore-node", modelID)
+}
+
+func (s *testMeshService) UpdateNode(
+	_ context.Context,
+	_ access.Actor,
+	modelID, _ string,
+	_ models.NodeData,
+) (models.Node, error) {
+	return s.node("update-node", modelID)
+}
+
+func (s *testMeshService) DeleteNode(_ context.Context, _ access.Actor, modelID, _ string) error {
+	_, err := s.node("delete-node", modelID)
+
+	return err
+}
+
+func (s *testMeshService) GetNode(_ context.Context, modelID, _ string) (models.Node, error) {
+	return s.node("get-node", modelID)
+}
+
+func (s *testMeshService) GetNodeByCode(_ context.Context, modelID, _ string) (models.Node, error) {
+	return s.node("get-node-by-code", modelID)
+}
+
+func (s *testMeshService) GetNodes(_ context.Context, modelID string) ([]models.Node, error) {
+	s.record("get-nodes", modelID)
+
+	return []models.Node{}, nil
+}
+
+func (s *testMeshService) CreateRelation(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.RelationData,
+) (models.Relation, error) {
+	return s.relation("create-relation", modelID)
+}
+
+func (s *testMeshService) RestoreRelation(
+	_ context.Context,
+	_ access.Actor,
+	modelID string,
+	_ models.Relation,
+) (models.Relation, error) {
+	return s.relation("restore-relation", modelID)
+}
+
+func (s *testMeshService) UpdateRelation(
+	_ context.Context,
+	_ access.Actor,
+	modelID, _ string,
+	_ models.RelationData,
+) (models.Relation, error) {
+	return s.relation("update-relation", modelID)
+}
+
+func (s *testMeshService) DeleteRelation(_ context.Context, _ access.Actor, modelID, _ string) error {
+	_, err := s.relation("delete-relation", modelID)
+
+	return err
+}
+
+func (s *testMeshService) GetRelation(_ context.Context, modelID, _ string) (models.Relation, error) {
+	return s.relation("get-relation", modelID)
+}
+
+func (s *testMeshService) GetRelations(_ context.Context, modelID string) ([]models.Relation, error) {
+	s.record("get-relations", modelID)
+
+	return []models.Relation{}, nil
+}
+
+func (s *testMeshService) record(call, modelID string) {
+	s.calls = append(s.calls, call+" "+modelID)
+}
+
+func (s *testMeshService) mesh(call, modelID string) (models.Mesh, error) {
+	s.record(call, modelID)
+
+	return models.Mesh{ModelID: modelID}, nil
+}
+
+func (s *testMeshService) node(call, modelID string) (models.Node, error) {
+	s.record(call, modelID)
+
+	return models.Node{}, nil
+}
+
+func (s *testMeshService) relation(call, modelID string) (models.Relation, error) {
+	s.record(call, modelID)
+
+	return models.Relation{}, nil
+}
+
+// testUserService holds a user for each test actor. It records the calls.
+type testUserService struct {
+	calls []string
+}
+
+// Ensure that the testUserService implements the users.UserService interface.
+var _ users.UserService = (*testUserService)(nil)
+
+func (s *testUserService) CreateUser(_ context.Context, _ access.Actor, data users.UserData) (users.User, error) {
+	s.calls = append(s.calls, "create")
+
+	return users.User{ID: "new", Username: data.Username}, nil
+}
+
+func (s *testUserService) UpdateUser(
+	_ context.Context,
+	_ access.Actor,
+	id string,
+	_ users.UserData,
+) (users.User, error) {
+	s.calls = append(s.calls, "update "+id)
+
+	return users.User{ID: id}, nil
+}
+
+func (s *testUserService) DeleteUser(_ context.Context, _ access.Actor, id string) error {
+	s.calls = append(s.calls, "delete "+id)
+
+	return nil
+}
+
+func (s *testUserService) GetUser(_ context.Context, id string) (users.User, error) {
+	return s.find(func(u users.User) bool { return u.ID == id })
+}
+
+func (s *testUserService) GetUsersByIDs(_ context.Context, ids []string) ([]users.User, error) {
+	found := make([]users.User, 0, len(ids))
+
+	for _, id := range ids {
+		if u, err := s.find(func(u users.User) bool { return u.ID == id }); err == nil {
+			found = append(found, u)
+		}
+	}
+
+	return found, nil
+}
+
+func (s *testUserService) GetUserByExternalID(_ context.Context, externalID string) (users.User, error) {
+	return s.find(func(u users.User) bool { return u.ExternalID == externalID })
+}
+
+func (s *testUserService) GetUserByUsername(_ context.Context, username string) (users.User, error) {
+	return s.find(func(u users.User) bool { return u.Username == username })
+}
+
+func (s *testUserService) find(match func(users.User) bool) (users.User, error) {
+	i := slices.IndexFunc(testUsers(), match)
+	if i < 0 {
+		return users.User{}, errorz.NewNotFoundError("user not found")
+	}
+
+	return testUsers()[i], nil
+}
+
+// testUsers returns a user for each test actor, with the user ID as external ID and
+// username.
+func testUsers() []users.User {
+	actors := []access.Actor{adminActor, creatorActor, editorActor, guestActor, otherActor, noneActor}
+	found := make([]users.User, 0, len(actors))
+
+	for _, a := range actors {
+		found = append(found, users.User{ID: a.UserID, ExternalID: a.UserID, Username: a.UserID})
+	}
+
+	return found
+}
+
+// testBinding returns a role binding to a model.
+func testBinding(id, ownerID, userID, modelID string, role access.Role) permissions.RoleBinding {
+	return permissions.RoleBinding{
+		ID:           id,
+		OwnerID:      ownerID,
+		UserID:       userID,
+		ResourceID:   modelID,
+		ResourceType: permissions.ResourceTypeModel,
+		Role:         role,
+	}
+}
+
+// actorContext returns a context carrying the actor.
+func actorContext(actor access.Actor) context.Context {
+	return accessctx.WithActor(context.Background(), actor)
+}
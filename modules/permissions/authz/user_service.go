@@ -0,0 +1,186 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/users"
+)
+
+// UserService decorates a user service with authorization.
+//
+// It implements the users.UserService interface.
+//
+// Users are not resources with role bindings, so they are authorized by the global role of
+// the actor. Creating and deleting users requires the admin permission, and so does updating
+// other users. Actors may update their own display name and email, but not the external ID
+// and username that link their logins to them. The getters take the actor from the context
+// and require the read permission, except for actors reading themselves. GetUsersByIDs leaves
+// out the users the actor cannot read.
+//
+// Authenticating actors usually looks up their users before there is an actor to authorize;
+// it must use the decorated service.
+//
+// We do not wrap the errors returned by the decorated service and the authorizer because
+// they are already packed as domain errors. Therefore, we disable the wrapcheck linter for
+// these calls.
+type UserService struct {
+	next  users.UserService
+	authz *Authorizer
+}
+
+// Ensure UserService implements the users.UserService interface.
+var _ users.UserService = (*UserService)(nil)
+
+// NewUserService creates a new user service authorizing the calls to the decorated service.
+func NewUserService(next users.UserService, authz *Authorizer) *UserService {
+	return &UserService{
+		next:  next,
+		authz: authz,
+	}
+}
+
+// CreateUser implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) CreateUser(ctx context.Context, actor access.Actor, data users.UserData) (users.User, error) {
+	if err := s.authz.AuthorizeRole(actor, access.PermissionAdmin); err != nil {
+		return users.User{}, err
+	}
+
+	return s.next.CreateUser(ctx, actor, data)
+}
+
+// UpdateUser implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) UpdateUser(
+	ctx context.Context,
+	actor access.Actor,
+	id string,
+	data users.UserData,
+) (users.User, error) {
+	if !isSelf(actor, id) {
+		if err := s.authz.AuthorizeRole(actor, access.PermissionAdmin); err != nil {
+			return users.User{}, err
+		}
+	} else if s.authz.AuthorizeRole(actor, access.PermissionAdmin) != nil {
+		if err := s.ensureSelfUpdatable(ctx, id, data); err != nil {
+			return users.User{}, err
+		}
+	}
+
+	return s.next.UpdateUser(ctx, actor, id, data)
+}
+
+// DeleteUser implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) DeleteUser(ctx context.Context, actor access.Actor, id string) error {
+	if err := s.authz.AuthorizeRole(actor, access.PermissionAdmin); err != nil {
+		return err
+	}
+
+	return s.next.DeleteUser(ctx, actor, id)
+}
+
+// GetUser implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) GetUser(ctx context.Context, id string) (users.User, error) {
+	if actor := accessctx.Actor(ctx); !isSelf(actor, id) {
+		if err := s.authz.AuthorizeRole(actor, access.PermissionRead); err != nil {
+			return users.User{}, err
+		}
+	}
+
+	return s.next.GetUser(ctx, id)
+}
+
+// GetUsersByIDs implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) GetUsersByIDs(ctx context.Context, ids []string) ([]users.User, error) {
+	found, err := s.next.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := accessctx.Actor(ctx)
+
+	if s.authz.AuthorizeRole(actor, access.PermissionRead) == nil {
+		return found, nil
+	}
+
+	filtered := make([]users.User, 0, 1)
+
+	for _, user := range found {
+		if isSelf(actor, user.ID) {
+			filtered = append(filtered, user)
+		}
+	}
+
+	return filtered, nil
+}
+
+// GetUserByExternalID implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) GetUserByExternalID(ctx context.Context, externalID string) (users.User, error) {
+	return s.getUser(ctx, func() (users.User, error) {
+		return s.next.GetUserByExternalID(ctx, externalID)
+	})
+}
+
+// GetUserByUsername implements the users.UserService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (users.User, error) {
+	return s.getUser(ctx, func() (users.User, error) {
+		return s.next.GetUserByUsername(ctx, username)
+	})
+}
+
+// getUser gets a user the actor of the context may read. If the actor lacks the read
+// permission, it is denied access to any user but itself, whether the user exists or not.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) getUser(ctx context.Context, get func() (users.User, error)) (users.User, error) {
+	actor := accessctx.Actor(ctx)
+
+	authErr := s.authz.AuthorizeRole(actor, access.PermissionRead)
+	if authErr == nil {
+		return get()
+	}
+
+	user, err := get()
+	if err != nil || !isSelf(actor, user.ID) {
+		return users.User{}, authErr
+	}
+
+	return user, nil
+}
+
+// ensureSelfUpdatable checks that an update of users by themselves keeps the fields that
+// link their logins to them.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *UserService) ensureSelfUpdatable(ctx context.Context, id string, data users.UserData) error {
+	user, err := s.next.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if data.ExternalID != user.ExternalID || data.Username != user.Username {
+		return errorz.NewAccessDeniedError("user %s cannot change their external ID or username", id)
+	}
+
+	return nil
+}
+
+// isSelf checks if the user is the actor.
+func isSelf(actor access.Actor, userID string) bool {
+	return actor.UserID != "" && actor.UserID == userID
+}
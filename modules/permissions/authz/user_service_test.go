@@ -0,0 +1,262 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/users"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_authorize(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor     access.Actor
+		call      func(ctx context.Context, svc *UserService, actor access.Actor) error
+		wantCalls []string
+		wantErr   error
+	}{
+		"create-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.CreateUser(ctx, actor, users.UserData{Username: "new"})
+
+				return err
+			},
+			wantCalls: []string{"create"},
+		},
+		"create-creator": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.CreateUser(ctx, actor, users.UserData{Username: "new"})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-self": {
+			actor: noneActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, noneActor.UserID, users.UserData{
+					ExternalID:  noneActor.UserID,
+					Username:    noneActor.UserID,
+					DisplayName: "None",
+				})
+
+				return err
+			},
+			wantCalls: []string{"update none"},
+		},
+		"update-self-external-id": {
+			actor: noneActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, noneActor.UserID, users.UserData{
+					ExternalID: adminActor.UserID,
+					Username:   noneActor.UserID,
+				})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-self-username": {
+			actor: noneActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, noneActor.UserID, users.UserData{
+					ExternalID: noneActor.UserID,
+					Username:   adminActor.UserID,
+				})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-self-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, adminActor.UserID, users.UserData{ExternalID: "new"})
+
+				return err
+			},
+			wantCalls: []string{"update admin"},
+		},
+		"update-other": {
+			actor: creatorActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, guestActor.UserID, users.UserData{})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"update-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, guestActor.UserID, users.UserData{})
+
+				return err
+			},
+			wantCalls: []string{"update guest"},
+		},
+		"update-anonymous": {
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				_, err := svc.UpdateUser(ctx, actor, "", users.UserData{})
+
+				return err
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-self": {
+			actor: guestActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				return svc.DeleteUser(ctx, actor, guestActor.UserID)
+			},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"delete-admin": {
+			actor: adminActor,
+			call: func(ctx context.Context, svc *UserService, actor access.Actor) error {
+				return svc.DeleteUser(ctx, actor, guestActor.UserID)
+			},
+			wantCalls: []string{"delete guest"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			next := &testUserService{}
+			svc := NewUserService(next, NewAuthorizer(newTestPermissionService(false)))
+
+			err := test.call(actorContext(test.actor), svc, test.actor)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, next.calls)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.wantCalls, next.calls)
+		})
+	}
+}
+
+func TestUserService_getters(t *testing.T) {
+	t.Parallel()
+
+	getters := map[string]func(ctx context.Context, svc *UserService, id string) (users.User, error){
+		"by-id": func(ctx context.Context, svc *UserService, id string) (users.User, error) {
+			return svc.GetUser(ctx, id)
+		},
+		"by-external-id": func(ctx context.Context, svc *UserService, id string) (users.User, error) {
+			return svc.GetUserByExternalID(ctx, id)
+		},
+		"by-username": func(ctx context.Context, svc *UserService, id string) (users.User, error) {
+			return svc.GetUserByUsername(ctx, id)
+		},
+	}
+
+	tests := map[string]struct {
+		actor   access.Actor
+		id      string
+		wantErr error
+	}{
+		"guest": {
+			actor: guestActor,
+			id:    editorActor.UserID,
+		},
+		"guest-unknown": {
+			actor:   guestActor,
+			id:      "unknown",
+			wantErr: errorz.NotFoundError{},
+		},
+		"none-self": {
+			actor: noneActor,
+			id:    noneActor.UserID,
+		},
+		"none-other": {
+			actor:   noneActor,
+			id:      editorActor.UserID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"none-unknown": {
+			actor:   noneActor,
+			id:      "unknown",
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"anonymous": {
+			id:      editorActor.UserID,
+			wantErr: errorz.AccessDeniedError{},
+		},
+	}
+
+	for getterName, get := range getters {
+		for name, test := range tests {
+			t.Run(getterName+"-"+name, func(t *testing.T) {
+				t.Parallel()
+
+				svc := NewUserService(&testUserService{}, NewAuthorizer(newTestPermissionService(false)))
+
+				user, err := get(actorContext(test.actor), svc, test.id)
+
+				if test.wantErr != nil {
+					require.IsType(t, test.wantErr, err)
+					require.Empty(t, user)
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, test.id, user.ID)
+			})
+		}
+	}
+}
+
+func TestUserService_GetUsersByIDs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor   access.Actor
+		wantIDs []string
+	}{
+		"guest": {
+			actor:   guestActor,
+			wantIDs: []string{editorActor.UserID, noneActor.UserID},
+		},
+		"none": {
+			actor:   noneActor,
+			wantIDs: []string{noneActor.UserID},
+		},
+		"anonymous": {
+			wantIDs: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := NewUserService(&testUserService{}, NewAuthorizer(newTestPermissionService(false)))
+
+			found, err := svc.GetUsersByIDs(actorContext(test.actor),
+				[]string{editorActor.UserID, noneActor.UserID, "unknown"})
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(found))
+
+			for _, u := range found {
+				ids = append(ids, u.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+}
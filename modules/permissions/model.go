@@ -20,8 +20,11 @@ type RoleBinding struct {
 // ResourceType represents the type of resource.
 type ResourceType int
 
-// ResourceTypeModel is the model resource type.
-const ResourceTypeModel ResourceType = iota
+// ResourceType enumeration.
+const (
+	ResourceTypeModel ResourceType = iota
+	ResourceTypeTemplate
+)
 
 // AllResourceTypes is a list of all resource types. Used for testing purposes to validate that all
 // enum values are covered.
@@ -29,12 +32,16 @@ const ResourceTypeModel ResourceType = iota
 //nolint:gochecknoglobals
 var AllResourceTypes = []ResourceType{
 	ResourceTypeModel,
+	ResourceTypeTemplate,
 }
 
 // String returns the string representation of the resource type.
 func (o ResourceType) String() string {
-	if o == ResourceTypeModel {
+	switch o {
+	case ResourceTypeModel:
 		return "model"
+	case ResourceTypeTemplate:
+		return "template"
 	}
 
 	return "ResourceType(" + strconv.Itoa(int(o)) + ")"
@@ -13,6 +13,7 @@ type PermissionService interface {
 	DeleteRoleBinding(ctx context.Context, actor access.Actor, id string) error
 	DeleteRoleBindingsByResource(ctx context.Context, actor access.Actor, resourceID string, resourceType ResourceType) error
 	GetRoleBinding(ctx context.Context, query RoleBindingQuery) (RoleBinding, error)
+	GetRoleBindingByID(ctx context.Context, id string) (RoleBinding, error)
 	GetRoleBindingsByOwner(ctx context.Context, ownerID string) ([]RoleBinding, error)
 	GetAccessibleResources(ctx context.Context, query AccessibleResourcesQuery) ([]string, error)
 }
@@ -21,6 +21,7 @@ type store interface {
 	DeleteRoleBinding(ctx context.Context, id string) error
 	DeleteRoleBindingsByResource(ctx context.Context, resourceID string, resourceType permissions.ResourceType) error
 	GetRoleBinding(ctx context.Context, query permissions.RoleBindingQuery) (permissions.RoleBinding, error)
+	GetRoleBindingByID(ctx context.Context, id string) (permissions.RoleBinding, error)
 	GetRoleBindingsByOwner(ctx context.Context, ownerID string) ([]permissions.RoleBinding, error)
 	GetAccessibleResources(ctx context.Context, query permissions.AccessibleResourcesQuery) ([]string, error)
 }
@@ -202,6 +203,25 @@ func (s *PermissionService) GetRoleBinding(
 	return roleBinding, nil
 }
 
+// GetRoleBindingByID implements the permissions.PermissionService interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionService) GetRoleBindingByID(
+	ctx context.Context,
+	id string,
+) (permissions.RoleBinding, error) {
+	if err := validateID(id); err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	roleBinding, err := s.store.GetRoleBindingByID(ctx, id)
+	if err != nil {
+		return permissions.RoleBinding{}, err
+	}
+
+	return roleBinding, nil
+}
+
 // GetRoleBindingsByOwner implements the permissions.PermissionService interface.
 //
 //nolint:wrapcheck // see comment in the header
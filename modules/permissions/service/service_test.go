@@ -316,6 +316,46 @@ func TestPermissionService_GetRoleBinding(t *testing.T) {
 	}
 }
 
+func TestPermissionService_GetRoleBindingByID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		id         string
+		storeError bool
+		wantErr    error
+	}{
+		"invalid-id": {
+			id:      "",
+			wantErr: errorz.ValidationError{},
+		},
+		"store-error": {
+			id:         validRoleBindingID,
+			storeError: true,
+			wantErr:    errorz.StoreError{},
+		},
+		"success": {
+			id: validRoleBindingID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := NewPermissionService(newTestStore(t, test.storeError), newTestIDGenerator())
+
+			rb, err := svc.GetRoleBindingByID(context.Background(), test.id)
+
+			if test.wantErr != nil {
+				require.Error(t, err)
+				require.IsType(t, test.wantErr, err)
+				require.Empty(t, rb)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, validRoleBinding, rb)
+			}
+		})
+	}
+}
+
 func TestPermissionService_GetRoleBindingsByOwner(t *testing.T) {
 	t.Parallel()
 
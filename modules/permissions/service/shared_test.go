@@ -182,6 +182,21 @@ func (s *testStore) GetRoleBinding(
 	return permissions.RoleBinding{ID: validRoleBindingID}, nil
 }
 
+func (s *testStore) GetRoleBindingByID(
+	_ context.Context,
+	id string,
+) (permissions.RoleBinding, error) {
+	s.t.Helper()
+
+	if s.forcedError != nil {
+		return permissions.RoleBinding{}, s.forcedError
+	}
+
+	require.Equal(s.t, validRoleBindingID, id)
+
+	return validRoleBinding, nil
+}
+
 func (s *testStore) GetRoleBindingsByOwner(
 	_ context.Context,
 	ownerID string,
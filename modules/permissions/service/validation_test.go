@@ -50,6 +50,13 @@ func Test_validateRole(t *testing.T) {
 
 	require.NoError(t, validateRole(access.RoleAdmin))
 	require.Error(t, validateRole(access.Role(100)))
+
+	_, _, err := access.NewMatrixFromConfig(access.MatrixConfig{
+		Roles: []access.RoleDefinition{{ID: 101, Name: "operator", Permissions: []access.Permission{access.PermissionRead}}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, validateRole(access.Role(101)))
 }
 
 func Test_validateRoleBindingData(t *testing.T) {
@@ -84,6 +84,13 @@ func (s *PermissionStore) GetRoleBinding(
 	return q.GetOne(s.permissions, fromStoreRoleBinding).Exec(ctx, filter)
 }
 
+// GetRoleBindingByID implements the permissions store interface.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *PermissionStore) GetRoleBindingByID(ctx context.Context, id string) (permissions.RoleBinding, error) {
+	return q.GetOne(s.permissions, fromStoreRoleBinding).Exec(ctx, id)
+}
+
 // GetRoleBindingsByOwner implements the permissions store interface.
 //
 //nolint:wrapcheck // see comment in the header
@@ -115,6 +115,29 @@ func TestPermissionStore_GetRoleBinding(t *testing.T) {
 	})
 }
 
+func TestPermissionStore_GetRoleBindingByID(t *testing.T) {
+	t.Parallel()
+
+	withStore(t, func(t *testing.T, ctx context.Context, store *mongo.PermissionStore) {
+		t.Run("not-found", func(t *testing.T) {
+			_, err := store.GetRoleBindingByID(ctx, "missing")
+
+			require.IsType(t, errorz.NotFoundError{}, err)
+		})
+
+		t.Run("success", func(t *testing.T) {
+			roleBinding := testRoleBinding()
+
+			require.NoError(t, store.CreateRoleBinding(ctx, roleBinding))
+
+			createdRoleBinding, err := store.GetRoleBindingByID(ctx, roleBinding.ID)
+
+			require.NoError(t, err)
+			require.Equal(t, roleBinding, createdRoleBinding)
+		})
+	})
+}
+
 func TestPermissionStore_GetRoleBindingsByOwner(t *testing.T) {
 	t.Parallel()
 
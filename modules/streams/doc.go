@@ -0,0 +1,3 @@
+// Package streams provides a model and service for streaming the mesh events of a model to
+// subscribers as they occur.
+package streams
@@ -0,0 +1,23 @@
+package streams
+
+import "github.com/energimind/powermesh-core/modules/models"
+
+// Message represents a mesh event delivered to a subscriber.
+type Message struct {
+	ID    uint64 // position of the event in the stream of its model, starting at 1
+	Epoch string // epoch of the stream, changes when the stream starts over
+	Event models.MeshEvent
+}
+
+// MeshEventTypes is a list of the event types streamed to subscribers.
+//
+//nolint:gochecknoglobals
+var MeshEventTypes = []models.EventType{
+	models.MeshCreated,
+	models.MeshUpdated,
+	models.MeshDeleted,
+	models.MeshContentsCreated,
+	models.MeshContentsUpdated,
+	models.MeshContentsDeleted,
+	models.MeshProfilesUpdated,
+}
@@ -0,0 +1,16 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeshEventTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, et := range MeshEventTypes {
+		require.True(t, strings.HasPrefix(string(et), "mesh"), et)
+	}
+}
@@ -0,0 +1,41 @@
+package streams
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// StreamService defines the stream service.
+type StreamService interface {
+	Subscribe(ctx context.Context, actor access.Actor, data SubscriptionData) (Subscription, error)
+}
+
+// SubscriptionData defines the subscription data. It is used to subscribe to the stream of
+// a model.
+//
+// A subscriber resuming an interrupted stream passes the ID and the epoch of the last message
+// it received to get the messages it missed. If no event types are given, all events are
+// delivered.
+type SubscriptionData struct {
+	ModelID     string
+	LastEventID uint64             // ID of the last message received (optional)
+	LastEpoch   string             // epoch of the last message received (optional)
+	Types       []models.EventType // event types to deliver (optional)
+}
+
+// Subscription represents the subscription of a subscriber to the stream of a model.
+//
+// Messages returns the channel the messages are delivered on. The channel is closed when
+// the subscription ends; Err then reports why, or nil if it was closed by the subscriber
+// or the mesh was deleted. Missed reports if the messages following the last event ID of
+// a resumed subscription are no longer available, or the stream has started over since, in
+// which case the subscriber has to reload the mesh. Subscriptions must be closed when they
+// are no longer used.
+type Subscription interface {
+	Messages() <-chan Message
+	Missed() bool
+	Err() error
+	Close()
+}
@@ -0,0 +1,2 @@
+// Package service implements the stream service.
+package service
@@ -0,0 +1,53 @@
+package service
+
+import "github.com/energimind/powermesh-core/modules/streams"
+
+// ring keeps the latest messages of a stream up to its capacity.
+type ring struct {
+	messages []streams.Message
+	start    int // index of the oldest message
+}
+
+func newRing(capacity int) *ring {
+	return &ring{messages: make([]streams.Message, 0, capacity)}
+}
+
+// push adds the message, replacing the oldest one if the ring is full.
+func (r *ring) push(msg streams.Message) {
+	if cap(r.messages) == 0 {
+		return
+	}
+
+	if len(r.messages) < cap(r.messages) {
+		r.messages = append(r.messages, msg)
+
+		return
+	}
+
+	r.messages[r.start] = msg
+	r.start = (r.start + 1) % len(r.messages)
+}
+
+// since returns the messages following the one with the given ID, the oldest first. It
+// reports false if some of them are no longer kept, or the ID is unknown.
+func (r *ring) since(id, lastID uint64) ([]streams.Message, bool) {
+	if id > lastID {
+		return nil, false
+	}
+
+	var found []streams.Message
+
+	for i := range r.messages {
+		msg := r.messages[(r.start+i)%len(r.messages)]
+
+		if msg.ID > id {
+			found = append(found, msg)
+		}
+	}
+
+	if uint64(len(found)) < lastID-id {
+		return nil, false
+	}
+
+	return found, true
+}
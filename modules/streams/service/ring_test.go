@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/streams"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ring(t *testing.T) {
+	t.Parallel()
+
+	r := newRing(3)
+
+	for id := uint64(1); id <= 5; id++ {
+		r.push(streams.Message{ID: id})
+	}
+
+	tests := map[string]struct {
+		id           uint64
+		wantIDs      []uint64
+		wantComplete bool
+	}{
+		"kept":       {id: 2, wantIDs: []uint64{3, 4, 5}, wantComplete: true},
+		"latest":     {id: 5, wantComplete: true},
+		"some-kept":  {id: 3, wantIDs: []uint64{4, 5}, wantComplete: true},
+		"dropped":    {id: 1},
+		"from-start": {id: 0},
+		"unknown":    {id: 6},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			found, complete := r.since(test.id, 5)
+
+			require.Equal(t, test.wantComplete, complete)
+
+			var ids []uint64
+
+			for _, msg := range found {
+				ids = append(ids, msg.ID)
+			}
+
+			require.Equal(t, test.wantIDs, ids)
+		})
+	}
+
+	t.Run("no-capacity", func(t *testing.T) {
+		empty := newRing(0)
+		empty.push(streams.Message{ID: 1})
+
+		_, complete := empty.since(0, 1)
+
+		require.False(t, complete)
+	})
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/streams"
+)
+
+const (
+	// defaultBufferSize is the default number of messages buffered for each subscriber.
+	defaultBufferSize = 64
+
+	// defaultHistorySize is the default number of messages kept for each model.
+	defaultHistorySize = 256
+
+	// defaultIdleTimeout is the default time the stream of a model without subscribers is
+	// kept after its last activity.
+	defaultIdleTimeout = 10 * time.Minute
+)
+
+// authorizer defines the external authorizer of the actions of actors.
+// It is implemented by the authorizer of the authz package.
+type authorizer interface {
+	Authorize(
+		ctx context.Context,
+		actor access.Actor,
+		resourceType permissions.ResourceType,
+		resourceID string,
+		permission access.Permission,
+	) error
+}
+
+// StreamService implements the stream service.
+//
+// It implements the streams.StreamService interface.
+//
+// The service receives the mesh events as a mesh listener of the mesh service and fans them
+// out to the subscribers of their models. It numbers the events of each model and keeps the
+// latest of them, so interrupted streams can be resumed. Delivering never blocks the mesh
+// service: every subscriber has a bounded buffer, and a subscriber whose buffer is full is
+// dropped with a state error. It can resume its stream from the last message it received.
+//
+// The streams are kept in memory. A stream without subscribers is dropped once it has been
+// idle for the idle timeout, so subscribers can resume it after short interruptions. Every
+// stream has a random epoch that comes with its messages: the event IDs start over when a
+// stream is dropped or the service is restarted, and subscribers resuming a stream with
+// another epoch are told they missed messages.
+//
+// We do not wrap the errors returned by the authorizer because they are already
+// packed as domain errors. Therefore, we disable the wrapcheck linter for these calls.
+type StreamService struct {
+	authz       authorizer
+	bufferSize  int
+	historySize int
+	idleTimeout time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	streams     map[string]*modelStream
+	lastSweep   time.Time
+}
+
+// Ensure StreamService implements the streams.StreamService interface.
+var _ streams.StreamService = (*StreamService)(nil)
+
+// NewStreamService creates a new stream service.
+func NewStreamService(authz authorizer, opts ...Option) *StreamService {
+	svc := &StreamService{
+		authz:       authz,
+		bufferSize:  defaultBufferSize,
+		historySize: defaultHistorySize,
+		idleTimeout: defaultIdleTimeout,
+		now:         time.Now,
+		streams:     map[string]*modelStream{},
+	}
+
+	for _, opt := range opts {
+		opt(svc)
+	}
+
+	return svc
+}
+
+// modelStream holds the stream of a model.
+type modelStream struct {
+	epoch       string
+	lastID      uint64
+	lastActive  time.Time
+	history     *ring
+	subscribers map[*subscription]struct{}
+}
+
+// Subscribe implements the streams.StreamService interface.
+// The actor needs read access to the model.
+//
+// A resumed subscription first gets the missed messages. If they are no longer kept, the
+// stream has another epoch, or they do not fit in the buffer of the subscriber, the
+// subscription reports them as missed and only gets the messages that follow.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *StreamService) Subscribe(
+	ctx context.Context,
+	actor access.Actor,
+	data streams.SubscriptionData,
+) (streams.Subscription, error) {
+	if err := validateSubscriptionData(data); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, actor, data.ModelID, access.PermissionRead); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.sweep(now)
+
+	stream := s.stream(data.ModelID)
+	stream.lastActive = now
+
+	sub := &subscription{
+		svc:      s,
+		modelID:  data.ModelID,
+		types:    data.Types,
+		messages: make(chan streams.Message, s.bufferSize),
+	}
+
+	if data.LastEventID > 0 {
+		missed, complete := stream.history.since(data.LastEventID, stream.lastID)
+		complete = complete && data.LastEpoch == stream.epoch
+
+		missed = slices.DeleteFunc(missed, func(msg streams.Message) bool { return !sub.accepts(msg) })
+
+		if complete && len(missed) <= s.bufferSize {
+			for _, msg := range missed {
+				sub.messages <- msg
+			}
+		} else {
+			sub.missed = true
+		}
+	}
+
+	stream.subscribers[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// HandleMeshEvent delivers the event to the subscribers of its model. Once the mesh is
+// deleted, the subscriptions to it end and its stream is dropped.
+func (s *StreamService) HandleMeshEvent(_ context.Context, event models.MeshEvent) error {
+	modelID := event.Updates.ModelID
+	if modelID == "" {
+		modelID = event.Deletes.ModelID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.sweep(now)
+
+	stream := s.stream(modelID)
+	stream.lastID++
+	stream.lastActive = now
+
+	msg := streams.Message{ID: stream.lastID, Epoch: stream.epoch, Event: event}
+
+	stream.history.push(msg)
+
+	for sub := range stream.subscribers {
+		if !sub.accepts(msg) {
+			continue
+		}
+
+		select {
+		case sub.messages <- msg:
+		default:
+			s.end(sub, errorz.NewStateError("subscriber of model %s fell behind at event %d", modelID, msg.ID))
+		}
+	}
+
+	if event.Type == models.MeshDeleted {
+		for sub := range stream.subscribers {
+			s.end(sub, nil)
+		}
+
+		delete(s.streams, modelID)
+	}
+
+	return nil
+}
+
+// stream returns the stream of the model, creating it if needed.
+// It must be called with the lock held.
+func (s *StreamService) stream(modelID string) *modelStream {
+	stream, ok := s.streams[modelID]
+	if !ok {
+		stream = &modelStream{
+			epoch:       newEpoch(),
+			lastActive:  s.now(),
+			history:     newRing(s.historySize),
+			subscribers: map[*subscription]struct{}{},
+		}
+		s.streams[modelID] = stream
+	}
+
+	return stream
+}
+
+// sweep drops the streams without subscribers that have been idle for the idle timeout.
+// Sweeps are at least the idle timeout apart, so they do not slow down every call.
+// It must be called with the lock held.
+func (s *StreamService) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.idleTimeout {
+		return
+	}
+
+	s.lastSweep = now
+
+	for modelID, stream := range s.streams {
+		if len(stream.subscribers) == 0 && now.Sub(stream.lastActive) >= s.idleTimeout {
+			delete(s.streams, modelID)
+		}
+	}
+}
+
+// end ends the subscription for the reason, if it has not ended yet.
+// It must be called with the lock held.
+func (s *StreamService) end(sub *subscription, err error) {
+	if sub.ended {
+		return
+	}
+
+	sub.ended = true
+	sub.err = err
+
+	close(sub.messages)
+
+	if stream, ok := s.streams[sub.modelID]; ok {
+		delete(stream.subscribers, sub)
+
+		stream.lastActive = s.now()
+	}
+}
+
+// newEpoch returns a random epoch for a new stream.
+func newEpoch() string {
+	b := make([]byte, 8)
+
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// authorize checks that the actor has the permission on the model.
+//
+//nolint:wrapcheck // see comment in the header
+func (s *StreamService) authorize(
+	ctx context.Context,
+	actor access.Actor,
+	modelID string,
+	permission access.Permission,
+) error {
+	return s.authz.Authorize(ctx, actor, permissions.ResourceTypeModel, modelID, permission)
+}
+
+// subscription implements the streams.Subscription interface.
+// Its state is guarded by the lock of the service.
+type subscription struct {
+	svc      *StreamService
+	modelID  string
+	types    []models.EventType
+	messages chan streams.Message
+	missed   bool
+	ended    bool
+	err      error
+}
+
+// Messages implements the streams.Subscription interface.
+func (s *subscription) Messages() <-chan streams.Message {
+	return s.messages
+}
+
+// Missed implements the streams.Subscription interface.
+func (s *subscription) Missed() bool {
+	return s.missed
+}
+
+// Err implements the streams.Subscription interface.
+func (s *subscription) Err() error {
+	s.svc.mu.Lock()
+	defer s.svc.mu.Unlock()
+
+	return s.err
+}
+
+// Close implements the streams.Subscription interface.
+func (s *subscription) Close() {
+	s.svc.mu.Lock()
+	defer s.svc.mu.Unlock()
+
+	s.svc.end(s, nil)
+}
+
+// accepts checks if the message is to be delivered to the subscriber.
+func (s *subscription) accepts(msg streams.Message) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, msg.Event.Type)
+}
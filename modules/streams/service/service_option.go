@@ -0,0 +1,29 @@
+package service
+
+import "time"
+
+// Option defines the option for the service.
+type Option func(service *StreamService)
+
+// WithBufferSize sets the number of messages buffered for each subscriber. Subscribers
+// falling further behind are dropped.
+func WithBufferSize(size int) Option {
+	return func(s *StreamService) {
+		s.bufferSize = size
+	}
+}
+
+// WithIdleTimeout sets the time the stream of a model without subscribers is kept after
+// its last activity. Subscribers can resume a stream as long as it is kept.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(s *StreamService) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithHistorySize sets the number of messages kept for each model to resume streams from.
+func WithHistorySize(size int) Option {
+	return func(s *StreamService) {
+		s.historySize = size
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions/authz"
+	"github.com/energimind/powermesh-core/modules/streams"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamService_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actor        access.Actor
+		data         streams.SubscriptionData
+		bindingError bool
+		wantErr      error
+	}{
+		"invalid-data": {
+			actor:   viewerActor,
+			data:    streams.SubscriptionData{},
+			wantErr: errorz.ValidationError{},
+		},
+		"invalid-type": {
+			actor:   viewerActor,
+			data:    streams.SubscriptionData{ModelID: validModelID, Types: []models.EventType{models.ModelCreated}},
+			wantErr: errorz.ValidationError{},
+		},
+		"no-binding": {
+			actor:   otherActor,
+			data:    streams.SubscriptionData{ModelID: validModelID},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"no-permission": {
+			actor:   revokedActor,
+			data:    streams.SubscriptionData{ModelID: validModelID},
+			wantErr: errorz.AccessDeniedError{},
+		},
+		"binding-error": {
+			actor:        viewerActor,
+			data:         streams.SubscriptionData{ModelID: validModelID},
+			bindingError: true,
+			wantErr:      errorz.StoreError{},
+		},
+		"success-viewer": {
+			actor: viewerActor,
+			data:  streams.SubscriptionData{ModelID: validModelID},
+		},
+		"success-admin": {
+			actor:        adminActor,
+			data:         streams.SubscriptionData{ModelID: otherModelID},
+			bindingError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var tb testBindingProvider
+
+			if test.bindingError {
+				tb.forcedError = errorz.NewStoreError("forced-error")
+			}
+
+			svc := NewStreamService(authz.NewAuthorizer(tb))
+
+			sub, err := svc.Subscribe(context.Background(), test.actor, test.data)
+
+			if test.wantErr != nil {
+				require.IsType(t, test.wantErr, err)
+				require.Nil(t, sub)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.False(t, sub.Missed())
+			require.Empty(t, received(sub))
+
+			sub.Close()
+		})
+	}
+}
+
+func TestStreamService_HandleMeshEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("delivery", func(t *testing.T) {
+		svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}))
+
+		all, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{ModelID: validModelID})
+		require.NoError(t, err)
+
+		deletes, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{
+			ModelID: validModelID,
+			Types:   []models.EventType{models.MeshContentsDeleted},
+		})
+		require.NoError(t, err)
+
+		publish(t, svc,
+			testEvent(models.MeshContentsCreated, validModelID),
+			testEvent(models.MeshContentsCreated, otherModelID),
+			testEvent(models.MeshContentsDeleted, validModelID),
+		)
+
+		require.Equal(t, []uint64{1, 2}, received(all))
+		require.Equal(t, []uint64{2}, received(deletes))
+	})
+
+	t.Run("slow-subscriber", func(t *testing.T) {
+		svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}), WithBufferSize(2))
+
+		sub, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{ModelID: validModelID})
+		require.NoError(t, err)
+
+		publish(t, svc,
+			testEvent(models.MeshContentsCreated, validModelID),
+			testEvent(models.MeshContentsUpdated, validModelID),
+			testEvent(models.MeshContentsDeleted, validModelID),
+		)
+
+		require.Equal(t, []uint64{1, 2}, received(sub))
+		require.IsType(t, errorz.StateError{}, sub.Err())
+
+		sub.Close()
+	})
+
+	t.Run("mesh-deleted", func(t *testing.T) {
+		svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}))
+
+		sub, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{ModelID: validModelID})
+		require.NoError(t, err)
+
+		publish(t, svc, testEvent(models.MeshDeleted, validModelID))
+
+		msg := <-sub.Messages()
+
+		require.Equal(t, uint64(1), msg.ID)
+		require.Empty(t, received(sub))
+		require.NoError(t, sub.Err())
+
+		// the stream starts over with another epoch, so earlier event IDs are unknown
+		resumed, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{
+			ModelID:     validModelID,
+			LastEventID: msg.ID,
+			LastEpoch:   msg.Epoch,
+		})
+		require.NoError(t, err)
+		require.True(t, resumed.Missed())
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}))
+
+		sub, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{ModelID: validModelID})
+		require.NoError(t, err)
+
+		sub.Close()
+		sub.Close()
+
+		publish(t, svc, testEvent(models.MeshContentsCreated, validModelID))
+
+		_, ok := <-sub.Messages()
+
+		require.False(t, ok)
+		require.NoError(t, sub.Err())
+	})
+}
+
+func TestStreamService_resume(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data       streams.SubscriptionData
+		otherEpoch bool
+		bufferSize int
+		wantIDs    []uint64
+		wantMissed bool
+	}{
+		"up-to-date": {
+			data:    streams.SubscriptionData{ModelID: validModelID, LastEventID: 5},
+			wantIDs: []uint64{6},
+		},
+		"kept": {
+			data:    streams.SubscriptionData{ModelID: validModelID, LastEventID: 3},
+			wantIDs: []uint64{4, 5, 6},
+		},
+		"kept-filtered": {
+			data: streams.SubscriptionData{
+				ModelID:     validModelID,
+				LastEventID: 2,
+				Types:       []models.EventType{models.MeshContentsDeleted},
+			},
+			wantIDs: []uint64{4, 6},
+		},
+		"dropped": {
+			data:       streams.SubscriptionData{ModelID: validModelID, LastEventID: 1},
+			wantIDs:    []uint64{6},
+			wantMissed: true,
+		},
+		"other-epoch": {
+			data:       streams.SubscriptionData{ModelID: validModelID, LastEventID: 5},
+			otherEpoch: true,
+			wantIDs:    []uint64{6},
+			wantMissed: true,
+		},
+		"unknown": {
+			data:       streams.SubscriptionData{ModelID: validModelID, LastEventID: 9},
+			wantIDs:    []uint64{6},
+			wantMissed: true,
+		},
+		"beyond-buffer": {
+			data:       streams.SubscriptionData{ModelID: validModelID, LastEventID: 2},
+			bufferSize: 2,
+			wantIDs:    []uint64{6},
+			wantMissed: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bufferSize := test.bufferSize
+			if bufferSize == 0 {
+				bufferSize = 3
+			}
+
+			svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}), WithHistorySize(3), WithBufferSize(bufferSize))
+
+			publish(t, svc,
+				testEvent(models.MeshContentsCreated, validModelID),
+				testEvent(models.MeshContentsUpdated, validModelID),
+				testEvent(models.MeshContentsCreated, validModelID),
+				testEvent(models.MeshContentsDeleted, validModelID),
+				testEvent(models.MeshContentsUpdated, validModelID),
+			)
+
+			if !test.otherEpoch {
+				test.data.LastEpoch = svc.streams[validModelID].epoch
+			}
+
+			sub, err := svc.Subscribe(context.Background(), viewerActor, test.data)
+			require.NoError(t, err)
+
+			publish(t, svc, testEvent(models.MeshContentsDeleted, validModelID))
+
+			require.Equal(t, test.wantMissed, sub.Missed())
+			require.Equal(t, test.wantIDs, received(sub))
+		})
+	}
+}
+
+func TestStreamService_idle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := NewStreamService(authz.NewAuthorizer(testBindingProvider{}), WithIdleTimeout(time.Minute))
+	svc.now = func() time.Time { return now }
+
+	kept, err := svc.Subscribe(ctx, viewerActor, streams.SubscriptionData{ModelID: validModelID})
+	require.NoError(t, err)
+
+	closed, err := svc.Subscribe(ctx, adminActor, streams.SubscriptionData{ModelID: otherModelID})
+	require.NoError(t, err)
+
+	publish(t, svc,
+		testEvent(models.MeshContentsCreated, validModelID),
+		testEvent(models.MeshContentsCreated, otherModelID),
+	)
+
+	msg := <-closed.Messages()
+
+	closed.Close()
+
+	// a stream without subscribers is kept until it has been idle for the timeout
+	now = now.Add(30 * time.Second)
+
+	publish(t, svc, testEvent(models.MeshContentsUpdated, validModelID))
+
+	require.Contains(t, svc.streams, otherModelID)
+
+	now = now.Add(time.Minute)
+
+	publish(t, svc, testEvent(models.MeshContentsUpdated, validModelID))
+
+	require.Contains(t, svc.streams, validModelID)
+	require.NotContains(t, svc.streams, otherModelID)
+	require.Equal(t, []uint64{1, 2, 3}, received(kept))
+
+	resumed, err := svc.Subscribe(ctx, adminActor, streams.SubscriptionData{
+		ModelID:     otherModelID,
+		LastEventID: msg.ID,
+		LastEpoch:   msg.Epoch,
+	})
+	require.NoError(t, err)
+	require.True(t, resumed.Missed())
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/permissions"
+	"github.com/energimind/powermesh-core/modules/streams"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminActor   = access.Actor{UserID: "admin", Role: access.RoleAdmin}
+	viewerActor  = access.Actor{UserID: "viewer", Role: access.RoleCreator}
+	revokedActor = access.Actor{UserID: "revoked", Role: access.RoleCreator}
+	otherActor   = access.Actor{UserID: "other", Role: access.RoleCreator}
+	validModelID = "model1"
+	otherModelID = "model2"
+)
+
+// testBindingProvider grants the viewer the guest role and the revoked actor no role on the
+// valid model.
+type testBindingProvider struct {
+	forcedError error
+}
+
+func (p testBindingProvider) GetRoleBinding(
+	_ context.Context,
+	query permissions.RoleBindingQuery,
+) (permissions.RoleBinding, error) {
+	if p.forcedError != nil {
+		return permissions.RoleBinding{}, p.forcedError
+	}
+
+	roles := map[string]access.Role{
+		viewerActor.UserID:  access.RoleGuest,
+		revokedActor.UserID: access.RoleNone,
+	}
+
+	role, ok := roles[query.UserID]
+	if !ok || query.ResourceID != validModelID || query.ResourceType != permissions.ResourceTypeModel {
+		return permissions.RoleBinding{}, errorz.NewNotFoundError("role binding not found")
+	}
+
+	return permissions.RoleBinding{
+		UserID:       query.UserID,
+		ResourceID:   query.ResourceID,
+		ResourceType: query.ResourceType,
+		Role:         role,
+	}, nil
+}
+
+// testEvent returns a mesh contents event of the model.
+func testEvent(eventType models.EventType, modelID string) models.MeshEvent {
+	event := models.MeshEvent{
+		EventHeader: models.EventHeader{Type: eventType, Actor: otherActor},
+	}
+
+	if eventType == models.MeshContentsDeleted {
+		event.Deletes = models.Mesh{ModelID: modelID}
+	} else {
+		event.Updates = models.Mesh{ModelID: modelID}
+	}
+
+	return event
+}
+
+// publish passes the events to the service.
+func publish(t *testing.T, svc *StreamService, events ...models.MeshEvent) {
+	t.Helper()
+
+	for _, event := range events {
+		require.NoError(t, svc.HandleMeshEvent(context.Background(), event))
+	}
+}
+
+// received drains the messages buffered for the subscription and returns their IDs.
+func received(sub streams.Subscription) []uint64 {
+	ids := []uint64{}
+
+	for {
+		select {
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return ids
+			}
+
+			ids = append(ids, msg.ID)
+		default:
+			return ids
+		}
+	}
+}
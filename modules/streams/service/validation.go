@@ -0,0 +1,34 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/streams"
+)
+
+func requireString(value, name string) error {
+	if value == "" {
+		return errorz.NewValidationError("%s is required", name)
+	}
+
+	return nil
+}
+
+func validateModelID(id string) error {
+	return requireString(id, "model id")
+}
+
+func validateSubscriptionData(data streams.SubscriptionData) error {
+	if err := validateModelID(data.ModelID); err != nil {
+		return err
+	}
+
+	for _, t := range data.Types {
+		if !slices.Contains(streams.MeshEventTypes, t) {
+			return errorz.NewValidationError("invalid event type: %s", t)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/streams"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_requireString(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, requireString("value", "name"))
+	require.Error(t, requireString("", "name"))
+	require.IsType(t, errorz.ValidationError{}, requireString("", "name"))
+}
+
+func Test_validateModelID(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateModelID("1"))
+	require.Error(t, validateModelID(""))
+}
+
+func Test_validateSubscriptionData(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateSubscriptionData(streams.SubscriptionData{ModelID: "1"}))
+	require.NoError(t, validateSubscriptionData(streams.SubscriptionData{
+		ModelID: "1",
+		Types:   streams.MeshEventTypes,
+	}))
+	require.Error(t, validateSubscriptionData(streams.SubscriptionData{}))
+	require.Error(t, validateSubscriptionData(streams.SubscriptionData{
+		ModelID: "1",
+		Types:   []models.EventType{models.ModelCreated},
+	}))
+}
@@ -0,0 +1,3 @@
+// Package sse provides an HTTP handler streaming the mesh events of a model as
+// Server-Sent Events.
+package sse
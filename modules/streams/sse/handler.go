@@ -0,0 +1,229 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/streams"
+)
+
+const (
+	// defaultHeartbeat is the default interval of the heartbeat comments.
+	defaultHeartbeat = 30 * time.Second
+
+	// headerLastEventID is the header a reconnecting event source sends the last event ID in.
+	headerLastEventID = "Last-Event-ID"
+
+	// The query parameters of a stream request.
+	paramModelID     = "modelId"
+	paramTypes       = "types"
+	paramLastEventID = "lastEventId"
+
+	// The SSE event names that are not mesh event types.
+	eventReset = "reset"
+	eventError = "error"
+)
+
+// streamService defines the external service the events are streamed from.
+// It is implemented by the stream service.
+type streamService interface {
+	Subscribe(ctx context.Context, actor access.Actor, data streams.SubscriptionData) (streams.Subscription, error)
+}
+
+// Handler streams the mesh events of a model as Server-Sent Events.
+//
+// The model is selected by the modelId query parameter, and the types parameter takes a
+// comma-separated list of the event types to stream. The actor is taken from the request
+// context, see the accessctx package; authenticating the request is up to the middleware
+// in front of the handler.
+//
+// Every message is sent with its epoch and ID, as "<epoch>-<id>", the mesh event type as the
+// SSE event name, and the event as JSON data. Event sources reconnecting with the
+// Last-Event-ID header, or the lastEventId query parameter, resume the stream. If the missed
+// events are no longer available, or the stream has started over since, a reset event is
+// sent first, telling the client to reload the mesh. If the client falls behind, an error
+// event is sent and the stream is closed; the client can reconnect and resume.
+type Handler struct {
+	streams   streamService
+	heartbeat time.Duration
+}
+
+// NewHandler creates a new handler streaming the events of the stream service.
+func NewHandler(svc streamService, opts ...Option) *Handler {
+	h := &Handler{
+		streams:   svc,
+		heartbeat: defaultHeartbeat,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	data, err := subscriptionData(r)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+
+		return
+	}
+
+	sub, err := h.streams.Subscribe(r.Context(), accessctx.Actor(r.Context()), data)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode(err))
+
+		return
+	}
+
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Missed() {
+		writeEvent(w, "", eventReset, "{}")
+	}
+
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				if err := sub.Err(); err != nil {
+					writeEvent(w, "", eventError, quote(err.Error()))
+					flusher.Flush()
+				}
+
+				return
+			}
+
+			payload, err := json.Marshal(toEventPayload(msg.Event))
+			if err != nil {
+				writeEvent(w, "", eventError, quote(err.Error()))
+				flusher.Flush()
+
+				return
+			}
+
+			writeEvent(w, formatEventID(msg), string(msg.Event.Type), string(payload))
+		}
+
+		flusher.Flush()
+	}
+}
+
+// subscriptionData reads the subscription data from the request.
+func subscriptionData(r *http.Request) (streams.SubscriptionData, error) {
+	query := r.URL.Query()
+
+	data := streams.SubscriptionData{
+		ModelID: query.Get(paramModelID),
+	}
+
+	if types := query.Get(paramTypes); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			data.Types = append(data.Types, models.EventType(strings.TrimSpace(t)))
+		}
+	}
+
+	lastEventID := r.Header.Get(headerLastEventID)
+	if lastEventID == "" {
+		lastEventID = query.Get(paramLastEventID)
+	}
+
+	if lastEventID != "" {
+		epoch, id, err := parseEventID(lastEventID)
+		if err != nil {
+			return streams.SubscriptionData{}, err
+		}
+
+		data.LastEpoch = epoch
+		data.LastEventID = id
+	}
+
+	return data, nil
+}
+
+// formatEventID returns the SSE event ID of the message.
+func formatEventID(msg streams.Message) string {
+	return msg.Epoch + "-" + strconv.FormatUint(msg.ID, 10)
+}
+
+// parseEventID returns the epoch and the ID of an SSE event ID. An ID without an epoch is
+// taken as a message ID of an unknown epoch.
+func parseEventID(eventID string) (string, uint64, error) {
+	epoch, rawID := "", eventID
+
+	if i := strings.LastIndexByte(eventID, '-'); i >= 0 {
+		epoch, rawID = eventID[:i], eventID[i+1:]
+	}
+
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return "", 0, errorz.NewValidationError("invalid last event id: %s", eventID)
+	}
+
+	return epoch, id, nil
+}
+
+// writeEvent writes an SSE event. The data must not contain line breaks.
+func writeEvent(w http.ResponseWriter, id, event, data string) {
+	if id != "" {
+		_, _ = fmt.Fprintf(w, "id: %s\n", id)
+	}
+
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// quote returns the text as a JSON string.
+func quote(text string) string {
+	quoted, _ := json.Marshal(text)
+
+	return string(quoted)
+}
+
+// statusCode returns the HTTP status code reporting the error.
+func statusCode(err error) int {
+	switch {
+	case errorz.IsValidationError(err), errorz.IsBadRequestError(err):
+		return http.StatusBadRequest
+	case errorz.IsUnauthorizedError(err):
+		return http.StatusUnauthorized
+	case errorz.IsAccessDeniedError(err):
+		return http.StatusForbidden
+	case errorz.IsNotFoundError(err):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
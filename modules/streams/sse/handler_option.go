@@ -0,0 +1,13 @@
+package sse
+
+import "time"
+
+// Option defines the option for the handler.
+type Option func(handler *Handler)
+
+// WithHeartbeat sets the interval of the comments sent to keep idle streams open.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(h *Handler) {
+		h.heartbeat = interval
+	}
+}
@@ -0,0 +1,224 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/energimind/powermesh-core/access"
+	"github.com/energimind/powermesh-core/accessctx"
+	"github.com/energimind/powermesh-core/errorz"
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/energimind/powermesh-core/modules/streams"
+	"github.com/stretchr/testify/require"
+)
+
+var viewerActor = access.Actor{UserID: "viewer", Role: access.RoleCreator}
+
+// testSubscription delivers the buffered messages and ends with the error.
+type testSubscription struct {
+	messages chan streams.Message
+	missed   bool
+	err      error
+	closed   bool
+}
+
+// Ensure that the testSubscription implements the streams.Subscription interface.
+var _ streams.Subscription = (*testSubscription)(nil)
+
+func newTestSubscription(missed bool, err error, msgs ...streams.Message) *testSubscription {
+	s := &testSubscription{
+		messages: make(chan streams.Message, len(msgs)),
+		missed:   missed,
+		err:      err,
+	}
+
+	for _, msg := range msgs {
+		s.messages <- msg
+	}
+
+	close(s.messages)
+
+	return s
+}
+
+func (s *testSubscription) Messages() <-chan streams.Message { return s.messages }
+func (s *testSubscription) Missed() bool                     { return s.missed }
+func (s *testSubscription) Err() error                       { return s.err }
+func (s *testSubscription) Close()                           { s.closed = true }
+
+// testStreamService records the subscription data and returns the subscription.
+type testStreamService struct {
+	sub       *testSubscription
+	forcedErr error
+	actor     access.Actor
+	data      streams.SubscriptionData
+}
+
+// Ensure that the testStreamService implements the streamService interface.
+var _ streamService = (*testStreamService)(nil)
+
+func (s *testStreamService) Subscribe(
+	_ context.Context,
+	actor access.Actor,
+	data streams.SubscriptionData,
+) (streams.Subscription, error) {
+	s.actor = actor
+	s.data = data
+
+	if s.forcedErr != nil {
+		return nil, s.forcedErr
+	}
+
+	return s.sub, nil
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	created := streams.Message{
+		ID:    7,
+		Epoch: "e1",
+		Event: models.MeshEvent{
+			EventHeader: models.EventHeader{
+				Type:      models.MeshContentsCreated,
+				Actor:     viewerActor,
+				Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Updates: models.Mesh{
+				ModelID: "model1",
+				Nodes:   map[string]models.Node{"n1": {ID: "n1", Kind: "bus"}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		target     string
+		lastID     string
+		sub        *testSubscription
+		forcedErr  error
+		wantStatus int
+		wantData   streams.SubscriptionData
+		wantBody   string
+	}{
+		"invalid-last-event-id": {
+			target:     "/stream?modelId=model1",
+			lastID:     "abc",
+			wantStatus: http.StatusBadRequest,
+		},
+		"access-denied": {
+			target:     "/stream?modelId=model1",
+			forcedErr:  errorz.NewAccessDeniedError("denied"),
+			wantStatus: http.StatusForbidden,
+			wantData:   streams.SubscriptionData{ModelID: "model1"},
+		},
+		"invalid-type": {
+			target:     "/stream?modelId=model1&types=model.created",
+			forcedErr:  errorz.NewValidationError("invalid event type"),
+			wantStatus: http.StatusBadRequest,
+			wantData:   streams.SubscriptionData{ModelID: "model1", Types: []models.EventType{models.ModelCreated}},
+		},
+		"messages": {
+			target:     "/stream?modelId=model1&types=mesh-contents.created,%20mesh-contents.deleted",
+			sub:        newTestSubscription(false, nil, created),
+			wantStatus: http.StatusOK,
+			wantData: streams.SubscriptionData{
+				ModelID: "model1",
+				Types:   []models.EventType{models.MeshContentsCreated, models.MeshContentsDeleted},
+			},
+			wantBody: "id: e1-7\nevent: mesh-contents.created\n" +
+				`data: {"type":"mesh-contents.created","actorId":"viewer","timestamp":"2024-01-01T00:00:00Z",` +
+				`"updates":{"modelId":"model1","nodes":{"n1":{"id":"n1","kind":"bus"}}},"deletes":{}}` + "\n\n",
+		},
+		"invalid-last-event-id-with-epoch": {
+			target:     "/stream?modelId=model1",
+			lastID:     "e1-",
+			wantStatus: http.StatusBadRequest,
+		},
+		"resumed-missed": {
+			target:     "/stream?modelId=model1",
+			lastID:     "e1-5",
+			sub:        newTestSubscription(true, nil),
+			wantStatus: http.StatusOK,
+			wantData:   streams.SubscriptionData{ModelID: "model1", LastEventID: 5, LastEpoch: "e1"},
+			wantBody:   "event: reset\ndata: {}\n\n",
+		},
+		"resumed-by-query": {
+			target:     "/stream?modelId=model1&lastEventId=e1-5",
+			sub:        newTestSubscription(false, nil),
+			wantStatus: http.StatusOK,
+			wantData:   streams.SubscriptionData{ModelID: "model1", LastEventID: 5, LastEpoch: "e1"},
+		},
+		"resumed-without-epoch": {
+			target:     "/stream?modelId=model1&lastEventId=5",
+			sub:        newTestSubscription(false, nil),
+			wantStatus: http.StatusOK,
+			wantData:   streams.SubscriptionData{ModelID: "model1", LastEventID: 5},
+		},
+		"fell-behind": {
+			target:     "/stream?modelId=model1",
+			sub:        newTestSubscription(false, errorz.NewStateError("fell behind")),
+			wantStatus: http.StatusOK,
+			wantData:   streams.SubscriptionData{ModelID: "model1"},
+			wantBody:   "event: error\ndata: \"fell behind\"\n\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc := &testStreamService{sub: test.sub, forcedErr: test.forcedErr}
+
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			req = req.WithContext(accessctx.WithActor(req.Context(), viewerActor))
+
+			if test.lastID != "" {
+				req.Header.Set(headerLastEventID, test.lastID)
+			}
+
+			rec := httptest.NewRecorder()
+
+			NewHandler(svc).ServeHTTP(rec, req)
+
+			require.Equal(t, test.wantStatus, rec.Code)
+			require.Equal(t, test.wantData, svc.data)
+
+			if test.sub == nil {
+				return
+			}
+
+			require.Equal(t, viewerActor, svc.actor)
+			require.True(t, test.sub.closed)
+			require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+			require.Equal(t, test.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	sub := &testSubscription{messages: make(chan streams.Message)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/stream?modelId=model1", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	NewHandler(&testStreamService{sub: sub}, WithHeartbeat(10*time.Millisecond)).ServeHTTP(rec, req)
+
+	require.Contains(t, rec.Body.String(), ": heartbeat\n\n")
+	require.True(t, sub.closed)
+}
+
+func Test_statusCode(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, http.StatusBadRequest, statusCode(errorz.NewValidationError("invalid")))
+	require.Equal(t, http.StatusUnauthorized, statusCode(errorz.NewUnauthorizedError("unauthorized")))
+	require.Equal(t, http.StatusForbidden, statusCode(errorz.NewAccessDeniedError("denied")))
+	require.Equal(t, http.StatusNotFound, statusCode(errorz.NewNotFoundError("not found")))
+	require.Equal(t, http.StatusInternalServerError, statusCode(errorz.NewStoreError("failed")))
+}
@@ -0,0 +1,89 @@
+package sse
+
+import (
+	"time"
+
+	"github.com/energimind/powermesh-core/modules/models"
+)
+
+// eventPayload is the JSON data of a streamed mesh event.
+type eventPayload struct {
+	Type      models.EventType `json:"type"`
+	ActorID   string           `json:"actorId"`
+	Timestamp time.Time        `json:"timestamp"`
+	Updates   meshPayload      `json:"updates"`
+	Deletes   meshPayload      `json:"deletes"`
+	Profiles  []profilePayload `json:"profiles,omitempty"`
+}
+
+// meshPayload is the JSON data of the elements of a streamed mesh event.
+type meshPayload struct {
+	ModelID   string                     `json:"modelId,omitempty"`
+	Code      string                     `json:"code,omitempty"`
+	Nodes     map[string]nodePayload     `json:"nodes,omitempty"`
+	Relations map[string]relationPayload `json:"relations,omitempty"`
+}
+
+// nodePayload is the JSON data of a node.
+type nodePayload struct {
+	ID    string         `json:"id"`
+	Kind  string         `json:"kind,omitempty"`
+	Code  string         `json:"code,omitempty"`
+	Props models.PropBag `json:"props,omitempty"`
+}
+
+// relationPayload is the JSON data of a relation.
+type relationPayload struct {
+	ID    string         `json:"id"`
+	Kind  string         `json:"kind,omitempty"`
+	From  string         `json:"from,omitempty"`
+	To    string         `json:"to,omitempty"`
+	Props models.PropBag `json:"props,omitempty"`
+}
+
+// profilePayload is the JSON data of a changed profile.
+type profilePayload struct {
+	NodeID string `json:"nodeId"`
+	Series string `json:"series"`
+}
+
+func toEventPayload(event models.MeshEvent) eventPayload {
+	payload := eventPayload{
+		Type:      event.Type,
+		ActorID:   event.Actor.UserID,
+		Timestamp: event.Timestamp,
+		Updates:   toMeshPayload(event.Updates),
+		Deletes:   toMeshPayload(event.Deletes),
+	}
+
+	for _, p := range event.Profiles {
+		payload.Profiles = append(payload.Profiles, profilePayload{NodeID: p.NodeID, Series: p.Series})
+	}
+
+	return payload
+}
+
+func toMeshPayload(m models.Mesh) meshPayload {
+	payload := meshPayload{
+		ModelID: m.ModelID,
+		Code:    m.Code,
+	}
+
+	for id, n := range m.Nodes {
+		if payload.Nodes == nil {
+			payload.Nodes = make(map[string]nodePayload, len(m.Nodes))
+		}
+
+		payload.Nodes[id] = nodePayload{ID: n.ID, Kind: n.Kind, Code: n.Code, Props: n.Props}
+	}
+
+	for id, r := range m.Relations {
+		if payload.Relations == nil {
+			payload.Relations = make(map[string]relationPayload, len(m.Relations))
+		}
+
+		payload.Relations[id] = relationPayload{ID: r.ID, Kind: r.Kind, From: r.From, To: r.To, Props: r.Props}
+	}
+
+	return payload
+}
@@ -0,0 +1,36 @@
+package sse
+
+import (
+	"testing"
+
+	"github.com/energimind/powermesh-core/modules/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_toEventPayload(t *testing.T) {
+	t.Parallel()
+
+	event := models.MeshEvent{
+		EventHeader: models.EventHeader{Type: models.MeshProfilesUpdated, Actor: viewerActor},
+		Updates: models.Mesh{
+			ModelID:   "model1",
+			Relations: map[string]models.Relation{"r1": {ID: "r1", Kind: "line", From: "n1", To: "n2"}},
+		},
+		Deletes:  models.Mesh{ModelID: "model1"},
+		Profiles: []models.ProfileKey{{ModelID: "model1", NodeID: "n1", Series: "load"}},
+	}
+
+	require.Equal(t,
+		eventPayload{
+			Type:    models.MeshProfilesUpdated,
+			ActorID: viewerActor.UserID,
+			Updates: meshPayload{
+				ModelID:   "model1",
+				Relations: map[string]relationPayload{"r1": {ID: "r1", Kind: "line", From: "n1", To: "n2"}},
+			},
+			Deletes:  meshPayload{ModelID: "model1"},
+			Profiles: []profilePayload{{NodeID: "n1", Series: "load"}},
+		},
+		toEventPayload(event),
+	)
+}
@@ -0,0 +1,42 @@
+package mongoquery
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
+
+// CreateMany creates a new CreateManyQuery.
+func CreateMany[D, T any](coll collection, mapper mapper[T, D]) CreateManyQuery[D, T] {
+	return CreateManyQuery[D, T]{
+		coll:   coll,
+		mapper: mapper,
+	}
+}
+
+// CreateManyQuery creates multiple documents in the collection.
+type CreateManyQuery[D, T any] struct {
+	coll   collection
+	mapper mapper[T, D]
+}
+
+// Exec executes the query.
+// It inserts the documents into the collection. Nothing is inserted if there are no values.
+// It returns an error if the operation failed.
+func (q CreateManyQuery[D, T]) Exec(ctx context.Context, values []T) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	qValues := make([]any, len(values))
+
+	for i, value := range values {
+		qValues[i] = q.mapper(value)
+	}
+
+	if _, err := q.coll.InsertMany(ctx, qValues); err != nil {
+		return errorz.NewStoreError("failed to create %s: %v", q.coll.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package mongoquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCreateMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			insertMany: func() (*mongo.InsertManyResult, error) {
+				return nil, nil
+			},
+		}
+
+		require.NoError(t, CreateMany(coll, toDBPerson).Exec(context.Background(), []person{testDomainPerson}))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		coll := &mockCollection{t: t}
+
+		require.NoError(t, CreateMany(coll, toDBPerson).Exec(context.Background(), nil))
+	})
+
+	t.Run("insert-error", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			insertMany: func() (*mongo.InsertManyResult, error) {
+				return nil, forcedError{}
+			},
+		}
+
+		require.ErrorContains(t,
+			CreateMany(coll, toDBPerson).Exec(context.Background(), []person{testDomainPerson}),
+			"forced error")
+	})
+}
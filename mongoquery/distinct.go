@@ -0,0 +1,44 @@
+package mongoquery
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+)
+
+// Distinct creates a new DistinctQuery.
+func Distinct[T any](coll collection, field string) DistinctQuery[T] {
+	return DistinctQuery[T]{
+		coll:  coll,
+		field: field,
+	}
+}
+
+// DistinctQuery retrieves the distinct values of a field in the collection.
+type DistinctQuery[T any] struct {
+	coll  collection
+	field string
+}
+
+// Exec executes the query.
+// It retrieves the distinct values of the field in the documents matching the filter.
+// It returns an error if the operation failed or a value is not of the expected type.
+func (q DistinctQuery[T]) Exec(ctx context.Context, filter Filter) ([]T, error) {
+	qValues, err := q.coll.Distinct(ctx, q.field, filter.toBSON())
+	if err != nil {
+		return nil, errorz.NewStoreError("failed to find distinct %s in %s: %v", q.field, q.coll.Name(), err)
+	}
+
+	values := make([]T, len(qValues))
+
+	for i, qValue := range qValues {
+		value, ok := qValue.(T)
+		if !ok {
+			return nil, errorz.NewStoreError("unexpected %s value in %s: %v", q.field, q.coll.Name(), qValue)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,56 @@
+package mongoquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	filter := Filter{}.GT("age", 20)
+
+	t.Run("success", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			distinct: func() ([]interface{}, error) {
+				return []interface{}{"John", "Jane"}, nil
+			},
+		}
+
+		names, err := Distinct[string](coll, "name").Exec(context.Background(), filter)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"John", "Jane"}, names)
+	})
+
+	t.Run("unexpected-type", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			distinct: func() ([]interface{}, error) {
+				return []interface{}{"John", 30}, nil
+			},
+		}
+
+		names, err := Distinct[string](coll, "name").Exec(context.Background(), filter)
+
+		require.ErrorContains(t, err, "unexpected name value")
+		require.Nil(t, names)
+	})
+
+	t.Run("distinct-error", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			distinct: func() ([]interface{}, error) {
+				return nil, forcedError{}
+			},
+		}
+
+		names, err := Distinct[string](coll, "name").Exec(context.Background(), filter)
+
+		require.ErrorContains(t, err, "forced error")
+		require.Nil(t, names)
+	})
+}
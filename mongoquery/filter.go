@@ -56,11 +56,66 @@ func (f Filter) IN(key string, values any) Filter {
 	return f
 }
 
+// NIN adds a not in filter.
+func (f Filter) NIN(key string, values any) Filter {
+	f[key] = bson.M{"$nin": values}
+
+	return f
+}
+
+// REGEX adds a regular expression filter.
+// The options are passed to MongoDB as is (e.g. "i" for case-insensitive matching).
+func (f Filter) REGEX(key, pattern, options string) Filter {
+	f[key] = bson.M{"$regex": pattern, "$options": options}
+
+	return f
+}
+
+// ELEMMATCH adds a filter that matches if an element of the array field matches the given filter.
+func (f Filter) ELEMMATCH(key string, filter Filter) Filter {
+	f[key] = bson.M{"$elemMatch": filter.toBSON()}
+
+	return f
+}
+
+// NELEMMATCH adds a filter that matches if no element of the array field matches the given filter.
+// It also matches documents without the field.
+func (f Filter) NELEMMATCH(key string, filter Filter) Filter {
+	f[key] = bson.M{"$not": bson.M{"$elemMatch": filter.toBSON()}}
+
+	return f
+}
+
+// AND adds a filter that matches if all the given filters match.
+func (f Filter) AND(filters ...Filter) Filter {
+	f["$and"] = toBSONArray(filters)
+
+	return f
+}
+
+// OR adds a filter that matches if any of the given filters match.
+func (f Filter) OR(filters ...Filter) Filter {
+	f["$or"] = toBSONArray(filters)
+
+	return f
+}
+
 // toBSON converts the filter to a BSON document.
 func (f Filter) toBSON() bson.M {
 	return bson.M(f)
 }
 
+// toBSONArray converts the filters to a BSON array.
+func toBSONArray(filters []Filter) bson.A {
+	arr := make(bson.A, len(filters))
+
+	for i, f := range filters {
+		arr[i] = f.toBSON()
+	}
+
+	return arr
+}
+
 func buildFilter(key string, idOrFilter any) bson.M {
 	if f, isFilter := idOrFilter.(Filter); isFilter {
 		return f.toBSON()
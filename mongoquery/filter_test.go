@@ -49,6 +49,42 @@ func TestFilter(t *testing.T) {
 
 		require.Equal(t, Filter{"key": bson.M{"$in": []any{1, 2, 3}}}, f)
 	})
+
+	t.Run("NIN", func(t *testing.T) {
+		f := Filter{}.NIN("key", []any{1, 2, 3})
+
+		require.Equal(t, Filter{"key": bson.M{"$nin": []any{1, 2, 3}}}, f)
+	})
+
+	t.Run("REGEX", func(t *testing.T) {
+		f := Filter{}.REGEX("key", "^a", "i")
+
+		require.Equal(t, Filter{"key": bson.M{"$regex": "^a", "$options": "i"}}, f)
+	})
+
+	t.Run("ELEMMATCH", func(t *testing.T) {
+		f := Filter{}.ELEMMATCH("key", Filter{}.EQ("a", 1))
+
+		require.Equal(t, Filter{"key": bson.M{"$elemMatch": bson.M{"a": 1}}}, f)
+	})
+
+	t.Run("NELEMMATCH", func(t *testing.T) {
+		f := Filter{}.NELEMMATCH("key", Filter{}.EQ("a", 1))
+
+		require.Equal(t, Filter{"key": bson.M{"$not": bson.M{"$elemMatch": bson.M{"a": 1}}}}, f)
+	})
+
+	t.Run("AND", func(t *testing.T) {
+		f := Filter{}.AND(Filter{}.EQ("a", 1), Filter{}.EQ("b", 2))
+
+		require.Equal(t, Filter{"$and": bson.A{bson.M{"a": 1}, bson.M{"b": 2}}}, f)
+	})
+
+	t.Run("OR", func(t *testing.T) {
+		f := Filter{}.OR(Filter{}.EQ("a", 1), Filter{}.EQ("b", 2))
+
+		require.Equal(t, Filter{"$or": bson.A{bson.M{"a": 1}, bson.M{"b": 2}}}, f)
+	})
 }
 
 func TestFilter_toBSON(t *testing.T) {
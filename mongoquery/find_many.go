@@ -22,6 +22,8 @@ type FindManyQuery[D, T any] struct {
 	coll       collection
 	mapper     mapper[D, T]
 	projection bson.D
+	sort       bson.D
+	limit      int64
 	drain      func(ctx context.Context, cursor cursor) ([]D, error)
 }
 
@@ -29,15 +31,7 @@ type FindManyQuery[D, T any] struct {
 // It retrieves the documents from the collection.
 // It returns an error if the operation failed.
 func (q FindManyQuery[D, T]) Exec(ctx context.Context, filter Filter) ([]T, error) {
-	buildOptions := func() *options.FindOptions {
-		if len(q.projection) == 0 {
-			return nil
-		}
-
-		return options.Find().SetProjection(q.projection)
-	}
-
-	cur, err := q.coll.Find(ctx, filter.toBSON(), buildOptions())
+	cur, err := q.coll.Find(ctx, filter.toBSON(), q.buildOptions())
 	if err != nil {
 		return nil, errorz.NewStoreError("failed to find many from %s: %v", q.coll.Name(), err)
 	}
@@ -56,6 +50,52 @@ func (q FindManyQuery[D, T]) Exec(ctx context.Context, filter Filter) ([]T, erro
 	return values, nil
 }
 
+// WithSort adds a sort field to the query.
+// The fields are applied in the order they are added.
+func (q FindManyQuery[D, T]) WithSort(field string, descending bool) FindManyQuery[D, T] {
+	order := 1
+
+	if descending {
+		order = -1
+	}
+
+	q.sort = append(q.sort, bson.E{Key: field, Value: order})
+
+	return q
+}
+
+// WithLimit sets the maximum number of documents to return.
+// A zero limit means no limit.
+func (q FindManyQuery[D, T]) WithLimit(limit int64) FindManyQuery[D, T] {
+	q.limit = limit
+
+	return q
+}
+
+// buildOptions builds the find options for the query.
+// It returns nil if no options are set.
+func (q FindManyQuery[D, T]) buildOptions() *options.FindOptions {
+	if len(q.projection) == 0 && len(q.sort) == 0 && q.limit == 0 {
+		return nil
+	}
+
+	opts := options.Find()
+
+	if len(q.projection) > 0 {
+		opts.SetProjection(q.projection)
+	}
+
+	if len(q.sort) > 0 {
+		opts.SetSort(q.sort)
+	}
+
+	if q.limit > 0 {
+		opts.SetLimit(q.limit)
+	}
+
+	return opts
+}
+
 // WithProjection sets the projections for the query.
 func (q FindManyQuery[D, T]) WithProjection(fields ...string) FindManyQuery[D, T] {
 	for _, field := range fields {
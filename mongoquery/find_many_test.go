@@ -49,6 +49,26 @@ func TestFindMany(t *testing.T) {
 		require.Equal(t, []string{"John"}, rsp)
 	})
 
+	t.Run("success-with-sort-and-limit", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			find: func() (*mongo.Cursor, error) {
+				documents, err := mongo.NewCursorFromDocuments(findResult, nil, nil)
+
+				return documents, err
+			},
+		}
+
+		rsp, err := FindMany(coll, fromDBPerson).
+			WithSort("name", false).
+			WithSort("id", true).
+			WithLimit(10).
+			Exec(context.Background(), filter)
+
+		require.NoError(t, err)
+		require.Len(t, rsp, 1)
+	})
+
 	t.Run("find-error", func(t *testing.T) {
 		coll := &mockCollection{
 			t: t,
@@ -87,3 +107,24 @@ func TestFindMany(t *testing.T) {
 		require.Nil(t, rsp)
 	})
 }
+
+func TestFindManyQuery_buildOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("none", func(t *testing.T) {
+		require.Nil(t, FindMany(&mockCollection{}, fromDBPerson).buildOptions())
+	})
+
+	t.Run("all", func(t *testing.T) {
+		opts := FindMany(&mockCollection{}, fromDBPerson).
+			WithProjection("name").
+			WithSort("name", false).
+			WithSort("id", true).
+			WithLimit(10).
+			buildOptions()
+
+		require.Equal(t, bson.D{{Key: "name", Value: 1}}, opts.Projection)
+		require.Equal(t, bson.D{{Key: "name", Value: 1}, {Key: "id", Value: -1}}, opts.Sort)
+		require.Equal(t, int64(10), *opts.Limit)
+	})
+}
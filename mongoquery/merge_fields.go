@@ -29,10 +29,10 @@ func (q MergeFieldsQuery) Key(key string) MergeFieldsQuery {
 }
 
 // Exec executes the query.
-// It updates the fields in the document.
+// It updates the fields in the document. The id can also be a filter.
 // It returns an error if the operation failed.
 func (q MergeFieldsQuery) Exec(ctx context.Context, id any, fields map[string]any) error {
-	qFilter := bson.M{resolveKey(q.key): id}
+	qFilter := buildFilter(q.key, id)
 	qUpdate := bson.M{"$set": bson.M(fields)}
 
 	res, err := q.coll.UpdateOne(ctx, qFilter, qUpdate)
@@ -26,6 +26,18 @@ func TestMergeFields(t *testing.T) {
 		require.NoError(t, MergeFields(coll).Key("id").Exec(context.Background(), testID, testFields))
 	})
 
+	t.Run("filter", func(t *testing.T) {
+		coll := &mockCollection{
+			t:      t,
+			caller: "MergeFields",
+			updateOne: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 1}, nil
+			},
+		}
+
+		require.NoError(t, MergeFields(coll).Exec(context.Background(), Filter{}.EQ("id", testID), testFields))
+	})
+
 	t.Run("not-found", func(t *testing.T) {
 		coll := &mockCollection{
 			t:      t,
@@ -0,0 +1,56 @@
+package mongoquery
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PushFields creates a new query to append values to one or more array fields in a document.
+func PushFields(coll collection) PushFieldsQuery {
+	return PushFieldsQuery{
+		coll: coll,
+	}
+}
+
+// PushFieldsQuery is a query to append values to one or more array fields in a document.
+// All fields are updated in a single atomic operation.
+type PushFieldsQuery struct {
+	coll collection
+	key  string
+}
+
+// Key sets the key to use for the query.
+// It returns the query itself.
+func (q PushFieldsQuery) Key(key string) PushFieldsQuery {
+	q.key = key
+
+	return q
+}
+
+// Exec executes the query.
+// It appends the values to the array fields of the document. Each value of the fields map
+// must be a slice of already mapped documents. The id can also be a filter.
+// It returns an error if the operation failed.
+func (q PushFieldsQuery) Exec(ctx context.Context, id any, fields map[string]any) error {
+	qFilter := buildFilter(q.key, id)
+	qPush := bson.M{}
+
+	for field, values := range fields {
+		qPush[field] = bson.M{"$each": values}
+	}
+
+	qUpdate := bson.M{"$push": qPush}
+
+	res, err := q.coll.UpdateOne(ctx, qFilter, qUpdate)
+	if err != nil {
+		return errorz.NewStoreError("failed to push to %s: %v", singular(q.coll.Name()), err)
+	}
+
+	if res.MatchedCount == 0 {
+		return errorz.NewNotFoundError("%s %v not found", singular(q.coll.Name()), id)
+	}
+
+	return nil
+}
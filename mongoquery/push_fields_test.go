@@ -0,0 +1,55 @@
+package mongoquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPushFields(t *testing.T) {
+	t.Parallel()
+
+	testFields := map[string]any{"addresses": []dbAddress{testDBAddress}}
+
+	t.Run("success", func(t *testing.T) {
+		coll := &mockCollection{
+			t:      t,
+			caller: "PushFields",
+			updateOne: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 1}, nil
+			},
+		}
+
+		require.NoError(t, PushFields(coll).Key("id").Exec(context.Background(), testID, testFields))
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		coll := &mockCollection{
+			t:      t,
+			caller: "PushFields",
+			updateOne: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 0}, nil
+			},
+		}
+
+		require.ErrorContains(t,
+			PushFields(coll).Exec(context.Background(), testID, testFields),
+			"person 1 not found")
+	})
+
+	t.Run("update-error", func(t *testing.T) {
+		coll := &mockCollection{
+			t:      t,
+			caller: "PushFields",
+			updateOne: func() (*mongo.UpdateResult, error) {
+				return nil, forcedError{}
+			},
+		}
+
+		require.ErrorContains(t,
+			PushFields(coll).Exec(context.Background(), testID, testFields),
+			"forced error")
+	})
+}
@@ -12,8 +12,12 @@ import (
 type collection interface {
 	InsertOne(ctx context.Context, document interface{},
 		opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents []interface{},
+		opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
 	UpdateOne(ctx context.Context, filter interface{}, update interface{},
 		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{},
+		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	DeleteOne(ctx context.Context, filter interface{},
 		opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
 	DeleteMany(ctx context.Context, filter interface{},
@@ -24,6 +28,8 @@ type collection interface {
 		opts ...*options.FindOptions) (cur *mongo.Cursor, err error)
 	CountDocuments(ctx context.Context, filter interface{},
 		opts ...*options.CountOptions) (int64, error)
+	Distinct(ctx context.Context, fieldName string, filter interface{},
+		opts ...*options.DistinctOptions) ([]interface{}, error)
 	Name() string
 }
 
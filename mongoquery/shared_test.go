@@ -129,12 +129,15 @@ type mockCollection struct {
 	t              *testing.T
 	caller         string
 	insertOne      func() (*mongo.InsertOneResult, error)
+	insertMany     func() (*mongo.InsertManyResult, error)
 	updateOne      func() (*mongo.UpdateResult, error)
+	updateMany     func() (*mongo.UpdateResult, error)
 	deleteOne      func() (*mongo.DeleteResult, error)
 	deleteMany     func() (*mongo.DeleteResult, error)
 	findOne        func() *mongo.SingleResult
 	find           func() (*mongo.Cursor, error)
 	countDocuments func() (int64, error)
+	distinct       func() ([]interface{}, error)
 }
 
 // Ensure mockCollection implements the collection interface.
@@ -153,6 +156,18 @@ func (c *mockCollection) InsertOne(_ context.Context, document interface{}, _ ..
 	return c.insertOne()
 }
 
+func (c *mockCollection) InsertMany(_ context.Context, documents []interface{}, _ ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	c.t.Helper()
+
+	require.Equal(c.t, []interface{}{testDBPerson}, documents)
+
+	if c.insertMany == nil {
+		return nil, errors.New("insertMany not implemented")
+	}
+
+	return c.insertMany()
+}
+
 func (c *mockCollection) UpdateOne(_ context.Context, filter interface{}, update interface{}, _ ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
 	c.t.Helper()
 
@@ -175,6 +190,9 @@ func (c *mockCollection) UpdateOne(_ context.Context, filter interface{}, update
 	case "EmbeddedPush":
 		require.Equal(c.t, bson.M{"id": testID}, fm)
 		require.Equal(c.t, bson.M{"$push": bson.M{"address": testDBAddress}}, um)
+	case "PushFields":
+		require.Equal(c.t, bson.M{"id": testID}, fm)
+		require.Equal(c.t, bson.M{"$push": bson.M{"addresses": bson.M{"$each": []dbAddress{testDBAddress}}}}, um)
 	case "EmbeddedUpdate":
 		require.Equal(c.t, bson.M{"id": testID, "address.id": testAddressID}, fm)
 		require.Equal(c.t, bson.M{"$set": bson.M{"address.$": testDBAddress}}, um)
@@ -189,6 +207,19 @@ func (c *mockCollection) UpdateOne(_ context.Context, filter interface{}, update
 	return c.updateOne()
 }
 
+func (c *mockCollection) UpdateMany(_ context.Context, filter interface{}, update interface{}, _ ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	c.t.Helper()
+
+	require.Equal(c.t, bson.M{"age": bson.M{"$gt": 20}}, filter)
+	require.Equal(c.t, bson.M{"$set": bson.M{"name": "John"}}, update)
+
+	if c.updateMany == nil {
+		return nil, errors.New("updateMany not implemented")
+	}
+
+	return c.updateMany()
+}
+
 func (c *mockCollection) DeleteOne(_ context.Context, filter interface{}, _ ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
 	c.t.Helper()
 
@@ -269,6 +300,19 @@ func (c *mockCollection) CountDocuments(_ context.Context, filter interface{}, _
 	return c.countDocuments()
 }
 
+func (c *mockCollection) Distinct(_ context.Context, fieldName string, filter interface{}, _ ...*options.DistinctOptions) ([]interface{}, error) {
+	c.t.Helper()
+
+	require.Equal(c.t, "name", fieldName)
+	require.Equal(c.t, bson.M{"age": bson.M{"$gt": 20}}, filter)
+
+	if c.distinct == nil {
+		return nil, errors.New("distinct not implemented")
+	}
+
+	return c.distinct()
+}
+
 func (c *mockCollection) Name() string {
 	return "persons"
 }
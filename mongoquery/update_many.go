@@ -0,0 +1,34 @@
+package mongoquery
+
+import (
+	"context"
+
+	"github.com/energimind/powermesh-core/errorz"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpdateMany creates a new query to update one or more fields in multiple documents.
+func UpdateMany(coll collection) UpdateManyQuery {
+	return UpdateManyQuery{
+		coll: coll,
+	}
+}
+
+// UpdateManyQuery is a query to update one or more fields in multiple documents.
+type UpdateManyQuery struct {
+	coll collection
+}
+
+// Exec executes the query.
+// It updates the fields in all documents matching the filter.
+// It returns the number of matched documents and an error if the operation failed.
+func (q UpdateManyQuery) Exec(ctx context.Context, filter Filter, fields map[string]any) (int64, error) {
+	qUpdate := bson.M{"$set": bson.M(fields)}
+
+	res, err := q.coll.UpdateMany(ctx, filter.toBSON(), qUpdate)
+	if err != nil {
+		return 0, errorz.NewStoreError("failed to update %s: %v", singular(q.coll.Name()), err)
+	}
+
+	return res.MatchedCount, nil
+}
@@ -0,0 +1,59 @@
+package mongoquery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestUpdateMany(t *testing.T) {
+	t.Parallel()
+
+	filter := Filter{}.GT("age", 20)
+	testFields := bson.M{"name": "John"}
+
+	t.Run("success", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			updateMany: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 2}, nil
+			},
+		}
+
+		count, err := UpdateMany(coll).Exec(context.Background(), filter, testFields)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			updateMany: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 0}, nil
+			},
+		}
+
+		count, err := UpdateMany(coll).Exec(context.Background(), filter, testFields)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("update-error", func(t *testing.T) {
+		coll := &mockCollection{
+			t: t,
+			updateMany: func() (*mongo.UpdateResult, error) {
+				return nil, forcedError{}
+			},
+		}
+
+		count, err := UpdateMany(coll).Exec(context.Background(), filter, testFields)
+
+		require.ErrorContains(t, err, "forced error")
+		require.Equal(t, int64(0), count)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/energimind/powermesh-core/errorz"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // UpdateOne creates a new UpdateOneQuery.
@@ -20,6 +21,7 @@ type UpdateOneQuery[D, T any] struct {
 	coll   collection
 	mapper mapper[T, D]
 	key    string
+	upsert bool
 }
 
 // Key sets the key to use for the query.
@@ -30,20 +32,29 @@ func (q UpdateOneQuery[D, T]) Key(key string) UpdateOneQuery[D, T] {
 	return q
 }
 
+// Upsert makes the query insert the document if it does not exist.
+// It returns the query itself.
+func (q UpdateOneQuery[D, T]) Upsert() UpdateOneQuery[D, T] {
+	q.upsert = true
+
+	return q
+}
+
 // Exec executes the query.
 // It updates the document in the collection.
+// If the query is an upsert, it inserts the document if it does not exist.
 // It returns an error if the operation failed.
 func (q UpdateOneQuery[D, T]) Exec(ctx context.Context, id any, value T) error {
 	qValue := q.mapper(value)
 	qFilter := bson.M{resolveKey(q.key): id}
 	qUpdate := bson.M{"$set": qValue}
 
-	res, err := q.coll.UpdateOne(ctx, qFilter, qUpdate)
+	res, err := q.coll.UpdateOne(ctx, qFilter, qUpdate, options.Update().SetUpsert(q.upsert))
 	if err != nil {
 		return errorz.NewStoreError("failed to update %s: %v", singular(q.coll.Name()), err)
 	}
 
-	if res.MatchedCount == 0 {
+	if res.MatchedCount == 0 && !q.upsert {
 		return errorz.NewNotFoundError("%s %v not found", singular(q.coll.Name()), id)
 	}
 
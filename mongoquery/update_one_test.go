@@ -37,6 +37,18 @@ func TestUpdateOne(t *testing.T) {
 			"person 1 not found")
 	})
 
+	t.Run("upsert", func(t *testing.T) {
+		coll := &mockCollection{
+			t:      t,
+			caller: "UpdateOne",
+			updateOne: func() (*mongo.UpdateResult, error) {
+				return &mongo.UpdateResult{MatchedCount: 0, UpsertedCount: 1}, nil
+			},
+		}
+
+		require.NoError(t, UpdateOne(coll, toDBPerson).Upsert().Exec(context.Background(), testID, testDomainPerson))
+	})
+
 	t.Run("update-error", func(t *testing.T) {
 		coll := &mockCollection{
 			t:      t,